@@ -0,0 +1,181 @@
+package backfill
+
+import (
+	"errors"
+	"fmt"
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrBackfillRunning is returned when a backfill is started while a run of it is still going
+var ErrBackfillRunning = errors.New("this backfill is still running")
+
+// ErrUnknownBackfill is returned for a backfill name that is not registered
+var ErrUnknownBackfill = errors.New("unknown backfill")
+
+// Batch sizes
+const (
+	DefaultBatchSize = 500
+	MaxBatchSize     = 5000
+)
+
+// staleAfter is how long a running record may go without progress before its process is assumed dead
+const staleAfter = 15 * time.Minute
+
+// Backfill populates a column added after data already existed from the tables it is derived from.
+// Pending only returns rows still missing the value, so a backfill can be run again at any time and
+// picks up where an interrupted run left off.
+type Backfill struct {
+	Name        string
+	Description string
+	Table       string                                       // Table of the rows to backfill
+	Pending     func(db *gorm.DB) *gorm.DB                   // Rows still missing the value
+	Apply       func(tx *gorm.DB, ids []uint) (int64, error) // Fills the rows, returns how many got a value
+}
+
+// All returns the registered backfills
+func All() []Backfill {
+	return []Backfill{
+		orderCourierLane,
+		complainOperatorStages,
+		complainStatus,
+	}
+}
+
+// Find returns the registered backfill with the given name
+func Find(name string) (Backfill, bool) {
+	for _, backfill := range All() {
+		if backfill.Name == name {
+			return backfill, true
+		}
+	}
+	return Backfill{}, false
+}
+
+// PendingCount returns how many rows the backfill still has to look at
+func PendingCount(db *gorm.DB, backfill Backfill) (int64, error) {
+	var count int64
+	err := backfill.Pending(db).Count(&count).Error
+	return count, err
+}
+
+// Start records a new running backfill. Running records without progress for a while are assumed
+// dead and marked as failed first.
+func Start(db *gorm.DB, name string, batchSize int, triggeredBy *uint) (*models.BackfillRun, error) {
+	backfill, found := Find(name)
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBackfill, name)
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	batchSize = min(batchSize, MaxBatchSize)
+
+	now := time.Now()
+	if err := db.Model(&models.BackfillRun{}).
+		Where("status = ? AND updated_at < ?", models.BackfillStatusRunning, now.Add(-staleAfter)).
+		Updates(map[string]interface{}{"status": models.BackfillStatusFailed, "error": "backfill stopped reporting progress", "finished_at": now}).Error; err != nil {
+		return nil, err
+	}
+
+	var running int64
+	if err := db.Model(&models.BackfillRun{}).Where("name = ? AND status = ?", name, models.BackfillStatusRunning).Count(&running).Error; err != nil {
+		return nil, err
+	}
+	if running > 0 {
+		return nil, ErrBackfillRunning
+	}
+
+	total, err := PendingCount(db, backfill)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count pending rows: %w", err)
+	}
+
+	run := models.BackfillRun{
+		Name:        name,
+		Status:      models.BackfillStatusRunning,
+		BatchSize:   batchSize,
+		Total:       total,
+		StartedAt:   now,
+		TriggeredBy: triggeredBy,
+	}
+	if err := db.Create(&run).Error; err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+// Execute runs the backfill recorded by Start batch by batch, each batch in its own transaction, saving
+// progress on the record after every batch. progress, when set, is called after each saved batch.
+func Execute(db *gorm.DB, run *models.BackfillRun, progress func(run *models.BackfillRun)) error {
+	err := execute(db, run, progress)
+
+	now := time.Now()
+	run.FinishedAt = &now
+	run.Status = models.BackfillStatusCompleted
+	if err != nil {
+		run.Status = models.BackfillStatusFailed
+		run.Error = err.Error()
+	}
+
+	if saveErr := db.Save(run).Error; saveErr != nil {
+		return saveErr
+	}
+
+	return err
+}
+
+func execute(db *gorm.DB, run *models.BackfillRun, progress func(run *models.BackfillRun)) error {
+	backfill, found := Find(run.Name)
+	if !found {
+		return fmt.Errorf("%w: %s", ErrUnknownBackfill, run.Name)
+	}
+
+	idColumn := backfill.Table + ".id"
+	for {
+		var ids []uint
+		if err := backfill.Pending(db).
+			Where(idColumn+" > ?", run.LastID).
+			Order(idColumn+" ASC").
+			Limit(run.BatchSize).
+			Pluck(idColumn, &ids).Error; err != nil {
+			return fmt.Errorf("failed to load batch after id %d: %w", run.LastID, err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		var updated int64
+		err := utilities.WithTransaction(db, func(tx *gorm.DB) error {
+			var err error
+			updated, err = backfill.Apply(tx, ids)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to backfill batch after id %d: %w", run.LastID, err)
+		}
+
+		run.Scanned += int64(len(ids))
+		run.Updated += updated
+		run.LastID = ids[len(ids)-1]
+		if err := db.Save(run).Error; err != nil {
+			return fmt.Errorf("failed to save progress: %w", err)
+		}
+		if progress != nil {
+			progress(run)
+		}
+	}
+}
+
+// Run starts and executes a backfill in one call, used by the command line tool
+func Run(db *gorm.DB, name string, batchSize int, progress func(run *models.BackfillRun)) (*models.BackfillRun, error) {
+	run, err := Start(db, name, batchSize, nil)
+	if err != nil {
+		return nil, err
+	}
+	return run, Execute(db, run, progress)
+}
@@ -0,0 +1,124 @@
+package backfill
+
+import (
+	"fmt"
+	"livo-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// complainOperatorStages fills the handling stages of complain operators recorded before stages
+// existed, from the same picking, QC and outbound records complain creation uses
+var complainOperatorStages = Backfill{
+	Name:        "complain-operator-stages",
+	Description: "Set the handling stages of complained operators from picking, QC and outbound records",
+	Table:       "complain_user_details",
+	Pending: func(db *gorm.DB) *gorm.DB {
+		return db.Model(&models.ComplainUserDetail{}).
+			Where("complain_user_details.stages IS NULL OR complain_user_details.stages = ''")
+	},
+	Apply: func(tx *gorm.DB, ids []uint) (int64, error) {
+		var details []models.ComplainUserDetail
+		if err := tx.Preload("Complain").Where("id IN ?", ids).Find(&details).Error; err != nil {
+			return 0, fmt.Errorf("failed to load complain user details: %w", err)
+		}
+
+		trackings := make([]string, 0, len(details))
+		for _, detail := range details {
+			if detail.Complain.Tracking != "" {
+				trackings = append(trackings, detail.Complain.Tracking)
+			}
+		}
+		if len(trackings) == 0 {
+			return 0, nil
+		}
+
+		// Stages per tracking and operator, in the order complain creation adds them
+		stages := make(map[string]map[uint][]string)
+		addStage := func(tracking string, operatorID *uint, stage string) {
+			if operatorID == nil {
+				return
+			}
+			if stages[tracking] == nil {
+				stages[tracking] = make(map[uint][]string)
+			}
+			stages[tracking][*operatorID] = append(stages[tracking][*operatorID], stage)
+		}
+
+		var qcRibbons []models.QcRibbon
+		if err := tx.Where("tracking IN ?", trackings).Find(&qcRibbons).Error; err != nil {
+			return 0, fmt.Errorf("failed to load qc-ribbons: %w", err)
+		}
+		for _, qc := range qcRibbons {
+			addStage(qc.Tracking, qc.QcBy, models.ComplainStageQcRibbon)
+		}
+
+		var qcOnlines []models.QcOnline
+		if err := tx.Where("tracking IN ?", trackings).Find(&qcOnlines).Error; err != nil {
+			return 0, fmt.Errorf("failed to load qc-onlines: %w", err)
+		}
+		for _, qc := range qcOnlines {
+			addStage(qc.Tracking, qc.QcBy, models.ComplainStageQcOnline)
+		}
+
+		var outbounds []models.Outbound
+		if err := tx.Where("tracking IN ?", trackings).Find(&outbounds).Error; err != nil {
+			return 0, fmt.Errorf("failed to load outbounds: %w", err)
+		}
+		for _, outbound := range outbounds {
+			addStage(outbound.Tracking, outbound.OutboundBy, models.ComplainStageOutbound)
+		}
+
+		var orders []models.Order
+		if err := tx.Where("tracking IN ?", trackings).Find(&orders).Error; err != nil {
+			return 0, fmt.Errorf("failed to load orders: %w", err)
+		}
+		for _, order := range orders {
+			addStage(order.Tracking, order.PickedBy, models.ComplainStagePicking)
+		}
+
+		var updated int64
+		for i := range details {
+			detail := &details[i]
+			for _, stage := range stages[detail.Complain.Tracking][detail.OperatorID] {
+				detail.AddStage(stage)
+			}
+			if detail.Stages == "" {
+				continue
+			}
+
+			if err := tx.Model(&models.ComplainUserDetail{}).Where("id = ?", detail.ID).Update("stages", detail.Stages).Error; err != nil {
+				return updated, fmt.Errorf("failed to update complain user detail %d: %w", detail.ID, err)
+			}
+			updated++
+		}
+
+		return updated, nil
+	},
+}
+
+// complainStatus gives complains created before the resolution workflow a status: checked complains
+// count as resolved when they were last updated, the others as open
+var complainStatus = Backfill{
+	Name:        "complain-status",
+	Description: "Set the resolution status of complains from their checked flag",
+	Table:       "complains",
+	Pending: func(db *gorm.DB) *gorm.DB {
+		return db.Model(&models.Complain{}).Where("complains.status IS NULL OR complains.status = ''")
+	},
+	Apply: func(tx *gorm.DB, ids []uint) (int64, error) {
+		resolved := tx.Model(&models.Complain{}).Where("id IN ? AND checked = ?", ids, true).
+			UpdateColumns(map[string]interface{}{"status": models.ComplainStatusResolved, "resolved_at": gorm.Expr("updated_at")})
+		if resolved.Error != nil {
+			return 0, fmt.Errorf("failed to resolve checked complains: %w", resolved.Error)
+		}
+
+		open := tx.Model(&models.Complain{}).Where("id IN ? AND checked = ?", ids, false).
+			UpdateColumn("status", models.ComplainStatusOpen)
+		if open.Error != nil {
+			return resolved.RowsAffected, fmt.Errorf("failed to open unchecked complains: %w", open.Error)
+		}
+
+		return resolved.RowsAffected + open.RowsAffected, nil
+	},
+}
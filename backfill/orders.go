@@ -0,0 +1,55 @@
+package backfill
+
+import (
+	"fmt"
+	"livo-backend/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// orderCourierLane gives orders created before courier lanes their instant flag and SLA due date.
+// Orders whose deadline already passed are marked as breached at their due date, so the SLA checks
+// do not raise alerts for history.
+var orderCourierLane = Backfill{
+	Name:        "order-courier-lane",
+	Description: "Set the instant flag and SLA due date of orders from their courier and sent before",
+	Table:       "orders",
+	Pending: func(db *gorm.DB) *gorm.DB {
+		return db.Model(&models.Order{}).Where("orders.sla_due_at IS NULL")
+	},
+	Apply: func(tx *gorm.DB, ids []uint) (int64, error) {
+		var orders []models.Order
+		if err := tx.Where("id IN ?", ids).Find(&orders).Error; err != nil {
+			return 0, fmt.Errorf("failed to load orders: %w", err)
+		}
+
+		now := time.Now()
+		var updated int64
+		for i := range orders {
+			order := &orders[i]
+			if err := order.ApplyCourierLane(tx); err != nil {
+				return updated, fmt.Errorf("failed to resolve courier lane of order %s: %w", order.Tracking, err)
+			}
+			if order.SLADueAt == nil {
+				continue
+			}
+
+			updates := map[string]interface{}{
+				"instant":    order.Instant,
+				"sla_due_at": order.SLADueAt,
+			}
+			if order.SLADueAt.Before(now) {
+				updates["sla_breached_at"] = order.SLADueAt
+			}
+
+			// Bulk update keeps the order version, the backfill is not an edit
+			if err := tx.Model(&models.Order{}).Where("id = ?", order.ID).Updates(updates).Error; err != nil {
+				return updated, fmt.Errorf("failed to update order %s: %w", order.Tracking, err)
+			}
+			updated++
+		}
+
+		return updated, nil
+	},
+}
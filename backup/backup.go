@@ -0,0 +1,229 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"livo-backend/config"
+	"livo-backend/models"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrBackupRunning is returned when a backup is started while another one is still running
+var ErrBackupRunning = errors.New("another backup is still running")
+
+// ErrBackupDisabled is returned when backups are disabled in the configuration
+var ErrBackupDisabled = errors.New("backups are disabled")
+
+var apiClient = &http.Client{Timeout: 5 * time.Minute}
+
+// Start records a new running backup. Backups that have been running for longer
+// than the configured timeout are assumed dead and marked as failed first.
+func Start(db *gorm.DB, cfg *config.Config, triggeredBy *uint) (*models.Backup, error) {
+	if cfg.BackupMethod == models.BackupMethodDisabled {
+		return nil, ErrBackupDisabled
+	}
+
+	now := time.Now()
+	timeout := time.Duration(cfg.BackupTimeoutMinutes) * time.Minute
+
+	if err := db.Model(&models.Backup{}).
+		Where("status = ? AND started_at < ?", models.BackupStatusRunning, now.Add(-timeout)).
+		Updates(map[string]interface{}{"status": models.BackupStatusFailed, "error": "backup timed out", "finished_at": now}).Error; err != nil {
+		return nil, err
+	}
+
+	var running int64
+	if err := db.Model(&models.Backup{}).Where("status = ?", models.BackupStatusRunning).Count(&running).Error; err != nil {
+		return nil, err
+	}
+	if running > 0 {
+		return nil, ErrBackupRunning
+	}
+
+	record := models.Backup{
+		Method:             cfg.BackupMethod,
+		Status:             models.BackupStatusRunning,
+		StartedAt:          now,
+		TriggeredBy:        triggeredBy,
+		VerificationStatus: models.BackupVerificationPending,
+	}
+	if err := db.Create(&record).Error; err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Execute performs the backup recorded by Start and stores the outcome on the record
+func Execute(ctx context.Context, db *gorm.DB, cfg *config.Config, record *models.Backup) error {
+	var err error
+	switch record.Method {
+	case models.BackupMethodPgDump:
+		err = runPgDump(ctx, cfg, record)
+	case models.BackupMethodManaged:
+		err = runManagedBackup(ctx, cfg, record)
+	default:
+		err = fmt.Errorf("unknown backup method %q", record.Method)
+	}
+
+	now := time.Now()
+	record.FinishedAt = &now
+	if err != nil {
+		record.Status = models.BackupStatusFailed
+		record.Error = err.Error()
+	} else {
+		record.Status = models.BackupStatusSuccess
+		// The provider verifies its own snapshots, only dumps we hold can be restored here
+		if record.Method == models.BackupMethodManaged {
+			record.VerificationStatus = models.BackupVerificationSkipped
+		}
+	}
+
+	if saveErr := db.Save(record).Error; saveErr != nil {
+		return saveErr
+	}
+
+	if err == nil && record.Method == models.BackupMethodPgDump {
+		pruneDumps(db, cfg)
+	}
+
+	return err
+}
+
+// Run starts and executes a backup in one call, used by the scheduler
+func Run(ctx context.Context, db *gorm.DB, cfg *config.Config) error {
+	record, err := Start(db, cfg, nil)
+	if err != nil {
+		return err
+	}
+	return Execute(ctx, db, cfg, record)
+}
+
+func runPgDump(ctx context.Context, cfg *config.Config, record *models.Backup) error {
+	if err := os.MkdirAll(cfg.BackupDir, 0o750); err != nil {
+		return err
+	}
+
+	file := filepath.Join(cfg.BackupDir, fmt.Sprintf("%s-%s.dump", cfg.DBName, record.StartedAt.Format("20060102-150405")))
+
+	cmd := exec.CommandContext(ctx, cfg.PgDumpPath,
+		"--host", cfg.DBHost,
+		"--port", cfg.DBPort,
+		"--username", cfg.DBUser,
+		"--dbname", cfg.DBName,
+		"--format", "custom",
+		"--no-owner",
+		"--file", file,
+	)
+	cmd.Env = pgEnv(cfg)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		os.Remove(file)
+		return fmt.Errorf("pg_dump failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+
+	record.Location = file
+	record.SizeBytes = info.Size()
+	return nil
+}
+
+type managedBackupResponse struct {
+	ID        string `json:"id"`
+	Status    string `json:"status"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+// runManagedBackup asks the database provider to take a snapshot
+func runManagedBackup(ctx context.Context, cfg *config.Config, record *models.Backup) error {
+	if cfg.BackupAPIURL == "" {
+		return errors.New("BACKUP_API_URL is not configured")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.BackupAPIURL, nil)
+	if err != nil {
+		return err
+	}
+	if cfg.BackupAPIToken != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.BackupAPIToken)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := apiClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("backup API returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result managedBackupResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("invalid backup API response: %v", err)
+	}
+	if strings.EqualFold(result.Status, "failed") || strings.EqualFold(result.Status, "error") {
+		return fmt.Errorf("backup API reported status %s", result.Status)
+	}
+
+	record.Location = result.ID
+	record.SizeBytes = result.SizeBytes
+	return nil
+}
+
+// pruneDumps deletes dump files of successful backups beyond the retention count.
+// The backup records are kept so the history stays visible.
+func pruneDumps(db *gorm.DB, cfg *config.Config) {
+	if cfg.BackupRetentionCount <= 0 {
+		return
+	}
+
+	var backups []models.Backup
+	if err := db.Where("method = ? AND status = ? AND location <> ''", models.BackupMethodPgDump, models.BackupStatusSuccess).
+		Order("started_at DESC").
+		Offset(cfg.BackupRetentionCount).
+		Find(&backups).Error; err != nil {
+		log.Printf("⚠️ Failed to list old backups: %v", err)
+		return
+	}
+
+	for _, old := range backups {
+		if err := os.Remove(old.Location); err != nil && !os.IsNotExist(err) {
+			log.Printf("⚠️ Failed to remove old backup %s: %v", old.Location, err)
+			continue
+		}
+		db.Model(&old).Update("location", "")
+	}
+}
+
+// pgEnv passes the connection secrets to the postgres client tools without putting them on the command line
+func pgEnv(cfg *config.Config) []string {
+	return append(os.Environ(),
+		"PGPASSWORD="+cfg.DBPassword,
+		"PGSSLMODE="+cfg.DBSSLMode,
+	)
+}
@@ -0,0 +1,86 @@
+package backup
+
+import (
+	"fmt"
+	"livo-backend/config"
+	"livo-backend/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Backup health statuses
+const (
+	HealthOK       = "ok"
+	HealthStale    = "stale"
+	HealthFailing  = "failing"
+	HealthDisabled = "disabled"
+)
+
+// Health summarises the state of the backups for readiness checks and the admin dashboard
+type Health struct {
+	Status             string `json:"status" example:"ok"`
+	Message            string `json:"message"`
+	Method             string `json:"method" example:"pg_dump"`
+	LastBackupAt       string `json:"last_backup_at"`
+	LastBackupStatus   string `json:"last_backup_status"`
+	LastVerifiedAt     string `json:"last_verified_at"`
+	VerificationStatus string `json:"verification_status"`
+}
+
+// CheckHealth reports whether a recent backup exists and whether the latest verified restore succeeded
+func CheckHealth(db *gorm.DB, cfg *config.Config) Health {
+	health := Health{
+		Status:             HealthOK,
+		Method:             cfg.BackupMethod,
+		LastBackupAt:       "-",
+		LastBackupStatus:   "-",
+		LastVerifiedAt:     "-",
+		VerificationStatus: "-",
+	}
+
+	if cfg.BackupMethod == models.BackupMethodDisabled {
+		health.Status = HealthDisabled
+		health.Message = "backups are disabled"
+		return health
+	}
+
+	var latest models.Backup
+	if err := db.Where("status <> ?", models.BackupStatusRunning).Order("started_at DESC").First(&latest).Error; err == nil {
+		health.LastBackupAt = latest.StartedAt.Format("2006-01-02 15:04:05")
+		health.LastBackupStatus = latest.Status
+	}
+
+	var lastSuccess models.Backup
+	if err := db.Where("status = ?", models.BackupStatusSuccess).Order("started_at DESC").First(&lastSuccess).Error; err != nil {
+		health.Status = HealthStale
+		health.Message = "no successful backup recorded"
+		return health
+	}
+
+	maxAge := time.Duration(cfg.BackupMaxAgeHours) * time.Hour
+	if age := time.Since(lastSuccess.StartedAt); age > maxAge {
+		health.Status = HealthStale
+		health.Message = fmt.Sprintf("last successful backup is %s old", age.Round(time.Minute))
+	}
+
+	var lastVerified models.Backup
+	if err := db.Where("verification_status IN ?", []string{models.BackupVerificationVerified, models.BackupVerificationFailed}).
+		Order("verified_at DESC").First(&lastVerified).Error; err == nil {
+		health.VerificationStatus = lastVerified.VerificationStatus
+		if lastVerified.VerifiedAt != nil {
+			health.LastVerifiedAt = lastVerified.VerifiedAt.Format("2006-01-02 15:04:05")
+		}
+		if lastVerified.VerificationStatus == models.BackupVerificationFailed {
+			health.Status = HealthFailing
+			health.Message = "latest restore verification failed: " + lastVerified.VerificationError
+			return health
+		}
+	}
+
+	if latest.Status == models.BackupStatusFailed && health.Status == HealthOK {
+		health.Message = "latest backup run failed: " + latest.Error
+	}
+
+	return health
+}
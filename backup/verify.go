@@ -0,0 +1,189 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"livo-backend/config"
+	"livo-backend/models"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var schemaNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// VerifyLatest restores the most recent successful dump that has not been verified yet
+func VerifyLatest(ctx context.Context, db *gorm.DB, cfg *config.Config) error {
+	var record models.Backup
+	err := db.Where("method = ? AND status = ? AND verification_status = ? AND location <> ''",
+		models.BackupMethodPgDump, models.BackupStatusSuccess, models.BackupVerificationPending).
+		Order("started_at DESC").
+		First(&record).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return Verify(ctx, db, cfg, &record)
+}
+
+// Verify restores a dump into the scratch schema to prove it is usable and stores
+// the outcome on the backup record. The scratch schema is dropped afterwards.
+func Verify(ctx context.Context, db *gorm.DB, cfg *config.Config, record *models.Backup) error {
+	if record.Method != models.BackupMethodPgDump || record.Status != models.BackupStatusSuccess {
+		return errors.New("only successful pg_dump backups can be verified")
+	}
+	if record.Location == "" {
+		return errors.New("backup file has been pruned")
+	}
+
+	if err := db.Model(record).Update("verification_status", models.BackupVerificationRunning).Error; err != nil {
+		return err
+	}
+
+	tables, err := restoreIntoScratchSchema(ctx, db, cfg, record.Location)
+
+	now := time.Now()
+	updates := map[string]interface{}{"verified_at": now, "restored_tables": tables}
+	if err != nil {
+		updates["verification_status"] = models.BackupVerificationFailed
+		updates["verification_error"] = err.Error()
+	} else {
+		updates["verification_status"] = models.BackupVerificationVerified
+		updates["verification_error"] = ""
+	}
+
+	if saveErr := db.Model(record).Updates(updates).Error; saveErr != nil {
+		return saveErr
+	}
+
+	return err
+}
+
+func restoreIntoScratchSchema(ctx context.Context, db *gorm.DB, cfg *config.Config, file string) (int, error) {
+	schema := cfg.BackupVerifySchema
+	if !schemaNamePattern.MatchString(schema) || schema == "public" {
+		return 0, fmt.Errorf("invalid verification schema %q", schema)
+	}
+
+	if _, err := os.Stat(file); err != nil {
+		return 0, err
+	}
+
+	if err := db.Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", schema)).Error; err != nil {
+		return 0, err
+	}
+	if err := db.Exec(fmt.Sprintf("CREATE SCHEMA %s", schema)).Error; err != nil {
+		return 0, err
+	}
+	defer db.Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", schema))
+
+	// pg_restore renders the dump as SQL, the public schema is renamed on the fly and psql applies it
+	restore := exec.CommandContext(ctx, cfg.PgRestorePath, "--no-owner", "--no-privileges", "--schema", "public", "--file", "-", file)
+	restore.Env = pgEnv(cfg)
+	var restoreErr bytes.Buffer
+	restore.Stderr = &restoreErr
+
+	psql := exec.CommandContext(ctx, cfg.PsqlPath,
+		"--host", cfg.DBHost,
+		"--port", cfg.DBPort,
+		"--username", cfg.DBUser,
+		"--dbname", cfg.DBName,
+		"--quiet",
+		"--no-psqlrc",
+		"--set", "ON_ERROR_STOP=1",
+	)
+	psql.Env = pgEnv(cfg)
+	var psqlErr bytes.Buffer
+	psql.Stderr = &psqlErr
+
+	dump, err := restore.StdoutPipe()
+	if err != nil {
+		return 0, err
+	}
+	input, err := psql.StdinPipe()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := restore.Start(); err != nil {
+		return 0, err
+	}
+	if err := psql.Start(); err != nil {
+		restore.Process.Kill()
+		restore.Wait()
+		return 0, err
+	}
+
+	rewriteErr := rewriteSchema(dump, input, schema)
+	input.Close()
+	if rewriteErr != nil {
+		// psql stopped reading, unblock pg_restore
+		restore.Process.Kill()
+	}
+
+	restoreWaitErr := restore.Wait()
+	psqlWaitErr := psql.Wait()
+
+	if psqlWaitErr != nil {
+		return 0, fmt.Errorf("restore into %s failed: %v: %s", schema, psqlWaitErr, strings.TrimSpace(psqlErr.String()))
+	}
+	if restoreWaitErr != nil {
+		return 0, fmt.Errorf("pg_restore failed: %v: %s", restoreWaitErr, strings.TrimSpace(restoreErr.String()))
+	}
+	if rewriteErr != nil {
+		return 0, rewriteErr
+	}
+
+	var tables int64
+	if err := db.Table("information_schema.tables").Where("table_schema = ?", schema).Count(&tables).Error; err != nil {
+		return 0, err
+	}
+	if tables == 0 {
+		return 0, errors.New("restore produced no tables")
+	}
+
+	return int(tables), nil
+}
+
+// rewriteSchema copies the SQL script replacing the public schema qualifier, COPY data blocks are passed through untouched
+func rewriteSchema(r io.Reader, w io.Writer, schema string) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	writer := bufio.NewWriter(w)
+
+	inCopy := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case inCopy:
+			if line == `\.` {
+				inCopy = false
+			}
+		case strings.HasPrefix(line, "COPY "):
+			inCopy = true
+			line = strings.ReplaceAll(line, "public.", schema+".")
+		default:
+			line = strings.ReplaceAll(line, "public.", schema+".")
+		}
+
+		if _, err := writer.WriteString(line + "\n"); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return writer.Flush()
+}
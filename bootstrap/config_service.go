@@ -0,0 +1,231 @@
+package bootstrap
+
+import (
+	"fmt"
+	"livo-backend/config"
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ConfigService layers the config overrides stored in the database over the environment and applies
+// the result through ApplySettings, so reloadable settings change without a redeploy. Changes made
+// through the service apply at once, changes made by other processes once Start's poll sees them.
+type ConfigService struct {
+	DB *gorm.DB
+
+	mu      sync.Mutex
+	env     *config.Config // Loaded from the environment alone
+	current *config.Config // Environment with the overrides applied
+	version uint           // Latest config change applied
+	stop    chan struct{}
+}
+
+// NewConfigService creates a config service over cfg, the configuration loaded from the environment.
+// Overrides take effect on the first Reload.
+func NewConfigService(db *gorm.DB, cfg *config.Config) *ConfigService {
+	return &ConfigService{DB: db, env: cfg, current: cfg}
+}
+
+// Current returns the configuration in effect
+func (s *ConfigService) Current() *config.Config {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current
+}
+
+// Env returns the configuration loaded from the environment, without overrides
+func (s *ConfigService) Env() *config.Config {
+	return s.env
+}
+
+// Reload loads the overrides from the database and applies them
+func (s *ConfigService) Reload() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reload()
+}
+
+func (s *ConfigService) reload() error {
+	// Taken before the overrides, so a change made meanwhile is picked up by the next poll
+	version, err := models.LatestConfigChangeID(s.DB)
+	if err != nil {
+		return err
+	}
+	overrides, err := models.LoadConfigOverrides(s.DB)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]string, len(overrides))
+	for key, override := range overrides {
+		if _, ok := config.FindReloadableSetting(key); !ok {
+			log.Printf("⚠️ Ignoring config override of %s, the setting is not reloadable", key)
+			continue
+		}
+		values[key] = override.Value
+	}
+
+	cfg := config.LoadConfigWithOverrides(values)
+	ApplySettings(cfg)
+	s.current = cfg
+	s.version = version
+	return nil
+}
+
+// Set overrides a reloadable setting, records the change and applies it
+func (s *ConfigService) Set(key, value string, userID uint) (models.ConfigChange, error) {
+	setting, ok := config.FindReloadableSetting(key)
+	if !ok {
+		return models.ConfigChange{}, utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeConfigNotFound, "Config setting not found", fmt.Sprintf("%s is not a reloadable setting", key))
+	}
+	if err := validateSetting(setting, value); err != nil {
+		return models.ConfigChange{}, utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid config value", err.Error())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	change := models.ConfigChange{
+		Key:       key,
+		Action:    models.ConfigChangeSet,
+		OldValue:  s.current.Value(key),
+		NewValue:  value,
+		ChangedBy: userID,
+	}
+	err := utilities.WithTransaction(s.DB, func(tx *gorm.DB) error {
+		var override models.ConfigOverride
+		err := tx.Where("key = ?", key).First(&override).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		override.Key = key
+		override.Value = value
+		override.UpdatedBy = userID
+		if err := tx.Save(&override).Error; err != nil {
+			return fmt.Errorf("failed to save config override: %w", err)
+		}
+		if err := tx.Create(&change).Error; err != nil {
+			return fmt.Errorf("failed to record config change: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.ConfigChange{}, err
+	}
+
+	return change, s.reload()
+}
+
+// Reset removes the override of a setting, putting it back to its environment value
+func (s *ConfigService) Reset(key string, userID uint) (models.ConfigChange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	change := models.ConfigChange{
+		Key:       key,
+		Action:    models.ConfigChangeReset,
+		OldValue:  s.current.Value(key),
+		NewValue:  s.env.Value(key),
+		ChangedBy: userID,
+	}
+	err := utilities.WithTransaction(s.DB, func(tx *gorm.DB) error {
+		result := tx.Where("key = ?", key).Delete(&models.ConfigOverride{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to remove config override: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeConfigNotFound, "Config override not found", fmt.Sprintf("%s is not overridden", key))
+		}
+		if err := tx.Create(&change).Error; err != nil {
+			return fmt.Errorf("failed to record config change: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.ConfigChange{}, err
+	}
+
+	return change, s.reload()
+}
+
+// Start checks every interval whether another process changed the config and reloads it if so. A
+// zero interval leaves it to the changes made through this service.
+func (s *ConfigService) Start(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	s.stop = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				if err := s.reloadIfChanged(); err != nil {
+					log.Printf("⚠️ Failed to reload config: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends the checks started by Start
+func (s *ConfigService) Stop() {
+	if s.stop != nil {
+		close(s.stop)
+	}
+}
+
+func (s *ConfigService) reloadIfChanged() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	version, err := models.LatestConfigChangeID(s.DB)
+	if err != nil {
+		return err
+	}
+	if version == s.version {
+		return nil
+	}
+
+	log.Println("🔄 Config changed, reloading settings...")
+	return s.reload()
+}
+
+// validateSetting checks value the way ApplySettings reads the setting, so an override never falls
+// back to the default
+func validateSetting(setting config.ReloadableSetting, value string) error {
+	if err := setting.Validate(value); err != nil {
+		return err
+	}
+
+	var err error
+	switch setting.Key {
+	case "ORDER_STUCK_THRESHOLDS":
+		_, err = models.ParseOrderStuckThresholds(value)
+	case "COMPLAIN_FEE_SPLIT_STAGE_WEIGHTS":
+		_, err = models.ParseComplainStageWeights(value)
+	case "ORDER_LIST_DEFAULT_SORT":
+		_, err = utilities.BuildOrderBy(value, "", models.OrderSortKeys, "")
+	case "QUOTA_REPORTS", "QUOTA_EXPORTS", "QUOTA_BULK_IMPORTS":
+		_, err = models.ParseQuotaPolicy(value)
+	case "DEFAULT_LANGUAGE":
+		if !utilities.SupportedLanguage(value) {
+			err = fmt.Errorf("unsupported language %q", value)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", setting.Key, err)
+	}
+	return nil
+}
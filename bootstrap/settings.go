@@ -0,0 +1,164 @@
+package bootstrap
+
+import (
+	"livo-backend/config"
+	"livo-backend/integrations"
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"log"
+	"time"
+)
+
+// ApplySettings configures the settings of models and utilities from cfg. Every entrypoint (API
+// server and worker) calls it right after loading the configuration, and the config service calls it
+// again on reload. Both packages get a complete new snapshot swapped in at once, so requests running
+// during a reload see either the old or the new settings, never a mix. Invalid values keep the
+// setting in use.
+func ApplySettings(cfg *config.Config) {
+	utilitySettings := *utilities.CurrentSettings()
+	modelSettings := *models.CurrentSettings()
+
+	// Warehouse timezone for day boundaries
+	if location, err := time.LoadLocation(cfg.AppTimezone); err != nil {
+		log.Printf("⚠️ Invalid APP_TIMEZONE %q, using %s: %v", cfg.AppTimezone, utilitySettings.Location, err)
+	} else {
+		utilitySettings.Location = location
+	}
+
+	// Retry policy for critical write transactions
+	utilitySettings.TransactionRetry.Attempts = cfg.DBRetryAttempts
+	utilitySettings.TransactionRetry.BaseDelay = time.Duration(cfg.DBRetryBaseDelayMs) * time.Millisecond
+
+	// Identifiers given to duplicated orders
+	modelSettings.OrderDuplicatePolicy = models.DuplicatePolicy{
+		GineeIDSuffix:  cfg.OrderDuplicateGineeSuffix,
+		TrackingPrefix: cfg.OrderDuplicateTrackingPrefix,
+		MaxDuplicates:  cfg.OrderDuplicateMax,
+		ModifyOriginal: cfg.OrderDuplicateModifyOriginal,
+	}
+
+	// SLA of orders shipped by instant couriers
+	modelSettings.InstantSLA = time.Duration(cfg.InstantSLAMinutes) * time.Minute
+
+	// Double scan tolerance at QC and outbound
+	modelSettings.ScanRepeatWindow = time.Duration(cfg.ScanRepeatWindowSeconds) * time.Second
+
+	// Stage weights for splitting complain fees
+	if weights, err := models.ParseComplainStageWeights(cfg.ComplainFeeSplitStageWeights); err != nil {
+		log.Printf("⚠️ Invalid COMPLAIN_FEE_SPLIT_STAGE_WEIGHTS, keeping the current weights: %v", err)
+	} else {
+		modelSettings.ComplainStageWeights = weights
+	}
+
+	// Error rate at which integrations count as degraded
+	modelSettings.IntegrationHealth = models.IntegrationHealthPolicy{
+		Window:    time.Duration(cfg.IntegrationHealthWindowMinutes) * time.Minute,
+		ErrorRate: float64(cfg.IntegrationErrorRatePercent) / 100,
+		MinCalls:  cfg.IntegrationMinCalls,
+	}
+
+	// Productivity weighting of grabbed orders
+	modelSettings.PickerProductivity = models.PickerProductivityPolicy{
+		Days:           cfg.PickerProductivityDays,
+		MinPicks:       cfg.PickerProductivityMinPicks,
+		SpeedWeight:    cfg.PickerSpeedWeight,
+		AccuracyWeight: cfg.PickerAccuracyWeight,
+		UrgentWindow:   time.Duration(cfg.PickerUrgentWindowMinutes) * time.Minute,
+	}
+
+	// Rules of order auto-assignment
+	modelSettings.AutoAssign = models.AutoAssignPolicy{
+		ActiveMinutes: cfg.AutoAssignActiveMinutes,
+		MaxOpenOrders: cfg.AutoAssignMaxOpenOrders,
+		ZoneAffinity:  cfg.AutoAssignZoneAffinity,
+		RestrictZones: cfg.PickerZoneMode == models.PickerZoneModeRestrict,
+	}
+
+	// Window in which orders are escalated to urgent
+	modelSettings.OrderUrgentWindow = time.Duration(cfg.OrderUrgentHours) * time.Hour
+
+	// Box usage forecast window and low stock alert horizon
+	modelSettings.BoxStock = models.BoxStockPolicy{
+		UsageDays: cfg.BoxStockUsageDays,
+		AlertDays: cfg.BoxStockAlertDays,
+	}
+
+	// Channel intake baseline and drop threshold
+	modelSettings.ChannelIntake = models.ChannelIntakePolicy{
+		BaselineDays: cfg.ChannelIntakeBaselineDays,
+		DropPercent:  cfg.ChannelIntakeDropPercent,
+		MinExpected:  cfg.ChannelIntakeMinExpected,
+	}
+
+	// Time orders may stay in a processing status before they are flagged at risk
+	if thresholds, err := models.ParseOrderStuckThresholds(cfg.OrderStuckThresholds); err != nil {
+		log.Printf("⚠️ Invalid ORDER_STUCK_THRESHOLDS, keeping the current thresholds: %v", err)
+	} else {
+		modelSettings.OrderStuckThresholds = thresholds
+	}
+
+	// Default order list sort
+	if _, err := utilities.BuildOrderBy(cfg.OrderListDefaultSort, "", models.OrderSortKeys, ""); err != nil {
+		log.Printf("⚠️ Invalid ORDER_LIST_DEFAULT_SORT, using %s: %v", modelSettings.OrderListDefaultSort, err)
+	} else {
+		modelSettings.OrderListDefaultSort = cfg.OrderListDefaultSort
+	}
+
+	// Complexity of new passwords and lockout after wrong passwords
+	utilitySettings.PasswordRules = utilities.PasswordPolicy{
+		MinLength:     cfg.PasswordMinLength,
+		RequireUpper:  cfg.PasswordRequireUpper,
+		RequireLower:  cfg.PasswordRequireLower,
+		RequireDigit:  cfg.PasswordRequireDigit,
+		RequireSymbol: cfg.PasswordRequireSymbol,
+	}
+	if cfg.UserImportEmailDomain != "" {
+		modelSettings.ImportedUserEmailDomain = cfg.UserImportEmailDomain
+	}
+	modelSettings.LoginLockout = models.LoginLockoutPolicy{
+		MaxFailures: cfg.LoginMaxFailures,
+		Duration:    time.Duration(cfg.LoginLockoutMinutes) * time.Minute,
+	}
+
+	// Address validation of created and edited orders
+	if geocoder, err := integrations.NewGeocoder(cfg.GeocodingProvider, cfg.GeocodingURL, cfg.GeocodingAPIKey); err != nil {
+		log.Printf("⚠️ Invalid geocoding configuration, leaving addresses unchecked: %v", err)
+		modelSettings.Geocoder = nil
+	} else {
+		modelSettings.Geocoder = geocoder
+	}
+	modelSettings.AddressValidationRequired = cfg.AddressValidationRequired
+
+	// Time complains have to be checked, stores may have an SLA of their own
+	modelSettings.ComplainSLAHours = cfg.ComplainSLAHours
+
+	// Window of transactions that keep master data from being deleted
+	modelSettings.MasterDeleteGuardDays = cfg.MasterDeleteGuardDays
+
+	// Language of response messages
+	if utilities.SupportedLanguage(cfg.DefaultLanguage) {
+		utilitySettings.DefaultLanguage = cfg.DefaultLanguage
+	} else {
+		log.Printf("⚠️ Unsupported DEFAULT_LANGUAGE %q, using %s", cfg.DefaultLanguage, utilitySettings.DefaultLanguage)
+	}
+
+	// Daily request quotas per role. The map of the current snapshot is shared with running
+	// requests, the new quotas go into a map of their own.
+	quotas := map[string]string{
+		models.QuotaOperationReport:     cfg.QuotaReports,
+		models.QuotaOperationExport:     cfg.QuotaExports,
+		models.QuotaOperationBulkImport: cfg.QuotaBulkImports,
+	}
+	modelSettings.RequestQuotas = make(map[string]models.QuotaPolicy, len(quotas))
+	for operation, value := range quotas {
+		policy, err := models.ParseQuotaPolicy(value)
+		if err != nil {
+			log.Printf("⚠️ Invalid %s quota, leaving it unlimited: %v", operation, err)
+			continue
+		}
+		modelSettings.RequestQuotas[operation] = policy
+	}
+
+	utilities.SetSettings(&utilitySettings)
+	models.SetSettings(&modelSettings)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"livo-backend/backfill"
+	"livo-backend/bootstrap"
+	"livo-backend/config"
+	"livo-backend/models"
+	"log"
+	"os"
+)
+
+// The backfill tool populates columns added after data already existed from the orders, QC and
+// outbound tables. Backfills only fill rows still missing the value, so they can be run again
+// safely and resume after an interruption.
+//
+//	go run ./cmd/backfill -list
+//	go run ./cmd/backfill -name order-courier-lane -batch 1000
+//	go run ./cmd/backfill -all
+func main() {
+	list := flag.Bool("list", false, "List the available backfills with their pending rows")
+	name := flag.String("name", "", "Backfill to run")
+	all := flag.Bool("all", false, "Run every backfill")
+	batchSize := flag.Int("batch", backfill.DefaultBatchSize, "Rows per transaction")
+	flag.Parse()
+
+	if !*list && !*all && *name == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Load configuration
+	cfg := config.LoadConfig()
+	bootstrap.ApplySettings(cfg)
+
+	// Connect to database with retry logic. Migrations are left to the API server.
+	config.ConnectDatabase(cfg)
+	db := config.GetDB()
+	if err := bootstrap.NewConfigService(db, cfg).Reload(); err != nil {
+		log.Printf("⚠️ Failed to load config overrides, using the environment: %v", err)
+	}
+
+	if *list {
+		for _, b := range backfill.All() {
+			pending, err := backfill.PendingCount(db, b)
+			if err != nil {
+				log.Fatalf("❌ Failed to count pending rows of %s: %v", b.Name, err)
+			}
+			fmt.Printf("%-28s %8d pending  %s\n", b.Name, pending, b.Description)
+		}
+		return
+	}
+
+	names := []string{*name}
+	if *all {
+		names = names[:0]
+		for _, b := range backfill.All() {
+			names = append(names, b.Name)
+		}
+	}
+
+	failed := false
+	for _, backfillName := range names {
+		log.Printf("🔄 Running backfill %s...", backfillName)
+		run, err := backfill.Run(db, backfillName, *batchSize, func(run *models.BackfillRun) {
+			log.Printf("   %s: %d/%d scanned, %d updated (last id %d)", run.Name, run.Scanned, run.Total, run.Updated, run.LastID)
+		})
+		if err != nil {
+			log.Printf("❌ Backfill %s failed: %v", backfillName, err)
+			failed = true
+			continue
+		}
+		log.Printf("✓ Backfill %s completed: %d of %d row(s) updated", run.Name, run.Updated, run.Scanned)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
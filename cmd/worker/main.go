@@ -0,0 +1,54 @@
+package main
+
+import (
+	"livo-backend/bootstrap"
+	"livo-backend/config"
+	"livo-backend/jobs"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// The worker runs the background jobs (delivery status sync, webhooks, reports, backups and
+// retention, SLA checks) outside the API process, so they can be scaled and deployed on their own.
+// Set RUN_BACKGROUND_JOBS=false on the API when a worker is running.
+func main() {
+	log.Println("🚀 Starting Livotech Worker...")
+
+	// Load configuration
+	log.Println("📝 Loading configuration...")
+	cfg := config.LoadConfig()
+	bootstrap.ApplySettings(cfg)
+	log.Println("✓ Configuration loaded successfully")
+
+	// Connect to database with retry logic. Migrations are left to the API server.
+	log.Println("🔌 Connecting to database...")
+	config.ConnectDatabase(cfg)
+	db := config.GetDB()
+
+	// Apply the settings overridden through the admin config API, and pick up later changes
+	configService := bootstrap.NewConfigService(db, cfg)
+	if err := configService.Reload(); err != nil {
+		log.Printf("⚠️ Failed to load config overrides, using the environment: %v", err)
+	}
+	configService.Start(time.Duration(cfg.ConfigReloadSeconds) * time.Second)
+
+	// Start background jobs
+	log.Println("⏱️  Starting background jobs...")
+	scheduler := jobs.NewScheduler(db)
+	jobs.RegisterAll(scheduler, cfg)
+	scheduler.Start()
+	log.Println("✓ Worker ready")
+
+	// Run until asked to stop, then let running jobs finish
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("🛑 Stopping worker, waiting for running jobs...")
+	scheduler.Stop()
+	configService.Stop()
+	log.Println("✓ Worker stopped")
+}
@@ -23,18 +23,358 @@ type Config struct {
 	CORSAllowedOrigins     string
 	CORSAllowedMethods     string
 	APIHost                string
+
+	// CORS headers (comma separated), whether browsers may send cookies/credentials and how long
+	// preflight responses may be cached
+	CORSAllowedHeaders   string
+	CORSExposedHeaders   string
+	CORSAllowCredentials bool
+	CORSMaxAgeHours      int
+
+	// Proxies (IPs or CIDRs, comma separated) whose forwarded client IP headers are trusted, e.g. the
+	// load balancer's subnet. Empty trusts none and uses the connecting address as client IP.
+	TrustedProxies  string
+	RemoteIPHeaders string
+
+	// Route groups (path prefixes, comma separated) whose GET responses are gzipped when at least
+	// GzipMinBytes long, and whose GET responses get an ETag so unchanged pages answer 304
+	GzipRoutes   string
+	GzipMinBytes int
+	ETagRoutes   string
+
+	// Query time limits in seconds, 0 for none: the Postgres statement_timeout of every connection,
+	// and how long the queries of an API request may run before it is answered 503. Requests below
+	// LongQueryRoutes (path prefixes, comma separated) get LongQueryTimeoutSeconds.
+	DBStatementTimeoutSeconds int
+	QueryTimeoutSeconds       int
+	LongQueryTimeoutSeconds   int
+	LongQueryRoutes           string
+
+	// Complain escalation thresholds (hours spent in a tier before auto escalation)
+	ComplainEscalationCSHours          int
+	ComplainEscalationCoordinatorHours int
+	ComplainEscalationCheckMinutes     int
+
+	// Complain SLA (hours from creation until a complain must be checked)
+	ComplainSLAHours        int
+	ComplainSLACheckMinutes int
+
+	// Complain fee split: default policy (equal, stage_weight or fault) and the stage weights used by
+	// stage_weight as comma separated "stage=weight" pairs (picking, qc_ribbon, qc_online, outbound)
+	ComplainFeeSplitPolicy       string
+	ComplainFeeSplitStageWeights string
+
+	// Picker zones: "prefer" shows in-zone orders first, "restrict" hides orders outside the picker's zones
+	PickerZoneMode string
+
+	// Delivery status polling for outbound trackings
+	DeliveryTrackingPollMinutes int
+	DeliveryTrackingMaxDays     int
+	DeliveryTrackingBatchSize   int
+
+	// Database backups: method is "pg_dump", "managed" (provider backup API) or "disabled"
+	BackupMethod              string
+	BackupDir                 string
+	BackupIntervalHours       int
+	BackupRetentionCount      int
+	BackupMaxAgeHours         int
+	BackupTimeoutMinutes      int
+	BackupVerifyIntervalHours int
+	BackupVerifySchema        string
+	BackupAPIURL              string
+	BackupAPIToken            string
+	PgDumpPath                string
+	PgRestorePath             string
+	PsqlPath                  string
+
+	// Sessions: idle timeout revokes tokens of inactive users, shift end logs out operator roles.
+	// Shift end times are comma separated "HH:MM" values in server local time, empty disables the job.
+	SessionIdleTimeoutHours int
+	ShiftEndTimes           string
+	ShiftLogoutRoles        string
+	ShiftLogoutCheckMinutes int
+
+	// Daily summary report: cron schedule in server local time, "previous" reports the day before the run
+	// and "current" the day of the run. Delivered by email and/or webhook, whichever is configured.
+	DailySummaryCron       string
+	DailySummaryDay        string
+	DailySummaryRecipients string
+	DailySummaryWebhookURL string
+
+	// Outgoing mail
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+
+	// Transaction retry on transient database errors (serialization failures, dropped connections).
+	// Attempts include the first run, 1 disables retrying.
+	DBRetryAttempts    int
+	DBRetryBaseDelayMs int
+
+	// Order duplication: "{n}" in the suffix/prefix is replaced with the copy ordinal (the first order
+	// counts as 1), without it the affix is repeated per copy. Modify original retires the original
+	// under the new identifiers and lets the copy keep the marketplace ones.
+	OrderDuplicateGineeSuffix    string
+	OrderDuplicateTrackingPrefix string
+	OrderDuplicateMax            int
+	OrderDuplicateModifyOriginal bool
+
+	// Instant courier lane: minutes from order creation until an instant courier order must be sent out
+	InstantSLAMinutes      int
+	InstantSLACheckMinutes int
+
+	// Seconds during which the same operator scanning the same tracking again at QC or outbound gets the
+	// existing record back instead of a duplicate error, 0 disables it
+	ScanRepeatWindowSeconds int
+
+	// Background jobs: the API process runs them too unless disabled, set to false when the
+	// standalone worker (cmd/worker) is deployed so each job runs in one place only
+	RunBackgroundJobs bool
+
+	// Daily request quotas per role as comma separated "role=limit" pairs, "default" covers roles not
+	// listed and roles without a limit (and superadmins) are unlimited. Reports covers the report
+	// endpoints, exports the CSV/PDF/ZIP exports and bulk imports the bulk create endpoints.
+	QuotaReports     string
+	QuotaExports     string
+	QuotaBulkImports string
+
+	// Directory holding uploaded files. Readiness fails when the disk it is on has less than the minimum
+	// free space left, 0 disables the disk check.
+	UploadDir           string
+	HealthMinFreeDiskMB int
+
+	// Storage of uploaded files such as product images: "disk" keeps them in the upload directory and
+	// serves them at /uploads, "s3" puts them in an S3 compatible bucket. Files are linked under the
+	// public URL when set (CDN or absolute host), otherwise under /uploads or the bucket URL.
+	StorageDriver    string
+	StoragePublicURL string
+	S3Endpoint       string
+	S3Region         string
+	S3Bucket         string
+	S3AccessKey      string
+	S3SecretKey      string
+
+	// Product image uploads: maximum file size and the edge of the square the thumbnails fit in
+	ProductImageMaxMB    int
+	ProductThumbnailSize int
+
+	// Sort of the order list when the client sends none: comma separated sent_before, created_at or
+	// priority keys, each optionally suffixed with :asc or :desc
+	OrderListDefaultSort string
+
+	// Integration health (courier APIs, email, webhooks): an integration is degraded when at least the
+	// minimum number of calls were made in the window and the given percentage of them failed
+	IntegrationHealthWindowMinutes int
+	IntegrationErrorRatePercent    int
+	IntegrationMinCalls            int
+	IntegrationHealthCheckMinutes  int
+
+	// Productivity weighting of grabbed orders, set per warehouse deployment: pick speed and accuracy
+	// over the last days are weighed by the given weights, pickers with fewer picks count as average.
+	// Instant orders and orders due within the urgent window go to productive pickers first.
+	PickerProductivityDays     int
+	PickerProductivityMinPicks int
+	PickerSpeedWeight          int
+	PickerAccuracyWeight       int
+	PickerUrgentWindowMinutes  int
+
+	// Auto-assignment of ready to pick orders: pickers count as active when seen within the active
+	// minutes and get no more orders once they hold the maximum of open orders (0 for no limit).
+	// With zone affinity orders go to the pickers of the zones their products live in.
+	AutoAssignActiveMinutes int
+	AutoAssignMaxOpenOrders int
+	AutoAssignZoneAffinity  bool
+
+	// Timezone of the warehouse (IANA name), day boundaries of "today" lists, date filters and daily
+	// counts follow it whatever timezone the server and database run in
+	AppTimezone string
+
+	// Order priority: orders become urgent this many hours before their sent before and late once it
+	// passed, the escalation job checks every given minutes
+	OrderUrgentHours          int
+	OrderPriorityCheckMinutes int
+
+	// Box stock forecast: daily usage is averaged over the usage days, coordinators (and the webhook
+	// when set) are alerted when a box is projected to run out within the alert days
+	BoxStockUsageDays    int
+	BoxStockAlertDays    int
+	BoxStockCheckMinutes int
+	BoxStockWebhookURL   string
+
+	// Channel intake monitor: a channel's baseline is its average daily orders over the baseline days.
+	// Coordinators (and the webhook when set) are alerted when a channel goes over its daily quota or
+	// its orders stay below the drop percent of the baseline expected by then, once the minimum
+	// expected orders are reached, checked every given minutes.
+	ChannelIntakeBaselineDays int
+	ChannelIntakeDropPercent  int
+	ChannelIntakeMinExpected  int
+	ChannelIntakeCheckMinutes int
+	ChannelIntakeWebhookURL   string
+
+	// Stuck order monitor: comma separated "status=duration" pairs of how long an order may stay in a
+	// processing status before it is flagged at risk, checked every given minutes. A summary of newly
+	// flagged orders is posted to the webhook (e.g. a Slack incoming webhook) when set.
+	OrderStuckThresholds   string
+	OrderStuckCheckMinutes int
+	OrderStuckWebhookURL   string
+
+	// Read-only GraphQL gateway for the dashboard at /graphql, disabled unless enabled
+	GraphQLEnabled bool
+
+	// Read-only gRPC API the analytics pipeline streams orders, outbounds and QC records from, on its
+	// own port, disabled unless enabled
+	GRPCEnabled bool
+	GRPCPort    string
+
+	// Password policy for new passwords (register, user creation and password changes)
+	PasswordMinLength     int
+	PasswordRequireUpper  bool
+	PasswordRequireLower  bool
+	PasswordRequireDigit  bool
+	PasswordRequireSymbol bool
+
+	// Domain of the placeholder email (<username>@<domain>) of users imported in bulk without one
+	UserImportEmailDomain string
+
+	// Account lockout: this many wrong passwords in a row lock logins for the given minutes, after
+	// which the account unlocks by itself. 0 max failures disables the lockout.
+	LoginMaxFailures    int
+	LoginLockoutMinutes int
+
+	// Language of response messages when the request has no supported Accept-Language (en or id)
+	DefaultLanguage string
+
+	// Ginee order intake: every given minutes, paid orders updated on Ginee since the last run (the
+	// first run looks back the given hours) are fetched from the Ginee OpenAPI and created. Disabled
+	// unless enabled with the access and secret key of a Ginee OpenAPI app.
+	GineeSyncEnabled   bool
+	GineeAPIURL        string
+	GineeAccessKey     string
+	GineeSecretKey     string
+	GineeCountry       string
+	GineePollMinutes   int
+	GineeLookbackHours int
+	GineePageSize      int
+
+	// Address validation of created and edited orders: the geocoding provider (empty disables it) and
+	// its endpoint and key. Required validation rejects orders whose address cannot be placed instead
+	// of keeping them as unresolved.
+	GeocodingProvider         string
+	GeocodingURL              string
+	GeocodingAPIKey           string
+	AddressValidationRequired bool
+
+	// Days back transactions keep the boxes, channels, stores and expeditions they reference from being
+	// deleted, 0 disables the guard
+	MasterDeleteGuardDays int
+
+	// Seconds between checks for settings changed through the admin config API by another process,
+	// 0 to only pick up changes made by this process
+	ConfigReloadSeconds int
+
+	values map[string]string // Raw value of every setting, by environment variable name
 }
 
 func LoadConfig() *Config {
+	return LoadConfigWithOverrides(nil)
+}
+
+// LoadConfigWithOverrides loads the configuration like LoadConfig, with the values in overrides (by
+// environment variable name) taking precedence over the environment and the defaults
+func LoadConfigWithOverrides(overrides map[string]string) *Config {
 	err := godotenv.Load()
 	if err != nil {
 		log.Println("Warning: .env file not found, using environment variables")
 	}
 
+	// Shadows getEnv below for the rest of the function, recording the value of every setting read
+	values := make(map[string]string)
+	getEnv := func(key, defaultValue string) string {
+		value, ok := overrides[key]
+		if !ok {
+			value = getEnv(key, defaultValue)
+		}
+		values[key] = value
+		return value
+	}
+
 	jwtExpireHours, _ := strconv.Atoi(getEnv("JWT_EXPIRE_HOURS", "24"))
 	refreshTokenExpireDays, _ := strconv.Atoi(getEnv("REFRESH_TOKEN_EXPIRE_DAYS", "28"))
+	complainEscalationCSHours, _ := strconv.Atoi(getEnv("COMPLAIN_ESCALATION_CS_HOURS", "24"))
+	complainEscalationCoordinatorHours, _ := strconv.Atoi(getEnv("COMPLAIN_ESCALATION_COORDINATOR_HOURS", "48"))
+	complainEscalationCheckMinutes, _ := strconv.Atoi(getEnv("COMPLAIN_ESCALATION_CHECK_MINUTES", "15"))
+	complainSLAHours, _ := strconv.Atoi(getEnv("COMPLAIN_SLA_HOURS", "48"))
+	complainSLACheckMinutes, _ := strconv.Atoi(getEnv("COMPLAIN_SLA_CHECK_MINUTES", "15"))
+	deliveryTrackingPollMinutes, _ := strconv.Atoi(getEnv("DELIVERY_TRACKING_POLL_MINUTES", "30"))
+	deliveryTrackingMaxDays, _ := strconv.Atoi(getEnv("DELIVERY_TRACKING_MAX_DAYS", "14"))
+	deliveryTrackingBatchSize, _ := strconv.Atoi(getEnv("DELIVERY_TRACKING_BATCH_SIZE", "200"))
+	backupIntervalHours, _ := strconv.Atoi(getEnv("BACKUP_INTERVAL_HOURS", "24"))
+	backupRetentionCount, _ := strconv.Atoi(getEnv("BACKUP_RETENTION_COUNT", "7"))
+	backupMaxAgeHours, _ := strconv.Atoi(getEnv("BACKUP_MAX_AGE_HOURS", "36"))
+	backupTimeoutMinutes, _ := strconv.Atoi(getEnv("BACKUP_TIMEOUT_MINUTES", "120"))
+	backupVerifyIntervalHours, _ := strconv.Atoi(getEnv("BACKUP_VERIFY_INTERVAL_HOURS", "24"))
+	sessionIdleTimeoutHours, _ := strconv.Atoi(getEnv("SESSION_IDLE_TIMEOUT_HOURS", "8"))
+	shiftLogoutCheckMinutes, _ := strconv.Atoi(getEnv("SHIFT_LOGOUT_CHECK_MINUTES", "5"))
+	dbRetryAttempts, _ := strconv.Atoi(getEnv("DB_RETRY_ATTEMPTS", "3"))
+	dbRetryBaseDelayMs, _ := strconv.Atoi(getEnv("DB_RETRY_BASE_DELAY_MS", "50"))
+	orderDuplicateMax, _ := strconv.Atoi(getEnv("ORDER_DUPLICATE_MAX", "3"))
+	orderDuplicateModifyOriginal, _ := strconv.ParseBool(getEnv("ORDER_DUPLICATE_MODIFY_ORIGINAL", "true"))
+	instantSLAMinutes, _ := strconv.Atoi(getEnv("INSTANT_SLA_MINUTES", "60"))
+	instantSLACheckMinutes, _ := strconv.Atoi(getEnv("INSTANT_SLA_CHECK_MINUTES", "5"))
+	scanRepeatWindowSeconds, _ := strconv.Atoi(getEnv("SCAN_REPEAT_WINDOW_SECONDS", "10"))
+	runBackgroundJobs, _ := strconv.ParseBool(getEnv("RUN_BACKGROUND_JOBS", "true"))
+	corsAllowCredentials, _ := strconv.ParseBool(getEnv("CORS_ALLOW_CREDENTIALS", "true"))
+	corsMaxAgeHours, _ := strconv.Atoi(getEnv("CORS_MAX_AGE_HOURS", "12"))
+	gzipMinBytes, _ := strconv.Atoi(getEnv("GZIP_MIN_BYTES", "1024"))
+	dbStatementTimeoutSeconds, _ := strconv.Atoi(getEnv("DB_STATEMENT_TIMEOUT_SECONDS", "300"))
+	queryTimeoutSeconds, _ := strconv.Atoi(getEnv("QUERY_TIMEOUT_SECONDS", "30"))
+	longQueryTimeoutSeconds, _ := strconv.Atoi(getEnv("LONG_QUERY_TIMEOUT_SECONDS", "120"))
+	healthMinFreeDiskMB, _ := strconv.Atoi(getEnv("HEALTH_MIN_FREE_DISK_MB", "500"))
+	integrationHealthWindowMinutes, _ := strconv.Atoi(getEnv("INTEGRATION_HEALTH_WINDOW_MINUTES", "15"))
+	integrationErrorRatePercent, _ := strconv.Atoi(getEnv("INTEGRATION_ERROR_RATE_PERCENT", "50"))
+	integrationMinCalls, _ := strconv.Atoi(getEnv("INTEGRATION_MIN_CALLS", "5"))
+	integrationHealthCheckMinutes, _ := strconv.Atoi(getEnv("INTEGRATION_HEALTH_CHECK_MINUTES", "5"))
+	pickerProductivityDays, _ := strconv.Atoi(getEnv("PICKER_PRODUCTIVITY_DAYS", "14"))
+	pickerProductivityMinPicks, _ := strconv.Atoi(getEnv("PICKER_PRODUCTIVITY_MIN_PICKS", "20"))
+	pickerSpeedWeight, _ := strconv.Atoi(getEnv("PICKER_SPEED_WEIGHT", "50"))
+	pickerAccuracyWeight, _ := strconv.Atoi(getEnv("PICKER_ACCURACY_WEIGHT", "50"))
+	pickerUrgentWindowMinutes, _ := strconv.Atoi(getEnv("PICKER_URGENT_WINDOW_MINUTES", "120"))
+	autoAssignActiveMinutes, _ := strconv.Atoi(getEnv("AUTO_ASSIGN_ACTIVE_MINUTES", "30"))
+	autoAssignMaxOpenOrders, _ := strconv.Atoi(getEnv("AUTO_ASSIGN_MAX_OPEN_ORDERS", "5"))
+	autoAssignZoneAffinity, _ := strconv.ParseBool(getEnv("AUTO_ASSIGN_ZONE_AFFINITY", "true"))
+	orderUrgentHours, _ := strconv.Atoi(getEnv("ORDER_URGENT_HOURS", "6"))
+	orderPriorityCheckMinutes, _ := strconv.Atoi(getEnv("ORDER_PRIORITY_CHECK_MINUTES", "10"))
+	productImageMaxMB, _ := strconv.Atoi(getEnv("PRODUCT_IMAGE_MAX_MB", "5"))
+	productThumbnailSize, _ := strconv.Atoi(getEnv("PRODUCT_THUMBNAIL_SIZE", "240"))
+	boxStockUsageDays, _ := strconv.Atoi(getEnv("BOX_STOCK_USAGE_DAYS", "14"))
+	boxStockAlertDays, _ := strconv.Atoi(getEnv("BOX_STOCK_ALERT_DAYS", "7"))
+	boxStockCheckMinutes, _ := strconv.Atoi(getEnv("BOX_STOCK_CHECK_MINUTES", "60"))
+	channelIntakeBaselineDays, _ := strconv.Atoi(getEnv("CHANNEL_INTAKE_BASELINE_DAYS", "14"))
+	channelIntakeDropPercent, _ := strconv.Atoi(getEnv("CHANNEL_INTAKE_DROP_PERCENT", "50"))
+	channelIntakeMinExpected, _ := strconv.Atoi(getEnv("CHANNEL_INTAKE_MIN_EXPECTED", "20"))
+	channelIntakeCheckMinutes, _ := strconv.Atoi(getEnv("CHANNEL_INTAKE_CHECK_MINUTES", "15"))
+	orderStuckCheckMinutes, _ := strconv.Atoi(getEnv("ORDER_STUCK_CHECK_MINUTES", "15"))
+	graphQLEnabled, _ := strconv.ParseBool(getEnv("GRAPHQL_ENABLED", "false"))
+	grpcEnabled, _ := strconv.ParseBool(getEnv("GRPC_ENABLED", "false"))
+	passwordMinLength, _ := strconv.Atoi(getEnv("PASSWORD_MIN_LENGTH", "8"))
+	passwordRequireUpper, _ := strconv.ParseBool(getEnv("PASSWORD_REQUIRE_UPPER", "false"))
+	passwordRequireLower, _ := strconv.ParseBool(getEnv("PASSWORD_REQUIRE_LOWER", "false"))
+	passwordRequireDigit, _ := strconv.ParseBool(getEnv("PASSWORD_REQUIRE_DIGIT", "true"))
+	passwordRequireSymbol, _ := strconv.ParseBool(getEnv("PASSWORD_REQUIRE_SYMBOL", "false"))
+	loginMaxFailures, _ := strconv.Atoi(getEnv("LOGIN_MAX_FAILURES", "5"))
+	loginLockoutMinutes, _ := strconv.Atoi(getEnv("LOGIN_LOCKOUT_MINUTES", "15"))
+	configReloadSeconds, _ := strconv.Atoi(getEnv("CONFIG_RELOAD_SECONDS", "60"))
+	addressValidationRequired, _ := strconv.ParseBool(getEnv("ADDRESS_VALIDATION_REQUIRED", "false"))
+	masterDeleteGuardDays, _ := strconv.Atoi(getEnv("MASTER_DELETE_GUARD_DAYS", "90"))
+
+	gineeSyncEnabled, _ := strconv.ParseBool(getEnv("GINEE_SYNC_ENABLED", "false"))
+	gineePollMinutes, _ := strconv.Atoi(getEnv("GINEE_POLL_MINUTES", "10"))
+	gineeLookbackHours, _ := strconv.Atoi(getEnv("GINEE_LOOKBACK_HOURS", "24"))
+	gineePageSize, _ := strconv.Atoi(getEnv("GINEE_PAGE_SIZE", "100"))
 
-	return &Config{
+	cfg := &Config{
 		DBHost:                 getEnv("DB_HOST", "localhost"),
 		DBPort:                 getEnv("DB_PORT", "5432"),
 		DBUser:                 getEnv("DB_USER", "Nuxx"),
@@ -49,7 +389,178 @@ func LoadConfig() *Config {
 		CORSAllowedOrigins:     getEnv("CORS_ALLOWED_ORIGINS", "*"),
 		CORSAllowedMethods:     getEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE,OPTIONS"),
 		APIHost:                getEnv("API_HOST", "localhost"),
+
+		CORSAllowedHeaders:   getEnv("CORS_ALLOWED_HEADERS", "Origin,Content-Length,Content-Type,Authorization,Accept,X-Requested-With,If-None-Match"),
+		CORSExposedHeaders:   getEnv("CORS_EXPOSED_HEADERS", "Content-Length,Content-Type,Content-Disposition,ETag,X-Quota-Limit,X-Quota-Remaining,X-Quota-Reset,Retry-After"),
+		CORSAllowCredentials: corsAllowCredentials,
+		CORSMaxAgeHours:      corsMaxAgeHours,
+
+		TrustedProxies:  getEnv("TRUSTED_PROXIES", "127.0.0.1,::1,192.168.31.52,192.168.31.53,192.168.31.54,192.168.31.55"),
+		RemoteIPHeaders: getEnv("REMOTE_IP_HEADERS", "X-Forwarded-For,X-Real-IP"),
+
+		GzipRoutes:   getEnv("GZIP_ROUTES", "/api/orders,/api/reports,/api/outbounds,/api/returns,/api/complains,/api/products,/api/mobile"),
+		GzipMinBytes: gzipMinBytes,
+		ETagRoutes:   getEnv("ETAG_ROUTES", "/api/orders,/api/reports,/api/outbounds,/api/returns,/api/complains,/api/products"),
+
+		DBStatementTimeoutSeconds: dbStatementTimeoutSeconds,
+		QueryTimeoutSeconds:       queryTimeoutSeconds,
+		LongQueryTimeoutSeconds:   longQueryTimeoutSeconds,
+		LongQueryRoutes:           getEnv("LONG_QUERY_ROUTES", "/api/reports,/api/flows,/api/report-presets,/graphql"),
+
+		ComplainEscalationCSHours:          complainEscalationCSHours,
+		ComplainEscalationCoordinatorHours: complainEscalationCoordinatorHours,
+		ComplainEscalationCheckMinutes:     complainEscalationCheckMinutes,
+
+		ComplainSLAHours:        complainSLAHours,
+		ComplainSLACheckMinutes: complainSLACheckMinutes,
+
+		ComplainFeeSplitPolicy:       getEnv("COMPLAIN_FEE_SPLIT_POLICY", "equal"),
+		ComplainFeeSplitStageWeights: getEnv("COMPLAIN_FEE_SPLIT_STAGE_WEIGHTS", "picking=40,pending=10,qc_ribbon=30,qc_online=30,outbound=30,return=10"),
+
+		PickerZoneMode: getEnv("PICKER_ZONE_MODE", "prefer"),
+
+		DeliveryTrackingPollMinutes: deliveryTrackingPollMinutes,
+		DeliveryTrackingMaxDays:     deliveryTrackingMaxDays,
+		DeliveryTrackingBatchSize:   deliveryTrackingBatchSize,
+
+		BackupMethod:              getEnv("BACKUP_METHOD", "pg_dump"),
+		BackupDir:                 getEnv("BACKUP_DIR", "./backups"),
+		BackupIntervalHours:       backupIntervalHours,
+		BackupRetentionCount:      backupRetentionCount,
+		BackupMaxAgeHours:         backupMaxAgeHours,
+		BackupTimeoutMinutes:      backupTimeoutMinutes,
+		BackupVerifyIntervalHours: backupVerifyIntervalHours,
+		BackupVerifySchema:        getEnv("BACKUP_VERIFY_SCHEMA", "backup_verify"),
+		BackupAPIURL:              getEnv("BACKUP_API_URL", ""),
+		BackupAPIToken:            getEnv("BACKUP_API_TOKEN", ""),
+		PgDumpPath:                getEnv("PG_DUMP_PATH", "pg_dump"),
+		PgRestorePath:             getEnv("PG_RESTORE_PATH", "pg_restore"),
+		PsqlPath:                  getEnv("PSQL_PATH", "psql"),
+
+		SessionIdleTimeoutHours: sessionIdleTimeoutHours,
+		ShiftEndTimes:           getEnv("SHIFT_END_TIMES", ""),
+		ShiftLogoutRoles:        getEnv("SHIFT_LOGOUT_ROLES", "picker,outbound,qc-ribbon,qc-online,retur"),
+		ShiftLogoutCheckMinutes: shiftLogoutCheckMinutes,
+
+		DailySummaryCron:       getEnv("DAILY_SUMMARY_CRON", "0 7 * * *"),
+		DailySummaryDay:        getEnv("DAILY_SUMMARY_DAY", "previous"),
+		DailySummaryRecipients: getEnv("DAILY_SUMMARY_RECIPIENTS", ""),
+		DailySummaryWebhookURL: getEnv("DAILY_SUMMARY_WEBHOOK_URL", ""),
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", "livo-backend@localhost"),
+
+		DBRetryAttempts:    dbRetryAttempts,
+		DBRetryBaseDelayMs: dbRetryBaseDelayMs,
+
+		OrderDuplicateGineeSuffix:    getEnv("ORDER_DUPLICATE_GINEE_SUFFIX", "-X{n}"),
+		OrderDuplicateTrackingPrefix: getEnv("ORDER_DUPLICATE_TRACKING_PREFIX", "X-"),
+		OrderDuplicateMax:            orderDuplicateMax,
+		OrderDuplicateModifyOriginal: orderDuplicateModifyOriginal,
+
+		InstantSLAMinutes:      instantSLAMinutes,
+		InstantSLACheckMinutes: instantSLACheckMinutes,
+
+		ScanRepeatWindowSeconds: scanRepeatWindowSeconds,
+
+		RunBackgroundJobs: runBackgroundJobs,
+
+		QuotaReports:     getEnv("QUOTA_REPORTS", "guest=50"),
+		QuotaExports:     getEnv("QUOTA_EXPORTS", "guest=5,default=100"),
+		QuotaBulkImports: getEnv("QUOTA_BULK_IMPORTS", "guest=0,default=200"),
+
+		UploadDir:           getEnv("UPLOAD_DIR", "./uploads"),
+		HealthMinFreeDiskMB: healthMinFreeDiskMB,
+
+		StorageDriver:    getEnv("STORAGE_DRIVER", "disk"),
+		StoragePublicURL: getEnv("STORAGE_PUBLIC_URL", ""),
+		S3Endpoint:       getEnv("S3_ENDPOINT", ""),
+		S3Region:         getEnv("S3_REGION", "ap-southeast-3"),
+		S3Bucket:         getEnv("S3_BUCKET", ""),
+		S3AccessKey:      getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:      getEnv("S3_SECRET_KEY", ""),
+
+		ProductImageMaxMB:    productImageMaxMB,
+		ProductThumbnailSize: productThumbnailSize,
+
+		OrderListDefaultSort: getEnv("ORDER_LIST_DEFAULT_SORT", "created_at:desc"),
+
+		IntegrationHealthWindowMinutes: integrationHealthWindowMinutes,
+		IntegrationErrorRatePercent:    integrationErrorRatePercent,
+		IntegrationMinCalls:            integrationMinCalls,
+		IntegrationHealthCheckMinutes:  integrationHealthCheckMinutes,
+
+		PickerProductivityDays:     pickerProductivityDays,
+		PickerProductivityMinPicks: pickerProductivityMinPicks,
+		PickerSpeedWeight:          pickerSpeedWeight,
+		PickerAccuracyWeight:       pickerAccuracyWeight,
+		PickerUrgentWindowMinutes:  pickerUrgentWindowMinutes,
+
+		AutoAssignActiveMinutes: autoAssignActiveMinutes,
+		AutoAssignMaxOpenOrders: autoAssignMaxOpenOrders,
+		AutoAssignZoneAffinity:  autoAssignZoneAffinity,
+
+		AppTimezone: getEnv("APP_TIMEZONE", "Asia/Jakarta"),
+
+		OrderUrgentHours:          orderUrgentHours,
+		OrderPriorityCheckMinutes: orderPriorityCheckMinutes,
+
+		BoxStockUsageDays:    boxStockUsageDays,
+		BoxStockAlertDays:    boxStockAlertDays,
+		BoxStockCheckMinutes: boxStockCheckMinutes,
+		BoxStockWebhookURL:   getEnv("BOX_STOCK_WEBHOOK_URL", ""),
+
+		ChannelIntakeBaselineDays: channelIntakeBaselineDays,
+		ChannelIntakeDropPercent:  channelIntakeDropPercent,
+		ChannelIntakeMinExpected:  channelIntakeMinExpected,
+		ChannelIntakeCheckMinutes: channelIntakeCheckMinutes,
+		ChannelIntakeWebhookURL:   getEnv("CHANNEL_INTAKE_WEBHOOK_URL", ""),
+
+		OrderStuckThresholds:   getEnv("ORDER_STUCK_THRESHOLDS", "picking process=4h,picking complete=12h,picking completed=12h"),
+		OrderStuckCheckMinutes: orderStuckCheckMinutes,
+		OrderStuckWebhookURL:   getEnv("ORDER_STUCK_WEBHOOK_URL", ""),
+
+		GraphQLEnabled: graphQLEnabled,
+
+		GRPCEnabled: grpcEnabled,
+		GRPCPort:    getEnv("GRPC_PORT", "9090"),
+
+		PasswordMinLength:     passwordMinLength,
+		PasswordRequireUpper:  passwordRequireUpper,
+		PasswordRequireLower:  passwordRequireLower,
+		PasswordRequireDigit:  passwordRequireDigit,
+		PasswordRequireSymbol: passwordRequireSymbol,
+
+		UserImportEmailDomain: getEnv("USER_IMPORT_EMAIL_DOMAIN", "users.livo.local"),
+
+		LoginMaxFailures:    loginMaxFailures,
+		LoginLockoutMinutes: loginLockoutMinutes,
+
+		DefaultLanguage: getEnv("DEFAULT_LANGUAGE", "en"),
+
+		GineeSyncEnabled:   gineeSyncEnabled,
+		GineeAPIURL:        getEnv("GINEE_API_URL", "https://api.ginee.com"),
+		GineeAccessKey:     getEnv("GINEE_ACCESS_KEY", ""),
+		GineeSecretKey:     getEnv("GINEE_SECRET_KEY", ""),
+		GineeCountry:       getEnv("GINEE_COUNTRY", "ID"),
+		GineePollMinutes:   gineePollMinutes,
+		GineeLookbackHours: gineeLookbackHours,
+		GineePageSize:      gineePageSize,
+
+		GeocodingProvider:         getEnv("GEOCODING_PROVIDER", ""),
+		GeocodingURL:              getEnv("GEOCODING_URL", ""),
+		GeocodingAPIKey:           getEnv("GEOCODING_API_KEY", ""),
+		AddressValidationRequired: addressValidationRequired,
+
+		MasterDeleteGuardDays: masterDeleteGuardDays,
+
+		ConfigReloadSeconds: configReloadSeconds,
 	}
+	cfg.values = values
+	return cfg
 }
 
 func getEnv(key, defaultValue string) string {
@@ -17,6 +17,10 @@ func ConnectDatabase(config *Config) {
 		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=UTC",
 		config.DBHost, config.DBUser, config.DBPassword, config.DBName, config.DBPort, config.DBSSLMode,
 	)
+	if config.DBStatementTimeoutSeconds > 0 {
+		// Set on every connection of the pool, also bounds the queries of jobs and backfills
+		dsn += fmt.Sprintf(" statement_timeout=%d", config.DBStatementTimeoutSeconds*1000)
+	}
 
 	maxRetries := 10
 	retryInterval := 10 * time.Second
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Kinds of values a reloadable setting takes
+const (
+	KindInt    = "int"
+	KindBool   = "bool"
+	KindString = "string"
+)
+
+// ReloadableSetting is a setting that can be overridden at runtime through the admin config API
+type ReloadableSetting struct {
+	Key         string // Environment variable name
+	Kind        string
+	Description string
+}
+
+// ReloadableSettings lists the settings that may be overridden at runtime. Only settings applied
+// through bootstrap.ApplySettings are listed, the others are read once by the servers, jobs and
+// middleware they configure and need a restart.
+var ReloadableSettings = []ReloadableSetting{
+	{"INSTANT_SLA_MINUTES", KindInt, "Minutes instant courier orders have to ship"},
+	{"COMPLAIN_SLA_HOURS", KindInt, "Hours complains have to be checked, unless their store sets its own"},
+	{"ORDER_URGENT_HOURS", KindInt, "Hours before the deadline orders are escalated to urgent"},
+	{"ORDER_STUCK_THRESHOLDS", KindString, "Time orders may stay in a processing status, e.g. picking process=4h"},
+	{"ORDER_LIST_DEFAULT_SORT", KindString, "Sort of order lists requested without sort_by"},
+	{"ORDER_DUPLICATE_MAX", KindInt, "Duplicates an order may have"},
+	{"SCAN_REPEAT_WINDOW_SECONDS", KindInt, "Seconds a repeated QC or outbound scan is treated as a double scan"},
+	{"COMPLAIN_FEE_SPLIT_STAGE_WEIGHTS", KindString, "Stage weights for splitting complain fees"},
+	{"PICKER_PRODUCTIVITY_DAYS", KindInt, "Days of picks the picker productivity covers"},
+	{"PICKER_PRODUCTIVITY_MIN_PICKS", KindInt, "Picks before a picker's productivity counts"},
+	{"PICKER_SPEED_WEIGHT", KindInt, "Weight of speed in picker productivity"},
+	{"PICKER_ACCURACY_WEIGHT", KindInt, "Weight of accuracy in picker productivity"},
+	{"PICKER_URGENT_WINDOW_MINUTES", KindInt, "Minutes before the deadline grabbed orders count as urgent"},
+	{"AUTO_ASSIGN_ACTIVE_MINUTES", KindInt, "Minutes since their last request pickers count as active for auto-assignment"},
+	{"AUTO_ASSIGN_MAX_OPEN_ORDERS", KindInt, "Open orders a picker may hold before auto-assignment skips them"},
+	{"AUTO_ASSIGN_ZONE_AFFINITY", KindBool, "Whether auto-assignment prefers pickers of the zones of an order"},
+	{"INTEGRATION_HEALTH_WINDOW_MINUTES", KindInt, "Minutes of integration calls the health covers"},
+	{"INTEGRATION_ERROR_RATE_PERCENT", KindInt, "Error rate at which integrations count as degraded"},
+	{"INTEGRATION_MIN_CALLS", KindInt, "Calls before an integration's error rate counts"},
+	{"BOX_STOCK_USAGE_DAYS", KindInt, "Days of QC the box usage forecast averages"},
+	{"BOX_STOCK_ALERT_DAYS", KindInt, "Days ahead a box running out raises a low stock alert"},
+	{"CHANNEL_INTAKE_BASELINE_DAYS", KindInt, "Days of orders a channel's intake baseline averages"},
+	{"CHANNEL_INTAKE_DROP_PERCENT", KindInt, "Share of the expected orders below which a channel's intake drops"},
+	{"CHANNEL_INTAKE_MIN_EXPECTED", KindInt, "Expected orders before a channel's intake drop is alerted"},
+	{"PASSWORD_MIN_LENGTH", KindInt, "Minimum length of new passwords"},
+	{"PASSWORD_REQUIRE_UPPER", KindBool, "Whether new passwords need an uppercase letter"},
+	{"PASSWORD_REQUIRE_LOWER", KindBool, "Whether new passwords need a lowercase letter"},
+	{"PASSWORD_REQUIRE_DIGIT", KindBool, "Whether new passwords need a digit"},
+	{"PASSWORD_REQUIRE_SYMBOL", KindBool, "Whether new passwords need a symbol"},
+	{"LOGIN_MAX_FAILURES", KindInt, "Wrong passwords before an account is locked"},
+	{"LOGIN_LOCKOUT_MINUTES", KindInt, "Minutes an account stays locked"},
+	{"ADDRESS_VALIDATION_REQUIRED", KindBool, "Whether orders whose address the geocoder cannot place are rejected"},
+	{"MASTER_DELETE_GUARD_DAYS", KindInt, "Days back transactions keep the master data they reference from being deleted"},
+	{"DEFAULT_LANGUAGE", KindString, "Language of response messages when the request asks for none"},
+	{"QUOTA_REPORTS", KindString, "Daily report quota per role, e.g. guest=50"},
+	{"QUOTA_EXPORTS", KindString, "Daily export quota per role"},
+	{"QUOTA_BULK_IMPORTS", KindString, "Daily bulk import quota per role"},
+}
+
+// FindReloadableSetting returns the reloadable setting with the given key
+func FindReloadableSetting(key string) (ReloadableSetting, bool) {
+	for _, setting := range ReloadableSettings {
+		if setting.Key == key {
+			return setting, true
+		}
+	}
+	return ReloadableSetting{}, false
+}
+
+// Validate checks that value is of the setting's kind. Integers may not be negative.
+func (s ReloadableSetting) Validate(value string) error {
+	switch s.Kind {
+	case KindInt:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("%s must be a whole number of at least 0, got %q", s.Key, value)
+		}
+	case KindBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%s must be true or false, got %q", s.Key, value)
+		}
+	case KindString:
+		if value == "" {
+			return fmt.Errorf("%s must not be empty", s.Key)
+		}
+	}
+	return nil
+}
+
+// Value returns the raw value the setting with the given environment variable name was loaded with
+func (c *Config) Value(key string) string {
+	return c.values[key]
+}
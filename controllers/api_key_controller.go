@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type APIKeyController struct {
+	DB *gorm.DB
+}
+
+// NewAPIKeyController creates a new API key controller
+func NewAPIKeyController(db *gorm.DB) *APIKeyController {
+	return &APIKeyController{DB: db}
+}
+
+// GetAPIKeys godoc
+// @Summary Get API keys
+// @Description Get the API keys of machine clients with their scopes, status and last use, newest first. Keys themselves are never returned.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param include_revoked query bool false "Include revoked keys" default(false)
+// @Success 200 {object} utilities.Response{data=APIKeysListResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/api-keys [get]
+func (akc *APIKeyController) GetAPIKeys(c *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	query := akc.DB.WithContext(c).Model(&models.APIKey{})
+	if c.Query("include_revoked") != "true" {
+		query = query.Where("revoked_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count API keys", err.Error())
+		return
+	}
+
+	var apiKeys []models.APIKey
+	if err := query.Preload("User").Preload("Creator").Order("created_at DESC").Limit(limit).Offset(offset).Find(&apiKeys).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve API keys", err.Error())
+		return
+	}
+
+	apiKeyResponses := make([]models.APIKeyResponse, len(apiKeys))
+	for i, apiKey := range apiKeys {
+		apiKeyResponses[i] = apiKey.ToAPIKeyResponse()
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "API keys retrieved successfully", APIKeysListResponse{
+		APIKeys: apiKeyResponses,
+		Pagination: utilities.PaginationResponse{
+			Page:  page,
+			Limit: limit,
+			Total: int(total),
+		},
+	})
+}
+
+// CreateAPIKey godoc
+// @Summary Create API key
+// @Description Create a scoped API key for a machine client. Requests sending it in the X-API-Key header act as the given user (the creator by default), limited to the scopes. The key is only shown in this response.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateAPIKeyRequest true "API key data"
+// @Success 201 {object} utilities.Response{data=CreateAPIKeyResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/api-keys [post]
+func (akc *APIKeyController) CreateAPIKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	scopes, err := models.NormalizeAPIKeyScopes(req.Scopes)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid scopes", err.Error())
+		return
+	}
+
+	currentUserID := c.GetUint("user_id")
+	var currentUser models.User
+	if err := akc.DB.WithContext(c).Preload("UserRoles.Role").First(&currentUser, currentUserID).Error; err != nil {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeUserNotFound, "User not found", err.Error())
+		return
+	}
+
+	// Keys act as a user, which may not outrank the creator
+	keyUser := currentUser
+	if req.UserID != 0 && req.UserID != currentUserID {
+		if err := akc.DB.WithContext(c).Preload("UserRoles.Role").First(&keyUser, req.UserID).Error; err != nil {
+			utilities.LookupErrorResponse(c, err, utilities.ErrCodeUserNotFound, "User not found", "Failed to retrieve user")
+			return
+		}
+		if !currentUser.CanManageUser(&keyUser) {
+			utilities.ErrorResponseCode(c, http.StatusForbidden, utilities.ErrCodeInsufficientAccess, "Insufficient permissions", "cannot create a key acting as a user with an equal or higher role")
+			return
+		}
+	}
+	if !keyUser.IsActive {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeAccountInactive, "User is inactive", "API keys cannot act as an inactive user")
+		return
+	}
+
+	key, prefix, hash, err := models.GenerateAPIKey()
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate API key", err.Error())
+		return
+	}
+
+	apiKey := models.APIKey{
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    scopes,
+		UserID:    keyUser.ID,
+		CreatedBy: currentUserID,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		apiKey.ExpiresAt = &expiresAt
+	}
+
+	if err := akc.DB.WithContext(c).Create(&apiKey).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create API key", err.Error())
+		return
+	}
+	apiKey.User = &keyUser
+	apiKey.Creator = &currentUser
+
+	utilities.SuccessResponse(c, http.StatusCreated, "API key created successfully, store it now as it will not be shown again", CreateAPIKeyResponse{
+		Key:    key,
+		APIKey: apiKey.ToAPIKeyResponse(),
+	})
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke API key
+// @Description Revoke an API key, requests using it are rejected right away
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API key ID"
+// @Success 200 {object} utilities.Response{data=models.APIKeyResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/api-keys/{id} [delete]
+func (akc *APIKeyController) RevokeAPIKey(c *gin.Context) {
+	apiKeyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid API key ID", err.Error())
+		return
+	}
+
+	var apiKey models.APIKey
+	if err := akc.DB.WithContext(c).Preload("User").Preload("Creator").First(&apiKey, uint(apiKeyID)).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeAPIKeyNotFound, "API key not found", "Failed to retrieve API key")
+		return
+	}
+
+	if apiKey.RevokedAt != nil {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeAlreadyResolved, "API key already revoked", "the API key was revoked on "+apiKey.RevokedAt.Format("2006-01-02 15:04:05"))
+		return
+	}
+
+	now := time.Now()
+	userID := c.GetUint("user_id")
+	apiKey.RevokedAt = &now
+	apiKey.RevokedBy = &userID
+	if err := akc.DB.WithContext(c).Model(&apiKey).Updates(map[string]interface{}{"revoked_at": now, "revoked_by": userID}).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to revoke API key", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "API key revoked successfully", apiKey.ToAPIKeyResponse())
+}
+
+// Request/Response structs
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required" example:"Ginee sync"`
+	Scopes        []string `json:"scopes" binding:"required,min=1" example:"orders:write,products:read"`
+	UserID        uint     `json:"user_id" example:"12"`                          // User the key acts as, defaults to the creator
+	ExpiresInDays int      `json:"expires_in_days" binding:"min=0" example:"365"` // 0 for a key that does not expire
+}
+
+type CreateAPIKeyResponse struct {
+	Key    string                `json:"key" example:"livo_3f9a1c2e..."` // Send as X-API-Key, only returned once
+	APIKey models.APIKeyResponse `json:"api_key"`
+}
+
+type APIKeysListResponse struct {
+	APIKeys    []models.APIKeyResponse      `json:"api_keys"`
+	Pagination utilities.PaginationResponse `json:"pagination"`
+}
@@ -1,10 +1,14 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"livo-backend/config"
 	"livo-backend/models"
 	"livo-backend/utilities"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -19,21 +23,26 @@ type AuthController struct {
 type RegisterRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=50" example:"john_doe"`
 	Email    string `json:"email" binding:"required,email" example:"john@example.com"`
-	Password string `json:"password" binding:"required,min=6" example:"password123"`
+	Password string `json:"password" binding:"required" example:"password123"`
 	FullName string `json:"full_name" binding:"required" example:"John Doe"`
 }
 
 // LoginRequest represents the login request
 type LoginRequest struct {
-	Username string `json:"username" binding:"required" example:"john_doe"`
-	Password string `json:"password" binding:"required" example:"password123"`
+	Username   string `json:"username" binding:"required" example:"john_doe"`
+	Password   string `json:"password" binding:"required" example:"password123"`
+	DeviceName string `json:"device_name" binding:"max=100" example:"Zebra TC26"` // Shown in the session list
 }
 
-// LoginResponse represents the login response
+// LoginResponse represents the login response. Users with two-factor authentication get no tokens
+// yet, but a two-factor token to send with their TOTP code to /api/auth/2fa/verify.
 type LoginResponse struct {
-	AccessToken  string              `json:"access_token"`
-	RefreshToken string              `json:"refresh_token"`
-	User         models.UserResponse `json:"user"`
+	AccessToken         string                       `json:"access_token"`
+	RefreshToken        string                       `json:"refresh_token"`
+	User                models.UserResponse          `json:"user"`
+	TwoFactorRequired   bool                         `json:"two_factor_required,omitempty"`
+	TwoFactorToken      string                       `json:"two_factor_token,omitempty"`
+	TwoFactorEnrollment *TwoFactorEnrollmentResponse `json:"two_factor_enrollment,omitempty"` // Set when an enrollment token is redeemed
 }
 
 // RefreshTokenRequest represents the refresh token request
@@ -41,6 +50,32 @@ type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
+// TwoFactorEnrollmentResponse holds a new TOTP secret. Frontends render the URI as a QR code for
+// authenticator apps, the secret is for typing in by hand.
+type TwoFactorEnrollmentResponse struct {
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	URI    string `json:"uri" example:"otpauth://totp/Livo:john_doe?algorithm=SHA1&digits=6&issuer=Livo&period=30&secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+}
+
+// VerifyTwoFactorRequest represents the second login step
+type VerifyTwoFactorRequest struct {
+	TwoFactorToken string `json:"two_factor_token" binding:"required"`
+	Code           string `json:"code" binding:"required" example:"123456"`
+	DeviceName     string `json:"device_name" binding:"max=100" example:"Zebra TC26"` // Shown in the session list
+}
+
+// RedeemTwoFactorEnrollmentRequest represents the enrollment of a user who cannot log in without
+// two-factor authentication
+type RedeemTwoFactorEnrollmentRequest struct {
+	EnrollmentToken string `json:"enrollment_token" binding:"required"`
+	Password        string `json:"password" binding:"required" example:"password123"`
+}
+
+// TwoFactorCodeRequest carries a TOTP code from the user's authenticator app
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
 // NewAuthController creates a new auth controller
 func NewAuthController(db *gorm.DB, config *config.Config) *AuthController {
 	return &AuthController{
@@ -51,7 +86,7 @@ func NewAuthController(db *gorm.DB, config *config.Config) *AuthController {
 
 // Register godoc
 // @Summary Register a new user
-// @Description Register a new user account
+// @Description Register a new user account. The password must meet the configured password policy.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -66,11 +101,15 @@ func (ac *AuthController) Register(c *gin.Context) {
 		utilities.ValidationErrorResponse(c, err)
 		return
 	}
+	if err := utilities.CurrentSettings().PasswordRules.Validate(req.Password); err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodePasswordWeak, "Password does not meet the policy", err.Error())
+		return
+	}
 
 	// Check if user already exists
 	var existingUser models.User
-	if err := ac.DB.Where("username = ? OR email = ?", req.Username, req.Email).First(&existingUser).Error; err == nil {
-		utilities.ErrorResponse(c, http.StatusConflict, "User already exists", "username or email already taken")
+	if err := ac.DB.WithContext(c).Where("username = ? OR email = ?", req.Username, req.Email).First(&existingUser).Error; err == nil {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeUserExists, "User already exists", "username or email already taken")
 		return
 	}
 
@@ -90,31 +129,39 @@ func (ac *AuthController) Register(c *gin.Context) {
 		IsActive: true,
 	}
 
-	if err := ac.DB.Create(&user).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create user", err.Error())
-		return
-	}
+	// The user and its default role are created together
+	err = utilities.WithTransaction(ac.DB.WithContext(c), func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
 
-	// Assign guest role by default
-	var guestRole models.Role
-	if err := ac.DB.Where("name = ?", "guest").First(&guestRole).Error; err == nil {
-		userRole := models.UserRole{
-			UserID:     user.ID,
-			RoleID:     guestRole.ID,
-			AssignedBy: 1,
+		// Assign guest role by default
+		var guestRole models.Role
+		if err := tx.Where("name = ?", "guest").First(&guestRole).Error; err == nil {
+			userRole := models.UserRole{
+				UserID:     user.ID,
+				RoleID:     guestRole.ID,
+				AssignedBy: 1,
+			}
+			return tx.Create(&userRole).Error
 		}
-		ac.DB.Create(&userRole)
+
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to create user")
+		return
 	}
 
 	// Load user with roles
-	ac.DB.Preload("UserRoles.Role").First(&user, user.ID)
+	ac.DB.WithContext(c).Preload("UserRoles.Role").First(&user, user.ID)
 
 	utilities.SuccessResponse(c, http.StatusCreated, "User registered successfully", user.ToUserResponse())
 }
 
 // Login godoc
 // @Summary Login user
-// @Description Authenticate user and return JWT tokens
+// @Description Authenticate user and return JWT tokens. Users with two-factor authentication, or with a role enforcing it, get a two-factor token instead and finish with /api/auth/2fa/verify; users who still have to enroll also get their TOTP secret. Too many wrong passwords in a row lock the account for a while.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -122,6 +169,7 @@ func (ac *AuthController) Register(c *gin.Context) {
 // @Success 200 {object} utilities.Response{data=LoginResponse}
 // @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
+// @Failure 423 {object} utilities.Response
 // @Router /api/auth/login [post]
 func (ac *AuthController) Login(c *gin.Context) {
 	var req LoginRequest
@@ -132,20 +180,76 @@ func (ac *AuthController) Login(c *gin.Context) {
 
 	// Find user
 	var user models.User
-	if err := ac.DB.Preload("UserRoles.Role").Where("username = ?", req.Username).First(&user).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Invalid credentials", "user not found")
+	if err := ac.DB.WithContext(c).Preload("UserRoles.Role").Where("username = ?", req.Username).First(&user).Error; err != nil {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidCredentials, "Invalid credentials", "user not found")
+		return
+	}
+
+	// Locked accounts are refused before the password is checked
+	now := time.Now()
+	if user.LoginLocked(now) {
+		accountLockedResponse(c, &user)
 		return
 	}
 
 	// Check password
 	if !utilities.CheckPasswordHash(req.Password, user.Password) {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Invalid credentials", "incorrect password")
+		if err := models.RecordLoginFailure(ac.DB.WithContext(c), &user, now); err != nil {
+			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to record login attempt", err.Error())
+			return
+		}
+		if user.LoginLocked(now) {
+			accountLockedResponse(c, &user)
+			return
+		}
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidCredentials, "Invalid credentials", "incorrect password")
+		return
+	}
+	if err := models.ResetLoginFailures(ac.DB.WithContext(c), &user); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to record login attempt", err.Error())
 		return
 	}
 
 	// Check if user is active
 	if !user.IsActive {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Account is inactive", "user account is disabled")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeAccountInactive, "Account is inactive", "user account is disabled")
+		return
+	}
+
+	// Enforced by a role but not enrolled yet. The password alone must not enroll an authenticator,
+	// or a leaked password would be enough to pass two-factor authentication.
+	if !user.TwoFactorEnabled() && user.TwoFactorRequired() {
+		utilities.ErrorResponseCode(c, http.StatusForbidden, utilities.ErrCodeTwoFactorRequired, "Two-factor enrollment required", "one of your roles requires two-factor authentication, enroll from a logged-in session or with an enrollment token from an admin")
+		return
+	}
+
+	// The password alone is not enough with two-factor authentication
+	if user.TwoFactorEnabled() {
+		twoFactorToken, err := utilities.GenerateTwoFactorToken(user.ID, ac.Config.JWTSecret, models.TwoFactorChallengeTTL)
+		if err != nil {
+			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate two-factor token", err.Error())
+			return
+		}
+
+		response := LoginResponse{
+			User:              user.ToUserResponse(),
+			TwoFactorRequired: true,
+			TwoFactorToken:    twoFactorToken,
+		}
+		utilities.SuccessResponse(c, http.StatusOK, "Two-factor code required", response)
+		return
+	}
+
+	ac.completeLogin(c, &user, req.DeviceName)
+}
+
+// completeLogin opens a session for the device of a user who passed every login check and issues
+// its tokens
+func (ac *AuthController) completeLogin(c *gin.Context, user *models.User, deviceName string) {
+	now := time.Now()
+	session, err := models.OpenSession(ac.DB.WithContext(c), user.ID, strings.TrimSpace(deviceName), c.Request.UserAgent(), c.ClientIP(), now)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to start session", err.Error())
 		return
 	}
 
@@ -160,6 +264,9 @@ func (ac *AuthController) Login(c *gin.Context) {
 		user.ID,
 		user.Username,
 		roles,
+		user.GetHighestRoleLevel(),
+		user.TokenVersion,
+		session.ID,
 		ac.Config.JWTSecret,
 		ac.Config.JWTExpireHours,
 		ac.Config.RefreshTokenExpireDays,
@@ -169,9 +276,14 @@ func (ac *AuthController) Login(c *gin.Context) {
 		return
 	}
 
-	// Save refresh token
-	user.RefreshToken = refreshToken
-	ac.DB.Save(&user)
+	if err := session.Rotate(ac.DB.WithContext(c), refreshToken, c.ClientIP(), ac.refreshTokenTTL(), now); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to start session", err.Error())
+		return
+	}
+
+	// Logging in starts a fresh activity window
+	user.LastActiveAt = &now
+	ac.DB.WithContext(c).Save(user)
 
 	response := LoginResponse{
 		AccessToken:  accessToken,
@@ -184,7 +296,7 @@ func (ac *AuthController) Login(c *gin.Context) {
 
 // RefreshToken godoc
 // @Summary Refresh access token
-// @Description Generate new access token using refresh token
+// @Description Generate new access token using refresh token. Each refresh replaces the refresh token of the device session, sending a replaced one again revokes the session.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -203,14 +315,56 @@ func (ac *AuthController) RefreshToken(c *gin.Context) {
 	// Validate refresh token
 	claims, err := utilities.ValidateRefreshToken(req.RefreshToken, ac.Config.JWTSecret)
 	if err != nil {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Invalid refresh token", err.Error())
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidRefreshToken, "Invalid refresh token", err.Error())
 		return
 	}
 
 	// Find user
 	var user models.User
-	if err := ac.DB.Preload("UserRoles.Role").Preload("UserRoles.Assigner").Where("id = ? AND refresh_token = ?", claims.UserID, req.RefreshToken).First(&user).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Invalid refresh token", "refresh token not found")
+	if err := ac.DB.WithContext(c).Preload("UserRoles.Role").Preload("UserRoles.Assigner").First(&user, claims.UserID).Error; err != nil {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidRefreshToken, "Invalid refresh token", "user not found")
+		return
+	}
+
+	// Deactivated users cannot renew their tokens
+	if !user.IsActive {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeAccountInactive, "Account is inactive", "user account is disabled")
+		return
+	}
+
+	// Refresh tokens issued before a shift end or an idle timeout are no longer valid. A role change
+	// does not end the session, the new access token carries the current roles and token version.
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+	if err := models.CheckUserSession(ac.DB.WithContext(c), user.ID, issuedAt, user.TokenVersion, time.Duration(ac.Config.SessionIdleTimeoutHours)*time.Hour); err != nil {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeSessionExpired, "Session expired", err.Error())
+		return
+	}
+
+	// Tokens issued before device sessions were tracked match the user's single refresh token and
+	// move to a session of their own
+	now := time.Now()
+	var session *models.Session
+	if claims.SessionID == 0 {
+		if user.RefreshToken == "" || user.RefreshToken != req.RefreshToken {
+			utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidRefreshToken, "Invalid refresh token", "refresh token not found")
+			return
+		}
+		session, err = models.OpenSession(ac.DB.WithContext(c), user.ID, "", c.Request.UserAgent(), c.ClientIP(), now)
+		if err == nil {
+			err = ac.DB.WithContext(c).Model(&user).UpdateColumn("refresh_token", "").Error
+		}
+	} else {
+		session, err = models.FindRefreshSession(ac.DB.WithContext(c), user.ID, claims.SessionID, req.RefreshToken, now)
+		if errors.Is(err, models.ErrSessionRevoked) || errors.Is(err, models.ErrRefreshTokenUsed) {
+			utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidRefreshToken, "Invalid refresh token", err.Error())
+			return
+		}
+	}
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to refresh session", err.Error())
 		return
 	}
 
@@ -225,6 +379,9 @@ func (ac *AuthController) RefreshToken(c *gin.Context) {
 		user.ID,
 		user.Username,
 		roles,
+		user.GetHighestRoleLevel(),
+		user.TokenVersion,
+		session.ID,
 		ac.Config.JWTSecret,
 		ac.Config.JWTExpireHours,
 		ac.Config.RefreshTokenExpireDays,
@@ -234,9 +391,11 @@ func (ac *AuthController) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	// Update refresh token
-	user.RefreshToken = refreshToken
-	ac.DB.Save(&user)
+	// The previous refresh token of the session stops working
+	if err := session.Rotate(ac.DB.WithContext(c), refreshToken, c.ClientIP(), ac.refreshTokenTTL(), now); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to refresh session", err.Error())
+		return
+	}
 
 	response := LoginResponse{
 		AccessToken:  accessToken,
@@ -247,9 +406,14 @@ func (ac *AuthController) RefreshToken(c *gin.Context) {
 	utilities.SuccessResponse(c, http.StatusOK, "Token refreshed successfully", response)
 }
 
+// refreshTokenTTL is how long a refresh token stays valid
+func (ac *AuthController) refreshTokenTTL() time.Duration {
+	return time.Duration(ac.Config.RefreshTokenExpireDays) * 24 * time.Hour
+}
+
 // Logout godoc
 // @Summary Logout user
-// @Description Logout user by invalidating refresh token
+// @Description Logout the device of the access token by revoking its session. Other devices of the user stay logged in.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -260,11 +424,330 @@ func (ac *AuthController) RefreshToken(c *gin.Context) {
 func (ac *AuthController) Logout(c *gin.Context) {
 	userID := c.GetUint("user_id")
 
-	// Clear refresh token
-	if err := ac.DB.Model(&models.User{}).Where("id = ?", userID).Update("refresh_token", "").Error; err != nil {
+	// Revoke the session of this device, tokens from before sessions were tracked clear the user's refresh token
+	var err error
+	if sessionID := c.GetUint("session_id"); sessionID != 0 {
+		err = models.RevokeSession(ac.DB.WithContext(c), userID, sessionID, time.Now())
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = nil
+		}
+	} else {
+		err = ac.DB.WithContext(c).Model(&models.User{}).Where("id = ?", userID).Update("refresh_token", "").Error
+	}
+	if err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to logout", err.Error())
 		return
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, "Logout successful", nil)
 }
+
+// EnrollTwoFactor godoc
+// @Summary Enroll in two-factor authentication
+// @Description Generate a TOTP secret for the logged-in user, returned with an otpauth:// URI to show as a QR code. Two-factor authentication is enabled once a code from the app is sent to /api/auth/2fa/confirm; enrolling again before that replaces the secret.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utilities.Response{data=TwoFactorEnrollmentResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/auth/2fa/enroll [post]
+func (ac *AuthController) EnrollTwoFactor(c *gin.Context) {
+	var user models.User
+	if err := ac.DB.WithContext(c).First(&user, c.GetUint("user_id")).Error; err != nil {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeUserNotFound, "User not found", err.Error())
+		return
+	}
+
+	if user.TwoFactorEnabled() {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeTwoFactorEnabled, "Two-factor authentication already enabled", "disable it first to enroll a new device")
+		return
+	}
+
+	enrollment, err := ac.startTwoFactorEnrollment(c, &user)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to start two-factor enrollment", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Add the secret to an authenticator app and confirm with its code", enrollment)
+}
+
+// ConfirmTwoFactor godoc
+// @Summary Confirm two-factor enrollment
+// @Description Enable two-factor authentication for the logged-in user with a code from the enrolled authenticator app. Later logins require a code.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body TwoFactorCodeRequest true "TOTP code"
+// @Success 200 {object} utilities.Response{data=models.UserResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Failure 429 {object} utilities.Response
+// @Router /api/auth/2fa/confirm [post]
+func (ac *AuthController) ConfirmTwoFactor(c *gin.Context) {
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	var user models.User
+	if err := ac.DB.WithContext(c).Preload("UserRoles.Role").Preload("UserRoles.Assigner").First(&user, c.GetUint("user_id")).Error; err != nil {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeUserNotFound, "User not found", err.Error())
+		return
+	}
+
+	if user.TwoFactorEnabled() {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeTwoFactorEnabled, "Two-factor authentication already enabled", "two-factor authentication is already enabled")
+		return
+	}
+
+	now := time.Now()
+	if err := models.VerifyTwoFactorCode(ac.DB.WithContext(c), &user, req.Code, now); err != nil {
+		twoFactorErrorResponse(c, err)
+		return
+	}
+
+	if err := ac.DB.WithContext(c).Model(&models.User{}).Where("id = ?", user.ID).Update("two_factor_enabled_at", now).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to enable two-factor authentication", err.Error())
+		return
+	}
+	user.TwoFactorEnabledAt = &now
+
+	utilities.SuccessResponse(c, http.StatusOK, "Two-factor authentication enabled", user.ToUserResponse())
+}
+
+// VerifyTwoFactor godoc
+// @Summary Complete login with a two-factor code
+// @Description Exchange the two-factor token from login and a code from the authenticator app for JWT tokens. For users enrolling with an enrollment token the first valid code also enables two-factor authentication. Five wrong codes lock the check for 15 minutes.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body VerifyTwoFactorRequest true "Two-factor token and TOTP code"
+// @Success 200 {object} utilities.Response{data=LoginResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 429 {object} utilities.Response
+// @Router /api/auth/2fa/verify [post]
+func (ac *AuthController) VerifyTwoFactor(c *gin.Context) {
+	var req VerifyTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	claims, err := utilities.ValidateTwoFactorToken(req.TwoFactorToken, ac.Config.JWTSecret)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeTwoFactorInvalid, "Invalid two-factor token", err.Error())
+		return
+	}
+
+	var user models.User
+	if err := ac.DB.WithContext(c).Preload("UserRoles.Role").Preload("UserRoles.Assigner").First(&user, claims.UserID).Error; err != nil {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeTwoFactorInvalid, "Invalid two-factor token", "user not found")
+		return
+	}
+
+	if !user.IsActive {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeAccountInactive, "Account is inactive", "user account is disabled")
+		return
+	}
+
+	now := time.Now()
+	if err := models.VerifyTwoFactorCode(ac.DB.WithContext(c), &user, req.Code, now); err != nil {
+		twoFactorErrorResponse(c, err)
+		return
+	}
+
+	// First code of an enrollment started with an enrollment token
+	if !user.TwoFactorEnabled() {
+		user.TwoFactorEnabledAt = &now
+	}
+
+	ac.completeLogin(c, &user, req.DeviceName)
+}
+
+// RedeemTwoFactorEnrollment godoc
+// @Summary Enroll in two-factor authentication with an enrollment token
+// @Description For users whose role requires two-factor authentication and who have not enrolled, so cannot log in. Redeems the single-use enrollment token an admin issued, together with the user's password, for a TOTP secret and a two-factor token. Sending a code from the authenticator app with the two-factor token to /api/auth/2fa/verify enables two-factor authentication and completes the login.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body RedeemTwoFactorEnrollmentRequest true "Enrollment token and password"
+// @Success 200 {object} utilities.Response{data=LoginResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Failure 423 {object} utilities.Response
+// @Router /api/auth/2fa/enroll/token [post]
+func (ac *AuthController) RedeemTwoFactorEnrollment(c *gin.Context) {
+	var req RedeemTwoFactorEnrollmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	now := time.Now()
+	user, err := models.FindTwoFactorEnrollment(ac.DB.WithContext(c), req.EnrollmentToken, now)
+	if err != nil {
+		if err == models.ErrTwoFactorEnrollmentInvalid {
+			utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeTwoFactorInvalid, "Invalid enrollment token", err.Error())
+			return
+		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to redeem enrollment token", err.Error())
+		return
+	}
+
+	// The token alone is not enough either, wrong passwords count towards the login lock
+	if user.LoginLocked(now) {
+		accountLockedResponse(c, &user)
+		return
+	}
+	if !utilities.CheckPasswordHash(req.Password, user.Password) {
+		if err := models.RecordLoginFailure(ac.DB.WithContext(c), &user, now); err != nil {
+			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to record login attempt", err.Error())
+			return
+		}
+		if user.LoginLocked(now) {
+			accountLockedResponse(c, &user)
+			return
+		}
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidCredentials, "Invalid credentials", "incorrect password")
+		return
+	}
+	if !user.IsActive {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeAccountInactive, "Account is inactive", "user account is disabled")
+		return
+	}
+	if user.TwoFactorEnabled() {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeTwoFactorEnabled, "Two-factor authentication already enabled", "two-factor authentication is already enabled")
+		return
+	}
+
+	if err := models.RedeemTwoFactorEnrollment(ac.DB.WithContext(c), user.ID, req.EnrollmentToken, now); err != nil {
+		if err == models.ErrTwoFactorEnrollmentInvalid {
+			utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeTwoFactorInvalid, "Invalid enrollment token", err.Error())
+			return
+		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to redeem enrollment token", err.Error())
+		return
+	}
+	if err := models.ResetLoginFailures(ac.DB.WithContext(c), &user); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to record login attempt", err.Error())
+		return
+	}
+
+	enrollment, err := ac.startTwoFactorEnrollment(c, &user)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to start two-factor enrollment", err.Error())
+		return
+	}
+	twoFactorToken, err := utilities.GenerateTwoFactorToken(user.ID, ac.Config.JWTSecret, models.TwoFactorChallengeTTL)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate two-factor token", err.Error())
+		return
+	}
+
+	response := LoginResponse{
+		User:                user.ToUserResponse(),
+		TwoFactorRequired:   true,
+		TwoFactorToken:      twoFactorToken,
+		TwoFactorEnrollment: &enrollment,
+	}
+	utilities.SuccessResponse(c, http.StatusOK, "Two-factor authentication is required for your role, add the secret to an authenticator app and send its code", response)
+}
+
+// DisableTwoFactor godoc
+// @Summary Disable two-factor authentication
+// @Description Turn off two-factor authentication for the logged-in user, confirmed with a current code. Not allowed while one of the user's roles enforces it.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body TwoFactorCodeRequest true "TOTP code"
+// @Success 200 {object} utilities.Response{data=models.UserResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 429 {object} utilities.Response
+// @Router /api/auth/2fa/disable [post]
+func (ac *AuthController) DisableTwoFactor(c *gin.Context) {
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	var user models.User
+	if err := ac.DB.WithContext(c).Preload("UserRoles.Role").Preload("UserRoles.Assigner").First(&user, c.GetUint("user_id")).Error; err != nil {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeUserNotFound, "User not found", err.Error())
+		return
+	}
+
+	if !user.TwoFactorEnabled() {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeTwoFactorNotEnabled, "Two-factor authentication not enabled", models.ErrTwoFactorNotEnrolled.Error())
+		return
+	}
+	if user.TwoFactorRequired() {
+		utilities.ErrorResponseCode(c, http.StatusForbidden, utilities.ErrCodeTwoFactorRequired, "Two-factor authentication is required", "one of your roles requires two-factor authentication")
+		return
+	}
+
+	if err := models.VerifyTwoFactorCode(ac.DB.WithContext(c), &user, req.Code, time.Now()); err != nil {
+		twoFactorErrorResponse(c, err)
+		return
+	}
+
+	if err := models.ResetTwoFactor(ac.DB.WithContext(c), user.ID); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to disable two-factor authentication", err.Error())
+		return
+	}
+	user.TwoFactorEnabledAt = nil
+
+	utilities.SuccessResponse(c, http.StatusOK, "Two-factor authentication disabled", user.ToUserResponse())
+}
+
+// startTwoFactorEnrollment gives the user a new pending TOTP secret
+func (ac *AuthController) startTwoFactorEnrollment(c *gin.Context, user *models.User) (TwoFactorEnrollmentResponse, error) {
+	secret, err := utilities.GenerateTOTPSecret()
+	if err != nil {
+		return TwoFactorEnrollmentResponse{}, err
+	}
+
+	if err := ac.DB.WithContext(c).Model(&models.User{}).Where("id = ?", user.ID).
+		Updates(map[string]interface{}{"totp_secret": secret, "two_factor_last_step": 0}).Error; err != nil {
+		return TwoFactorEnrollmentResponse{}, err
+	}
+	user.TOTPSecret = secret
+	user.TwoFactorLastStep = 0
+
+	return TwoFactorEnrollmentResponse{
+		Secret: secret,
+		URI:    utilities.TOTPProvisioningURI(models.TwoFactorIssuer, user.Username, secret),
+	}, nil
+}
+
+// accountLockedResponse writes the response of a login to an account locked after too many wrong
+// passwords
+func accountLockedResponse(c *gin.Context, user *models.User) {
+	utilities.ErrorResponseCode(c, http.StatusLocked, utilities.ErrCodeAccountLocked, "Account is locked",
+		fmt.Sprintf("too many failed logins, try again after %s", user.LockedUntil.Format("2006-01-02 15:04:05")))
+}
+
+// twoFactorErrorResponse writes the response of a failed two-factor code check
+func twoFactorErrorResponse(c *gin.Context, err error) {
+	switch err {
+	case models.ErrTwoFactorInvalidCode:
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeTwoFactorInvalid, "Invalid two-factor code", err.Error())
+	case models.ErrTwoFactorLocked:
+		utilities.ErrorResponseCode(c, http.StatusTooManyRequests, utilities.ErrCodeTwoFactorLocked, "Too many invalid two-factor codes", err.Error())
+	case models.ErrTwoFactorNotEnrolled:
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeTwoFactorNotEnabled, "Two-factor authentication not enrolled", err.Error())
+	default:
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to verify two-factor code", err.Error())
+	}
+}
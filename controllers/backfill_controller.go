@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"errors"
+	"livo-backend/backfill"
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type BackfillController struct {
+	DB *gorm.DB
+}
+
+// NewBackfillController creates a new backfill controller
+func NewBackfillController(db *gorm.DB) *BackfillController {
+	return &BackfillController{DB: db}
+}
+
+// GetBackfills godoc
+// @Summary Get backfills
+// @Description Get the available data backfills with the number of rows each still has to look at, and the most recent runs newest first
+// @Tags backfills
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of recent runs" default(20)
+// @Success 200 {object} utilities.Response{data=BackfillsResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/backfills [get]
+func (bc *BackfillController) GetBackfills(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	available := make([]BackfillInfo, 0, len(backfill.All()))
+	for _, b := range backfill.All() {
+		pending, err := backfill.PendingCount(bc.DB.WithContext(c), b)
+		if err != nil {
+			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count pending rows", err.Error())
+			return
+		}
+		available = append(available, BackfillInfo{Name: b.Name, Description: b.Description, Pending: pending})
+	}
+
+	var runs []models.BackfillRun
+	if err := bc.DB.WithContext(c).Preload("Trigger").Order("started_at DESC").Limit(limit).Find(&runs).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve backfill runs", err.Error())
+		return
+	}
+
+	runResponses := make([]models.BackfillRunResponse, len(runs))
+	for i, run := range runs {
+		runResponses[i] = run.ToBackfillRunResponse()
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Backfills retrieved successfully", BackfillsResponse{
+		Backfills: available,
+		Runs:      runResponses,
+	})
+}
+
+// GetBackfillRun godoc
+// @Summary Get backfill run
+// @Description Get the progress of a backfill run
+// @Tags backfills
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Backfill run ID"
+// @Success 200 {object} utilities.Response{data=models.BackfillRunResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/backfills/runs/{id} [get]
+func (bc *BackfillController) GetBackfillRun(c *gin.Context) {
+	runID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid backfill run ID", err.Error())
+		return
+	}
+
+	var run models.BackfillRun
+	if err := bc.DB.WithContext(c).Preload("Trigger").First(&run, uint(runID)).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeBackfillNotFound, "Backfill run not found", "Failed to retrieve backfill run")
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Backfill run retrieved successfully", run.ToBackfillRunResponse())
+}
+
+// StartBackfill godoc
+// @Summary Start a backfill
+// @Description Start a data backfill in the background. Backfills only fill rows still missing the value, so running one again is safe. Poll the run for progress.
+// @Tags backfills
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Backfill name" example(order-courier-lane)
+// @Param request body StartBackfillRequest false "Batch size"
+// @Success 202 {object} utilities.Response{data=models.BackfillRunResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/backfills/{name} [post]
+func (bc *BackfillController) StartBackfill(c *gin.Context) {
+	var req StartBackfillRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utilities.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+
+	userID := c.GetUint("user_id")
+	run, err := backfill.Start(bc.DB.WithContext(c), c.Param("name"), req.BatchSize, &userID)
+	if errors.Is(err, backfill.ErrUnknownBackfill) {
+		utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeBackfillNotFound, "Backfill not found", err.Error())
+		return
+	}
+	if err == backfill.ErrBackfillRunning {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeJobRunning, "Backfill is already running", err.Error())
+		return
+	}
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to start backfill", err.Error())
+		return
+	}
+
+	go func(run *models.BackfillRun) {
+		if err := backfill.Execute(bc.DB, run, nil); err != nil {
+			log.Printf("⚠️ Backfill %s (run %d) failed: %v", run.Name, run.ID, err)
+			return
+		}
+		log.Printf("✓ Backfill %s (run %d) completed: %d of %d row(s) updated", run.Name, run.ID, run.Updated, run.Scanned)
+	}(run)
+
+	utilities.SuccessResponse(c, http.StatusAccepted, "Backfill started", run.ToBackfillRunResponse())
+}
+
+// Request/Response structs
+type StartBackfillRequest struct {
+	BatchSize int `json:"batch_size" binding:"min=0,max=5000" example:"500"` // Rows per transaction, defaults to 500
+}
+
+type BackfillInfo struct {
+	Name        string `json:"name" example:"order-courier-lane"`
+	Description string `json:"description"`
+	Pending     int64  `json:"pending" example:"1200"` // Rows still missing the value
+}
+
+type BackfillsResponse struct {
+	Backfills []BackfillInfo               `json:"backfills"`
+	Runs      []models.BackfillRunResponse `json:"runs"`
+}
@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"context"
+	"livo-backend/backup"
+	"livo-backend/config"
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type BackupController struct {
+	DB     *gorm.DB
+	Config *config.Config
+}
+
+// NewBackupController creates a new backup controller
+func NewBackupController(db *gorm.DB, cfg *config.Config) *BackupController {
+	return &BackupController{DB: db, Config: cfg}
+}
+
+// GetBackups godoc
+// @Summary Get backup history
+// @Description Get database backup runs with their restore verification result, newest first
+// @Tags backups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utilities.Response{data=BackupsListResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/backups [get]
+func (bc *BackupController) GetBackups(c *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	var backups []models.Backup
+	var total int64
+
+	if err := bc.DB.WithContext(c).Model(&models.Backup{}).Count(&total).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count backups", err.Error())
+		return
+	}
+
+	if err := bc.DB.WithContext(c).Preload("Trigger").Order("started_at DESC").Limit(limit).Offset(offset).Find(&backups).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve backups", err.Error())
+		return
+	}
+
+	backupResponses := make([]models.BackupResponse, len(backups))
+	for i, b := range backups {
+		backupResponses[i] = b.ToBackupResponse()
+	}
+
+	response := BackupsListResponse{
+		Backups: backupResponses,
+		Health:  backup.CheckHealth(bc.DB.WithContext(c), bc.Config),
+		Pagination: utilities.PaginationResponse{
+			Page:  page,
+			Limit: limit,
+			Total: int(total),
+		},
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Backups retrieved successfully", response)
+}
+
+// GetBackupHealth godoc
+// @Summary Get backup health
+// @Description Report whether a recent successful backup exists and whether the latest restore verification passed
+// @Tags backups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utilities.Response{data=backup.Health}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/backups/health [get]
+func (bc *BackupController) GetBackupHealth(c *gin.Context) {
+	utilities.SuccessResponse(c, http.StatusOK, "Backup health retrieved successfully", backup.CheckHealth(bc.DB.WithContext(c), bc.Config))
+}
+
+// CreateBackup godoc
+// @Summary Trigger a backup
+// @Description Start a database backup now. The backup runs in the background, poll the backup history for the result.
+// @Tags backups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} utilities.Response{data=models.BackupResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/backups [post]
+func (bc *BackupController) CreateBackup(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	record, err := backup.Start(bc.DB.WithContext(c), bc.Config, &userID)
+	if err == backup.ErrBackupDisabled {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeFeatureDisabled, "Backups are disabled", err.Error())
+		return
+	}
+	if err == backup.ErrBackupRunning {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeJobRunning, "A backup is already running", err.Error())
+		return
+	}
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to start backup", err.Error())
+		return
+	}
+
+	go func(record *models.Backup) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(bc.Config.BackupTimeoutMinutes)*time.Minute)
+		defer cancel()
+
+		if err := backup.Execute(ctx, bc.DB, bc.Config, record); err != nil {
+			log.Printf("⚠️ Backup %d failed: %v", record.ID, err)
+		}
+	}(record)
+
+	utilities.SuccessResponse(c, http.StatusAccepted, "Backup started", record.ToBackupResponse())
+}
+
+// VerifyBackup godoc
+// @Summary Verify a backup
+// @Description Restore a pg_dump backup into the scratch schema in the background and record whether it succeeded
+// @Tags backups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Backup ID"
+// @Success 202 {object} utilities.Response{data=models.BackupResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/backups/{id}/verify [post]
+func (bc *BackupController) VerifyBackup(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid backup ID", err.Error())
+		return
+	}
+
+	var record models.Backup
+	if err := bc.DB.WithContext(c).Preload("Trigger").First(&record, uint(id)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeBackupNotFound, "Backup not found", err.Error())
+			return
+		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve backup", err.Error())
+		return
+	}
+
+	if record.Method != models.BackupMethodPgDump || record.Status != models.BackupStatusSuccess || record.Location == "" {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeBackupUnavailable, "Backup cannot be verified", "only successful pg_dump backups that are still on disk can be verified")
+		return
+	}
+
+	if record.VerificationStatus == models.BackupVerificationRunning {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeJobRunning, "Backup verification is already running", "verification in progress")
+		return
+	}
+
+	go func(record models.Backup) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(bc.Config.BackupTimeoutMinutes)*time.Minute)
+		defer cancel()
+
+		if err := backup.Verify(ctx, bc.DB, bc.Config, &record); err != nil {
+			log.Printf("⚠️ Verification of backup %d failed: %v", record.ID, err)
+		}
+	}(record)
+
+	record.VerificationStatus = models.BackupVerificationRunning
+	utilities.SuccessResponse(c, http.StatusAccepted, "Backup verification started", record.ToBackupResponse())
+}
+
+// Request/Response structs
+type BackupsListResponse struct {
+	Backups    []models.BackupResponse      `json:"backups"`
+	Health     backup.Health                `json:"health"`
+	Pagination utilities.PaginationResponse `json:"pagination"`
+}
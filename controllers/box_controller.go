@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"fmt"
 	"livo-backend/models"
 	"livo-backend/utilities"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BoxController struct {
@@ -30,7 +32,10 @@ func NewBoxController(db *gorm.DB) *BoxController {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param search query string false "Search by box code (partial match)"
+// @Param sort_by query string false "Sort keys, comma separated, each optionally suffixed with :asc or :desc (created_at, code, name, stock)"
+// @Param sort_dir query string false "Direction of sort keys without a suffix: asc or desc" default(asc)
 // @Success 200 {object} utilities.Response{data=BoxesListResponse}
+// @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Router /api/boxes [get]
@@ -47,21 +52,27 @@ func (bc *BoxController) GetBoxes(c *gin.Context) {
 	var total int64
 
 	// Build query with optional search
-	query := bc.DB.Model(&models.Box{})
+	query := bc.DB.WithContext(c).Model(&models.Box{})
 
 	if search != "" {
 		// Search by box code with partial match
 		query = query.Where("code ILIKE ?", "%"+search+"%")
 	}
 
+	sortScope, err := utilities.ApplySort(c, models.BoxSortKeys, "", "boxes.id")
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid sort", err.Error())
+		return
+	}
+
 	// Get total count with search filter
 	if err := query.Count(&total).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count boxes", err.Error())
 		return
 	}
 
-	// Get boxes with pagination, search filter, and order by ID ascending
-	if err := query.Order("id ASC").Limit(limit).Offset(offset).Find(&boxes).Error; err != nil {
+	// Get boxes with pagination, search filter, and sorting
+	if err := query.Scopes(sortScope).Limit(limit).Offset(offset).Find(&boxes).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve boxes", err.Error())
 		return
 	}
@@ -82,9 +93,9 @@ func (bc *BoxController) GetBoxes(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Boxes retrieved successfully"
+	message := utilities.Messagef(c, "Boxes retrieved successfully")
 	if search != "" {
-		message += " (filtered by code: " + search + ")"
+		message += utilities.Messagef(c, " (filtered by code: %s)", search)
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
@@ -107,8 +118,8 @@ func (bc *BoxController) GetBox(c *gin.Context) {
 	boxID := c.Param("id")
 
 	var box models.Box
-	if err := bc.DB.First(&box, boxID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Box not found", err.Error())
+	if err := bc.DB.WithContext(c).First(&box, boxID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeBoxNotFound, "Box not found", "Failed to retrieve box")
 		return
 	}
 
@@ -140,23 +151,29 @@ func (bc *BoxController) UpdateBox(c *gin.Context) {
 	}
 
 	var box models.Box
-	if err := bc.DB.First(&box, boxID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Box not found", err.Error())
+	if err := bc.DB.WithContext(c).First(&box, boxID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeBoxNotFound, "Box not found", "Failed to retrieve box")
 		return
 	}
 
 	// Check for duplicate box code (excluding current box)
 	var existingBox models.Box
-	if err := bc.DB.Where("code = ? AND id != ?", req.Code, boxID).First(&existingBox).Error; err == nil {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Box code already exists", "A box with this code already exists")
+	if err := bc.DB.WithContext(c).Where("code = ? AND id != ?", req.Code, boxID).First(&existingBox).Error; err == nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDuplicateCode, "Box code already exists", "A box with this code already exists")
 		return
 	}
 
 	// Update box fields
 	box.Code = req.Code
 	box.Name = req.Name
-
-	if err := bc.DB.Save(&box).Error; err != nil {
+	box.InnerLength = req.InnerLength
+	box.InnerWidth = req.InnerWidth
+	box.InnerHeight = req.InnerHeight
+	box.MaxWeight = req.MaxWeight
+	box.Cost = req.Cost
+
+	// Stock is left out, QC may have used boxes since it was read
+	if err := bc.DB.WithContext(c).Model(&box).Select("Code", "Name", "InnerLength", "InnerWidth", "InnerHeight", "MaxWeight", "Cost").Updates(&box).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update box", err.Error())
 		return
 	}
@@ -164,9 +181,55 @@ func (bc *BoxController) UpdateBox(c *gin.Context) {
 	utilities.SuccessResponse(c, http.StatusOK, "Box updated successfully", box.ToBoxResponse())
 }
 
+// UpdateBoxStock godoc
+// @Summary Update box stock
+// @Description Book a replenishment of a box (mode add, the default) or a stock count (mode set). The first stock update starts tracking the stock of the box: from then on boxes used at QC are taken off it and the low stock alert covers it. Updating the stock rearms the low stock alert.
+// @Tags boxes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Box ID"
+// @Param request body UpdateBoxStockRequest true "Update box stock request"
+// @Success 200 {object} utilities.Response{data=models.BoxResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/boxes/{id}/stock [put]
+func (bc *BoxController) UpdateBoxStock(c *gin.Context) {
+	boxID := c.Param("id")
+
+	var req UpdateBoxStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	if req.Mode == "" {
+		req.Mode = models.BoxStockAdd
+	}
+	if req.Mode == models.BoxStockAdd && req.Quantity == 0 {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid quantity", "quantity must be at least 1 when adding stock")
+		return
+	}
+
+	var box models.Box
+	if err := bc.DB.WithContext(c).First(&box, boxID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeBoxNotFound, "Box not found", "Failed to retrieve box")
+		return
+	}
+
+	if err := models.UpdateBoxStock(bc.DB.WithContext(c), &box, req.Mode, req.Quantity); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update box stock", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Box stock updated successfully", box.ToBoxResponse())
+}
+
 // RemoveBox godoc
 // @Summary Remove box
-// @Description Soft delete a box.
+// @Description Soft delete a box. Boxs referenced by QC details of the last MASTER_DELETE_GUARD_DAYS days cannot be deleted. Deleted boxs are listed in the trash and can be restored.
 // @Tags boxes
 // @Accept json
 // @Produce json
@@ -176,17 +239,29 @@ func (bc *BoxController) UpdateBox(c *gin.Context) {
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
 // @Router /api/boxes/{id} [delete]
 func (bc *BoxController) RemoveBox(c *gin.Context) {
 	boxID := c.Param("id")
 
 	var box models.Box
-	if err := bc.DB.First(&box, boxID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Box not found", err.Error())
+	if err := bc.DB.WithContext(c).First(&box, boxID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeBoxNotFound, "Box not found", "Failed to retrieve box")
 		return
 	}
 
-	if err := bc.DB.Delete(&box).Error; err != nil {
+	// Keep boxes that recent transactions still point to
+	references, err := box.RecentReferences(bc.DB.WithContext(c))
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to check box references", err.Error())
+		return
+	}
+	if len(references) > 0 {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeMasterInUse, "Box is still in use", models.DescribeMasterReferences(references))
+		return
+	}
+
+	if err := bc.DB.WithContext(c).Delete(&box).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to remove box", err.Error())
 		return
 	}
@@ -194,6 +269,105 @@ func (bc *BoxController) RemoveBox(c *gin.Context) {
 	utilities.SuccessResponse(c, http.StatusOK, "Box removed successfully", nil)
 }
 
+// GetBoxTrash godoc
+// @Summary Get deleted boxes
+// @Description Get the soft deleted boxes, most recently deleted first.
+// @Tags boxes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param search query string false "Search by box code (partial match)"
+// @Success 200 {object} utilities.Response{data=BoxesListResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/boxes/trash [get]
+func (bc *BoxController) GetBoxTrash(c *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	search := c.Query("search")
+
+	query := bc.DB.WithContext(c).Unscoped().Model(&models.Box{}).Where("deleted_at IS NOT NULL")
+	if search != "" {
+		query = query.Where("code ILIKE ?", "%"+search+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count deleted boxes", err.Error())
+		return
+	}
+
+	var boxes []models.Box
+	if err := query.Order("deleted_at DESC, id DESC").Limit(limit).Offset(offset).Find(&boxes).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve deleted boxes", err.Error())
+		return
+	}
+
+	boxResponses := make([]models.BoxResponse, len(boxes))
+	for i := range boxes {
+		boxResponses[i] = boxes[i].ToBoxResponse()
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Deleted boxes retrieved successfully", BoxesListResponse{
+		Boxes: boxResponses,
+		Pagination: utilities.PaginationResponse{
+			Page:  page,
+			Limit: limit,
+			Total: int(total),
+		},
+	})
+}
+
+// RestoreBox godoc
+// @Summary Restore a deleted box
+// @Description Restore a soft deleted box from the trash.
+// @Tags boxes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Box ID"
+// @Success 200 {object} utilities.Response{data=models.BoxResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/boxes/{id}/restore [post]
+func (bc *BoxController) RestoreBox(c *gin.Context) {
+	boxID := c.Param("id")
+
+	var box models.Box
+	err := utilities.WithTransaction(bc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Find the box, deleted or not, locked until commit
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&box, boxID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeBoxNotFound, "Box not found", "no box found with the specified ID")
+			}
+			return err
+		}
+
+		if !box.DeletedAt.Valid {
+			return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeStateInvalid, "Box is not deleted", "only deleted boxes can be restored")
+		}
+
+		if err := tx.Unscoped().Model(&box).Update("deleted_at", nil).Error; err != nil {
+			return fmt.Errorf("failed to restore box: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to restore box")
+		return
+	}
+
+	box.DeletedAt = gorm.DeletedAt{}
+	utilities.SuccessResponse(c, http.StatusOK, "Box restored successfully", box.ToBoxResponse())
+}
+
 // CreateBox godoc
 // @Summary Create new box
 // @Description Create a new box.
@@ -218,19 +392,24 @@ func (bc *BoxController) CreateBox(c *gin.Context) {
 	req.Code = strings.ToUpper(strings.TrimSpace(req.Code))
 
 	box := models.Box{
-		Code: req.Code,
-		Name: req.Name,
+		Code:        req.Code,
+		Name:        req.Name,
+		InnerLength: req.InnerLength,
+		InnerWidth:  req.InnerWidth,
+		InnerHeight: req.InnerHeight,
+		MaxWeight:   req.MaxWeight,
+		Cost:        req.Cost,
 	}
 
 	// Check for duplicate box code
 	var existingBox models.Box
-	if err := bc.DB.Where("code = ?", req.Code).First(&existingBox).Error; err == nil {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Box code already exists", "A box with this code already exists")
+	if err := bc.DB.WithContext(c).Where("code = ?", req.Code).First(&existingBox).Error; err == nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDuplicateCode, "Box code already exists", "A box with this code already exists")
 		return
 	}
 
 	// Create a new box and return the response
-	if err := bc.DB.Create(&box).Error; err != nil {
+	if err := bc.DB.WithContext(c).Create(&box).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create box", err.Error())
 		return
 	}
@@ -245,11 +424,26 @@ type BoxesListResponse struct {
 }
 
 type UpdateBoxRequest struct {
-	Code string `json:"code" binding:"required"`
-	Name string `json:"name" binding:"required"`
+	Code        string  `json:"code" binding:"required"`
+	Name        string  `json:"name" binding:"required"`
+	InnerLength float64 `json:"inner_length" binding:"min=0" example:"40"`
+	InnerWidth  float64 `json:"inner_width" binding:"min=0" example:"20"`
+	InnerHeight float64 `json:"inner_height" binding:"min=0" example:"15"`
+	MaxWeight   int     `json:"max_weight" binding:"min=0" example:"5000"`
+	Cost        int     `json:"cost" binding:"min=0" example:"3500"`
 }
 
 type CreateBoxRequest struct {
-	Code string `json:"code" binding:"required"`
-	Name string `json:"name" binding:"required"`
+	Code        string  `json:"code" binding:"required"`
+	Name        string  `json:"name" binding:"required"`
+	InnerLength float64 `json:"inner_length" binding:"min=0" example:"40"`
+	InnerWidth  float64 `json:"inner_width" binding:"min=0" example:"20"`
+	InnerHeight float64 `json:"inner_height" binding:"min=0" example:"15"`
+	MaxWeight   int     `json:"max_weight" binding:"min=0" example:"5000"`
+	Cost        int     `json:"cost" binding:"min=0" example:"3500"`
+}
+
+type UpdateBoxStockRequest struct {
+	Mode     string `json:"mode" binding:"omitempty,oneof=add set" example:"add"` // add (replenishment, default) or set (stock count)
+	Quantity int    `json:"quantity" binding:"min=0" example:"500"`
 }
@@ -0,0 +1,270 @@
+package controllers
+
+import (
+	"encoding/json"
+	"livo-backend/config"
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type BulkFailureController struct {
+	DB     *gorm.DB
+	Config *config.Config
+}
+
+// NewBulkFailureController creates a new bulk failure controller
+func NewBulkFailureController(db *gorm.DB, cfg *config.Config) *BulkFailureController {
+	return &BulkFailureController{DB: db, Config: cfg}
+}
+
+// Retry outcomes
+const (
+	bulkRetrySucceeded = "succeeded"
+	bulkRetrySkipped   = "skipped"
+	bulkRetryFailed    = "failed"
+)
+
+// GetBulkFailures godoc
+// @Summary Get bulk failures
+// @Description Get rows that failed in bulk order creation, bulk picker assignment and bulk outbound scans, newest first
+// @Tags bulk-failures
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param operation query string false "Bulk operation" Enums(order_create, picker_assign, outbound_create)
+// @Param status query string false "Failure status, all for every status" default(open) Enums(open, resolved, all)
+// @Param search query string false "Search by tracking or Ginee order ID"
+// @Success 200 {object} utilities.Response{data=BulkFailuresListResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/bulk-failures [get]
+func (bfc *BulkFailureController) GetBulkFailures(c *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	query := bfc.DB.WithContext(c).Model(&models.BulkFailure{})
+
+	if operation := c.Query("operation"); operation != "" {
+		if !isBulkOperation(operation) {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeUnknownOperation, "Invalid operation", "operation must be one of order_create, picker_assign or outbound_create")
+			return
+		}
+		query = query.Where("operation = ?", operation)
+	}
+
+	switch status := c.DefaultQuery("status", models.BulkFailureOpen); status {
+	case "all":
+	case models.BulkFailureOpen, models.BulkFailureResolved:
+		query = query.Where("status = ?", status)
+	default:
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid status", "status must be open, resolved or all")
+		return
+	}
+
+	if search := c.Query("search"); search != "" {
+		query = query.Where("item_key ILIKE ?", "%"+search+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count bulk failures", err.Error())
+		return
+	}
+
+	var failures []models.BulkFailure
+	if err := query.Preload("Creator").Preload("Resolver").Order("created_at DESC").Limit(limit).Offset(offset).Find(&failures).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve bulk failures", err.Error())
+		return
+	}
+
+	failureResponses := make([]models.BulkFailureResponse, len(failures))
+	for i, failure := range failures {
+		failureResponses[i] = failure.ToBulkFailureResponse()
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Bulk failures retrieved successfully", BulkFailuresListResponse{
+		BulkFailures: failureResponses,
+		Pagination: utilities.PaginationResponse{
+			Page:  page,
+			Limit: limit,
+			Total: int(total),
+		},
+	})
+}
+
+// RetryBulkFailure godoc
+// @Summary Retry a bulk failure
+// @Description Send the failed row through its bulk operation again, acting as the current user. The failure is resolved when the row goes through or turns out to need nothing (order already exists, parcel already sent out), otherwise its attempts and error are updated.
+// @Tags bulk-failures
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Bulk failure ID"
+// @Success 200 {object} utilities.Response{data=RetryBulkFailureResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Failure 422 {object} utilities.Response
+// @Router /api/bulk-failures/{id}/retry [post]
+func (bfc *BulkFailureController) RetryBulkFailure(c *gin.Context) {
+	failureID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid bulk failure ID", err.Error())
+		return
+	}
+
+	var failure models.BulkFailure
+	if err := bfc.DB.WithContext(c).First(&failure, uint(failureID)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeBulkFailureNotFound, "Bulk failure not found", err.Error())
+			return
+		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to find bulk failure", err.Error())
+		return
+	}
+
+	if failure.Status == models.BulkFailureResolved {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeAlreadyResolved, "Bulk failure already resolved", failure.Resolution)
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	outcome, detail, result, err := bfc.retry(c, userID, &failure)
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to retry bulk failure")
+		return
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{"last_retry_at": now}
+	if outcome == bulkRetryFailed {
+		updates["attempts"] = gorm.Expr("attempts + 1")
+		updates["error"] = detail
+	} else {
+		updates["status"] = models.BulkFailureResolved
+		updates["resolution"] = detail
+		updates["resolved_at"] = now
+		updates["resolved_by"] = userID
+	}
+	if err := bfc.DB.WithContext(c).Model(&failure).Updates(updates).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update bulk failure", err.Error())
+		return
+	}
+
+	if err := bfc.DB.WithContext(c).Preload("Creator").Preload("Resolver").First(&failure, failure.ID).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload bulk failure", err.Error())
+		return
+	}
+
+	message := "Bulk failure retried successfully"
+	if outcome == bulkRetryFailed {
+		message = "Retry failed again"
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, message, RetryBulkFailureResponse{
+		Outcome:     outcome,
+		BulkFailure: failure.ToBulkFailureResponse(),
+		Result:      result,
+	})
+}
+
+// retry runs the stored single row request through its bulk operation and returns the outcome with
+// the skip reason or error of the row, and the bulk response
+func (bfc *BulkFailureController) retry(c *gin.Context, userID uint, failure *models.BulkFailure) (string, string, interface{}, error) {
+	invalidPayload := func(err error) error {
+		return utilities.NewStatusErrorCode(http.StatusUnprocessableEntity, utilities.ErrCodeValidationFailed, "Invalid bulk failure payload", err.Error())
+	}
+
+	switch failure.Operation {
+	case models.BulkOperationOrderCreate:
+		var req BulkCreateOrderRequest
+		if err := json.Unmarshal([]byte(failure.Payload), &req); err != nil {
+			return "", "", nil, invalidPayload(err)
+		}
+		orderController := NewOrderController(bfc.DB.WithContext(c))
+		response := orderController.bulkCreateOrders(c, req)
+		switch {
+		case len(response.FailedOrders) > 0:
+			return bulkRetryFailed, response.FailedOrders[0].Error, response, nil
+		case len(response.SkippedOrders) > 0:
+			return bulkRetrySkipped, response.SkippedOrders[0].Reason, response, nil
+		}
+		return bulkRetrySucceeded, "Order created on retry", response, nil
+
+	case models.BulkOperationPickerAssign:
+		var req MobileBulkAssignPickerRequest
+		if err := json.Unmarshal([]byte(failure.Payload), &req); err != nil {
+			return "", "", nil, invalidPayload(err)
+		}
+		var picker models.User
+		if err := bfc.DB.WithContext(c).First(&picker, req.PickerID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return bulkRetryFailed, "Picker no longer exists", nil, nil
+			}
+			return "", "", nil, err
+		}
+		mobileOrderController := NewMobileOrderController(bfc.DB.WithContext(c), bfc.Config)
+		response := mobileOrderController.bulkAssignPicker(c, userID, req)
+		switch {
+		case len(response.FailedOrders) > 0:
+			return bulkRetryFailed, response.FailedOrders[0].Error, response, nil
+		case len(response.SkippedOrders) > 0:
+			return bulkRetrySkipped, response.SkippedOrders[0].Reason, response, nil
+		}
+		return bulkRetrySucceeded, "Picker assigned on retry", response, nil
+
+	case models.BulkOperationOutboundCreate:
+		var req BulkCreateOutboundRequest
+		if err := json.Unmarshal([]byte(failure.Payload), &req); err != nil {
+			return "", "", nil, invalidPayload(err)
+		}
+		outboundController := NewOutboundController(bfc.DB.WithContext(c))
+		response, err := outboundController.bulkCreateOutbounds(c, userID, req)
+		if err != nil {
+			return "", "", nil, err
+		}
+		switch {
+		case len(response.FailedTrackings) > 0:
+			return bulkRetryFailed, response.FailedTrackings[0].Error, response, nil
+		case len(response.SkippedTrackings) > 0:
+			return bulkRetrySkipped, response.SkippedTrackings[0].Reason, response, nil
+		}
+		return bulkRetrySucceeded, "Outbound created on retry", response, nil
+	}
+
+	return "", "", nil, utilities.NewStatusErrorCode(http.StatusUnprocessableEntity, utilities.ErrCodeUnknownOperation, "Unknown bulk operation", "bulk operation "+failure.Operation+" cannot be retried")
+}
+
+func isBulkOperation(operation string) bool {
+	for _, known := range models.BulkOperations {
+		if known == operation {
+			return true
+		}
+	}
+	return false
+}
+
+// Request/Response structs
+type BulkFailuresListResponse struct {
+	BulkFailures []models.BulkFailureResponse `json:"bulk_failures"`
+	Pagination   utilities.PaginationResponse `json:"pagination"`
+}
+
+type RetryBulkFailureResponse struct {
+	Outcome     string                     `json:"outcome" example:"succeeded"` // succeeded, skipped or failed
+	BulkFailure models.BulkFailureResponse `json:"bulk_failure"`
+	Result      interface{}                `json:"result" swaggertype:"object"` // Response of the bulk operation for the row
+}
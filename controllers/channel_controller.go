@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"fmt"
 	"livo-backend/models"
 	"livo-backend/utilities"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ChannelController struct {
@@ -30,7 +32,10 @@ func NewChannelController(db *gorm.DB) *ChannelController {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param search query string false "Search by Code or Name (partial match)"
+// @Param sort_by query string false "Sort keys, comma separated, each optionally suffixed with :asc or :desc (created_at, code, name)"
+// @Param sort_dir query string false "Direction of sort keys without a suffix: asc or desc" default(asc)
 // @Success 200 {object} utilities.Response{data=ChannelsListResponse}
+// @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Router /api/channels [get]
@@ -47,21 +52,27 @@ func (cc *ChannelController) GetChannels(c *gin.Context) {
 	var total int64
 
 	// Build query with optional search
-	query := cc.DB.Model(&models.Channel{})
+	query := cc.DB.WithContext(c).Model(&models.Channel{})
 
 	if search != "" {
 		// Search by Code or Name with partial match
 		query = query.Where("code ILIKE ? OR name ILIKE ?", "%"+search+"%", "%"+search+"%")
 	}
 
+	sortScope, err := utilities.ApplySort(c, models.ChannelSortKeys, "", "channels.id")
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid sort", err.Error())
+		return
+	}
+
 	// Get total count with search filter
 	if err := query.Count(&total).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count channels", err.Error())
 		return
 	}
 
-	// Get channels with pagination, search filter, and order by ID ascending
-	if err := query.Order("id ASC").Limit(limit).Offset(offset).Find(&channels).Error; err != nil {
+	// Get channels with pagination, search filter, and sorting
+	if err := query.Scopes(sortScope).Limit(limit).Offset(offset).Find(&channels).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve channels", err.Error())
 		return
 	}
@@ -82,9 +93,9 @@ func (cc *ChannelController) GetChannels(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Channels retrieved successfully"
+	message := utilities.Messagef(c, "Channels retrieved successfully")
 	if search != "" {
-		message += " (filtered by code or name: " + search + ")"
+		message += utilities.Messagef(c, " (filtered by code or name: %s)", search)
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
@@ -107,8 +118,8 @@ func (cc *ChannelController) GetChannel(c *gin.Context) {
 	channelID := c.Param("id")
 
 	var channel models.Channel
-	if err := cc.DB.First(&channel, channelID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Channel not found", err.Error())
+	if err := cc.DB.WithContext(c).First(&channel, channelID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeChannelNotFound, "Channel not found", "Failed to retrieve channel")
 		return
 	}
 
@@ -140,15 +151,15 @@ func (cc *ChannelController) UpdateChannel(c *gin.Context) {
 	}
 
 	var channel models.Channel
-	if err := cc.DB.First(&channel, channelID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Channel not found", err.Error())
+	if err := cc.DB.WithContext(c).First(&channel, channelID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeChannelNotFound, "Channel not found", "Failed to retrieve channel")
 		return
 	}
 
 	// Check for duplicate channel code (excluding current channel)
 	var existingChannel models.Channel
-	if err := cc.DB.Where("code = ? AND id <> ?", req.Code, channelID).First(&existingChannel).Error; err == nil {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Channel code already exists", "A channel with this code already exists")
+	if err := cc.DB.WithContext(c).Where("code = ? AND id <> ?", req.Code, channelID).First(&existingChannel).Error; err == nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDuplicateCode, "Channel code already exists", "A channel with this code already exists")
 		return
 	}
 
@@ -156,7 +167,7 @@ func (cc *ChannelController) UpdateChannel(c *gin.Context) {
 	channel.Code = req.Code
 	channel.Name = req.Name
 
-	if err := cc.DB.Save(&channel).Error; err != nil {
+	if err := cc.DB.WithContext(c).Save(&channel).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update channel", err.Error())
 		return
 	}
@@ -166,7 +177,7 @@ func (cc *ChannelController) UpdateChannel(c *gin.Context) {
 
 // RemoveChannel godoc
 // @Summary Remove channel
-// @Description Soft delete a channel.
+// @Description Soft delete a channel. Channels referenced by orders or complains of the last MASTER_DELETE_GUARD_DAYS days cannot be deleted. Deleted channels are listed in the trash and can be restored.
 // @Tags channels
 // @Accept json
 // @Produce json
@@ -176,17 +187,29 @@ func (cc *ChannelController) UpdateChannel(c *gin.Context) {
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
 // @Router /api/channels/{id} [delete]
 func (cc *ChannelController) RemoveChannel(c *gin.Context) {
 	channelID := c.Param("id")
 
 	var channel models.Channel
-	if err := cc.DB.First(&channel, channelID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Channel not found", err.Error())
+	if err := cc.DB.WithContext(c).First(&channel, channelID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeChannelNotFound, "Channel not found", "Failed to retrieve channel")
+		return
+	}
+
+	// Keep channels that recent transactions still point to
+	references, err := channel.RecentReferences(cc.DB.WithContext(c))
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to check channel references", err.Error())
+		return
+	}
+	if len(references) > 0 {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeMasterInUse, "Channel is still in use", models.DescribeMasterReferences(references))
 		return
 	}
 
-	if err := cc.DB.Delete(&channel).Error; err != nil {
+	if err := cc.DB.WithContext(c).Delete(&channel).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to remove channel", err.Error())
 		return
 	}
@@ -194,6 +217,105 @@ func (cc *ChannelController) RemoveChannel(c *gin.Context) {
 	utilities.SuccessResponse(c, http.StatusOK, "Channel removed successfully", nil)
 }
 
+// GetChannelTrash godoc
+// @Summary Get deleted channels
+// @Description Get the soft deleted channels, most recently deleted first.
+// @Tags channels
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param search query string false "Search by Code or Name (partial match)"
+// @Success 200 {object} utilities.Response{data=ChannelsListResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/channels/trash [get]
+func (cc *ChannelController) GetChannelTrash(c *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	search := c.Query("search")
+
+	query := cc.DB.WithContext(c).Unscoped().Model(&models.Channel{}).Where("deleted_at IS NOT NULL")
+	if search != "" {
+		query = query.Where("code ILIKE ? OR name ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count deleted channels", err.Error())
+		return
+	}
+
+	var channels []models.Channel
+	if err := query.Order("deleted_at DESC, id DESC").Limit(limit).Offset(offset).Find(&channels).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve deleted channels", err.Error())
+		return
+	}
+
+	channelResponses := make([]models.ChannelResponse, len(channels))
+	for i := range channels {
+		channelResponses[i] = channels[i].ToChannelResponse()
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Deleted channels retrieved successfully", ChannelsListResponse{
+		Channels: channelResponses,
+		Pagination: utilities.PaginationResponse{
+			Page:  page,
+			Limit: limit,
+			Total: int(total),
+		},
+	})
+}
+
+// RestoreChannel godoc
+// @Summary Restore a deleted channel
+// @Description Restore a soft deleted channel from the trash.
+// @Tags channels
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Success 200 {object} utilities.Response{data=models.ChannelResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/channels/{id}/restore [post]
+func (cc *ChannelController) RestoreChannel(c *gin.Context) {
+	channelID := c.Param("id")
+
+	var channel models.Channel
+	err := utilities.WithTransaction(cc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Find the channel, deleted or not, locked until commit
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&channel, channelID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeChannelNotFound, "Channel not found", "no channel found with the specified ID")
+			}
+			return err
+		}
+
+		if !channel.DeletedAt.Valid {
+			return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeStateInvalid, "Channel is not deleted", "only deleted channels can be restored")
+		}
+
+		if err := tx.Unscoped().Model(&channel).Update("deleted_at", nil).Error; err != nil {
+			return fmt.Errorf("failed to restore channel: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to restore channel")
+		return
+	}
+
+	channel.DeletedAt = gorm.DeletedAt{}
+	utilities.SuccessResponse(c, http.StatusOK, "Channel restored successfully", channel.ToChannelResponse())
+}
+
 // CreateChannel godoc
 // @Summary Create new channel
 // @Description Create a new channel.
@@ -224,13 +346,13 @@ func (cc *ChannelController) CreateChannel(c *gin.Context) {
 
 	// Check for duplicate channel code
 	var existingChannel models.Channel
-	if err := cc.DB.Where("code = ?", req.Code).First(&existingChannel).Error; err == nil {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Channel code already exists", "A channel with this code already exists")
+	if err := cc.DB.WithContext(c).Where("code = ?", req.Code).First(&existingChannel).Error; err == nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDuplicateCode, "Channel code already exists", "A channel with this code already exists")
 		return
 	}
 
 	// Create a new channel and return the response
-	if err := cc.DB.Create(&channel).Error; err != nil {
+	if err := cc.DB.WithContext(c).Create(&channel).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create channel", err.Error())
 		return
 	}
@@ -238,6 +360,46 @@ func (cc *ChannelController) CreateChannel(c *gin.Context) {
 	utilities.SuccessResponse(c, http.StatusCreated, "Channel created successfully", channel.ToChannelResponse())
 }
 
+// UpdateChannelIntake godoc
+// @Summary Update channel intake thresholds
+// @Description Set the daily order quota of a channel (0 for none) and the share of its expected orders below which its intake counts as dropped (0 keeps CHANNEL_INTAKE_DROP_PERCENT). Coordinators are alerted when the channel goes over its quota or drops, see the channel intake report.
+// @Tags channels
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Param request body UpdateChannelIntakeRequest true "Intake thresholds"
+// @Success 200 {object} utilities.Response{data=models.ChannelResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/channels/{id}/intake [put]
+func (cc *ChannelController) UpdateChannelIntake(c *gin.Context) {
+	channelID := c.Param("id")
+
+	var req UpdateChannelIntakeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	var channel models.Channel
+	if err := cc.DB.WithContext(c).First(&channel, channelID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeChannelNotFound, "Channel not found", "Failed to retrieve channel")
+		return
+	}
+
+	channel.IntakeQuota = req.Quota
+	channel.IntakeDropPercent = req.DropPercent
+	if err := cc.DB.WithContext(c).Model(&channel).Select("IntakeQuota", "IntakeDropPercent").Updates(&channel).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update channel intake", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Channel intake updated successfully", channel.ToChannelResponse())
+}
+
 // Request/Response structs
 type ChannelsListResponse struct {
 	Channels   []models.ChannelResponse     `json:"channels"`
@@ -253,3 +415,8 @@ type CreateChannelRequest struct {
 	Code string `json:"code" binding:"required"`
 	Name string `json:"name" binding:"required"`
 }
+
+type UpdateChannelIntakeRequest struct {
+	Quota       int `json:"quota" binding:"min=0" example:"3000"`
+	DropPercent int `json:"drop_percent" binding:"min=0,max=100" example:"50"`
+}
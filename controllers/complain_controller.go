@@ -1,10 +1,16 @@
 package controllers
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"livo-backend/config"
 	"livo-backend/models"
+	"livo-backend/repositories"
 	"livo-backend/utilities"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -14,12 +20,20 @@ import (
 )
 
 type ComplainController struct {
-	DB *gorm.DB
+	DB      *gorm.DB
+	Config  *config.Config
+	Orders  *repositories.OrderRepository
+	Returns *repositories.ReturnRepository
 }
 
 // NewComplainController creates a new complain controller
-func NewComplainController(db *gorm.DB) *ComplainController {
-	return &ComplainController{DB: db}
+func NewComplainController(db *gorm.DB, cfg *config.Config) *ComplainController {
+	return &ComplainController{
+		DB:      db,
+		Config:  cfg,
+		Orders:  repositories.NewOrderRepository(db),
+		Returns: repositories.NewReturnRepository(db),
+	}
 }
 
 // GetComplains godoc
@@ -34,6 +48,13 @@ func NewComplainController(db *gorm.DB) *ComplainController {
 // @Param start_date query string false "Start date (YYYY-MM-DD format)"
 // @Param end_date query string false "End date (YYYY-MM-DD format)"
 // @Param search query string false "Search by complain code, tracking, order_ginee_id (partial match)"
+// @Param escalation_level query string false "Filter by escalation level (cs, coordinator, management)"
+// @Param status query string false "Filter by resolution status (open, investigating, awaiting_buyer, resolved, rejected)"
+// @Param assigned_to query int false "Filter by responsible user ID"
+// @Param include query string false "Relations to load, comma separated (product_details, user_details, user_roles, channel, store, creator, assignee, order, return). Defaults to all"
+// @Param fields query string false "Complain fields to return, comma separated (id is always returned)"
+// @Param sort_by query string false "Sort keys, comma separated, each optionally suffixed with :asc or :desc (created_at, tracking, code, status, due_at, total_fee)" default(created_at:desc)
+// @Param sort_dir query string false "Direction of sort keys without a suffix: asc or desc" default(asc)
 // @Success 200 {object} utilities.Response{data=ComplainsListResponse}
 // @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
@@ -51,18 +72,21 @@ func (cc *ComplainController) GetComplains(c *gin.Context) {
 
 	// Parse search parameter
 	search := c.Query("search")
+	escalationLevel := c.Query("escalation_level")
+	status := c.Query("status")
+	assignedTo := c.Query("assigned_to")
 
 	var complains []models.Complain
 	var total int64
 
 	// Build query with optional search
-	query := cc.DB.Model(&models.Complain{})
+	query := cc.DB.WithContext(c).Model(&models.Complain{})
 
 	// Apply date range filters if provided
 	if startDate != "" {
 		// Parse start date and set time to beginning of day
 		if parsedStartDate, err := time.Parse("2006-01-02", startDate); err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
 			return
 		} else {
 			startOfDay := parsedStartDate.Format("2006-01-02 00:00:00")
@@ -73,7 +97,7 @@ func (cc *ComplainController) GetComplains(c *gin.Context) {
 	if endDate != "" {
 		// Parse end date and set time to end of day
 		if parsedEndDate, err := time.Parse("2006-01-02", endDate); err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
 			return
 		} else {
 			// Add 24 hours to get the start of next day, then use < instead of <=
@@ -87,6 +111,33 @@ func (cc *ComplainController) GetComplains(c *gin.Context) {
 		query = query.Where("code ILIKE ? OR tracking ILIKE ? OR order_ginee_id ILIKE ?", "%"+search+"%", "%"+search+"%", "%"+search+"%")
 	}
 
+	if escalationLevel != "" {
+		query = query.Where("escalation_level = ?", escalationLevel)
+	}
+
+	if status != "" {
+		if !slices.Contains(models.ComplainStatuses, status) {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid status", "status must be one of "+strings.Join(models.ComplainStatuses, ", "))
+			return
+		}
+		query = query.Where("status = ?", status)
+	}
+
+	if assignedTo != "" {
+		assigneeID, err := strconv.ParseUint(assignedTo, 10, 64)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid assigned_to", "assigned_to must be a user ID")
+			return
+		}
+		query = query.Where("assigned_to = ?", assigneeID)
+	}
+
+	sortScope, err := utilities.ApplySort(c, models.ComplainSortKeys, "created_at:desc", "complains.id")
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid sort", err.Error())
+		return
+	}
+
 	// Get total count with search filter
 	if err := query.Count(&total).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count complains", err.Error())
@@ -94,15 +145,9 @@ func (cc *ComplainController) GetComplains(c *gin.Context) {
 	}
 
 	// ADDED: Preload relationships for complete data
-	if err := query.
-		Preload("ProductDetails.Product").
-		Preload("UserDetails.Operator.UserRoles.Role").
-		Preload("UserDetails.Operator.UserRoles.Assigner").
-		Preload("Channel").
-		Preload("Store").
-		Preload("Creator.UserRoles.Role").
-		Preload("Creator.UserRoles.Assigner").
-		Order("id DESC").
+	serializer := utilities.NewSerializer(c)
+	if err := preloadComplainRelations(query, serializer).
+		Scopes(sortScope).
 		Limit(limit).
 		Offset(offset).
 		Find(&complains).Error; err != nil {
@@ -112,26 +157,7 @@ func (cc *ComplainController) GetComplains(c *gin.Context) {
 
 	// ADDED: Load order data for each complain
 	for i := range complains {
-		if complains[i].Tracking != "" {
-			var order models.Order
-			if err := cc.DB.Preload("OrderDetails").
-				Preload("PickOperator.UserRoles.Role").
-				Preload("PickOperator.UserRoles.Assigner").
-				Where("tracking = ?", complains[i].Tracking).First(&order).Error; err == nil {
-				complains[i].Order = &order
-			}
-
-			// Load return data if tracking exists in old_tracking
-			var returnData models.Return
-			if err := cc.DB.Preload("ReturnDetails.Product").
-				Preload("Channel").
-				Preload("Store").
-				Preload("CreateOperator").
-				Preload("UpdateOperator").
-				Where("old_tracking = ?", complains[i].Tracking).First(&returnData).Error; err == nil {
-				complains[i].Return = &returnData
-			}
-		}
+		cc.loadComplainTrackingData(c, &complains[i], serializer)
 	}
 
 	// Convert to response format
@@ -150,7 +176,7 @@ func (cc *ComplainController) GetComplains(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Complains retrieved successfully"
+	message := utilities.Messagef(c, "Complains retrieved successfully")
 	var filters []string
 
 	if startDate != "" || endDate != "" {
@@ -168,11 +194,76 @@ func (cc *ComplainController) GetComplains(c *gin.Context) {
 		filters = append(filters, "search: "+search)
 	}
 
+	if escalationLevel != "" {
+		filters = append(filters, "escalation_level: "+escalationLevel)
+	}
+
+	if status != "" {
+		filters = append(filters, "status: "+status)
+	}
+
+	if assignedTo != "" {
+		filters = append(filters, "assigned_to: "+assignedTo)
+	}
+
 	if len(filters) > 0 {
-		message += fmt.Sprintf(" (filtered by %s)", strings.Join(filters, " | "))
+		message += utilities.Messagef(c, " (filtered by %s)", strings.Join(filters, " | "))
 	}
 
-	utilities.SuccessResponse(c, http.StatusOK, message, response)
+	utilities.SuccessResponse(c, http.StatusOK, message, serializer.Serialize(response, "complains"))
+}
+
+// GetOverdueComplains godoc
+// @Summary Get overdue complains
+// @Description Get unchecked complains whose SLA due date has passed, oldest due date first.
+// @Tags complains
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param include query string false "Relations to load, comma separated (product_details, user_details, user_roles, channel, store, creator, assignee, order, return). Defaults to all"
+// @Param fields query string false "Complain fields to return, comma separated (id is always returned)"
+// @Success 200 {object} utilities.Response{data=ComplainsListResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/complains/overdue [get]
+func (cc *ComplainController) GetOverdueComplains(c *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	var complains []models.Complain
+	var total int64
+
+	query := cc.DB.WithContext(c).Model(&models.Complain{}).Where("checked = ? AND due_at < ?", false, time.Now())
+
+	if err := query.Count(&total).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count overdue complains", err.Error())
+		return
+	}
+
+	serializer := utilities.NewSerializer(c)
+	if err := preloadComplainRelations(query, serializer).
+		Order("due_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&complains).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch overdue complains", err.Error())
+		return
+	}
+
+	response := ComplainsListResponse{
+		Complains: models.ToComplainResponses(complains),
+		Pagination: utilities.PaginationResponse{
+			Page:  page,
+			Limit: limit,
+			Total: int(total),
+		},
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Overdue complains retrieved successfully", serializer.Serialize(response, "complains"))
 }
 
 // GetComplain godoc
@@ -183,6 +274,8 @@ func (cc *ComplainController) GetComplains(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Complain ID"
+// @Param include query string false "Relations to load, comma separated (product_details, user_details, user_roles, channel, store, creator, assignee, order, return). Defaults to all"
+// @Param fields query string false "Complain fields to return, comma separated (id is always returned)"
 // @Success 200 {object} utilities.Response{data=models.ComplainResponse}
 // @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
@@ -192,41 +285,61 @@ func (cc *ComplainController) GetComplain(c *gin.Context) {
 	complainID := c.Param("id")
 
 	var complain models.Complain
-	if err := cc.DB.Preload("ProductDetails.Product").
-		Preload("UserDetails.Operator.UserRoles.Role").
-		Preload("UserDetails.Operator.UserRoles.Assigner").
-		Preload("Channel").
-		Preload("Store").
-		Preload("Creator.UserRoles.Role").
-		Preload("Creator.UserRoles.Assigner").
+	serializer := utilities.NewSerializer(c)
+	if err := preloadComplainRelations(cc.DB.WithContext(c), serializer).
 		First(&complain, complainID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Complain not found", err.Error())
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeComplainNotFound, "Complain not found", "Failed to retrieve complain")
 		return
 	}
 
-	// Load order data if tracking exists
-	if complain.Tracking != "" {
-		var order models.Order
-		if err := cc.DB.Preload("OrderDetails").
-			Preload("PickOperator.UserRoles.Role").
-			Preload("PickOperator.UserRoles.Assigner").
-			Where("tracking = ?", complain.Tracking).First(&order).Error; err == nil {
+	// Load order and return data if tracking exists
+	cc.loadComplainTrackingData(c, &complain, serializer)
+
+	utilities.SuccessResponse(c, http.StatusOK, "Complain retrieved successfully", serializer.Serialize(complain.ToComplainResponse(), ""))
+}
+
+// preloadComplainRelations preloads the complain relations selected with include=.
+// The role/assigner chains are the most expensive ones and are only loaded with user_roles.
+func preloadComplainRelations(query *gorm.DB, serializer *utilities.Serializer) *gorm.DB {
+	query = serializer.Preload(query, "product_details", "ProductDetails.Product")
+	query = serializer.Preload(query, "user_details", "UserDetails.Operator")
+	query = serializer.Preload(query, "channel", "Channel")
+	query = serializer.Preload(query, "store", "Store")
+	query = serializer.Preload(query, "creator", "Creator")
+	query = serializer.Preload(query, "assignee", "Assignee")
+
+	if serializer.Includes("user_details") {
+		query = serializer.Preload(query, "user_roles", "UserDetails.Operator.UserRoles.Role", "UserDetails.Operator.UserRoles.Assigner")
+	}
+	if serializer.Includes("creator") {
+		query = serializer.Preload(query, "user_roles", "Creator.UserRoles.Role", "Creator.UserRoles.Assigner")
+	}
+
+	return query
+}
+
+// loadComplainTrackingData attaches the order and return sharing the complain tracking, as selected with include=
+func (cc *ComplainController) loadComplainTrackingData(c *gin.Context, complain *models.Complain, serializer *utilities.Serializer) {
+	if complain.Tracking == "" {
+		return
+	}
+
+	if serializer.Includes("order") {
+		var relations []string
+		if serializer.Includes("user_roles") {
+			relations = append(relations, repositories.OrderPickerRoles)
+		}
+		if order, err := cc.Orders.FindByTracking(c, complain.Tracking, relations...); err == nil {
 			complain.Order = &order
 		}
+	}
 
-		// Load return data if tracking exists in old_tracking
-		var returnData models.Return
-		if err := cc.DB.Preload("ReturnDetails.Product").
-			Preload("Channel").
-			Preload("Store").
-			Preload("CreateOperator").
-			Preload("UpdateOperator").
-			Where("old_tracking = ?", complain.Tracking).First(&returnData).Error; err == nil {
+	// Load return data if tracking exists in old_tracking
+	if serializer.Includes("return") {
+		if returnData, err := cc.Returns.FindByOldTracking(c, complain.Tracking); err == nil {
 			complain.Return = &returnData
 		}
 	}
-
-	utilities.SuccessResponse(c, http.StatusOK, "Complain retrieved successfully", complain.ToComplainResponse())
 }
 
 // CreateComplain godoc
@@ -246,14 +359,14 @@ func (cc *ComplainController) CreateComplain(c *gin.Context) {
 	// Get user ID from JWT token
 	userID, exists := c.Get("user_id")
 	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User not authenticated")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeAuthRequired, "Unauthorized", "User not authenticated")
 		return
 	}
 
 	// Get username from JWT token
 	username, exists := c.Get("username")
 	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "Username not found in token")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeAuthRequired, "Unauthorized", "Username not found in token")
 		return
 	}
 
@@ -265,122 +378,96 @@ func (cc *ComplainController) CreateComplain(c *gin.Context) {
 
 	// Check for duplicate tracking
 	var existingComplain models.Complain
-	if err := cc.DB.Where("tracking = ?", req.Tracking).First(&existingComplain).Error; err == nil {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Complain tracking already exists", "A complain with this tracking already exists")
+	if err := cc.DB.WithContext(c).Where("tracking = ?", req.Tracking).First(&existingComplain).Error; err == nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDuplicateComplain, "Complain tracking already exists", "A complain with this tracking already exists")
 		return
 	}
 
-	// Start database transaction
-	tx := cc.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
-	// Find order by tracking to get OrderGineeID and populate product details
 	var order models.Order
-	if err := tx.Preload("OrderDetails").Where("tracking = ?", req.Tracking).First(&order).Error; err != nil {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusNotFound, "Order not found", "No order found with the specified tracking number")
-		return
-	}
-
-	// Generate complain code with username
-	complainCode := utilities.GenerateComplainCode(cc.DB, username.(string))
-
-	complain := models.Complain{
-		Code:         complainCode,
-		Tracking:     req.Tracking,
-		OrderGineeID: order.OrderGineeID, // ADDED: Fill OrderGineeID from order
-		ChannelID:    req.ChannelID,
-		StoreID:      req.StoreID,
-		Description:  req.Description,
-		CreatedBy:    userID.(uint),
-	}
-
-	// Create the complain
-	if err := tx.Create(&complain).Error; err != nil {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create complain", err.Error())
-		return
-	}
-
-	// Populate product details from order details
-	for _, orderDetail := range order.OrderDetails {
-		// Find product by SKU
-		var product models.Product
-		if err := tx.Where("sku = ?", orderDetail.Sku).First(&product).Error; err == nil {
-			productDetail := models.ComplainProductDetail{
-				ComplainID: complain.ID,
-				ProductID:  product.ID,
-				Quantity:   orderDetail.Quantity,
-			}
-
-			if err := tx.Create(&productDetail).Error; err != nil {
-				tx.Rollback()
-				utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create product detail", err.Error())
-				return
+	var complain models.Complain
+	err := utilities.WithTransaction(cc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Find order by tracking to get OrderGineeID and populate product details
+		if err := tx.Preload("OrderDetails").Where("tracking = ?", req.Tracking).First(&order).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "No order found with the specified tracking number")
 			}
+			return err
 		}
-	}
-
-	// Populate user details from workflow tables
-	operatorIDs := make(map[uint]bool) // To avoid duplicate users
 
-	// 1. Check QC-Ribbon
-	var qcRibbon models.QcRibbon
-	if err := tx.Where("tracking = ?", req.Tracking).First(&qcRibbon).Error; err == nil && qcRibbon.QcBy != nil {
-		operatorIDs[*qcRibbon.QcBy] = true
-	}
+		// Generate complain code with username
+		complainCode := utilities.GenerateComplainCode(cc.DB.WithContext(c), username.(string))
+
+		complain = models.Complain{
+			Code:         complainCode,
+			Tracking:     req.Tracking,
+			OrderGineeID: order.OrderGineeID, // ADDED: Fill OrderGineeID from order
+			ChannelID:    req.ChannelID,
+			StoreID:      req.StoreID,
+			Description:  req.Description,
+			CreatedBy:    userID.(uint),
+			Status:       models.ComplainStatusOpen,
+		}
 
-	// 2. Check QC-Online
-	var qcOnline models.QcOnline
-	if err := tx.Where("tracking = ?", req.Tracking).First(&qcOnline).Error; err == nil && qcOnline.QcBy != nil {
-		operatorIDs[*qcOnline.QcBy] = true
-	}
+		// Start the SLA clock, stores may have an SLA of their own
+		slaHours, err := models.ComplainSLAHours(tx, req.StoreID, models.CurrentSettings().ComplainSLAHours)
+		if err != nil {
+			return fmt.Errorf("failed to get complain SLA: %w", err)
+		}
+		dueAt := time.Now().Add(time.Duration(slaHours) * time.Hour)
+		complain.DueAt = &dueAt
+		complain.SlaStatus = models.ComplainSLAOnTrack
 
-	// 3. Check Outbound
-	var outbound models.Outbound
-	if err := tx.Where("tracking = ?", req.Tracking).First(&outbound).Error; err == nil && outbound.OutboundBy != nil {
-		operatorIDs[*outbound.OutboundBy] = true
-	}
+		// Create the complain
+		if err := tx.Create(&complain).Error; err != nil {
+			return fmt.Errorf("failed to create complain: %w", err)
+		}
 
-	// 4. Check Order
-	var orderOperator models.Order
-	if err := tx.Where("tracking = ?", req.Tracking).First(&orderOperator).Error; err == nil && orderOperator.PickedBy != nil {
-		operatorIDs[*orderOperator.PickedBy] = true
-	}
+		// Populate product details from order details
+		for _, orderDetail := range order.OrderDetails {
+			// Find product by SKU
+			var product models.Product
+			if err := tx.Where("sku = ?", orderDetail.Sku).First(&product).Error; err == nil {
+				productDetail := models.ComplainProductDetail{
+					ComplainID: complain.ID,
+					ProductID:  product.ID,
+					Quantity:   orderDetail.Quantity,
+				}
+
+				if err := tx.Create(&productDetail).Error; err != nil {
+					return fmt.Errorf("failed to create product detail: %w", err)
+				}
+			}
+		}
 
-	// Create user details for each unique user found
-	for operatorIDValue := range operatorIDs {
-		userDetail := models.ComplainUserDetail{
-			ComplainID: complain.ID,
-			OperatorID: operatorIDValue,
-			FeeCharge:  0, // Default fee, can be updated later
+		// Populate user details from workflow tables, one per operator with the stages they handled
+		userDetails, err := models.ComplainOperators(tx, complain.ID, req.Tracking)
+		if err != nil {
+			return err
 		}
 
-		if err := tx.Create(&userDetail).Error; err != nil {
-			tx.Rollback()
-			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create user detail", err.Error())
-			return
+		// Create user details for each unique user found
+		for _, userDetail := range userDetails {
+			if err := tx.Create(userDetail).Error; err != nil {
+				return fmt.Errorf("failed to create user detail: %w", err)
+			}
 		}
-	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction", err.Error())
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to create complain")
 		return
 	}
 
 	// Load the created complain with all relationships for complete response
-	cc.DB.Preload("ProductDetails.Product").
+	cc.DB.WithContext(c).Preload("ProductDetails.Product").
 		Preload("UserDetails.Operator.UserRoles.Role").
 		Preload("UserDetails.Operator.UserRoles.Assigner").
 		Preload("Channel").
 		Preload("Store").
 		Preload("Creator.UserRoles.Role").
 		Preload("Creator.UserRoles.Assigner").
+		Preload("Assignee").
 		First(&complain, complain.ID)
 
 	// Load order data (already found above)
@@ -391,7 +478,7 @@ func (cc *ComplainController) CreateComplain(c *gin.Context) {
 
 // UpdateSolutionComplain godoc
 // @Summary Update complain solution and user details
-// @Description Update complain solution, total fee, and manage user details (logged-in users only)
+// @Description Update complain solution, total fee, and manage user details (logged-in users only). Fee charges can be pre-filled with the fee split endpoint.
 // @Tags complains
 // @Accept json
 // @Produce json
@@ -414,85 +501,81 @@ func (cc *ComplainController) UpdateSolutionComplain(c *gin.Context) {
 	}
 
 	var complain models.Complain
-	if err := cc.DB.First(&complain, complainID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Complain not found", err.Error())
+	if err := cc.DB.WithContext(c).First(&complain, complainID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeComplainNotFound, "Complain not found", "Failed to retrieve complain")
 		return
 	}
 
 	// Start database transaction
-	tx := cc.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
+	err := utilities.WithTransaction(cc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Update complain solution and total fee
+		complain.Solution = req.Solution
+		complain.TotalFee = req.TotalFee
 
-	// Update complain solution and total fee
-	complain.Solution = req.Solution
-	complain.TotalFee = req.TotalFee
-
-	if err := tx.Save(&complain).Error; err != nil {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update complain", err.Error())
-		return
-	}
-
-	// Handle user details updates
-	if len(req.UserDetails) > 0 {
-		// Clear existing user details
-		if err := tx.Where("complain_id = ?", complain.ID).Delete(&models.ComplainUserDetail{}).Error; err != nil {
-			tx.Rollback()
-			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to clear existing user details", err.Error())
-			return
+		if err := tx.Save(&complain).Error; err != nil {
+			return fmt.Errorf("failed to update complain: %w", err)
 		}
 
-		// Create new user details
-		for _, userDetailReq := range req.UserDetails {
-			// Validate user exists
-			var user models.User
-			if err := tx.First(&user, userDetailReq.OperatorID).Error; err != nil {
-				tx.Rollback()
-				utilities.ErrorResponse(c, http.StatusBadRequest, "User not found", "User with ID "+strconv.Itoa(int(userDetailReq.OperatorID))+" not found")
-				return
+		// Handle user details updates
+		if len(req.UserDetails) > 0 {
+			// Keep the handling stages recorded when the complain was created
+			var existingDetails []models.ComplainUserDetail
+			if err := tx.Where("complain_id = ?", complain.ID).Find(&existingDetails).Error; err != nil {
+				return fmt.Errorf("failed to load existing user details: %w", err)
+			}
+			stages := make(map[uint]string, len(existingDetails))
+			for _, existing := range existingDetails {
+				stages[existing.OperatorID] = existing.Stages
 			}
 
-			userDetail := models.ComplainUserDetail{
-				ComplainID: complain.ID,
-				OperatorID: userDetailReq.OperatorID,
-				FeeCharge:  userDetailReq.FeeCharge,
+			// Clear existing user details
+			if err := tx.Where("complain_id = ?", complain.ID).Delete(&models.ComplainUserDetail{}).Error; err != nil {
+				return fmt.Errorf("failed to clear existing user details: %w", err)
 			}
 
-			if err := tx.Create(&userDetail).Error; err != nil {
-				tx.Rollback()
-				utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create user detail", err.Error())
-				return
+			// Create new user details
+			for _, userDetailReq := range req.UserDetails {
+				// Validate user exists
+				var user models.User
+				if err := tx.First(&user, userDetailReq.OperatorID).Error; err != nil {
+					return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeUserNotFound, "User not found", "User with ID "+strconv.Itoa(int(userDetailReq.OperatorID))+" not found")
+				}
+
+				userDetail := models.ComplainUserDetail{
+					ComplainID: complain.ID,
+					OperatorID: userDetailReq.OperatorID,
+					FeeCharge:  userDetailReq.FeeCharge,
+					Stages:     stages[userDetailReq.OperatorID],
+					AtFault:    userDetailReq.AtFault,
+				}
+
+				if err := tx.Create(&userDetail).Error; err != nil {
+					return fmt.Errorf("failed to create user detail: %w", err)
+				}
 			}
 		}
-	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction", err.Error())
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to update complain")
 		return
 	}
 
 	// Load updated complain with all relationships
-	cc.DB.Preload("ProductDetails.Product").
+	cc.DB.WithContext(c).Preload("ProductDetails.Product").
 		Preload("UserDetails.Operator.UserRoles.Role").
 		Preload("UserDetails.Operator.UserRoles.Assigner").
 		Preload("Channel").
 		Preload("Store").
 		Preload("Creator.UserRoles.Role").
 		Preload("Creator.UserRoles.Assigner").
+		Preload("Assignee").
 		First(&complain, complain.ID)
 
 	// Load order data if tracking exists
 	if complain.Tracking != "" {
-		var order models.Order
-		if err := cc.DB.Preload("OrderDetails").
-			Preload("PickOperator.UserRoles.Role").
-			Preload("PickOperator.UserRoles.Assigner").
-			Where("tracking = ?", complain.Tracking).First(&order).Error; err == nil {
+		if order, err := cc.Orders.FindByTracking(c, complain.Tracking, repositories.OrderPickerRoles); err == nil {
 			complain.Order = &order
 		}
 	}
@@ -500,9 +583,88 @@ func (cc *ComplainController) UpdateSolutionComplain(c *gin.Context) {
 	utilities.SuccessResponse(c, http.StatusOK, "Complain solution updated successfully", complain.ToComplainResponse())
 }
 
+// SplitComplainFee godoc
+// @Summary Suggest complain fee charges
+// @Description Split a total fee over the operators of a complain according to a policy and return the suggested fee charge of each, to be reviewed and saved with the solution endpoint. Policies: equal, stage_weight (by the configured weights of the stages each operator handled) and fault (equally over the operators marked at fault). Nothing is saved.
+// @Tags complains
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Complain ID"
+// @Param request body SplitComplainFeeRequest true "Split Complain Fee Request"
+// @Success 200 {object} utilities.Response{data=ComplainFeeSplitResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/complains/{id}/fee-split [post]
+func (cc *ComplainController) SplitComplainFee(c *gin.Context) {
+	complainID := c.Param("id")
+
+	var req SplitComplainFeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	if req.Policy == "" {
+		req.Policy = cc.Config.ComplainFeeSplitPolicy
+	}
+
+	var complain models.Complain
+	if err := cc.DB.WithContext(c).Preload("UserDetails", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Preload("UserDetails.Operator").First(&complain, complainID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeComplainNotFound, "Complain not found", "no complain found with the specified ID")
+			return
+		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve complain", err.Error())
+		return
+	}
+
+	// The request's fault assignment replaces the stored one when given
+	if req.AtFaultOperatorIDs != nil {
+		atFault := make(map[uint]bool, len(req.AtFaultOperatorIDs))
+		for _, operatorID := range req.AtFaultOperatorIDs {
+			atFault[operatorID] = true
+		}
+		for i := range complain.UserDetails {
+			complain.UserDetails[i].AtFault = atFault[complain.UserDetails[i].OperatorID]
+		}
+	}
+
+	charges, err := models.SplitComplainFee(req.TotalFee, complain.UserDetails, req.Policy)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeComplainStateInvalid, "Cannot split fee", err.Error())
+		return
+	}
+
+	response := ComplainFeeSplitResponse{
+		Policy:      req.Policy,
+		TotalFee:    req.TotalFee,
+		UserDetails: make([]ComplainFeeSplitItem, len(complain.UserDetails)),
+	}
+	for i, userDetail := range complain.UserDetails {
+		operatorName := "-"
+		if userDetail.Operator != nil {
+			operatorName = userDetail.Operator.FullName
+		}
+		response.UserDetails[i] = ComplainFeeSplitItem{
+			OperatorID:   userDetail.OperatorID,
+			OperatorName: operatorName,
+			Stages:       userDetail.StageList(),
+			AtFault:      userDetail.AtFault,
+			FeeCharge:    charges[i],
+		}
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Complain fee split calculated successfully", response)
+}
+
 // UpdateCheckComplain godoc
 // @Summary Update complain check status
-// @Description Update complain checked status (logged-in users only)
+// @Description Update complain checked status (logged-in users only). Checking an unresolved complain resolves it and unchecking a resolved or rejected complain reopens it as investigating.
 // @Tags complains
 // @Accept json
 // @Produce json
@@ -525,41 +687,55 @@ func (cc *ComplainController) UpdateCheckComplain(c *gin.Context) {
 	}
 
 	var complain models.Complain
-	if err := cc.DB.First(&complain, complainID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Complain not found", err.Error())
+	if err := cc.DB.WithContext(c).First(&complain, complainID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeComplainNotFound, "Complain not found", "Failed to retrieve complain")
 		return
 	}
 
 	if req.Checked == nil {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Validation failed", "checked field is required")
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeValidationFailed, "Validation failed", "checked field is required")
 		return
 	}
 
-	// Update complain checked status
-	complain.Checked = *req.Checked
+	// Checking closes the complain as resolved and unchecking a closed complain reopens it, keeping
+	// the resolution status in step with the checked flag
+	status := ""
+	if *req.Checked && !models.IsFinalComplainStatus(complain.Status) {
+		status = models.ComplainStatusResolved
+	} else if !*req.Checked && models.IsFinalComplainStatus(complain.Status) {
+		status = models.ComplainStatusInvestigating
+	}
 
-	if err := cc.DB.Save(&complain).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update complain check status", err.Error())
-		return
+	if status != "" {
+		if err := complain.TransitionStatus(cc.DB.WithContext(c), status, nil); err != nil {
+			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update complain check status", err.Error())
+			return
+		}
+	} else {
+		// Update complain checked status
+		complain.Checked = *req.Checked
+		complain.RefreshSLAStatus(time.Now())
+
+		if err := cc.DB.WithContext(c).Save(&complain).Error; err != nil {
+			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update complain check status", err.Error())
+			return
+		}
 	}
 
 	// Load updated complain with all relationships
-	cc.DB.Preload("ProductDetails.Product").
+	cc.DB.WithContext(c).Preload("ProductDetails.Product").
 		Preload("UserDetails.Operator.UserRoles.Role").
 		Preload("UserDetails.Operator.UserRoles.Assigner").
 		Preload("Channel").
 		Preload("Store").
 		Preload("Creator.UserRoles.Role").
 		Preload("Creator.UserRoles.Assigner").
+		Preload("Assignee").
 		First(&complain, complain.ID)
 
 	// Load order data if tracking exists
 	if complain.Tracking != "" {
-		var order models.Order
-		if err := cc.DB.Preload("OrderDetails").
-			Preload("PickOperator.UserRoles.Role").
-			Preload("PickOperator.UserRoles.Assigner").
-			Where("tracking = ?", complain.Tracking).First(&order).Error; err == nil {
+		if order, err := cc.Orders.FindByTracking(c, complain.Tracking, repositories.OrderPickerRoles); err == nil {
 			complain.Order = &order
 		}
 	}
@@ -567,6 +743,573 @@ func (cc *ComplainController) UpdateCheckComplain(c *gin.Context) {
 	utilities.SuccessResponse(c, http.StatusOK, "Complain check status updated successfully", complain.ToComplainResponse())
 }
 
+// EscalateComplain godoc
+// @Summary Escalate complain
+// @Description Manually move a complain to the next escalation tier (CS -> coordinator -> management) and notify that tier
+// @Tags complains
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Complain ID"
+// @Param request body EscalateComplainRequest true "Escalate Complain Request"
+// @Success 200 {object} utilities.Response{data=models.ComplainResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/complains/{id}/escalate [put]
+func (cc *ComplainController) EscalateComplain(c *gin.Context) {
+	complainID := c.Param("id")
+
+	var req EscalateComplainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeUserNotFound, "User not found", "user ID not found in context")
+		return
+	}
+
+	userID, ok := userIDInterface.(uint)
+	if !ok {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Invalid user ID", "user ID has invalid type")
+		return
+	}
+
+	var complain models.Complain
+	if err := cc.DB.WithContext(c).First(&complain, complainID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeComplainNotFound, "Complain not found", err.Error())
+			return
+		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve complain", err.Error())
+		return
+	}
+
+	if complain.Checked {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeComplainStateInvalid, "Complain already checked", "checked complains cannot be escalated")
+		return
+	}
+
+	if models.NextComplainEscalationLevel(complain.EscalationLevel) == "" {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeComplainStateInvalid, "Complain cannot be escalated", "complain is already at the highest escalation level")
+		return
+	}
+
+	err := utilities.WithTransaction(cc.DB.WithContext(c), func(tx *gorm.DB) error {
+		_, err := complain.Escalate(tx, &userID, req.Reason)
+		return err
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to escalate complain")
+		return
+	}
+
+	// Load updated complain with all relationships
+	cc.DB.WithContext(c).Preload("ProductDetails.Product").
+		Preload("UserDetails.Operator.UserRoles.Role").
+		Preload("UserDetails.Operator.UserRoles.Assigner").
+		Preload("Channel").
+		Preload("Store").
+		Preload("Creator.UserRoles.Role").
+		Preload("Creator.UserRoles.Assigner").
+		Preload("Assignee").
+		First(&complain, complain.ID)
+
+	utilities.SuccessResponse(c, http.StatusOK, utilities.Messagef(c, "Complain escalated to %s successfully", complain.EscalationLevel), complain.ToComplainResponse())
+}
+
+// UpdateComplainStatus godoc
+// @Summary Update complain resolution status
+// @Description Move a complain through its resolution workflow (open -> investigating -> awaiting_buyer -> resolved or rejected) and optionally assign the user responsible for it. Resolved and rejected complains are marked checked and can be reopened by moving them back to investigating. Moving a complain without a responsible user to investigating assigns the current user.
+// @Tags complains
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Complain ID"
+// @Param request body UpdateComplainStatusRequest true "Update Complain Status Request"
+// @Success 200 {object} utilities.Response{data=models.ComplainResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/complains/{id}/status [put]
+func (cc *ComplainController) UpdateComplainStatus(c *gin.Context) {
+	complainID := c.Param("id")
+
+	var req UpdateComplainStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeUserNotFound, "User not found", "user ID not found in context")
+		return
+	}
+
+	userID, ok := userIDInterface.(uint)
+	if !ok {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Invalid user ID", "user ID has invalid type")
+		return
+	}
+
+	var complain models.Complain
+	if err := cc.DB.WithContext(c).First(&complain, complainID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeComplainNotFound, "Complain not found", err.Error())
+			return
+		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve complain", err.Error())
+		return
+	}
+
+	if !models.CanTransitionComplainStatus(complain.Status, req.Status) {
+		current := complain.Status
+		if current == "" {
+			current = models.ComplainStatusOpen
+		}
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeStatusTransition, "Invalid status transition", fmt.Sprintf("complain cannot move from %s to %s", current, req.Status))
+		return
+	}
+
+	assignedTo := req.AssignedTo
+	if assignedTo != nil {
+		var assignee models.User
+		if err := cc.DB.WithContext(c).Where("id = ? AND is_active = ?", *assignedTo, true).First(&assignee).Error; err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeUserNotFound, "User not found", fmt.Sprintf("no active user found with ID %d", *assignedTo))
+			return
+		}
+	} else if complain.AssignedTo == nil && req.Status == models.ComplainStatusInvestigating {
+		assignedTo = &userID
+	}
+
+	err := utilities.WithTransaction(cc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Drafts start their SLA clock once opened
+		if complain.Status == models.ComplainStatusDraft && req.Status == models.ComplainStatusOpen {
+			slaHours, err := models.ComplainSLAHours(tx, complain.StoreID, models.CurrentSettings().ComplainSLAHours)
+			if err != nil {
+				return fmt.Errorf("failed to get complain SLA: %w", err)
+			}
+			dueAt := time.Now().Add(time.Duration(slaHours) * time.Hour)
+			complain.DueAt = &dueAt
+			if err := tx.Model(&complain).Update("due_at", dueAt).Error; err != nil {
+				return fmt.Errorf("failed to start complain SLA: %w", err)
+			}
+		}
+		return complain.TransitionStatus(tx, req.Status, assignedTo)
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to update complain status")
+		return
+	}
+
+	// Load updated complain with all relationships
+	cc.DB.WithContext(c).Preload("ProductDetails.Product").
+		Preload("UserDetails.Operator.UserRoles.Role").
+		Preload("UserDetails.Operator.UserRoles.Assigner").
+		Preload("Channel").
+		Preload("Store").
+		Preload("Creator.UserRoles.Role").
+		Preload("Creator.UserRoles.Assigner").
+		Preload("Assignee").
+		First(&complain, complain.ID)
+
+	utilities.SuccessResponse(c, http.StatusOK, utilities.Messagef(c, "Complain status updated to %s successfully", complain.Status), complain.ToComplainResponse())
+}
+
+// GetComplainEvidenceBundle godoc
+// @Summary Export complain evidence bundle
+// @Description Assemble everything recorded about the complain tracking (order, QC records, outbound scan, courier milestones, return and complain history) into a single PDF or a ZIP containing the PDF and raw JSON, for marketplace dispute appeals.
+// @Tags complains
+// @Produce application/zip
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path int true "Complain ID"
+// @Param format query string false "Bundle format (zip or pdf)" default(zip)
+// @Success 200 {file} file
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/complains/{id}/evidence [get]
+func (cc *ComplainController) GetComplainEvidenceBundle(c *gin.Context) {
+	complainID := c.Param("id")
+	format := c.DefaultQuery("format", "zip")
+
+	if format != "zip" && format != "pdf" {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid format", "format must be zip or pdf")
+		return
+	}
+
+	var complain models.Complain
+	if err := cc.DB.WithContext(c).Preload("ProductDetails.Product").
+		Preload("UserDetails.Operator").
+		Preload("Channel").
+		Preload("Store").
+		Preload("Creator").
+		Preload("Escalations.Escalator").
+		First(&complain, complainID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeComplainNotFound, "Complain not found", err.Error())
+			return
+		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve complain", err.Error())
+		return
+	}
+
+	evidence := ComplainEvidence{Complain: complain.ToComplainResponse()}
+
+	var order models.Order
+	if err := cc.DB.WithContext(c).Scopes(repositories.OrderWithDetails(repositories.OrderAssigner)).
+		Where("tracking = ?", complain.Tracking).First(&order).Error; err == nil {
+		orderResponse := order.ToOrderResponse()
+		evidence.Order = &orderResponse
+
+		var histories []models.DeliveryHistory
+		cc.DB.WithContext(c).Where("order_id = ?", order.ID).Order("event_at ASC").Find(&histories)
+		for _, history := range histories {
+			evidence.DeliveryHistory = append(evidence.DeliveryHistory, history.ToDeliveryHistoryResponse())
+		}
+	}
+
+	var qcOnline models.QcOnline
+	if err := cc.DB.WithContext(c).Preload("QcOnlineDetails.Box").Preload("QcOperator").
+		Where("tracking = ?", complain.Tracking).First(&qcOnline).Error; err == nil {
+		qcOnlineResponse := qcOnline.ToQcOnlineResponse()
+		evidence.QcOnline = &qcOnlineResponse
+	}
+
+	var qcRibbon models.QcRibbon
+	if err := cc.DB.WithContext(c).Preload("QcRibbonDetails.Box").Preload("QcOperator").
+		Where("tracking = ?", complain.Tracking).First(&qcRibbon).Error; err == nil {
+		qcRibbonResponse := qcRibbon.ToQcRibbonResponse()
+		evidence.QcRibbon = &qcRibbonResponse
+	}
+
+	var outbound models.Outbound
+	if err := cc.DB.WithContext(c).Preload("OutboundOperator").
+		Where("tracking = ?", complain.Tracking).First(&outbound).Error; err == nil {
+		outboundResponse := outbound.ToOutboundResponse()
+		evidence.Outbound = &outboundResponse
+	}
+
+	var returnData models.Return
+	if err := cc.DB.WithContext(c).Preload("ReturnDetails.Product").Preload("CreateOperator").
+		Where("old_tracking = ?", complain.Tracking).First(&returnData).Error; err == nil {
+		returnResponse := returnData.ToReturnResponse()
+		evidence.Return = &returnResponse
+	}
+
+	pdf := buildEvidencePDF(&complain, &evidence)
+	filename := fmt.Sprintf("evidence-%s-%s", complain.Code, complain.Tracking)
+
+	if format == "pdf" {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.pdf", filename))
+		c.Data(http.StatusOK, "application/pdf", pdf)
+		return
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	files := map[string]interface{}{
+		"data/complain.json":         evidence.Complain,
+		"data/order.json":            evidence.Order,
+		"data/qc_online.json":        evidence.QcOnline,
+		"data/qc_ribbon.json":        evidence.QcRibbon,
+		"data/outbound.json":         evidence.Outbound,
+		"data/delivery_history.json": evidence.DeliveryHistory,
+		"data/return.json":           evidence.Return,
+	}
+
+	writeErr := writeZipFile(archive, filename+".pdf", pdf)
+	for name, data := range files {
+		if writeErr != nil {
+			break
+		}
+		content, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			writeErr = err
+			break
+		}
+		writeErr = writeZipFile(archive, name, content)
+	}
+	if writeErr == nil {
+		writeErr = archive.Close()
+	}
+	if writeErr != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build evidence bundle", writeErr.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.zip", filename))
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+func writeZipFile(archive *zip.Writer, name string, content []byte) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(content)
+	return err
+}
+
+// buildEvidencePDF renders the evidence as a printable summary, oldest event first
+func buildEvidencePDF(complain *models.Complain, evidence *ComplainEvidence) []byte {
+	doc := utilities.NewPDFDocument(fmt.Sprintf("Evidence bundle - %s", complain.Code))
+
+	doc.AddHeading("Complain")
+	doc.AddField("Code", complain.Code)
+	doc.AddField("Tracking", complain.Tracking)
+	doc.AddField("Order Ginee ID", complain.OrderGineeID)
+	if complain.Channel != nil {
+		doc.AddField("Channel", complain.Channel.Name)
+	}
+	if complain.Store != nil {
+		doc.AddField("Store", complain.Store.Name)
+	}
+	doc.AddField("Created at", complain.CreatedAt.Format("2006-01-02 15:04:05"))
+	doc.AddField("Description", complain.Description)
+	doc.AddField("Solution", complain.Solution)
+	for _, pd := range complain.ProductDetails {
+		if pd.Product != nil {
+			doc.AddLine(fmt.Sprintf("- %s %s x%d", pd.Product.Sku, pd.Product.Name, pd.Quantity))
+		}
+	}
+
+	if order := evidence.Order; order != nil {
+		doc.AddHeading("Order")
+		doc.AddField("Buyer", order.Buyer)
+		doc.AddField("Courier", order.Courier)
+		doc.AddField("Processing status", order.ProcessingStatus)
+		doc.AddField("Assigned by / at", order.AssignedBy+" / "+order.AssignedAt)
+		doc.AddField("Picked by / at", order.PickedBy+" / "+order.PickedAt)
+		for _, detail := range order.OrderDetails {
+			doc.AddLine(fmt.Sprintf("- %s %s %s x%d (scanned %d)", detail.Sku, detail.ProductName, detail.Variant, detail.Quantity, detail.ScannedQuantity))
+		}
+	}
+
+	if qc := evidence.QcRibbon; qc != nil {
+		doc.AddHeading("QC Ribbon")
+		doc.AddField("Checked at", qc.CreatedAt.Format("2006-01-02 15:04:05"))
+		if qc.QcOperator != nil {
+			doc.AddField("Checked by", qc.QcOperator.FullName)
+		}
+		for _, detail := range qc.QcRibbonDetails {
+			doc.AddLine(fmt.Sprintf("- Box %s x%d", detail.Box.Name, detail.Quantity))
+		}
+	}
+
+	if qc := evidence.QcOnline; qc != nil {
+		doc.AddHeading("QC Online")
+		doc.AddField("Checked at", qc.CreatedAt.Format("2006-01-02 15:04:05"))
+		if qc.QcOperator != nil {
+			doc.AddField("Checked by", qc.QcOperator.FullName)
+		}
+		for _, detail := range qc.QcOnlineDetails {
+			doc.AddLine(fmt.Sprintf("- Box %s x%d", detail.Box.Name, detail.Quantity))
+		}
+	}
+
+	if outbound := evidence.Outbound; outbound != nil {
+		doc.AddHeading("Outbound")
+		doc.AddField("Scanned at", outbound.CreatedAt.Format("2006-01-02 15:04:05"))
+		doc.AddField("Expedition", outbound.Expedition)
+		if outbound.OutboundOperator != nil {
+			doc.AddField("Scanned by", outbound.OutboundOperator.FullName)
+		}
+	}
+
+	if len(evidence.DeliveryHistory) > 0 {
+		doc.AddHeading("Courier milestones")
+		for _, history := range evidence.DeliveryHistory {
+			doc.AddLine(fmt.Sprintf("%s  %s  %s %s", history.EventAt, history.Status, history.Description, history.Location))
+		}
+	}
+
+	if ret := evidence.Return; ret != nil {
+		doc.AddHeading("Return")
+		doc.AddField("New tracking", ret.NewTracking)
+		doc.AddField("Return type", ret.ReturnType)
+		doc.AddField("Return reason", ret.ReturnReason)
+		doc.AddField("Received at", ret.CreatedAt.Format("2006-01-02 15:04:05"))
+	}
+
+	doc.AddHeading("Complain history")
+	doc.AddLine(fmt.Sprintf("%s  created", complain.CreatedAt.Format("2006-01-02 15:04:05")))
+	for _, escalation := range complain.Escalations {
+		by := "automatic"
+		if escalation.Escalator != nil {
+			by = escalation.Escalator.FullName
+		}
+		doc.AddLine(fmt.Sprintf("%s  escalated %s -> %s by %s: %s", escalation.CreatedAt.Format("2006-01-02 15:04:05"), escalation.FromLevel, escalation.ToLevel, by, escalation.Reason))
+	}
+	if complain.Checked {
+		doc.AddLine(fmt.Sprintf("%s  checked", complain.UpdatedAt.Format("2006-01-02 15:04:05")))
+	}
+
+	return doc.Bytes()
+}
+
+// GetComplainDocument godoc
+// @Summary Print complain report
+// @Description Generate a printable complain report (PDF) with the order, the operators involved, the fees charged and the resolution, headed with the store's label header and closed with its document footer, to attach to marketplace dispute forms.
+// @Tags complains
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path int true "Complain ID"
+// @Success 200 {file} file
+// @Failure 401 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/complains/{id}/document [get]
+func (cc *ComplainController) GetComplainDocument(c *gin.Context) {
+	complainID := c.Param("id")
+
+	var complain models.Complain
+	if err := cc.DB.WithContext(c).Preload("ProductDetails.Product").
+		Preload("UserDetails.Operator").
+		Preload("Channel").
+		Preload("Store").
+		Preload("Assignee").
+		First(&complain, complainID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeComplainNotFound, "Complain not found", err.Error())
+			return
+		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve complain", err.Error())
+		return
+	}
+
+	var orders []models.Order
+	if err := cc.DB.WithContext(c).Scopes(repositories.OrderWithDetails()).
+		Where("tracking = ?", complain.Tracking).Limit(1).Find(&orders).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order", err.Error())
+		return
+	}
+	var order *models.Order
+	if len(orders) > 0 {
+		order = &orders[0]
+	}
+
+	pdf := buildComplainDocumentPDF(&complain, order, time.Now())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=complain-%s.pdf", complain.Code))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// buildComplainDocumentPDF renders the complain as a letter headed with the store's branding
+func buildComplainDocumentPDF(complain *models.Complain, order *models.Order, printedAt time.Time) []byte {
+	var settings models.StoreSettings
+	header := "Complain report"
+	if complain.Store != nil {
+		settings = complain.Store.GetSettings()
+		header = complain.Store.Name
+		if settings.LabelHeader != "" {
+			header = settings.LabelHeader
+		}
+	}
+	channel := "-"
+	if complain.Channel != nil {
+		channel = complain.Channel.Name
+	}
+
+	doc := utilities.NewPDFDocument(header)
+	doc.AddField("Complain report", complain.Code)
+	doc.AddField("Date", printedAt.In(utilities.AppLocation()).Format("2006-01-02"))
+	doc.AddLine("")
+	doc.AddLine("To whom it may concern,")
+	doc.AddLine(fmt.Sprintf("This report describes the complain about order %s (tracking %s) sold on %s, how it was handled in our warehouse, the operators involved, the fees charged and the resolution.", complain.OrderGineeID, complain.Tracking, channel))
+
+	doc.AddHeading("Complain")
+	doc.AddField("Code", complain.Code)
+	doc.AddField("Received at", complain.CreatedAt.Format("2006-01-02 15:04:05"))
+	doc.AddField("Description", complain.Description)
+	for _, pd := range complain.ProductDetails {
+		if pd.Product != nil {
+			doc.AddLine(fmt.Sprintf("- %s %s x%d", pd.Product.Sku, pd.Product.Name, pd.Quantity))
+		}
+	}
+
+	doc.AddHeading("Order")
+	doc.AddField("Order Ginee ID", complain.OrderGineeID)
+	doc.AddField("Tracking", complain.Tracking)
+	doc.AddField("Channel", channel)
+	if complain.Store != nil {
+		doc.AddField("Store", complain.Store.Name)
+	}
+	if order != nil {
+		doc.AddField("Buyer", order.Buyer)
+		doc.AddField("Courier", order.Courier)
+		doc.AddField("Ordered at", order.CreatedAt.Format("2006-01-02 15:04:05"))
+		if order.PickedAt != nil {
+			picker := "-"
+			if order.PickOperator != nil {
+				picker = order.PickOperator.FullName
+			}
+			doc.AddField("Picked by / at", picker+" / "+order.PickedAt.Format("2006-01-02 15:04:05"))
+		}
+		for _, detail := range order.OrderDetails {
+			doc.AddLine(fmt.Sprintf("- %s %s %s x%d", detail.Sku, detail.ProductName, detail.Variant, detail.Quantity))
+		}
+	} else {
+		doc.AddLine("The order is no longer on record.")
+	}
+
+	doc.AddHeading("Operators involved")
+	var charged uint
+	for _, detail := range complain.UserDetails {
+		name := "-"
+		if detail.Operator != nil {
+			name = detail.Operator.FullName
+		}
+		fault := "not at fault"
+		if detail.AtFault {
+			fault = "at fault"
+		}
+		stages := detail.Stages
+		if stages == "" {
+			stages = "-"
+		}
+		doc.AddLine(fmt.Sprintf("- %s (%s), %s, fee %d", name, stages, fault, detail.FeeCharge))
+		charged += detail.FeeCharge
+	}
+	if len(complain.UserDetails) == 0 {
+		doc.AddLine("No operators recorded.")
+	}
+
+	doc.AddHeading("Fees")
+	doc.AddField("Total fee", complain.TotalFee)
+	doc.AddField("Charged to operators", charged)
+
+	doc.AddHeading("Resolution")
+	doc.AddField("Status", complain.Status)
+	solution := complain.Solution
+	if solution == "" {
+		solution = "-"
+	}
+	doc.AddField("Solution", solution)
+	if complain.ResolvedAt != nil {
+		doc.AddField("Resolved at", complain.ResolvedAt.Format("2006-01-02 15:04:05"))
+	}
+	if complain.RejectedAt != nil {
+		doc.AddField("Rejected at", complain.RejectedAt.Format("2006-01-02 15:04:05"))
+	}
+	if complain.Assignee != nil {
+		doc.AddField("Handled by", complain.Assignee.FullName)
+	}
+
+	if settings.DocumentFooter != "" {
+		doc.AddLine("")
+		doc.AddLine(settings.DocumentFooter)
+	}
+
+	return doc.Bytes()
+}
+
 // Request/Response structs
 type ComplainsListResponse struct {
 	Complains  []models.ComplainResponse    `json:"complains"`
@@ -588,9 +1331,50 @@ type UpdateSolutionComplainRequest struct {
 
 type ComplainUserDetailRequest struct {
 	OperatorID uint `json:"operator_id" binding:"required" example:"1"`
-	FeeCharge  uint `json:"fee_charge" binding:"required" example:"10000"`
+	FeeCharge  uint `json:"fee_charge" example:"10000"` // May be 0 for operators not charged, e.g. not at fault
+	AtFault    bool `json:"at_fault" example:"true"`
+}
+
+type SplitComplainFeeRequest struct {
+	TotalFee           uint   `json:"total_fee" binding:"required" example:"50000"`
+	Policy             string `json:"policy" binding:"omitempty,oneof=equal stage_weight fault" example:"stage_weight"` // Defaults to the configured policy
+	AtFaultOperatorIDs []uint `json:"at_fault_operator_ids" example:"3"`                                                // Replaces the stored fault assignment when sent
+}
+
+type ComplainFeeSplitResponse struct {
+	Policy      string                 `json:"policy"`
+	TotalFee    uint                   `json:"total_fee"`
+	UserDetails []ComplainFeeSplitItem `json:"user_details"`
+}
+
+type ComplainFeeSplitItem struct {
+	OperatorID   uint     `json:"operator_id"`
+	OperatorName string   `json:"operator_name"`
+	Stages       []string `json:"stages"`
+	AtFault      bool     `json:"at_fault"`
+	FeeCharge    uint     `json:"fee_charge"`
 }
 
 type UpdateCheckComplainRequest struct {
 	Checked *bool `json:"checked" binding:"required"`
 }
+
+type EscalateComplainRequest struct {
+	Reason string `json:"reason" binding:"required" example:"Customer threatened chargeback"`
+}
+
+type UpdateComplainStatusRequest struct {
+	Status     string `json:"status" binding:"required,oneof=open investigating awaiting_buyer resolved rejected" example:"investigating"`
+	AssignedTo *uint  `json:"assigned_to" example:"5"` // Responsible user, keeps the current one when omitted
+}
+
+// ComplainEvidence collects every record linked to a complain tracking
+type ComplainEvidence struct {
+	Complain        models.ComplainResponse          `json:"complain"`
+	Order           *models.OrderResponse            `json:"order"`
+	QcOnline        *models.QcOnlineResponse         `json:"qc_online"`
+	QcRibbon        *models.QcRibbonResponse         `json:"qc_ribbon"`
+	Outbound        *models.OutboundResponse         `json:"outbound"`
+	DeliveryHistory []models.DeliveryHistoryResponse `json:"delivery_history"`
+	Return          *models.ReturnResponse           `json:"return"`
+}
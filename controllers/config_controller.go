@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"livo-backend/bootstrap"
+	"livo-backend/config"
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ConfigController struct {
+	DB     *gorm.DB
+	Config *bootstrap.ConfigService
+}
+
+// NewConfigController creates a new config controller
+func NewConfigController(db *gorm.DB, configService *bootstrap.ConfigService) *ConfigController {
+	return &ConfigController{DB: db, Config: configService}
+}
+
+// GetConfig godoc
+// @Summary Get reloadable settings
+// @Description Get the settings that can be changed without a redeploy, with their environment value, their override and the value in effect
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utilities.Response{data=[]ConfigSettingResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/admin/config [get]
+func (cc *ConfigController) GetConfig(c *gin.Context) {
+	overrides, err := models.LoadConfigOverrides(cc.DB.WithContext(c))
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve config", err.Error())
+		return
+	}
+
+	current := cc.Config.Current()
+	env := cc.Config.Env()
+	settings := make([]ConfigSettingResponse, len(config.ReloadableSettings))
+	for i, setting := range config.ReloadableSettings {
+		settings[i] = ConfigSettingResponse{
+			Key:         setting.Key,
+			Kind:        setting.Kind,
+			Description: setting.Description,
+			Value:       current.Value(setting.Key),
+			EnvValue:    env.Value(setting.Key),
+			Override:    "-",
+			UpdatedBy:   "-",
+			UpdatedAt:   "-",
+		}
+		if override, found := overrides[setting.Key]; found {
+			settings[i].Overridden = true
+			settings[i].Override = override.Value
+			settings[i].UpdatedAt = override.UpdatedAt.Format("2006-01-02 15:04:05")
+			if override.Updater != nil {
+				settings[i].UpdatedBy = override.Updater.FullName
+			}
+		}
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Config retrieved successfully", settings)
+}
+
+// UpdateConfig godoc
+// @Summary Override a reloadable setting
+// @Description Override the environment value of a reloadable setting. The new value applies at once in this process and within CONFIG_RELOAD_SECONDS in the others, and the change is kept in the config audit.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Setting key, e.g. INSTANT_SLA_MINUTES"
+// @Param request body UpdateConfigRequest true "Override value"
+// @Success 200 {object} utilities.Response{data=models.ConfigChangeResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/admin/config/{key} [put]
+func (cc *ConfigController) UpdateConfig(c *gin.Context) {
+	var req UpdateConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	key := strings.ToUpper(strings.TrimSpace(c.Param("key")))
+	change, err := cc.Config.Set(key, strings.TrimSpace(req.Value), c.GetUint("user_id"))
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to update config")
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Config updated successfully", change.ToConfigChangeResponse())
+}
+
+// ResetConfig godoc
+// @Summary Remove a setting override
+// @Description Remove the override of a reloadable setting, putting it back to its environment value. The change is kept in the config audit.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Setting key, e.g. INSTANT_SLA_MINUTES"
+// @Success 200 {object} utilities.Response{data=models.ConfigChangeResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/admin/config/{key} [delete]
+func (cc *ConfigController) ResetConfig(c *gin.Context) {
+	key := strings.ToUpper(strings.TrimSpace(c.Param("key")))
+	change, err := cc.Config.Reset(key, c.GetUint("user_id"))
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to reset config")
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Config reset successfully", change.ToConfigChangeResponse())
+}
+
+// GetConfigChanges godoc
+// @Summary Get config changes
+// @Description Get the audit of setting overrides, newest first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param key query string false "Filter by setting key"
+// @Success 200 {object} utilities.Response{data=ConfigChangesListResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/admin/config/changes [get]
+func (cc *ConfigController) GetConfigChanges(c *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	query := cc.DB.WithContext(c).Model(&models.ConfigChange{})
+	if key := c.Query("key"); key != "" {
+		query = query.Where("key = ?", strings.ToUpper(strings.TrimSpace(key)))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count config changes", err.Error())
+		return
+	}
+
+	var changes []models.ConfigChange
+	if err := query.Preload("Changer").Order("id DESC").Limit(limit).Offset(offset).Find(&changes).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve config changes", err.Error())
+		return
+	}
+
+	changeResponses := make([]models.ConfigChangeResponse, len(changes))
+	for i := range changes {
+		changeResponses[i] = changes[i].ToConfigChangeResponse()
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Config changes retrieved successfully", ConfigChangesListResponse{
+		Changes: changeResponses,
+		Pagination: utilities.PaginationResponse{
+			Page:  page,
+			Limit: limit,
+			Total: int(total),
+		},
+	})
+}
+
+// Request/Response structs
+type UpdateConfigRequest struct {
+	Value string `json:"value" binding:"required" example:"90"`
+}
+
+type ConfigSettingResponse struct {
+	Key         string `json:"key" example:"INSTANT_SLA_MINUTES"`
+	Kind        string `json:"kind" example:"int"`
+	Description string `json:"description"`
+	Value       string `json:"value" example:"90"`     // Value in effect
+	EnvValue    string `json:"env_value" example:"60"` // Value from the environment or the default
+	Overridden  bool   `json:"overridden"`
+	Override    string `json:"override" example:"90"`
+	UpdatedBy   string `json:"updated_by"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+type ConfigChangesListResponse struct {
+	Changes    []models.ConfigChangeResponse `json:"changes"`
+	Pagination utilities.PaginationResponse  `json:"pagination"`
+}
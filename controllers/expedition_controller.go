@@ -1,14 +1,17 @@
 package controllers
 
 import (
+	"fmt"
 	"livo-backend/models"
 	"livo-backend/utilities"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ExpeditionController struct {
@@ -30,7 +33,10 @@ func NewExpeditionController(db *gorm.DB) *ExpeditionController {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param search query string false "Search by Code or Name (partial match)"
+// @Param sort_by query string false "Sort keys, comma separated, each optionally suffixed with :asc or :desc (created_at, code, name)"
+// @Param sort_dir query string false "Direction of sort keys without a suffix: asc or desc" default(asc)
 // @Success 200 {object} utilities.Response{data=ExpeditionsListResponse}
+// @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Router /api/expeditions [get]
@@ -47,21 +53,27 @@ func (ec *ExpeditionController) GetExpeditions(c *gin.Context) {
 	var total int64
 
 	// Build query with optional search
-	query := ec.DB.Model(&models.Expedition{})
+	query := ec.DB.WithContext(c).Model(&models.Expedition{})
 
 	if search != "" {
 		// Search by Code or Name with partial match
 		query = query.Where("code ILIKE ? OR name ILIKE ?", "%"+search+"%", "%"+search+"%")
 	}
 
+	sortScope, err := utilities.ApplySort(c, models.ExpeditionSortKeys, "", "expeditions.id")
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid sort", err.Error())
+		return
+	}
+
 	// Get total count with search filter
 	if err := query.Count(&total).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count expeditions", err.Error())
 		return
 	}
 
-	// Get expeditions with pagination, search filter, and order by ID ascending
-	if err := query.Order("id ASC").Limit(limit).Offset(offset).Find(&expeditions).Error; err != nil {
+	// Get expeditions with pagination, search filter, and sorting
+	if err := query.Scopes(sortScope).Limit(limit).Offset(offset).Find(&expeditions).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve expeditions", err.Error())
 		return
 	}
@@ -82,9 +94,9 @@ func (ec *ExpeditionController) GetExpeditions(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Expeditions retrieved successfully"
+	message := utilities.Messagef(c, "Expeditions retrieved successfully")
 	if search != "" {
-		message += " (filtered by code or name: " + search + ")"
+		message += utilities.Messagef(c, " (filtered by code or name: %s)", search)
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
@@ -107,8 +119,8 @@ func (ec *ExpeditionController) GetExpedition(c *gin.Context) {
 	expeditionID := c.Param("id")
 
 	var expedition models.Expedition
-	if err := ec.DB.First(&expedition, expeditionID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Expedition not found", err.Error())
+	if err := ec.DB.WithContext(c).First(&expedition, expeditionID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeExpeditionNotFound, "Expedition not found", "Failed to retrieve expedition")
 		return
 	}
 
@@ -140,15 +152,15 @@ func (ec *ExpeditionController) UpdateExpedition(c *gin.Context) {
 	}
 
 	var expedition models.Expedition
-	if err := ec.DB.First(&expedition, expeditionID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Expedition not found", err.Error())
+	if err := ec.DB.WithContext(c).First(&expedition, expeditionID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeExpeditionNotFound, "Expedition not found", "Failed to retrieve expedition")
 		return
 	}
 
 	// Check for duplicate code (excluding current expedition)
 	var existingExpedition models.Expedition
-	if err := ec.DB.Where("code = ? AND id != ?", req.Code, expedition.ID).First(&existingExpedition).Error; err == nil {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Expedition code already exists", "A expedition with this code already exists")
+	if err := ec.DB.WithContext(c).Where("code = ? AND id != ?", req.Code, expedition.ID).First(&existingExpedition).Error; err == nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDuplicateCode, "Expedition code already exists", "A expedition with this code already exists")
 		return
 	}
 
@@ -156,9 +168,31 @@ func (ec *ExpeditionController) UpdateExpedition(c *gin.Context) {
 	expedition.Code = req.Code
 	expedition.Name = req.Name
 	expedition.Color = req.Color
+	expedition.Instant = req.Instant
+	expedition.PickupCutoff = strings.TrimSpace(req.PickupCutoff)
+	expedition.MatchPattern = req.MatchPattern
+	expedition.MatchPriority = req.MatchPriority
 	expedition.Slug = req.Slug
+	expedition.TrackingProvider = req.TrackingProvider
+	expedition.TrackingEndpoint = req.TrackingEndpoint
+	if req.TrackingAPIKey != "" {
+		expedition.TrackingAPIKey = req.TrackingAPIKey
+	}
+	expedition.TrackingMinLength = req.TrackingMinLength
+	expedition.TrackingMaxLength = req.TrackingMaxLength
+	expedition.TrackingPattern = req.TrackingPattern
+	expedition.TrackingChecksum = req.TrackingChecksum
 
-	if err := ec.DB.Save(&expedition).Error; err != nil {
+	if err := validateTrackingRules(&expedition); err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidTrackingConfig, "Invalid tracking validation rules", err.Error())
+		return
+	}
+	if _, err := models.ParsePickupCutoff(expedition.PickupCutoff); err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid pickup cutoff", err.Error())
+		return
+	}
+
+	if err := ec.DB.WithContext(c).Save(&expedition).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update expedition", err.Error())
 		return
 	}
@@ -168,7 +202,7 @@ func (ec *ExpeditionController) UpdateExpedition(c *gin.Context) {
 
 // RemoveExpedition godoc
 // @Summary Remove expedition
-// @Description Soft delete expedition (logged-in users only)
+// @Description Soft delete a expedition. Expeditions referenced by orders or outbound scans of the last MASTER_DELETE_GUARD_DAYS days cannot be deleted. Deleted expeditions are listed in the trash and can be restored.
 // @Tags expeditions
 // @Accept json
 // @Produce json
@@ -178,17 +212,29 @@ func (ec *ExpeditionController) UpdateExpedition(c *gin.Context) {
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
 // @Router /api/expeditions/{id} [delete]
 func (ec *ExpeditionController) RemoveExpedition(c *gin.Context) {
 	expeditionID := c.Param("id")
 
 	var expedition models.Expedition
-	if err := ec.DB.First(&expedition, expeditionID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Expedition not found", err.Error())
+	if err := ec.DB.WithContext(c).First(&expedition, expeditionID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeExpeditionNotFound, "Expedition not found", "Failed to retrieve expedition")
+		return
+	}
+
+	// Keep expeditions that recent transactions still point to
+	references, err := expedition.RecentReferences(ec.DB.WithContext(c))
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to check expedition references", err.Error())
+		return
+	}
+	if len(references) > 0 {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeMasterInUse, "Expedition is still in use", models.DescribeMasterReferences(references))
 		return
 	}
 
-	if err := ec.DB.Delete(&expedition).Error; err != nil {
+	if err := ec.DB.WithContext(c).Delete(&expedition).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to remove expedition", err.Error())
 		return
 	}
@@ -196,6 +242,105 @@ func (ec *ExpeditionController) RemoveExpedition(c *gin.Context) {
 	utilities.SuccessResponse(c, http.StatusOK, "Expedition removed successfully", nil)
 }
 
+// GetExpeditionTrash godoc
+// @Summary Get deleted expeditions
+// @Description Get the soft deleted expeditions, most recently deleted first.
+// @Tags expeditions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param search query string false "Search by Code or Name (partial match)"
+// @Success 200 {object} utilities.Response{data=ExpeditionsListResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/expeditions/trash [get]
+func (ec *ExpeditionController) GetExpeditionTrash(c *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	search := c.Query("search")
+
+	query := ec.DB.WithContext(c).Unscoped().Model(&models.Expedition{}).Where("deleted_at IS NOT NULL")
+	if search != "" {
+		query = query.Where("code ILIKE ? OR name ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count deleted expeditions", err.Error())
+		return
+	}
+
+	var expeditions []models.Expedition
+	if err := query.Order("deleted_at DESC, id DESC").Limit(limit).Offset(offset).Find(&expeditions).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve deleted expeditions", err.Error())
+		return
+	}
+
+	expeditionResponses := make([]models.ExpeditionResponse, len(expeditions))
+	for i := range expeditions {
+		expeditionResponses[i] = expeditions[i].ToExpeditionResponse()
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Deleted expeditions retrieved successfully", ExpeditionsListResponse{
+		Expeditions: expeditionResponses,
+		Pagination: utilities.PaginationResponse{
+			Page:  page,
+			Limit: limit,
+			Total: int(total),
+		},
+	})
+}
+
+// RestoreExpedition godoc
+// @Summary Restore a deleted expedition
+// @Description Restore a soft deleted expedition from the trash.
+// @Tags expeditions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Expedition ID"
+// @Success 200 {object} utilities.Response{data=models.ExpeditionResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/expeditions/{id}/restore [post]
+func (ec *ExpeditionController) RestoreExpedition(c *gin.Context) {
+	expeditionID := c.Param("id")
+
+	var expedition models.Expedition
+	err := utilities.WithTransaction(ec.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Find the expedition, deleted or not, locked until commit
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&expedition, expeditionID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeExpeditionNotFound, "Expedition not found", "no expedition found with the specified ID")
+			}
+			return err
+		}
+
+		if !expedition.DeletedAt.Valid {
+			return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeStateInvalid, "Expedition is not deleted", "only deleted expeditions can be restored")
+		}
+
+		if err := tx.Unscoped().Model(&expedition).Update("deleted_at", nil).Error; err != nil {
+			return fmt.Errorf("failed to restore expedition: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to restore expedition")
+		return
+	}
+
+	expedition.DeletedAt = gorm.DeletedAt{}
+	utilities.SuccessResponse(c, http.StatusOK, "Expedition restored successfully", expedition.ToExpeditionResponse())
+}
+
 // CreateExpedition godoc
 // @Summary Create new expedition
 // @Description Create a new expedition.
@@ -223,21 +368,45 @@ func (ec *ExpeditionController) CreateExpedition(c *gin.Context) {
 	req.Slug = strings.ToLower(strings.TrimSpace(req.Slug))
 
 	expedition := models.Expedition{
-		Code:  req.Code,
-		Name:  req.Name,
-		Slug:  req.Slug,
-		Color: req.Color,
+		Code:    req.Code,
+		Name:    req.Name,
+		Slug:    req.Slug,
+		Color:   req.Color,
+		Instant: req.Instant,
+
+		PickupCutoff: strings.TrimSpace(req.PickupCutoff),
+
+		MatchPattern:  req.MatchPattern,
+		MatchPriority: req.MatchPriority,
+
+		TrackingProvider: req.TrackingProvider,
+		TrackingEndpoint: req.TrackingEndpoint,
+		TrackingAPIKey:   req.TrackingAPIKey,
+
+		TrackingMinLength: req.TrackingMinLength,
+		TrackingMaxLength: req.TrackingMaxLength,
+		TrackingPattern:   req.TrackingPattern,
+		TrackingChecksum:  req.TrackingChecksum,
+	}
+
+	if err := validateTrackingRules(&expedition); err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidTrackingConfig, "Invalid tracking validation rules", err.Error())
+		return
+	}
+	if _, err := models.ParsePickupCutoff(expedition.PickupCutoff); err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid pickup cutoff", err.Error())
+		return
 	}
 
 	// Check for duplicate expedition code
 	var existingExpedition models.Expedition
-	if err := ec.DB.Where("code = ?", req.Code).First(&existingExpedition).Error; err == nil {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Expedition code already exists", "A expedition with this code already exists")
+	if err := ec.DB.WithContext(c).Where("code = ?", req.Code).First(&existingExpedition).Error; err == nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDuplicateCode, "Expedition code already exists", "A expedition with this code already exists")
 		return
 	}
 
 	// Create a new expedition and return the response
-	if err := ec.DB.Create(&expedition).Error; err != nil {
+	if err := ec.DB.WithContext(c).Create(&expedition).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create expedition", err.Error())
 		return
 	}
@@ -245,6 +414,92 @@ func (ec *ExpeditionController) CreateExpedition(c *gin.Context) {
 	utilities.SuccessResponse(c, http.StatusCreated, "Expedition created successfully", expedition.ToExpeditionResponse())
 }
 
+// MatchExpedition godoc
+// @Summary Preview expedition detection
+// @Description Preview which expedition a tracking resolves to at outbound and QC, with every matching expedition ranked by match priority and matched length, and whether the tracking passes the winner's scan validation
+// @Tags expeditions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MatchExpeditionRequest true "Tracking to resolve"
+// @Success 200 {object} utilities.Response{data=MatchExpeditionResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/expeditions/match [post]
+func (ec *ExpeditionController) MatchExpedition(c *gin.Context) {
+	var req MatchExpeditionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+	req.Tracking = strings.TrimSpace(req.Tracking)
+
+	matcher, err := models.LoadExpeditionMatcher(ec.DB.WithContext(c))
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve expeditions", err.Error())
+		return
+	}
+
+	response := MatchExpeditionResponse{
+		Tracking:   req.Tracking,
+		Candidates: []ExpeditionMatchCandidate{},
+	}
+	for _, match := range matcher.Candidates(req.Tracking) {
+		response.Candidates = append(response.Candidates, ExpeditionMatchCandidate{
+			ExpeditionID: match.Expedition.ID,
+			Code:         match.Expedition.Code,
+			Name:         match.Expedition.Name,
+			Rule:         match.Rule,
+			Matched:      match.Matched,
+			Priority:     match.Priority,
+		})
+	}
+
+	if expedition := matcher.Match(req.Tracking); expedition != nil {
+		expeditionResponse := expedition.ToExpeditionResponse()
+		response.Expedition = &expeditionResponse
+		response.Valid = true
+		if err := expedition.ValidateTracking(req.Tracking); err != nil {
+			response.Valid = false
+			response.ValidationError = err.Error()
+		}
+	}
+
+	message := "Tracking does not match any expedition"
+	if response.Expedition != nil {
+		message = utilities.Messagef(c, "Tracking resolves to %s", response.Expedition.Name)
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, message, response)
+}
+
+// validateTrackingRules checks the scan validation settings before they are saved
+func validateTrackingRules(expedition *models.Expedition) error {
+	expedition.TrackingPattern = strings.TrimSpace(expedition.TrackingPattern)
+	expedition.MatchPattern = strings.TrimSpace(expedition.MatchPattern)
+	expedition.TrackingChecksum = strings.ToLower(strings.TrimSpace(expedition.TrackingChecksum))
+
+	if expedition.TrackingMaxLength > 0 && expedition.TrackingMinLength > expedition.TrackingMaxLength {
+		return fmt.Errorf("tracking_min_length must not exceed tracking_max_length")
+	}
+	if expedition.TrackingPattern != "" {
+		if _, err := regexp.Compile(expedition.TrackingPattern); err != nil {
+			return fmt.Errorf("tracking_pattern is not a valid regular expression: %v", err)
+		}
+	}
+	if expedition.MatchPattern != "" {
+		if _, err := regexp.Compile(expedition.MatchPattern); err != nil {
+			return fmt.Errorf("match_pattern is not a valid regular expression: %v", err)
+		}
+	}
+	if expedition.TrackingChecksum != "" && !utilities.IsTrackingChecksum(expedition.TrackingChecksum) {
+		return fmt.Errorf("tracking_checksum must be one of %s", strings.Join(utilities.TrackingChecksums, ", "))
+	}
+
+	return nil
+}
+
 // Request/Response structs
 type ExpeditionsListResponse struct {
 	Expeditions []models.ExpeditionResponse  `json:"expeditions"`
@@ -256,6 +511,21 @@ type UpdateExpeditionRequest struct {
 	Name  string `json:"name" binding:"required"`
 	Slug  string `json:"slug" binding:"required"`
 	Color string `json:"color" binding:"required"`
+
+	Instant      bool   `json:"instant" example:"false"`       // Same-day courier, its orders get the instant lane
+	PickupCutoff string `json:"pickup_cutoff" example:"16:00"` // Daily pickup time (HH:MM), empty for on demand pickup
+
+	MatchPattern  string `json:"match_pattern" example:"^JP[0-9]{10}$"` // Regular expression detecting its trackings, replaces the code prefix
+	MatchPriority int    `json:"match_priority" example:"0"`            // Higher priorities win when several expeditions match
+
+	TrackingProvider string `json:"tracking_provider" example:"shopee"`
+	TrackingEndpoint string `json:"tracking_endpoint" example:"https://tracking.example.com/status"`
+	TrackingAPIKey   string `json:"tracking_api_key"`
+
+	TrackingMinLength int    `json:"tracking_min_length" binding:"min=0" example:"12"`
+	TrackingMaxLength int    `json:"tracking_max_length" binding:"min=0" example:"16"`
+	TrackingPattern   string `json:"tracking_pattern" example:"^JNE[0-9]{10}$"`
+	TrackingChecksum  string `json:"tracking_checksum" example:"luhn"`
 }
 
 type CreateExpeditionRequest struct {
@@ -263,4 +533,40 @@ type CreateExpeditionRequest struct {
 	Name  string `json:"name" binding:"required"`
 	Slug  string `json:"slug" binding:"required"`
 	Color string `json:"color" binding:"required"`
+
+	Instant      bool   `json:"instant" example:"false"`       // Same-day courier, its orders get the instant lane
+	PickupCutoff string `json:"pickup_cutoff" example:"16:00"` // Daily pickup time (HH:MM), empty for on demand pickup
+
+	MatchPattern  string `json:"match_pattern" example:"^JP[0-9]{10}$"` // Regular expression detecting its trackings, replaces the code prefix
+	MatchPriority int    `json:"match_priority" example:"0"`            // Higher priorities win when several expeditions match
+
+	TrackingProvider string `json:"tracking_provider" example:"shopee"`
+	TrackingEndpoint string `json:"tracking_endpoint" example:"https://tracking.example.com/status"`
+	TrackingAPIKey   string `json:"tracking_api_key"`
+
+	TrackingMinLength int    `json:"tracking_min_length" binding:"min=0" example:"12"`
+	TrackingMaxLength int    `json:"tracking_max_length" binding:"min=0" example:"16"`
+	TrackingPattern   string `json:"tracking_pattern" example:"^JNE[0-9]{10}$"`
+	TrackingChecksum  string `json:"tracking_checksum" example:"luhn"`
+}
+
+type MatchExpeditionRequest struct {
+	Tracking string `json:"tracking" binding:"required" example:"JP1234567890"`
+}
+
+type ExpeditionMatchCandidate struct {
+	ExpeditionID uint   `json:"expedition_id"`
+	Code         string `json:"code" example:"JP"`
+	Name         string `json:"name" example:"J&T Express"`
+	Rule         string `json:"rule" example:"prefix"` // pattern or prefix
+	Matched      string `json:"matched" example:"JP"`
+	Priority     int    `json:"priority" example:"0"`
+}
+
+type MatchExpeditionResponse struct {
+	Tracking        string                     `json:"tracking" example:"JP1234567890"`
+	Expedition      *models.ExpeditionResponse `json:"expedition"` // null when no expedition matches
+	Valid           bool                       `json:"valid"`      // Tracking passes the expedition's scan validation
+	ValidationError string                     `json:"validation_error"`
+	Candidates      []ExpeditionMatchCandidate `json:"candidates"` // Every matching expedition, best match first
 }
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"context"
+	"livo-backend/config"
+	"livo-backend/integrations/ginee"
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type GineeController struct {
+	DB     *gorm.DB
+	Config *config.Config
+}
+
+// NewGineeController creates a new Ginee integration controller
+func NewGineeController(db *gorm.DB, cfg *config.Config) *GineeController {
+	return &GineeController{DB: db, Config: cfg}
+}
+
+// GetGineeRuns godoc
+// @Summary Get Ginee sync runs
+// @Description Get the runs of the Ginee order intake, newest first, with the orders fetched, created, skipped (known already or no tracking yet) and failed, and the errors of failed orders
+// @Tags integrations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param status query string false "Filter by status (running, completed, failed)"
+// @Success 200 {object} utilities.Response{data=GineeRunsListResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/integrations/ginee/runs [get]
+func (gc *GineeController) GetGineeRuns(c *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	query := gc.DB.WithContext(c).Model(&models.GineeSyncRun{})
+	if status := c.Query("status"); status != "" {
+		statuses := []string{models.GineeSyncStatusRunning, models.GineeSyncStatusCompleted, models.GineeSyncStatusFailed}
+		if !slices.Contains(statuses, status) {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid status", "status must be one of "+strings.Join(statuses, ", "))
+			return
+		}
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count Ginee sync runs", err.Error())
+		return
+	}
+
+	var runs []models.GineeSyncRun
+	if err := query.Preload("Trigger").Order("started_at DESC").Limit(limit).Offset(offset).Find(&runs).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve Ginee sync runs", err.Error())
+		return
+	}
+
+	runResponses := make([]models.GineeSyncRunResponse, len(runs))
+	for i := range runs {
+		runResponses[i] = runs[i].ToGineeSyncRunResponse()
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Ginee sync runs retrieved successfully", GineeRunsListResponse{
+		Runs:    runResponses,
+		Enabled: gc.Config.GineeSyncEnabled,
+		Pagination: utilities.PaginationResponse{
+			Page:  page,
+			Limit: limit,
+			Total: int(total),
+		},
+	})
+}
+
+// TriggerGineeSync godoc
+// @Summary Trigger a Ginee sync
+// @Description Poll Ginee for paid orders now instead of waiting for the schedule. The run covers the orders updated since the last completed run and continues in the background, poll the runs for the result.
+// @Tags integrations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} utilities.Response{data=models.GineeSyncRunResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/integrations/ginee/runs [post]
+func (gc *GineeController) TriggerGineeSync(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	run, err := ginee.Start(gc.DB.WithContext(c), gc.Config, &userID)
+	if err == ginee.ErrSyncDisabled {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeFeatureDisabled, "Ginee sync is disabled", err.Error())
+		return
+	}
+	if err == ginee.ErrSyncRunning {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeJobRunning, "A Ginee sync is already running", err.Error())
+		return
+	}
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to start Ginee sync", err.Error())
+		return
+	}
+
+	go func(run *models.GineeSyncRun) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(max(gc.Config.GineePollMinutes, 1))*time.Minute)
+		defer cancel()
+
+		if err := ginee.Execute(ctx, gc.DB, gc.Config, run); err != nil {
+			log.Printf("⚠️ Ginee sync %d failed: %v", run.ID, err)
+			return
+		}
+		log.Printf("✓ Ginee sync %d completed: %d of %d order(s) created", run.ID, run.Created, run.Fetched)
+	}(run)
+
+	utilities.SuccessResponse(c, http.StatusAccepted, "Ginee sync started", run.ToGineeSyncRunResponse())
+}
+
+// Request/Response structs
+type GineeRunsListResponse struct {
+	Runs       []models.GineeSyncRunResponse `json:"runs"`
+	Enabled    bool                          `json:"enabled"` // Whether the scheduled sync runs
+	Pagination utilities.PaginationResponse  `json:"pagination"`
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"livo-backend/graphql"
+	"livo-backend/utilities"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type GraphQLController struct {
+	DB     *gorm.DB
+	server http.Handler
+}
+
+// NewGraphQLController creates a new GraphQL controller
+func NewGraphQLController(db *gorm.DB) *GraphQLController {
+	return &GraphQLController{DB: db, server: graphql.NewServer(db)}
+}
+
+// Query godoc
+// @Summary Run a GraphQL query
+// @Description Read-only GraphQL gateway for dashboard pages, so a page can fetch orders, flows, reports and users in one request and select only the fields it shows. Query fields: orders, order, qc_ribbons, qc_onlines, outbounds, users (coordinators only), daily_summary and qc_dashboard; list fields return { total page limit items { ... } } and items have the fields of the matching REST responses. Related records (order details, operators, flow orders) are only loaded when selected, batched over the whole list. The schema is in graphql/schema.graphqls and can be introspected. Responses follow the GraphQL format, not the usual response envelope.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body GraphQLRequest true "GraphQL query"
+// @Success 200 {object} GraphQLResponse
+// @Failure 400 {object} GraphQLResponse
+// @Failure 401 {object} utilities.Response
+// @Failure 422 {object} GraphQLResponse
+// @Router /graphql [post]
+func (gc *GraphQLController) Query(c *gin.Context) {
+	ctx := graphql.WithCaller(c.Request.Context(), graphql.Caller{
+		UserID: c.GetUint("user_id"),
+		Roles:  c.GetStringSlice("roles"),
+	})
+	gc.server.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+}
+
+// GetSchema godoc
+// @Summary Get GraphQL query fields
+// @Description List the query fields of the GraphQL gateway with their arguments
+// @Tags graphql
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utilities.Response{data=[]GraphQLFieldInfo}
+// @Failure 401 {object} utilities.Response
+// @Router /graphql/schema [get]
+func (gc *GraphQLController) GetSchema(c *gin.Context) {
+	fields := make([]GraphQLFieldInfo, 0, len(graphql.Schema.Query.Fields))
+	for _, field := range graphql.Schema.Query.Fields {
+		if strings.HasPrefix(field.Name, "__") {
+			continue
+		}
+		arguments := make([]string, len(field.Arguments))
+		for i, argument := range field.Arguments {
+			arguments[i] = argument.Name + ": " + argument.Type.String()
+		}
+		fields = append(fields, GraphQLFieldInfo{Name: field.Name, Description: field.Description, Arguments: arguments, Type: field.Type.String()})
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "GraphQL schema retrieved successfully", fields)
+}
+
+// Request/Response structs
+type GraphQLRequest struct {
+	Query         string                 `json:"query" binding:"required" example:"{ orders(limit: 5) { total items { tracking picked_by } } }"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type GraphQLResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []GraphQLError         `json:"errors,omitempty"`
+}
+
+type GraphQLError struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+type GraphQLFieldInfo struct {
+	Name        string   `json:"name" example:"orders"`
+	Description string   `json:"description"`
+	Arguments   []string `json:"arguments" example:"page: Int,limit: Int"`
+	Type        string   `json:"type" example:"OrderList!"`
+}
@@ -0,0 +1,390 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type InboundController struct {
+	DB *gorm.DB
+}
+
+// NewInboundController creates a new inbound controller
+func NewInboundController(db *gorm.DB) *InboundController {
+	return &InboundController{DB: db}
+}
+
+// GetInbounds godoc
+// @Summary Get inbounds
+// @Description Get supplier shipments, newest first, optionally filtered by status and creation date
+// @Tags inbounds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param search query string false "Search by reference or supplier"
+// @Param status query string false "Filter by status (expected, receiving, received)"
+// @Param start_date query string false "Start date (YYYY-MM-DD format)"
+// @Param end_date query string false "End date (YYYY-MM-DD format)"
+// @Param sort_by query string false "Sort keys, comma separated, each optionally suffixed with :asc or :desc (created_at, expected_at, reference, supplier, status)" default(created_at:desc)
+// @Param sort_dir query string false "Direction of sort keys without a suffix: asc or desc" default(asc)
+// @Success 200 {object} utilities.Response{data=InboundsListResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Router /api/inbounds [get]
+func (ic *InboundController) GetInbounds(c *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	query := ic.DB.WithContext(c).Model(&models.Inbound{})
+	if search := c.Query("search"); search != "" {
+		query = query.Where("reference ILIKE ? OR supplier ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if startDate := c.Query("start_date"); startDate != "" {
+		parsed, err := utilities.ParseDate(startDate)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
+			return
+		}
+		start, _ := utilities.DayRange(parsed)
+		query = query.Where("created_at >= ?", start)
+	}
+	if endDate := c.Query("end_date"); endDate != "" {
+		parsed, err := utilities.ParseDate(endDate)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
+			return
+		}
+		_, nextDay := utilities.DayRange(parsed)
+		query = query.Where("created_at < ?", nextDay)
+	}
+
+	var total int64
+	sortScope, err := utilities.ApplySort(c, models.InboundSortKeys, "created_at:desc", "inbounds.id")
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid sort", err.Error())
+		return
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count inbounds", err.Error())
+		return
+	}
+
+	var inbounds []models.Inbound
+	if err := preloadInbound(query).Scopes(sortScope).Limit(limit).Offset(offset).Find(&inbounds).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve inbounds", err.Error())
+		return
+	}
+
+	inboundResponses := make([]models.InboundResponse, len(inbounds))
+	for i := range inbounds {
+		inboundResponses[i] = inbounds[i].ToInboundResponse()
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Inbounds retrieved successfully", InboundsListResponse{
+		Inbounds: inboundResponses,
+		Pagination: utilities.PaginationResponse{
+			Page:  page,
+			Limit: limit,
+			Total: int(total),
+		},
+	})
+}
+
+// GetInbound godoc
+// @Summary Get inbound
+// @Description Get a supplier shipment with its lines and what was counted so far
+// @Tags inbounds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Inbound ID"
+// @Success 200 {object} utilities.Response{data=models.InboundResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/inbounds/{id} [get]
+func (ic *InboundController) GetInbound(c *gin.Context) {
+	inboundID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid inbound ID", err.Error())
+		return
+	}
+
+	var inbound models.Inbound
+	if err := preloadInbound(ic.DB.WithContext(c)).First(&inbound, uint(inboundID)).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeInboundNotFound, "Inbound not found", "Failed to retrieve inbound")
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Inbound retrieved successfully", inbound.ToInboundResponse())
+}
+
+// CreateInbound godoc
+// @Summary Create inbound
+// @Description Announce a supplier shipment with the SKUs and quantities the supplier is sending. Every SKU must be a known product and appear once.
+// @Tags inbounds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body InboundRequest true "Inbound data"
+// @Success 201 {object} utilities.Response{data=models.InboundResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/inbounds [post]
+func (ic *InboundController) CreateInbound(c *gin.Context) {
+	var req InboundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	inbound := models.Inbound{Status: models.InboundStatusExpected, CreatedBy: &userID}
+	details, ok := ic.applyRequest(c, &inbound, req)
+	if !ok {
+		return
+	}
+
+	err := utilities.WithTransaction(ic.DB.WithContext(c), func(tx *gorm.DB) error {
+		if err := tx.Create(&inbound).Error; err != nil {
+			return fmt.Errorf("failed to create inbound: %w", err)
+		}
+		for i := range details {
+			details[i].InboundID = inbound.ID
+		}
+		if err := tx.Create(&details).Error; err != nil {
+			return fmt.Errorf("failed to create inbound details: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to create inbound")
+		return
+	}
+
+	preloadInbound(ic.DB.WithContext(c)).First(&inbound, inbound.ID)
+	utilities.SuccessResponse(c, http.StatusCreated, "Inbound created successfully", inbound.ToInboundResponse())
+}
+
+// UpdateInbound godoc
+// @Summary Update inbound
+// @Description Replace the reference, supplier, expected date, notes and lines of an inbound. Only allowed before receiving starts.
+// @Tags inbounds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Inbound ID"
+// @Param request body InboundRequest true "Inbound data"
+// @Success 200 {object} utilities.Response{data=models.InboundResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/inbounds/{id} [put]
+func (ic *InboundController) UpdateInbound(c *gin.Context) {
+	inboundID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid inbound ID", err.Error())
+		return
+	}
+
+	var req InboundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	var inbound models.Inbound
+	if err := ic.DB.WithContext(c).First(&inbound, uint(inboundID)).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeInboundNotFound, "Inbound not found", "Failed to retrieve inbound")
+		return
+	}
+	if inbound.Status != models.InboundStatusExpected {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeInboundStateInvalid, "Inbound cannot be changed", fmt.Sprintf("inbound is %s, only expected inbounds can be changed", inbound.Status))
+		return
+	}
+
+	details, ok := ic.applyRequest(c, &inbound, req)
+	if !ok {
+		return
+	}
+
+	err = utilities.WithTransaction(ic.DB.WithContext(c), func(tx *gorm.DB) error {
+		if err := tx.Save(&inbound).Error; err != nil {
+			return fmt.Errorf("failed to update inbound: %w", err)
+		}
+		if err := tx.Where("inbound_id = ?", inbound.ID).Delete(&models.InboundDetail{}).Error; err != nil {
+			return fmt.Errorf("failed to replace inbound details: %w", err)
+		}
+		for i := range details {
+			details[i].InboundID = inbound.ID
+		}
+		if err := tx.Create(&details).Error; err != nil {
+			return fmt.Errorf("failed to replace inbound details: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to update inbound")
+		return
+	}
+
+	preloadInbound(ic.DB.WithContext(c)).First(&inbound, inbound.ID)
+	utilities.SuccessResponse(c, http.StatusOK, "Inbound updated successfully", inbound.ToInboundResponse())
+}
+
+// DeleteInbound godoc
+// @Summary Delete inbound
+// @Description Delete an inbound that was announced by mistake. Only allowed before receiving starts.
+// @Tags inbounds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Inbound ID"
+// @Success 200 {object} utilities.Response
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/inbounds/{id} [delete]
+func (ic *InboundController) DeleteInbound(c *gin.Context) {
+	inboundID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid inbound ID", err.Error())
+		return
+	}
+
+	var inbound models.Inbound
+	if err := ic.DB.WithContext(c).First(&inbound, uint(inboundID)).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeInboundNotFound, "Inbound not found", "Failed to retrieve inbound")
+		return
+	}
+	if inbound.Status != models.InboundStatusExpected {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeInboundStateInvalid, "Inbound cannot be changed", fmt.Sprintf("inbound is %s, only expected inbounds can be deleted", inbound.Status))
+		return
+	}
+
+	err = utilities.WithTransaction(ic.DB.WithContext(c), func(tx *gorm.DB) error {
+		if err := tx.Where("inbound_id = ?", inbound.ID).Delete(&models.InboundDetail{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&inbound).Error
+	})
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete inbound", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Inbound deleted successfully", nil)
+}
+
+// applyRequest copies req onto inbound and returns its lines. It answers and returns ok false when
+// the request is invalid or the reference is taken by another inbound.
+func (ic *InboundController) applyRequest(c *gin.Context, inbound *models.Inbound, req InboundRequest) ([]models.InboundDetail, bool) {
+	inbound.Reference = strings.TrimSpace(req.Reference)
+	inbound.Supplier = strings.TrimSpace(req.Supplier)
+	inbound.Notes = strings.TrimSpace(req.Notes)
+	inbound.ExpectedAt = nil
+	if req.ExpectedAt != "" {
+		expectedAt, err := utilities.ParseDate(req.ExpectedAt)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid expected_at format", "expected_at must be in YYYY-MM-DD format")
+			return nil, false
+		}
+		inbound.ExpectedAt = &expectedAt
+	}
+
+	var existing models.Inbound
+	err := ic.DB.WithContext(c).Where("reference = ? AND id <> ?", inbound.Reference, inbound.ID).First(&existing).Error
+	if err == nil {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeDuplicateInbound, "Inbound reference already exists", fmt.Sprintf("reference %s is used by inbound %d", inbound.Reference, existing.ID))
+		return nil, false
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to validate inbound", err.Error())
+		return nil, false
+	}
+
+	// Every SKU must be a known product, once per inbound
+	skus := make([]string, 0, len(req.Details))
+	seen := make(map[string]bool, len(req.Details))
+	for _, detail := range req.Details {
+		sku := strings.TrimSpace(detail.ProductSKU)
+		if seen[strings.ToUpper(sku)] {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInboundDetailsInvalid, "Invalid inbound details", fmt.Sprintf("SKU %s is listed more than once", sku))
+			return nil, false
+		}
+		seen[strings.ToUpper(sku)] = true
+		skus = append(skus, sku)
+	}
+
+	var known []string
+	if err := ic.DB.WithContext(c).Model(&models.Product{}).Where("sku IN ?", skus).Pluck("sku", &known).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to validate inbound", err.Error())
+		return nil, false
+	}
+	knownSKUs := make(map[string]bool, len(known))
+	for _, sku := range known {
+		knownSKUs[sku] = true
+	}
+
+	details := make([]models.InboundDetail, len(req.Details))
+	for i, detail := range req.Details {
+		if !knownSKUs[skus[i]] {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInboundDetailsInvalid, "Invalid inbound details", fmt.Sprintf("no product with SKU %s", skus[i]))
+			return nil, false
+		}
+		details[i] = models.InboundDetail{ProductSKU: skus[i], ExpectedQuantity: detail.Quantity}
+	}
+	return details, true
+}
+
+// preloadInbound loads the lines of inbounds with their products and the operators involved
+func preloadInbound(query *gorm.DB) *gorm.DB {
+	return query.Preload("InboundDetails", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).
+		Preload("InboundDetails.Product").
+		Preload("InboundDetails.Checker").
+		Preload("Creator").
+		Preload("Receiver")
+}
+
+// Request/Response structs
+type InboundDetailRequest struct {
+	ProductSKU string `json:"product_sku" binding:"required,sku" example:"LY-GLIPOW-128-HL705-30G"`
+	Quantity   int    `json:"quantity" binding:"required,min=1" example:"100"`
+}
+
+type InboundRequest struct {
+	Reference  string                 `json:"reference" binding:"required,max=100" example:"PO-2510-0042"`
+	Supplier   string                 `json:"supplier" binding:"required,max=255" example:"CV Sumber Makmur"`
+	ExpectedAt string                 `json:"expected_at" binding:"omitempty,date" example:"2025-10-20"` // YYYY-MM-DD, optional
+	Notes      string                 `json:"notes" example:"Two pallets, call before unloading"`
+	Details    []InboundDetailRequest `json:"details" binding:"required,min=1,dive"`
+}
+
+type InboundsListResponse struct {
+	Inbounds   []models.InboundResponse     `json:"inbounds"`
+	Pagination utilities.PaginationResponse `json:"pagination"`
+}
@@ -1,13 +1,16 @@
 package controllers
 
 import (
+	"fmt"
 	"livo-backend/models"
 	"livo-backend/utilities"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type LostFoundController struct {
@@ -21,7 +24,7 @@ func NewLostFoundController(db *gorm.DB) *LostFoundController {
 
 // GetLostFounds godoc
 // @Summary Get all lost and found items
-// @Description Get list of all lost and found items.
+// @Description Get list of all lost and found items with their resolution.
 // @Tags lost-founds
 // @Accept json
 // @Produce json
@@ -29,7 +32,11 @@ func NewLostFoundController(db *gorm.DB) *LostFoundController {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param search query string false "Search by product sku or reason (partial match)"
+// @Param status query string false "Filter by status: open, found or written_off"
+// @Param sort_by query string false "Sort keys, comma separated, each optionally suffixed with :asc or :desc (created_at, product_sku, quantity, status)" default(created_at:desc)
+// @Param sort_dir query string false "Direction of sort keys without a suffix: asc or desc" default(asc)
 // @Success 200 {object} utilities.Response{data=LostFoundsListResponse}
+// @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Router /api/lost-founds [get]
@@ -46,8 +53,11 @@ func (lfc *LostFoundController) GetLostFounds(c *gin.Context) {
 	var total int64
 
 	// Build query with optional search
-	query := lfc.DB.Model(&models.LostFound{}).
+	query := lfc.DB.WithContext(c).Model(&models.LostFound{}).
 		Preload("Product").
+		Preload("Complain").
+		Preload("Return").
+		Preload("Resolver").
 		Preload("CreateOperator.UserRoles.Role").
 		Preload("CreateOperator.UserRoles.Assigner")
 
@@ -55,6 +65,15 @@ func (lfc *LostFoundController) GetLostFounds(c *gin.Context) {
 		// Search by product sku or reason with partial match
 		query = query.Where("product_sku ILIKE ? OR reason ILIKE ?", "%"+search+"%", "%"+search+"%")
 	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	sortScope, err := utilities.ApplySort(c, models.LostFoundSortKeys, "created_at:desc", "lost_founds.id")
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid sort", err.Error())
+		return
+	}
 
 	// Get total count with search filter
 	if err := query.Count(&total).Error; err != nil {
@@ -63,7 +82,7 @@ func (lfc *LostFoundController) GetLostFounds(c *gin.Context) {
 	}
 
 	// Get lost founds with pagination, search filter, and order by id descending
-	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&lostFounds).Error; err != nil {
+	if err := query.Scopes(sortScope).Limit(limit).Offset(offset).Find(&lostFounds).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve lost and found items", err.Error())
 		return
 	}
@@ -109,11 +128,14 @@ func (lfc *LostFoundController) GetLostFound(c *gin.Context) {
 	lostFoundID := c.Param("id")
 
 	var lostFound models.LostFound
-	if err := lfc.DB.Preload("Product").
+	if err := lfc.DB.WithContext(c).Preload("Product").
+		Preload("Complain").
+		Preload("Return").
+		Preload("Resolver").
 		Preload("CreateOperator.UserRoles.Role").
 		Preload("CreateOperator.UserRoles.Assigner").
 		First(&lostFound, lostFoundID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Lost and found item not found", err.Error())
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeLostFoundNotFound, "Lost and found item not found", "Failed to retrieve lost and found item")
 		return
 	}
 
@@ -145,8 +167,8 @@ func (lfc *LostFoundController) UpdateLostFound(c *gin.Context) {
 	}
 
 	var lostFound models.LostFound
-	if err := lfc.DB.First(&lostFound, lostFoundID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Lost and found item not found", err.Error())
+	if err := lfc.DB.WithContext(c).First(&lostFound, lostFoundID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeLostFoundNotFound, "Lost and found item not found", "Failed to retrieve lost and found item")
 		return
 	}
 
@@ -155,13 +177,16 @@ func (lfc *LostFoundController) UpdateLostFound(c *gin.Context) {
 	lostFound.Quantity = req.Quantity
 	lostFound.Reason = req.Reason
 
-	if err := lfc.DB.Save(&lostFound).Error; err != nil {
+	if err := lfc.DB.WithContext(c).Save(&lostFound).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update lost and found item", err.Error())
 		return
 	}
 
 	// Reload with relationships
-	if err := lfc.DB.Preload("Product").
+	if err := lfc.DB.WithContext(c).Preload("Product").
+		Preload("Complain").
+		Preload("Return").
+		Preload("Resolver").
 		Preload("CreateOperator.UserRoles.Role").
 		Preload("CreateOperator.UserRoles.Assigner").
 		First(&lostFound, lostFoundID).Error; err != nil {
@@ -189,12 +214,12 @@ func (lfc *LostFoundController) RemoveLostFound(c *gin.Context) {
 	lostFoundID := c.Param("id")
 
 	var lostFound models.LostFound
-	if err := lfc.DB.First(&lostFound, lostFoundID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Lost and found item not found", err.Error())
+	if err := lfc.DB.WithContext(c).First(&lostFound, lostFoundID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeLostFoundNotFound, "Lost and found item not found", "Failed to retrieve lost and found item")
 		return
 	}
 
-	if err := lfc.DB.Delete(&lostFound).Error; err != nil {
+	if err := lfc.DB.WithContext(c).Delete(&lostFound).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to remove lost and found item", err.Error())
 		return
 	}
@@ -202,6 +227,91 @@ func (lfc *LostFoundController) RemoveLostFound(c *gin.Context) {
 	utilities.SuccessResponse(c, http.StatusOK, "Lost and found item removed successfully", nil)
 }
 
+// ResolveLostFound godoc
+// @Summary Resolve a lost and found item
+// @Description Close an open lost and found item as found or written off, optionally linking the complain or return it is accounted for by. The response carries the stock adjustment of the resolution: found items come back into stock, written off items leave it.
+// @Tags lost-founds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Lost and Found ID"
+// @Param request body ResolveLostFoundRequest true "Resolve lost and found request"
+// @Success 200 {object} utilities.Response{data=models.LostFoundResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/lost-founds/{id}/resolve [put]
+func (lfc *LostFoundController) ResolveLostFound(c *gin.Context) {
+	lostFoundID := c.Param("id")
+	userID := c.GetUint("user_id")
+
+	var req ResolveLostFoundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	var lostFound models.LostFound
+	err := utilities.WithTransaction(lfc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Find the item, locked until commit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&lostFound, lostFoundID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeLostFoundNotFound, "Lost and found item not found", "no lost and found item found with the specified ID")
+			}
+			return err
+		}
+
+		if lostFound.Status != models.LostFoundOpen {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeAlreadyResolved, "Lost and found item already resolved", fmt.Sprintf("item is already %s", lostFound.Status))
+		}
+
+		if req.ComplainID != nil {
+			if err := tx.First(&models.Complain{}, *req.ComplainID).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeComplainNotFound, "Complain not found", "no complain found with the specified complain_id")
+				}
+				return err
+			}
+		}
+		if req.ReturnID != nil {
+			if err := tx.First(&models.Return{}, *req.ReturnID).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeReturnNotFound, "Return not found", "no return found with the specified return_id")
+				}
+				return err
+			}
+		}
+
+		now := time.Now()
+		lostFound.Status = req.Status
+		lostFound.ComplainID = req.ComplainID
+		lostFound.ReturnID = req.ReturnID
+		lostFound.ResolutionNote = req.Note
+		lostFound.ResolvedBy = &userID
+		lostFound.ResolvedAt = &now
+		if err := tx.Save(&lostFound).Error; err != nil {
+			return fmt.Errorf("failed to update lost and found item: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to resolve lost and found item")
+		return
+	}
+
+	lfc.DB.WithContext(c).Preload("Product").
+		Preload("Complain").
+		Preload("Return").
+		Preload("Resolver").
+		Preload("CreateOperator.UserRoles.Role").
+		Preload("CreateOperator.UserRoles.Assigner").
+		First(&lostFound, lostFound.ID)
+
+	utilities.SuccessResponse(c, http.StatusOK, "Lost and found item resolved successfully", lostFound.ToLostFoundResponse())
+}
+
 // CreateLostFound godoc
 // @Summary Create new lost and found item
 // @Description Create a new lost and found item.
@@ -219,7 +329,7 @@ func (lfc *LostFoundController) CreateLostFound(c *gin.Context) {
 	// Get user ID from JWT token
 	userID, exists := c.Get("user_id")
 	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in token")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeAuthRequired, "Unauthorized", "User ID not found in token")
 		return
 	}
 
@@ -244,13 +354,16 @@ func (lfc *LostFoundController) CreateLostFound(c *gin.Context) {
 		CreatedBy:  &createdBy,
 	}
 
-	if err := lfc.DB.Create(&lostFound).Error; err != nil {
+	if err := lfc.DB.WithContext(c).Create(&lostFound).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create lost and found item", err.Error())
 		return
 	}
 
 	// Reload with relationships
-	if err := lfc.DB.Preload("Product").
+	if err := lfc.DB.WithContext(c).Preload("Product").
+		Preload("Complain").
+		Preload("Return").
+		Preload("Resolver").
 		Preload("CreateOperator.UserRoles.Role").
 		Preload("CreateOperator.UserRoles.Assigner").
 		First(&lostFound, lostFound.ID).Error; err != nil {
@@ -273,6 +386,13 @@ type UpdateLostFoundRequest struct {
 	Reason     string `json:"reason" binding:"required"`
 }
 
+type ResolveLostFoundRequest struct {
+	Status     string `json:"status" binding:"required,oneof=found written_off" example:"found"`
+	ComplainID *uint  `json:"complain_id" example:"12"` // Complain the item is accounted for by, if any
+	ReturnID   *uint  `json:"return_id" example:"34"`   // Return the item is accounted for by, if any
+	Note       string `json:"note" binding:"max=500" example:"Found behind rack B-12"`
+}
+
 type CreateLostFoundRequest struct {
 	ProductSKU string `json:"product_sku" binding:"required"`
 	Quantity   int    `json:"quantity" binding:"required,min=1"`
@@ -35,7 +35,7 @@ func (mcc *MobileChannelController) GetMobileChannels(c *gin.Context) {
 	var total int64
 
 	// Build query with optional search
-	query := mcc.DB.Model(&models.Channel{})
+	query := mcc.DB.WithContext(c).Model(&models.Channel{})
 
 	if search != "" {
 		// Search by channel code or name with partial match
@@ -66,9 +66,9 @@ func (mcc *MobileChannelController) GetMobileChannels(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Channels retrieved successfully"
+	message := utilities.Messagef(c, "Channels retrieved successfully")
 	if search != "" {
-		message += " (filtered by code or name: " + search + ")"
+		message += utilities.Messagef(c, " (filtered by code or name: %s)", search)
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
@@ -0,0 +1,225 @@
+package controllers
+
+import (
+	"fmt"
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MobileInboundController struct {
+	DB *gorm.DB
+}
+
+// NewMobileInboundController creates a new mobile inbound controller
+func NewMobileInboundController(db *gorm.DB) *MobileInboundController {
+	return &MobileInboundController{DB: db}
+}
+
+// GetMobileInbounds godoc
+// @Summary Get open inbounds by mobile
+// @Description Get supplier shipments still to be received (expected or receiving), soonest expected first
+// @Tags inbounds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param search query string false "Search by reference or supplier"
+// @Success 200 {object} utilities.Response{data=InboundsListResponse}
+// @Failure 401 {object} utilities.Response
+// @Router /api/mobile/inbounds [get]
+func (mic *MobileInboundController) GetMobileInbounds(c *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	query := mic.DB.WithContext(c).Model(&models.Inbound{}).
+		Where("status IN ?", []string{models.InboundStatusExpected, models.InboundStatusReceiving})
+	if search := c.Query("search"); search != "" {
+		query = query.Where("reference ILIKE ? OR supplier ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count inbounds", err.Error())
+		return
+	}
+
+	var inbounds []models.Inbound
+	if err := preloadInbound(query).Order("expected_at ASC NULLS LAST, id ASC").Limit(limit).Offset(offset).Find(&inbounds).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve inbounds", err.Error())
+		return
+	}
+
+	inboundResponses := make([]models.InboundResponse, len(inbounds))
+	for i := range inbounds {
+		inboundResponses[i] = inbounds[i].ToInboundResponse()
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Inbounds retrieved successfully", InboundsListResponse{
+		Inbounds: inboundResponses,
+		Pagination: utilities.PaginationResponse{
+			Page:  page,
+			Limit: limit,
+			Total: int(total),
+		},
+	})
+}
+
+// GetInboundChecklist godoc
+// @Summary Get inbound receiving checklist
+// @Description Get the lines of an inbound to count, unchecked lines first, with the quantity expected and what was counted so far
+// @Tags inbounds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Inbound ID"
+// @Success 200 {object} utilities.Response{data=models.InboundResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/mobile/inbounds/{id}/checklist [get]
+func (mic *MobileInboundController) GetInboundChecklist(c *gin.Context) {
+	inboundID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid inbound ID", err.Error())
+		return
+	}
+
+	var inbound models.Inbound
+	if err := preloadInbound(mic.DB.WithContext(c)).First(&inbound, uint(inboundID)).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeInboundNotFound, "Inbound not found", "Failed to retrieve inbound")
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Inbound checklist retrieved successfully", checklistResponse(&inbound))
+}
+
+// CheckInboundItems godoc
+// @Summary Check inbound lines
+// @Description Record the quantities counted for lines of an inbound, each identified by its SKU or the product barcode scanned. A line counted again is overwritten. With complete set, receiving is completed once every line is checked.
+// @Tags inbounds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Inbound ID"
+// @Param request body InboundChecklistRequest true "Counted lines"
+// @Success 200 {object} utilities.Response{data=models.InboundResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/mobile/inbounds/{id}/checklist [put]
+func (mic *MobileInboundController) CheckInboundItems(c *gin.Context) {
+	inboundID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid inbound ID", err.Error())
+		return
+	}
+
+	var req InboundChecklistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	now := utilities.Now()
+
+	var inbound models.Inbound
+	err = utilities.WithTransaction(mic.DB.WithContext(c), func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&inbound, uint(inboundID)).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeInboundNotFound, "Inbound not found", err.Error())
+			}
+			return err
+		}
+		if inbound.Status == models.InboundStatusReceived {
+			return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeInboundStateInvalid, "Inbound cannot be changed", "inbound is received already")
+		}
+		if err := tx.Preload("Product").Where("inbound_id = ?", inbound.ID).Order("id ASC").Find(&inbound.InboundDetails).Error; err != nil {
+			return fmt.Errorf("failed to load inbound details: %w", err)
+		}
+
+		for _, item := range req.Items {
+			detail := inbound.DetailByCode(item.Code)
+			if detail == nil {
+				return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeInboundDetailsInvalid, "Invalid inbound details", fmt.Sprintf("%s is not a line of inbound %s", item.Code, inbound.Reference))
+			}
+			if err := models.ValidateInboundCount(item.ReceivedQuantity, item.DamagedQuantity); err != nil {
+				return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeInboundCountInvalid, "Invalid inbound count", fmt.Sprintf("%s: %s", detail.ProductSKU, err.Error()))
+			}
+
+			detail.ReceivedQuantity = item.ReceivedQuantity
+			detail.DamagedQuantity = item.DamagedQuantity
+			detail.Note = strings.TrimSpace(item.Note)
+			detail.CheckedBy = &userID
+			detail.CheckedAt = &now
+			if err := tx.Model(detail).Select("ReceivedQuantity", "DamagedQuantity", "Note", "CheckedBy", "CheckedAt").Updates(detail).Error; err != nil {
+				return fmt.Errorf("failed to update inbound detail: %w", err)
+			}
+		}
+
+		updates := map[string]interface{}{"status": models.InboundStatusReceiving}
+		if req.Complete {
+			if unchecked := inbound.UncheckedSKUs(); len(unchecked) > 0 {
+				return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeInboundNotChecked, "Inbound has unchecked lines", fmt.Sprintf("lines not counted yet: %s", strings.Join(unchecked, ", ")))
+			}
+			updates = map[string]interface{}{
+				"status":      models.InboundStatusReceived,
+				"received_by": userID,
+				"received_at": now,
+			}
+		}
+		if err := tx.Model(&inbound).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update inbound: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to check inbound")
+		return
+	}
+
+	preloadInbound(mic.DB.WithContext(c)).First(&inbound, inbound.ID)
+	utilities.SuccessResponse(c, http.StatusOK, "Inbound checked successfully", checklistResponse(&inbound))
+}
+
+// checklistResponse returns the response of inbound with the lines still to count first
+func checklistResponse(inbound *models.Inbound) models.InboundResponse {
+	response := inbound.ToInboundResponse()
+	lines := make([]models.InboundDetailResponse, 0, len(response.Details))
+	for _, line := range response.Details {
+		if !line.Checked {
+			lines = append(lines, line)
+		}
+	}
+	for _, line := range response.Details {
+		if line.Checked {
+			lines = append(lines, line)
+		}
+	}
+	response.Details = lines
+	return response
+}
+
+// Request/Response structs
+type InboundChecklistItem struct {
+	Code             string `json:"code" binding:"required,sku" example:"LY-GLIPOW-128-HL705-30G"` // SKU or product barcode scanned
+	ReceivedQuantity int    `json:"received_quantity" binding:"min=0" example:"98"`
+	DamagedQuantity  int    `json:"damaged_quantity" binding:"min=0" example:"2"`
+	Note             string `json:"note" example:"Two boxes crushed"`
+}
+
+type InboundChecklistRequest struct {
+	Items    []InboundChecklistItem `json:"items" binding:"omitempty,dive"`
+	Complete bool                   `json:"complete" example:"false"` // Complete receiving, every line must be checked
+}
@@ -1,33 +1,44 @@
 package controllers
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"livo-backend/config"
 	"livo-backend/models"
+	"livo-backend/repositories"
 	"livo-backend/utilities"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type MobileOrderController struct {
-	DB *gorm.DB
+	DB     *gorm.DB
+	Config *config.Config
+	Orders *repositories.OrderRepository
 }
 
 // NewMobileOrderController creates a new mobile order controller
-func NewMobileOrderController(db *gorm.DB) *MobileOrderController {
-	return &MobileOrderController{DB: db}
+func NewMobileOrderController(db *gorm.DB, cfg *config.Config) *MobileOrderController {
+	return &MobileOrderController{DB: db, Config: cfg, Orders: repositories.NewOrderRepository(db)}
 }
 
 // GetMyPickingOrders godoc
 // @Summary Get my ongoing picking orders by mobile
-// @Description Get list of orders currently being picked by the logged-in user (processing status: "picking process")
+// @Description Get list of orders currently being picked by the logged-in user (processing status: "picking process"). Late and urgent orders come first, then instant courier orders, then orders in the picker's zones; in restrict zone mode orders outside them are hidden. Order lines come in pick path order: zones in pick sequence, then aisle and rack within a zone, then locations outside every zone alphabetically.
 // @Tags mobile-orders
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param instant query bool false "Only instant courier orders (true) or only regular orders (false)"
 // @Success 200 {object} utilities.Response{data=[]models.OrderResponse}
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
@@ -36,78 +47,203 @@ func (moc *MobileOrderController) GetMyPickingOrders(c *gin.Context) {
 	// Get current user ID from context
 	userIDInterface, exists := c.Get("user_id")
 	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "User not found", "user ID not found in context")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeUserNotFound, "User not found", "user ID not found in context")
 		return
 	}
 
 	userID, ok := userIDInterface.(uint)
 	if !ok {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID", "user ID has invalid type")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidToken, "Invalid user ID", "user ID has invalid type")
+		return
+	}
+
+	zoneIDs, err := models.GetPickerZoneIDs(moc.DB.WithContext(c), userID)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve picker zones", err.Error())
 		return
 	}
 
 	var orders []models.Order
 
 	// Get orders currently being picked by this user
-	if err := moc.DB.Where("picked_by = ? AND processing_status = ?", userID, "picking process").
-		Order("id ASC").
-		Preload("OrderDetails").
-		Preload("PickOperator").
-		Preload("AssignOperator").
-		Preload("PendingOperator").
-		Preload("ChangeOperator").
-		Preload("CancelOperator").
+	query := moc.DB.WithContext(c).Where("picked_by = ? AND processing_status = ?", userID, "picking process")
+	if len(zoneIDs) > 0 {
+		if moc.Config.PickerZoneMode == models.PickerZoneModeRestrict {
+			query = query.Where(models.OrderInZonesCondition, zoneIDs).Order(models.OrderPriorityRank + ", instant DESC, id ASC")
+		} else {
+			query = query.Order(models.OrderByZonePreference(zoneIDs))
+		}
+	} else {
+		query = query.Order(models.OrderPriorityRank + ", instant DESC, id ASC")
+	}
+
+	query, err = filterInstant(c, query)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid instant filter", err.Error())
+		return
+	}
+
+	if err := query.Scopes(repositories.OrderWithDetails(repositories.OrderOperators...)).
 		Find(&orders).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve picking orders", err.Error())
 		return
 	}
 
-	// Manually fetch and attach products to order details, then sort by location
+	pickPath, err := models.LoadPickPath(moc.DB.WithContext(c))
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve zones", err.Error())
+		return
+	}
+
+	// Attach products to order details, then sort them in pick path order
+	if err := moc.Orders.AttachListProducts(c, orders); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve picking orders", err.Error())
+		return
+	}
 	for i := range orders {
-		// First, attach products to order details
-		for j := range orders[i].OrderDetails {
-			var product models.Product
-			if err := moc.DB.Where("sku = ?", orders[i].OrderDetails[j].Sku).First(&product).Error; err == nil {
-				orders[i].OrderDetails[j].Product = &product
+		pickPath.SortOrderDetails(orders[i].OrderDetails)
+	}
+
+	// Convert to response format
+	orderResponses := make([]models.OrderResponse, len(orders))
+	for i, order := range orders {
+		orderResponses[i] = order.ToOrderResponse()
+	}
+
+	message := utilities.Messagef(c, "Found %d order(s) currently being picked for you", len(orders))
+
+	utilities.SuccessResponse(c, http.StatusOK, message, orderResponses)
+}
+
+// GetPickerDashboard godoc
+// @Summary Get my picker dashboard by mobile
+// @Description Get the logged-in picker's numbers for the app home screen in one call: orders in hand, orders picked today, orders set back to pending picking today, today's average pick time (assignment to completed pick) and the current streak of picks completed fully scanned, broken by a pick completed with an override reason or a pending pick.
+// @Tags mobile-orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utilities.Response{data=models.PickerDashboard}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/mobile/dashboard [get]
+func (moc *MobileOrderController) GetPickerDashboard(c *gin.Context) {
+	dashboard, err := models.BuildPickerDashboard(moc.DB.WithContext(c), c.GetUint("user_id"), time.Now())
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build picker dashboard", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Picker dashboard retrieved successfully", dashboard)
+}
+
+// GrabNextOrder godoc
+// @Summary Grab the next order to pick by mobile
+// @Description Assign the next "ready to pick" order to the logged-in picker: late and urgent orders first, then instant courier orders, then earliest sent_before. Orders in the picker's zones are preferred; in restrict zone mode only those are eligible. Orders on hold are skipped. Urgent orders (instant or due within the urgent window) go to pickers in proportion to their productivity weight: pickers below the team average in pick speed and accuracy get regular orders first unless none are left.
+// @Tags mobile-orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utilities.Response{data=models.OrderResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/mobile/orders/grab-next [post]
+func (moc *MobileOrderController) GrabNextOrder(c *gin.Context) {
+	// Get current user ID from context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeUserNotFound, "User not found", "user ID not found in context")
+		return
+	}
+
+	userID, ok := userIDInterface.(uint)
+	if !ok {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidToken, "Invalid user ID", "user ID has invalid type")
+		return
+	}
+
+	zoneIDs, err := models.GetPickerZoneIDs(moc.DB.WithContext(c), userID)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve picker zones", err.Error())
+		return
+	}
+
+	weight, err := models.PickerWeight(moc.DB.WithContext(c), userID, time.Now())
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve picker productivity", err.Error())
+		return
+	}
+
+	var order models.Order
+	err = utilities.WithTransaction(moc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Skip rows another picker is grabbing at the same moment
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("processing_status = ?", "ready to pick").
+			Where("event_status IS NULL OR event_status <> ?", "cancelled").
+			Where("merged_into_id IS NULL").
+			Where("held_at IS NULL")
+
+		if len(zoneIDs) > 0 {
+			if moc.Config.PickerZoneMode == models.PickerZoneModeRestrict {
+				query = query.Where(models.OrderInZonesCondition, zoneIDs).Order(models.OrderPriorityRank + ", instant DESC, sent_before ASC, id ASC")
+			} else {
+				query = query.Order(models.OrderByZonePreference(zoneIDs))
 			}
+		} else {
+			query = query.Order(models.OrderPriorityRank + ", instant DESC, sent_before ASC, id ASC")
 		}
 
-		// Sort order details by product location
-		// Using a simple bubble sort to keep it readable
-		for j := 0; j < len(orders[i].OrderDetails)-1; j++ {
-			for k := j + 1; k < len(orders[i].OrderDetails); k++ {
-				locationJ := ""
-				locationK := ""
-
-				if orders[i].OrderDetails[j].Product != nil {
-					locationJ = orders[i].OrderDetails[j].Product.Location
-				}
-				if orders[i].OrderDetails[k].Product != nil {
-					locationK = orders[i].OrderDetails[k].Product.Location
-				}
+		// Pickers below the team average leave urgent orders to faster, more accurate pickers while
+		// regular orders remain, the lower their weight the more often
+		now := time.Now()
+		query = query.Session(&gorm.Session{})
+		found := false
+		if !models.ServeUrgentFirst(weight) {
+			err := query.Where("NOT "+models.UrgentOrderCondition, now.Add(models.CurrentSettings().PickerProductivity.UrgentWindow)).First(&order).Error
+			if err != nil && err != gorm.ErrRecordNotFound {
+				return fmt.Errorf("failed to find next order: %w", err)
+			}
+			found = err == nil
+		}
 
-				// Sort alphabetically by location
-				if locationJ > locationK {
-					orders[i].OrderDetails[j], orders[i].OrderDetails[k] = orders[i].OrderDetails[k], orders[i].OrderDetails[j]
+		if !found {
+			if err := query.First(&order).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeNoOrderAvailable, "No order available", "no ready to pick order found for your zones")
 				}
+				return fmt.Errorf("failed to find next order: %w", err)
 			}
 		}
-	}
 
-	// Convert to response format
-	orderResponses := make([]models.OrderResponse, len(orders))
-	for i, order := range orders {
-		orderResponses[i] = order.ToOrderResponse()
+		order.AssignedBy = &userID
+		order.AssignedAt = &now
+		order.PickedBy = &userID
+		order.ProcessingStatus = "picking process"
+
+		if err := tx.Save(&order).Error; err != nil {
+			return fmt.Errorf("failed to assign order: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to assign order")
+		return
 	}
 
-	message := fmt.Sprintf("Found %d order(s) currently being picked for you", len(orders))
+	// Load order with relationships and products, in pick path order
+	if err := moc.Orders.Reload(c, &order, repositories.OrderAssigner); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload order", err.Error())
+		return
+	}
+	moc.sortByPickPath(c, order.OrderDetails)
 
-	utilities.SuccessResponse(c, http.StatusOK, message, orderResponses)
+	utilities.SuccessResponse(c, http.StatusOK, utilities.Messagef(c, "Order %s assigned to you", order.Tracking), order.ToOrderResponse())
 }
 
 // GetMyPickingOrder godoc
 // @Summary Get my ongoing picking order by mobile
-// @Description Get the order currently being picked by the logged-in user (processing status: "picking process")
+// @Description Get the order currently being picked by the logged-in user (processing status: "picking process"), its lines in pick path order
 // @Tags mobile-orders
 // @Accept json
 // @Produce json
@@ -120,143 +256,284 @@ func (moc *MobileOrderController) GetMyPickingOrders(c *gin.Context) {
 // @Failure 404 {object} utilities.Response
 // @Router /api/mobile/orders/{id} [get]
 func (moc *MobileOrderController) GetMyPickingOrder(c *gin.Context) {
-	orderID := c.Param("id")
-	var order models.Order
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid order ID", err.Error())
+		return
+	}
 
-	if err := moc.DB.Preload("OrderDetails").
-		Preload("PickOperator").
-		Preload("AssignOperator").
-		Preload("PendingOperator").
-		Preload("ChangeOperator").
-		Preload("CancelOperator").
-		First(&order, orderID).Error; err != nil {
+	// Load order with relationships and products, in pick path order
+	order, err := moc.Orders.FindWithDetails(c, uint(orderID), repositories.OrderOperators...)
+	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			utilities.ErrorResponse(c, http.StatusNotFound, "Order not found", "no order found with the specified ID")
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
 			return
 		}
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order", err.Error())
 		return
 	}
 
-	// Manually fetch and attach products
-	for i := range order.OrderDetails {
-		var product models.Product
-		if err := moc.DB.Where("sku = ?", order.OrderDetails[i].Sku).First(&product).Error; err == nil {
-			order.OrderDetails[i].Product = &product
-		}
-	}
+	moc.sortByPickPath(c, order.OrderDetails)
 
 	utilities.SuccessResponse(c, http.StatusOK, "Order retrieved successfully", order.ToOrderResponse())
 }
 
-// CompletePickingOrder godoc
-// @Summary Complete picking process by mobile
-// @Description Change order processing status from "picking process" to "picking complete" and create pick order records
+// sortByPickPath sorts order lines in pick path order, leaving them as they are when the zones
+// cannot be loaded
+func (moc *MobileOrderController) sortByPickPath(c *gin.Context, details []models.OrderDetail) {
+	if pickPath, err := models.LoadPickPath(moc.DB.WithContext(c)); err == nil {
+		pickPath.SortOrderDetails(details)
+	}
+}
+
+// ScanOrderDetail godoc
+// @Summary Scan a product barcode for an order line by mobile
+// @Description Record a scanned product barcode and quantity against an order detail of an order the logged-in picker is picking. The barcode must match the product of the line and the total scanned quantity cannot exceed the ordered quantity.
 // @Tags mobile-orders
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Order ID"
-// @Success 200 {object} utilities.Response{data=models.OrderResponse}
+// @Param detailId path int true "Order detail ID"
+// @Param request body ScanOrderDetailRequest true "Scan order detail request"
+// @Success 200 {object} utilities.Response{data=ScanOrderDetailResponse}
 // @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Failure 404 {object} utilities.Response
-// @Router /api/mobile/orders/{id}/complete [put]
-func (moc *MobileOrderController) CompletePickingOrder(c *gin.Context) {
-	// Get order ID from URL parameter
+// @Router /api/mobile/orders/{id}/details/{detailId}/scan [put]
+func (moc *MobileOrderController) ScanOrderDetail(c *gin.Context) {
 	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID", err.Error())
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid order ID", err.Error())
+		return
+	}
+
+	detailID, err := strconv.ParseUint(c.Param("detailId"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid order detail ID", err.Error())
+		return
+	}
+
+	var req ScanOrderDetailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
 		return
 	}
 
 	// Get current user ID from context
 	userIDInterface, exists := c.Get("user_id")
 	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "User not found", "user ID not found in context")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeUserNotFound, "User not found", "user ID not found in context")
 		return
 	}
 
 	userID, ok := userIDInterface.(uint)
 	if !ok {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID", "user ID has invalid type")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidToken, "Invalid user ID", "user ID has invalid type")
 		return
 	}
 
-	// Start database transaction
-	tx := moc.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
+	response, err := moc.scanOrderDetail(c, userID, uint(orderID), uint(detailID), req)
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to record scan")
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, utilities.Messagef(c, "Scanned %d of %d for %s", response.ScannedQuantity, response.Quantity, response.Sku), response)
+}
 
+// scanOrderDetail records a scan of a product barcode against a line of an order the picker is
+// picking. Rejections are returned as StatusErrors.
+func (moc *MobileOrderController) scanOrderDetail(c *gin.Context, userID, orderID, detailID uint, req ScanOrderDetailRequest) (ScanOrderDetailResponse, error) {
 	var order models.Order
-	// Find order assigned to current picker with "picking process" processing status
-	if err := tx.Preload("OrderDetails").Where("id = ? AND picked_by = ? AND processing_status = ?", orderID, userID, "picking process").First(&order).Error; err != nil {
-		tx.Rollback()
-		if err == gorm.ErrRecordNotFound {
-			utilities.ErrorResponse(c, http.StatusNotFound, "Order not found or not in picking process", "order not found or not in picking process")
-		} else {
-			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to find order", err.Error())
+	var detail models.OrderDetail
+	err := utilities.WithTransaction(moc.DB.WithContext(c), func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND picked_by = ? AND processing_status = ?", orderID, userID, "picking process").First(&order).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found or not in picking process", "order not found or not in picking process")
+			}
+			return fmt.Errorf("failed to find order: %w", err)
 		}
-		return
+
+		// Lock the line so concurrent scans cannot push it over the ordered quantity
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND order_id = ?", detailID, order.ID).First(&detail).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderDetailNotFound, "Order detail not found", "no order detail found with the specified ID for this order")
+			}
+			return fmt.Errorf("failed to find order detail: %w", err)
+		}
+
+		// The scanned barcode must belong to the product of this line (SKU is accepted for products without barcode)
+		barcode := strings.TrimSpace(req.Barcode)
+		var product models.Product
+		if err := tx.Where("sku = ?", detail.Sku).First(&product).Error; err != nil {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeProductNotFound, "Product not found", fmt.Sprintf("no product found for SKU %s", detail.Sku))
+		}
+
+		if barcode != product.Barcode && !strings.EqualFold(barcode, product.Sku) {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeBarcodeMismatch, "Barcode mismatch", fmt.Sprintf("barcode %s does not match product %s", barcode, product.Sku))
+		}
+
+		if detail.ScannedQuantity+req.Quantity > detail.Quantity {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeScanQuantityExceeded, "Scanned quantity exceeds ordered quantity", fmt.Sprintf("line %s has %d of %d scanned, cannot add %d", detail.Sku, detail.ScannedQuantity, detail.Quantity, req.Quantity))
+		}
+
+		scan := models.OrderDetailScan{
+			OrderID:       order.ID,
+			OrderDetailID: detail.ID,
+			Barcode:       barcode,
+			Quantity:      req.Quantity,
+			ScannedBy:     userID,
+		}
+		if err := tx.Create(&scan).Error; err != nil {
+			return fmt.Errorf("failed to record scan: %w", err)
+		}
+
+		detail.ScannedQuantity += req.Quantity
+		if err := tx.Model(&detail).Update("scanned_quantity", detail.ScannedQuantity).Error; err != nil {
+			return fmt.Errorf("failed to update scanned quantity: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return ScanOrderDetailResponse{}, err
 	}
 
-	// Update order processing status and set picked_at timestamp
-	now := time.Now()
-	order.ProcessingStatus = "picking complete"
-	order.PickedAt = &now
+	return ScanOrderDetailResponse{
+		OrderID:         order.ID,
+		OrderDetailID:   detail.ID,
+		Sku:             detail.Sku,
+		Quantity:        detail.Quantity,
+		ScannedQuantity: detail.ScannedQuantity,
+		Remaining:       detail.Quantity - detail.ScannedQuantity,
+		Complete:        detail.ScannedQuantity >= detail.Quantity,
+	}, nil
+}
 
-	// Create PickedOrder record
-	pickedOrder := models.PickedOrder{
-		OrderID:  order.ID,
-		PickedBy: userID,
+// CompletePickingOrder godoc
+// @Summary Complete picking process by mobile
+// @Description Change order processing status from "picking process" to "picking complete" and create pick order records. Every order line must be fully scanned unless an override reason is given.
+// @Tags mobile-orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param request body CompletePickingOrderRequest false "Complete picking request"
+// @Success 200 {object} utilities.Response{data=models.OrderResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/mobile/orders/{id}/complete [put]
+func (moc *MobileOrderController) CompletePickingOrder(c *gin.Context) {
+	// Get order ID from URL parameter
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid order ID", err.Error())
+		return
 	}
 
-	if err := tx.Create(&pickedOrder).Error; err != nil {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create picked order record", err.Error())
+	// Get current user ID from context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeUserNotFound, "User not found", "user ID not found in context")
 		return
 	}
 
-	// Save the order changes
-	if err := tx.Save(&order).Error; err != nil {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to complete order", err.Error())
+	userID, ok := userIDInterface.(uint)
+	if !ok {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidToken, "Invalid user ID", "user ID has invalid type")
 		return
 	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction", err.Error())
+	// The body is optional, it only carries the override reason
+	var req CompletePickingOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+	order, err := moc.completePickingOrder(c, userID, uint(orderID), req)
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to complete picking order")
 		return
 	}
 
-	// Load order with details and picker for response
-	moc.DB.Preload("OrderDetails").
-		Preload("PickOperator").
-		Preload("AssignOperator").
-		Preload("PendingOperator").
-		Preload("ChangeOperator").
-		Preload("CancelOperator").
-		First(&order, order.ID)
+	// Load order with details, picker and products for response
+	if err := moc.Orders.Reload(c, &order, repositories.OrderOperators...); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload order", err.Error())
+		return
+	}
 
-	// Manually fetch and attach products to order details
-	for i := range order.OrderDetails {
-		var product models.Product
-		if err := moc.DB.Where("sku = ?", order.OrderDetails[i].Sku).First(&product).Error; err == nil {
-			order.OrderDetails[i].Product = &product
+	utilities.SuccessResponse(c, http.StatusOK, "Order picking completed successfully and pick order records created", order.ToOrderResponse())
+}
+
+// completePickingOrder moves an order the picker is picking to "picking complete" and records the
+// pick. Lines not fully scanned need an override reason. Rejections are returned as StatusErrors.
+func (moc *MobileOrderController) completePickingOrder(c *gin.Context, userID, orderID uint, req CompletePickingOrderRequest) (models.Order, error) {
+	req.OverrideReason = strings.TrimSpace(req.OverrideReason)
+
+	var order models.Order
+	err := utilities.WithRetryTransaction(moc.DB.WithContext(c), "complete-picking", func(tx *gorm.DB) error {
+		order = models.Order{}
+
+		// Find order assigned to current picker with "picking process" processing status, locked until commit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("OrderDetails").Where("id = ? AND picked_by = ? AND processing_status = ?", orderID, userID, "picking process").First(&order).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found or not in picking process", "order not found or not in picking process")
+			}
+			return fmt.Errorf("failed to find order: %w", err)
+		}
+
+		// Every line must be fully scanned unless the picker gives an override reason
+		var unscanned []string
+		for _, detail := range order.OrderDetails {
+			if detail.ScannedQuantity < detail.Quantity {
+				unscanned = append(unscanned, fmt.Sprintf("%s (%d/%d)", detail.Sku, detail.ScannedQuantity, detail.Quantity))
+			}
+		}
+
+		if len(unscanned) > 0 && req.OverrideReason == "" {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeOrderNotFullyScanned, "Order not fully scanned", "unscanned lines: "+strings.Join(unscanned, ", ")+"; provide override_reason to complete anyway")
+		}
+
+		// Update order processing status and set picked_at timestamp
+		now := time.Now()
+		order.ProcessingStatus = "picking complete"
+		order.PickedAt = &now
+
+		// Create PickedOrder record
+		pickedOrder := models.PickedOrder{
+			OrderID:  order.ID,
+			PickedBy: userID,
+		}
+		if len(unscanned) > 0 {
+			pickedOrder.OverrideReason = req.OverrideReason
+		}
+
+		if err := tx.Create(&pickedOrder).Error; err != nil {
+			return fmt.Errorf("failed to create picked order record: %w", err)
+		}
+
+		// Save the order changes
+		if err := tx.Save(&order).Error; err != nil {
+			return fmt.Errorf("failed to complete order: %w", err)
 		}
+
+		return nil
+	})
+	if err != nil {
+		return models.Order{}, err
 	}
 
-	utilities.SuccessResponse(c, http.StatusOK, "Order picking completed successfully and pick order records created", order.ToOrderResponse())
+	return order, nil
 }
 
 // PendingPickOrders godoc
 // @Summary Get orders pending pick assignment
-// @Description Pending order that already assigned to a picker, but not picked yet. Requires coordinator username and password.
+// @Description Pending order that already assigned to a picker, but not picked yet. Requires coordinator username and password. What was scanned of its lines is cleared.
 // @Tags mobile-orders
 // @Accept json
 // @Produce json
@@ -270,7 +547,11 @@ func (moc *MobileOrderController) CompletePickingOrder(c *gin.Context) {
 // @Failure 404 {object} utilities.Response
 // @Router /api/mobile/orders/{id}/pending-pick [put]
 func (moc *MobileOrderController) PendingPickOrders(c *gin.Context) {
-	orderID := c.Param("id")
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid order ID", err.Error())
+		return
+	}
 
 	var req PendingPickRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -278,17 +559,46 @@ func (moc *MobileOrderController) PendingPickOrders(c *gin.Context) {
 		return
 	}
 
+	// Get current user ID from context (pending operator)
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeUserNotFound, "User not found", "user ID not found in context")
+		return
+	}
+
+	userID, ok := userIDInterface.(uint)
+	if !ok {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidToken, "Invalid user ID", "user ID has invalid type")
+		return
+	}
+
+	order, err := moc.pendingPickOrder(c, userID, uint(orderID), req)
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to set order to pending pick")
+		return
+	}
+
+	// Reload order with all relationships
+	if err := moc.Orders.Reload(c, &order, repositories.OrderPickerRoles, repositories.OrderPending); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload order", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Order set to pending pick successfully", order.ToOrderResponse())
+}
+
+// pendingPickOrder puts an order in picking process back to "pending picking" on behalf of the
+// picker, approved with the credentials of a coordinator. Rejections are returned as StatusErrors.
+func (moc *MobileOrderController) pendingPickOrder(c *gin.Context, userID, orderID uint, req PendingPickRequest) (models.Order, error) {
 	// Verify coordinator credentials from request body
 	var coordinator models.User
-	if err := moc.DB.Preload("UserRoles.Role").Where("username = ?", req.Username).First(&coordinator).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Invalid coordinator credentials", "coordinator user not found")
-		return
+	if err := moc.DB.WithContext(c).Preload("UserRoles.Role").Where("username = ?", req.Username).First(&coordinator).Error; err != nil {
+		return models.Order{}, utilities.NewStatusErrorCode(http.StatusUnauthorized, utilities.ErrCodeCoordinatorCredentials, "Invalid coordinator credentials", "coordinator user not found")
 	}
 
 	// Check password
 	if !utilities.CheckPasswordHash(req.Password, coordinator.Password) {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Invalid coordinator credentials", "incorrect password")
-		return
+		return models.Order{}, utilities.NewStatusErrorCode(http.StatusUnauthorized, utilities.ErrCodeCoordinatorCredentials, "Invalid coordinator credentials", "incorrect password")
 	}
 
 	// Check if user has coordinator role
@@ -301,38 +611,21 @@ func (moc *MobileOrderController) PendingPickOrders(c *gin.Context) {
 	}
 
 	if !hasCoordinatorRole {
-		utilities.ErrorResponse(c, http.StatusForbidden, "Insufficient permissions", "user does not have coordinator role")
-		return
-	}
-
-	// Get current user ID from context (pending operator)
-	userIDInterface, exists := c.Get("user_id")
-	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "User not found", "user ID not found in context")
-		return
-	}
-
-	userID, ok := userIDInterface.(uint)
-	if !ok {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID", "user ID has invalid type")
-		return
+		return models.Order{}, utilities.NewStatusErrorCode(http.StatusForbidden, utilities.ErrCodeInsufficientAccess, "Insufficient permissions", "user does not have coordinator role")
 	}
 
 	// Find the order
 	var order models.Order
-	if err := moc.DB.First(&order, orderID).Error; err != nil {
+	if err := moc.DB.WithContext(c).First(&order, orderID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			utilities.ErrorResponse(c, http.StatusNotFound, "Order not found", "no order found with the specified ID")
-			return
+			return models.Order{}, utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
 		}
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to find order", err.Error())
-		return
+		return models.Order{}, fmt.Errorf("failed to find order: %w", err)
 	}
 
 	// Check if status order is "picking process"
 	if order.ProcessingStatus != "picking process" {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Order not in picking process", "only orders in 'picking process' status can be set to pending pick")
-		return
+		return models.Order{}, utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeOrderStateInvalid, "Order not in picking process", "only orders in 'picking process' status can be set to pending pick")
 	}
 
 	// Update order with pending pick details
@@ -344,36 +637,212 @@ func (moc *MobileOrderController) PendingPickOrders(c *gin.Context) {
 	order.AssignedBy = nil // Clear assigned_by since it's pending
 	order.AssignedAt = nil // Clear assigned_at since it's pending
 
-	if err := moc.DB.Save(&order).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to set order to pending pick", err.Error())
+	err := utilities.WithTransaction(moc.DB.WithContext(c), func(tx *gorm.DB) error {
+		if err := tx.Save(&order).Error; err != nil {
+			return err
+		}
+
+		// The next picker scans the order from the start
+		return models.ResetOrderScans(tx, order.ID)
+	})
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	return order, nil
+}
+
+// SyncMutations godoc
+// @Summary Submit mutations queued offline by mobile
+// @Description Apply a batch of mutations a picker queued while offline (scan, complete, pending_pick), in the order given. Every mutation carries a client generated UUID: a mutation already applied or rejected is not applied again and its recorded outcome is returned with duplicate set, so a batch can safely be resubmitted after a lost response. A failing mutation does not stop the batch; each item reports its own outcome (applied, rejected, failed on a server error and safe to resubmit, or processing when another submission is applying it) for the client to resolve conflicts.
+// @Tags mobile-orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MobileSyncRequest true "Mobile sync request"
+// @Success 200 {object} utilities.Response{data=MobileSyncResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Router /api/mobile/sync [post]
+func (moc *MobileOrderController) SyncMutations(c *gin.Context) {
+	var req MobileSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
 		return
 	}
 
-	// Reload order with all relationships
-	if err := moc.DB.
-		Preload("OrderDetails").
-		Preload("PickOperator.UserRoles.Role").
-		Preload("PickOperator.UserRoles.Assigner").
-		Preload("PendingOperator").
-		First(&order, order.ID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload order", err.Error())
+	// Get current user ID from context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeUserNotFound, "User not found", "user ID not found in context")
 		return
 	}
 
-	// Manually fetch and attach products to order details
-	for i := range order.OrderDetails {
-		var product models.Product
-		if err := moc.DB.Where("sku = ?", order.OrderDetails[i].Sku).First(&product).Error; err == nil {
-			order.OrderDetails[i].Product = &product
+	userID, ok := userIDInterface.(uint)
+	if !ok {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidToken, "Invalid user ID", "user ID has invalid type")
+		return
+	}
+
+	response := MobileSyncResponse{Results: make([]MobileSyncResult, len(req.Mutations))}
+	for i, mutation := range req.Mutations {
+		result := moc.syncMutation(c, userID, mutation)
+		result.Index = i
+		response.Results[i] = result
+
+		switch result.Status {
+		case models.SyncMutationApplied:
+			response.Summary.Applied++
+		case models.SyncMutationRejected:
+			response.Summary.Rejected++
+		default:
+			response.Summary.Failed++
+		}
+		if result.Duplicate {
+			response.Summary.Duplicates++
 		}
 	}
+	response.Summary.Total = len(req.Mutations)
 
-	utilities.SuccessResponse(c, http.StatusOK, "Order set to pending pick successfully", order.ToOrderResponse())
+	utilities.SuccessResponse(c, http.StatusOK, utilities.Messagef(c, "Synced %d mutation(s): %d applied, %d rejected, %d failed", response.Summary.Total, response.Summary.Applied, response.Summary.Rejected, response.Summary.Failed), response)
+}
+
+// syncMutation applies one queued mutation unless its client ID was seen before. The client ID is
+// claimed with a processing record first, so concurrent submissions of the same batch apply it once.
+func (moc *MobileOrderController) syncMutation(c *gin.Context, userID uint, mutation MobileSyncMutation) MobileSyncResult {
+	result := MobileSyncResult{ClientID: mutation.ClientID, Type: mutation.Type}
+
+	record := models.SyncMutation{
+		ClientID: mutation.ClientID,
+		UserID:   userID,
+		Type:     mutation.Type,
+		OrderID:  mutation.OrderID,
+		Status:   models.SyncMutationProcessing,
+		QueuedAt: mutation.QueuedAt,
+	}
+	claim := moc.DB.WithContext(c).Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+	if claim.Error != nil {
+		result.Status = "failed"
+		result.StatusCode = http.StatusInternalServerError
+		result.Code = utilities.ErrCodeInternal
+		result.Message = "Failed to record mutation"
+		result.Error = claim.Error.Error()
+		return result
+	}
+
+	if claim.RowsAffected == 0 {
+		var existing models.SyncMutation
+		if err := moc.DB.WithContext(c).Where("client_id = ? AND user_id = ?", mutation.ClientID, userID).First(&existing).Error; err != nil {
+			result.Status = "failed"
+			result.StatusCode = http.StatusInternalServerError
+			result.Code = utilities.ErrCodeInternal
+			result.Message = "Failed to load recorded mutation"
+			result.Error = err.Error()
+			return result
+		}
+
+		result.Duplicate = true
+		result.Status = existing.Status
+		result.StatusCode = existing.StatusCode
+		result.Message = existing.Message
+		result.Error = existing.Error
+		if existing.Result != "" {
+			result.Data = json.RawMessage(existing.Result)
+		}
+		if existing.Status == models.SyncMutationProcessing {
+			result.StatusCode = http.StatusConflict
+			result.Message = "Mutation is being applied by another submission"
+		}
+		if existing.Status != models.SyncMutationApplied {
+			result.Code = existing.Code
+			// Mutations still being applied, and rejections recorded before codes were stored, get
+			// the generic code of their status
+			if existing.Status == models.SyncMutationProcessing || result.Code == "" {
+				result.Code = utilities.GenericErrorCode(result.StatusCode)
+			}
+		}
+		return result
+	}
+
+	data, message, err := moc.applySyncMutation(c, userID, mutation)
+	if err != nil {
+		var statusErr *utilities.StatusError
+		if !errors.As(err, &statusErr) {
+			// Server side failure, release the claim so the client can resubmit
+			moc.DB.WithContext(c).Unscoped().Delete(&record)
+			result.Status = "failed"
+			result.StatusCode = http.StatusInternalServerError
+			result.Code = utilities.ErrCodeInternal
+			result.Message = "Failed to apply mutation"
+			result.Error = err.Error()
+			return result
+		}
+
+		record.Status = models.SyncMutationRejected
+		record.StatusCode = statusErr.Status
+		record.Code = statusErr.Code
+		record.Message = statusErr.Message
+		record.Error = statusErr.Detail
+	} else {
+		encoded, _ := json.Marshal(data)
+		record.Status = models.SyncMutationApplied
+		record.StatusCode = http.StatusOK
+		record.Message = message
+		record.Result = string(encoded)
+		result.Data = encoded
+	}
+
+	if err := moc.DB.WithContext(c).Save(&record).Error; err != nil {
+		log.Printf("⚠️ Failed to record outcome of sync mutation %s: %v", mutation.ClientID, err)
+	}
+
+	result.Status = record.Status
+	result.StatusCode = record.StatusCode
+	result.Message = record.Message
+	result.Error = record.Error
+	if record.Status == models.SyncMutationRejected {
+		result.Code = record.Code
+	}
+	return result
+}
+
+// applySyncMutation applies a queued mutation like its online endpoint would
+func (moc *MobileOrderController) applySyncMutation(c *gin.Context, userID uint, mutation MobileSyncMutation) (interface{}, string, error) {
+	switch mutation.Type {
+	case models.SyncMutationScan:
+		if mutation.DetailID == 0 || strings.TrimSpace(mutation.Barcode) == "" || mutation.Quantity < 1 {
+			return nil, "", utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeValidationFailed, "Validation failed", "scan mutations require detail_id, barcode and a quantity of at least 1")
+		}
+		response, err := moc.scanOrderDetail(c, userID, mutation.OrderID, mutation.DetailID, ScanOrderDetailRequest{Barcode: mutation.Barcode, Quantity: mutation.Quantity})
+		if err != nil {
+			return nil, "", err
+		}
+		return response, fmt.Sprintf("Scanned %d of %d for %s", response.ScannedQuantity, response.Quantity, response.Sku), nil
+
+	case models.SyncMutationComplete:
+		order, err := moc.completePickingOrder(c, userID, mutation.OrderID, CompletePickingOrderRequest{OverrideReason: mutation.OverrideReason})
+		if err != nil {
+			return nil, "", err
+		}
+		return order.ToOrderResponse(), "Order picking completed successfully", nil
+
+	case models.SyncMutationPendingPick:
+		if mutation.Username == "" || mutation.Password == "" {
+			return nil, "", utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeValidationFailed, "Validation failed", "pending_pick mutations require the coordinator username and password")
+		}
+		order, err := moc.pendingPickOrder(c, userID, mutation.OrderID, PendingPickRequest{Username: mutation.Username, Password: mutation.Password})
+		if err != nil {
+			return nil, "", err
+		}
+		return order.ToOrderResponse(), "Order set to pending pick successfully", nil
+	}
+
+	return nil, "", utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeUnknownOperation, "Unknown mutation type", "type must be scan, complete or pending_pick")
 }
 
 // BulkAssignPicker godoc
 // @Summary Bulk assign a picker to multiple orders by mobile
-// @Description Assign a picker to multiple orders by scanning tracking numbers, setting assigned_by to current user, assigned_at to now, picked_by to specified picker, and processing_status to "picking process"
+// @Description Assign a picker to multiple orders by scanning tracking numbers, setting assigned_by to current user, assigned_at to now, picked_by to specified picker, and processing_status to "picking process". Failed rows are kept as bulk failures for retrying.
 // @Tags mobile-orders
 // @Accept json
 // @Produce json
@@ -394,27 +863,59 @@ func (moc *MobileOrderController) BulkAssignPicker(c *gin.Context) {
 	// Get current user ID from context (assigner)
 	userIDInterface, exists := c.Get("user_id")
 	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "User not found", "user ID not found in context")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeUserNotFound, "User not found", "user ID not found in context")
 		return
 	}
 
 	userID, ok := userIDInterface.(uint)
 	if !ok {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID", "user ID has invalid type")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidToken, "Invalid user ID", "user ID has invalid type")
 		return
 	}
 
 	// Verify the picker exists
 	var picker models.User
-	if err := moc.DB.First(&picker, req.PickerID).Error; err != nil {
+	if err := moc.DB.WithContext(c).First(&picker, req.PickerID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			utilities.ErrorResponse(c, http.StatusNotFound, "Picker not found", "no user found with the specified picker ID")
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodePickerNotFound, "Picker not found", "no user found with the specified picker ID")
 			return
 		}
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to find picker", err.Error())
 		return
 	}
 
+	response := moc.bulkAssignPicker(c, userID, req)
+
+	// Keep failed rows for retrying once the response is gone
+	failures := make([]models.BulkFailure, 0, len(response.FailedOrders))
+	for _, failed := range response.FailedOrders {
+		payload := MobileBulkAssignPickerRequest{PickerID: req.PickerID, Trackings: []string{failed.Tracking}}
+		failures = append(failures, models.NewBulkFailure(models.BulkOperationPickerAssign, failed.Index, failed.Tracking, payload, failed.Error, userID))
+	}
+	models.RecordBulkFailures(moc.DB.WithContext(c), failures)
+
+	// Determine response status and message
+	statusCode := http.StatusOK
+	message := "Bulk picker assignment completed"
+
+	if len(response.AssignedOrders) == 0 {
+		if len(response.SkippedOrders) > 0 {
+			message = "All orders were skipped"
+		} else {
+			statusCode = http.StatusBadRequest
+			message = "No orders could be assigned"
+		}
+	} else if len(response.FailedOrders) > 0 || len(response.SkippedOrders) > 0 {
+		message = "Bulk picker assignment completed with some issues"
+	} else {
+		message = utilities.Messagef(c, "Successfully assigned %d order(s) to picker", len(response.AssignedOrders))
+	}
+
+	utilities.SuccessResponse(c, statusCode, message, response)
+}
+
+// bulkAssignPicker assigns the picker to the orders of a bulk request one by one, userID is the assigner
+func (moc *MobileOrderController) bulkAssignPicker(c *gin.Context, userID uint, req MobileBulkAssignPickerRequest) MobileBulkAssignPickerResponse {
 	var assignedOrders []models.Order
 	var skippedOrders []SkippedAssignment
 	var failedOrders []FailedAssignment
@@ -424,72 +925,69 @@ func (moc *MobileOrderController) BulkAssignPicker(c *gin.Context) {
 	// Process each tracking number
 	for i, tracking := range req.Trackings {
 		var order models.Order
+		skipReason := ""
+
+		// Each order is locked and assigned in its own transaction so one failure does not undo the others
+		err := utilities.WithTransaction(moc.DB.WithContext(c), func(tx *gorm.DB) error {
+			// Find order by tracking number
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("tracking = ?", tracking).First(&order).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					skipReason = "Order not found"
+					return nil
+				}
+				return err
+			}
 
-		// Find order by tracking number
-		if err := moc.DB.Where("tracking = ?", tracking).First(&order).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				skippedOrders = append(skippedOrders, SkippedAssignment{
-					Index:    i,
-					Tracking: tracking,
-					Reason:   "Order not found",
-				})
-			} else {
-				failedOrders = append(failedOrders, FailedAssignment{
-					Index:    i,
-					Tracking: tracking,
-					Error:    err.Error(),
-				})
+			// Validate order status
+			if order.EventStatus != nil && *order.EventStatus == "cancelled" {
+				skipReason = "Order is cancelled"
+				return nil
+			}
+			if order.MergedAway() {
+				skipReason = fmt.Sprintf("Order is merged into order %d", *order.MergedIntoID)
+				return nil
+			}
+			if order.IsHeld() {
+				skipReason = "Order is on hold: " + order.HoldReason
+				return nil
 			}
-			continue
-		}
 
-		// Validate order status
-		if order.EventStatus != nil && *order.EventStatus == "cancelled" {
-			skippedOrders = append(skippedOrders, SkippedAssignment{
-				Index:    i,
-				Tracking: tracking,
-				Reason:   "Order is cancelled",
-			})
-			continue
-		}
+			// Only allow assignment for "ready to pick" or "pending picking" status
+			if order.ProcessingStatus != "ready to pick" && order.ProcessingStatus != "pending picking" {
+				skipReason = fmt.Sprintf("Cannot assign order with status '%s'. Only 'ready to pick' or 'pending picking' orders can be assigned", order.ProcessingStatus)
+				return nil
+			}
 
-		// Only allow assignment for "ready to pick" or "pending picking" status
-		if order.ProcessingStatus != "ready to pick" && order.ProcessingStatus != "pending picking" {
-			skippedOrders = append(skippedOrders, SkippedAssignment{
+			// Update order with assignment details
+			order.AssignedBy = &userID
+			order.AssignedAt = &now
+			order.PickedBy = &req.PickerID
+			order.ProcessingStatus = "picking process"
+
+			return tx.Save(&order).Error
+		})
+		if err != nil {
+			failedOrders = append(failedOrders, FailedAssignment{
 				Index:    i,
 				Tracking: tracking,
-				Reason:   fmt.Sprintf("Cannot assign order with status '%s'. Only 'ready to pick' or 'pending picking' orders can be assigned", order.ProcessingStatus),
+				Error:    err.Error(),
 			})
 			continue
 		}
 
-		// Update order with assignment details
-		order.AssignedBy = &userID
-		order.AssignedAt = &now
-		order.PickedBy = &req.PickerID
-		order.ProcessingStatus = "picking process"
-
-		if err := moc.DB.Save(&order).Error; err != nil {
-			failedOrders = append(failedOrders, FailedAssignment{
+		if skipReason != "" {
+			skippedOrders = append(skippedOrders, SkippedAssignment{
 				Index:    i,
 				Tracking: tracking,
-				Error:    err.Error(),
+				Reason:   skipReason,
 			})
 			continue
 		}
 
-		// Load order with relationships
-		moc.DB.Preload("OrderDetails").
-			Preload("PickOperator").
-			Preload("AssignOperator").
-			First(&order, order.ID)
-
-		// Manually fetch and attach products
-		for j := range order.OrderDetails {
-			var product models.Product
-			if err := moc.DB.Where("sku = ?", order.OrderDetails[j].Sku).First(&product).Error; err == nil {
-				order.OrderDetails[j].Product = &product
-			}
+		// Load order with relationships and products. The order is assigned already, a failed
+		// reload only leaves it without them in the response.
+		if err := moc.Orders.Reload(c, &order, repositories.OrderAssigner); err != nil {
+			log.Printf("⚠️ Failed to reload assigned order %s: %v", tracking, err)
 		}
 
 		assignedOrders = append(assignedOrders, order)
@@ -501,7 +999,7 @@ func (moc *MobileOrderController) BulkAssignPicker(c *gin.Context) {
 		assignedOrderResponses[i] = order.ToOrderResponse()
 	}
 
-	response := MobileBulkAssignPickerResponse{
+	return MobileBulkAssignPickerResponse{
 		Summary: BulkAssignSummary{
 			Total:    len(req.Trackings),
 			Assigned: len(assignedOrders),
@@ -512,25 +1010,6 @@ func (moc *MobileOrderController) BulkAssignPicker(c *gin.Context) {
 		SkippedOrders:  skippedOrders,
 		FailedOrders:   failedOrders,
 	}
-
-	// Determine response status and message
-	statusCode := http.StatusOK
-	message := "Bulk picker assignment completed"
-
-	if len(assignedOrders) == 0 {
-		if len(skippedOrders) > 0 {
-			message = "All orders were skipped"
-		} else {
-			statusCode = http.StatusBadRequest
-			message = "No orders could be assigned"
-		}
-	} else if len(failedOrders) > 0 || len(skippedOrders) > 0 {
-		message = "Bulk picker assignment completed with some issues"
-	} else {
-		message = fmt.Sprintf("Successfully assigned %d order(s) to picker", len(assignedOrders))
-	}
-
-	utilities.SuccessResponse(c, statusCode, message, response)
 }
 
 // GetMobilePickedOrders godoc
@@ -543,7 +1022,12 @@ func (moc *MobileOrderController) BulkAssignPicker(c *gin.Context) {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Number of items per page" default(10)
 // @Param search query string false "Search term to filter by order ginee ID or tracking number"
+// @Param instant query bool false "Only instant courier orders (true) or only regular orders (false)"
+// @Param held query bool false "Only orders on hold (true) or only orders not on hold (false)"
+// @Param sort_by query string false "Sort keys, comma separated, each optionally suffixed with :asc or :desc (sent_before, created_at, assigned_at, instant, priority, buyer, tracking)" default(instant:desc,assigned_at:desc)
+// @Param sort_dir query string false "Direction of sort keys without a suffix: asc or desc" default(asc)
 // @Success 200 {object} utilities.Response{data=MobileOrdersListResponse}
+// @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Router /api/mobile/orders/picked-orders [get]
@@ -560,40 +1044,49 @@ func (moc *MobileOrderController) GetMobilePickedOrders(c *gin.Context) {
 	var total int64
 
 	// Build query with filters
-	query := moc.DB.Model(&models.Order{}).Where("processing_status = ?", "picking process")
+	query := moc.DB.WithContext(c).Model(&models.Order{}).Where("processing_status = ?", "picking process")
 
 	// Apply search filter if provided
 	if search != "" {
 		query = query.Where("order_ginee_id LIKE ? OR tracking LIKE ?", "%"+search+"%", "%"+search+"%")
 	}
 
+	query, err := filterInstant(c, query)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid instant filter", err.Error())
+		return
+	}
+
+	query, err = filterHeld(c, query)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid held filter", err.Error())
+		return
+	}
+
+	sortScope, err := utilities.ApplySort(c, models.PickedOrderSortKeys, "instant:desc,assigned_at:desc", "orders.id")
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid sort", err.Error())
+		return
+	}
+
 	// Get total count with all filters
 	if err := query.Count(&total).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count picked orders", err.Error())
 		return
 	}
 
-	// Get orders with pagination, filters, sorted by assigned_at descending
-	if err := query.Order("assigned_at DESC").Limit(limit).Offset(offset).
-		Preload("OrderDetails").
-		Preload("PickOperator").
-		Preload("AssignOperator").
-		Preload("PendingOperator").
-		Preload("ChangeOperator").
-		Preload("CancelOperator").
-		Find(&orders).Error; err != nil {
+	// Get orders with pagination, filters and the requested sort, by default instant courier orders
+	// first, then by assigned_at descending
+	if err := query.Scopes(sortScope, repositories.OrderWithDetails(repositories.OrderOperators...)).
+		Limit(limit).Offset(offset).Find(&orders).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve picked orders", err.Error())
 		return
 	}
 
-	// After loading orders, manually fetch and attach products
-	for i := range orders {
-		for j := range orders[i].OrderDetails {
-			var product models.Product
-			if err := moc.DB.Where("sku = ?", orders[i].OrderDetails[j].Sku).First(&product).Error; err == nil {
-				orders[i].OrderDetails[j].Product = &product
-			}
-		}
+	// After loading orders, attach products
+	if err := moc.Orders.AttachListProducts(c, orders); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve picked orders", err.Error())
+		return
 	}
 
 	// Convert to response format
@@ -602,7 +1095,7 @@ func (moc *MobileOrderController) GetMobilePickedOrders(c *gin.Context) {
 		orderResponses[i] = order.ToOrderResponse()
 	}
 
-	message := fmt.Sprintf("Found %d picked order(s)", len(orders))
+	message := utilities.Messagef(c, "Found %d picked order(s)", len(orders))
 
 	utilities.SuccessResponse(c, http.StatusOK, message, orderResponses)
 }
@@ -620,6 +1113,7 @@ type MobileOrderDetailResponse struct {
 	Courier          string                         `json:"courier"`
 	Tracking         string                         `json:"tracking"`
 	SentBefore       string                         `json:"sent_before"`
+	Instant          bool                           `json:"instant"`
 	PickedBy         string                         `json:"picked_by"`
 	PickedAt         string                         `json:"picked_at"`
 	PendingBy        string                         `json:"pending_by"`
@@ -645,6 +1139,7 @@ type MobileOrderListResponse struct {
 	Courier          string                         `json:"courier"`
 	Tracking         string                         `json:"tracking"`
 	SentBefore       string                         `json:"sent_before"`
+	Instant          bool                           `json:"instant"`
 	PickedBy         string                         `json:"picked_by"`
 	PickedAt         string                         `json:"picked_at"`
 	PendingBy        string                         `json:"pending_by"`
@@ -665,9 +1160,10 @@ type MobileOrdersListResponse struct {
 
 type MobileOrderDetailWithProduct struct {
 	models.OrderDetailResponse
-	Image    string `json:"image"`
-	Location string `json:"location"`
-	Barcode  string `json:"barcode"`
+	Image     string `json:"image"`
+	Thumbnail string `json:"thumbnail"`
+	Location  string `json:"location"`
+	Barcode   string `json:"barcode"`
 }
 
 type PendingPickRequest struct {
@@ -705,3 +1201,66 @@ type FailedAssignment struct {
 	Tracking string `json:"tracking"`
 	Error    string `json:"error"`
 }
+
+type ScanOrderDetailRequest struct {
+	Barcode  string `json:"barcode" binding:"required" example:"8999999000012"`
+	Quantity int    `json:"quantity" binding:"required,min=1" example:"1"`
+}
+
+type ScanOrderDetailResponse struct {
+	OrderID         uint   `json:"order_id"`
+	OrderDetailID   uint   `json:"order_detail_id"`
+	Sku             string `json:"sku"`
+	Quantity        int    `json:"quantity"`
+	ScannedQuantity int    `json:"scanned_quantity"`
+	Remaining       int    `json:"remaining"`
+	Complete        bool   `json:"complete"`
+}
+
+type CompletePickingOrderRequest struct {
+	OverrideReason string `json:"override_reason" example:"Barcode label damaged, verified manually"`
+}
+
+type MobileSyncRequest struct {
+	Mutations []MobileSyncMutation `json:"mutations" binding:"required,min=1,max=200,dive"`
+}
+
+// MobileSyncMutation is one mutation queued offline, with the fields of its online endpoint
+type MobileSyncMutation struct {
+	ClientID       string     `json:"client_id" binding:"required,uuid" example:"0b8f6c1e-3f5a-4c1e-9a57-2d6f0e4b7a11"`
+	Type           string     `json:"type" binding:"required,oneof=scan complete pending_pick" example:"scan"`
+	OrderID        uint       `json:"order_id" binding:"required" example:"42"`
+	DetailID       uint       `json:"detail_id" example:"101"`                         // scan
+	Barcode        string     `json:"barcode" example:"8999999000012"`                 // scan
+	Quantity       int        `json:"quantity" example:"1"`                            // scan
+	OverrideReason string     `json:"override_reason" example:"Barcode label damaged"` // complete
+	Username       string     `json:"username" example:"coordinator_user"`             // pending_pick, coordinator approving it
+	Password       string     `json:"password" example:"coordinator_password"`         // pending_pick
+	QueuedAt       *time.Time `json:"queued_at"`                                       // When the client queued it
+}
+
+type MobileSyncResponse struct {
+	Summary MobileSyncSummary  `json:"summary"`
+	Results []MobileSyncResult `json:"results"`
+}
+
+type MobileSyncSummary struct {
+	Total      int `json:"total"`
+	Applied    int `json:"applied"`
+	Rejected   int `json:"rejected"`
+	Failed     int `json:"failed"`
+	Duplicates int `json:"duplicates"` // Mutations submitted before, included in the counts above
+}
+
+type MobileSyncResult struct {
+	Index      int             `json:"index"`
+	ClientID   string          `json:"client_id"`
+	Type       string          `json:"type"`
+	Status     string          `json:"status" example:"applied"` // applied, rejected, failed or processing
+	Duplicate  bool            `json:"duplicate"`                // Outcome recorded for an earlier submission
+	StatusCode int             `json:"status_code" example:"200"`
+	Code       string          `json:"code,omitempty" example:"ORDER_STATE_INVALID"` // Error code of rejected and failed mutations
+	Message    string          `json:"message"`
+	Error      string          `json:"error,omitempty"`
+	Data       json.RawMessage `json:"data,omitempty" swaggertype:"object"`
+}
@@ -0,0 +1,252 @@
+package controllers
+
+import (
+	"fmt"
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MobileQcController struct {
+	DB *gorm.DB
+}
+
+// NewMobileQcController creates a new mobile QC controller
+func NewMobileQcController(db *gorm.DB) *MobileQcController {
+	return &MobileQcController{DB: db}
+}
+
+// VerifyQcItems godoc
+// @Summary Verify parcel contents at QC
+// @Description Confirm the quantity of every SKU packed for an order, each identified by its SKU or the product barcode scanned. SKUs of the order left out count as zero. Counts that differ from the order details are recorded as discrepancies: a surplus is moved to lost and found, a shortage raises a complain draft on the tracking (or is linked to the complain already raised). An order verified as matching cannot be verified again.
+// @Tags onlines
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body QcVerificationRequest true "Confirmed quantities"
+// @Success 201 {object} utilities.Response{data=models.QcVerificationResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/mobile/qc [post]
+func (mqc *MobileQcController) VerifyQcItems(c *gin.Context) {
+	var req QcVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+	req.Tracking = strings.TrimSpace(req.Tracking)
+
+	userID := c.GetUint("user_id")
+	username := c.GetString("username")
+
+	var verification models.QcVerification
+	err := utilities.WithTransaction(mqc.DB.WithContext(c), func(tx *gorm.DB) error {
+		var order models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("tracking = ?", req.Tracking).First(&order).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "No order found with the specified tracking number")
+			}
+			return err
+		}
+		if order.EventStatus != nil && *order.EventStatus == "cancelled" {
+			return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeOrderCancelled, "Order is cancelled", fmt.Sprintf("order %s is cancelled", order.OrderGineeID))
+		}
+		if order.MergedAway() {
+			return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeOrderStateInvalid, "Order already merged", fmt.Sprintf("order %s is merged into order %d", order.OrderGineeID, *order.MergedIntoID))
+		}
+		if order.IsHeld() {
+			return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeOrderHeld, "Order is on hold", fmt.Sprintf("order %s is on hold: %s", order.OrderGineeID, order.HoldReason))
+		}
+
+		var verified int64
+		if err := tx.Model(&models.QcVerification{}).Where("order_id = ? AND matched = ?", order.ID, true).Count(&verified).Error; err != nil {
+			return fmt.Errorf("failed to check previous verifications: %w", err)
+		}
+		if verified > 0 {
+			return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeQcAlreadyVerified, "QC already verified", fmt.Sprintf("the contents of %s already matched the order", order.Tracking))
+		}
+
+		if err := tx.Where("order_id = ?", order.ID).Order("id ASC").Find(&order.OrderDetails).Error; err != nil {
+			return fmt.Errorf("failed to load order details: %w", err)
+		}
+
+		confirmed := make(map[string]int, len(req.Items))
+		for _, item := range req.Items {
+			sku, err := resolveQcCode(tx, &order, item.Code)
+			if err != nil {
+				return err
+			}
+			confirmed[sku] += item.Quantity
+		}
+
+		discrepancies := models.CompareQcCounts(&order, confirmed)
+		verification = models.QcVerification{
+			Tracking:   order.Tracking,
+			OrderID:    order.ID,
+			VerifiedBy: userID,
+			Matched:    len(discrepancies) == 0,
+		}
+		if err := tx.Create(&verification).Error; err != nil {
+			return fmt.Errorf("failed to create QC verification: %w", err)
+		}
+
+		var complainID *uint
+		var shortages []models.QcDiscrepancy
+		for _, discrepancy := range discrepancies {
+			if discrepancy.Difference() < 0 {
+				shortages = append(shortages, discrepancy)
+			}
+		}
+		if len(shortages) > 0 {
+			id, err := draftQcComplain(tx, &order, shortages, userID, username)
+			if err != nil {
+				return err
+			}
+			complainID = id
+		}
+
+		for i := range discrepancies {
+			discrepancy := &discrepancies[i]
+			discrepancy.QcVerificationID = verification.ID
+			if discrepancy.Difference() > 0 {
+				lostFound := models.LostFound{
+					ProductSKU: discrepancy.Sku,
+					Quantity:   discrepancy.Difference(),
+					Reason:     fmt.Sprintf("QC surplus on tracking %s: %d packed, %d ordered", order.Tracking, discrepancy.ConfirmedQuantity, discrepancy.ExpectedQuantity),
+					CreatedBy:  &userID,
+				}
+				if err := tx.Create(&lostFound).Error; err != nil {
+					return fmt.Errorf("failed to create lost and found: %w", err)
+				}
+				discrepancy.LostFoundID = &lostFound.ID
+			} else {
+				discrepancy.ComplainID = complainID
+			}
+			if err := tx.Create(discrepancy).Error; err != nil {
+				return fmt.Errorf("failed to create QC discrepancy: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to verify QC")
+		return
+	}
+
+	mqc.DB.WithContext(c).Preload("Discrepancies", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Preload("Verifier").First(&verification, verification.ID)
+
+	message := "QC verified successfully"
+	if !verification.Matched {
+		message = "QC verified with discrepancies"
+	}
+	utilities.SuccessResponse(c, http.StatusCreated, message, verification.ToQcVerificationResponse())
+}
+
+// resolveQcCode returns the SKU of a scanned SKU or product barcode, preferring the lines of order
+func resolveQcCode(tx *gorm.DB, order *models.Order, code string) (string, error) {
+	code = strings.TrimSpace(code)
+	for _, detail := range order.OrderDetails {
+		if strings.EqualFold(detail.Sku, code) {
+			return detail.Sku, nil
+		}
+	}
+
+	var product models.Product
+	if err := tx.Where("sku = ? OR barcode = ?", code, code).First(&product).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeQcItemsInvalid, "Invalid QC items", fmt.Sprintf("%s is not a known SKU or barcode", code))
+		}
+		return "", fmt.Errorf("failed to find product: %w", err)
+	}
+	for _, detail := range order.OrderDetails {
+		if strings.EqualFold(detail.Sku, product.Sku) {
+			return detail.Sku, nil
+		}
+	}
+	return product.Sku, nil
+}
+
+// draftQcComplain raises a complain draft for the SKUs short in the parcel of order, with the picker
+// as the operator involved, and returns its ID. A complain already raised on the tracking is reused,
+// and no complain is drafted when the channel or store of the order is unknown.
+func draftQcComplain(tx *gorm.DB, order *models.Order, shortages []models.QcDiscrepancy, userID uint, username string) (*uint, error) {
+	var existing models.Complain
+	if err := tx.Where("tracking = ?", order.Tracking).First(&existing).Error; err == nil {
+		return &existing.ID, nil
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to find complain: %w", err)
+	}
+
+	var channel models.Channel
+	var store models.Store
+	if err := tx.Where("name = ?", order.Channel).First(&channel).Error; err != nil {
+		return nil, nil
+	}
+	if err := tx.Where("name = ?", order.Store).First(&store).Error; err != nil {
+		return nil, nil
+	}
+
+	missing := make([]string, len(shortages))
+	for i, shortage := range shortages {
+		missing[i] = fmt.Sprintf("%s %d of %d", shortage.Sku, shortage.ConfirmedQuantity, shortage.ExpectedQuantity)
+	}
+	complain := models.Complain{
+		Code:         utilities.GenerateComplainCode(tx, username),
+		Tracking:     order.Tracking,
+		OrderGineeID: order.OrderGineeID,
+		ChannelID:    channel.ID,
+		StoreID:      store.ID,
+		CreatedBy:    userID,
+		Description:  "Short at QC: " + strings.Join(missing, ", "),
+		Status:       models.ComplainStatusDraft,
+	}
+	if err := tx.Create(&complain).Error; err != nil {
+		return nil, fmt.Errorf("failed to create complain draft: %w", err)
+	}
+
+	for _, shortage := range shortages {
+		var product models.Product
+		if err := tx.Where("sku = ?", shortage.Sku).First(&product).Error; err == nil {
+			productDetail := models.ComplainProductDetail{
+				ComplainID: complain.ID,
+				ProductID:  product.ID,
+				Quantity:   -shortage.Difference(),
+			}
+			if err := tx.Create(&productDetail).Error; err != nil {
+				return nil, fmt.Errorf("failed to create product detail: %w", err)
+			}
+		}
+	}
+
+	if order.PickedBy != nil {
+		userDetail := models.ComplainUserDetail{
+			ComplainID: complain.ID,
+			OperatorID: *order.PickedBy,
+		}
+		userDetail.AddStage(models.ComplainStagePicking)
+		if err := tx.Create(&userDetail).Error; err != nil {
+			return nil, fmt.Errorf("failed to create user detail: %w", err)
+		}
+	}
+	return &complain.ID, nil
+}
+
+// Request/Response structs
+type QcVerificationItem struct {
+	Code     string `json:"code" binding:"required,sku" example:"LY-GLIPOW-128-HL705-30G"` // SKU or product barcode scanned
+	Quantity int    `json:"quantity" binding:"min=0" example:"2"`
+}
+
+type QcVerificationRequest struct {
+	Tracking string               `json:"tracking" binding:"required,tracking" example:"JNE1234567890"`
+	Items    []QcVerificationItem `json:"items" binding:"omitempty,dive"`
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"fmt"
 	"livo-backend/models"
 	"livo-backend/utilities"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type MobileReturnController struct {
@@ -30,7 +32,10 @@ func NewMobileReturnController(db *gorm.DB) *MobileReturnController {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param search query string false "Search by return mobile tracking (partial match)"
+// @Param sort_by query string false "Sort keys, comma separated, each optionally suffixed with :asc or :desc (created_at, updated_at, tracking, old_tracking, order_ginee_id)" default(created_at:desc)
+// @Param sort_dir query string false "Direction of sort keys without a suffix: asc or desc" default(asc)
 // @Success 200 {object} utilities.Response{data=MobileReturnsListResponse}
+// @Failure 400 {object} utilities.Response
 // @Router /api/mobile/returns [get]
 func (mrc *MobileReturnController) GetMobileReturns(c *gin.Context) {
 	// Parse pagination parameters
@@ -48,21 +53,27 @@ func (mrc *MobileReturnController) GetMobileReturns(c *gin.Context) {
 	oneWeekAgo := time.Now().AddDate(0, 0, -7)
 
 	// Build query with optional search and date filter
-	query := mrc.DB.Model(&models.Return{}).Where("created_at >= ?", oneWeekAgo)
+	query := mrc.DB.WithContext(c).Model(&models.Return{}).Where("created_at >= ?", oneWeekAgo)
 
 	if search != "" {
 		// Search by return mobile tracking with partial match
 		query = query.Where("new_tracking ILIKE ?", "%"+search+"%")
 	}
 
+	sortScope, err := utilities.ApplySort(c, models.ReturnSortKeys, "created_at:desc", "returns.id")
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid sort", err.Error())
+		return
+	}
+
 	// Get total count with search filter and date filter
 	if err := query.Count(&total).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count return", err.Error())
 		return
 	}
 
-	// Get returns with pagination, search filter, date filter, preload relationships, and order by ID descending
-	if err := query.Preload("Channel").Preload("Store").Order("id DESC").Limit(limit).Offset(offset).Find(&mobileReturns).Error; err != nil {
+	// Get returns with pagination, search filter, date filter, preload relationships, and sorting
+	if err := query.Preload("Channel").Preload("Store").Scopes(sortScope).Limit(limit).Offset(offset).Find(&mobileReturns).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch return mobiles", err.Error())
 		return
 	}
@@ -83,9 +94,9 @@ func (mrc *MobileReturnController) GetMobileReturns(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Return mobiles from the last 7 days retrieved successfully"
+	message := utilities.Messagef(c, "Return mobiles from the last 7 days retrieved successfully")
 	if search != "" {
-		message += " (filtered by tracking: " + search + ")"
+		message += utilities.Messagef(c, " (filtered by tracking: %s)", search)
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
@@ -106,8 +117,8 @@ func (mrc *MobileReturnController) GetMobileReturn(c *gin.Context) {
 	mobileReturnID := c.Param("id")
 
 	var mobileReturn models.Return
-	if err := mrc.DB.Preload("Channel").Preload("Store").First(&mobileReturn, mobileReturnID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Return not found", err.Error())
+	if err := mrc.DB.WithContext(c).Preload("Channel").Preload("Store").First(&mobileReturn, mobileReturnID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeReturnNotFound, "Return not found", "Failed to retrieve return")
 		return
 	}
 
@@ -142,13 +153,13 @@ func (mrc *MobileReturnController) CreateMobileReturn(c *gin.Context) {
 
 	// Check for duplicate tracking
 	var existingMobileReturn models.Return
-	if err := mrc.DB.Where("new_tracking = ?", req.Tracking).First(&existingMobileReturn).Error; err == nil {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Return mobile tracking already exists", "A return mobile with this tracking already exists")
+	if err := mrc.DB.WithContext(c).Where("new_tracking = ?", req.Tracking).First(&existingMobileReturn).Error; err == nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDuplicateTracking, "Return mobile tracking already exists", "A return mobile with this tracking already exists")
 		return
 	}
 
 	// Create a new return mobile and return the response
-	if err := mrc.DB.Create(&mobileReturn).Error; err != nil {
+	if err := mrc.DB.WithContext(c).Create(&mobileReturn).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create return mobile", err.Error())
 		return
 	}
@@ -156,6 +167,132 @@ func (mrc *MobileReturnController) CreateMobileReturn(c *gin.Context) {
 	utilities.SuccessResponse(c, http.StatusCreated, "Return mobile created successfully", mobileReturn.ToMobileReturnResponse())
 }
 
+// GetPutawayTasks godoc
+// @Summary Get putaway tasks
+// @Description Get putaway tasks of approved returns, oldest first, telling which location to place each returned item
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param status query string false "Task status (pending, completed)" default(pending)
+// @Param search query string false "Search by return tracking, product SKU or barcode (partial match)"
+// @Success 200 {object} utilities.Response{data=PutawayTasksListResponse}
+// @Failure 401 {object} utilities.Response
+// @Router /api/mobile/putaway-tasks [get]
+func (mrc *MobileReturnController) GetPutawayTasks(c *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	status := c.DefaultQuery("status", models.PutawayStatusPending)
+	search := c.Query("search")
+
+	query := mrc.DB.WithContext(c).Model(&models.PutawayTask{}).Where("putaway_tasks.status = ?", status)
+
+	if search != "" {
+		query = query.Joins("JOIN returns ON returns.id = putaway_tasks.return_id").
+			Joins("JOIN products ON products.id = putaway_tasks.product_id").
+			Where("returns.new_tracking ILIKE ? OR products.sku ILIKE ? OR products.barcode ILIKE ?", "%"+search+"%", "%"+search+"%", "%"+search+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count putaway tasks", err.Error())
+		return
+	}
+
+	var tasks []models.PutawayTask
+	if err := query.Preload("Return").Preload("Product").Preload("Completer").
+		Order("putaway_tasks.id ASC").Limit(limit).Offset(offset).Find(&tasks).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch putaway tasks", err.Error())
+		return
+	}
+
+	taskResponses := make([]models.PutawayTaskResponse, len(tasks))
+	for i, task := range tasks {
+		taskResponses[i] = task.ToPutawayTaskResponse()
+	}
+
+	response := PutawayTasksListResponse{
+		PutawayTasks: taskResponses,
+		Pagination: utilities.PaginationResponse{
+			Page:  page,
+			Limit: limit,
+			Total: int(total),
+		},
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Putaway tasks retrieved successfully", response)
+}
+
+// CompletePutawayTask godoc
+// @Summary Complete a putaway task
+// @Description Complete a putaway task with the location the items were placed at, scanned from the shelf label. Updates the product's location stats and sets the product location when it has none.
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Putaway Task ID"
+// @Param request body CompletePutawayTaskRequest true "Complete Putaway Task Request"
+// @Success 200 {object} utilities.Response{data=models.PutawayTaskResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/mobile/putaway-tasks/{id}/complete [put]
+func (mrc *MobileReturnController) CompletePutawayTask(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	taskID := c.Param("id")
+
+	var req CompletePutawayTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	req.Location = strings.TrimSpace(req.Location)
+	if req.Location == "" {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid location", "scanned location cannot be empty")
+		return
+	}
+
+	var task models.PutawayTask
+	err := utilities.WithTransaction(mrc.DB.WithContext(c), func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&task, taskID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodePutawayTaskNotFound, "Putaway task not found", "no putaway task found with the specified ID")
+			}
+			return fmt.Errorf("failed to load putaway task: %w", err)
+		}
+
+		if task.Status == models.PutawayStatusCompleted {
+			return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeAlreadyResolved, "Putaway task already completed", "the items of this task have already been put away")
+		}
+
+		now := time.Now()
+		task.Location = req.Location
+		task.Status = models.PutawayStatusCompleted
+		task.CompletedBy = &userID
+		task.CompletedAt = &now
+		if err := tx.Save(&task).Error; err != nil {
+			return fmt.Errorf("failed to complete putaway task: %w", err)
+		}
+
+		return models.RecordPutaway(tx, task.ProductID, task.Location, task.Quantity, now)
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to complete putaway task")
+		return
+	}
+
+	mrc.DB.WithContext(c).Preload("Return").Preload("Product").Preload("Completer").First(&task, task.ID)
+
+	utilities.SuccessResponse(c, http.StatusOK, "Putaway task completed successfully", task.ToPutawayTaskResponse())
+}
+
 // Request/Response structs
 type MobileReturnsListResponse struct {
 	MobileReturns []models.MobileReturnResponse `json:"mobile_returns"`
@@ -167,3 +304,12 @@ type CreateMobileReturnRequest struct {
 	ChannelID uint   `json:"channel_id" binding:"required"`
 	StoreID   uint   `json:"store_id" binding:"required"`
 }
+
+type PutawayTasksListResponse struct {
+	PutawayTasks []models.PutawayTaskResponse `json:"putaway_tasks"`
+	Pagination   utilities.PaginationResponse `json:"pagination"`
+}
+
+type CompletePutawayTaskRequest struct {
+	Location string `json:"location" binding:"required" example:"Rak A1-4"` // Scanned shelf location
+}
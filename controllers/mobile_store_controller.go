@@ -35,7 +35,7 @@ func (smc *MobileStoreController) GetMobileStores(c *gin.Context) {
 	var total int64
 
 	// Build query with optional search
-	query := smc.DB.Model(&models.Store{})
+	query := smc.DB.WithContext(c).Model(&models.Store{})
 
 	if search != "" {
 		// Search by store mobile tracking with partial match
@@ -66,9 +66,9 @@ func (smc *MobileStoreController) GetMobileStores(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Store mobiles retrieved successfully"
+	message := utilities.Messagef(c, "Store mobiles retrieved successfully")
 	if search != "" {
-		message += " (filtered by code or name: " + search + ")"
+		message += utilities.Messagef(c, " (filtered by code or name: %s)", search)
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
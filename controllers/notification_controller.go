@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type NotificationController struct {
+	DB *gorm.DB
+}
+
+// NewNotificationController creates a new notification controller
+func NewNotificationController(db *gorm.DB) *NotificationController {
+	return &NotificationController{DB: db}
+}
+
+// GetNotifications godoc
+// @Summary Get my notifications
+// @Description Get notifications for the logged-in user, newest first
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param unread query bool false "Only return unread notifications"
+// @Success 200 {object} utilities.Response{data=NotificationsListResponse}
+// @Failure 401 {object} utilities.Response
+// @Router /api/notifications [get]
+func (nc *NotificationController) GetNotifications(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	unread := c.Query("unread") == "true"
+
+	var notifications []models.Notification
+	var total int64
+	var unreadCount int64
+
+	query := nc.DB.WithContext(c).Model(&models.Notification{}).Where("user_id = ?", userID)
+	if unread {
+		query = query.Where("read_at IS NULL")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count notifications", err.Error())
+		return
+	}
+
+	if err := nc.DB.WithContext(c).Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&unreadCount).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count unread notifications", err.Error())
+		return
+	}
+
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&notifications).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve notifications", err.Error())
+		return
+	}
+
+	notificationResponses := make([]models.NotificationResponse, len(notifications))
+	for i, notification := range notifications {
+		notificationResponses[i] = notification.ToNotificationResponse()
+	}
+
+	response := NotificationsListResponse{
+		Notifications: notificationResponses,
+		UnreadCount:   int(unreadCount),
+		Pagination: utilities.PaginationResponse{
+			Page:  page,
+			Limit: limit,
+			Total: int(total),
+		},
+	}
+
+	// Build success message
+	message := utilities.Messagef(c, "Notifications retrieved successfully")
+	var filters []string
+
+	if unread {
+		filters = append(filters, "unread only")
+	}
+
+	if len(filters) > 0 {
+		message += utilities.Messagef(c, " (filtered by %s)", strings.Join(filters, " | "))
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, message, response)
+}
+
+// MarkNotificationRead godoc
+// @Summary Mark notification as read
+// @Description Mark one of the logged-in user's notifications as read
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Notification ID"
+// @Success 200 {object} utilities.Response{data=models.NotificationResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/notifications/{id}/read [put]
+func (nc *NotificationController) MarkNotificationRead(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	notificationID := c.Param("id")
+
+	var notification models.Notification
+	if err := nc.DB.WithContext(c).Where("user_id = ?", userID).First(&notification, notificationID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeNotificationNotFound, "Notification not found", err.Error())
+			return
+		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve notification", err.Error())
+		return
+	}
+
+	if notification.ReadAt == nil {
+		now := time.Now()
+		notification.ReadAt = &now
+		if err := nc.DB.WithContext(c).Save(&notification).Error; err != nil {
+			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update notification", err.Error())
+			return
+		}
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Notification marked as read", notification.ToNotificationResponse())
+}
+
+// Request/Response structs
+type NotificationsListResponse struct {
+	Notifications []models.NotificationResponse `json:"notifications"`
+	UnreadCount   int                           `json:"unread_count"`
+	Pagination    utilities.PaginationResponse  `json:"pagination"`
+}
@@ -1,13 +1,11 @@
 package controllers
 
 import (
-	"fmt"
 	"livo-backend/models"
 	"livo-backend/utilities"
 	"net/http"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -56,28 +54,28 @@ func (ofc *OnlineFlowController) GetOnlineFlows(c *gin.Context) {
 	var total int64
 
 	// Get tracking numbers primarily from mb_onlines
-	query := ofc.DB.Model(&models.QcOnline{}).Select("DISTINCT tracking").Where("tracking IS NOT NULL AND tracking != ''")
+	query := ofc.DB.WithContext(c).Model(&models.QcOnline{}).Select("DISTINCT tracking").Where("tracking IS NOT NULL AND tracking != ''")
 
 	// Apply date range filters if provided
 	if startDate != "" {
 		// Parse start date and set time to beginning of day
-		if parsedStartDate, err := time.Parse("2006-01-02", startDate); err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
+		if parsedStartDate, err := utilities.ParseDate(startDate); err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
 			return
 		} else {
-			startOfDay := parsedStartDate.Format("2006-01-02 00:00:00")
+			startOfDay, _ := utilities.DayRange(parsedStartDate)
 			query = query.Where("created_at >= ?", startOfDay)
 		}
 	}
 
 	if endDate != "" {
 		// Parse end date and set time to end of day
-		if parsedEndDate, err := time.Parse("2006-01-02", endDate); err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
+		if parsedEndDate, err := utilities.ParseDate(endDate); err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
 			return
 		} else {
 			// Add 24 hours to get the start of next day, then use < instead of <=
-			nextDay := parsedEndDate.AddDate(0, 0, 1).Format("2006-01-02 00:00:00")
+			_, nextDay := utilities.DayRange(parsedEndDate)
 			query = query.Where("created_at < ?", nextDay)
 		}
 	}
@@ -102,7 +100,7 @@ func (ofc *OnlineFlowController) GetOnlineFlows(c *gin.Context) {
 	// Build online flows for each tracking
 	var onlineFlows []OnlineFlowResponse
 	for _, tracking := range trackingNumbers {
-		flow := ofc.buildOnlineFlow(tracking)
+		flow := ofc.buildOnlineFlow(c, tracking)
 		onlineFlows = append(onlineFlows, flow)
 	}
 
@@ -116,7 +114,7 @@ func (ofc *OnlineFlowController) GetOnlineFlows(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Online flows retrieved successfully"
+	message := utilities.Messagef(c, "Online flows retrieved successfully")
 	var filters []string
 
 	if startDate != "" || endDate != "" {
@@ -135,7 +133,7 @@ func (ofc *OnlineFlowController) GetOnlineFlows(c *gin.Context) {
 	}
 
 	if len(filters) > 0 {
-		message += fmt.Sprintf(" (filtered by %s)", strings.Join(filters, " | "))
+		message += utilities.Messagef(c, " (filtered by %s)", strings.Join(filters, " | "))
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
@@ -143,7 +141,7 @@ func (ofc *OnlineFlowController) GetOnlineFlows(c *gin.Context) {
 
 // GetOnlineFlow godoc
 // @Summary Get online flow tracking
-// @Description Get the complete flow tracking through online process (qc-online -> outbound -> order).
+// @Description Get the complete flow tracking through online process (qc-online -> outbound -> order). Deprecated: use GET /api/orders/{id}/timeline, which also covers picking, returns and complains.
 // @Tags onlines
 // @Accept json
 // @Produce json
@@ -153,20 +151,21 @@ func (ofc *OnlineFlowController) GetOnlineFlows(c *gin.Context) {
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Failure 404 {object} utilities.Response
+// @Deprecated
 // @Router /api/onlines/online-flows/{tracking} [get]
 func (ofc *OnlineFlowController) GetOnlineFlow(c *gin.Context) {
 	tracking := c.Param("tracking")
 
 	if tracking == "" {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid tracking", "Tracking number is required")
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidTracking, "Invalid tracking", "Tracking number is required")
 		return
 	}
 
-	flow := ofc.buildOnlineFlow(tracking)
+	flow := ofc.buildOnlineFlow(c, tracking)
 
 	// CHANGED: Check if qc-online exists (since it's the primary source)
 	if flow.QcOnline == nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Tracking not found", "No qc-online record found for the specified tracking number")
+		utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeTrackingNotFound, "Tracking not found", "No qc-online record found for the specified tracking number")
 		return
 	}
 
@@ -174,31 +173,39 @@ func (ofc *OnlineFlowController) GetOnlineFlow(c *gin.Context) {
 }
 
 // Helper function to build online flow for a tracking number
-func (ofc *OnlineFlowController) buildOnlineFlow(tracking string) OnlineFlowResponse {
+func (ofc *OnlineFlowController) buildOnlineFlow(c *gin.Context, tracking string) OnlineFlowResponse {
 	var response OnlineFlowResponse
 	response.Tracking = tracking
 
 	// 1. Query QC Online (PRIMARY SOURCE)
-	var qcOnline models.QcOnline
-	if err := ofc.DB.Preload("QcOperator.UserRoles.Role").Preload("QcOperator.UserRoles.Assigner").Where("tracking = ?", tracking).First(&qcOnline).Error; err == nil {
-		var operator *OnlineOperatorFlowInfo
-		if qcOnline.QcOperator != nil {
-			operator = &OnlineOperatorFlowInfo{
-				ID:       qcOnline.QcOperator.ID,
-				Username: qcOnline.QcOperator.Username,
-				FullName: qcOnline.QcOperator.FullName,
+	// Multi-parcel orders have one QC per parcel, the first parcel stands for the whole QC
+	var qcOnlines []models.QcOnline
+	if err := ofc.DB.WithContext(c).Preload("QcOperator.UserRoles.Role").Preload("QcOperator.UserRoles.Assigner").Where("tracking = ?", tracking).Order("parcel_number ASC").Find(&qcOnlines).Error; err == nil && len(qcOnlines) > 0 {
+		info := &QcOnlineFlowInfo{Parcels: make([]QcOnlineParcelFlowInfo, len(qcOnlines))}
+		for i, qcOnline := range qcOnlines {
+			var operator *OnlineOperatorFlowInfo
+			if qcOnline.QcOperator != nil {
+				operator = &OnlineOperatorFlowInfo{
+					ID:       qcOnline.QcOperator.ID,
+					Username: qcOnline.QcOperator.Username,
+					FullName: qcOnline.QcOperator.FullName,
+				}
 			}
-		}
 
-		response.QcOnline = &QcOnlineFlowInfo{
-			Operator:  operator,
-			CreatedAt: qcOnline.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			info.Parcels[i] = QcOnlineParcelFlowInfo{
+				ParcelNumber: qcOnline.ParcelNumber,
+				Operator:     operator,
+				CreatedAt:    qcOnline.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			}
 		}
+		info.Operator = info.Parcels[0].Operator
+		info.CreatedAt = info.Parcels[0].CreatedAt
+		response.QcOnline = info
 	}
 
 	// 2. Query Outbound
 	var outbound models.Outbound
-	if err := ofc.DB.Preload("OutboundOperator.UserRoles.Role").Preload("OutboundOperator.UserRoles.Assigner").Where("tracking = ?", tracking).First(&outbound).Error; err == nil {
+	if err := ofc.DB.WithContext(c).Preload("OutboundOperator.UserRoles.Role").Preload("OutboundOperator.UserRoles.Assigner").Where("tracking = ?", tracking).First(&outbound).Error; err == nil {
 		var operator *OnlineOperatorFlowInfo
 		if outbound.OutboundOperator != nil {
 			operator = &OnlineOperatorFlowInfo{
@@ -218,7 +225,7 @@ func (ofc *OnlineFlowController) buildOnlineFlow(tracking string) OnlineFlowResp
 
 	// 3. Query Order (LAST)
 	var order models.Order
-	if err := ofc.DB.Preload("AssignOperator").
+	if err := ofc.DB.WithContext(c).Preload("AssignOperator").
 		Preload("PickOperator").
 		Preload("PendingOperator").
 		Preload("ChangeOperator").
@@ -229,6 +236,7 @@ func (ofc *OnlineFlowController) buildOnlineFlow(tracking string) OnlineFlowResp
 			ProcessingStatus: order.ProcessingStatus,
 			OrderGineeID:     order.OrderGineeID,
 			Complained:       order.Complained,
+			ParcelCount:      order.ParcelCount,
 			CreatedAt:        order.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		}
 
@@ -300,6 +308,21 @@ func (ofc *OnlineFlowController) buildOnlineFlow(tracking string) OnlineFlowResp
 		response.Order = &orderInfo
 	}
 
+	// QC is complete once every parcel the order declares is QC'd
+	if response.QcOnline != nil {
+		parcelCount := 1
+		if response.Order != nil {
+			parcelCount = response.Order.ParcelCount
+		}
+		parcels := make([]int, len(response.QcOnline.Parcels))
+		for i, parcel := range response.QcOnline.Parcels {
+			parcels[i] = parcel.ParcelNumber
+		}
+		progress := models.NewQcParcelProgress(parcelCount, parcels)
+		response.QcOnline.ParcelCount = progress.ParcelCount
+		response.QcOnline.Complete = progress.Complete
+	}
+
 	return response
 }
 
@@ -318,8 +341,17 @@ type OnlineFlowResponse struct {
 }
 
 type QcOnlineFlowInfo struct {
-	Operator  *OnlineOperatorFlowInfo `json:"operator,omitempty"`
-	CreatedAt string                  `json:"created_at"`
+	Operator    *OnlineOperatorFlowInfo  `json:"operator,omitempty"` // Operator of the first parcel
+	CreatedAt   string                   `json:"created_at"`
+	ParcelCount int                      `json:"parcel_count" example:"1"` // Parcels the order declares
+	Complete    bool                     `json:"complete"`                 // Every declared parcel is QC'd
+	Parcels     []QcOnlineParcelFlowInfo `json:"parcels"`
+}
+
+type QcOnlineParcelFlowInfo struct {
+	ParcelNumber int                     `json:"parcel_number" example:"1"`
+	Operator     *OnlineOperatorFlowInfo `json:"operator,omitempty"`
+	CreatedAt    string                  `json:"created_at"`
 }
 
 type OnlineOrderFlowInfo struct {
@@ -327,6 +359,7 @@ type OnlineOrderFlowInfo struct {
 	ProcessingStatus string                  `json:"processing_status"`
 	OrderGineeID     string                  `json:"order_ginee_id"`
 	Complained       bool                    `json:"complained"`
+	ParcelCount      int                     `json:"parcel_count"`
 	CreatedAt        string                  `json:"created_at"`
 	AssignedBy       *OnlineOperatorFlowInfo `json:"assigned_by,omitempty"`
 	AssignedAt       *string                 `json:"assigned_at,omitempty"`
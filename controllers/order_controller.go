@@ -1,8 +1,11 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"livo-backend/models"
+	"livo-backend/repositories"
 	"livo-backend/utilities"
 	"net/http"
 	"strconv"
@@ -11,15 +14,17 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderController struct {
-	DB *gorm.DB
+	DB     *gorm.DB
+	Orders *repositories.OrderRepository
 }
 
 // NewOrderController creates a new order controller
 func NewOrderController(db *gorm.DB) *OrderController {
-	return &OrderController{DB: db}
+	return &OrderController{DB: db, Orders: repositories.NewOrderRepository(db)}
 }
 
 // UpdateOrderComplainedStatus godoc
@@ -48,9 +53,9 @@ func (oc *OrderController) UpdateOrderComplainedStatus(c *gin.Context) {
 
 	// Find the order
 	var order models.Order
-	if err := oc.DB.First(&order, orderID).Error; err != nil {
+	if err := oc.DB.WithContext(c).First(&order, orderID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			utilities.ErrorResponse(c, http.StatusNotFound, "Order not found", "no order found with the specified ID")
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
 			return
 		}
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to find order", err.Error())
@@ -60,13 +65,13 @@ func (oc *OrderController) UpdateOrderComplainedStatus(c *gin.Context) {
 	// Update complained status
 	order.Complained = req.Complained
 
-	if err := oc.DB.Save(&order).Error; err != nil {
+	if err := oc.DB.WithContext(c).Save(&order).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update order complained status", err.Error())
 		return
 	}
 
 	// Load order with details for response
-	oc.DB.Preload("OrderDetails").Preload("PickOperator.UserRoles.Role").Preload("PickOperator.UserRoles.Assigner").First(&order, order.ID)
+	oc.DB.WithContext(c).Scopes(repositories.OrderWithDetails(repositories.OrderPickerRoles)).First(&order, order.ID)
 
 	message := "Order complained status updated successfully"
 	if req.Complained {
@@ -85,7 +90,7 @@ type UpdateComplainedStatusRequest struct {
 
 // GetOrders godoc
 // @Summary Get all orders
-// @Description Get list of all orders with optional date range filtering and search.
+// @Description Get list of orders visible to the user's roles with optional date range filtering and search. Pickers only see orders assigned to them, QC roles orders in the QC stages, outbound orders ready to be sent out, and admin roles all orders.
 // @Tags orders
 // @Accept json
 // @Produce json
@@ -95,6 +100,17 @@ type UpdateComplainedStatusRequest struct {
 // @Param start_date query string false "Start date (YYYY-MM-DD format)"
 // @Param end_date query string false "End date (YYYY-MM-DD format)"
 // @Param search query string false "Search by Order Ginee ID or Tracking number"
+// @Param channel query string false "Only orders of this channel (exact match)"
+// @Param store query string false "Only orders of this store (exact match)"
+// @Param instant query bool false "Only instant courier orders (true) or only regular orders (false)"
+// @Param priority query string false "Only orders with this priority" Enums(normal, urgent, late)
+// @Param at_risk query bool false "Only orders flagged as stuck in their processing status (true) or only orders that are not (false)"
+// @Param held query bool false "Only orders on hold (true) or only orders not on hold (false)"
+// @Param sort_by query string false "Sort keys, comma separated, each optionally suffixed with :asc or :desc (sent_before, created_at, priority, buyer, tracking, status). Priority puts late and urgent orders first. Defaults to the configured order list sort"
+// @Param sort_dir query string false "Direction of sort keys without a suffix: asc or desc" default(asc)
+// @Param include_deleted query bool false "Also list soft deleted orders (superadmin and admin only)"
+// @Param include query string false "Relations to load, comma separated (order_details, products, operators). Defaults to all"
+// @Param fields query string false "Order fields to return, comma separated (id is always returned)"
 // @Success 200 {object} utilities.Response{data=OrdersListResponse}
 // @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
@@ -116,14 +132,18 @@ func (oc *OrderController) GetOrders(c *gin.Context) {
 	var orders []models.Order
 	var total int64
 
-	// Build the query
-	query := oc.DB.Model(&models.Order{})
+	// Build the query, limited to the orders the user's roles may see
+	query := oc.DB.WithContext(c).Model(&models.Order{}).Scopes(models.VisibleOrders(c.GetUint("user_id"), c.GetStringSlice("roles")))
+	query, ok := includeDeletedOrders(c, query)
+	if !ok {
+		return
+	}
 
 	// Apply date range filters if provided
 	if startDate != "" {
 		// Parse start date and set time to beginning of day
 		if parsedStartDate, err := time.Parse("2006-01-02", startDate); err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
 			return
 		} else {
 			startOfDay := parsedStartDate.Format("2006-01-02 00:00:00")
@@ -134,7 +154,7 @@ func (oc *OrderController) GetOrders(c *gin.Context) {
 	if endDate != "" {
 		// Parse end date and set time to end of day
 		if parsedEndDate, err := time.Parse("2006-01-02", endDate); err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
 			return
 		} else {
 			// Add 24 hours to get the start of next day, then use < instead of <=
@@ -149,32 +169,66 @@ func (oc *OrderController) GetOrders(c *gin.Context) {
 		query = query.Where("order_ginee_id ILIKE ? OR tracking ILIKE ?", "%"+search+"%", "%"+search+"%")
 	}
 
+	// Apply channel and store filters if provided (EXACT MATCH)
+	if channel := c.Query("channel"); channel != "" {
+		query = query.Where("channel = ?", channel)
+	}
+	if store := c.Query("store"); store != "" {
+		query = query.Where("store = ?", store)
+	}
+
+	query, err := filterInstant(c, query)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid instant filter", err.Error())
+		return
+	}
+
+	query, err = filterAtRisk(c, query)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid at_risk filter", err.Error())
+		return
+	}
+
+	query, err = filterHeld(c, query)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid held filter", err.Error())
+		return
+	}
+
+	switch priority := c.Query("priority"); priority {
+	case "":
+	case models.OrderPriorityNormal, models.OrderPriorityUrgent, models.OrderPriorityLate:
+		query = query.Where("priority = ?", priority)
+	default:
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid priority filter", "priority must be normal, urgent or late")
+		return
+	}
+
+	sortScope, err := utilities.ApplySort(c, models.OrderSortKeys, models.CurrentSettings().OrderListDefaultSort, "orders.id")
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid sort", err.Error())
+		return
+	}
+
 	// Get total count with all filters
 	if err := query.Count(&total).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count orders", err.Error())
 		return
 	}
 
-	// Get orders with pagination, filters, sorted by ID descending
-	if err := query.Order("id DESC").Limit(limit).Offset(offset).
-		Preload("PickOperator").
-		Preload("PendingOperator").
-		Preload("ChangeOperator").
-		Preload("CancelOperator").
-		Preload("AssignOperator").
-		Preload("OrderDetails").
+	// Get orders with pagination, filters and the requested sort
+	serializer := utilities.NewSerializer(c)
+	if err := preloadOrderRelations(query, serializer).Scopes(sortScope).Limit(limit).Offset(offset).
 		Find(&orders).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve orders", err.Error())
 		return
 	}
 
-	// After loading orders, manually fetch and attach products
-	for i := range orders {
-		for j := range orders[i].OrderDetails {
-			var product models.Product
-			if err := oc.DB.Where("sku = ?", orders[i].OrderDetails[j].Sku).First(&product).Error; err == nil {
-				orders[i].OrderDetails[j].Product = &product
-			}
+	// After loading orders, attach products
+	if serializer.Includes("products") {
+		if err := oc.Orders.AttachListProducts(c, orders); err != nil {
+			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve orders", err.Error())
+			return
 		}
 	}
 
@@ -194,7 +248,7 @@ func (oc *OrderController) GetOrders(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Orders retrieved successfully"
+	message := utilities.Messagef(c, "Orders retrieved successfully")
 	var filters []string
 
 	if startDate != "" || endDate != "" {
@@ -213,20 +267,84 @@ func (oc *OrderController) GetOrders(c *gin.Context) {
 	}
 
 	if len(filters) > 0 {
-		message += fmt.Sprintf(" (filtered by %s)", strings.Join(filters, " | "))
+		message += utilities.Messagef(c, " (filtered by %s)", strings.Join(filters, " | "))
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, message, serializer.Serialize(response, "orders"))
+}
+
+// GetOrderStatusSummary godoc
+// @Summary Get order counts by status
+// @Description Count orders per processing status and per event status (orders without one count as "none"), and per combination of both, in one grouped query for the dashboard tiles. Orders on hold are counted too. Only orders the user's roles may see are counted.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string false "Only orders created on or after this day (YYYY-MM-DD format)"
+// @Param end_date query string false "Only orders created on or before this day (YYYY-MM-DD format)"
+// @Param channel query string false "Only orders of this channel (exact match)"
+// @Param store query string false "Only orders of this store (exact match)"
+// @Success 200 {object} utilities.Response{data=OrderStatusSummaryResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/orders/status-summary [get]
+func (oc *OrderController) GetOrderStatusSummary(c *gin.Context) {
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+
+	query := oc.DB.WithContext(c).Model(&models.Order{}).Scopes(models.VisibleOrders(c.GetUint("user_id"), c.GetStringSlice("roles")))
+
+	if startDate != "" {
+		parsedStartDate, err := utilities.ParseDate(startDate)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
+			return
+		}
+		start, _ := utilities.DayRange(parsedStartDate)
+		query = query.Where("orders.created_at >= ?", start)
+	}
+	if endDate != "" {
+		parsedEndDate, err := utilities.ParseDate(endDate)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
+			return
+		}
+		_, end := utilities.DayRange(parsedEndDate)
+		query = query.Where("orders.created_at < ?", end)
+	}
+
+	if channel := c.Query("channel"); channel != "" {
+		query = query.Where("orders.channel = ?", channel)
+	}
+	if store := c.Query("store"); store != "" {
+		query = query.Where("orders.store = ?", store)
 	}
 
-	utilities.SuccessResponse(c, http.StatusOK, message, response)
+	summary, err := models.BuildOrderStatusSummary(query)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count orders", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Order status summary retrieved successfully", OrderStatusSummaryResponse{
+		StartDate:          startDate,
+		EndDate:            endDate,
+		OrderStatusSummary: summary,
+	})
 }
 
 // GetOrder godoc
 // @Summary Get order by ID
-// @Description Get specific order information with complete details.
+// @Description Get specific order information with complete details. Orders outside the user's role visibility are reported as not found.
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Order ID"
+// @Param include_deleted query bool false "Also find a soft deleted order (superadmin and admin only)"
+// @Param include query string false "Relations to load, comma separated (order_details, products, operators). Defaults to all"
+// @Param fields query string false "Order fields to return, comma separated (id is always returned)"
 // @Success 200 {object} utilities.Response{data=models.OrderResponse}
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
@@ -236,36 +354,123 @@ func (oc *OrderController) GetOrder(c *gin.Context) {
 	orderID := c.Param("id")
 	var order models.Order
 
-	if err := oc.DB.
-		Preload("OrderDetails").
-		Preload("PickOperator").
-		Preload("PendingOperator").
-		Preload("ChangeOperator").
-		Preload("CancelOperator").
-		Preload("AssignOperator").
+	query, ok := includeDeletedOrders(c, oc.DB.WithContext(c).Scopes(models.VisibleOrders(c.GetUint("user_id"), c.GetStringSlice("roles"))))
+	if !ok {
+		return
+	}
+
+	serializer := utilities.NewSerializer(c)
+	if err := preloadOrderRelations(query, serializer).
 		First(&order, orderID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			utilities.ErrorResponse(c, http.StatusNotFound, "Order not found", "no order found with the specified ID")
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
 			return
 		}
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order", err.Error())
 		return
 	}
 
-	// Manually fetch and attach products
-	for i := range order.OrderDetails {
-		var product models.Product
-		if err := oc.DB.Where("sku = ?", order.OrderDetails[i].Sku).First(&product).Error; err == nil {
-			order.OrderDetails[i].Product = &product
+	// Attach products
+	if serializer.Includes("products") {
+		if err := oc.Orders.AttachProducts(c, &order); err != nil {
+			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order", err.Error())
+			return
+		}
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Order retrieved successfully", serializer.Serialize(order.ToOrderResponse(), ""))
+}
+
+// filterInstant narrows query to instant courier orders, or to the regular ones, when the instant
+// query parameter is set
+func filterInstant(c *gin.Context, query *gorm.DB) (*gorm.DB, error) {
+	value := c.Query("instant")
+	if value == "" {
+		return query, nil
+	}
+
+	instant, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("instant must be true or false")
+	}
+
+	return query.Where("instant = ?", instant), nil
+}
+
+// filterAtRisk applies the at_risk query parameter: true lists only orders flagged as stuck, false
+// only orders that are not
+func filterAtRisk(c *gin.Context, query *gorm.DB) (*gorm.DB, error) {
+	value := c.Query("at_risk")
+	if value == "" {
+		return query, nil
+	}
+
+	atRisk, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("at_risk must be true or false")
+	}
+
+	if atRisk {
+		return query.Where("at_risk_at IS NOT NULL"), nil
+	}
+	return query.Where("at_risk_at IS NULL"), nil
+}
+
+// filterHeld applies the held query parameter: true lists only orders on hold, false only the
+// others
+func filterHeld(c *gin.Context, query *gorm.DB) (*gorm.DB, error) {
+	value := c.Query("held")
+	if value == "" {
+		return query, nil
+	}
+
+	held, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("held must be true or false")
+	}
+
+	if held {
+		return query.Where("held_at IS NOT NULL"), nil
+	}
+	return query.Where("held_at IS NULL"), nil
+}
+
+// includeDeletedOrders applies the include_deleted query parameter, letting superadmins and admins
+// see soft deleted orders. Writes the error response and returns false when it is not allowed.
+func includeDeletedOrders(c *gin.Context, query *gorm.DB) (*gorm.DB, bool) {
+	value := c.Query("include_deleted")
+	if value == "" {
+		return query, true
+	}
+
+	include, err := strconv.ParseBool(value)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid include_deleted filter", "include_deleted must be true or false")
+		return nil, false
+	}
+	if !include {
+		return query, true
+	}
+
+	for _, role := range c.GetStringSlice("roles") {
+		if role == "superadmin" || role == "admin" {
+			return query.Unscoped(), true
 		}
 	}
+	utilities.ErrorResponseCode(c, http.StatusForbidden, utilities.ErrCodeInsufficientAccess, "Insufficient permissions", "only superadmin and admin may list deleted orders")
+	return nil, false
+}
 
-	utilities.SuccessResponse(c, http.StatusOK, "Order retrieved successfully", order.ToOrderResponse())
+// preloadOrderRelations preloads the order relations selected with include=
+func preloadOrderRelations(query *gorm.DB, serializer *utilities.Serializer) *gorm.DB {
+	query = serializer.Preload(query, "order_details", "OrderDetails")
+	query = serializer.Preload(query, "operators", "PickOperator", "PendingOperator", "ChangeOperator", "CancelOperator", "AssignOperator")
+	return query
 }
 
 // BulkCreateOrders godoc
 // @Summary Bulk create orders
-// @Description Create multiple orders at once, skipping duplicates.
+// @Description Create multiple orders at once, skipping duplicates. Failed rows are kept as bulk failures for retrying.
 // @Tags orders
 // @Accept json
 // @Produce json
@@ -283,23 +488,42 @@ func (oc *OrderController) BulkCreateOrders(c *gin.Context) {
 		return
 	}
 
+	response := oc.bulkCreateOrders(c, req)
+
+	// Keep failed rows for retrying once the response is gone
+	failures := make([]models.BulkFailure, 0, len(response.FailedOrders))
+	for _, failed := range response.FailedOrders {
+		payload := BulkCreateOrderRequest{Orders: []CreateOrderRequest{req.Orders[failed.Index]}}
+		failures = append(failures, models.NewBulkFailure(models.BulkOperationOrderCreate, failed.Index, failed.OrderGineeID, payload, failed.Error, c.GetUint("user_id")))
+	}
+	models.RecordBulkFailures(oc.DB.WithContext(c), failures)
+
+	// Determine response status
+	statusCode := http.StatusCreated
+	message := "Bulk order creation completed"
+
+	if len(response.CreatedOrders) == 0 {
+		if len(response.SkippedOrders) > 0 {
+			statusCode = http.StatusOK
+			message = "All orders were skipped (already exist)"
+		} else {
+			statusCode = http.StatusBadRequest
+			message = "No orders could be created"
+		}
+	} else if len(response.FailedOrders) > 0 || len(response.SkippedOrders) > 0 {
+		message = "Bulk order creation completed with some issues"
+	}
+
+	utilities.SuccessResponse(c, statusCode, message, response)
+}
+
+// bulkCreateOrders creates the orders of a bulk request one by one, skipping existing ones
+func (oc *OrderController) bulkCreateOrders(c *gin.Context, req BulkCreateOrderRequest) BulkCreateOrderResponse {
 	var createdOrders []models.Order
 	var skippedOrders []SkippedOrder
 	var failedOrders []FailedOrder
 
 	for i, orderReq := range req.Orders {
-		// Check if order with same OrderGineeID already exists
-		var existingOrder models.Order
-		if err := oc.DB.Where("order_ginee_id = ?", orderReq.OrderGineeID).First(&existingOrder).Error; err == nil {
-			// Order exists, skip it
-			skippedOrders = append(skippedOrders, SkippedOrder{
-				Index:        i,
-				OrderGineeID: orderReq.OrderGineeID,
-				Reason:       "Order already exists",
-			})
-			continue
-		}
-
 		// Create order
 		order := models.Order{
 			OrderGineeID:     orderReq.OrderGineeID,
@@ -307,6 +531,7 @@ func (oc *OrderController) BulkCreateOrders(c *gin.Context) {
 			Channel:          orderReq.Channel,
 			Store:            orderReq.Store,
 			Buyer:            orderReq.Buyer,
+			BuyerPhone:       orderReq.BuyerPhone,
 			Address:          orderReq.Address,
 			Courier:          orderReq.Courier,
 			Tracking:         orderReq.Tracking,
@@ -334,8 +559,15 @@ func (oc *OrderController) BulkCreateOrders(c *gin.Context) {
 			order.OrderDetails = append(order.OrderDetails, orderDetail)
 		}
 
-		// Try to create the order
-		if err := oc.DB.Create(&order).Error; err != nil {
+		// Try to create the order, skipping it when an order with the same OrderGineeID already exists
+		if err := models.CreateIntakeOrder(oc.DB.WithContext(c), &order); errors.Is(err, models.ErrOrderExists) {
+			skippedOrders = append(skippedOrders, SkippedOrder{
+				Index:        i,
+				OrderGineeID: orderReq.OrderGineeID,
+				Reason:       "Order already exists",
+			})
+			continue
+		} else if err != nil {
 			// Failed to create order
 			failedOrders = append(failedOrders, FailedOrder{
 				Index:        i,
@@ -346,7 +578,7 @@ func (oc *OrderController) BulkCreateOrders(c *gin.Context) {
 		}
 
 		// Load order with details for response
-		oc.DB.Preload("OrderDetails").Preload("PickOperator").First(&order, order.ID)
+		oc.DB.WithContext(c).Scopes(repositories.OrderWithDetails()).First(&order, order.ID)
 		createdOrders = append(createdOrders, order)
 	}
 
@@ -356,7 +588,7 @@ func (oc *OrderController) BulkCreateOrders(c *gin.Context) {
 		createdOrderResponses[i] = order.ToOrderResponse()
 	}
 
-	response := BulkCreateOrderResponse{
+	return BulkCreateOrderResponse{
 		Summary: BulkCreateSummary{
 			Total:   len(req.Orders),
 			Created: len(createdOrders),
@@ -367,29 +599,11 @@ func (oc *OrderController) BulkCreateOrders(c *gin.Context) {
 		SkippedOrders: skippedOrders,
 		FailedOrders:  failedOrders,
 	}
-
-	// Determine response status
-	statusCode := http.StatusCreated
-	message := "Bulk order creation completed"
-
-	if len(createdOrders) == 0 {
-		if len(skippedOrders) > 0 {
-			statusCode = http.StatusOK
-			message = "All orders were skipped (already exist)"
-		} else {
-			statusCode = http.StatusBadRequest
-			message = "No orders could be created"
-		}
-	} else if len(failedOrders) > 0 || len(skippedOrders) > 0 {
-		message = "Bulk order creation completed with some issues"
-	}
-
-	utilities.SuccessResponse(c, statusCode, message, response)
 }
 
 // UpdateOrder godoc
 // @Summary Update order and order details
-// @Description Update order information and manage order details (add, update, remove products)
+// @Description Update order information and manage order details (add, update, remove products). All fields and the full list of order details are required: details left out of the list are removed. Use PATCH to change only some fields or details. A changed address is validated with the configured geocoder, which fills in province, city and postal code. The changed fields are returned in changes and recorded in the order history. A tracking referenced by QC, outbound, return or complain records can only be changed with the renumber trackings endpoint.
 // @Tags orders
 // @Accept json
 // @Produce json
@@ -401,374 +615,639 @@ func (oc *OrderController) BulkCreateOrders(c *gin.Context) {
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
 // @Router /api/orders/{id} [put]
 func (oc *OrderController) UpdateOrder(c *gin.Context) {
-	orderID := c.Param("id")
-
 	var req UpdateOrderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utilities.ValidationErrorResponse(c, err)
 		return
 	}
 
-	// Get current user ID from context
-	userIDInterface, exists := c.Get("user_id")
-	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "User not found", "user ID not found in context")
-		return
-	}
-
-	userID, ok := userIDInterface.(uint)
-	if !ok {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID", "user ID has invalid type")
-		return
-	}
-
-	// Find the order
-	var order models.Order
-	if err := oc.DB.Preload("OrderDetails").First(&order, orderID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			utilities.ErrorResponse(c, http.StatusNotFound, "Order not found", "no order found with the specified ID")
-			return
-		}
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to find order", err.Error())
-		return
-	}
-
-	// Check if order status allows modification
-	if order.ProcessingStatus == "picking process" || order.ProcessingStatus == "qc process" {
-		utilities.ErrorResponse(c, http.StatusForbidden, "Order modification not allowed", fmt.Sprintf("cannot modify order when processing status is '%s'.", order.ProcessingStatus))
-		return
-	}
-
-	// Check if order is cancelled
-	if order.EventStatus != nil && *order.EventStatus == "cancelled" {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Order already cancelled", "this order has already been cancelled")
-		return
+	edit := orderEdit{
+		Channel:    &req.Channel,
+		Store:      &req.Store,
+		Buyer:      &req.Buyer,
+		BuyerPhone: &req.BuyerPhone,
+		Address:    &req.Address,
+		Courier:    &req.Courier,
+		Tracking:   &req.Tracking,
 	}
-
-	// Update basic order fields
-	order.ChangedBy = &userID
-	eventStatus := "changed"
-	order.EventStatus = &eventStatus
-	order.Channel = req.Channel
-	order.Store = req.Store
-	order.Buyer = req.Buyer
-	order.Address = req.Address
-	order.Courier = req.Courier
-	order.Tracking = req.Tracking
-
 	if req.SentBefore != "" {
-		if parsedTime, err := time.Parse("2006-01-02 15:04:05", req.SentBefore); err == nil {
-			order.SentBefore = parsedTime
-		}
-	}
-
-	// Set changed_by and changed_at
-	now := time.Now()
-	order.ChangedBy = &userID
-	order.ChangedAt = &now
-
-	// Begin transaction
-	tx := oc.DB.Begin()
-
-	// Save order changes
-	if err := tx.Save(&order).Error; err != nil {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update order", err.Error())
-		return
+		edit.SentBefore = &req.SentBefore
 	}
 
-	// Process order details updates
-	// Create a map of existing order details by ID for quick lookup
-	existingDetailsMap := make(map[uint]models.OrderDetail)
-	for _, detail := range order.OrderDetails {
-		existingDetailsMap[detail.ID] = detail
-	}
+	oc.updateOrder(c, req.Version, edit, func(tx *gorm.DB, order *models.Order) error {
+		// Create a map of existing order details by ID for quick lookup
+		existingDetailsMap := make(map[uint]models.OrderDetail)
+		for _, detail := range order.OrderDetails {
+			existingDetailsMap[detail.ID] = detail
+		}
 
-	// Track which existing details are still in the update
-	updatedDetailIDs := make(map[uint]bool)
+		// Track which existing details are still in the update
+		updatedDetailIDs := make(map[uint]bool)
+
+		// Process each detail in the request
+		for _, detailReq := range req.OrderDetails {
+			if detailReq.ID == 0 {
+				// New product - create new order detail
+				newDetail := models.OrderDetail{
+					OrderID:     order.ID,
+					Sku:         detailReq.Sku,
+					ProductName: detailReq.ProductName,
+					Variant:     detailReq.Variant,
+					Quantity:    detailReq.Quantity,
+					Price:       detailReq.Price,
+				}
+				if err := tx.Create(&newDetail).Error; err != nil {
+					return fmt.Errorf("failed to add new order detail: %w", err)
+				}
+			} else {
+				// Update existing product
+				existingDetail, exists := existingDetailsMap[detailReq.ID]
+				if !exists {
+					return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderDetailNotFound, "Order detail not found", fmt.Sprintf("order detail with ID %d not found for this order", detailReq.ID))
+				}
 
-	// Process each detail in the request
-	for _, detailReq := range req.OrderDetails {
-		if detailReq.ID == 0 {
-			// New product - create new order detail
-			newDetail := models.OrderDetail{
-				OrderID:     order.ID,
-				Sku:         detailReq.Sku,
-				ProductName: detailReq.ProductName,
-				Variant:     detailReq.Variant,
-				Quantity:    detailReq.Quantity,
-				Price:       detailReq.Price,
-			}
-			if err := tx.Create(&newDetail).Error; err != nil {
-				tx.Rollback()
-				utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to add new order detail", err.Error())
-				return
-			}
-		} else {
-			// Update existing product
-			if existingDetail, exists := existingDetailsMap[detailReq.ID]; exists {
 				existingDetail.Sku = detailReq.Sku
 				existingDetail.ProductName = detailReq.ProductName
 				existingDetail.Variant = detailReq.Variant
 				existingDetail.Quantity = detailReq.Quantity
 				existingDetail.Price = detailReq.Price
+				existingDetail.ScannedQuantity = 0 // Scans of the line before the change no longer count
 
 				if err := tx.Save(&existingDetail).Error; err != nil {
-					tx.Rollback()
-					utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update order detail", err.Error())
-					return
+					return fmt.Errorf("failed to update order detail: %w", err)
 				}
 				updatedDetailIDs[detailReq.ID] = true
-			} else {
-				tx.Rollback()
-				utilities.ErrorResponse(c, http.StatusNotFound, "Order detail not found", fmt.Sprintf("order detail with ID %d not found for this order", detailReq.ID))
-				return
 			}
 		}
-	}
 
-	// Remove products that are not in the update request
-	for detailID := range existingDetailsMap {
-		if !updatedDetailIDs[detailID] {
-			// This detail was not in the update request, so delete it
-			if err := tx.Delete(&models.OrderDetail{}, detailID).Error; err != nil {
-				tx.Rollback()
-				utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to remove order detail", err.Error())
-				return
+		// Remove products that are not in the update request
+		for detailID := range existingDetailsMap {
+			if !updatedDetailIDs[detailID] {
+				if err := tx.Delete(&models.OrderDetail{}, detailID).Error; err != nil {
+					return fmt.Errorf("failed to remove order detail: %w", err)
+				}
 			}
 		}
-	}
-
-	// Verify at least one order detail remains
-	var detailCount int64
-	if err := tx.Model(&models.OrderDetail{}).Where("order_id = ?", order.ID).Count(&detailCount).Error; err != nil {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count order details", err.Error())
-		return
-	}
-
-	if detailCount == 0 {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid update", "order must have at least one order detail")
-		return
-	}
-
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction", err.Error())
-		return
-	}
-
-	// Reload order with all relationships
-	if err := oc.DB.
-		Preload("OrderDetails").
-		Preload("PickOperator.UserRoles.Role").
-		Preload("PickOperator.UserRoles.Assigner").
-		Preload("ChangeOperator").
-		First(&order, order.ID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload order", err.Error())
-		return
-	}
-
-	// Manually fetch and attach products to order details
-	for i := range order.OrderDetails {
-		var product models.Product
-		if err := oc.DB.Where("sku = ?", order.OrderDetails[i].Sku).First(&product).Error; err == nil {
-			order.OrderDetails[i].Product = &product
-		}
-	}
 
-	utilities.SuccessResponse(c, http.StatusOK, "Order updated successfully", order.ToOrderResponse())
+		return nil
+	})
 }
 
-// DuplicateOrder godoc
-// @Summary Duplicate an order
-// @Description Duplicate an existing order with all its details. The new order will have "X-" prefix added to tracking and the original order will have "-X2" suffix added to order_ginee_id
+// PatchOrder godoc
+// @Summary Partially update an order
+// @Description Change only the order fields sent, leaving the others as they are. Order details are changed explicitly: add_details are added, update_details change the sent fields of existing details and remove_details (detail IDs) are removed, details not mentioned stay untouched. The same rules as for a full update apply: orders in picking or QC process and cancelled orders cannot be changed, at least one detail must remain, a changed address is validated with the configured geocoder and a tracking referenced by QC, outbound, return or complain records can only be changed with the renumber trackings endpoint. The changed fields are returned in changes and recorded in the order history.
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param id path int true "Order ID to duplicate"
-// @Success 201 {object} utilities.Response{data=DuplicateOrderResponse}
+// @Param id path int true "Order ID"
+// @Param request body PatchOrderRequest true "Patch order request"
+// @Success 200 {object} utilities.Response{data=models.OrderResponse}
 // @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Failure 404 {object} utilities.Response
-// @Router /api/orders/{id}/duplicate [post]
-func (oc *OrderController) DuplicateOrder(c *gin.Context) {
-	orderID := c.Param("id")
-
-	// Get current user ID from context
-	userIDInterface, exists := c.Get("user_id")
-	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "User not found", "user ID not found in context")
+// @Failure 409 {object} utilities.Response
+// @Router /api/orders/{id} [patch]
+func (oc *OrderController) PatchOrder(c *gin.Context) {
+	var req PatchOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
 		return
 	}
 
-	userID, ok := userIDInterface.(uint)
-	if !ok {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID", "user ID has invalid type")
+	edit := orderEdit{
+		Channel:    req.Channel,
+		Store:      req.Store,
+		Buyer:      req.Buyer,
+		BuyerPhone: req.BuyerPhone,
+		Address:    req.Address,
+		Courier:    req.Courier,
+		Tracking:   req.Tracking,
+		SentBefore: req.SentBefore,
+	}
+	if edit == (orderEdit{}) && len(req.AddDetails) == 0 && len(req.UpdateDetails) == 0 && len(req.RemoveDetails) == 0 {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid update", "no fields or details to change were sent")
 		return
 	}
 
-	// Find the original order
-	var originalOrder models.Order
-	if err := oc.DB.Preload("OrderDetails").First(&originalOrder, orderID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			utilities.ErrorResponse(c, http.StatusNotFound, "Order not found", "no order found with the specified ID")
+	// A detail can only be updated or removed once
+	touched := make(map[uint]bool)
+	for _, detailReq := range req.UpdateDetails {
+		if touched[detailReq.ID] {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid update", fmt.Sprintf("order detail %d is sent more than once", detailReq.ID))
 			return
 		}
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to find order", err.Error())
-		return
+		touched[detailReq.ID] = true
 	}
-
-	// Check if order status allows modification
-	if originalOrder.ProcessingStatus == "picking process" || originalOrder.ProcessingStatus == "qc process" {
-		utilities.ErrorResponse(c, http.StatusForbidden, "Order modification not allowed", fmt.Sprintf("cannot modify order when processing status is '%s'.", originalOrder.ProcessingStatus))
-		return
+	for _, detailID := range req.RemoveDetails {
+		if touched[detailID] {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid update", fmt.Sprintf("order detail %d is sent more than once", detailID))
+			return
+		}
+		touched[detailID] = true
 	}
 
-	// Check if order is cancelled
-	if originalOrder.EventStatus != nil && *originalOrder.EventStatus == "cancelled" {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Order already cancelled", "this order has already been cancelled")
-		return
-	}
+	oc.updateOrder(c, req.Version, edit, func(tx *gorm.DB, order *models.Order) error {
+		existingDetailsMap := make(map[uint]models.OrderDetail)
+		for _, detail := range order.OrderDetails {
+			existingDetailsMap[detail.ID] = detail
+		}
 
-	// Check if order has already been duplicated
-	if originalOrder.EventStatus != nil && *originalOrder.EventStatus == "old duplicated" {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Order already duplicated", "this order has already been duplicated")
-		return
-	}
+		for _, detailID := range req.RemoveDetails {
+			if _, exists := existingDetailsMap[detailID]; !exists {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderDetailNotFound, "Order detail not found", fmt.Sprintf("order detail with ID %d not found for this order", detailID))
+			}
+			if err := tx.Delete(&models.OrderDetail{}, detailID).Error; err != nil {
+				return fmt.Errorf("failed to remove order detail: %w", err)
+			}
+		}
 
-	// Begin transaction
-	tx := oc.DB.Begin()
+		for _, detailReq := range req.UpdateDetails {
+			existingDetail, exists := existingDetailsMap[detailReq.ID]
+			if !exists {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderDetailNotFound, "Order detail not found", fmt.Sprintf("order detail with ID %d not found for this order", detailReq.ID))
+			}
 
-	// Store the original tracking before modification
-	originalTracking := originalOrder.Tracking
-	newTracking := "X-" + originalOrder.Tracking
+			if detailReq.Sku != nil {
+				existingDetail.Sku = *detailReq.Sku
+			}
+			if detailReq.ProductName != nil {
+				existingDetail.ProductName = *detailReq.ProductName
+			}
+			if detailReq.Variant != nil {
+				existingDetail.Variant = *detailReq.Variant
+			}
+			if detailReq.Quantity != nil {
+				existingDetail.Quantity = *detailReq.Quantity
+			}
+			if detailReq.Price != nil {
+				existingDetail.Price = *detailReq.Price
+			}
+			existingDetail.ScannedQuantity = 0 // A changed line is scanned again
 
-	// Update original order's order_ginee_id by adding "-X2" suffix and tracking with "X-" prefix
-	oldDuplicatedEventStatus := "old duplicated"
-	originalOrder.EventStatus = &oldDuplicatedEventStatus
-	originalOrder.OrderGineeID = originalOrder.OrderGineeID + "-X2"
-	originalOrder.Tracking = newTracking
-	if err := tx.Save(&originalOrder).Error; err != nil {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update original order", err.Error())
-		return
-	}
+			if err := tx.Save(&existingDetail).Error; err != nil {
+				return fmt.Errorf("failed to update order detail: %w", err)
+			}
+		}
 
-	// Update tracking in qc_online if it exists
-	if err := tx.Model(&models.QcOnline{}).Where("tracking = ?", originalTracking).Update("tracking", newTracking).Error; err != nil {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update qc_online tracking", err.Error())
-		return
-	}
+		for _, detailReq := range req.AddDetails {
+			newDetail := models.OrderDetail{
+				OrderID:     order.ID,
+				Sku:         detailReq.Sku,
+				ProductName: detailReq.ProductName,
+				Variant:     detailReq.Variant,
+				Quantity:    detailReq.Quantity,
+				Price:       detailReq.Price,
+			}
+			if err := tx.Create(&newDetail).Error; err != nil {
+				return fmt.Errorf("failed to add new order detail: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// orderEdit holds the order fields an update changes, nil for the ones it leaves as they are
+type orderEdit struct {
+	Channel    *string
+	Store      *string
+	Buyer      *string
+	BuyerPhone *string
+	Address    *string
+	Courier    *string
+	Tracking   *string
+	SentBefore *string // 2006-01-02 15:04:05, other formats are ignored
+}
+
+// updateOrder applies edit and editDetails to the order of the id parameter in one transaction
+// and responds with the updated order and the changed fields. The order must be at version and
+// not in picking or QC process or cancelled, and keep at least one detail. A revision is stored
+// before the change and the changed fields are recorded in the order history.
+func (oc *OrderController) updateOrder(c *gin.Context, version uint, edit orderEdit, editDetails func(tx *gorm.DB, order *models.Order) error) {
+	orderID := c.Param("id")
 
-	// Update tracking in qc_ribbon if it exists
-	if err := tx.Model(&models.QcRibbon{}).Where("tracking = ?", originalTracking).Update("tracking", newTracking).Error; err != nil {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update qc_ribbon tracking", err.Error())
+	// Get current user ID from context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeUserNotFound, "User not found", "user ID not found in context")
 		return
 	}
 
-	// Update tracking in outbound if it exists
-	if err := tx.Model(&models.Outbound{}).Where("tracking = ?", originalTracking).Update("tracking", newTracking).Error; err != nil {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update outbound tracking", err.Error())
+	userID, ok := userIDInterface.(uint)
+	if !ok {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidToken, "Invalid user ID", "user ID has invalid type")
 		return
 	}
 
-	// Create new duplicated order
-	now := time.Now()
-	duplicatedEventStatus := "duplicated"
-	duplicatedOrder := models.Order{
-		OrderGineeID:     originalOrder.OrderGineeID[:len(originalOrder.OrderGineeID)-3], // Remove the "-X2" suffix for the new order
-		ProcessingStatus: originalOrder.ProcessingStatus,
-		EventStatus:      &duplicatedEventStatus,
-		Channel:          originalOrder.Channel,
-		Store:            originalOrder.Store,
-		Buyer:            originalOrder.Buyer,
-		Address:          originalOrder.Address,
-		Courier:          originalOrder.Courier,
-		Tracking:         originalTracking, // Use original tracking without "X-" prefix
-		SentBefore:       originalOrder.SentBefore,
-		Complained:       false,
-		ChangedBy:        &userID,
-		ChangedAt:        &now,
-	}
-
-	// Create the duplicated order
-	if err := tx.Create(&duplicatedOrder).Error; err != nil {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create duplicated order", err.Error())
-		return
-	}
-
-	// Duplicate order details
-	for _, detail := range originalOrder.OrderDetails {
-		duplicatedDetail := models.OrderDetail{
-			OrderID:     duplicatedOrder.ID,
-			Sku:         detail.Sku,
-			ProductName: detail.ProductName,
-			Variant:     detail.Variant,
-			Quantity:    detail.Quantity,
-			Price:       detail.Price,
-		}
-		if err := tx.Create(&duplicatedDetail).Error; err != nil {
-			tx.Rollback()
-			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to duplicate order details", err.Error())
+	// Validated before the order is locked, the geocoder is an outside call
+	var addressCheck models.AddressCheck
+	if edit.Address != nil {
+		var err error
+		addressCheck, err = models.CheckAddress(oc.DB.WithContext(c), *edit.Address)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid address", err.Error())
 			return
 		}
 	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction", err.Error())
+	var order models.Order
+	var changes []models.OrderChange
+	err := utilities.WithTransaction(oc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Find the order, locked until commit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("OrderDetails").First(&order, orderID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
+			}
+			return err
+		}
+
+		// Reject edits based on an outdated copy of the order
+		if err := checkOrderVersion(&order, version); err != nil {
+			return err
+		}
+
+		// Keep the stored copy to diff against
+		before := order
+		before.OrderDetails = append([]models.OrderDetail(nil), order.OrderDetails...)
+
+		if err := models.RecordOrderRevision(tx, &order, models.OrderRevisionUpdate, userID); err != nil {
+			return err
+		}
+
+		// Check if order status allows modification
+		if order.ProcessingStatus == "picking process" || order.ProcessingStatus == "qc process" {
+			return utilities.NewStatusErrorCode(http.StatusForbidden, utilities.ErrCodeActionNotAllowed, "Order modification not allowed", fmt.Sprintf("cannot modify order when processing status is '%s'.", order.ProcessingStatus))
+		}
+
+		// Check if order is cancelled
+		if order.EventStatus != nil && *order.EventStatus == "cancelled" {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeOrderStateInvalid, "Order already cancelled", "this order has already been cancelled")
+		}
+
+		// Update basic order fields
+		order.ChangedBy = &userID
+		eventStatus := "changed"
+		order.EventStatus = &eventStatus
+		if edit.Channel != nil {
+			order.Channel = *edit.Channel
+		}
+		if edit.Store != nil {
+			order.Store = *edit.Store
+		}
+		if edit.Buyer != nil {
+			order.Buyer = *edit.Buyer
+		}
+		// Clients only see the masked number, sending it back must not overwrite the stored one
+		if edit.BuyerPhone != nil && *edit.BuyerPhone != "" && !strings.Contains(*edit.BuyerPhone, "*") {
+			order.BuyerPhone = *edit.BuyerPhone
+		}
+		if edit.Address != nil {
+			// A failed check keeps the destination of an unchanged address
+			if addressCheck.Status != models.AddressUnchecked || order.Address != *edit.Address {
+				addressCheck.ApplyTo(&order)
+			}
+			order.Address = *edit.Address
+		}
+
+		deadlineChanged := false
+		if edit.Tracking != nil {
+			// Trackings referenced by QC, outbound, return or complain records only change through renumbering
+			if err := models.CheckTrackingChange(tx, order.Tracking, *edit.Tracking); err != nil {
+				var referenced *models.TrackingReferencedError
+				if errors.As(err, &referenced) {
+					return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeTrackingLocked, "Tracking cannot be changed", referenced.Error())
+				}
+				return fmt.Errorf("failed to check tracking references: %w", err)
+			}
+			deadlineChanged = order.Tracking != *edit.Tracking
+			order.Tracking = *edit.Tracking
+		}
+		if edit.Courier != nil {
+			deadlineChanged = deadlineChanged || order.Courier != *edit.Courier
+			order.Courier = *edit.Courier
+		}
+
+		if edit.SentBefore != nil {
+			if parsedTime, err := time.Parse("2006-01-02 15:04:05", *edit.SentBefore); err == nil {
+				deadlineChanged = deadlineChanged || !parsedTime.Equal(order.SentBefore)
+				order.SentBefore = parsedTime
+			}
+		}
+
+		// A new courier or sent before moves the deadline, which may already be over
+		now := time.Now()
+		if deadlineChanged {
+			if err := order.ApplyCourierLane(tx); err != nil {
+				return fmt.Errorf("failed to apply courier lane: %w", err)
+			}
+			if _, err := order.FlagMissedDeadline(tx, now); err != nil {
+				return fmt.Errorf("failed to flag missed deadline: %w", err)
+			}
+			order.ApplyPriority(now)
+		}
+
+		// Set changed_by and changed_at
+		order.ChangedBy = &userID
+		order.ChangedAt = &now
+
+		// Save order changes
+		if err := tx.Save(&order).Error; err != nil {
+			return fmt.Errorf("failed to update order: %w", err)
+		}
+
+		// Process order details updates
+		if err := editDetails(tx, &order); err != nil {
+			return err
+		}
+
+		// Verify at least one order detail remains
+		var detailCount int64
+		if err := tx.Model(&models.OrderDetail{}).Where("order_id = ?", order.ID).Count(&detailCount).Error; err != nil {
+			return fmt.Errorf("failed to count order details: %w", err)
+		}
+
+		if detailCount == 0 {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid update", "order must have at least one order detail")
+		}
+
+		// Record what the edit changed for the order history
+		after := order
+		after.OrderDetails = nil
+		if err := tx.Where("order_id = ?", order.ID).Find(&after.OrderDetails).Error; err != nil {
+			return fmt.Errorf("failed to load order details: %w", err)
+		}
+
+		changes = models.DiffOrder(&before, &after)
+		for i := range changes {
+			changes[i].Version = before.Version + 1
+			changes[i].ChangedBy = userID
+		}
+		if len(changes) > 0 {
+			if err := tx.Create(&changes).Error; err != nil {
+				return fmt.Errorf("failed to record order changes: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to update order")
+		return
+	}
+
+	// Reload order with all relationships
+	if err := oc.Orders.Reload(c, &order, repositories.OrderPickerRoles, repositories.OrderChanger); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload order", err.Error())
+		return
+	}
+
+	response := order.ToOrderResponse()
+	response.Changes = make([]models.OrderChangeResponse, len(changes))
+	for i := range changes {
+		response.Changes[i] = changes[i].ToOrderChangeResponse()
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Order updated successfully", response)
+}
+
+// checkOrderVersion rejects a change based on another version of the order than the stored one
+func checkOrderVersion(order *models.Order, version uint) error {
+	if order.Version != version {
+		return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeOrderVersionConflict, "Order was modified by someone else", fmt.Sprintf("order is at version %d but the request is based on version %d, reload the order and try again", order.Version, version))
+	}
+	return nil
+}
+
+// DuplicateOrder godoc
+// @Summary Duplicate an order
+// @Description Duplicate an existing order with all its details, count times (default 1). Copies get identifiers from the configured duplicate policy: by default the original order is retired with "-X{n}" appended to order_ginee_id and "X-" prefixed to tracking, and the first copy keeps the marketplace identifiers. The generated identifiers are returned.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID to duplicate"
+// @Param request body DuplicateOrderRequest false "Duplicate order request"
+// @Success 201 {object} utilities.Response{data=DuplicateOrderResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/orders/{id}/duplicate [post]
+func (oc *OrderController) DuplicateOrder(c *gin.Context) {
+	orderID := c.Param("id")
+
+	// The body is optional, it only carries the number of copies
+	var req DuplicateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		utilities.ValidationErrorResponse(c, err)
 		return
 	}
+	if req.Count == 0 {
+		req.Count = 1
+	}
 
-	// Reload both orders with all relationships
-	if err := oc.DB.
-		Preload("OrderDetails").
-		Preload("PickOperator.UserRoles.Role").
-		Preload("PickOperator.UserRoles.Assigner").
-		Preload("ChangeOperator").
-		First(&originalOrder, originalOrder.ID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload original order", err.Error())
+	// Get current user ID from context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeUserNotFound, "User not found", "user ID not found in context")
 		return
 	}
 
-	if err := oc.DB.
-		Preload("OrderDetails").
-		Preload("PickOperator.UserRoles.Role").
-		Preload("PickOperator.UserRoles.Assigner").
-		Preload("ChangeOperator").
-		First(&duplicatedOrder, duplicatedOrder.ID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload duplicated order", err.Error())
+	userID, ok := userIDInterface.(uint)
+	if !ok {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidToken, "Invalid user ID", "user ID has invalid type")
 		return
 	}
 
-	// Manually fetch and attach products to order details for both orders
-	for i := range originalOrder.OrderDetails {
-		var product models.Product
-		if err := oc.DB.Where("sku = ?", originalOrder.OrderDetails[i].Sku).First(&product).Error; err == nil {
-			originalOrder.OrderDetails[i].Product = &product
+	policy := models.CurrentSettings().OrderDuplicatePolicy
+
+	var originalOrder models.Order
+	var duplicatedOrders []models.Order
+	var generated []DuplicateIdentifier
+	err := utilities.WithTransaction(oc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Find the original order, locked until commit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("OrderDetails").First(&originalOrder, orderID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
+			}
+			return err
+		}
+
+		// Check if order status allows modification
+		if originalOrder.ProcessingStatus == "picking process" || originalOrder.ProcessingStatus == "qc process" {
+			return utilities.NewStatusErrorCode(http.StatusForbidden, utilities.ErrCodeActionNotAllowed, "Order modification not allowed", fmt.Sprintf("cannot modify order when processing status is '%s'.", originalOrder.ProcessingStatus))
+		}
+
+		// Check if order is cancelled
+		if originalOrder.EventStatus != nil && *originalOrder.EventStatus == "cancelled" {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeOrderStateInvalid, "Order already cancelled", "this order has already been cancelled")
+		}
+
+		// A retired original no longer holds the marketplace identifiers, duplicate the live copy instead
+		if originalOrder.EventStatus != nil && *originalOrder.EventStatus == "old duplicated" {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeOrderStateInvalid, "Order already duplicated", "this order has already been duplicated, duplicate the current copy instead")
+		}
+
+		// A merged order lives on in the order it was merged into
+		if originalOrder.MergedAway() {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeOrderStateInvalid, "Order already merged", fmt.Sprintf("this order was merged into order %d, duplicate that order instead", *originalOrder.MergedIntoID))
+		}
+
+		// Enforce the copy limit over the whole chain
+		chain, err := policy.FindDuplicateChain(tx, &originalOrder)
+		if err != nil {
+			return fmt.Errorf("failed to find duplicate chain: %w", err)
+		}
+		if existing := len(chain) - 1; existing+req.Count > policy.MaxDuplicates {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeOrderDuplicateLimit, "Duplicate limit reached", fmt.Sprintf("order chain already has %d of %d allowed duplicates, cannot add %d more", existing, policy.MaxDuplicates, req.Count))
+		}
+
+		if err := models.RecordOrderRevision(tx, &originalOrder, models.OrderRevisionDuplicate, userID); err != nil {
+			return err
+		}
+
+		baseGineeID := policy.BaseGineeID(originalOrder.OrderGineeID)
+		baseTracking := policy.BaseTracking(originalOrder.Tracking)
+
+		// Hand out the next ordinal whose identifiers are not taken yet, soft deleted orders included
+		ordinal := len(chain)
+		nextIdentifiers := func() (string, string, error) {
+			for {
+				ordinal++
+				gineeID := policy.GineeID(baseGineeID, ordinal)
+				tracking := policy.Tracking(baseTracking, ordinal)
+
+				var taken int64
+				if err := tx.Unscoped().Model(&models.Order{}).Where("order_ginee_id = ? OR tracking = ?", gineeID, tracking).Count(&taken).Error; err != nil {
+					return "", "", fmt.Errorf("failed to check duplicate identifiers: %w", err)
+				}
+				if taken == 0 {
+					return gineeID, tracking, nil
+				}
+			}
+		}
+
+		// Identifiers of the copies, in creation order
+		type identifiers struct{ gineeID, tracking string }
+		var copies []identifiers
+
+		if policy.ModifyOriginal {
+			gineeID, tracking, err := nextIdentifiers()
+			if err != nil {
+				return err
+			}
+
+			// The first copy takes over the marketplace identifiers of the original
+			copies = append(copies, identifiers{originalOrder.OrderGineeID, originalOrder.Tracking})
+			originalTracking := originalOrder.Tracking
+
+			// Move the original's QC, outbound, return and complain records along with its tracking
+			if err := models.RenameTracking(tx, originalTracking, tracking); err != nil {
+				return err
+			}
+
+			// Retire the original order under the generated identifiers
+			oldDuplicatedEventStatus := "old duplicated"
+			originalOrder.EventStatus = &oldDuplicatedEventStatus
+			originalOrder.OrderGineeID = gineeID
+			originalOrder.Tracking = tracking
+			if err := tx.Save(&originalOrder).Error; err != nil {
+				return fmt.Errorf("failed to update original order: %w", err)
+			}
+			generated = append(generated, DuplicateIdentifier{OrderID: originalOrder.ID, Role: "original", OrderGineeID: gineeID, Tracking: tracking})
+		}
+
+		for len(copies) < req.Count {
+			gineeID, tracking, err := nextIdentifiers()
+			if err != nil {
+				return err
+			}
+			copies = append(copies, identifiers{gineeID, tracking})
+		}
+
+		now := time.Now()
+		for _, ids := range copies {
+			duplicatedEventStatus := "duplicated"
+			duplicatedOrder := models.Order{
+				OrderGineeID:     ids.gineeID,
+				ProcessingStatus: originalOrder.ProcessingStatus,
+				EventStatus:      &duplicatedEventStatus,
+				Channel:          originalOrder.Channel,
+				Store:            originalOrder.Store,
+				Buyer:            originalOrder.Buyer,
+				BuyerPhone:       originalOrder.BuyerPhone,
+				Address:          originalOrder.Address,
+				Province:         originalOrder.Province,
+				City:             originalOrder.City,
+				PostalCode:       originalOrder.PostalCode,
+				AddressStatus:    originalOrder.AddressStatus,
+				Courier:          originalOrder.Courier,
+				Tracking:         ids.tracking,
+				SentBefore:       originalOrder.SentBefore,
+				Complained:       false,
+				ChangedBy:        &userID,
+				ChangedAt:        &now,
+			}
+
+			// Create the duplicated order
+			if err := tx.Create(&duplicatedOrder).Error; err != nil {
+				return fmt.Errorf("failed to create duplicated order: %w", err)
+			}
+
+			// Duplicate order details
+			for _, detail := range originalOrder.OrderDetails {
+				duplicatedDetail := models.OrderDetail{
+					OrderID:     duplicatedOrder.ID,
+					Sku:         detail.Sku,
+					ProductName: detail.ProductName,
+					Variant:     detail.Variant,
+					Quantity:    detail.Quantity,
+					Price:       detail.Price,
+				}
+				if err := tx.Create(&duplicatedDetail).Error; err != nil {
+					return fmt.Errorf("failed to duplicate order details: %w", err)
+				}
+			}
+
+			duplicatedOrders = append(duplicatedOrders, duplicatedOrder)
+			generated = append(generated, DuplicateIdentifier{OrderID: duplicatedOrder.ID, Role: "duplicate", OrderGineeID: ids.gineeID, Tracking: ids.tracking})
 		}
+
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to duplicate order")
+		return
+	}
+
+	// Reload all orders with their relationships
+	orders := []*models.Order{&originalOrder}
+	for i := range duplicatedOrders {
+		orders = append(orders, &duplicatedOrders[i])
 	}
 
-	for i := range duplicatedOrder.OrderDetails {
-		var product models.Product
-		if err := oc.DB.Where("sku = ?", duplicatedOrder.OrderDetails[i].Sku).First(&product).Error; err == nil {
-			duplicatedOrder.OrderDetails[i].Product = &product
+	for _, order := range orders {
+		if err := oc.Orders.Reload(c, order, repositories.OrderPickerRoles, repositories.OrderChanger); err != nil {
+			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload order", err.Error())
+			return
 		}
 	}
 
+	duplicatedResponses := make([]models.OrderResponse, len(duplicatedOrders))
+	for i := range duplicatedOrders {
+		duplicatedResponses[i] = duplicatedOrders[i].ToOrderResponse()
+	}
+
 	response := DuplicateOrderResponse{
-		OriginalOrder:   originalOrder.ToOrderResponse(),
-		DuplicatedOrder: duplicatedOrder.ToOrderResponse(),
+		OriginalOrder:    originalOrder.ToOrderResponse(),
+		DuplicatedOrder:  duplicatedResponses[0],
+		DuplicatedOrders: duplicatedResponses,
+		Generated:        generated,
 	}
 
 	utilities.SuccessResponse(c, http.StatusCreated, "Order duplicated successfully", response)
@@ -776,449 +1255,1649 @@ func (oc *OrderController) DuplicateOrder(c *gin.Context) {
 
 // CancelOrder godoc
 // @Summary Cancel an order
-// @Description Cancel an order by setting event_status to "cancelled" and recording who cancelled it and when
+// @Description Cancel an order by setting event_status to "cancelled" and recording who cancelled it and when. Its pick and QC records are flagged with the cancellation time in the same transaction, and outbound scans of its tracking are refused from then on.
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Order ID to cancel"
+// @Param request body CancelOrderRequest true "Cancel order request"
 // @Success 200 {object} utilities.Response{data=models.OrderResponse}
 // @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
 // @Router /api/orders/{id}/cancel [put]
 func (oc *OrderController) CancelOrder(c *gin.Context) {
 	orderID := c.Param("id")
 
+	var req CancelOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
 	// Get current user ID from context
 	userIDInterface, exists := c.Get("user_id")
 	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "User not found", "user ID not found in context")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeUserNotFound, "User not found", "user ID not found in context")
 		return
 	}
 
 	userID, ok := userIDInterface.(uint)
 	if !ok {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID", "user ID has invalid type")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidToken, "Invalid user ID", "user ID has invalid type")
 		return
 	}
 
-	// Find the order
 	var order models.Order
-	if err := oc.DB.First(&order, orderID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			utilities.ErrorResponse(c, http.StatusNotFound, "Order not found", "no order found with the specified ID")
-			return
+	var cascade models.OrderCancellation
+	err := utilities.WithTransaction(oc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Find the order, locked until commit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
+			}
+			return err
 		}
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to find order", err.Error())
+
+		// Reject a cancellation based on an outdated copy of the order
+		if err := checkOrderVersion(&order, req.Version); err != nil {
+			return err
+		}
+
+		// Check if order status allows modification
+		if order.ProcessingStatus == "picking process" || order.ProcessingStatus == "qc process" {
+			return utilities.NewStatusErrorCode(http.StatusForbidden, utilities.ErrCodeActionNotAllowed, "Order modification not allowed", fmt.Sprintf("cannot modify order when processing status is '%s'.", order.ProcessingStatus))
+		}
+
+		// Check if order is already cancelled
+		if order.EventStatus != nil && *order.EventStatus == "cancelled" {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeOrderStateInvalid, "Order already cancelled", "this order has already been cancelled")
+		}
+
+		if err := models.RecordOrderRevision(tx, &order, models.OrderRevisionCancel, userID); err != nil {
+			return err
+		}
+
+		// Update order with cancellation details
+		eventStatus := "cancelled"
+		now := time.Now()
+		order.EventStatus = &eventStatus
+		order.CancelledBy = &userID
+		order.CancelledAt = &now
+
+		if err := tx.Save(&order).Error; err != nil {
+			return err
+		}
+
+		// Flag the pick and QC records of the order along with it
+		var err error
+		cascade, err = models.CascadeOrderCancellation(tx, &order, now)
+		if err != nil {
+			return fmt.Errorf("failed to cancel pick and QC records: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to cancel order")
 		return
 	}
 
-	// Check if order status allows modification
-	if order.ProcessingStatus == "picking process" || order.ProcessingStatus == "qc process" {
-		utilities.ErrorResponse(c, http.StatusForbidden, "Order modification not allowed", fmt.Sprintf("cannot modify order when processing status is '%s'.", order.ProcessingStatus))
+	// Reload order with all relationships
+	if err := oc.Orders.Reload(c, &order, repositories.OrderPickerRoles, repositories.OrderCanceller); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload order", err.Error())
 		return
 	}
 
-	// Check if order is already cancelled
-	if order.EventStatus != nil && *order.EventStatus == "cancelled" {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Order already cancelled", "this order has already been cancelled")
+	message := "Order cancelled successfully"
+	if flagged := cascade.PickedOrders + cascade.QcRibbons + cascade.QcOnlines; flagged > 0 {
+		message = utilities.Messagef(c, "Order cancelled successfully, %d pick and QC record(s) flagged as cancelled", flagged)
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, message, order.ToOrderResponse())
+}
+
+// HoldOrder godoc
+// @Summary Put an order on hold
+// @Description Put an order on hold with a reason, e.g. while the buyer changes the address before shipment. Held orders cannot be assigned to a picker, QC'd or sent out until they are released; pickers grabbing orders skip them. Orders already sent out, cancelled or merged cannot be held. The hold is recorded in the order history.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID to hold"
+// @Param request body HoldOrderRequest true "Hold order request"
+// @Success 200 {object} utilities.Response{data=models.OrderResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/orders/{id}/hold [put]
+func (oc *OrderController) HoldOrder(c *gin.Context) {
+	orderID := c.Param("id")
+	userID := c.GetUint("user_id")
+
+	var req HoldOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeReasonRequired, "Reason is required", "reason must not be blank")
+		return
+	}
+
+	var order models.Order
+	err := utilities.WithTransaction(oc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Find the order, locked until commit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
+			}
+			return err
+		}
+
+		// Reject a hold based on an outdated copy of the order
+		if err := checkOrderVersion(&order, req.Version); err != nil {
+			return err
+		}
+
+		if order.IsHeld() {
+			return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeOrderHeld, "Order is on hold", fmt.Sprintf("the order is already on hold: %s", order.HoldReason))
+		}
+		if order.IsCancelled() {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeOrderStateInvalid, "Order already cancelled", "a cancelled order cannot be held")
+		}
+		if order.MergedAway() {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeOrderStateInvalid, "Order already merged", fmt.Sprintf("cannot hold an order merged into order %d", *order.MergedIntoID))
+		}
+		if order.ProcessingStatus == "outbound completed" {
+			return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeOrderStateInvalid, "Order already sent out", "the order was already handed to the courier")
+		}
+
+		return models.HoldOrder(tx, &order, req.Reason, userID, time.Now())
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to hold order")
+		return
+	}
+
+	if err := oc.DB.WithContext(c).Scopes(repositories.OrderWithDetails(repositories.OrderPickerRoles)).First(&order, order.ID).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload order", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Order held successfully", order.ToOrderResponse())
+}
+
+// UnholdOrder godoc
+// @Summary Release an order from hold
+// @Description Release a held order so it can be assigned, QC'd and sent out again. The release is recorded in the order history.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID to release"
+// @Param request body UnholdOrderRequest true "Unhold order request"
+// @Success 200 {object} utilities.Response{data=models.OrderResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/orders/{id}/unhold [put]
+func (oc *OrderController) UnholdOrder(c *gin.Context) {
+	orderID := c.Param("id")
+	userID := c.GetUint("user_id")
+
+	var req UnholdOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	var order models.Order
+	err := utilities.WithTransaction(oc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Find the order, locked until commit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
+			}
+			return err
+		}
+
+		// Reject a release based on an outdated copy of the order
+		if err := checkOrderVersion(&order, req.Version); err != nil {
+			return err
+		}
+
+		if !order.IsHeld() {
+			return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeOrderStateInvalid, "Order is not on hold", "only held orders can be released")
+		}
+
+		return models.UnholdOrder(tx, &order, userID)
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to release order")
 		return
 	}
 
-	// Update order with cancellation details
-	eventStatus := "cancelled"
-	now := time.Now()
-	order.EventStatus = &eventStatus
-	order.CancelledBy = &userID
-	order.CancelledAt = &now
-
-	if err := oc.DB.Save(&order).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to cancel order", err.Error())
+	if err := oc.DB.WithContext(c).Scopes(repositories.OrderWithDetails(repositories.OrderPickerRoles)).First(&order, order.ID).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload order", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Order released successfully", order.ToOrderResponse())
+}
+
+// MergeOrders godoc
+// @Summary Merge two orders of one shipment
+// @Description Merge an order Ginee resent under another ID into the canonical order of the shipment. The canonical order keeps its tracking and gets the lines of the merged order: lines of the same SKU and variant keep the larger quantity (quantities "max", the default, for a resent order) or add up (quantities "sum", for a shipment split across both). The earlier sent before of both applies. The merged order is linked to the canonical one with event_status "merged" and can no longer be picked. Neither order may be cancelled, merged or picked already.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MergeOrdersRequest true "Merge orders request"
+// @Success 200 {object} utilities.Response{data=MergeOrdersResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/orders/merge [post]
+func (oc *OrderController) MergeOrders(c *gin.Context) {
+	var req MergeOrdersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+	if req.Quantities == "" {
+		req.Quantities = models.OrderMergeQuantityMax
+	}
+
+	userID := c.GetUint("user_id")
+
+	var order, merged models.Order
+	var changes []models.OrderChange
+	err := utilities.WithTransaction(oc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Lock both orders in ID order so concurrent merges cannot deadlock
+		var orders []models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("OrderDetails").
+			Where("id IN ?", []uint{req.OrderID, req.MergeOrderID}).Order("id ASC").Find(&orders).Error; err != nil {
+			return err
+		}
+		for _, found := range orders {
+			if found.ID == req.OrderID {
+				order = found
+			}
+			if found.ID == req.MergeOrderID {
+				merged = found
+			}
+		}
+		if order.ID == 0 || merged.ID == 0 {
+			return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
+		}
+
+		// Reject a merge based on outdated copies of the orders
+		if err := checkOrderVersion(&order, req.Version); err != nil {
+			return err
+		}
+		if err := checkOrderVersion(&merged, req.MergeVersion); err != nil {
+			return err
+		}
+
+		if err := models.ValidateOrderMerge(&order, &merged); err != nil {
+			return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeOrderMergeInvalid, "Orders cannot be merged", err.Error())
+		}
+
+		// Keep the stored copy to diff against
+		before := order
+		before.OrderDetails = append([]models.OrderDetail(nil), order.OrderDetails...)
+
+		for _, detail := range models.MergeOrderDetails(&order, &merged, req.Quantities) {
+			if err := tx.Save(&detail).Error; err != nil {
+				return fmt.Errorf("failed to merge order details: %w", err)
+			}
+		}
+
+		// The shipment has to leave by the earlier deadline of both orders
+		now := time.Now()
+		if merged.SentBefore.Before(order.SentBefore) {
+			order.SentBefore = merged.SentBefore
+			if err := order.ApplyCourierLane(tx); err != nil {
+				return fmt.Errorf("failed to apply courier lane: %w", err)
+			}
+			if _, err := order.FlagMissedDeadline(tx, now); err != nil {
+				return fmt.Errorf("failed to flag missed deadline: %w", err)
+			}
+			order.ApplyPriority(now)
+		}
+
+		order.ChangedBy = &userID
+		order.ChangedAt = &now
+		order.OrderDetails = nil
+		if err := tx.Save(&order).Error; err != nil {
+			return fmt.Errorf("failed to update order: %w", err)
+		}
+
+		// Retire the merged order, linked to the order it was merged into
+		mergedEventStatus := "merged"
+		merged.EventStatus = &mergedEventStatus
+		merged.MergedIntoID = &order.ID
+		merged.MergedAt = &now
+		merged.ChangedBy = &userID
+		merged.ChangedAt = &now
+		mergedDetails := merged.OrderDetails
+		merged.OrderDetails = nil
+		if err := tx.Save(&merged).Error; err != nil {
+			return fmt.Errorf("failed to update merged order: %w", err)
+		}
+		merged.OrderDetails = mergedDetails
+
+		// Record the merge in the history of both orders
+		after := order
+		if err := tx.Where("order_id = ?", order.ID).Find(&after.OrderDetails).Error; err != nil {
+			return fmt.Errorf("failed to load order details: %w", err)
+		}
+		changes = models.DiffOrder(&before, &after)
+		changes = append(changes, models.OrderChange{OrderID: order.ID, Field: "merged_order", NewValue: merged.OrderGineeID})
+		for i := range changes {
+			changes[i].Version = before.Version + 1
+			changes[i].ChangedBy = userID
+		}
+		recorded := append(append([]models.OrderChange(nil), changes...), models.OrderChange{
+			OrderID:   merged.ID,
+			Version:   req.MergeVersion + 1,
+			Field:     "merged_into",
+			NewValue:  order.OrderGineeID,
+			ChangedBy: userID,
+		})
+		if err := tx.Create(&recorded).Error; err != nil {
+			return fmt.Errorf("failed to record order changes: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to merge orders")
+		return
+	}
+
+	// Reload both orders with their relationships
+	for _, reloaded := range []*models.Order{&order, &merged} {
+		if err := oc.Orders.Reload(c, reloaded, repositories.OrderPickerRoles, repositories.OrderChanger); err != nil {
+			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload order", err.Error())
+			return
+		}
+	}
+
+	response := MergeOrdersResponse{
+		Order:       order.ToOrderResponse(),
+		MergedOrder: merged.ToOrderResponse(),
+	}
+	response.Order.Changes = make([]models.OrderChangeResponse, len(changes))
+	for i := range changes {
+		response.Order.Changes[i] = changes[i].ToOrderChangeResponse()
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Orders merged successfully", response)
+}
+
+// DeleteOrder godoc
+// @Summary Delete an order
+// @Description Soft delete an order, such as a test order, so it no longer shows up in order lists and reports. Orders being picked or QC'd cannot be deleted. Deleted orders can be restored (superadmin only)
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} utilities.Response{data=models.OrderResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/orders/{id} [delete]
+func (oc *OrderController) DeleteOrder(c *gin.Context) {
+	orderID := c.Param("id")
+	userID := c.GetUint("user_id")
+
+	var order models.Order
+	err := utilities.WithTransaction(oc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Find the order, locked until commit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
+			}
+			return err
+		}
+
+		if order.ProcessingStatus == "picking process" || order.ProcessingStatus == "qc process" {
+			return utilities.NewStatusErrorCode(http.StatusForbidden, utilities.ErrCodeActionNotAllowed, "Order deletion not allowed", fmt.Sprintf("cannot delete order when processing status is '%s'.", order.ProcessingStatus))
+		}
+
+		change := models.OrderChange{OrderID: order.ID, Version: order.Version + 1, Field: "deleted", OldValue: "no", NewValue: "yes", ChangedBy: userID}
+		if err := tx.Model(&order).Update("deleted_by", userID).Error; err != nil {
+			return fmt.Errorf("failed to update order: %w", err)
+		}
+		if err := tx.Delete(&order).Error; err != nil {
+			return fmt.Errorf("failed to delete order: %w", err)
+		}
+		if err := tx.Create(&change).Error; err != nil {
+			return fmt.Errorf("failed to record order deletion: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to delete order")
+		return
+	}
+
+	if err := oc.DB.WithContext(c).Unscoped().Preload("OrderDetails").First(&order, order.ID).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload order", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Order deleted successfully", order.ToOrderResponse())
+}
+
+// RestoreOrder godoc
+// @Summary Restore a deleted order
+// @Description Restore a soft deleted order so it shows up in order lists and reports again (superadmin only)
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} utilities.Response{data=models.OrderResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/orders/{id}/restore [post]
+func (oc *OrderController) RestoreOrder(c *gin.Context) {
+	orderID := c.Param("id")
+	userID := c.GetUint("user_id")
+
+	var order models.Order
+	err := utilities.WithTransaction(oc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Find the order, deleted or not, locked until commit
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
+			}
+			return err
+		}
+
+		if !order.DeletedAt.Valid {
+			return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeOrderStateInvalid, "Order is not deleted", "only deleted orders can be restored")
+		}
+
+		change := models.OrderChange{OrderID: order.ID, Version: order.Version + 1, Field: "deleted", OldValue: "yes", NewValue: "no", ChangedBy: userID}
+		if err := tx.Unscoped().Model(&order).Updates(map[string]interface{}{"deleted_at": nil, "deleted_by": nil}).Error; err != nil {
+			return fmt.Errorf("failed to restore order: %w", err)
+		}
+		if err := tx.Create(&change).Error; err != nil {
+			return fmt.Errorf("failed to record order restore: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to restore order")
+		return
+	}
+
+	if err := oc.DB.WithContext(c).Scopes(repositories.OrderWithDetails(repositories.OrderPickerRoles)).First(&order, order.ID).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload order", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Order restored successfully", order.ToOrderResponse())
+}
+
+// AssignPicker godoc
+// @Summary Assign a picker to an order
+// @Description Assign a picker to an order, setting assigned_by to current user, assigned_at to now, picked_by to specified picker, and processing_status to "picking process"
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body AssignPickerRequest true "Assign picker request"
+// @Success 200 {object} utilities.Response{data=models.OrderResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/orders/assign-picker [post]
+func (oc *OrderController) AssignPicker(c *gin.Context) {
+	var req AssignPickerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	// Get current user ID from context (assigner)
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeUserNotFound, "User not found", "user ID not found in context")
+		return
+	}
+
+	userID, ok := userIDInterface.(uint)
+	if !ok {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidToken, "Invalid user ID", "user ID has invalid type")
+		return
+	}
+
+	// Verify the picker exists
+	var picker models.User
+	if err := oc.DB.WithContext(c).First(&picker, req.PickerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodePickerNotFound, "Picker not found", "no user found with the specified picker ID")
+			return
+		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to find picker", err.Error())
+		return
+	}
+
+	var order models.Order
+	err := utilities.WithTransaction(oc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Find the order by tracking, locked until commit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("tracking = ?", req.Tracking).First(&order).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified tracking number")
+			}
+			return err
+		}
+
+		// Reject an assignment based on an outdated copy of the order
+		if err := checkOrderVersion(&order, req.Version); err != nil {
+			return err
+		}
+
+		// Check if order is cancelled
+		if order.EventStatus != nil && *order.EventStatus == "cancelled" {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeOrderStateInvalid, "Order already cancelled", "cannot assign picker to a cancelled order")
+		}
+
+		// Merged orders are picked as part of the order they were merged into
+		if order.MergedAway() {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeOrderStateInvalid, "Order already merged", fmt.Sprintf("cannot assign picker to an order merged into order %d", *order.MergedIntoID))
+		}
+
+		// Held orders wait until they are released
+		if order.IsHeld() {
+			return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeOrderHeld, "Order is on hold", fmt.Sprintf("cannot assign picker to an order on hold: %s", order.HoldReason))
+		}
+
+		// Check if order is not "ready to pick"
+		if order.ProcessingStatus != "ready to pick" && order.ProcessingStatus != "pending picking" {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodePickerUnavailable, "Cannot assign picker", "Only orders that are in 'ready to pick' or 'pending picking' status can be assigned to a picker. Status now is '"+order.ProcessingStatus+"'.")
+		}
+
+		// Update order with assignment details
+		now := time.Now()
+		order.AssignedBy = &userID
+		order.AssignedAt = &now
+		order.PickedBy = &req.PickerID
+		order.ProcessingStatus = "picking process"
+
+		return tx.Save(&order).Error
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to assign picker")
+		return
+	}
+
+	// Reload order with all relationships
+	if err := oc.Orders.Reload(c, &order, repositories.OrderPickerRoles, repositories.OrderAssigner); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload order", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Picker assigned successfully", order.ToOrderResponse())
+}
+
+// AutoAssignOrders godoc
+// @Summary Auto-assign ready to pick orders to pickers
+// @Description Distribute "ready to pick" orders among the active pickers: pickers seen within the configured active minutes, holding fewer than the maximum of open orders. Late and urgent orders go first, then instant courier orders, then by sent before. With zone affinity each order goes to the picker assigned to most of the zones its products live in, ties go to the picker with the fewest open orders; in restrict zone mode pickers with zones only get orders in their zones. Cancelled, merged and held orders are left out. Without apply the proposed assignment is returned and nothing is saved. With apply it is saved right away. Sending the assignments of a proposal back confirms them: each is saved unless its order changed since (other version) or can no longer be assigned.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body AutoAssignOrdersRequest false "Auto-assign request"
+// @Success 200 {object} utilities.Response{data=AutoAssignOrdersResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/orders/auto-assign [post]
+func (oc *OrderController) AutoAssignOrders(c *gin.Context) {
+	var req AutoAssignOrdersRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 100
+	}
+
+	policy := models.CurrentSettings().AutoAssign
+	if req.MaxOpenOrders != nil {
+		policy.MaxOpenOrders = *req.MaxOpenOrders
+	}
+	if req.ZoneAffinity != nil {
+		policy.ZoneAffinity = *req.ZoneAffinity
+	}
+
+	userID := c.GetUint("user_id")
+	now := time.Now()
+
+	// Confirmed assignments of an earlier proposal
+	if len(req.Assignments) > 0 {
+		response, err := oc.confirmAutoAssignments(c, req.Assignments, userID, now)
+		if err != nil {
+			utilities.TransactionErrorResponse(c, err, "Failed to auto-assign orders")
+			return
+		}
+		utilities.SuccessResponse(c, http.StatusOK, "Orders auto-assigned successfully", response)
+		return
+	}
+
+	plan, err := models.PlanAutoAssignment(oc.DB.WithContext(c), policy, req.Limit, now)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to plan auto-assignment", err.Error())
+		return
+	}
+
+	response := AutoAssignOrdersResponse{
+		Pickers:     plan.Pickers,
+		Assignments: plan.Assignments,
+		Unassigned:  plan.Unassigned,
+		Skipped:     []SkippedAssignment{},
+		Failed:      []FailedAssignment{},
+	}
+	if !req.Apply {
+		response.Summary = BulkAssignSummary{Total: len(plan.Assignments) + len(plan.Unassigned)}
+		utilities.SuccessResponse(c, http.StatusOK, "Auto-assignment proposed successfully", response)
+		return
+	}
+
+	applied := oc.applyAutoAssignments(c, plan.Assignments, userID, now)
+	applied.Pickers = plan.Pickers
+	applied.Unassigned = plan.Unassigned
+	applied.Summary.Total += len(plan.Unassigned)
+	utilities.SuccessResponse(c, http.StatusOK, "Orders auto-assigned successfully", applied)
+}
+
+// confirmAutoAssignments saves the assignments of a proposal a coordinator confirmed, to pickers
+// that are still active picker accounts
+func (oc *OrderController) confirmAutoAssignments(c *gin.Context, confirmed []AutoAssignConfirmation, userID uint, now time.Time) (AutoAssignOrdersResponse, error) {
+	policy := models.CurrentSettings().AutoAssign
+	policy.ActiveMinutes = 0
+	pickers, err := models.ActiveAutoAssignPickers(oc.DB.WithContext(c), policy, now)
+	if err != nil {
+		return AutoAssignOrdersResponse{}, err
+	}
+	names := make(map[uint]string, len(pickers))
+	for _, picker := range pickers {
+		names[picker.PickerID] = picker.FullName
+	}
+
+	assignments := make([]models.AutoAssignment, len(confirmed))
+	for i, item := range confirmed {
+		if _, found := names[item.PickerID]; !found {
+			return AutoAssignOrdersResponse{}, utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodePickerNotFound, "Picker not found", fmt.Sprintf("user %d is not an active picker", item.PickerID))
+		}
+		assignments[i] = models.AutoAssignment{OrderID: item.OrderID, Version: item.Version, PickerID: item.PickerID, PickerName: names[item.PickerID]}
+	}
+
+	response := oc.applyAutoAssignments(c, assignments, userID, now)
+	response.Pickers = pickers
+	response.Unassigned = []models.AutoAssignUnassigned{}
+	return response, nil
+}
+
+// applyAutoAssignments assigns each order to its picker in its own transaction, skipping orders
+// that changed since the assignment was planned
+func (oc *OrderController) applyAutoAssignments(c *gin.Context, assignments []models.AutoAssignment, userID uint, now time.Time) AutoAssignOrdersResponse {
+	response := AutoAssignOrdersResponse{
+		Applied:     true,
+		Assignments: []models.AutoAssignment{},
+		Skipped:     []SkippedAssignment{},
+		Failed:      []FailedAssignment{},
+	}
+
+	for i, assignment := range assignments {
+		var order models.Order
+		skipReason := ""
+
+		err := utilities.WithTransaction(oc.DB.WithContext(c), func(tx *gorm.DB) error {
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, assignment.OrderID).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					skipReason = "Order not found"
+					return nil
+				}
+				return err
+			}
+
+			switch {
+			case order.Version != assignment.Version:
+				skipReason = fmt.Sprintf("Order was modified since the proposal (version %d, proposed on version %d)", order.Version, assignment.Version)
+			case order.IsCancelled():
+				skipReason = "Order is cancelled"
+			case order.MergedAway():
+				skipReason = fmt.Sprintf("Order is merged into order %d", *order.MergedIntoID)
+			case order.IsHeld():
+				skipReason = "Order is on hold: " + order.HoldReason
+			case order.ProcessingStatus != "ready to pick":
+				skipReason = fmt.Sprintf("Order is %s, only 'ready to pick' orders are auto-assigned", order.ProcessingStatus)
+			}
+			if skipReason != "" {
+				return nil
+			}
+
+			order.AssignedBy = &userID
+			order.AssignedAt = &now
+			order.PickedBy = &assignment.PickerID
+			order.ProcessingStatus = "picking process"
+			return tx.Save(&order).Error
+		})
+
+		tracking := assignment.Tracking
+		if tracking == "" {
+			tracking = order.Tracking
+		}
+		if err != nil {
+			response.Failed = append(response.Failed, FailedAssignment{Index: i, Tracking: tracking, Error: err.Error()})
+			continue
+		}
+		if skipReason != "" {
+			response.Skipped = append(response.Skipped, SkippedAssignment{Index: i, Tracking: tracking, Reason: skipReason})
+			continue
+		}
+
+		assignment.OrderGineeID = order.OrderGineeID
+		assignment.Tracking = order.Tracking
+		assignment.Priority = order.Priority
+		assignment.Version = order.Version
+		response.Assignments = append(response.Assignments, assignment)
+	}
+
+	response.Summary = BulkAssignSummary{
+		Total:    len(assignments),
+		Assigned: len(response.Assignments),
+		Skipped:  len(response.Skipped),
+		Failed:   len(response.Failed),
+	}
+	return response
+}
+
+// PendingPickOrders godoc
+// @Summary Get orders pending pick assignment
+// @Description Pending order that already assigned to a picker, but not picked yet. What was scanned of its lines is cleared, the next picker scans them again.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID to pending picking process"
+// @Success 200 {object} utilities.Response{data=models.OrderResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/orders/{id}/pending-pick [put]
+func (oc *OrderController) PendingPickOrders(c *gin.Context) {
+	orderID := c.Param("id")
+
+	// Get current user ID from context (pending operator)
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeUserNotFound, "User not found", "user ID not found in context")
+		return
+	}
+
+	userID, ok := userIDInterface.(uint)
+	if !ok {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidToken, "Invalid user ID", "user ID has invalid type")
+		return
+	}
+
+	var order models.Order
+	err := utilities.WithTransaction(oc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Find the order, locked until commit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
+			}
+			return err
+		}
+
+		// Check if status order is "picking process"
+		if order.ProcessingStatus != "picking process" {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeOrderStateInvalid, "Order not in picking process", "only orders in 'picking process' status can be set to pending pick")
+		}
+
+		// Update order with pending pick details
+		now := time.Now()
+		order.ProcessingStatus = "pending picking"
+		order.PendingBy = &userID // Set pending operator
+		order.PendingAt = &now
+		order.PickedBy = nil   // Clear picked_by since it's pending
+		order.AssignedBy = nil // Clear assigned_by since it's pending
+		order.AssignedAt = nil // Clear assigned_at since it's pending
+
+		if err := tx.Save(&order).Error; err != nil {
+			return err
+		}
+
+		// Scans of the picker set back do not carry over to the next one
+		return models.ResetOrderScans(tx, order.ID)
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to set order to pending pick")
+		return
+	}
+
+	// Reload order with all relationships
+	if err := oc.Orders.Reload(c, &order, repositories.OrderPickerRoles, repositories.OrderPending); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload order", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Order set to pending pick successfully", order.ToOrderResponse())
+}
+
+// GetAssignedOrders godoc
+// @Summary Get orders assigned to all pickers
+// @Description Retrieve all orders currently assigned to all pickers that are in "picking process" status, instant courier orders first.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param search query string false "Search by Order Ginee ID or Tracking number"
+// @Param instant query bool false "Only instant courier orders (true) or only regular orders (false)"
+// @Success 200 {object} utilities.Response{data=[]models.OrderResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/orders/assigned [get]
+func (oc *OrderController) GetAssignedOrders(c *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	// Parse search parameter (optional)
+	search := c.Query("search")
+
+	var orders []models.Order
+	var total int64
+
+	// Build query with necessary preloads and filters
+	query := oc.DB.WithContext(c).Model(&models.Order{}).
+		Where("processing_status = ?", "picking process")
+
+	if search != "" {
+		// Search by Order Ginee ID or Tracking number
+		query = query.Where("order_ginee_id LIKE ? OR tracking LIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	query, err := filterInstant(c, query)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid instant filter", err.Error())
+		return
+	}
+
+	// Get total count with filters
+	if err := query.Count(&total).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count orders", err.Error())
+		return
+	}
+
+	// Get assigned orders with pagination, instant courier orders first
+	if err := query.Order("instant DESC, assigned_at DESC").
+		Scopes(repositories.OrderWithDetails(repositories.OrderOperators...)).
+		Limit(limit).Offset(offset).Find(&orders).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve orders", err.Error())
+		return
+	}
+
+	// Attach products to order details
+	if err := oc.Orders.AttachListProducts(c, orders); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve orders", err.Error())
+		return
+	}
+
+	// Convert orders to response format
+	orderResponses := make([]models.OrderResponse, len(orders))
+	for i, order := range orders {
+		orderResponses[i] = order.ToOrderResponse()
+	}
+
+	// Return success response
+	utilities.SuccessResponse(c, http.StatusOK, "Assigned orders retrieved successfully", orderResponses)
+}
+
+// QCProcessStatusOrder godoc
+// @Summary Change operation status order to "qc process"
+// @Description Change operation status order to "qc process" when starting qc process.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID to change to qc process"
+// @Success 200 {object} utilities.Response{data=models.OrderResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/orders/{id}/qc-process [put]
+func (oc *OrderController) QCProcessStatusOrder(c *gin.Context) {
+	orderID := c.Param("id")
+
+	var order models.Order
+	err := utilities.WithTransaction(oc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Find the order, locked until commit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
+			}
+			return err
+		}
+
+		// Check if order is already in qc process
+		if order.ProcessingStatus == "qc process" {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeOrderStateInvalid, "Order already in QC process", "this order is already in 'qc process' status")
+		}
+
+		// Check if order is cancelled
+		if order.EventStatus != nil && *order.EventStatus == "cancelled" {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeOrderStateInvalid, "Order already cancelled", "cannot change status of a cancelled order")
+		}
+
+		// Update order status to "qc process"
+		order.ProcessingStatus = "qc process"
+		return tx.Save(&order).Error
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to update order status")
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Order status updated to QC process", order.ToOrderResponse())
+}
+
+// PickingCompletedStatusOrder godoc
+// @Summary Change operation status order to "picking completed"
+// @Description Change operation status order to "picking completed" when qc process are canceled.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID to change to picking completed"
+// @Success 200 {object} utilities.Response{data=models.OrderResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/orders/{id}/picking-completed [put]
+func (oc *OrderController) PickingCompletedStatusOrder(c *gin.Context) {
+	orderID := c.Param("id")
+
+	var order models.Order
+	err := utilities.WithRetryTransaction(oc.DB.WithContext(c), "picking-completed", func(tx *gorm.DB) error {
+		order = models.Order{}
+
+		// Find the order, locked until commit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
+			}
+			return err
+		}
+
+		// Check if order is already completed
+		if order.ProcessingStatus == "qc completed" {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeOrderStateInvalid, "Order already finished qc", "this order is already in 'qc completed' status")
+		}
+
+		// Check if order is cancelled
+		if order.EventStatus != nil && *order.EventStatus == "cancelled" {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeOrderStateInvalid, "Order already cancelled", "cannot change status of a cancelled order")
+		}
+
+		// Update order status to "picking completed"
+		order.ProcessingStatus = "picking completed"
+		return tx.Save(&order).Error
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to update order status")
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Order status updated to picking completed", order.ToOrderResponse())
+}
+
+// GetOrderDeliveryHistory godoc
+// @Summary Get order delivery history
+// @Description Get the courier delivery status and checkpoints pulled from the expedition tracking integration for an order.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} utilities.Response{data=OrderDeliveryHistoryResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/orders/{id}/delivery-history [get]
+func (oc *OrderController) GetOrderDeliveryHistory(c *gin.Context) {
+	orderID := c.Param("id")
+
+	var order models.Order
+	if err := oc.DB.WithContext(c).First(&order, orderID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
+			return
+		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order", err.Error())
+		return
+	}
+
+	var histories []models.DeliveryHistory
+	if err := oc.DB.WithContext(c).Where("order_id = ?", order.ID).Order("event_at DESC").Find(&histories).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve delivery history", err.Error())
+		return
+	}
+
+	historyResponses := make([]models.DeliveryHistoryResponse, len(histories))
+	for i, history := range histories {
+		historyResponses[i] = history.ToDeliveryHistoryResponse()
+	}
+
+	orderResponse := order.ToOrderResponse()
+	response := OrderDeliveryHistoryResponse{
+		OrderID:          order.ID,
+		Tracking:         order.Tracking,
+		DeliveryStatus:   order.DeliveryStatus,
+		DeliveryStatusAt: orderResponse.DeliveryStatusAt,
+		History:          historyResponses,
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Order delivery history retrieved successfully", response)
+}
+
+// GetOrderHistory godoc
+// @Summary Get order edit history
+// @Description Get the edits made to an order, newest first, each with the fields and order details it changed (old and new values).
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} utilities.Response{data=OrderHistoryResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/orders/{id}/history [get]
+func (oc *OrderController) GetOrderHistory(c *gin.Context) {
+	orderID := c.Param("id")
+
+	var order models.Order
+	if err := oc.DB.WithContext(c).First(&order, orderID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
+			return
+		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order", err.Error())
+		return
+	}
+
+	var changes []models.OrderChange
+	if err := oc.DB.WithContext(c).Preload("Changer").
+		Where("order_id = ?", order.ID).
+		Order("version DESC, id ASC").
+		Find(&changes).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order history", err.Error())
+		return
+	}
+
+	response := OrderHistoryResponse{
+		OrderID:  order.ID,
+		Tracking: order.Tracking,
+		Version:  order.Version,
+		Edits:    models.GroupOrderEdits(changes),
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Order history retrieved successfully", response)
+}
+
+// GetOrderRevisions godoc
+// @Summary Get order revisions
+// @Description Get the stored copies of an order, newest first. A copy of the whole order with its order details is stored right before every update, cancellation and duplication; buyer phone numbers are stored masked.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utilities.Response{data=OrderRevisionsListResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/orders/{id}/revisions [get]
+func (oc *OrderController) GetOrderRevisions(c *gin.Context) {
+	orderID := c.Param("id")
+
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	var order models.Order
+	if err := oc.DB.WithContext(c).First(&order, orderID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
+			return
+		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order", err.Error())
+		return
+	}
+
+	query := oc.DB.WithContext(c).Model(&models.OrderRevision{}).Where("order_id = ?", order.ID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count order revisions", err.Error())
+		return
+	}
+
+	var revisions []models.OrderRevision
+	if err := query.Preload("Creator").Order("id DESC").Limit(limit).Offset(offset).Find(&revisions).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order revisions", err.Error())
+		return
+	}
+
+	revisionResponses := make([]models.OrderRevisionResponse, len(revisions))
+	for i := range revisions {
+		revisionResponses[i] = revisions[i].ToOrderRevisionResponse()
+	}
+
+	response := OrderRevisionsListResponse{
+		OrderID:   order.ID,
+		Tracking:  order.Tracking,
+		Version:   order.Version,
+		Revisions: revisionResponses,
+		Pagination: utilities.PaginationResponse{
+			Page:  page,
+			Limit: limit,
+			Total: int(total),
+		},
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Order revisions retrieved successfully", response)
+}
+
+// GetOrderRevisionDiff godoc
+// @Summary Compare order revisions
+// @Description Get the fields and order details that differ between two stored copies of an order, or between a stored copy and the order as it is now when to is left out.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param from query int true "Revision ID to compare from"
+// @Param to query int false "Revision ID to compare to, the current order when left out"
+// @Success 200 {object} utilities.Response{data=OrderRevisionDiffResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/orders/{id}/revisions/diff [get]
+func (oc *OrderController) GetOrderRevisionDiff(c *gin.Context) {
+	orderID := c.Param("id")
+
+	fromID, err := strconv.ParseUint(c.Query("from"), 10, 64)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid revision ID", "from must be the ID of an order revision")
+		return
+	}
+	var toID uint64
+	if value := c.Query("to"); value != "" {
+		toID, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid revision ID", "to must be the ID of an order revision")
+			return
+		}
+	}
+
+	var order models.Order
+	if err := oc.DB.WithContext(c).Preload("OrderDetails").First(&order, orderID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
+			return
+		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order", err.Error())
+		return
+	}
+
+	before, ok := oc.loadOrderRevision(c, order.ID, uint(fromID))
+	if !ok {
+		return
+	}
+
+	after := &order
+	response := OrderRevisionDiffResponse{OrderID: order.ID, From: uint(fromID), FromVersion: before.OrderVersion, ToVersion: order.Version}
+	if toID != 0 {
+		revision, ok := oc.loadOrderRevision(c, order.ID, uint(toID))
+		if !ok {
+			return
+		}
+		response.To = revision.ID
+		response.ToVersion = revision.OrderVersion
+		if after, err = revision.Order(); err != nil {
+			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to read order revision", err.Error())
+			return
+		}
+	}
+
+	beforeOrder, err := before.Order()
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to read order revision", err.Error())
 		return
 	}
 
-	// Reload order with all relationships
-	if err := oc.DB.
-		Preload("OrderDetails").
-		Preload("PickOperator.UserRoles.Role").
-		Preload("PickOperator.UserRoles.Assigner").
-		Preload("CancelOperator").
-		First(&order, order.ID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload order", err.Error())
-		return
+	changes := models.DiffOrder(beforeOrder, after)
+	response.Changes = make([]models.OrderChangeResponse, len(changes))
+	for i := range changes {
+		response.Changes[i] = changes[i].ToOrderChangeResponse()
 	}
 
-	// Manually fetch and attach products to order details
-	for i := range order.OrderDetails {
-		var product models.Product
-		if err := oc.DB.Where("sku = ?", order.OrderDetails[i].Sku).First(&product).Error; err == nil {
-			order.OrderDetails[i].Product = &product
+	utilities.SuccessResponse(c, http.StatusOK, "Order revision diff retrieved successfully", response)
+}
+
+// loadOrderRevision loads a revision of an order, responding with the error and returning false
+// when it cannot be found
+func (oc *OrderController) loadOrderRevision(c *gin.Context, orderID, revisionID uint) (*models.OrderRevision, bool) {
+	var revision models.OrderRevision
+	if err := oc.DB.WithContext(c).Where("order_id = ?", orderID).First(&revision, revisionID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeOrderRevisionNotFound, "Order revision not found", fmt.Sprintf("order has no revision with ID %d", revisionID))
+			return nil, false
 		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order revision", err.Error())
+		return nil, false
 	}
-
-	utilities.SuccessResponse(c, http.StatusOK, "Order cancelled successfully", order.ToOrderResponse())
+	return &revision, true
 }
 
-// AssignPicker godoc
-// @Summary Assign a picker to an order
-// @Description Assign a picker to an order, setting assigned_by to current user, assigned_at to now, picked_by to specified picker, and processing_status to "picking process"
+// GetOrderTimeline godoc
+// @Summary Get order timeline
+// @Description Get every workflow event of an order in chronological order with the user who performed it: creation, picker assignment, edits, picking, QC ribbon/online, outbound, courier checkpoints, returns, complains and cancellation
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param request body AssignPickerRequest true "Assign picker request"
-// @Success 200 {object} utilities.Response{data=models.OrderResponse}
-// @Failure 400 {object} utilities.Response
+// @Param id path int true "Order ID"
+// @Success 200 {object} utilities.Response{data=OrderTimelineResponse}
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Failure 404 {object} utilities.Response
-// @Router /api/orders/assign-picker [post]
-func (oc *OrderController) AssignPicker(c *gin.Context) {
-	var req AssignPickerRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utilities.ValidationErrorResponse(c, err)
+// @Router /api/orders/{id}/timeline [get]
+func (oc *OrderController) GetOrderTimeline(c *gin.Context) {
+	orderID := c.Param("id")
+
+	var order models.Order
+	if err := oc.DB.WithContext(c).Scopes(models.VisibleOrders(c.GetUint("user_id"), c.GetStringSlice("roles"))).First(&order, orderID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
+			return
+		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order", err.Error())
 		return
 	}
 
-	// Get current user ID from context (assigner)
-	userIDInterface, exists := c.Get("user_id")
-	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "User not found", "user ID not found in context")
+	events, err := models.BuildOrderTimeline(oc.DB.WithContext(c), &order)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order timeline", err.Error())
 		return
 	}
 
-	userID, ok := userIDInterface.(uint)
-	if !ok {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID", "user ID has invalid type")
-		return
+	response := OrderTimelineResponse{
+		OrderID:  order.ID,
+		Tracking: order.Tracking,
+		Events:   events,
 	}
 
-	// Verify the picker exists
-	var picker models.User
-	if err := oc.DB.First(&picker, req.PickerID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			utilities.ErrorResponse(c, http.StatusNotFound, "Picker not found", "no user found with the specified picker ID")
-			return
-		}
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to find picker", err.Error())
+	utilities.SuccessResponse(c, http.StatusOK, "Order timeline retrieved successfully", response)
+}
+
+// GetOrderLabel godoc
+// @Summary Reprint shipping label
+// @Description Render the shipping label of an order (tracking barcode, buyer, courier, store) as ZPL for thermal printers or as a 4x6 inch PDF. The header and the courier of orders without one come from the store settings.
+// @Tags orders
+// @Produce application/pdf
+// @Produce plain
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param format query string false "Label format: pdf or zpl" default(pdf)
+// @Success 200 {file} file
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/orders/{id}/label [get]
+func (oc *OrderController) GetOrderLabel(c *gin.Context) {
+	orderID := c.Param("id")
+
+	format := strings.ToLower(c.DefaultQuery("format", "pdf"))
+	if format != "pdf" && format != "zpl" {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid label format", "format must be pdf or zpl")
 		return
 	}
 
-	// Find the order by tracking
 	var order models.Order
-	if err := oc.DB.Where("tracking = ?", req.Tracking).First(&order).Error; err != nil {
+	if err := oc.DB.WithContext(c).Preload("OrderDetails").First(&order, orderID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			utilities.ErrorResponse(c, http.StatusNotFound, "Order not found", "no order found with the specified tracking number")
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
 			return
 		}
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to find order", err.Error())
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order", err.Error())
 		return
 	}
 
-	// Check if order is cancelled
+	// A cancelled parcel must not leave the warehouse with a fresh label
 	if order.EventStatus != nil && *order.EventStatus == "cancelled" {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Order already cancelled", "cannot assign picker to a cancelled order")
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeOrderStateInvalid, "Cannot print label for a cancelled order", "order is cancelled")
 		return
 	}
 
-	// Check if order is not "ready to pick"
-	if order.ProcessingStatus != "ready to pick" && order.ProcessingStatus != "pending picking" {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Cannot assign picker", "Only orders that are in 'ready to pick' or 'pending picking' status can be assigned to a picker. Status now is '"+order.ProcessingStatus+"'.")
+	if order.Tracking == "" {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeOrderStateInvalid, "Order has no tracking number", "tracking is empty")
 		return
 	}
 
-	// Update order with assignment details
-	now := time.Now()
-	order.AssignedBy = &userID
-	order.AssignedAt = &now
-	order.PickedBy = &req.PickerID
-	order.ProcessingStatus = "picking process"
+	items := make([]utilities.LabelItem, len(order.OrderDetails))
+	for i, detail := range order.OrderDetails {
+		items[i] = utilities.LabelItem{
+			Sku:      detail.Sku,
+			Variant:  detail.Variant,
+			Quantity: detail.Quantity,
+		}
+	}
 
-	if err := oc.DB.Save(&order).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to assign picker", err.Error())
+	// Stores may brand their labels and name a courier for orders without one
+	settings, err := models.FindStoreSettings(oc.DB.WithContext(c), order.Store)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve store settings", err.Error())
 		return
 	}
+	courier := order.Courier
+	if courier == "" {
+		courier = settings.DefaultCourier
+	}
 
-	// Reload order with all relationships
-	if err := oc.DB.
-		Preload("OrderDetails").
-		Preload("PickOperator.UserRoles.Role").
-		Preload("PickOperator.UserRoles.Assigner").
-		Preload("AssignOperator").
-		First(&order, order.ID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload order", err.Error())
+	label := utilities.NewLabelData(utilities.LabelData{
+		Header:       settings.LabelHeader,
+		Tracking:     order.Tracking,
+		Courier:      courier,
+		Store:        order.Store,
+		Channel:      order.Channel,
+		Buyer:        order.Buyer,
+		Address:      order.Address,
+		OrderGineeID: order.OrderGineeID,
+		SentBefore:   order.SentBefore.Format("2006-01-02 15:04"),
+		PrintedAt:    time.Now().Format("2006-01-02 15:04:05"),
+	}, items)
+
+	filename := fmt.Sprintf("label-%s", order.Tracking)
+
+	if format == "zpl" {
+		zpl, err := utilities.RenderLabelZPL(label)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusInternalServerError, utilities.ErrCodeLabelRenderFailed, "Failed to render label", err.Error())
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%s.zpl", filename))
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", zpl)
 		return
 	}
 
-	// Manually fetch and attach products to order details
-	for i := range order.OrderDetails {
-		var product models.Product
-		if err := oc.DB.Where("sku = ?", order.OrderDetails[i].Sku).First(&product).Error; err == nil {
-			order.OrderDetails[i].Product = &product
-		}
+	pdf, err := utilities.RenderLabelPDF(label)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeLabelRenderFailed, "Failed to render label", err.Error())
+		return
 	}
 
-	utilities.SuccessResponse(c, http.StatusOK, "Picker assigned successfully", order.ToOrderResponse())
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%s.pdf", filename))
+	c.Data(http.StatusOK, "application/pdf", pdf)
 }
 
-// PendingPickOrders godoc
-// @Summary Get orders pending pick assignment
-// @Description Pending order that already assigned to a picker, but not picked yet.
+// RevealOrderContact godoc
+// @Summary Reveal order contact
+// @Description Return the unmasked buyer contact of an order. Every reveal is logged with the user, the reason and the client IP.
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param id path int true "Order ID to pending picking process"
-// @Success 200 {object} utilities.Response{data=models.OrderResponse}
+// @Param id path int true "Order ID"
+// @Param request body RevealOrderContactRequest true "Reveal reason"
+// @Success 200 {object} utilities.Response{data=OrderContactResponse}
 // @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Failure 404 {object} utilities.Response
-// @Router /api/orders/{id}/pending-pick [put]
-func (oc *OrderController) PendingPickOrders(c *gin.Context) {
+// @Router /api/orders/{id}/contact/reveal [post]
+func (oc *OrderController) RevealOrderContact(c *gin.Context) {
 	orderID := c.Param("id")
+	userID := c.GetUint("user_id")
 
-	// Get current user ID from context (pending operator)
-	userIDInterface, exists := c.Get("user_id")
-	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "User not found", "user ID not found in context")
+	var req RevealOrderContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
 		return
 	}
 
-	userID, ok := userIDInterface.(uint)
-	if !ok {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID", "user ID has invalid type")
+	req.Reason = strings.TrimSpace(req.Reason)
+	if len(req.Reason) < 5 {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeReasonRequired, "Reason is required", "reason must be at least 5 characters")
 		return
 	}
 
-	// Find the order
 	var order models.Order
-	if err := oc.DB.First(&order, orderID).Error; err != nil {
+	if err := oc.DB.WithContext(c).First(&order, orderID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			utilities.ErrorResponse(c, http.StatusNotFound, "Order not found", "no order found with the specified ID")
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "no order found with the specified ID")
 			return
 		}
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to find order", err.Error())
-		return
-	}
-
-	// Check if status order is "picking process"
-	if order.ProcessingStatus != "picking process" {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Order not in picking process", "only orders in 'picking process' status can be set to pending pick")
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order", err.Error())
 		return
 	}
 
-	// Update order with pending pick details
-	now := time.Now()
-	order.ProcessingStatus = "pending picking"
-	order.PendingBy = &userID // Set pending operator
-	order.PendingAt = &now
-	order.PickedBy = nil   // Clear picked_by since it's pending
-	order.AssignedBy = nil // Clear assigned_by since it's pending
-	order.AssignedAt = nil // Clear assigned_at since it's pending
-
-	if err := oc.DB.Save(&order).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to set order to pending pick", err.Error())
-		return
+	// No audit entry, no contact
+	reveal := models.ContactReveal{
+		OrderID:   order.ID,
+		UserID:    userID,
+		Reason:    req.Reason,
+		IPAddress: c.ClientIP(),
 	}
-
-	// Reload order with all relationships
-	if err := oc.DB.
-		Preload("OrderDetails").
-		Preload("PickOperator.UserRoles.Role").
-		Preload("PickOperator.UserRoles.Assigner").
-		Preload("PendingOperator").
-		First(&order, order.ID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload order", err.Error())
+	if err := oc.DB.WithContext(c).Create(&reveal).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to log contact reveal", err.Error())
 		return
 	}
 
-	// Manually fetch and attach products to order details
-	for i := range order.OrderDetails {
-		var product models.Product
-		if err := oc.DB.Where("sku = ?", order.OrderDetails[i].Sku).First(&product).Error; err == nil {
-			order.OrderDetails[i].Product = &product
-		}
+	response := OrderContactResponse{
+		OrderID:    order.ID,
+		Tracking:   order.Tracking,
+		Buyer:      order.Buyer,
+		BuyerPhone: order.BuyerPhone,
+		Address:    order.Address,
 	}
 
-	utilities.SuccessResponse(c, http.StatusOK, "Order set to pending pick successfully", order.ToOrderResponse())
+	utilities.SuccessResponse(c, http.StatusOK, "Order contact revealed", response)
 }
 
-// GetAssignedOrders godoc
-// @Summary Get orders assigned to all pickers
-// @Description Retrieve all orders currently assigned to all pickers that are in "picking process" status. and filter by current date.
+// GetContactReveals godoc
+// @Summary Get contact reveal log
+// @Description Get who revealed order contacts, when and why, newest first
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
-// @Param search query string false "Search by Order Ginee ID or Tracking number"
-// @Success 200 {object} utilities.Response{data=[]models.OrderResponse}
-// @Failure 400 {object} utilities.Response
+// @Param order_id query int false "Filter by order ID"
+// @Param user_id query int false "Filter by user ID"
+// @Success 200 {object} utilities.Response{data=ContactRevealsListResponse}
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
-// @Router /api/orders/assigned [get]
-func (oc *OrderController) GetAssignedOrders(c *gin.Context) {
+// @Router /api/orders/contact-reveals [get]
+func (oc *OrderController) GetContactReveals(c *gin.Context) {
 	// Parse pagination parameters
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	offset := (page - 1) * limit
 
-	// Parse search parameter (optional)
-	search := c.Query("search")
-
-	var orders []models.Order
-	var total int64
-
-	// Build query with necessary preloads and filters
-	query := oc.DB.Model(&models.Order{}).
-		Where("processing_status = ?", "picking process")
-
-	if search != "" {
-		// Search by Order Ginee ID or Tracking number
-		query = query.Where("order_ginee_id LIKE ? OR tracking LIKE ?", "%"+search+"%", "%"+search+"%")
+	query := oc.DB.WithContext(c).Model(&models.ContactReveal{})
+	if orderID := c.Query("order_id"); orderID != "" {
+		query = query.Where("order_id = ?", orderID)
+	}
+	if userID := c.Query("user_id"); userID != "" {
+		query = query.Where("user_id = ?", userID)
 	}
 
-	// Get total count with filters
+	var total int64
 	if err := query.Count(&total).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count orders", err.Error())
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count contact reveals", err.Error())
 		return
 	}
 
-	// Get assigned orders with pagination
-	if err := query.Order("assigned_at DESC").
-		Preload("OrderDetails").
-		Preload("PickOperator").
-		Preload("AssignOperator").
-		Preload("ChangeOperator").
-		Preload("PendingOperator").
-		Preload("CancelOperator").
-		Limit(limit).Offset(offset).Find(&orders).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve orders", err.Error())
+	var reveals []models.ContactReveal
+	if err := query.Preload("Order").Preload("User").Order("created_at DESC").Limit(limit).Offset(offset).Find(&reveals).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve contact reveals", err.Error())
 		return
 	}
 
-	// Manually fetch and attach products to order details
-	for i := range orders {
-		for j := range orders[i].OrderDetails {
-			var product models.Product
-			if err := oc.DB.Where("sku = ?", orders[i].OrderDetails[j].Sku).First(&product).Error; err == nil {
-				orders[i].OrderDetails[j].Product = &product
-			}
-		}
+	revealResponses := make([]models.ContactRevealResponse, len(reveals))
+	for i, reveal := range reveals {
+		revealResponses[i] = reveal.ToContactRevealResponse()
 	}
 
-	// Convert orders to response format
-	orderResponses := make([]models.OrderResponse, len(orders))
-	for i, order := range orders {
-		orderResponses[i] = order.ToOrderResponse()
+	response := ContactRevealsListResponse{
+		Reveals: revealResponses,
+		Pagination: utilities.PaginationResponse{
+			Page:  page,
+			Limit: limit,
+			Total: int(total),
+		},
 	}
 
-	// Return success response
-	utilities.SuccessResponse(c, http.StatusOK, "Assigned orders retrieved successfully", orderResponses)
+	utilities.SuccessResponse(c, http.StatusOK, "Contact reveals retrieved successfully", response)
 }
 
-// QCProcessStatusOrder godoc
-// @Summary Change operation status order to "qc process"
-// @Description Change operation status order to "qc process" when starting qc process.
+// RenumberTrackings godoc
+// @Summary Renumber order trackings
+// @Description Change the tracking of several orders at once, moving their QC, outbound, return, complain and delivery history records along. This is the only way to change a tracking those records reference. Either every rename succeeds or none does.
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param id path int true "Order ID to change to qc process"
-// @Success 200 {object} utilities.Response{data=models.OrderResponse}
+// @Param request body RenumberTrackingsRequest true "Renumber trackings request"
+// @Success 200 {object} utilities.Response{data=[]RenumberedTracking}
 // @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Failure 404 {object} utilities.Response
-// @Router /api/orders/{id}/qc-process [put]
-func (oc *OrderController) QCProcessStatusOrder(c *gin.Context) {
-	orderID := c.Param("id")
+// @Failure 409 {object} utilities.Response
+// @Router /api/orders/renumber-trackings [post]
+func (oc *OrderController) RenumberTrackings(c *gin.Context) {
+	userID := c.GetUint("user_id")
 
-	// Find the order
-	var order models.Order
-	if err := oc.DB.First(&order, orderID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			utilities.ErrorResponse(c, http.StatusNotFound, "Order not found", "no order found with the specified ID")
-			return
-		}
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order", err.Error())
+	var req RenumberTrackingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
 		return
 	}
 
-	// Check if order is already in qc process
-	if order.ProcessingStatus == "qc process" {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Order already in QC process", "this order is already in 'qc process' status")
-		return
-	}
+	// Normalize trackings and reject renames that depend on each other
+	sources := make(map[string]bool, len(req.Renames))
+	targets := make(map[string]bool, len(req.Renames))
+	for i := range req.Renames {
+		rename := &req.Renames[i]
+		rename.From = strings.ToUpper(strings.TrimSpace(rename.From))
+		rename.To = strings.ToUpper(strings.TrimSpace(rename.To))
 
-	// Check if order is cancelled
-	if order.EventStatus != nil && *order.EventStatus == "cancelled" {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Order already cancelled", "cannot change status of a cancelled order")
-		return
+		if rename.From == "" || rename.To == "" || rename.From == rename.To {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid rename", fmt.Sprintf("rename %d must change a tracking to a different, non-empty one", i))
+			return
+		}
+		if sources[rename.From] || targets[rename.To] {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid rename", fmt.Sprintf("rename %d repeats tracking %s or %s", i, rename.From, rename.To))
+			return
+		}
+		sources[rename.From] = true
+		targets[rename.To] = true
 	}
-
-	// Update order status to "qc process"
-	order.ProcessingStatus = "qc process"
-	if err := oc.DB.Save(&order).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update order status", err.Error())
-		return
+	for _, rename := range req.Renames {
+		if sources[rename.To] {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid rename", fmt.Sprintf("tracking %s is both renamed and a new tracking, rename in separate requests", rename.To))
+			return
+		}
 	}
 
-	utilities.SuccessResponse(c, http.StatusOK, "Order status updated to QC process", order.ToOrderResponse())
-}
+	results := make([]RenumberedTracking, 0, len(req.Renames))
+	err := utilities.WithTransaction(oc.DB.WithContext(c), func(tx *gorm.DB) error {
+		now := time.Now()
+		for _, rename := range req.Renames {
+			var order models.Order
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("tracking = ?", rename.From).First(&order).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", fmt.Sprintf("no order found with tracking %s", rename.From))
+				}
+				return fmt.Errorf("failed to load order %s: %w", rename.From, err)
+			}
 
-// PickingCompletedStatusOrder godoc
-// @Summary Change operation status order to "picking completed"
-// @Description Change operation status order to "picking completed" when qc process are canceled.
-// @Tags orders
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "Order ID to change to picking completed"
-// @Success 200 {object} utilities.Response{data=models.OrderResponse}
-// @Failure 400 {object} utilities.Response
-// @Failure 401 {object} utilities.Response
-// @Failure 403 {object} utilities.Response
-// @Failure 404 {object} utilities.Response
-// @Router /api/orders/{id}/picking-completed [put]
-func (oc *OrderController) PickingCompletedStatusOrder(c *gin.Context) {
-	orderID := c.Param("id")
+			var taken int64
+			if err := tx.Unscoped().Model(&models.Order{}).Where("tracking = ?", rename.To).Count(&taken).Error; err != nil {
+				return fmt.Errorf("failed to check tracking %s: %w", rename.To, err)
+			}
+			if taken > 0 {
+				return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeDuplicateTracking, "Tracking already exists", fmt.Sprintf("tracking %s is already used by another order", rename.To))
+			}
 
-	// Find the order
-	var order models.Order
-	if err := oc.DB.First(&order, orderID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			utilities.ErrorResponse(c, http.StatusNotFound, "Order not found", "no order found with the specified ID")
-			return
-		}
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order", err.Error())
-		return
-	}
+			references, err := models.FindTrackingReferences(tx, rename.From)
+			if err != nil {
+				return err
+			}
 
-	// Check if order is already completed
-	if order.ProcessingStatus == "qc completed" {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Order already finished qc", "this order is already in 'qc completed' status")
-		return
-	}
+			if err := models.RenameTracking(tx, rename.From, rename.To); err != nil {
+				return err
+			}
 
-	// Check if order is cancelled
-	if order.EventStatus != nil && *order.EventStatus == "cancelled" {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Order already cancelled", "cannot change status of a cancelled order")
-		return
-	}
+			// Bump the version so clients holding the old tracking reload, and record the rename in the history
+			change := models.OrderChange{OrderID: order.ID, Version: order.Version + 1, Field: "tracking", OldValue: rename.From, NewValue: rename.To, ChangedBy: userID}
+			if err := tx.Model(&order).Updates(map[string]interface{}{"changed_by": userID, "changed_at": now}).Error; err != nil {
+				return fmt.Errorf("failed to update order %s: %w", rename.From, err)
+			}
+			if err := tx.Create(&change).Error; err != nil {
+				return fmt.Errorf("failed to record tracking change: %w", err)
+			}
 
-	// Update order status to "picking completed"
-	order.ProcessingStatus = "picking completed"
-	if err := oc.DB.Save(&order).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update order status", err.Error())
+			results = append(results, RenumberedTracking{OrderID: order.ID, From: rename.From, To: rename.To, MovedRecords: references})
+		}
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to renumber trackings")
 		return
 	}
 
-	utilities.SuccessResponse(c, http.StatusOK, "Order status updated to picking completed", order.ToOrderResponse())
+	utilities.SuccessResponse(c, http.StatusOK, utilities.Messagef(c, "%d tracking(s) renumbered successfully", len(results)), results)
 }
 
 // Request and Response Structs
@@ -1233,6 +2912,7 @@ type CreateOrderRequest struct {
 	Channel      string                     `json:"channel" binding:"required" example:"Shopee"`
 	Store        string                     `json:"store" binding:"required" example:"SP deParcelRibbon"`
 	Buyer        string                     `json:"buyer" binding:"required" example:"John Doe"`
+	BuyerPhone   string                     `json:"buyer_phone" example:"081234567890"`
 	Address      string                     `json:"address" binding:"required" example:"123 Main St, City, Country"`
 	Courier      string                     `json:"courier" example:"JNE"`
 	Tracking     string                     `json:"tracking" example:"JNE1234567890"`
@@ -1241,7 +2921,7 @@ type CreateOrderRequest struct {
 }
 
 type CreateOrderDetailRequest struct {
-	Sku         string `json:"sku" binding:"required" example:"PROD001"`
+	Sku         string `json:"sku" binding:"required,sku" example:"PROD001"`
 	ProductName string `json:"product_name" binding:"required" example:"Sample Product"`
 	Variant     string `json:"variant" example:"Red - Size M"`
 	Quantity    int    `json:"quantity" binding:"required,min=1" example:"2"`
@@ -1283,28 +2963,204 @@ type UpdateOrderRequest struct {
 	Channel      string                     `json:"channel" binding:"required" example:"Shopee"`
 	Store        string                     `json:"store" binding:"required" example:"SP deParcelRibbon"`
 	Buyer        string                     `json:"buyer" binding:"required" example:"John Doe"`
+	BuyerPhone   string                     `json:"buyer_phone" example:"081234567890"`
 	Address      string                     `json:"address" binding:"required" example:"123 Main St, City, Country"`
 	Courier      string                     `json:"courier" binding:"required" example:"JNE"`
 	Tracking     string                     `json:"tracking" binding:"required" example:"JNE1234567890"`
 	SentBefore   string                     `json:"sent_before" example:"2023-01-01 12:00:00"`
 	OrderDetails []UpdateOrderDetailRequest `json:"order_details" binding:"required,min=1"`
+	Version      uint                       `json:"version" binding:"required" example:"1"` // Version of the order the edit is based on
 }
 
 type UpdateOrderDetailRequest struct {
 	ID          uint   `json:"id" example:"1"` // 0 for new product, existing ID for update
-	Sku         string `json:"sku" binding:"required" example:"PROD001"`
+	Sku         string `json:"sku" binding:"required,sku" example:"PROD001"`
+	ProductName string `json:"product_name" binding:"required" example:"Sample Product"`
+	Variant     string `json:"variant" example:"Red - Size M"`
+	Quantity    int    `json:"quantity" binding:"required,min=1" example:"2"`
+	Price       int    `json:"price" binding:"required,min=0" example:"10000"`
+}
+
+type PatchOrderRequest struct {
+	Channel       *string                   `json:"channel" binding:"omitempty,min=1" example:"Shopee"`
+	Store         *string                   `json:"store" binding:"omitempty,min=1" example:"SP deParcelRibbon"`
+	Buyer         *string                   `json:"buyer" binding:"omitempty,min=1" example:"John Doe"`
+	BuyerPhone    *string                   `json:"buyer_phone" example:"081234567890"`
+	Address       *string                   `json:"address" binding:"omitempty,min=1" example:"123 Main St, City, Country"`
+	Courier       *string                   `json:"courier" binding:"omitempty,min=1" example:"JNE"`
+	Tracking      *string                   `json:"tracking" binding:"omitempty,min=1" example:"JNE1234567890"`
+	SentBefore    *string                   `json:"sent_before" binding:"omitempty,date_time" example:"2023-01-01 12:00:00"`
+	AddDetails    []AddOrderDetailRequest   `json:"add_details" binding:"omitempty,dive"`
+	UpdateDetails []PatchOrderDetailRequest `json:"update_details" binding:"omitempty,dive"`
+	RemoveDetails []uint                    `json:"remove_details" binding:"omitempty,dive,min=1" example:"3"` // IDs of the details to remove
+	Version       uint                      `json:"version" binding:"required" example:"1"`                    // Version of the order the edit is based on
+}
+
+type AddOrderDetailRequest struct {
+	Sku         string `json:"sku" binding:"required,sku" example:"PROD001"`
 	ProductName string `json:"product_name" binding:"required" example:"Sample Product"`
 	Variant     string `json:"variant" example:"Red - Size M"`
 	Quantity    int    `json:"quantity" binding:"required,min=1" example:"2"`
 	Price       int    `json:"price" binding:"required,min=0" example:"10000"`
 }
 
+type PatchOrderDetailRequest struct {
+	ID          uint    `json:"id" binding:"required" example:"1"`
+	Sku         *string `json:"sku" binding:"omitempty,sku" example:"PROD001"`
+	ProductName *string `json:"product_name" binding:"omitempty,min=1" example:"Sample Product"`
+	Variant     *string `json:"variant" example:"Red - Size M"`
+	Quantity    *int    `json:"quantity" binding:"omitempty,min=1" example:"2"`
+	Price       *int    `json:"price" binding:"omitempty,min=0" example:"10000"`
+}
+
+type DuplicateOrderRequest struct {
+	Count int `json:"count" binding:"omitempty,min=1" example:"1"` // Number of copies, defaults to 1
+}
+
 type DuplicateOrderResponse struct {
-	OriginalOrder   models.OrderResponse `json:"original_order"`
-	DuplicatedOrder models.OrderResponse `json:"duplicated_order"`
+	OriginalOrder    models.OrderResponse   `json:"original_order"`
+	DuplicatedOrder  models.OrderResponse   `json:"duplicated_order"` // First copy
+	DuplicatedOrders []models.OrderResponse `json:"duplicated_orders"`
+	Generated        []DuplicateIdentifier  `json:"generated"`
+}
+
+// DuplicateIdentifier is an identifier pair handed out by the duplicate policy
+type DuplicateIdentifier struct {
+	OrderID      uint   `json:"order_id"`
+	Role         string `json:"role" example:"duplicate"` // "original" when the original order was retired under it
+	OrderGineeID string `json:"order_ginee_id"`
+	Tracking     string `json:"tracking"`
+}
+
+type OrderStatusSummaryResponse struct {
+	StartDate string `json:"start_date,omitempty" example:"2025-01-01"`
+	EndDate   string `json:"end_date,omitempty" example:"2025-01-31"`
+	models.OrderStatusSummary
+}
+
+type AutoAssignOrdersRequest struct {
+	Limit         int                      `json:"limit" binding:"omitempty,min=1,max=500" example:"100"` // Ready to pick orders considered, default 100
+	MaxOpenOrders *int                     `json:"max_open_orders" binding:"omitempty,min=0" example:"5"` // Overrides the configured maximum of open orders per picker, 0 for no limit
+	ZoneAffinity  *bool                    `json:"zone_affinity" example:"true"`                          // Overrides the configured zone affinity
+	Apply         bool                     `json:"apply" example:"false"`                                 // Save the assignment instead of only proposing it
+	Assignments   []AutoAssignConfirmation `json:"assignments" binding:"omitempty,max=500,dive"`          // Confirms these assignments of a proposal instead of planning a new one
+}
+
+type AutoAssignConfirmation struct {
+	OrderID  uint `json:"order_id" binding:"required" example:"1"`
+	Version  uint `json:"version" binding:"required" example:"1"` // Order version of the proposal
+	PickerID uint `json:"picker_id" binding:"required" example:"7"`
+}
+
+type AutoAssignOrdersResponse struct {
+	Applied     bool                          `json:"applied"` // Whether the assignments were saved
+	Summary     BulkAssignSummary             `json:"summary"`
+	Pickers     []models.AutoAssignPicker     `json:"pickers"`
+	Assignments []models.AutoAssignment       `json:"assignments"` // Proposed, or saved when applied
+	Unassigned  []models.AutoAssignUnassigned `json:"unassigned"`  // Orders no picker could take
+	Skipped     []SkippedAssignment           `json:"skipped"`     // Orders that changed before they were saved
+	Failed      []FailedAssignment            `json:"failed"`
 }
 
 type AssignPickerRequest struct {
 	PickerID uint   `json:"picker_id" binding:"required" example:"1"`
 	Tracking string `json:"tracking" binding:"required" example:"JNE1234567890"`
+	Version  uint   `json:"version" binding:"required" example:"1"` // Version of the order the assignment is based on
+}
+
+type CancelOrderRequest struct {
+	Version uint `json:"version" binding:"required" example:"1"` // Version of the order the cancellation is based on
+}
+
+type HoldOrderRequest struct {
+	Reason  string `json:"reason" binding:"required,max=255" example:"Buyer is changing the address"`
+	Version uint   `json:"version" binding:"required" example:"1"` // Version of the order the hold is based on
+}
+
+type UnholdOrderRequest struct {
+	Version uint `json:"version" binding:"required" example:"1"` // Version of the order the release is based on
+}
+
+type MergeOrdersRequest struct {
+	OrderID      uint   `json:"order_id" binding:"required" example:"1"`                    // Canonical order, keeps its tracking
+	Version      uint   `json:"version" binding:"required" example:"1"`                     // Version of the canonical order the merge is based on
+	MergeOrderID uint   `json:"merge_order_id" binding:"required" example:"2"`              // Order merged away
+	MergeVersion uint   `json:"merge_version" binding:"required" example:"1"`               // Version of the merged order the merge is based on
+	Quantities   string `json:"quantities" binding:"omitempty,oneof=max sum" example:"max"` // Same SKU lines: "max" (default) or "sum"
+}
+
+type MergeOrdersResponse struct {
+	Order       models.OrderResponse `json:"order"`        // Canonical order, with the changes of the merge
+	MergedOrder models.OrderResponse `json:"merged_order"` // Order merged away
+}
+
+type RenumberTrackingsRequest struct {
+	Renames []TrackingRename `json:"renames" binding:"required,min=1,max=500,dive"`
+}
+
+type TrackingRename struct {
+	From string `json:"from" binding:"required" example:"JNE1234567890"`
+	To   string `json:"to" binding:"required" example:"JNE1234567899"`
+}
+
+type RenumberedTracking struct {
+	OrderID      uint     `json:"order_id"`
+	From         string   `json:"from"`
+	To           string   `json:"to"`
+	MovedRecords []string `json:"moved_records" example:"qc-ribbon,outbound"` // Modules whose records followed the rename
+}
+
+type OrderDeliveryHistoryResponse struct {
+	OrderID          uint                             `json:"order_id"`
+	Tracking         string                           `json:"tracking"`
+	DeliveryStatus   string                           `json:"delivery_status"`
+	DeliveryStatusAt string                           `json:"delivery_status_at"`
+	History          []models.DeliveryHistoryResponse `json:"history"`
+}
+
+type OrderHistoryResponse struct {
+	OrderID  uint                       `json:"order_id"`
+	Tracking string                     `json:"tracking"`
+	Version  uint                       `json:"version"`
+	Edits    []models.OrderEditResponse `json:"edits"`
+}
+
+type OrderRevisionsListResponse struct {
+	OrderID    uint                           `json:"order_id"`
+	Tracking   string                         `json:"tracking"`
+	Version    uint                           `json:"version"` // Current version of the order
+	Revisions  []models.OrderRevisionResponse `json:"revisions"`
+	Pagination utilities.PaginationResponse   `json:"pagination"`
+}
+
+type OrderRevisionDiffResponse struct {
+	OrderID     uint                         `json:"order_id"`
+	From        uint                         `json:"from"`
+	To          uint                         `json:"to"` // 0 when compared to the current order
+	FromVersion uint                         `json:"from_version"`
+	ToVersion   uint                         `json:"to_version"`
+	Changes     []models.OrderChangeResponse `json:"changes"`
+}
+
+type OrderTimelineResponse struct {
+	OrderID  uint                   `json:"order_id"`
+	Tracking string                 `json:"tracking"`
+	Events   []models.TimelineEvent `json:"events"`
+}
+
+type RevealOrderContactRequest struct {
+	Reason string `json:"reason" binding:"required" example:"Courier could not reach the buyer"`
+}
+
+type OrderContactResponse struct {
+	OrderID    uint   `json:"order_id"`
+	Tracking   string `json:"tracking"`
+	Buyer      string `json:"buyer"`
+	BuyerPhone string `json:"buyer_phone"`
+	Address    string `json:"address"`
+}
+
+type ContactRevealsListResponse struct {
+	Reveals    []models.ContactRevealResponse `json:"reveals"`
+	Pagination utilities.PaginationResponse   `json:"pagination"`
 }
@@ -1,7 +1,10 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"livo-backend/models"
+	"livo-backend/repositories"
 	"livo-backend/utilities"
 	"net/http"
 	"strconv"
@@ -10,15 +13,33 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OutboundController struct {
-	DB *gorm.DB
+	DB     *gorm.DB
+	Orders *repositories.OrderRepository
 }
 
 // NewOutboundController creates a new outbound controller
 func NewOutboundController(db *gorm.DB) *OutboundController {
-	return &OutboundController{DB: db}
+	return &OutboundController{DB: db, Orders: repositories.NewOrderRepository(db)}
+}
+
+// attachOrder sets the order of an outbound with its products, leaving outbounds whose order is
+// missing or cannot be loaded as they are
+func (oc *OutboundController) attachOrder(c *gin.Context, outbound *models.Outbound) {
+	if outbound.Tracking == "" {
+		return
+	}
+	order, err := oc.Orders.FindByTracking(c, outbound.Tracking, repositories.OrderPickerRoles)
+	if err != nil {
+		return
+	}
+	if err := oc.Orders.AttachProducts(c, &order); err != nil {
+		return
+	}
+	outbound.Order = &order
 }
 
 // GetOutbounds godoc
@@ -31,7 +52,10 @@ func NewOutboundController(db *gorm.DB) *OutboundController {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param search query string false "Search by outbound tracking (partial match)"
+// @Param sort_by query string false "Sort keys, comma separated, each optionally suffixed with :asc or :desc (created_at, tracking, expedition, pickup_at)" default(created_at:desc)
+// @Param sort_dir query string false "Direction of sort keys without a suffix: asc or desc" default(asc)
 // @Success 200 {object} utilities.Response{data=OutboundsListResponse}
+// @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Router /api/outbounds [get]
@@ -39,7 +63,7 @@ func (oc *OutboundController) GetOutbounds(c *gin.Context) {
 	// Get user ID from JWT token
 	userID, exists := c.Get("user_id")
 	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User not authenticated")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeAuthRequired, "Unauthorized", "User not authenticated")
 		return
 	}
 
@@ -54,27 +78,36 @@ func (oc *OutboundController) GetOutbounds(c *gin.Context) {
 	var outbounds []models.Outbound
 	var total int64
 
+	// Today in the warehouse timezone, not the database one
+	todayStart, todayEnd := utilities.DayRange(time.Now())
+
 	// Build query with outbound_by and current date filters
-	query := oc.DB.Model(&models.Outbound{}).
+	query := oc.DB.WithContext(c).Model(&models.Outbound{}).
 		Where("outbound_by = ?", userID).
-		Where("DATE(created_at) = CURRENT_DATE")
+		Where("created_at >= ? AND created_at < ?", todayStart, todayEnd)
 
 	if search != "" {
 		// Search by outbound tracking with partial match
 		query = query.Where("tracking ILIKE ?", "%"+search+"%")
 	}
 
+	sortScope, err := utilities.ApplySort(c, models.OutboundSortKeys, "created_at:desc", "outbounds.id")
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid sort", err.Error())
+		return
+	}
+
 	// Get total count with search filter
 	if err := query.Count(&total).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count outbounds", err.Error())
 		return
 	}
 
-	// Get outbounds with pagination, search filter, and order by ID descending
+	// Get outbounds with pagination, search filter, and sorting
 	if err := query.
 		Preload("OutboundOperator.UserRoles.Role").
 		Preload("OutboundOperator.UserRoles.Assigner").
-		Order("id DESC").
+		Scopes(sortScope).
 		Limit(limit).
 		Offset(offset).
 		Find(&outbounds).Error; err != nil {
@@ -82,26 +115,29 @@ func (oc *OutboundController) GetOutbounds(c *gin.Context) {
 		return
 	}
 
-	// Manually load order data for each outbound
+	// Load order data for each outbound
+	trackings := make([]string, 0, len(outbounds))
 	for i := range outbounds {
 		if outbounds[i].Tracking != "" {
-			var order models.Order
-			if err := oc.DB.Where("tracking = ?", outbounds[i].Tracking).
-				Preload("OrderDetails").
-				Preload("PickOperator.UserRoles.Role").
-				Preload("PickOperator.UserRoles.Assigner").
-				First(&order).Error; err == nil {
-				// Manually fetch and attach products to order details
-				for j := range order.OrderDetails {
-					var product models.Product
-					if err := oc.DB.Where("sku = ?", order.OrderDetails[j].Sku).First(&product).Error; err == nil {
-						order.OrderDetails[j].Product = &product
-					}
-				}
-				outbounds[i].Order = &order
-			}
+			trackings = append(trackings, outbounds[i].Tracking)
 		}
 	}
+	orders, err := oc.Orders.FindByTrackings(c, trackings, repositories.OrderPickerRoles)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve outbounds", err.Error())
+		return
+	}
+	found := make([]*models.Order, 0, len(orders))
+	for _, order := range orders {
+		found = append(found, order)
+	}
+	if err := oc.Orders.AttachProducts(c, found...); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve outbounds", err.Error())
+		return
+	}
+	for i := range outbounds {
+		outbounds[i].Order = orders[outbounds[i].Tracking]
+	}
 
 	// Convert to response format
 	outboundResponse := make([]models.OutboundResponse, len(outbounds))
@@ -119,9 +155,9 @@ func (oc *OutboundController) GetOutbounds(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Outbounds retrieved successfully"
+	message := utilities.Messagef(c, "Outbounds retrieved successfully")
 	if search != "" {
-		message += " (filtered by tracking: " + search + ")"
+		message += utilities.Messagef(c, " (filtered by tracking: %s)", search)
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
@@ -144,31 +180,15 @@ func (oc *OutboundController) GetOutbound(c *gin.Context) {
 	outboundID := c.Param("id")
 
 	var outbound models.Outbound
-	if err := oc.DB.Preload("OutboundOperator.UserRoles.Role").
+	if err := oc.DB.WithContext(c).Preload("OutboundOperator.UserRoles.Role").
 		Preload("OutboundOperator.UserRoles.Assigner").
 		First(&outbound, outboundID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Outbound not found", err.Error())
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeOutboundNotFound, "Outbound not found", "Failed to retrieve outbound")
 		return
 	}
 
 	// Manually load order data
-	if outbound.Tracking != "" {
-		var order models.Order
-		if err := oc.DB.Where("tracking = ?", outbound.Tracking).
-			Preload("OrderDetails").
-			Preload("PickOperator.UserRoles.Role").
-			Preload("PickOperator.UserRoles.Assigner").
-			First(&order).Error; err == nil {
-			// Manually fetch and attach products to order details
-			for i := range order.OrderDetails {
-				var product models.Product
-				if err := oc.DB.Where("sku = ?", order.OrderDetails[i].Sku).First(&product).Error; err == nil {
-					order.OrderDetails[i].Product = &product
-				}
-			}
-			outbound.Order = &order
-		}
-	}
+	oc.attachOrder(c, &outbound)
 
 	utilities.SuccessResponse(c, http.StatusOK, "Outbound retrieved successfully", outbound.ToOutboundResponse())
 }
@@ -198,16 +218,16 @@ func (oc *OutboundController) UpdateOutbound(c *gin.Context) {
 	}
 
 	var outbound models.Outbound
-	if err := oc.DB.Preload("OutboundOperator.UserRoles.Role").
+	if err := oc.DB.WithContext(c).Preload("OutboundOperator.UserRoles.Role").
 		Preload("OutboundOperator.UserRoles.Assigner").
 		First(&outbound, outboundID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Outbound not found", err.Error())
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeOutboundNotFound, "Outbound not found", "Failed to retrieve outbound")
 		return
 	}
 
 	// Check if tracking starts with "TKP0"
 	if len(outbound.Tracking) < 4 || outbound.Tracking[:4] != "TKP0" {
-		utilities.ErrorResponse(c, http.StatusForbidden, "Update not allowed", "Only outbounds with tracking starting with 'TKP0' can be updated")
+		utilities.ErrorResponseCode(c, http.StatusForbidden, utilities.ErrCodeActionNotAllowed, "Update not allowed", "Only outbounds with tracking starting with 'TKP0' can be updated")
 		return
 	}
 
@@ -216,52 +236,39 @@ func (oc *OutboundController) UpdateOutbound(c *gin.Context) {
 	outbound.ExpeditionColor = req.ExpeditionColor
 	outbound.ExpeditionSlug = req.ExpeditionSlug
 
-	if err := oc.DB.Save(&outbound).Error; err != nil {
+	if err := oc.DB.WithContext(c).Save(&outbound).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update outbound", err.Error())
 		return
 	}
 
 	// Load order data after update
-	if outbound.Tracking != "" {
-		var order models.Order
-		if err := oc.DB.Where("tracking = ?", outbound.Tracking).
-			Preload("OrderDetails").
-			Preload("PickOperator.UserRoles.Role").
-			Preload("PickOperator.UserRoles.Assigner").
-			First(&order).Error; err == nil {
-			// Manually fetch and attach products to order details
-			for i := range order.OrderDetails {
-				var product models.Product
-				if err := oc.DB.Where("sku = ?", order.OrderDetails[i].Sku).First(&product).Error; err == nil {
-					order.OrderDetails[i].Product = &product
-				}
-			}
-			outbound.Order = &order
-		}
-	}
+	oc.attachOrder(c, &outbound)
 
 	utilities.SuccessResponse(c, http.StatusOK, "Outbound updated successfully", outbound.ToOutboundResponse())
 }
 
 // CreateOutbound godoc
 // @Summary Create new outbound
-// @Description Create a new outbound with automatic expedition detection.
+// @Description Create a new outbound with automatic expedition detection. Cancelled orders are rejected. When the tracking belongs to another expedition than the order's courier the scan is blocked with 409 and an alert is raised; a coordinator overriding the alert gets a token, and the scan goes through when sent again with it as override_token. The same operator scanning the same tracking again within the double scan window gets the existing record back with 200.
 // @Tags outbounds
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param outbound body CreateOutboundRequest true "Create Outbound Request"
+// @Success 200 {object} utilities.Response{data=models.OutboundResponse}
 // @Success 201 {object} utilities.Response{data=models.OutboundResponse}
 // @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
 // @Failure 500 {object} utilities.Response
 // @Router /api/outbounds [post]
 func (oc *OutboundController) CreateOutbound(c *gin.Context) {
 	// Get user ID from JWT token
 	userID, exists := c.Get("user_id")
 	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User not authenticated")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeAuthRequired, "Unauthorized", "User not authenticated")
 		return
 	}
 
@@ -283,35 +290,86 @@ func (oc *OutboundController) CreateOutbound(c *gin.Context) {
 
 	// Check if tracking exists in orders table first
 	var order models.Order
-	if err := oc.DB.Where("tracking = ?", req.Tracking).First(&order).Error; err != nil {
+	if err := oc.DB.WithContext(c).Where("tracking = ?", req.Tracking).First(&order).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			utilities.ErrorResponse(c, http.StatusNotFound, "Order not found", "No order found with the specified tracking number")
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "No order found with the specified tracking number")
 			return
 		}
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to check order", err.Error())
 		return
 	}
 
+	// A cancelled order must not be sent out
+	if order.IsCancelled() {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeOrderCancelled, "Order is cancelled", "the order of this tracking was cancelled and cannot be sent out")
+		return
+	}
+
+	// A held order waits until it is released
+	if order.IsHeld() {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeOrderHeld, "Order is on hold", "the order of this tracking is on hold and cannot be sent out until it is released")
+		return
+	}
+
 	// Check if tracking exists in QC-Ribbon OR QC-Online (Quality Control process)
 	var qcRibbon models.QcRibbon
 	var qcOnline models.QcOnline
 
-	qcRibbonExists := oc.DB.Where("tracking = ?", req.Tracking).First(&qcRibbon).Error == nil
-	qcOnlineExists := oc.DB.Where("tracking = ?", req.Tracking).First(&qcOnline).Error == nil
+	qcRibbonExists := oc.DB.WithContext(c).Where("tracking = ?", req.Tracking).First(&qcRibbon).Error == nil
+	qcOnlineExists := oc.DB.WithContext(c).Where("tracking = ?", req.Tracking).First(&qcOnline).Error == nil
 
 	// Tracking must exist in either QC-Ribbon OR QC-Online
 	if !qcRibbonExists && !qcOnlineExists {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "QC process required", "Tracking must go through Quality Control (QC-Ribbon or QC-Online) before outbound")
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeOrderStateInvalid, "QC process required", "Tracking must go through Quality Control (QC-Ribbon or QC-Online) before outbound")
 		return
 	}
 
-	// Check for duplicate tracking
+	// Check for duplicate tracking, scanning a parcel that already left raises an alert for coordinators
 	var existing models.Outbound
-	if err := oc.DB.Where("tracking = ?", req.Tracking).First(&existing).Error; err == nil {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Tracking already exists", "An outbound with this tracking number already exists")
+	if err := oc.DB.WithContext(c).Where("tracking = ?", req.Tracking).First(&existing).Error; err == nil {
+		// A double scan by the same operator moments later is not a parcel leaving twice
+		if models.IsRepeatScan(existing.OutboundBy, existing.CreatedAt, userIDUint, time.Now()) {
+			oc.DB.WithContext(c).Preload("OutboundOperator.UserRoles.Role").
+				Preload("OutboundOperator.UserRoles.Assigner").
+				First(&existing, existing.ID)
+			existing.Order = &order
+
+			utilities.SuccessResponse(c, http.StatusOK, "Outbound already recorded", existing.ToOutboundResponse())
+			return
+		}
+
+		alert, err := models.RaiseOutboundAlert(oc.DB.WithContext(c), &order, models.OutboundAlertAlreadyShipped, &existing, userIDUint)
+		if err != nil {
+			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to raise duplicate parcel alert", err.Error())
+			return
+		}
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeDuplicateTracking, "Tracking already exists", fmt.Sprintf("An outbound with this tracking number already exists, alert #%d raised", alert.ID))
 		return
 	}
 
+	// Block the scan when another order of the duplicate chain already left, unless a coordinator overrode it
+	var override *models.OutboundAlert
+	sibling, err := models.FindShippedSibling(oc.DB.WithContext(c), &order)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to check duplicate orders", err.Error())
+		return
+	}
+	if sibling != nil {
+		var overridden models.OutboundAlert
+		if err := oc.DB.WithContext(c).Where("tracking = ? AND reason = ? AND status = ? AND outbound_id IS NULL", req.Tracking, models.OutboundAlertSiblingShipped, models.OutboundAlertOverridden).
+			First(&overridden).Error; err == nil {
+			override = &overridden
+		} else {
+			alert, err := models.RaiseOutboundAlert(oc.DB.WithContext(c), &order, models.OutboundAlertSiblingShipped, sibling, userIDUint)
+			if err != nil {
+				utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to raise duplicate parcel alert", err.Error())
+				return
+			}
+			utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeDuplicateParcel, "Duplicate parcel blocked", fmt.Sprintf("Duplicate order %s already left on %s, coordinator override required (alert #%d)", sibling.Tracking, sibling.CreatedAt.Format("2006-01-02 15:04"), alert.ID))
+			return
+		}
+	}
+
 	var expedition string
 	var expeditionColor string
 	var expeditionSlug string
@@ -323,31 +381,56 @@ func (oc *OutboundController) CreateOutbound(c *gin.Context) {
 		expeditionSlug = req.ExpeditionSlug
 	} else {
 		// Auto-detect expedition based on tracking prefix
-		var expeditions []models.Expedition
-		if err := oc.DB.Find(&expeditions).Error; err != nil {
+		exp, err := models.FindExpeditionByTracking(oc.DB.WithContext(c), req.Tracking)
+		if err != nil {
 			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve expeditions", err.Error())
 			return
 		}
 
-		expeditionFound := false
+		// If no expedition found based on prefix, return error
+		if exp == nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidTracking, "Invalid tracking code", "Tracking number does not match any known expedition prefix")
+			return
+		}
+
+		// Reject mis-scans that break the courier's tracking format
+		if err := exp.ValidateTracking(req.Tracking); err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidTracking, "Invalid tracking number", err.Error())
+			return
+		}
+
+		expedition = exp.Name
+		expeditionColor = exp.Color
+		expeditionSlug = exp.Slug
+	}
 
-		// Check each expedition code to see if tracking starts with it
-		for _, exp := range expeditions {
-			if len(req.Tracking) >= len(exp.Code) &&
-				req.Tracking[:len(exp.Code)] == exp.Code {
-				expedition = exp.Name
-				expeditionColor = exp.Color
-				expeditionSlug = exp.Slug
-				expeditionFound = true
-				break
+	// The label must be of the courier the order ships with, a mismatch needs the token of a
+	// coordinator's override
+	var courierOverride *models.OutboundAlert
+	courierExpedition, err := models.FindCourierMismatch(oc.DB.WithContext(c), &order, expedition)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to check order courier", err.Error())
+		return
+	}
+	if courierExpedition != nil {
+		token := strings.ToUpper(strings.TrimSpace(req.OverrideToken))
+		if token == "" {
+			alert, err := models.RaiseCourierMismatchAlert(oc.DB.WithContext(c), &order, expedition, order.Courier, userIDUint)
+			if err != nil {
+				utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to raise courier mismatch alert", err.Error())
+				return
 			}
+			utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeCourierMismatch, "Courier mismatch", fmt.Sprintf("Tracking is %s but the order ships with %s, coordinator override token required (alert #%d)", expedition, order.Courier, alert.ID))
+			return
 		}
 
-		// If no expedition found based on prefix, return error
-		if !expeditionFound {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid tracking code", "Tracking number does not match any known expedition prefix")
+		var overridden models.OutboundAlert
+		if err := oc.DB.WithContext(c).Where("tracking = ? AND reason = ? AND status = ? AND outbound_id IS NULL AND override_token = ?", req.Tracking, models.OutboundAlertCourierMismatch, models.OutboundAlertOverridden, token).
+			First(&overridden).Error; err != nil {
+			utilities.ErrorResponseCode(c, http.StatusForbidden, utilities.ErrCodeInvalidOverrideToken, "Invalid override token", "the token does not belong to an overridden courier mismatch alert of this tracking")
 			return
 		}
+		courierOverride = &overridden
 	}
 
 	outbound := models.Outbound{
@@ -358,43 +441,354 @@ func (oc *OutboundController) CreateOutbound(c *gin.Context) {
 		ExpeditionSlug:  expeditionSlug,
 	}
 
-	// Create outbound
-	if err := oc.DB.Create(&outbound).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create outbound", err.Error())
-		return
-	}
+	err = utilities.WithRetryTransaction(oc.DB.WithContext(c), "create-outbound", func(tx *gorm.DB) error {
+		// A failed attempt may have assigned an ID before rolling back
+		outbound.ID = 0
 
-	// Update order processing_status to "outbound completed"
-	if err := oc.DB.Model(&models.Order{}).Where("tracking = ?", req.Tracking).Update("processing_status", "outbound completed").Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update order status", err.Error())
+		// The order may have been cancelled or held since it was checked
+		if err := models.LockOrderForOutbound(tx, req.Tracking); err != nil {
+			if errors.Is(err, models.ErrOrderCancelled) {
+				return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeOrderCancelled, "Order is cancelled", "the order of this tracking was cancelled and cannot be sent out")
+			}
+			if errors.Is(err, models.ErrOrderHeld) {
+				return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeOrderHeld, "Order is on hold", "the order of this tracking is on hold and cannot be sent out until it is released")
+			}
+			return fmt.Errorf("failed to lock order: %w", err)
+		}
+
+		// Put the parcel on the next courier pickup with room left
+		if err := models.AssignPickupSlot(tx, &outbound, utilities.Now()); err != nil {
+			return fmt.Errorf("failed to assign pickup slot: %w", err)
+		}
+
+		// Create outbound
+		if err := tx.Create(&outbound).Error; err != nil {
+			return fmt.Errorf("failed to create outbound: %w", err)
+		}
+
+		// An override is good for a single outbound
+		for _, used := range []*models.OutboundAlert{override, courierOverride} {
+			if used == nil {
+				continue
+			}
+			if err := tx.Model(used).Update("outbound_id", outbound.ID).Error; err != nil {
+				return fmt.Errorf("failed to link outbound alert: %w", err)
+			}
+		}
+
+		// Update order processing_status to "outbound completed"
+		if err := tx.Model(&models.Order{}).Where("tracking = ?", req.Tracking).Update("processing_status", "outbound completed").Error; err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to create outbound")
 		return
 	}
 
 	// Load the created outbound with user relationship
-	oc.DB.Preload("OutboundOperator.UserRoles.Role").
+	oc.DB.WithContext(c).Preload("OutboundOperator.UserRoles.Role").
 		Preload("OutboundOperator.UserRoles.Assigner").
 		First(&outbound, outbound.ID)
 
 	// Load order data if exists
-	if outbound.Tracking != "" {
-		var order models.Order
-		if err := oc.DB.Where("tracking = ?", outbound.Tracking).
-			Preload("OrderDetails").
-			Preload("PickOperator.UserRoles.Role").
-			Preload("PickOperator.UserRoles.Assigner").
-			First(&order).Error; err == nil {
-			// Manually fetch and attach products to order details
-			for i := range order.OrderDetails {
-				var product models.Product
-				if err := oc.DB.Where("sku = ?", order.OrderDetails[i].Sku).First(&product).Error; err == nil {
-					order.OrderDetails[i].Product = &product
+	oc.attachOrder(c, &outbound)
+
+	utilities.SuccessResponse(c, http.StatusCreated, "Outbound created successfully", outbound.ToOutboundResponse())
+}
+
+// BulkCreateOutbounds godoc
+// @Summary Bulk create outbounds
+// @Description Scan a burst of parcels in one call. Each tracking is checked like a single scan (order exists and is not cancelled, went through QC, not shipped yet, no shipped duplicate order, label of the order's courier) and gets its expedition detected from its prefix. Trackings starting with "TKP0" use the expedition sent in the request. Returns per-tracking results; a failing tracking does not stop the others and is kept as a bulk failure for retrying.
+// @Tags outbounds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body BulkCreateOutboundRequest true "Bulk Create Outbound Request"
+// @Success 201 {object} utilities.Response{data=BulkCreateOutboundResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 500 {object} utilities.Response
+// @Router /api/outbounds/bulk [post]
+func (oc *OutboundController) BulkCreateOutbounds(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req BulkCreateOutboundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	response, err := oc.bulkCreateOutbounds(c, userID, req)
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to scan outbounds")
+		return
+	}
+
+	// Keep failed trackings for retrying once the response is gone
+	failures := make([]models.BulkFailure, 0, len(response.FailedTrackings))
+	for _, failed := range response.FailedTrackings {
+		payload := BulkCreateOutboundRequest{
+			Trackings:       []string{failed.Tracking},
+			Expedition:      req.Expedition,
+			ExpeditionColor: req.ExpeditionColor,
+			ExpeditionSlug:  req.ExpeditionSlug,
+		}
+		failures = append(failures, models.NewBulkFailure(models.BulkOperationOutboundCreate, failed.Index, failed.Tracking, payload, failed.Error, userID))
+	}
+	models.RecordBulkFailures(oc.DB.WithContext(c), failures)
+
+	// Determine response status
+	statusCode := http.StatusCreated
+	message := "Bulk outbound scan completed"
+
+	if len(response.CreatedOutbounds) == 0 {
+		if len(response.SkippedTrackings) > 0 && len(response.FailedTrackings) == 0 {
+			statusCode = http.StatusOK
+			message = "All trackings were skipped (already sent out)"
+		} else {
+			statusCode = http.StatusBadRequest
+			message = "No outbounds could be created"
+		}
+	} else if len(response.FailedTrackings) > 0 || len(response.SkippedTrackings) > 0 {
+		message = "Bulk outbound scan completed with some issues"
+	}
+
+	utilities.SuccessResponse(c, statusCode, message, response)
+}
+
+// bulkCreateOutbounds scans the trackings of a bulk request one by one, userID is the operator. The
+// error is only set when the batch lookups fail, per-tracking problems end up in the response.
+func (oc *OutboundController) bulkCreateOutbounds(c *gin.Context, userID uint, req BulkCreateOutboundRequest) (BulkCreateOutboundResponse, error) {
+	trackings := make([]string, len(req.Trackings))
+	for i, tracking := range req.Trackings {
+		trackings[i] = strings.ToUpper(strings.TrimSpace(tracking))
+	}
+
+	// Look everything up once for the whole batch
+	var orders []models.Order
+	if err := oc.DB.WithContext(c).Where("tracking IN ?", trackings).Find(&orders).Error; err != nil {
+		return BulkCreateOutboundResponse{}, utilities.NewStatusError(http.StatusInternalServerError, "Failed to check orders", err.Error())
+	}
+	ordersByTracking := make(map[string]*models.Order, len(orders))
+	for i := range orders {
+		ordersByTracking[orders[i].Tracking] = &orders[i]
+	}
+
+	var qcTrackings []string
+	if err := oc.DB.WithContext(c).Raw("SELECT tracking FROM qc_ribbons WHERE tracking IN ? AND deleted_at IS NULL UNION SELECT tracking FROM qc_onlines WHERE tracking IN ? AND deleted_at IS NULL", trackings, trackings).
+		Scan(&qcTrackings).Error; err != nil {
+		return BulkCreateOutboundResponse{}, utilities.NewStatusError(http.StatusInternalServerError, "Failed to check QC records", err.Error())
+	}
+	qcDone := make(map[string]bool, len(qcTrackings))
+	for _, tracking := range qcTrackings {
+		qcDone[tracking] = true
+	}
+
+	var existingOutbounds []models.Outbound
+	if err := oc.DB.WithContext(c).Where("tracking IN ?", trackings).Find(&existingOutbounds).Error; err != nil {
+		return BulkCreateOutboundResponse{}, utilities.NewStatusError(http.StatusInternalServerError, "Failed to check outbounds", err.Error())
+	}
+	existingByTracking := make(map[string]*models.Outbound, len(existingOutbounds))
+	for i := range existingOutbounds {
+		existingByTracking[existingOutbounds[i].Tracking] = &existingOutbounds[i]
+	}
+
+	expeditionMatcher, err := models.LoadExpeditionMatcher(oc.DB.WithContext(c))
+	if err != nil {
+		return BulkCreateOutboundResponse{}, utilities.NewStatusError(http.StatusInternalServerError, "Failed to retrieve expeditions", err.Error())
+	}
+
+	var createdIDs []uint
+	skippedTrackings := []SkippedOutbound{}
+	failedTrackings := []FailedOutbound{}
+	seen := make(map[string]bool, len(trackings))
+
+	for i, tracking := range trackings {
+		if seen[tracking] {
+			skippedTrackings = append(skippedTrackings, SkippedOutbound{Index: i, Tracking: tracking, Reason: "Tracking scanned twice in this batch"})
+			continue
+		}
+		seen[tracking] = true
+
+		order, found := ordersByTracking[tracking]
+		if !found {
+			failedTrackings = append(failedTrackings, FailedOutbound{Index: i, Tracking: tracking, Error: "No order found with the specified tracking number"})
+			continue
+		}
+
+		if order.IsCancelled() {
+			failedTrackings = append(failedTrackings, FailedOutbound{Index: i, Tracking: tracking, Error: "The order was cancelled and cannot be sent out"})
+			continue
+		}
+
+		if order.IsHeld() {
+			failedTrackings = append(failedTrackings, FailedOutbound{Index: i, Tracking: tracking, Error: "The order is on hold and cannot be sent out until it is released"})
+			continue
+		}
+
+		if !qcDone[tracking] {
+			failedTrackings = append(failedTrackings, FailedOutbound{Index: i, Tracking: tracking, Error: "Tracking must go through Quality Control (QC-Ribbon or QC-Online) before outbound"})
+			continue
+		}
+
+		// Scanning a parcel that already left raises an alert for coordinators, unless it is a double scan
+		if existing, shipped := existingByTracking[tracking]; shipped {
+			if models.IsRepeatScan(existing.OutboundBy, existing.CreatedAt, userID, time.Now()) {
+				skippedTrackings = append(skippedTrackings, SkippedOutbound{Index: i, Tracking: tracking, Reason: "Already scanned moments ago"})
+				continue
+			}
+			alert, err := models.RaiseOutboundAlert(oc.DB.WithContext(c), order, models.OutboundAlertAlreadyShipped, existing, userID)
+			if err != nil {
+				failedTrackings = append(failedTrackings, FailedOutbound{Index: i, Tracking: tracking, Error: "Failed to raise duplicate parcel alert: " + err.Error()})
+				continue
+			}
+			skippedTrackings = append(skippedTrackings, SkippedOutbound{Index: i, Tracking: tracking, Reason: fmt.Sprintf("Outbound already exists, alert #%d raised", alert.ID)})
+			continue
+		}
+
+		// Block the scan when another order of the duplicate chain already left, unless a coordinator overrode it
+		var override *models.OutboundAlert
+		sibling, err := models.FindShippedSibling(oc.DB.WithContext(c), order)
+		if err != nil {
+			failedTrackings = append(failedTrackings, FailedOutbound{Index: i, Tracking: tracking, Error: "Failed to check duplicate orders: " + err.Error()})
+			continue
+		}
+		if sibling != nil {
+			var overridden models.OutboundAlert
+			if err := oc.DB.WithContext(c).Where("tracking = ? AND reason = ? AND status = ? AND outbound_id IS NULL", tracking, models.OutboundAlertSiblingShipped, models.OutboundAlertOverridden).
+				First(&overridden).Error; err == nil {
+				override = &overridden
+			} else {
+				alert, err := models.RaiseOutboundAlert(oc.DB.WithContext(c), order, models.OutboundAlertSiblingShipped, sibling, userID)
+				if err != nil {
+					failedTrackings = append(failedTrackings, FailedOutbound{Index: i, Tracking: tracking, Error: "Failed to raise duplicate parcel alert: " + err.Error()})
+					continue
+				}
+				failedTrackings = append(failedTrackings, FailedOutbound{Index: i, Tracking: tracking, Error: fmt.Sprintf("Duplicate order %s already left on %s, coordinator override required (alert #%d)", sibling.Tracking, sibling.CreatedAt.Format("2006-01-02 15:04"), alert.ID)})
+				continue
+			}
+		}
+
+		outbound := models.Outbound{
+			Tracking:   tracking,
+			OutboundBy: &userID,
+		}
+
+		// Special case: trackings starting with "TKP0" use the expedition from the request body
+		if strings.HasPrefix(tracking, "TKP0") {
+			if req.Expedition == "" {
+				failedTrackings = append(failedTrackings, FailedOutbound{Index: i, Tracking: tracking, Error: "expedition is required for TKP0 trackings"})
+				continue
+			}
+			outbound.Expedition = req.Expedition
+			outbound.ExpeditionColor = req.ExpeditionColor
+			outbound.ExpeditionSlug = req.ExpeditionSlug
+		} else {
+			exp := expeditionMatcher.Match(tracking)
+			if exp == nil {
+				failedTrackings = append(failedTrackings, FailedOutbound{Index: i, Tracking: tracking, Error: "Tracking number does not match any known expedition prefix"})
+				continue
+			}
+
+			// Reject mis-scans that break the courier's tracking format
+			if err := exp.ValidateTracking(tracking); err != nil {
+				failedTrackings = append(failedTrackings, FailedOutbound{Index: i, Tracking: tracking, Error: err.Error()})
+				continue
+			}
+
+			outbound.Expedition = exp.Name
+			outbound.ExpeditionColor = exp.Color
+			outbound.ExpeditionSlug = exp.Slug
+		}
+
+		// Courier mismatches need a coordinator's override token, which only the single scan takes
+		courierExpedition, err := models.FindCourierMismatch(oc.DB.WithContext(c), order, outbound.Expedition)
+		if err != nil {
+			failedTrackings = append(failedTrackings, FailedOutbound{Index: i, Tracking: tracking, Error: "Failed to check order courier: " + err.Error()})
+			continue
+		}
+		if courierExpedition != nil {
+			alert, err := models.RaiseCourierMismatchAlert(oc.DB.WithContext(c), order, outbound.Expedition, order.Courier, userID)
+			if err != nil {
+				failedTrackings = append(failedTrackings, FailedOutbound{Index: i, Tracking: tracking, Error: "Failed to raise courier mismatch alert: " + err.Error()})
+				continue
+			}
+			failedTrackings = append(failedTrackings, FailedOutbound{Index: i, Tracking: tracking, Error: fmt.Sprintf("Tracking is %s but the order ships with %s, scan it alone with a coordinator override token (alert #%d)", outbound.Expedition, order.Courier, alert.ID)})
+			continue
+		}
+
+		// Each parcel gets its own transaction so one failure does not undo the rest of the batch
+		err = utilities.WithRetryTransaction(oc.DB.WithContext(c), "create-outbound", func(tx *gorm.DB) error {
+			// A failed attempt may have assigned an ID before rolling back
+			outbound.ID = 0
+
+			// The order may have been cancelled or held since the batch was looked up
+			if err := models.LockOrderForOutbound(tx, tracking); err != nil {
+				if errors.Is(err, models.ErrOrderCancelled) {
+					return errors.New("the order was cancelled and cannot be sent out")
+				}
+				if errors.Is(err, models.ErrOrderHeld) {
+					return errors.New("the order is on hold and cannot be sent out until it is released")
+				}
+				return fmt.Errorf("failed to lock order: %w", err)
+			}
+
+			if err := models.AssignPickupSlot(tx, &outbound, utilities.Now()); err != nil {
+				return fmt.Errorf("failed to assign pickup slot: %w", err)
+			}
+
+			if err := tx.Create(&outbound).Error; err != nil {
+				return fmt.Errorf("failed to create outbound: %w", err)
+			}
+
+			// An override is good for a single outbound
+			if override != nil {
+				if err := tx.Model(override).Update("outbound_id", outbound.ID).Error; err != nil {
+					return fmt.Errorf("failed to link outbound alert: %w", err)
 				}
 			}
-			outbound.Order = &order
+
+			if err := tx.Model(&models.Order{}).Where("tracking = ?", tracking).Update("processing_status", "outbound completed").Error; err != nil {
+				return fmt.Errorf("failed to update order status: %w", err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			failedTrackings = append(failedTrackings, FailedOutbound{Index: i, Tracking: tracking, Error: err.Error()})
+			continue
 		}
+
+		createdIDs = append(createdIDs, outbound.ID)
 	}
 
-	utilities.SuccessResponse(c, http.StatusCreated, "Outbound created successfully", outbound.ToOutboundResponse())
+	// Load the created outbounds with their operator for the response
+	createdOutbounds := []models.OutboundResponse{}
+	if len(createdIDs) > 0 {
+		var outbounds []models.Outbound
+		if err := oc.DB.WithContext(c).Preload("OutboundOperator").Where("id IN ?", createdIDs).Order("id ASC").Find(&outbounds).Error; err != nil {
+			return BulkCreateOutboundResponse{}, utilities.NewStatusError(http.StatusInternalServerError, "Failed to load created outbounds", err.Error())
+		}
+		for i := range outbounds {
+			createdOutbounds = append(createdOutbounds, outbounds[i].ToOutboundResponse())
+		}
+	}
+
+	return BulkCreateOutboundResponse{
+		Summary: BulkCreateOutboundSummary{
+			Total:   len(trackings),
+			Created: len(createdIDs),
+			Skipped: len(skippedTrackings),
+			Failed:  len(failedTrackings),
+		},
+		CreatedOutbounds: createdOutbounds,
+		SkippedTrackings: skippedTrackings,
+		FailedTrackings:  failedTrackings,
+	}, nil
 }
 
 // GetChartOutbounds godoc
@@ -410,7 +804,7 @@ func (oc *OutboundController) CreateOutbound(c *gin.Context) {
 // @Router /api/outbounds/chart [get]
 func (oc *OutboundController) GetChartOutbounds(c *gin.Context) {
 	// Get current month start and end dates
-	now := time.Now()
+	now := utilities.Now()
 	currentYear, currentMonth, _ := now.Date()
 	currentLocation := now.Location()
 
@@ -423,11 +817,11 @@ func (oc *OutboundController) GetChartOutbounds(c *gin.Context) {
 	// Query to get daily counts for current month
 	var dailyCounts []OutboundsDailyCount
 
-	if err := oc.DB.Model(&models.Outbound{}).
-		Select("DATE(created_at) as date, COUNT(*) as count").
+	if err := oc.DB.WithContext(c).Model(&models.Outbound{}).
+		Select(utilities.LocalDate("created_at")+" as date, COUNT(*) as count", utilities.AppTimezone()).
 		Where("created_at >= ?", firstOfMonth).
 		Where("created_at < ?", firstOfNextMonth).
-		Group("DATE(created_at)").
+		Group("date").
 		Order("date ASC").
 		Scan(&dailyCounts).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve outbound counts", err.Error())
@@ -436,7 +830,7 @@ func (oc *OutboundController) GetChartOutbounds(c *gin.Context) {
 
 	// Get total count for current month
 	var totalCount int64
-	if err := oc.DB.Model(&models.Outbound{}).
+	if err := oc.DB.WithContext(c).Model(&models.Outbound{}).
 		Where("created_at >= ?", firstOfMonth).
 		Where("created_at < ?", firstOfNextMonth).
 		Count(&totalCount).Error; err != nil {
@@ -451,11 +845,151 @@ func (oc *OutboundController) GetChartOutbounds(c *gin.Context) {
 		TotalCount:  int(totalCount),
 	}
 
-	message := "Outbound daily counts for " + currentMonth.String() + " " + strconv.Itoa(currentYear) + " retrieved successfully"
+	message := utilities.Messagef(c, "Outbound daily counts for %s %d retrieved successfully", currentMonth, currentYear)
+
+	utilities.SuccessResponse(c, http.StatusOK, message, response)
+}
+
+// GetOutboundAlerts godoc
+// @Summary Get duplicate parcel alerts
+// @Description Get outbound scans that were blocked because the parcel or its duplicate order already left, or the label is of another courier than the order's, newest first
+// @Tags outbounds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param status query string false "Filter by status (open, overridden, dismissed)"
+// @Success 200 {object} utilities.Response{data=OutboundAlertsListResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/outbounds/alerts [get]
+func (oc *OutboundController) GetOutboundAlerts(c *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	status := c.Query("status")
+
+	var alerts []models.OutboundAlert
+	var total int64
+
+	query := oc.DB.WithContext(c).Model(&models.OutboundAlert{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count outbound alerts", err.Error())
+		return
+	}
+
+	if err := query.Preload("Scanner").Preload("Resolver").Order("id DESC").Limit(limit).Offset(offset).Find(&alerts).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve outbound alerts", err.Error())
+		return
+	}
+
+	alertResponses := make([]models.OutboundAlertResponse, len(alerts))
+	for i, alert := range alerts {
+		alertResponses[i] = alert.ToOutboundAlertResponse()
+	}
+
+	response := OutboundAlertsListResponse{
+		Alerts: alertResponses,
+		Pagination: utilities.PaginationResponse{
+			Page:  page,
+			Limit: limit,
+			Total: int(total),
+		},
+	}
+
+	message := utilities.Messagef(c, "Outbound alerts retrieved successfully")
+	if status != "" {
+		message += utilities.Messagef(c, " (filtered by status: %s)", status)
+	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
 }
 
+// ResolveOutboundAlert godoc
+// @Summary Resolve a duplicate parcel alert
+// @Description Override an alert to let the blocked parcel through on its next outbound scan, or dismiss it when the parcel was pulled. Only duplicate-order and courier mismatch alerts can be overridden. Overriding a courier mismatch returns an override_token, the outbound scan of the parcel must carry it; every override keeps the coordinator, the note and the outbound it let through.
+// @Tags outbounds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Alert ID"
+// @Param request body ResolveOutboundAlertRequest true "Resolve alert request"
+// @Success 200 {object} utilities.Response{data=models.OutboundAlertResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/outbounds/alerts/{id}/resolve [put]
+func (oc *OutboundController) ResolveOutboundAlert(c *gin.Context) {
+	alertID := c.Param("id")
+	userID := c.GetUint("user_id")
+
+	var req ResolveOutboundAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	var alert models.OutboundAlert
+	err := utilities.WithTransaction(oc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Find the alert, locked until commit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&alert, alertID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeOutboundAlertNotFound, "Outbound alert not found", "no outbound alert found with the specified ID")
+			}
+			return err
+		}
+
+		if alert.Status != models.OutboundAlertOpen {
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeAlreadyResolved, "Outbound alert already resolved", fmt.Sprintf("alert is already %s", alert.Status))
+		}
+
+		status := models.OutboundAlertDismissed
+		if req.Action == "override" {
+			// The tracking itself already has an outbound, there is nothing to let through
+			if alert.Reason == models.OutboundAlertAlreadyShipped {
+				return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeStateInvalid, "Alert cannot be overridden", "only duplicate order and courier mismatch alerts can be overridden")
+			}
+			status = models.OutboundAlertOverridden
+
+			// The outbound station must give the token back, so the mismatch is let through knowingly
+			if alert.Reason == models.OutboundAlertCourierMismatch {
+				token, err := models.GenerateOverrideToken()
+				if err != nil {
+					return fmt.Errorf("failed to generate override token: %w", err)
+				}
+				alert.OverrideToken = token
+			}
+		}
+
+		now := time.Now()
+		alert.Status = status
+		alert.ResolvedBy = &userID
+		alert.ResolvedAt = &now
+		alert.ResolutionNote = req.Note
+		if err := tx.Save(&alert).Error; err != nil {
+			return fmt.Errorf("failed to update outbound alert: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to resolve outbound alert")
+		return
+	}
+
+	oc.DB.WithContext(c).Preload("Scanner").Preload("Resolver").First(&alert, alert.ID)
+
+	utilities.SuccessResponse(c, http.StatusOK, "Outbound alert resolved successfully", alert.ToOutboundAlertResponse())
+}
+
 // Request/Response structs
 type OutboundsListResponse struct {
 	Outbounds  []models.OutboundResponse    `json:"outbounds"`
@@ -469,10 +1003,45 @@ type UpdateOutboundRequest struct {
 }
 
 type CreateOutboundRequest struct {
-	Tracking        string `json:"tracking" binding:"required"`
+	Tracking        string `json:"tracking" binding:"required,tracking"`
 	Expedition      string `json:"expedition"`
 	ExpeditionColor string `json:"expedition_color"`
 	ExpeditionSlug  string `json:"expedition_slug"`
+	OverrideToken   string `json:"override_token" example:"9F3A12C4"` // From a coordinator's override of a courier mismatch alert
+}
+
+type BulkCreateOutboundRequest struct {
+	Trackings []string `json:"trackings" binding:"required,min=1,max=500" example:"JNE1234567890,SPXID056205885386"`
+	// Expedition for "TKP0" trackings, which cannot be detected from their prefix
+	Expedition      string `json:"expedition"`
+	ExpeditionColor string `json:"expedition_color"`
+	ExpeditionSlug  string `json:"expedition_slug"`
+}
+
+type BulkCreateOutboundResponse struct {
+	Summary          BulkCreateOutboundSummary `json:"summary"`
+	CreatedOutbounds []models.OutboundResponse `json:"created_outbounds"`
+	SkippedTrackings []SkippedOutbound         `json:"skipped_trackings"`
+	FailedTrackings  []FailedOutbound          `json:"failed_trackings"`
+}
+
+type BulkCreateOutboundSummary struct {
+	Total   int `json:"total"`
+	Created int `json:"created"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+type SkippedOutbound struct {
+	Index    int    `json:"index"`
+	Tracking string `json:"tracking"`
+	Reason   string `json:"reason"`
+}
+
+type FailedOutbound struct {
+	Index    int    `json:"index"`
+	Tracking string `json:"tracking"`
+	Error    string `json:"error"`
 }
 
 // OutboundsDailyCount represents the count of outbounds for a specific date
@@ -488,3 +1057,13 @@ type OutboundsDailyCountResponse struct {
 	DailyCounts []OutboundsDailyCount `json:"daily_counts"`
 	TotalCount  int                   `json:"total_count"` // Total for the month
 }
+
+type OutboundAlertsListResponse struct {
+	Alerts     []models.OutboundAlertResponse `json:"alerts"`
+	Pagination utilities.PaginationResponse   `json:"pagination"`
+}
+
+type ResolveOutboundAlertRequest struct {
+	Action string `json:"action" binding:"required,oneof=override dismiss" example:"override"`
+	Note   string `json:"note" binding:"required" example:"Original parcel returned to sender, resend approved"`
+}
@@ -1,8 +1,8 @@
 package controllers
 
 import (
-	"fmt"
 	"livo-backend/models"
+	"livo-backend/repositories"
 	"livo-backend/utilities"
 	"net/http"
 	"strconv"
@@ -34,6 +34,8 @@ func NewPickedOrderController(db *gorm.DB) *PickedOrderController {
 // @Param start_date query string false "Start date (YYYY-MM-DD format)"
 // @Param end_date query string false "End date (YYYY-MM-DD format)"
 // @Param search query string false "Search by Picker name, Order Ginee ID, or Tracking (partial match)"
+// @Param sort_by query string false "Sort keys, comma separated, each optionally suffixed with :asc or :desc (created_at)" default(created_at:desc)
+// @Param sort_dir query string false "Direction of sort keys without a suffix: asc or desc" default(asc)
 // @Success 200 {object} utilities.Response{data=PickOrdersListResponse}
 // @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
@@ -56,13 +58,13 @@ func (poc *PickedOrderController) GetPickedOrders(c *gin.Context) {
 	var total int64
 
 	// Build query with optional search
-	query := poc.DB.Model(&models.PickedOrder{})
+	query := poc.DB.WithContext(c).Model(&models.PickedOrder{})
 	// Apply date range filters if provided
 	if startDate != "" {
 		// Parse start date and set time to beginning of day
 		parsedStartDate, err := time.Parse("2006-01-02", startDate)
 		if err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
 			return
 		}
 		startOfDay := parsedStartDate.Format("2006-01-02 00:00:00")
@@ -73,7 +75,7 @@ func (poc *PickedOrderController) GetPickedOrders(c *gin.Context) {
 		// Parse end date and set time to end of day
 		parsedEndDate, err := time.Parse("2006-01-02", endDate)
 		if err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
 			return
 		}
 		// Add 24 hours to get the start of next day, then use < instead of <=
@@ -89,19 +91,20 @@ func (poc *PickedOrderController) GetPickedOrders(c *gin.Context) {
 				"%"+search+"%", "%"+search+"%", "%"+search+"%")
 	}
 
+	sortScope, err := utilities.ApplySort(c, models.PickedOrderListSortKeys, "created_at:desc", "picked_orders.id")
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid sort", err.Error())
+		return
+	}
+
 	// Get total count with search filter
 	if err := query.Count(&total).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count pick orders", err.Error())
 		return
 	}
 
-	// Get pick orders with pagination, search filter, and order by ID desc
-	if err := query.Preload("PickOperator.UserRoles.Role").
-		Preload("PickOperator.UserRoles.Assigner").
-		Preload("Order.OrderDetails").
-		Preload("Order.PickOperator.UserRoles.Role").
-		Preload("Order.PickOperator.UserRoles.Assigner").
-		Order("picked_orders.id DESC").
+	// Get pick orders with pagination, search filter, and sorting
+	if err := query.Scopes(repositories.PickedOrderWithDetails, sortScope).
 		Limit(limit).
 		Offset(offset).
 		Find(&pickOrders).Error; err != nil {
@@ -125,7 +128,7 @@ func (poc *PickedOrderController) GetPickedOrders(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Pick orders retrieved successfully"
+	message := utilities.Messagef(c, "Pick orders retrieved successfully")
 	var filters []string
 
 	if startDate != "" || endDate != "" {
@@ -144,7 +147,7 @@ func (poc *PickedOrderController) GetPickedOrders(c *gin.Context) {
 	}
 
 	if len(filters) > 0 {
-		message += fmt.Sprintf(" (filtered by %s)", strings.Join(filters, " | "))
+		message += utilities.Messagef(c, " (filtered by %s)", strings.Join(filters, " | "))
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
@@ -168,14 +171,10 @@ func (poc *PickedOrderController) GetPickedOrder(c *gin.Context) {
 	pickOrderId := c.Param("id")
 
 	var pickOrder models.PickedOrder
-	if err := poc.DB.Preload("PickOperator.UserRoles.Role").
-		Preload("PickOperator.UserRoles.Assigner").
-		Preload("Order.OrderDetails").
-		Preload("Order.PickOperator.UserRoles.Role").
-		Preload("Order.PickOperator.UserRoles.Assigner").
+	if err := poc.DB.WithContext(c).Scopes(repositories.PickedOrderWithDetails).
 		First(&pickOrder, pickOrderId).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			utilities.ErrorResponse(c, http.StatusNotFound, "Pick order not found", err.Error())
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodePickOrderNotFound, "Pick order not found", err.Error())
 			return
 		}
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve pick order", err.Error())
@@ -0,0 +1,261 @@
+package controllers
+
+import (
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type PickupController struct {
+	DB *gorm.DB
+}
+
+// NewPickupController creates a new courier pickup controller
+func NewPickupController(db *gorm.DB) *PickupController {
+	return &PickupController{DB: db}
+}
+
+// GetPickupSlots godoc
+// @Summary Get pickup slots
+// @Description Get the daily courier pickup slots, by pickup time. Outbounds are assigned to the next slot of their expedition with capacity left.
+// @Tags pickups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param expedition_id query int false "Filter by expedition ID"
+// @Success 200 {object} utilities.Response{data=[]models.PickupSlotResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/pickups [get]
+func (pc *PickupController) GetPickupSlots(c *gin.Context) {
+	query := pc.DB.WithContext(c).Preload("Expedition").Preload("Creator")
+	if expeditionID := c.Query("expedition_id"); expeditionID != "" {
+		query = query.Where("expedition_id = ?", expeditionID)
+	}
+
+	var slots []models.PickupSlot
+	if err := query.Order("pickup_time ASC, expedition_id ASC").Find(&slots).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve pickup slots", err.Error())
+		return
+	}
+
+	slotResponses := make([]models.PickupSlotResponse, len(slots))
+	for i := range slots {
+		slotResponses[i] = slots[i].ToPickupSlotResponse()
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Pickup slots retrieved successfully", slotResponses)
+}
+
+// GetTodayPickups godoc
+// @Summary Get today's pickups
+// @Description Get the fill rate of every active pickup slot today (or on date): the capacity, the outbounds assigned and whether the slot is open, full or departed already. Also counts today's outbounds that got no slot, because their expedition has none or every slot of the coming week is full.
+// @Tags pickups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param date query string false "Day to show instead of today (YYYY-MM-DD)"
+// @Success 200 {object} utilities.Response{data=TodayPickupsResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/pickups/today [get]
+func (pc *PickupController) GetTodayPickups(c *gin.Context) {
+	now := utilities.Now()
+	date := now
+	if value := c.Query("date"); value != "" {
+		parsed, err := utilities.ParseDate(value)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid date format", "date must be in YYYY-MM-DD format")
+			return
+		}
+		date = parsed
+	}
+
+	slots, unassigned, err := models.GetPickupFill(pc.DB.WithContext(c), date, now)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve pickups", err.Error())
+		return
+	}
+
+	response := TodayPickupsResponse{
+		Date:       date.Format("2006-01-02"),
+		Slots:      slots,
+		Unassigned: unassigned,
+	}
+	for _, slot := range slots {
+		response.Capacity += slot.Capacity
+		response.Assigned += slot.Assigned
+	}
+	if response.Capacity > 0 {
+		response.FillRate = float64(response.Assigned) * 100 / float64(response.Capacity)
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Pickups retrieved successfully", response)
+}
+
+// CreatePickupSlot godoc
+// @Summary Create pickup slot
+// @Description Register a daily pickup of an expedition at a time (HH:MM) with the number of parcels the courier takes. An expedition has one slot per pickup time.
+// @Tags pickups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body PickupSlotRequest true "Create pickup slot request"
+// @Success 201 {object} utilities.Response{data=models.PickupSlotResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/pickups [post]
+func (pc *PickupController) CreatePickupSlot(c *gin.Context) {
+	var req PickupSlotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	slot := models.PickupSlot{CreatedBy: c.GetUint("user_id"), Active: true}
+	if !pc.applyPickupSlotRequest(c, &slot, req) {
+		return
+	}
+
+	if err := pc.DB.WithContext(c).Create(&slot).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create pickup slot", err.Error())
+		return
+	}
+
+	pc.DB.WithContext(c).Preload("Expedition").Preload("Creator").First(&slot, slot.ID)
+
+	utilities.SuccessResponse(c, http.StatusCreated, "Pickup slot created successfully", slot.ToPickupSlotResponse())
+}
+
+// UpdatePickupSlot godoc
+// @Summary Update pickup slot
+// @Description Update a pickup slot. Outbounds already assigned keep their slot, a lower capacity only limits new assignments.
+// @Tags pickups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Pickup slot ID"
+// @Param request body PickupSlotRequest true "Update pickup slot request"
+// @Success 200 {object} utilities.Response{data=models.PickupSlotResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/pickups/{id} [put]
+func (pc *PickupController) UpdatePickupSlot(c *gin.Context) {
+	slotID := c.Param("id")
+
+	var req PickupSlotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	var slot models.PickupSlot
+	if err := pc.DB.WithContext(c).First(&slot, slotID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodePickupSlotNotFound, "Pickup slot not found", "Failed to retrieve pickup slot")
+		return
+	}
+
+	if !pc.applyPickupSlotRequest(c, &slot, req) {
+		return
+	}
+
+	if err := pc.DB.WithContext(c).Save(&slot).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update pickup slot", err.Error())
+		return
+	}
+
+	pc.DB.WithContext(c).Preload("Expedition").Preload("Creator").First(&slot, slot.ID)
+
+	utilities.SuccessResponse(c, http.StatusOK, "Pickup slot updated successfully", slot.ToPickupSlotResponse())
+}
+
+// RemovePickupSlot godoc
+// @Summary Remove pickup slot
+// @Description Soft delete a pickup slot. Outbounds already assigned keep their pickup time.
+// @Tags pickups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Pickup slot ID"
+// @Success 200 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/pickups/{id} [delete]
+func (pc *PickupController) RemovePickupSlot(c *gin.Context) {
+	slotID := c.Param("id")
+
+	var slot models.PickupSlot
+	if err := pc.DB.WithContext(c).First(&slot, slotID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodePickupSlotNotFound, "Pickup slot not found", "Failed to retrieve pickup slot")
+		return
+	}
+
+	if err := pc.DB.WithContext(c).Delete(&slot).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to remove pickup slot", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Pickup slot removed successfully", nil)
+}
+
+// applyPickupSlotRequest validates req and copies it onto slot, responding with the error and
+// returning false when it is invalid
+func (pc *PickupController) applyPickupSlotRequest(c *gin.Context, slot *models.PickupSlot, req PickupSlotRequest) bool {
+	pickupTime, err := time.Parse("15:04", req.PickupTime)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid pickup time", "pickup_time must be a time in HH:MM format")
+		return false
+	}
+
+	var expedition models.Expedition
+	if err := pc.DB.WithContext(c).First(&expedition, req.ExpeditionID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeExpeditionNotFound, "Expedition not found", "Failed to retrieve expedition")
+		return false
+	}
+
+	// Normalized so pickup times sort as text
+	req.PickupTime = pickupTime.Format("15:04")
+
+	var existing models.PickupSlot
+	if err := pc.DB.WithContext(c).Where("expedition_id = ? AND pickup_time = ? AND id != ?", expedition.ID, req.PickupTime, slot.ID).First(&existing).Error; err == nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDuplicateCode, "Pickup slot already exists", "The expedition already has a pickup at this time")
+		return false
+	}
+
+	slot.ExpeditionID = expedition.ID
+	slot.PickupTime = req.PickupTime
+	slot.Capacity = req.Capacity
+	slot.Notes = req.Notes
+	if req.Active != nil {
+		slot.Active = *req.Active
+	}
+	return true
+}
+
+// Request/Response structs
+type PickupSlotRequest struct {
+	ExpeditionID uint   `json:"expedition_id" binding:"required" example:"1"`
+	PickupTime   string `json:"pickup_time" binding:"required,clock" example:"15:00"` // Daily pickup time (HH:MM)
+	Capacity     int    `json:"capacity" binding:"required,min=1" example:"300"`
+	Active       *bool  `json:"active" example:"true"` // Inactive slots get no outbounds, true when left out on create
+	Notes        string `json:"notes" example:"Large van on weekdays"`
+}
+
+type TodayPickupsResponse struct {
+	Date       string                    `json:"date"`
+	Capacity   int                       `json:"capacity"`
+	Assigned   int                       `json:"assigned"`
+	FillRate   float64                   `json:"fill_rate"` // Percentage of today's capacity assigned
+	Slots      []models.PickupSlotFill   `json:"slots"`
+	Unassigned []models.PickupUnassigned `json:"unassigned"`
+}
@@ -1,22 +1,33 @@
 package controllers
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"livo-backend/config"
 	"livo-backend/models"
+	"livo-backend/storage"
 	"livo-backend/utilities"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 type ProductController struct {
-	DB *gorm.DB
+	DB      *gorm.DB
+	Config  *config.Config
+	Storage storage.Storage
 }
 
 // NewProductController creates a new product controller
-func NewProductController(db *gorm.DB) *ProductController {
-	return &ProductController{DB: db}
+func NewProductController(db *gorm.DB, cfg *config.Config, store storage.Storage) *ProductController {
+	return &ProductController{DB: db, Config: cfg, Storage: store}
 }
 
 // GetProducts godoc
@@ -29,7 +40,10 @@ func NewProductController(db *gorm.DB) *ProductController {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param search query string false "Search by SKU (partial match)"
+// @Param sort_by query string false "Sort keys, comma separated, each optionally suffixed with :asc or :desc (created_at, sku, name, location, category)"
+// @Param sort_dir query string false "Direction of sort keys without a suffix: asc or desc" default(asc)
 // @Success 200 {object} utilities.Response{data=ProductsListResponse}
+// @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Router /api/products [get]
@@ -46,21 +60,27 @@ func (pc *ProductController) GetProducts(c *gin.Context) {
 	var total int64
 
 	// Build query with optional search
-	query := pc.DB.Model(&models.Product{})
+	query := pc.DB.WithContext(c).Model(&models.Product{})
 
 	if search != "" {
 		// Search by SKU with partial match
 		query = query.Where("sku ILIKE ?", "%"+search+"%")
 	}
 
+	sortScope, err := utilities.ApplySort(c, models.ProductSortKeys, "", "products.id")
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid sort", err.Error())
+		return
+	}
+
 	// Get total count with search filter
 	if err := query.Count(&total).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count products", err.Error())
 		return
 	}
 
-	// Get products with pagination, search filter, and order by ID ascending
-	if err := query.Order("id ASC").Limit(limit).Offset(offset).Find(&products).Error; err != nil {
+	// Get products with pagination, search filter, and sorting
+	if err := query.Scopes(sortScope).Limit(limit).Offset(offset).Find(&products).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve products", err.Error())
 		return
 	}
@@ -81,9 +101,9 @@ func (pc *ProductController) GetProducts(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Products retrieved successfully"
+	message := utilities.Messagef(c, "Products retrieved successfully")
 	if search != "" {
-		message += " (filtered by SKU: " + search + ")"
+		message += utilities.Messagef(c, " (filtered by SKU: %s)", search)
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
@@ -106,8 +126,8 @@ func (pc *ProductController) GetProduct(c *gin.Context) {
 	productID := c.Param("id")
 
 	var product models.Product
-	if err := pc.DB.First(&product, productID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Product not found", err.Error())
+	if err := pc.DB.WithContext(c).First(&product, productID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeProductNotFound, "Product not found", "Failed to retrieve product")
 		return
 	}
 
@@ -139,25 +159,166 @@ func (pc *ProductController) UpdateProduct(c *gin.Context) {
 	}
 
 	var product models.Product
-	if err := pc.DB.First(&product, productID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Product not found", err.Error())
+	if err := pc.DB.WithContext(c).First(&product, productID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeProductNotFound, "Product not found", "Failed to retrieve product")
 		return
 	}
 
+	// An image URL replacing the uploaded image drops the upload and its thumbnail
+	var replacedKeys []string
+	if req.Image != product.Image && product.ImageKey != "" {
+		replacedKeys = []string{product.ImageKey, product.ThumbnailKey}
+		product.Thumbnail = ""
+		product.ImageKey = ""
+		product.ThumbnailKey = ""
+	}
+
 	// Update product fields
 	product.Name = req.Name
 	product.Image = req.Image
 	product.Variant = req.Variant
 	product.Location = req.Location
 	product.Barcode = req.Barcode
-	if err := pc.DB.Save(&product).Error; err != nil {
+	product.Category = strings.TrimSpace(req.Category)
+	product.Length = req.Length
+	product.Width = req.Width
+	product.Height = req.Height
+	product.Weight = req.Weight
+	product.Cost = req.Cost
+	product.ScrapValue = req.ScrapValue
+	if err := pc.DB.WithContext(c).Save(&product).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update product", err.Error())
 		return
 	}
+	pc.deleteFiles(c, replacedKeys...)
 
 	utilities.SuccessResponse(c, http.StatusOK, "Product updated successfully", product.ToProductResponse())
 }
 
+// UploadProductImage godoc
+// @Summary Upload product image
+// @Description Upload the image of a product as a multipart form file (coordinator only). JPEG, PNG and GIF images are accepted up to the configured size. The image is stored as uploaded together with a JPEG thumbnail for the mobile pick screen, and replaces the image URL and any earlier upload of the product.
+// @Tags products
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param image formData file true "Product image (JPEG, PNG or GIF)"
+// @Success 200 {object} utilities.Response{data=models.ProductResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 413 {object} utilities.Response
+// @Router /api/products/{id}/image [post]
+func (pc *ProductController) UploadProductImage(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid product ID", err.Error())
+		return
+	}
+
+	var product models.Product
+	if err := pc.DB.WithContext(c).First(&product, uint(productID)).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeProductNotFound, "Product not found", "Failed to retrieve product")
+		return
+	}
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidImage, "Image is required", "send the image as the image field of a multipart form")
+		return
+	}
+
+	maxBytes := int64(pc.Config.ProductImageMaxMB) << 20
+	if file.Size > maxBytes {
+		utilities.ErrorResponseCode(c, http.StatusRequestEntityTooLarge, utilities.ErrCodeFileTooLarge, "Image too large", fmt.Sprintf("images may be up to %d MB", pc.Config.ProductImageMaxMB))
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidImage, "Invalid image", err.Error())
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(src, maxBytes+1))
+	src.Close()
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidImage, "Invalid image", err.Error())
+		return
+	}
+	if int64(len(data)) > maxBytes {
+		utilities.ErrorResponseCode(c, http.StatusRequestEntityTooLarge, utilities.ErrCodeFileTooLarge, "Image too large", fmt.Sprintf("images may be up to %d MB", pc.Config.ProductImageMaxMB))
+		return
+	}
+
+	img, format, err := utilities.DecodeImage(data)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidImage, "Invalid image", err.Error())
+		return
+	}
+
+	thumbnail, err := utilities.Thumbnail(img, pc.Config.ProductThumbnailSize, 80)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate thumbnail", err.Error())
+		return
+	}
+
+	// Every upload gets new keys so the files can be cached forever
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to store image", err.Error())
+		return
+	}
+	base := fmt.Sprintf("products/%d/%s", product.ID, hex.EncodeToString(suffix))
+	imageKey := base + "." + imageExtensions[format]
+	thumbnailKey := base + "_thumb.jpg"
+
+	ctx := c.Request.Context()
+	if err := pc.Storage.Put(ctx, imageKey, "image/"+format, data); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to store image", err.Error())
+		return
+	}
+	if err := pc.Storage.Put(ctx, thumbnailKey, "image/jpeg", thumbnail); err != nil {
+		pc.deleteFiles(c, imageKey)
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to store image", err.Error())
+		return
+	}
+
+	replacedKeys := []string{product.ImageKey, product.ThumbnailKey}
+	product.Image = pc.Storage.URL(imageKey)
+	product.Thumbnail = pc.Storage.URL(thumbnailKey)
+	product.ImageKey = imageKey
+	product.ThumbnailKey = thumbnailKey
+	if err := pc.DB.WithContext(c).Model(&product).Select("Image", "Thumbnail", "ImageKey", "ThumbnailKey").Updates(&product).Error; err != nil {
+		pc.deleteFiles(c, imageKey, thumbnailKey)
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update product", err.Error())
+		return
+	}
+	pc.deleteFiles(c, replacedKeys...)
+
+	utilities.SuccessResponse(c, http.StatusOK, "Product image uploaded successfully", product.ToProductResponse())
+}
+
+// imageExtensions maps the decoded image formats to file extensions
+var imageExtensions = map[string]string{
+	"jpeg": "jpg",
+	"png":  "png",
+	"gif":  "gif",
+}
+
+// deleteFiles removes stored files that are no longer referenced, failures only leave orphans behind
+func (pc *ProductController) deleteFiles(c *gin.Context, keys ...string) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if err := pc.Storage.Delete(c.Request.Context(), key); err != nil {
+			log.Printf("⚠️ Failed to delete stored file %s: %v", key, err)
+		}
+	}
+}
+
 // RemoveProduct godoc
 // @Summary Remove product
 // @Description Soft delete a product (admin only)
@@ -175,12 +336,12 @@ func (pc *ProductController) RemoveProduct(c *gin.Context) {
 	productID := c.Param("id")
 
 	var product models.Product
-	if err := pc.DB.First(&product, productID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Product not found", err.Error())
+	if err := pc.DB.WithContext(c).First(&product, productID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeProductNotFound, "Product not found", "Failed to retrieve product")
 		return
 	}
 
-	if err := pc.DB.Delete(&product).Error; err != nil {
+	if err := pc.DB.WithContext(c).Delete(&product).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to remove product", err.Error())
 		return
 	}
@@ -209,16 +370,23 @@ func (pc *ProductController) CreateProduct(c *gin.Context) {
 	}
 
 	product := models.Product{
-		Sku:      req.Sku,
-		Name:     req.Name,
-		Image:    req.Image,
-		Variant:  req.Variant,
-		Location: req.Location,
-		Barcode:  req.Barcode,
+		Sku:        req.Sku,
+		Name:       req.Name,
+		Image:      req.Image,
+		Variant:    req.Variant,
+		Location:   req.Location,
+		Barcode:    req.Barcode,
+		Category:   strings.TrimSpace(req.Category),
+		Length:     req.Length,
+		Width:      req.Width,
+		Height:     req.Height,
+		Weight:     req.Weight,
+		Cost:       req.Cost,
+		ScrapValue: req.ScrapValue,
 	}
 
 	// Create a new product and return the response
-	if err := pc.DB.Create(&product).Error; err != nil {
+	if err := pc.DB.WithContext(c).Create(&product).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create product", err.Error())
 		return
 	}
@@ -226,6 +394,259 @@ func (pc *ProductController) CreateProduct(c *gin.Context) {
 	utilities.SuccessResponse(c, http.StatusCreated, "Product created successfully", product.ToProductResponse())
 }
 
+// SyncProducts godoc
+// @Summary Sync product catalog
+// @Description Upsert a batch of products by SKU (coordinator only). Existing products are only written when a field changed, soft deleted products are restored and items with deleted=true are removed. Invalid items are reported and skipped.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body SyncProductsRequest true "Products to sync"
+// @Success 200 {object} utilities.Response{data=SyncProductsResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/products/sync [post]
+func (pc *ProductController) SyncProducts(c *gin.Context) {
+	var req SyncProductsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	response := SyncProductsResponse{
+		Failed: []SyncProductFailure{},
+	}
+	response.Summary.Total = len(req.Products)
+
+	// Validate items, a later item with the same SKU wins and the earlier one counts as unchanged
+	items := make(map[string]SyncProductItem)
+	var skus []string
+	for i, item := range req.Products {
+		item.Sku = strings.TrimSpace(item.Sku)
+		item.Name = strings.TrimSpace(item.Name)
+
+		if item.Sku == "" {
+			response.Failed = append(response.Failed, SyncProductFailure{Index: i, Sku: item.Sku, Error: "sku is required"})
+			continue
+		}
+		if item.Name == "" && !item.Deleted {
+			response.Failed = append(response.Failed, SyncProductFailure{Index: i, Sku: item.Sku, Error: "name is required"})
+			continue
+		}
+
+		if _, seen := items[item.Sku]; !seen {
+			skus = append(skus, item.Sku)
+		} else {
+			response.Summary.Unchanged++
+		}
+		items[item.Sku] = item
+	}
+	response.Summary.Failed = len(response.Failed)
+
+	err := utilities.WithTransaction(pc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Soft deleted products still hold their SKU, include them so they can be restored
+		var existing []models.Product
+		if len(skus) > 0 {
+			if err := tx.Unscoped().Where("sku IN ?", skus).Find(&existing).Error; err != nil {
+				return fmt.Errorf("failed to find existing products: %w", err)
+			}
+		}
+
+		existingBySku := make(map[string]*models.Product, len(existing))
+		for i := range existing {
+			existingBySku[existing[i].Sku] = &existing[i]
+		}
+
+		for _, sku := range skus {
+			item := items[sku]
+			product, exists := existingBySku[sku]
+
+			switch {
+			case item.Deleted:
+				if !exists || product.DeletedAt.Valid {
+					response.Summary.Unchanged++
+					continue
+				}
+				if err := tx.Delete(product).Error; err != nil {
+					return fmt.Errorf("failed to delete product %s: %w", sku, err)
+				}
+				response.Summary.Deleted++
+
+			case !exists:
+				product := models.Product{Sku: item.Sku}
+				item.applyTo(&product)
+				if err := tx.Create(&product).Error; err != nil {
+					return fmt.Errorf("failed to create product %s: %w", sku, err)
+				}
+				response.Summary.Created++
+
+			default:
+				updated := *product
+				item.applyTo(&updated)
+				if !product.DeletedAt.Valid && updated == *product {
+					response.Summary.Unchanged++
+					continue
+				}
+
+				if err := tx.Unscoped().Model(product).Updates(map[string]interface{}{
+					"name":        updated.Name,
+					"image":       updated.Image,
+					"variant":     updated.Variant,
+					"location":    updated.Location,
+					"barcode":     updated.Barcode,
+					"category":    updated.Category,
+					"length":      updated.Length,
+					"width":       updated.Width,
+					"height":      updated.Height,
+					"weight":      updated.Weight,
+					"cost":        updated.Cost,
+					"scrap_value": updated.ScrapValue,
+					"deleted_at":  nil,
+				}).Error; err != nil {
+					return fmt.Errorf("failed to update product %s: %w", sku, err)
+				}
+				response.Summary.Updated++
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to sync products")
+		return
+	}
+
+	message := utilities.Messagef(c, "Products synced: %d created, %d updated, %d deleted, %d unchanged, %d failed",
+		response.Summary.Created, response.Summary.Updated, response.Summary.Deleted, response.Summary.Unchanged, response.Summary.Failed)
+
+	utilities.SuccessResponse(c, http.StatusOK, message, response)
+}
+
+// GetProductChanges godoc
+// @Summary Get product changes
+// @Description Get products created, updated or deleted after the cursor, oldest change first, so clients can refresh a local catalog incrementally. Omit since for a full download and pass next_cursor back until has_more is false.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param since query string false "Cursor from a previous response (next_cursor)"
+// @Param limit query int false "Maximum number of changes" default(500)
+// @Success 200 {object} utilities.Response{data=ProductChangesResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/products/changes [get]
+func (pc *ProductController) GetProductChanges(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "500"))
+	if limit < 1 || limit > maxProductChanges {
+		limit = maxProductChanges
+	}
+
+	// A soft delete only sets deleted_at, so the change time is whichever is later
+	changedAt := "GREATEST(updated_at, COALESCE(deleted_at, updated_at))"
+
+	query := pc.DB.WithContext(c).Unscoped().Model(&models.Product{})
+	if since := c.Query("since"); since != "" {
+		cursorTime, cursorID, err := parseProductCursor(since)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid since cursor", err.Error())
+			return
+		}
+		query = query.Where(changedAt+" > ? OR ("+changedAt+" = ? AND id > ?)", cursorTime, cursorTime, cursorID)
+	}
+
+	// Fetch one extra row to know whether more changes follow
+	var rows []productChangeRow
+	if err := query.Select("*, " + changedAt + " AS changed_at").
+		Order(changedAt + ", id").
+		Limit(limit + 1).
+		Scan(&rows).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve product changes", err.Error())
+		return
+	}
+
+	response := ProductChangesResponse{
+		Products:   []ProductChange{},
+		NextCursor: c.Query("since"),
+	}
+	if len(rows) > limit {
+		response.HasMore = true
+		rows = rows[:limit]
+	}
+
+	for _, row := range rows {
+		response.Products = append(response.Products, ProductChange{
+			ProductResponse: row.ToProductResponse(),
+			Deleted:         row.DeletedAt.Valid,
+		})
+	}
+	if len(rows) > 0 {
+		last := rows[len(rows)-1]
+		response.NextCursor = fmt.Sprintf("%d-%d", last.ChangedAt.UnixMicro(), last.ID)
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Product changes retrieved successfully", response)
+}
+
+// applyTo copies the synced fields onto product, dimensions and valuation only when they were sent
+func (item SyncProductItem) applyTo(product *models.Product) {
+	product.Name = item.Name
+	product.Image = item.Image
+	product.Variant = item.Variant
+	product.Location = item.Location
+	product.Barcode = item.Barcode
+	if item.Category != nil {
+		product.Category = strings.TrimSpace(*item.Category)
+	}
+	if item.Length != nil {
+		product.Length = *item.Length
+	}
+	if item.Width != nil {
+		product.Width = *item.Width
+	}
+	if item.Height != nil {
+		product.Height = *item.Height
+	}
+	if item.Weight != nil {
+		product.Weight = *item.Weight
+	}
+	if item.Cost != nil {
+		product.Cost = *item.Cost
+	}
+	if item.ScrapValue != nil {
+		product.ScrapValue = *item.ScrapValue
+	}
+}
+
+// maxProductChanges caps a single page of product changes
+const maxProductChanges = 1000
+
+// productChangeRow is a product with the time of its latest change
+type productChangeRow struct {
+	models.Product
+	ChangedAt time.Time
+}
+
+// parseProductCursor parses a "<unix microseconds>-<product ID>" cursor
+func parseProductCursor(cursor string) (time.Time, uint, error) {
+	micros, id, found := strings.Cut(cursor, "-")
+	if !found {
+		return time.Time{}, 0, fmt.Errorf("cursor must be a next_cursor value from a previous response")
+	}
+
+	microValue, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("cursor must be a next_cursor value from a previous response")
+	}
+	idValue, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("cursor must be a next_cursor value from a previous response")
+	}
+
+	return time.UnixMicro(microValue), uint(idValue), nil
+}
+
 // Request/Response structs
 type ProductsListResponse struct {
 	Products   []models.ProductResponse     `json:"products"`
@@ -233,18 +654,87 @@ type ProductsListResponse struct {
 }
 
 type UpdateProductRequest struct {
-	Name     string `json:"name" binding:"required"`
-	Image    string `json:"image" binding:"required"`
-	Variant  string `json:"variant" binding:"required"`
-	Location string `json:"location"`
-	Barcode  string `json:"barcode"`
+	Name       string  `json:"name" binding:"required"`
+	Image      string  `json:"image" binding:"required"`
+	Variant    string  `json:"variant" binding:"required"`
+	Location   string  `json:"location"`
+	Barcode    string  `json:"barcode"`
+	Category   string  `json:"category" example:"Craft Supplies"`
+	Length     float64 `json:"length" binding:"min=0" example:"10"`
+	Width      float64 `json:"width" binding:"min=0" example:"5"`
+	Height     float64 `json:"height" binding:"min=0" example:"2"`
+	Weight     int     `json:"weight" binding:"min=0" example:"30"`
+	Cost       int     `json:"cost" binding:"min=0" example:"12500"`
+	ScrapValue int     `json:"scrap_value" binding:"min=0" example:"2000"`
 }
 
 type CreateProductRequest struct {
-	Sku      string `json:"sku" binding:"required,alphanum"`
-	Name     string `json:"name" binding:"required"`
-	Image    string `json:"image" binding:"required"`
-	Variant  string `json:"variant" binding:"required"`
-	Location string `json:"location"`
-	Barcode  string `json:"barcode"`
+	Sku        string  `json:"sku" binding:"required,alphanum"`
+	Name       string  `json:"name" binding:"required"`
+	Image      string  `json:"image" binding:"required"`
+	Variant    string  `json:"variant" binding:"required"`
+	Location   string  `json:"location"`
+	Barcode    string  `json:"barcode"`
+	Category   string  `json:"category" example:"Craft Supplies"`
+	Length     float64 `json:"length" binding:"min=0" example:"10"`
+	Width      float64 `json:"width" binding:"min=0" example:"5"`
+	Height     float64 `json:"height" binding:"min=0" example:"2"`
+	Weight     int     `json:"weight" binding:"min=0" example:"30"`
+	Cost       int     `json:"cost" binding:"min=0" example:"12500"`
+	ScrapValue int     `json:"scrap_value" binding:"min=0" example:"2000"`
+}
+type SyncProductsRequest struct {
+	Products []SyncProductItem `json:"products" binding:"required,min=1,max=1000,dive"`
+}
+
+type SyncProductItem struct {
+	Sku      string `json:"sku" example:"LY-GLIPOW-128-HL705-30G"`
+	Name     string `json:"name" example:"Glitter Serbuk 3 Gram"`
+	Variant  string `json:"variant" example:"Biru Tua"`
+	Barcode  string `json:"barcode" example:"8999999000012"`
+	Location string `json:"location" example:"Rak A1-3"`
+	Image    string `json:"image" example:"https://cf.shopee.co.id/file/id-11134207-7rbk5-maibgarivyxe75"`
+	Deleted  bool   `json:"deleted" example:"false"` // Remove the product instead of upserting it
+
+	// Category and dimensions are optional, stored values are kept when omitted
+	Category *string  `json:"category" example:"Craft Supplies"`
+	Length   *float64 `json:"length" example:"10"`
+	Width    *float64 `json:"width" example:"5"`
+	Height   *float64 `json:"height" example:"2"`
+	Weight   *int     `json:"weight" example:"30"`
+
+	// Valuation is optional as well
+	Cost       *int `json:"cost" example:"12500"`
+	ScrapValue *int `json:"scrap_value" example:"2000"`
+}
+
+type SyncProductsResponse struct {
+	Summary SyncProductsSummary  `json:"summary"`
+	Failed  []SyncProductFailure `json:"failed"`
+}
+
+type SyncProductsSummary struct {
+	Total     int `json:"total"`
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Deleted   int `json:"deleted"`
+	Unchanged int `json:"unchanged"`
+	Failed    int `json:"failed"`
+}
+
+type SyncProductFailure struct {
+	Index int    `json:"index"`
+	Sku   string `json:"sku"`
+	Error string `json:"error"`
+}
+
+type ProductChangesResponse struct {
+	Products   []ProductChange `json:"products"`
+	NextCursor string          `json:"next_cursor"` // Pass as since on the next call
+	HasMore    bool            `json:"has_more"`
+}
+
+type ProductChange struct {
+	models.ProductResponse
+	Deleted bool `json:"deleted"`
 }
@@ -1,8 +1,11 @@
 package controllers
 
 import (
+	"fmt"
 	"livo-backend/models"
+	"livo-backend/repositories"
 	"livo-backend/utilities"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -13,12 +16,13 @@ import (
 )
 
 type QcOnlineController struct {
-	DB *gorm.DB
+	DB     *gorm.DB
+	Orders *repositories.OrderRepository
 }
 
 // NewQcOnlineController creates a new qc-online controller
 func NewQcOnlineController(db *gorm.DB) *QcOnlineController {
-	return &QcOnlineController{DB: db}
+	return &QcOnlineController{DB: db, Orders: repositories.NewOrderRepository(db)}
 }
 
 // GetQcOnlines godoc
@@ -31,7 +35,10 @@ func NewQcOnlineController(db *gorm.DB) *QcOnlineController {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param search query string false "Search by tracking number"
+// @Param sort_by query string false "Sort keys, comma separated, each optionally suffixed with :asc or :desc (created_at, tracking)" default(created_at:desc)
+// @Param sort_dir query string false "Direction of sort keys without a suffix: asc or desc" default(asc)
 // @Success 200 {object} utilities.Response{data=QcOnlinesListResponse}
+// @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Router /api/onlines/qc-onlines [get]
@@ -39,7 +46,7 @@ func (qoc *QcOnlineController) GetQcOnlines(c *gin.Context) {
 	// Get user ID from JWT token
 	userID, exists := c.Get("user_id")
 	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User not authenticated")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeAuthRequired, "Unauthorized", "User not authenticated")
 		return
 	}
 
@@ -54,14 +61,23 @@ func (qoc *QcOnlineController) GetQcOnlines(c *gin.Context) {
 	var qcOnlines []models.QcOnline
 	var total int64
 
+	// Today in the warehouse timezone, not the database one
+	todayStart, todayEnd := utilities.DayRange(time.Now())
+
 	// Build query with filters
-	query := qoc.DB.Model(&models.QcOnline{}).Where("qc_by = ?", userID).Where("DATE(created_at) = CURRENT_DATE")
+	query := qoc.DB.WithContext(c).Model(&models.QcOnline{}).Where("qc_by = ?", userID).Where("created_at >= ? AND created_at < ?", todayStart, todayEnd)
 
 	if search != "" {
 		// Search by tracking with partial match
 		query = query.Where("tracking ILIKE ?", "%"+search+"%")
 	}
 
+	sortScope, err := utilities.ApplySort(c, models.QcOnlineSortKeys, "created_at:desc", "qc_onlines.id")
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid sort", err.Error())
+		return
+	}
+
 	// Get total count with filters
 	if err := query.Count(&total).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count qc-onlines", err.Error())
@@ -69,7 +85,7 @@ func (qoc *QcOnlineController) GetQcOnlines(c *gin.Context) {
 	}
 
 	// Get qc-onlines with pagination, filters, and preload relationships
-	if err := query.Order("id DESC").
+	if err := query.Scopes(sortScope).
 		Preload("QcOnlineDetails.Box").
 		Preload("QcOperator.UserRoles.Role").
 		Preload("QcOperator.UserRoles.Assigner").
@@ -87,27 +103,13 @@ func (qoc *QcOnlineController) GetQcOnlines(c *gin.Context) {
 			}
 		}
 
-		var orders []models.Order
-		orderMap := make(map[string]*models.Order)
-
-		if len(trackingNumbers) > 0 {
-			if err := qoc.DB.Where("tracking IN ?", trackingNumbers).
-				Preload("OrderDetails").
-				Preload("PickOperator.UserRoles.Role").
-				Preload("PickOperator.UserRoles.Assigner").
-				Find(&orders).Error; err == nil {
-
-				for i := range orders {
-					orderMap[orders[i].Tracking] = &orders[i]
+		if orderMap, err := qoc.Orders.FindByTrackings(c, trackingNumbers, repositories.OrderPickerRoles); err == nil {
+			for i := range qcOnlines {
+				if order, exists := orderMap[qcOnlines[i].Tracking]; exists {
+					qcOnlines[i].Order = order
 				}
 			}
 		}
-
-		for i := range qcOnlines {
-			if order, exists := orderMap[qcOnlines[i].Tracking]; exists {
-				qcOnlines[i].Order = order
-			}
-		}
 	}
 
 	// Convert to response format
@@ -126,9 +128,9 @@ func (qoc *QcOnlineController) GetQcOnlines(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Qc-onlines retrieved successfully"
+	message := utilities.Messagef(c, "Qc-onlines retrieved successfully")
 	if search != "" {
-		message += " (filtered by tracking: " + search + ")"
+		message += utilities.Messagef(c, " (filtered by tracking: %s)", search)
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
@@ -152,17 +154,17 @@ func (qoc *QcOnlineController) GetQcOnline(c *gin.Context) {
 
 	var qcOnline models.QcOnline
 
-	if err := qoc.DB.Preload("QcOnlineDetails.Box").
+	if err := qoc.DB.WithContext(c).Preload("QcOnlineDetails.Box").
 		Preload("QcOperator.UserRoles.Role").
 		Preload("QcOperator.UserRoles.Assigner").
 		First(&qcOnline, qcOnlineID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Qc-online not found", err.Error())
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeQcNotFound, "Qc-online not found", "Failed to retrieve qc-online")
 		return
 	}
 
 	// Load order call from the model's LoadOrder method
 	if qcOnline.Tracking != "" {
-		qcOnline.LoadOrder(qoc.DB)
+		qcOnline.LoadOrder(qoc.DB.WithContext(c))
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, "Qc-online retrieved successfully", qcOnline.ToQcOnlineResponse())
@@ -170,23 +172,25 @@ func (qoc *QcOnlineController) GetQcOnline(c *gin.Context) {
 
 // CreateQcOnline godoc
 // @Summary Create a new qc-online
-// @Description Create new qc-online entry with multiple box details.
+// @Description Create new qc-online entry with multiple box details. Orders shipping in several parcels get one entry per parcel_number and parcel_count declares how many parcels there are; the order moves to "qc complete" once every declared parcel is QC'd and stays in "qc process" until then. The same operator scanning the same tracking again within the double scan window gets the existing record back with 200.
 // @Tags onlines
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body CreateQcOnlineRequest true "Create qc-online request"
+// @Success 200 {object} utilities.Response{data=models.QcOnlineResponse}
 // @Success 201 {object} utilities.Response{data=models.QcOnlineResponse}
 // @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
 // @Router /api/onlines/qc-onlines [post]
 func (qoc *QcOnlineController) CreateQcOnline(c *gin.Context) {
 	// Get user ID from JWT token
 	userID, exists := c.Get("user_id")
 	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User not authenticated")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeAuthRequired, "Unauthorized", "User not authenticated")
 		return
 	}
 
@@ -198,6 +202,10 @@ func (qoc *QcOnlineController) CreateQcOnline(c *gin.Context) {
 
 	// Convert tracking to uppercase
 	req.Tracking = strings.ToUpper(strings.TrimSpace(req.Tracking))
+	parcelNumber := req.ParcelNumber
+	if parcelNumber == 0 {
+		parcelNumber = 1
+	}
 
 	// Convert userID to uint
 	userIDUint, ok := userID.(uint)
@@ -206,10 +214,35 @@ func (qoc *QcOnlineController) CreateQcOnline(c *gin.Context) {
 		return
 	}
 
+	// Reject mis-scans that break the courier's tracking format
+	expedition, err := models.FindExpeditionByTracking(qoc.DB.WithContext(c), req.Tracking)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve expeditions", err.Error())
+		return
+	}
+	if expedition != nil {
+		if err := expedition.ValidateTracking(req.Tracking); err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidTracking, "Invalid tracking number", err.Error())
+			return
+		}
+	}
+
 	// Check if tracking already exists in qc_onlines table
 	var existingQcOnline models.QcOnline
-	if err := qoc.DB.Where("tracking = ?", req.Tracking).First(&existingQcOnline).Error; err == nil {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "QC Online with this tracking already exists", "Duplicate tracking")
+	if err := qoc.DB.WithContext(c).Where("tracking = ? AND parcel_number = ?", req.Tracking, parcelNumber).First(&existingQcOnline).Error; err == nil {
+		// The operator scanning the parcel again moments later gets the first scan back
+		if models.IsRepeatScan(existingQcOnline.QcBy, existingQcOnline.CreatedAt, userIDUint, time.Now()) {
+			qoc.DB.WithContext(c).Preload("QcOnlineDetails.Box").
+				Preload("QcOperator.UserRoles.Role").
+				Preload("QcOperator.UserRoles.Assigner").
+				First(&existingQcOnline, existingQcOnline.ID)
+			existingQcOnline.LoadOrder(qoc.DB.WithContext(c))
+
+			utilities.SuccessResponse(c, http.StatusOK, "Qc-online already recorded", existingQcOnline.ToQcOnlineResponse())
+			return
+		}
+
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDuplicateTracking, "QC Online with this tracking already exists", fmt.Sprintf("Parcel %d of this tracking is already QC'd", parcelNumber))
 		return
 	} else if err != gorm.ErrRecordNotFound {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to validate tracking", err.Error())
@@ -218,99 +251,136 @@ func (qoc *QcOnlineController) CreateQcOnline(c *gin.Context) {
 
 	// Check if tracking exists in orders table
 	var order models.Order
-	if err := qoc.DB.Where("tracking = ?", req.Tracking).First(&order).Error; err != nil {
+	if err := qoc.DB.WithContext(c).Preload("OrderDetails").Where("tracking = ?", req.Tracking).First(&order).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			utilities.ErrorResponse(c, http.StatusNotFound, "Order not found", "No order found with the specified tracking number. Please create Order first.")
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "No order found with the specified tracking number. Please create Order first.")
 			return
 		}
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to validate tracking in MB Online", err.Error())
 		return
 	}
 
+	// A held order is not QC'd until it is released
+	if order.IsHeld() {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeOrderHeld, "Order is on hold", "the order of this tracking is on hold and cannot be QC'd until it is released")
+		return
+	}
+
 	// Validate all boxes exist and no duplicates
 	boxIDs := make(map[uint]bool)
 	for _, detail := range req.Details {
 		// Check for duplicate box IDs in request
 		if boxIDs[detail.BoxID] {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Duplicate box ID", "Each box can only be added once per QC online")
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDuplicateBox, "Duplicate box ID", "Each box can only be added once per QC online")
 			return
 		}
 		boxIDs[detail.BoxID] = true
 
 		// Check if box exists
 		var box models.Box
-		if err := qoc.DB.First(&box, detail.BoxID).Error; err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Box not found", "Invalid box ID: "+strconv.Itoa(int(detail.BoxID)))
+		if err := qoc.DB.WithContext(c).First(&box, detail.BoxID).Error; err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeBoxNotFound, "Box not found", "Invalid box ID: "+strconv.Itoa(int(detail.BoxID)))
 			return
 		}
 
 		// Validate quantity
 		if detail.Quantity <= 0 {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid quantity", "Quantity must be greater than 0")
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid quantity", "Quantity must be greater than 0")
 			return
 		}
 	}
 
-	// Start database transaction
-	tx := qoc.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
-	// Create QC Online
-	qcOnline := models.QcOnline{
-		Tracking: req.Tracking,
-		QcBy:     &userIDUint,
+	// Orders shipping in several parcels are QC'd one parcel at a time
+	parcelCount := order.ParcelCount
+	if req.ParcelCount > 0 {
+		parcelCount = req.ParcelCount
 	}
-
-	if err := tx.Create(&qcOnline).Error; err != nil {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create qc-online", err.Error())
+	parcels, err := models.QcParcelNumbers(qoc.DB.WithContext(c), &models.QcOnline{}, req.Tracking)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to validate tracking", err.Error())
 		return
 	}
+	if err := models.ValidateQcParcel(parcelNumber, parcelCount, parcels); err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParcel, "Invalid parcel", err.Error())
+		return
+	}
+	progress := models.NewQcParcelProgress(parcelCount, append(parcels, parcelNumber))
 
-	// Create QC Online Detail for each box
+	// Suggest boxes and record whether QC went with the top one; a failing suggestion must not block QC
+	suggestions, err := models.SuggestBoxes(qoc.DB.WithContext(c), &order)
+	if err != nil {
+		log.Printf("⚠️ Box suggestion for %s failed: %v", req.Tracking, err)
+	}
+	chosenBoxIDs := make([]uint, 0, len(req.Details))
 	for _, detail := range req.Details {
-		qcOnlineDetail := models.QcOnlineDetail{
-			QcOnlineID: qcOnline.ID,
-			BoxID:      detail.BoxID,
-			Quantity:   detail.Quantity,
+		chosenBoxIDs = append(chosenBoxIDs, detail.BoxID)
+	}
+
+	var qcOnline models.QcOnline
+	err = utilities.WithTransaction(qoc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Create QC Online
+		qcOnline = models.QcOnline{
+			Tracking:           req.Tracking,
+			ParcelNumber:       parcelNumber,
+			QcBy:               &userIDUint,
+			SuggestionAccepted: models.BoxSuggestionAccepted(suggestions, chosenBoxIDs),
+		}
+		if len(suggestions) > 0 {
+			qcOnline.SuggestedBoxID = &suggestions[0].BoxID
 		}
 
-		if err := tx.Create(&qcOnlineDetail).Error; err != nil {
-			tx.Rollback()
-			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create qc-online detail", err.Error())
-			return
+		if err := tx.Create(&qcOnline).Error; err != nil {
+			return fmt.Errorf("failed to create qc-online: %w", err)
 		}
-	}
 
-	// Update order processing_status to "qc complete"
-	if err := tx.Model(&models.Order{}).Where("tracking = ?", req.Tracking).Update("processing_status", "qc complete").Error; err != nil {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update order status", err.Error())
-		return
-	}
+		// Create QC Online Detail for each box
+		for _, detail := range req.Details {
+			qcOnlineDetail := models.QcOnlineDetail{
+				QcOnlineID: qcOnline.ID,
+				BoxID:      detail.BoxID,
+				Quantity:   detail.Quantity,
+			}
+
+			if err := tx.Create(&qcOnlineDetail).Error; err != nil {
+				return fmt.Errorf("failed to create qc-online detail: %w", err)
+			}
+
+			if err := models.ConsumeBoxStock(tx, detail.BoxID, detail.Quantity); err != nil {
+				return fmt.Errorf("failed to update box stock: %w", err)
+			}
+		}
+
+		// The order is "qc complete" once its last parcel is QC'd
+		if err := tx.Model(&models.Order{}).Where("tracking = ?", req.Tracking).Updates(map[string]interface{}{
+			"processing_status": progress.ProcessingStatus(),
+			"parcel_count":      parcelCount,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction", err.Error())
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to create qc-online")
 		return
 	}
 
 	// Load the created qc-online with relationships
-	qoc.DB.Preload("QcOnlineDetails.Box").
+	qoc.DB.WithContext(c).Preload("QcOnlineDetails.Box").
 		Preload("QcOperator.UserRoles.Role").
 		Preload("QcOperator.UserRoles.Assigner").
 		First(&qcOnline, qcOnline.ID)
 
 	// Load order call from the model's LoadOrder method
 	if qcOnline.Tracking != "" {
-		qcOnline.LoadOrder(qoc.DB)
+		qcOnline.LoadOrder(qoc.DB.WithContext(c))
 	}
 
-	utilities.SuccessResponse(c, http.StatusCreated, "Qc-online created successfully", qcOnline.ToQcOnlineResponse())
+	response := qcOnline.ToQcOnlineResponse()
+	response.BoxSuggestions = suggestions
+	response.Parcels = &progress
+
+	utilities.SuccessResponse(c, http.StatusCreated, "Qc-online created successfully", response)
 }
 
 // GetChartQcOnlines godoc
@@ -327,7 +397,7 @@ func (qoc *QcOnlineController) CreateQcOnline(c *gin.Context) {
 // @Router /api/onlines/qc-onlines/chart [get]
 func (qoc *QcOnlineController) GetChartQcOnlines(c *gin.Context) {
 	// Get current month start and end dates
-	now := time.Now()
+	now := utilities.Now()
 	currentYear, currentMonth, _ := now.Date()
 	currentLocation := now.Location()
 
@@ -340,11 +410,11 @@ func (qoc *QcOnlineController) GetChartQcOnlines(c *gin.Context) {
 	// Query to get daily counts for current month
 	var dailyCounts []QcOnlineDailyCount
 
-	if err := qoc.DB.Model(&models.QcOnline{}).
-		Select("DATE(created_at) as date, COUNT(*) as count").
+	if err := qoc.DB.WithContext(c).Model(&models.QcOnline{}).
+		Select(utilities.LocalDate("created_at")+" as date, COUNT(*) as count", utilities.AppTimezone()).
 		Where("created_at >= ?", firstOfMonth).
 		Where("created_at < ?", firstOfNextMonth).
-		Group("DATE(created_at)").
+		Group("date").
 		Order("date ASC").
 		Scan(&dailyCounts).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve qc-online counts", err.Error())
@@ -353,7 +423,7 @@ func (qoc *QcOnlineController) GetChartQcOnlines(c *gin.Context) {
 
 	// Get total count for current month
 	var totalCount int64
-	if err := qoc.DB.Model(&models.QcOnline{}).
+	if err := qoc.DB.WithContext(c).Model(&models.QcOnline{}).
 		Where("created_at >= ?", firstOfMonth).
 		Where("created_at < ?", firstOfNextMonth).
 		Count(&totalCount).Error; err != nil {
@@ -368,7 +438,7 @@ func (qoc *QcOnlineController) GetChartQcOnlines(c *gin.Context) {
 		TotalCount:  int(totalCount),
 	}
 
-	message := "Qc-online daily counts for " + currentMonth.String() + " " + strconv.Itoa(currentYear) + " retrieved successfully"
+	message := utilities.Messagef(c, "Qc-online daily counts for %s %d retrieved successfully", currentMonth, currentYear)
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
 }
@@ -387,6 +457,9 @@ type QcOnlineDetailRequest struct {
 type CreateQcOnlineRequest struct {
 	Tracking string                  `json:"tracking" binding:"required" example:"TRK123456"`
 	Details  []QcOnlineDetailRequest `json:"details" binding:"required,dive,required"`
+
+	ParcelNumber int `json:"parcel_number" binding:"omitempty,min=1" example:"1"` // Parcel of a multi-parcel order, 1 when omitted
+	ParcelCount  int `json:"parcel_count" binding:"omitempty,min=1" example:"2"`  // Parcels the order ships in, the declared count is kept when omitted
 }
 
 // QcOnlineDailyCount represents the count of qc-onlines for a specific date
@@ -1,8 +1,11 @@
 package controllers
 
 import (
+	"fmt"
 	"livo-backend/models"
+	"livo-backend/repositories"
 	"livo-backend/utilities"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -13,12 +16,13 @@ import (
 )
 
 type QcRibbonController struct {
-	DB *gorm.DB
+	DB     *gorm.DB
+	Orders *repositories.OrderRepository
 }
 
 // NewQcRibbonController creates a new qc-ribbon controller
 func NewQcRibbonController(db *gorm.DB) *QcRibbonController {
-	return &QcRibbonController{DB: db}
+	return &QcRibbonController{DB: db, Orders: repositories.NewOrderRepository(db)}
 }
 
 // GetQcRibbons godoc
@@ -31,7 +35,10 @@ func NewQcRibbonController(db *gorm.DB) *QcRibbonController {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param search query string false "Search by tracking number"
+// @Param sort_by query string false "Sort keys, comma separated, each optionally suffixed with :asc or :desc (created_at, tracking)" default(created_at:desc)
+// @Param sort_dir query string false "Direction of sort keys without a suffix: asc or desc" default(asc)
 // @Success 200 {object} utilities.Response{data=QcRibbonsListResponse}
+// @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Router /api/ribbons/qc-ribbons [get]
@@ -39,7 +46,7 @@ func (qrc *QcRibbonController) GetQcRibbons(c *gin.Context) {
 	// Get user ID from JWT token
 	userID, exists := c.Get("user_id")
 	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User not authenticated")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeAuthRequired, "Unauthorized", "User not authenticated")
 		return
 	}
 
@@ -54,14 +61,23 @@ func (qrc *QcRibbonController) GetQcRibbons(c *gin.Context) {
 	var qcRibbons []models.QcRibbon
 	var total int64
 
+	// Today in the warehouse timezone, not the database one
+	todayStart, todayEnd := utilities.DayRange(time.Now())
+
 	// Build query with filters
-	query := qrc.DB.Model(&models.QcRibbon{}).Where("qc_by = ?", userID).Where("DATE(created_at) = CURRENT_DATE")
+	query := qrc.DB.WithContext(c).Model(&models.QcRibbon{}).Where("qc_by = ?", userID).Where("created_at >= ? AND created_at < ?", todayStart, todayEnd)
 
 	if search != "" {
 		// Search by tracking with partial match
 		query = query.Where("tracking ILIKE ?", "%"+search+"%")
 	}
 
+	sortScope, err := utilities.ApplySort(c, models.QcRibbonSortKeys, "created_at:desc", "qc_ribbons.id")
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid sort", err.Error())
+		return
+	}
+
 	// Get total count with filters
 	if err := query.Count(&total).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count qc-ribbons", err.Error())
@@ -69,7 +85,7 @@ func (qrc *QcRibbonController) GetQcRibbons(c *gin.Context) {
 	}
 
 	// Get qc-ribbons with pagination, filters, and preload relationships
-	if err := query.Order("id DESC").
+	if err := query.Scopes(sortScope).
 		Preload("QcRibbonDetails.Box").
 		Preload("QcOperator.UserRoles.Role").
 		Preload("QcOperator.UserRoles.Assigner").
@@ -88,26 +104,13 @@ func (qrc *QcRibbonController) GetQcRibbons(c *gin.Context) {
 			}
 		}
 
-		var orders []models.Order
-		orderMap := make(map[string]*models.Order)
-
-		if len(trackingNumbers) > 0 {
-			if err := qrc.DB.Where("tracking IN ?", trackingNumbers).
-				Preload("OrderDetails").
-				Preload("PickOperator").
-				Find(&orders).Error; err == nil {
-
-				for i := range orders {
-					orderMap[orders[i].Tracking] = &orders[i]
+		if orderMap, err := qrc.Orders.FindByTrackings(c, trackingNumbers); err == nil {
+			for i := range qcRibbons {
+				if order, exists := orderMap[qcRibbons[i].Tracking]; exists {
+					qcRibbons[i].Order = order
 				}
 			}
 		}
-
-		for i := range qcRibbons {
-			if order, exists := orderMap[qcRibbons[i].Tracking]; exists {
-				qcRibbons[i].Order = order
-			}
-		}
 	}
 
 	// Convert to response format
@@ -126,9 +129,9 @@ func (qrc *QcRibbonController) GetQcRibbons(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Qc-ribbons retrieved successfully"
+	message := utilities.Messagef(c, "Qc-ribbons retrieved successfully")
 	if search != "" {
-		message += " (filtered by tracking: " + search + ")"
+		message += utilities.Messagef(c, " (filtered by tracking: %s)", search)
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
@@ -152,17 +155,17 @@ func (qrc *QcRibbonController) GetQcRibbon(c *gin.Context) {
 
 	var qcRibbon models.QcRibbon
 
-	if err := qrc.DB.Preload("QcRibbonDetails.Box").
+	if err := qrc.DB.WithContext(c).Preload("QcRibbonDetails.Box").
 		Preload("QcOperator.UserRoles.Role").
 		Preload("QcOperator.UserRoles.Assigner").
 		First(&qcRibbon, qcRibbonID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Qc-ribbon not found", err.Error())
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeQcNotFound, "Qc-ribbon not found", "Failed to retrieve qc-ribbon")
 		return
 	}
 
 	// Load order call from the model's LoadOrder method
 	if qcRibbon.Tracking != "" {
-		qcRibbon.LoadOrder(qrc.DB)
+		qcRibbon.LoadOrder(qrc.DB.WithContext(c))
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, "Qc-ribbon retrieved successfully", qcRibbon.ToQcRibbonResponse())
@@ -170,23 +173,25 @@ func (qrc *QcRibbonController) GetQcRibbon(c *gin.Context) {
 
 // CreateQcRibbon godoc
 // @Summary Create new qc-ribbon
-// @Description Create a new qc-ribbon entry with multiple box details.
+// @Description Create a new qc-ribbon entry with multiple box details. Orders shipping in several parcels get one entry per parcel_number and parcel_count declares how many parcels there are; the order moves to "qc complete" once every declared parcel is QC'd and stays in "qc process" until then. The same operator scanning the same tracking again within the double scan window gets the existing record back with 200.
 // @Tags ribbons
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param qc_ribbon body CreateQcRibbonRequest true "Qc-ribbon data"
+// @Success 200 {object} utilities.Response{data=models.QcRibbonResponse}
 // @Success 201 {object} utilities.Response{data=models.QcRibbonResponse}
 // @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
 // @Router /api/ribbons/qc-ribbons [post]
 func (qrc *QcRibbonController) CreateQcRibbon(c *gin.Context) {
 	// Get user ID from JWT token
 	userID, exists := c.Get("user_id")
 	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User not authenticated")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeAuthRequired, "Unauthorized", "User not authenticated")
 		return
 	}
 
@@ -198,6 +203,10 @@ func (qrc *QcRibbonController) CreateQcRibbon(c *gin.Context) {
 
 	// Convert tracking to uppercase
 	req.Tracking = strings.ToUpper(strings.TrimSpace(req.Tracking))
+	parcelNumber := req.ParcelNumber
+	if parcelNumber == 0 {
+		parcelNumber = 1
+	}
 
 	// Convert userID to uint
 	userIDUint, ok := userID.(uint)
@@ -206,108 +215,216 @@ func (qrc *QcRibbonController) CreateQcRibbon(c *gin.Context) {
 		return
 	}
 
+	// Reject mis-scans that break the courier's tracking format
+	expedition, err := models.FindExpeditionByTracking(qrc.DB.WithContext(c), req.Tracking)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve expeditions", err.Error())
+		return
+	}
+	if expedition != nil {
+		if err := expedition.ValidateTracking(req.Tracking); err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidTracking, "Invalid tracking number", err.Error())
+			return
+		}
+	}
+
 	// Check if tracking exists in orders table first
 	var order models.Order
-	if err := qrc.DB.Where("tracking = ?", req.Tracking).First(&order).Error; err != nil {
+	if err := qrc.DB.WithContext(c).Preload("OrderDetails").Where("tracking = ?", req.Tracking).First(&order).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			utilities.ErrorResponse(c, http.StatusNotFound, "Order not found", "No order found with the specified tracking number")
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "No order found with the specified tracking number")
 			return
 		}
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to validate tracking", err.Error())
 		return
 	}
 
+	// A held order is not QC'd until it is released
+	if order.IsHeld() {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeOrderHeld, "Order is on hold", "the order of this tracking is on hold and cannot be QC'd until it is released")
+		return
+	}
+
 	// Validate all boxes exist and no duplicates
 	boxIDs := make(map[uint]bool)
 	for _, detail := range req.Details {
 		// Check for duplicate box IDs in the request
 		if boxIDs[detail.BoxID] {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Duplicate box ID", "Each box can only be added once per QC ribbon")
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDuplicateBox, "Duplicate box ID", "Each box can only be added once per QC ribbon")
 			return
 		}
 		boxIDs[detail.BoxID] = true
 
 		// Check if box exists
 		var box models.Box
-		if err := qrc.DB.First(&box, detail.BoxID).Error; err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Box not found", "Invalid box ID: "+strconv.Itoa(int(detail.BoxID)))
+		if err := qrc.DB.WithContext(c).First(&box, detail.BoxID).Error; err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeBoxNotFound, "Box not found", "Invalid box ID: "+strconv.Itoa(int(detail.BoxID)))
 			return
 		}
 
 		// Validate quantity
 		if detail.Quantity <= 0 {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid quantity", "Quantity must be greater than 0")
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid quantity", "Quantity must be greater than 0")
 			return
 		}
 	}
 
 	// Check for duplicate tracking
 	var existingQcRibbon models.QcRibbon
-	if err := qrc.DB.Where("tracking = ?", req.Tracking).First(&existingQcRibbon).Error; err == nil {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Qc-ribbon with this tracking already exists", "Duplicate tracking")
-		return
-	}
-
-	// Start database transaction
-	tx := qrc.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+	if err := qrc.DB.WithContext(c).Where("tracking = ? AND parcel_number = ?", req.Tracking, parcelNumber).First(&existingQcRibbon).Error; err == nil {
+		// The operator scanning the parcel again moments later gets the first scan back
+		if models.IsRepeatScan(existingQcRibbon.QcBy, existingQcRibbon.CreatedAt, userIDUint, time.Now()) {
+			qrc.DB.WithContext(c).Preload("QcRibbonDetails.Box").
+				Preload("QcOperator.UserRoles.Role").
+				Preload("QcOperator.UserRoles.Assigner").
+				First(&existingQcRibbon, existingQcRibbon.ID)
+			existingQcRibbon.LoadOrder(qrc.DB.WithContext(c))
+
+			utilities.SuccessResponse(c, http.StatusOK, "Qc-ribbon already recorded", existingQcRibbon.ToQcRibbonResponse())
+			return
 		}
-	}()
 
-	// Create QC Ribbon
-	qcRibbon := models.QcRibbon{
-		Tracking: req.Tracking,
-		QcBy:     &userIDUint,
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDuplicateTracking, "Qc-ribbon with this tracking already exists", fmt.Sprintf("Parcel %d of this tracking is already QC'd", parcelNumber))
+		return
 	}
 
-	if err := tx.Create(&qcRibbon).Error; err != nil {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create qc-ribbon", err.Error())
+	// Orders shipping in several parcels are QC'd one parcel at a time
+	parcelCount := order.ParcelCount
+	if req.ParcelCount > 0 {
+		parcelCount = req.ParcelCount
+	}
+	parcels, err := models.QcParcelNumbers(qrc.DB.WithContext(c), &models.QcRibbon{}, req.Tracking)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to validate tracking", err.Error())
 		return
 	}
+	if err := models.ValidateQcParcel(parcelNumber, parcelCount, parcels); err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParcel, "Invalid parcel", err.Error())
+		return
+	}
+	progress := models.NewQcParcelProgress(parcelCount, append(parcels, parcelNumber))
 
-	// Create QC Ribbon Detail for each box
+	// Suggest boxes and record whether QC went with the top one; a failing suggestion must not block QC
+	suggestions, err := models.SuggestBoxes(qrc.DB.WithContext(c), &order)
+	if err != nil {
+		log.Printf("⚠️ Box suggestion for %s failed: %v", req.Tracking, err)
+	}
+	chosenBoxIDs := make([]uint, 0, len(req.Details))
 	for _, detail := range req.Details {
-		qcRibbonDetail := models.QcRibbonDetail{
-			QcRibbonID: qcRibbon.ID,
-			BoxID:      detail.BoxID,
-			Quantity:   detail.Quantity,
+		chosenBoxIDs = append(chosenBoxIDs, detail.BoxID)
+	}
+
+	var qcRibbon models.QcRibbon
+	err = utilities.WithTransaction(qrc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Create QC Ribbon
+		qcRibbon = models.QcRibbon{
+			Tracking:           req.Tracking,
+			ParcelNumber:       parcelNumber,
+			QcBy:               &userIDUint,
+			SuggestionAccepted: models.BoxSuggestionAccepted(suggestions, chosenBoxIDs),
+		}
+		if len(suggestions) > 0 {
+			qcRibbon.SuggestedBoxID = &suggestions[0].BoxID
 		}
 
-		if err := tx.Create(&qcRibbonDetail).Error; err != nil {
-			tx.Rollback()
-			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create qc-ribbon detail", err.Error())
-			return
+		if err := tx.Create(&qcRibbon).Error; err != nil {
+			return fmt.Errorf("failed to create qc-ribbon: %w", err)
 		}
-	}
 
-	// Update order processing_status to "qc complete"
-	if err := tx.Model(&models.Order{}).Where("tracking = ?", req.Tracking).Update("processing_status", "qc complete").Error; err != nil {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update order status", err.Error())
-		return
-	}
+		// Create QC Ribbon Detail for each box
+		for _, detail := range req.Details {
+			qcRibbonDetail := models.QcRibbonDetail{
+				QcRibbonID: qcRibbon.ID,
+				BoxID:      detail.BoxID,
+				Quantity:   detail.Quantity,
+			}
+
+			if err := tx.Create(&qcRibbonDetail).Error; err != nil {
+				return fmt.Errorf("failed to create qc-ribbon detail: %w", err)
+			}
+
+			if err := models.ConsumeBoxStock(tx, detail.BoxID, detail.Quantity); err != nil {
+				return fmt.Errorf("failed to update box stock: %w", err)
+			}
+		}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction", err.Error())
+		// The order is "qc complete" once its last parcel is QC'd
+		if err := tx.Model(&models.Order{}).Where("tracking = ?", req.Tracking).Updates(map[string]interface{}{
+			"processing_status": progress.ProcessingStatus(),
+			"parcel_count":      parcelCount,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to create qc-ribbon")
 		return
 	}
 
 	// Load the created qc-ribbon with all relationships
-	qrc.DB.Preload("QcRibbonDetails.Box").
+	qrc.DB.WithContext(c).Preload("QcRibbonDetails.Box").
 		Preload("QcOperator.UserRoles.Role").
 		Preload("QcOperator.UserRoles.Assigner").
 		First(&qcRibbon, qcRibbon.ID)
 
 	// Load order call from the model's LoadOrder method
 	if qcRibbon.Tracking != "" {
-		qcRibbon.LoadOrder(qrc.DB)
+		qcRibbon.LoadOrder(qrc.DB.WithContext(c))
 	}
 
-	utilities.SuccessResponse(c, http.StatusCreated, "Qc-ribbon created successfully", qcRibbon.ToQcRibbonResponse())
+	response := qcRibbon.ToQcRibbonResponse()
+	response.BoxSuggestions = suggestions
+	response.Parcels = &progress
+
+	utilities.SuccessResponse(c, http.StatusCreated, "Qc-ribbon created successfully", response)
+}
+
+// GetBoxSuggestion godoc
+// @Summary Suggest boxes for a tracking
+// @Description Suggest the boxes QC most likely needs for the order of a tracking before it is packed, most likely first: the boxes chosen for earlier orders with the same contents (source history), then for earlier orders with as many products of the same categories (source category), then the smallest box the products fit in (source dimensions). Confidence is the share of similar orders packed in the box, or how snug the fit is.
+// @Tags ribbons
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tracking query string true "Tracking number"
+// @Success 200 {object} utilities.Response{data=BoxSuggestionResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/ribbons/box-suggestion [get]
+func (qrc *QcRibbonController) GetBoxSuggestion(c *gin.Context) {
+	tracking := strings.ToUpper(strings.TrimSpace(c.Query("tracking")))
+	if tracking == "" {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidTracking, "Invalid tracking", "tracking is required")
+		return
+	}
+
+	var order models.Order
+	if err := qrc.DB.WithContext(c).Preload("OrderDetails").Where("tracking = ?", tracking).First(&order).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "No order found with the specified tracking number")
+			return
+		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order", err.Error())
+		return
+	}
+
+	suggestions, err := models.SuggestBoxes(qrc.DB.WithContext(c), &order)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to suggest boxes", err.Error())
+		return
+	}
+	if suggestions == nil {
+		suggestions = []models.BoxSuggestion{}
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Box suggestions retrieved successfully", BoxSuggestionResponse{
+		Tracking:    order.Tracking,
+		Suggestions: suggestions,
+	})
 }
 
 // GetChartQcRibbons godoc
@@ -324,7 +441,7 @@ func (qrc *QcRibbonController) CreateQcRibbon(c *gin.Context) {
 // @Router /api/ribbons/qc-ribbons/chart [get]
 func (qrc *QcRibbonController) GetChartQcRibbons(c *gin.Context) {
 	// Get current month start and end dates
-	now := time.Now()
+	now := utilities.Now()
 	currentYear, currentMonth, _ := now.Date()
 	currentLocation := now.Location()
 
@@ -337,11 +454,11 @@ func (qrc *QcRibbonController) GetChartQcRibbons(c *gin.Context) {
 	// Query to get daily counts for current month
 	var dailyCounts []QcRibbonDailyCount
 
-	if err := qrc.DB.Model(&models.QcRibbon{}).
-		Select("DATE(created_at) as date, COUNT(*) as count").
+	if err := qrc.DB.WithContext(c).Model(&models.QcRibbon{}).
+		Select(utilities.LocalDate("created_at")+" as date, COUNT(*) as count", utilities.AppTimezone()).
 		Where("created_at >= ?", firstOfMonth).
 		Where("created_at < ?", firstOfNextMonth).
-		Group("DATE(created_at)").
+		Group("date").
 		Order("date ASC").
 		Scan(&dailyCounts).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve qc-ribbon counts", err.Error())
@@ -350,7 +467,7 @@ func (qrc *QcRibbonController) GetChartQcRibbons(c *gin.Context) {
 
 	// Get total count for current month
 	var totalCount int64
-	if err := qrc.DB.Model(&models.QcRibbon{}).
+	if err := qrc.DB.WithContext(c).Model(&models.QcRibbon{}).
 		Where("created_at >= ?", firstOfMonth).
 		Where("created_at < ?", firstOfNextMonth).
 		Count(&totalCount).Error; err != nil {
@@ -365,7 +482,7 @@ func (qrc *QcRibbonController) GetChartQcRibbons(c *gin.Context) {
 		TotalCount:  int(totalCount),
 	}
 
-	message := "Qc-ribbon daily counts for " + currentMonth.String() + " " + strconv.Itoa(currentYear) + " retrieved successfully"
+	message := utilities.Messagef(c, "Qc-ribbon daily counts for %s %d retrieved successfully", currentMonth, currentYear)
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
 }
@@ -384,6 +501,9 @@ type QcRibbonDetailRequest struct {
 type CreateQcRibbonRequest struct {
 	Tracking string                  `json:"tracking" binding:"required" example:"250925AASB6BSDJUI3C"`
 	Details  []QcRibbonDetailRequest `json:"details" binding:"required,dive,required"`
+
+	ParcelNumber int `json:"parcel_number" binding:"omitempty,min=1" example:"1"` // Parcel of a multi-parcel order, 1 when omitted
+	ParcelCount  int `json:"parcel_count" binding:"omitempty,min=1" example:"2"`  // Parcels the order ships in, the declared count is kept when omitted
 }
 
 // QcRibbonDailyCount represents the count of qc-ribbons for a specific date
@@ -399,3 +519,8 @@ type QcRibbonsDailyCountResponse struct {
 	DailyCounts []QcRibbonDailyCount `json:"daily_counts"`
 	TotalCount  int                  `json:"total_count"` // Total for the month
 }
+
+type BoxSuggestionResponse struct {
+	Tracking    string                 `json:"tracking"`
+	Suggestions []models.BoxSuggestion `json:"suggestions"`
+}
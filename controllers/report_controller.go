@@ -1,10 +1,14 @@
 package controllers
 
 import (
+	"encoding/csv"
 	"fmt"
 	"livo-backend/models"
+	"livo-backend/repositories"
 	"livo-backend/utilities"
+	"log"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -14,12 +18,13 @@ import (
 )
 
 type ReportController struct {
-	DB *gorm.DB
+	DB      *gorm.DB
+	Returns *repositories.ReturnRepository
 }
 
 // NewReportController creates a new report controller
 func NewReportController(db *gorm.DB) *ReportController {
-	return &ReportController{DB: db}
+	return &ReportController{DB: db, Returns: repositories.NewReturnRepository(db)}
 }
 
 // GetBoxReports godoc
@@ -34,6 +39,7 @@ func NewReportController(db *gorm.DB) *ReportController {
 // @Param start_date query string false "Start date (YYYY-MM-DD format)"
 // @Param end_date query string false "End date (YYYY-MM-DD format)"
 // @Param search query string false "Search by box code or name (partial match)"
+// @Param export query string false "Set to xlsx to download every matching report as a spreadsheet instead of a page of JSON"
 // @Success 200 {object} utilities.Response{data=BoxCountReportsListResponse}
 // @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
@@ -52,29 +58,34 @@ func (rc *ReportController) GetBoxReports(c *gin.Context) {
 	// Parse search parameter
 	search := c.Query("search")
 
+	export, ok := wantsXLSXExport(c)
+	if !ok {
+		return
+	}
+
 	// Build date filter conditions
 	var ribbonDateFilter, onlineDateFilter string
 
 	if startDate != "" {
 		// Validate start date format
-		if _, err := time.Parse("2006-01-02", startDate); err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
+		if _, err := utilities.ParseDate(startDate); err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
 			return
 		}
-		ribbonDateFilter += fmt.Sprintf(" AND qc_ribbon_details.created_at >= '%s 00:00:00'", startDate)
-		onlineDateFilter += fmt.Sprintf(" AND qc_online_details.created_at >= '%s 00:00:00'", startDate)
+		ribbonDateFilter += fmt.Sprintf(" AND qc_ribbon_details.created_at >= '%s'", reportDayStart(startDate))
+		onlineDateFilter += fmt.Sprintf(" AND qc_online_details.created_at >= '%s'", reportDayStart(startDate))
 	}
 
 	if endDate != "" {
 		// Validate end date format
-		if parsedEndDate, err := time.Parse("2006-01-02", endDate); err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
+		if parsedEndDate, err := utilities.ParseDate(endDate); err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
 			return
 		} else {
 			// Add 24 hours to get the start of next day
-			nextDay := parsedEndDate.AddDate(0, 0, 1).Format("2006-01-02 00:00:00")
-			ribbonDateFilter += fmt.Sprintf(" AND qc_ribbon_details.created_at < '%s'", nextDay)
-			onlineDateFilter += fmt.Sprintf(" AND qc_online_details.created_at < '%s'", nextDay)
+			_, nextDay := utilities.DayRange(parsedEndDate)
+			ribbonDateFilter += fmt.Sprintf(" AND qc_ribbon_details.created_at < '%s'", nextDay.Format(time.RFC3339))
+			onlineDateFilter += fmt.Sprintf(" AND qc_online_details.created_at < '%s'", nextDay.Format(time.RFC3339))
 		}
 	}
 
@@ -82,7 +93,7 @@ func (rc *ReportController) GetBoxReports(c *gin.Context) {
 	var total int64
 
 	// First, get the data without pagination for counting
-	countQuery := rc.DB.Table("boxes").
+	countQuery := rc.DB.WithContext(c).Table("boxes").
 		Select("boxes.id").
 		Joins(fmt.Sprintf(`
 			LEFT JOIN (
@@ -121,7 +132,7 @@ func (rc *ReportController) GetBoxReports(c *gin.Context) {
 	}
 
 	// Build main query for data retrieval
-	query := rc.DB.Table("boxes").
+	query := rc.DB.WithContext(c).Table("boxes").
 		Select(`
 			boxes.id as box_id,
 			boxes.code as box_code,
@@ -160,12 +171,12 @@ func (rc *ReportController) GetBoxReports(c *gin.Context) {
 		query = query.Where("boxes.code ILIKE ? OR boxes.name ILIKE ?", "%"+search+"%", "%"+search+"%")
 	}
 
-	// Get reports with pagination
-	if err := query.
-		Order("total_count DESC, boxes.code ASC").
-		Limit(limit).
-		Offset(offset).
-		Scan(&reports).Error; err != nil {
+	// Get reports with pagination, exports take every report
+	query = query.Order("total_count DESC, boxes.code ASC")
+	if !export {
+		query = query.Limit(limit).Offset(offset)
+	}
+	if err := query.Scan(&reports).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve box reports", err.Error())
 		return
 	}
@@ -175,22 +186,23 @@ func (rc *ReportController) GetBoxReports(c *gin.Context) {
 		// Build date filter for details query
 		detailDateFilter := "qc_ribbon_details.deleted_at IS NULL" // Specify table name
 		if startDate != "" {
-			detailDateFilter += fmt.Sprintf(" AND qc_ribbon_details.created_at >= '%s 00:00:00'", startDate)
+			detailDateFilter += fmt.Sprintf(" AND qc_ribbon_details.created_at >= '%s'", reportDayStart(startDate))
 		}
 		if endDate != "" {
-			parsedEndDate, _ := time.Parse("2006-01-02", endDate)
-			nextDay := parsedEndDate.AddDate(0, 0, 1).Format("2006-01-02 00:00:00")
-			detailDateFilter += fmt.Sprintf(" AND qc_ribbon_details.created_at < '%s'", nextDay)
+			parsedEndDate, _ := utilities.ParseDate(endDate)
+			_, nextDay := utilities.DayRange(parsedEndDate)
+			detailDateFilter += fmt.Sprintf(" AND qc_ribbon_details.created_at < '%s'", nextDay.Format(time.RFC3339))
 		}
 
 		// Get QC Ribbon details - JOIN with orders and users table
 		var ribbonDetails []BoxUsageDetail
-		ribbonQuery := rc.DB.Table("qc_ribbon_details").
+		ribbonQuery := rc.DB.WithContext(c).Table("qc_ribbon_details").
 			Select(`
 				qc_ribbons.tracking,
 				COALESCE(orders.order_ginee_id, '') as order_id,
 				boxes.name as box_name,
 				qc_ribbon_details.quantity,
+				qc_ribbons.parcel_number,
 				COALESCE(qc_ribbons.qc_by, 0) as qc_by,
 				COALESCE(users.username, '') as username,
 				COALESCE(users.full_name, '') as full_name,
@@ -213,22 +225,23 @@ func (rc *ReportController) GetBoxReports(c *gin.Context) {
 		// Build date filter for online details
 		onlineDetailDateFilter := "qc_online_details.deleted_at IS NULL" // Specify table name
 		if startDate != "" {
-			onlineDetailDateFilter += fmt.Sprintf(" AND qc_online_details.created_at >= '%s 00:00:00'", startDate)
+			onlineDetailDateFilter += fmt.Sprintf(" AND qc_online_details.created_at >= '%s'", reportDayStart(startDate))
 		}
 		if endDate != "" {
-			parsedEndDate, _ := time.Parse("2006-01-02", endDate)
-			nextDay := parsedEndDate.AddDate(0, 0, 1).Format("2006-01-02 00:00:00")
-			onlineDetailDateFilter += fmt.Sprintf(" AND qc_online_details.created_at < '%s'", nextDay)
+			parsedEndDate, _ := utilities.ParseDate(endDate)
+			_, nextDay := utilities.DayRange(parsedEndDate)
+			onlineDetailDateFilter += fmt.Sprintf(" AND qc_online_details.created_at < '%s'", nextDay.Format(time.RFC3339))
 		}
 
 		// Get QC Online details - JOIN with orders and users table
 		var onlineDetails []BoxUsageDetail
-		onlineQuery := rc.DB.Table("qc_online_details").
+		onlineQuery := rc.DB.WithContext(c).Table("qc_online_details").
 			Select(`
 				qc_onlines.tracking,
 				COALESCE(orders.order_ginee_id, '') as order_id,
 				boxes.name as box_name,
 				qc_online_details.quantity,
+				qc_onlines.parcel_number,
 				COALESCE(qc_onlines.qc_by, 0) as qc_by,
 				COALESCE(users.username, '') as username,
 				COALESCE(users.full_name, '') as full_name,
@@ -252,6 +265,11 @@ func (rc *ReportController) GetBoxReports(c *gin.Context) {
 		reports[i].Details = append(ribbonDetails, onlineDetails...)
 	}
 
+	if export {
+		sendBoxReportsXLSX(c, reports, startDate, endDate)
+		return
+	}
+
 	response := BoxCountReportsListResponse{
 		Reports: reports,
 		Pagination: utilities.PaginationResponse{
@@ -262,7 +280,7 @@ func (rc *ReportController) GetBoxReports(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Box count reports retrieved successfully"
+	message := utilities.Messagef(c, "Box count reports retrieved successfully")
 	var filters []string
 
 	if startDate != "" || endDate != "" {
@@ -281,7 +299,7 @@ func (rc *ReportController) GetBoxReports(c *gin.Context) {
 	}
 
 	if len(filters) > 0 {
-		message += fmt.Sprintf(" (filtered by %s)", strings.Join(filters, " | "))
+		message += utilities.Messagef(c, " (filtered by %s)", strings.Join(filters, " | "))
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
@@ -296,6 +314,7 @@ func (rc *ReportController) GetBoxReports(c *gin.Context) {
 // @Security BearerAuth
 // @Param date query string false "Filter by date (YYYY-MM-DD format)"
 // @Param search query string false "Search by exact slug match"
+// @Param export query string false "Set to xlsx to download the report as a spreadsheet"
 // @Success 200 {object} utilities.Response{data=OutboundReportsListResponse}
 // @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
@@ -308,22 +327,26 @@ func (rc *ReportController) GetOutboundReports(c *gin.Context) {
 	// Parse search parameter
 	search := c.Query("search")
 
+	export, ok := wantsXLSXExport(c)
+	if !ok {
+		return
+	}
+
 	var outbounds []models.Outbound
 	var total int64
 
 	// Build query for data retrieval
-	query := rc.DB.Model(&models.Outbound{})
+	query := rc.DB.WithContext(c).Model(&models.Outbound{})
 
 	// Apply date filter if provided
 	if date != "" {
 		// Parse date and validate format
-		if parsedDate, err := time.Parse("2006-01-02", date); err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid date format", "date must be in YYYY-MM-DD format")
+		if parsedDate, err := utilities.ParseDate(date); err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid date format", "date must be in YYYY-MM-DD format")
 			return
 		} else {
 			// Filter for the entire day (from 00:00:00 to 23:59:59)
-			startOfDay := parsedDate.Format("2006-01-02 00:00:00")
-			endOfDay := parsedDate.AddDate(0, 0, 1).Format("2006-01-02 00:00:00")
+			startOfDay, endOfDay := utilities.DayRange(parsedDate)
 			query = query.Where("created_at >= ? AND created_at < ?", startOfDay, endOfDay)
 		}
 	}
@@ -349,6 +372,11 @@ func (rc *ReportController) GetOutboundReports(c *gin.Context) {
 		return
 	}
 
+	if export {
+		sendOutboundReportsXLSX(c, outbounds, date)
+		return
+	}
+
 	// Convert to response format
 	outboundResponses := make([]models.OutboundResponse, len(outbounds))
 	for i, outbound := range outbounds {
@@ -361,7 +389,7 @@ func (rc *ReportController) GetOutboundReports(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Outbound reports retrieved successfully"
+	message := utilities.Messagef(c, "Outbound reports retrieved successfully")
 	var filters []string
 
 	if date != "" {
@@ -373,7 +401,7 @@ func (rc *ReportController) GetOutboundReports(c *gin.Context) {
 	}
 
 	if len(filters) > 0 {
-		message += fmt.Sprintf(" (filtered by %s)", strings.Join(filters, " | "))
+		message += utilities.Messagef(c, " (filtered by %s)", strings.Join(filters, " | "))
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
@@ -388,6 +416,7 @@ func (rc *ReportController) GetOutboundReports(c *gin.Context) {
 // @Security BearerAuth
 // @Param date query string false "Filter by date (YYYY-MM-DD format)"
 // @Param search query string false "Search by exact return type match"
+// @Param export query string false "Set to xlsx to download the report as a spreadsheet"
 // @Success 200 {object} utilities.Response{data=ReturnReportsListResponse}
 // @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
@@ -400,22 +429,26 @@ func (rc *ReportController) GetReturnReports(c *gin.Context) {
 	// Parse search parameter
 	search := c.Query("search")
 
+	export, ok := wantsXLSXExport(c)
+	if !ok {
+		return
+	}
+
 	var returns []models.Return
 	var total int64
 
 	// Build query for data retrieval
-	query := rc.DB.Model(&models.Return{})
+	query := rc.DB.WithContext(c).Model(&models.Return{})
 
 	// Apply date filter if provided (CHANGED: using updated_at instead of created_at)
 	if date != "" {
 		// Parse date and validate format
-		if parsedDate, err := time.Parse("2006-01-02", date); err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid date format", "date must be in YYYY-MM-DD format")
+		if parsedDate, err := utilities.ParseDate(date); err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid date format", "date must be in YYYY-MM-DD format")
 			return
 		} else {
 			// Filter for the entire day (from 00:00:00 to 23:59:59)
-			startOfDay := parsedDate.Format("2006-01-02 00:00:00")
-			endOfDay := parsedDate.AddDate(0, 0, 1).Format("2006-01-02 00:00:00")
+			startOfDay, endOfDay := utilities.DayRange(parsedDate)
 			query = query.Where("updated_at >= ? AND updated_at < ?", startOfDay, endOfDay)
 		}
 	}
@@ -447,18 +480,20 @@ func (rc *ReportController) GetReturnReports(c *gin.Context) {
 
 	// Manually load order data for each return using OldTracking
 	for i := range returns {
-		if returns[i].OldTracking != "" {
-			var order models.Order
-			if err := rc.DB.
-				Preload("OrderDetails").
-				Preload("PickOperator").
-				Preload("PickOperator.UserRoles.Role").
-				Preload("PickOperator.UserRoles.Assigner").
-				Where("tracking = ?", returns[i].OldTracking).
-				First(&order).Error; err == nil {
-				returns[i].Order = &order
-			}
+		if err := rc.Returns.AttachOrder(c, &returns[i], repositories.OrderPickerRoles); err != nil {
+			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve return reports", err.Error())
+			return
 		}
+
+		// Exchanges the return is part of
+		if chain, err := models.LoadReplacementChain(rc.DB.WithContext(c), &returns[i]); err == nil {
+			returns[i].ReplacementChain = chain
+		}
+	}
+
+	if export {
+		sendReturnReportsXLSX(c, returns, date)
+		return
 	}
 
 	// Convert to response format
@@ -473,7 +508,7 @@ func (rc *ReportController) GetReturnReports(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Return reports retrieved successfully"
+	message := utilities.Messagef(c, "Return reports retrieved successfully")
 	var filters []string
 
 	if date != "" {
@@ -485,7 +520,7 @@ func (rc *ReportController) GetReturnReports(c *gin.Context) {
 	}
 
 	if len(filters) > 0 {
-		message += fmt.Sprintf(" (filtered by %s)", strings.Join(filters, " | "))
+		message += utilities.Messagef(c, " (filtered by %s)", strings.Join(filters, " | "))
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
@@ -499,6 +534,7 @@ func (rc *ReportController) GetReturnReports(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param date query string false "Filter by date (YYYY-MM-DD format)"
+// @Param export query string false "Set to xlsx to download the report as a spreadsheet"
 // @Success 200 {object} utilities.Response{data=ComplainReportsListResponse}
 // @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
@@ -508,22 +544,26 @@ func (rc *ReportController) GetComplainReports(c *gin.Context) {
 	// Parse date parameter
 	date := c.Query("date")
 
+	export, ok := wantsXLSXExport(c)
+	if !ok {
+		return
+	}
+
 	var complains []models.Complain
 	var total int64
 
 	// Build query for data retrieval
-	query := rc.DB.Model(&models.Complain{})
+	query := rc.DB.WithContext(c).Model(&models.Complain{})
 
 	// Apply date filter if provided (using updated_at)
 	if date != "" {
 		// Parse date and validate format
-		if parsedDate, err := time.Parse("2006-01-02", date); err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid date format", "date must be in YYYY-MM-DD format")
+		if parsedDate, err := utilities.ParseDate(date); err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid date format", "date must be in YYYY-MM-DD format")
 			return
 		} else {
 			// Filter for the entire day (from 00:00:00 to 23:59:59)
-			startOfDay := parsedDate.Format("2006-01-02 00:00:00")
-			endOfDay := parsedDate.AddDate(0, 0, 1).Format("2006-01-02 00:00:00")
+			startOfDay, endOfDay := utilities.DayRange(parsedDate)
 			query = query.Where("updated_at >= ? AND updated_at < ?", startOfDay, endOfDay)
 		}
 	}
@@ -546,6 +586,17 @@ func (rc *ReportController) GetComplainReports(c *gin.Context) {
 		return
 	}
 
+	// Returns of the complained parcels with their replacement chains
+	if err := rc.loadComplainReturns(c, complains); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve complain reports", err.Error())
+		return
+	}
+
+	if export {
+		sendComplainReportsXLSX(c, complains, date)
+		return
+	}
+
 	// Convert to response format
 	complainResponses := make([]models.ComplainResponse, len(complains))
 	for i, comp := range complains {
@@ -558,7 +609,7 @@ func (rc *ReportController) GetComplainReports(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Complain reports retrieved successfully"
+	message := utilities.Messagef(c, "Complain reports retrieved successfully")
 	var filters []string
 
 	if date != "" {
@@ -566,12 +617,43 @@ func (rc *ReportController) GetComplainReports(c *gin.Context) {
 	}
 
 	if len(filters) > 0 {
-		message += fmt.Sprintf(" (filtered by %s)", strings.Join(filters, " | "))
+		message += utilities.Messagef(c, " (filtered by %s)", strings.Join(filters, " | "))
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
 }
 
+// loadComplainReturns attaches the return of each complained parcel, matched by old tracking, with
+// the replacement chain of the return
+func (rc *ReportController) loadComplainReturns(c *gin.Context, complains []models.Complain) error {
+	trackings := make([]string, 0, len(complains))
+	for _, complain := range complains {
+		trackings = append(trackings, complain.Tracking)
+	}
+	if len(trackings) == 0 {
+		return nil
+	}
+
+	var returns []models.Return
+	if err := rc.DB.WithContext(c).Where("old_tracking IN ?", trackings).Find(&returns).Error; err != nil {
+		return err
+	}
+	returnsByTracking := make(map[string]*models.Return, len(returns))
+	for i := range returns {
+		chain, err := models.LoadReplacementChain(rc.DB.WithContext(c), &returns[i])
+		if err != nil {
+			return err
+		}
+		returns[i].ReplacementChain = chain
+		returnsByTracking[returns[i].OldTracking] = &returns[i]
+	}
+
+	for i := range complains {
+		complains[i].Return = returnsByTracking[complains[i].Tracking]
+	}
+	return nil
+}
+
 // GetUserFeeReports godoc
 // @Summary Get user fee reports
 // @Description Get user fee reports with detailed records, date filtering and exact user search, with pagination (logged-in users only)
@@ -609,22 +691,22 @@ func (rc *ReportController) GetUserFeeReports(c *gin.Context) {
 
 	if startDate != "" {
 		// Validate start date format
-		if _, err := time.Parse("2006-01-02", startDate); err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
+		if _, err := utilities.ParseDate(startDate); err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
 			return
 		}
-		dateFilterCondition += fmt.Sprintf(" AND complains.updated_at >= '%s 00:00:00'", startDate)
+		dateFilterCondition += fmt.Sprintf(" AND complains.updated_at >= '%s'", reportDayStart(startDate))
 	}
 
 	if endDate != "" {
 		// Validate end date format
-		if parsedEndDate, err := time.Parse("2006-01-02", endDate); err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
+		if parsedEndDate, err := utilities.ParseDate(endDate); err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
 			return
 		} else {
 			// Add 24 hours to get the start of next day
-			nextDay := parsedEndDate.AddDate(0, 0, 1).Format("2006-01-02 00:00:00")
-			dateFilterCondition += fmt.Sprintf(" AND complains.updated_at < '%s'", nextDay)
+			_, nextDay := utilities.DayRange(parsedEndDate)
+			dateFilterCondition += fmt.Sprintf(" AND complains.updated_at < '%s'", nextDay.Format(time.RFC3339))
 		}
 	}
 
@@ -635,7 +717,7 @@ func (rc *ReportController) GetUserFeeReports(c *gin.Context) {
 	}
 
 	// First, get the data without pagination for counting unique users
-	countQuery := rc.DB.Table("complain_user_details").
+	countQuery := rc.DB.WithContext(c).Table("complain_user_details").
 		Select("DISTINCT complain_user_details.operator_id").
 		Joins("INNER JOIN complains ON complains.id = complain_user_details.complain_id").
 		Where(dateFilterCondition).
@@ -649,7 +731,7 @@ func (rc *ReportController) GetUserFeeReports(c *gin.Context) {
 
 	// Step 1: Get user summary data
 	var userSummaries []UserFeeReportSummary
-	summaryQuery := rc.DB.Table("complain_user_details").
+	summaryQuery := rc.DB.WithContext(c).Table("complain_user_details").
 		Select(`
 			complain_user_details.operator_id,
 			users.username,
@@ -678,7 +760,7 @@ func (rc *ReportController) GetUserFeeReports(c *gin.Context) {
 	for _, summary := range userSummaries {
 		// Get detailed records for this user
 		var details []ComplainDetailInReport
-		detailQuery := rc.DB.Table("complain_user_details").
+		detailQuery := rc.DB.WithContext(c).Table("complain_user_details").
 			Select(`
 				complains.id as complain_id,
 				complains.code as complain_code,
@@ -722,7 +804,7 @@ func (rc *ReportController) GetUserFeeReports(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "User fee reports retrieved successfully"
+	message := utilities.Messagef(c, "User fee reports retrieved successfully")
 	var filters []string
 
 	if startDate != "" || endDate != "" {
@@ -741,94 +823,1619 @@ func (rc *ReportController) GetUserFeeReports(c *gin.Context) {
 	}
 
 	if len(filters) > 0 {
-		message += fmt.Sprintf(" (filtered by %s)", strings.Join(filters, " | "))
+		message += utilities.Messagef(c, " (filtered by %s)", strings.Join(filters, " | "))
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
 }
 
-// Request/Response structs
-// BoxUsageDetail represents individual box usage record
-type BoxUsageDetail struct {
-	Tracking  string    `json:"tracking"`
-	OrderID   string    `json:"order_ginee_id"`
-	BoxName   string    `json:"box_name"`
-	Quantity  int       `json:"quantity"`
-	QcBy      uint      `json:"qc_by"`     // Added QC By user ID
-	Username  string    `json:"username"`  // Added username
-	FullName  string    `json:"full_name"` // Added full name
-	CreatedAt time.Time `json:"created_at"`
-	Source    string    `json:"source"` // "QC Ribbon" or "QC Online"
-}
+// GetComplainEscalationReports godoc
+// @Summary Get complain escalation reports
+// @Description Get escalation volume and time spent per tier (CS, coordinator, management) for complains created in the date range (logged-in users only)
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string false "Start date (YYYY-MM-DD format)"
+// @Param end_date query string false "End date (YYYY-MM-DD format)"
+// @Success 200 {object} utilities.Response{data=ComplainEscalationReportResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/reports/complain-escalations [get]
+func (rc *ReportController) GetComplainEscalationReports(c *gin.Context) {
+	// Parse date range parameters
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
 
-// BoxCountReport represents box count report
-type BoxCountReport struct {
-	BoxID       uint             `json:"box_id"`
-	BoxCode     string           `json:"box_code"`
-	BoxName     string           `json:"box_name"`
-	TotalCount  int              `json:"total_count"`
-	RibbonCount int              `json:"ribbon_count"`
-	OnlineCount int              `json:"online_count"`
-	Details     []BoxUsageDetail `json:"details" gorm:"-"` // Added gorm:"-" to ignore this field
-}
+	query := rc.DB.WithContext(c).Model(&models.Complain{})
 
-// BoxCountReportsListResponse represents the response for box count reports
-type BoxCountReportsListResponse struct {
-	Reports    []BoxCountReport             `json:"reports"`
-	Pagination utilities.PaginationResponse `json:"pagination"`
-}
+	if startDate != "" {
+		if parsedStartDate, err := utilities.ParseDate(startDate); err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
+			return
+		} else {
+			startOfDay, _ := utilities.DayRange(parsedStartDate)
+			query = query.Where("created_at >= ?", startOfDay)
+		}
+	}
 
-// OutboundReportsListResponse represents the response for outbound reports
-type OutboundReportsListResponse struct {
-	Outbounds []models.OutboundResponse `json:"outbounds"`
-	Total     int                       `json:"total"`
-}
+	if endDate != "" {
+		if parsedEndDate, err := utilities.ParseDate(endDate); err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
+			return
+		} else {
+			// Add 24 hours to get the start of next day, then use < instead of <=
+			_, nextDay := utilities.DayRange(parsedEndDate)
+			query = query.Where("created_at < ?", nextDay)
+		}
+	}
 
-// ReturnReportsListResponse represents the response for return reports
-type ReturnReportsListResponse struct {
-	Returns []models.ReturnResponse `json:"returns"`
-	Total   int                     `json:"total"`
+	var complains []models.Complain
+	if err := query.Preload("Escalations", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).Find(&complains).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve complains", err.Error())
+		return
+	}
+
+	tiers := make(map[string]*ComplainEscalationTierReport, len(models.ComplainEscalationLevels))
+	for _, level := range models.ComplainEscalationLevels {
+		tiers[level] = &ComplainEscalationTierReport{Level: level}
+	}
+
+	now := time.Now()
+	for _, complain := range complains {
+		// Walk the escalation history, each entry closes the time spent in the previous tier
+		level := models.ComplainEscalationCS
+		enteredAt := complain.CreatedAt
+
+		for _, escalation := range complain.Escalations {
+			if tier, ok := tiers[escalation.FromLevel]; ok {
+				tier.Entered++
+				tier.TotalHours += escalation.CreatedAt.Sub(enteredAt).Hours()
+				if escalation.EscalatedBy == nil {
+					tier.AutoEscalated++
+				} else {
+					tier.ManualEscalated++
+				}
+			}
+			level = escalation.ToLevel
+			enteredAt = escalation.CreatedAt
+		}
+
+		// Time in the final tier runs until the complain was checked, or until now
+		endAt := now
+		if complain.Checked {
+			endAt = complain.UpdatedAt
+		}
+		if tier, ok := tiers[level]; ok {
+			tier.Entered++
+			tier.TotalHours += endAt.Sub(enteredAt).Hours()
+			if complain.Checked {
+				tier.Resolved++
+			} else {
+				tier.Open++
+			}
+		}
+	}
+
+	reports := make([]ComplainEscalationTierReport, 0, len(models.ComplainEscalationLevels))
+	for _, level := range models.ComplainEscalationLevels {
+		tier := tiers[level]
+		if tier.Entered > 0 {
+			tier.AverageHours = tier.TotalHours / float64(tier.Entered)
+		}
+		reports = append(reports, *tier)
+	}
+
+	response := ComplainEscalationReportResponse{
+		Tiers:          reports,
+		TotalComplains: len(complains),
+	}
+
+	// Build success message
+	message := utilities.Messagef(c, "Complain escalation reports retrieved successfully")
+	var filters []string
+
+	if startDate != "" || endDate != "" {
+		var dateRange []string
+		if startDate != "" {
+			dateRange = append(dateRange, "from: "+startDate)
+		}
+		if endDate != "" {
+			dateRange = append(dateRange, "to: "+endDate)
+		}
+		filters = append(filters, "date: "+strings.Join(dateRange, ", "))
+	}
+
+	if len(filters) > 0 {
+		message += utilities.Messagef(c, " (filtered by %s)", strings.Join(filters, " | "))
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, message, response)
 }
 
-// ComplainReportsListResponse represents the response for complain reports
-type ComplainReportsListResponse struct {
-	Complains []models.ComplainResponse `json:"complains"`
-	Total     int                       `json:"total"`
+// GetDailySummary godoc
+// @Summary Get daily summary
+// @Description Get the daily summary (orders created, picked and cancelled, QC, outbounds per expedition, returns, complains) that is also sent by the scheduled report
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param date query string false "Summary date (YYYY-MM-DD format), defaults to today"
+// @Success 200 {object} utilities.Response{data=models.DailySummary}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/reports/daily-summary [get]
+func (rc *ReportController) GetDailySummary(c *gin.Context) {
+	date := time.Now()
+	if dateParam := c.Query("date"); dateParam != "" {
+		parsedDate, err := utilities.ParseDate(dateParam)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid date format", "date must be in YYYY-MM-DD format")
+			return
+		}
+		date = parsedDate
+	}
+
+	summary, err := models.BuildDailySummary(rc.DB.WithContext(c), date)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build daily summary", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Daily summary retrieved successfully", summary)
 }
 
-// UserFeeReportSummary represents user fee report summary data (without details)
-type UserFeeReportSummary struct {
-	UserID          uint   `json:"user_id"`
-	Username        string `json:"username"`
-	FullName        string `json:"full_name"`
-	Email           string `json:"email"`
-	TotalComplaints int    `json:"total_complaints"`
-	TotalFeeCharge  uint   `json:"total_fee_charge"`
+// GetChannelIntakeReport godoc
+// @Summary Get channel intake report
+// @Description Get the orders each channel took in on a day against its daily quota and its baseline, the average daily orders over the CHANNEL_INTAKE_BASELINE_DAYS days before. For today the expected orders cover the day up to now. Channels over their quota or far below the expected orders are flagged, with the intake alerts raised that day.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param date query string false "Intake date (YYYY-MM-DD format), defaults to today"
+// @Success 200 {object} utilities.Response{data=ChannelIntakeReportResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/reports/channel-intake [get]
+func (rc *ReportController) GetChannelIntakeReport(c *gin.Context) {
+	now := time.Now()
+	date := now
+	if dateParam := c.Query("date"); dateParam != "" {
+		parsedDate, err := utilities.ParseDate(dateParam)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid date format", "date must be in YYYY-MM-DD format")
+			return
+		}
+		date = parsedDate
+	}
+
+	channels, err := models.BuildChannelIntake(rc.DB.WithContext(c), date, now)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build channel intake report", err.Error())
+		return
+	}
+
+	alerts, err := models.ChannelIntakeAlertsOn(rc.DB.WithContext(c), date)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build channel intake report", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Channel intake report retrieved successfully", ChannelIntakeReportResponse{
+		Date:         date.In(utilities.AppLocation()).Format("2006-01-02"),
+		BaselineDays: models.CurrentSettings().ChannelIntake.BaselineDays,
+		Channels:     channels,
+		Alerts:       alerts,
+	})
 }
 
-// ComplainDetailInReport represents individual complain details in user fee report
-type ComplainDetailInReport struct {
-	ComplainID        uint      `json:"complain_id"`
-	ComplainCode      string    `json:"complain_code"`
-	Tracking          string    `json:"tracking"`
-	OrderGineeID      string    `json:"order_ginee_id"`
-	FeeCharge         uint      `json:"fee_charge"`
-	ComplainUpdatedAt time.Time `json:"complain_updated_at"`
+// GetBoxForecastReport godoc
+// @Summary Get box stock forecast
+// @Description Get the stock of every box whose stock is tracked, its average daily usage at QC over the usage window and when it is projected to run out, boxes running out first listed first. Low boxes run out within the alert window.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utilities.Response{data=BoxForecastReportResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/reports/box-forecast [get]
+func (rc *ReportController) GetBoxForecastReport(c *gin.Context) {
+	forecasts, err := models.ForecastBoxStock(rc.DB.WithContext(c), time.Now())
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build box forecast", err.Error())
+		return
+	}
+
+	policy := models.CurrentSettings().BoxStock
+	response := BoxForecastReportResponse{
+		UsageDays: policy.UsageDays,
+		AlertDays: policy.AlertDays,
+		Boxes:     forecasts,
+	}
+	for _, forecast := range forecasts {
+		if forecast.Low {
+			response.Low++
+		}
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Box forecast retrieved successfully", response)
 }
 
-// UserFeeReportWithDetails represents user fee report data with detailed records
-type UserFeeReportWithDetails struct {
-	UserID          uint                     `json:"user_id"`
-	Username        string                   `json:"username"`
-	FullName        string                   `json:"full_name"`
-	Email           string                   `json:"email"`
-	TotalComplaints int                      `json:"total_complaints"`
-	TotalFeeCharge  uint                     `json:"total_fee_charge"`
-	ComplainDetails []ComplainDetailInReport `json:"complain_details"`
+// GetBoxSuggestionReport godoc
+// @Summary Get box suggestion acceptance report
+// @Description Get how often QC went with the top box suggestion, overall and per suggested box, for QC Ribbon and QC Online in the date range
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string false "Start date (YYYY-MM-DD format)"
+// @Param end_date query string false "End date (YYYY-MM-DD format)"
+// @Success 200 {object} utilities.Response{data=BoxSuggestionReportResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/reports/box-suggestions [get]
+func (rc *ReportController) GetBoxSuggestionReport(c *gin.Context) {
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+
+	ribbons := rc.DB.WithContext(c).Model(&models.QcRibbon{}).Select("suggested_box_id, suggestion_accepted")
+	onlines := rc.DB.WithContext(c).Model(&models.QcOnline{}).Select("suggested_box_id, suggestion_accepted")
+
+	if startDate != "" {
+		parsedStartDate, err := utilities.ParseDate(startDate)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
+			return
+		}
+		ribbons = ribbons.Where("created_at >= ?", parsedStartDate)
+		onlines = onlines.Where("created_at >= ?", parsedStartDate)
+	}
+
+	if endDate != "" {
+		parsedEndDate, err := utilities.ParseDate(endDate)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
+			return
+		}
+		ribbons = ribbons.Where("created_at < ?", parsedEndDate.AddDate(0, 0, 1))
+		onlines = onlines.Where("created_at < ?", parsedEndDate.AddDate(0, 0, 1))
+	}
+
+	qcs := rc.DB.WithContext(c).Raw("? UNION ALL ?", ribbons, onlines)
+
+	var response BoxSuggestionReportResponse
+	if err := rc.DB.WithContext(c).Table("(?) AS qcs", qcs).
+		Select("COUNT(*) AS total_qc, COUNT(suggested_box_id) AS suggested, COUNT(*) FILTER (WHERE suggestion_accepted) AS accepted").
+		Scan(&response).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build box suggestion report", err.Error())
+		return
+	}
+
+	response.Boxes = []BoxSuggestionStat{}
+	if err := rc.DB.WithContext(c).Table("(?) AS qcs", qcs).
+		Select("boxes.id AS box_id, boxes.code, boxes.name, COUNT(*) AS suggested, COUNT(*) FILTER (WHERE qcs.suggestion_accepted) AS accepted").
+		Joins("JOIN boxes ON boxes.id = qcs.suggested_box_id").
+		Group("boxes.id, boxes.code, boxes.name").
+		Order("suggested DESC, boxes.code").
+		Scan(&response.Boxes).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build box suggestion report", err.Error())
+		return
+	}
+
+	if response.Suggested > 0 {
+		response.AcceptanceRate = float64(response.Accepted) / float64(response.Suggested)
+	}
+	for i := range response.Boxes {
+		response.Boxes[i].AcceptanceRate = float64(response.Boxes[i].Accepted) / float64(response.Boxes[i].Suggested)
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Box suggestion report retrieved successfully", response)
 }
 
-// UserFeeReportsWithDetailsListResponse represents the response for user fee reports with details
-type UserFeeReportsWithDetailsListResponse struct {
-	Reports    []UserFeeReportWithDetails   `json:"reports"`
-	Pagination utilities.PaginationResponse `json:"pagination"`
+// GetReturnValuationReport godoc
+// @Summary Get returns to stock valuation report
+// @Description Get the value of returned items put back into stock and of scrapped items per period, using product cost and scrap value. A return counts as scrapped once it has a scrap number and as restocked once it has a return number; returns with neither are reported as pending (finance and superadmin only)
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string true "Start date (YYYY-MM-DD format)"
+// @Param end_date query string true "End date (YYYY-MM-DD format)"
+// @Param period query string false "Period to group by: day or month (default month)"
+// @Success 200 {object} utilities.Response{data=ReturnValuationReportResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/reports/return-valuation [get]
+func (rc *ReportController) GetReturnValuationReport(c *gin.Context) {
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+	if startDate == "" || endDate == "" {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDateRangeInvalid, "Date range is required", "start_date and end_date are required")
+		return
+	}
+
+	parsedStartDate, err := utilities.ParseDate(startDate)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
+		return
+	}
+	parsedEndDate, err := utilities.ParseDate(endDate)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
+		return
+	}
+	if parsedEndDate.Before(parsedStartDate) {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDateRangeInvalid, "Invalid date range", "end_date must not be before start_date")
+		return
+	}
+
+	period := c.DefaultQuery("period", "month")
+	var periodFormat string
+	switch period {
+	case "day":
+		periodFormat = "YYYY-MM-DD"
+	case "month":
+		periodFormat = "YYYY-MM"
+	default:
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid period", "period must be day or month")
+		return
+	}
+
+	// Returns are dated by their last update, when the return or scrap number is filled in
+	var rows []ReturnValuationRow
+	if err := rc.DB.WithContext(c).Table("return_details").
+		Select(`TO_CHAR(returns.updated_at, ?) AS period,
+			CASE WHEN returns.scrap_number <> '' THEN 'scrapped' WHEN returns.return_number <> '' THEN 'restocked' ELSE 'pending' END AS disposition,
+			SUM(return_details.quantity) AS quantity,
+			SUM(return_details.quantity * products.cost) AS cost_value,
+			SUM(return_details.quantity * products.scrap_value) AS scrap_value,
+			COALESCE(SUM(return_details.quantity) FILTER (WHERE products.cost = 0), 0) AS unvalued_quantity`, periodFormat).
+		Joins("JOIN returns ON returns.id = return_details.return_id AND returns.deleted_at IS NULL").
+		Joins("JOIN products ON products.id = return_details.product_id").
+		Where("return_details.deleted_at IS NULL").
+		Where("returns.updated_at >= ? AND returns.updated_at < ?", parsedStartDate, parsedEndDate.AddDate(0, 0, 1)).
+		Group("1, 2").
+		Order("1, 2").
+		Scan(&rows).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build return valuation report", err.Error())
+		return
+	}
+
+	response := ReturnValuationReportResponse{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Period:    period,
+		Periods:   []ReturnValuationPeriod{},
+	}
+	for _, row := range rows {
+		if len(response.Periods) == 0 || response.Periods[len(response.Periods)-1].Period != row.Period {
+			response.Periods = append(response.Periods, ReturnValuationPeriod{Period: row.Period})
+		}
+		current := &response.Periods[len(response.Periods)-1]
+		current.add(row)
+		response.Total.add(row)
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Return valuation report retrieved successfully", response)
+}
+
+// GetPackingBillingReport godoc
+// @Summary Get packing material billing report
+// @Description Get the boxes used per store in a date range from QC ribbon and QC online details, priced at each box's packing material cost, for intercompany billing. Stores are taken from the order of the QC'd tracking (finance and superadmin only)
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string true "Start date (YYYY-MM-DD format)"
+// @Param end_date query string true "End date (YYYY-MM-DD format)"
+// @Param store query string false "Limit the report to one store"
+// @Param export query string false "Set to xlsx to download the report as a spreadsheet"
+// @Success 200 {object} utilities.Response{data=PackingBillingReportResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/reports/packing-billing [get]
+func (rc *ReportController) GetPackingBillingReport(c *gin.Context) {
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+	if startDate == "" || endDate == "" {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDateRangeInvalid, "Date range is required", "start_date and end_date are required")
+		return
+	}
+
+	parsedStartDate, err := utilities.ParseDate(startDate)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
+		return
+	}
+	parsedEndDate, err := utilities.ParseDate(endDate)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
+		return
+	}
+	if parsedEndDate.Before(parsedStartDate) {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDateRangeInvalid, "Invalid date range", "end_date must not be before start_date")
+		return
+	}
+
+	export, ok := wantsXLSXExport(c)
+	if !ok {
+		return
+	}
+
+	store := strings.TrimSpace(c.Query("store"))
+	end := parsedEndDate.AddDate(0, 0, 1)
+
+	// Box usage of both QC lines, attributed to the store of the QC'd order
+	usage := rc.DB.WithContext(c).Raw(`
+		SELECT qc_ribbons.tracking, qc_ribbon_details.box_id, qc_ribbon_details.quantity AS ribbon_quantity, 0 AS online_quantity
+		FROM qc_ribbon_details
+		JOIN qc_ribbons ON qc_ribbons.id = qc_ribbon_details.qc_ribbon_id AND qc_ribbons.deleted_at IS NULL
+		WHERE qc_ribbon_details.deleted_at IS NULL AND qc_ribbon_details.created_at >= ? AND qc_ribbon_details.created_at < ?
+		UNION ALL
+		SELECT qc_onlines.tracking, qc_online_details.box_id, 0, qc_online_details.quantity
+		FROM qc_online_details
+		JOIN qc_onlines ON qc_onlines.id = qc_online_details.qc_online_id AND qc_onlines.deleted_at IS NULL
+		WHERE qc_online_details.deleted_at IS NULL AND qc_online_details.created_at >= ? AND qc_online_details.created_at < ?`,
+		parsedStartDate, end, parsedStartDate, end)
+
+	query := rc.DB.WithContext(c).Table("(?) AS box_usage", usage).
+		Select(`orders.store AS store,
+			boxes.id AS box_id,
+			boxes.code AS box_code,
+			boxes.name AS box_name,
+			boxes.cost AS unit_cost,
+			SUM(box_usage.ribbon_quantity) AS ribbon_count,
+			SUM(box_usage.online_quantity) AS online_count,
+			SUM(box_usage.ribbon_quantity + box_usage.online_quantity) AS total_count,
+			SUM(box_usage.ribbon_quantity + box_usage.online_quantity) * boxes.cost AS amount`).
+		Joins("JOIN orders ON orders.tracking = box_usage.tracking AND orders.deleted_at IS NULL").
+		Joins("JOIN boxes ON boxes.id = box_usage.box_id").
+		Group("orders.store, boxes.id, boxes.code, boxes.name, boxes.cost").
+		Order("orders.store, boxes.code")
+	if store != "" {
+		query = query.Where("orders.store = ?", store)
+	}
+
+	var rows []PackingBillingItem
+	if err := query.Scan(&rows).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build packing billing report", err.Error())
+		return
+	}
+
+	response := PackingBillingReportResponse{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Stores:    []StorePackingBilling{},
+	}
+	for _, row := range rows {
+		if len(response.Stores) == 0 || response.Stores[len(response.Stores)-1].Store != row.Store {
+			response.Stores = append(response.Stores, StorePackingBilling{Store: row.Store})
+		}
+		current := &response.Stores[len(response.Stores)-1]
+		current.Boxes = append(current.Boxes, row)
+		current.add(row)
+		response.Total.add(row)
+	}
+
+	if export {
+		sendPackingBillingXLSX(c, response, store)
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Packing billing report retrieved successfully", response)
+}
+
+// GetComplainFeeReport godoc
+// @Summary Get complain fee settlement report
+// @Description Get the complain fees charged to each operator for a month with the complains they come from, for deduction from salaries. Complains are dated by creation, rejected complains are left out. Operators are approved once finance settles their total, an approval becomes pending again when the fees change (finance and superadmin only)
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param month query string false "Month (YYYY-MM format), defaults to the current month"
+// @Param approved_only query bool false "Only operators whose current total is approved"
+// @Param export query string false "Set to csv to download one row per operator for payroll import"
+// @Success 200 {object} utilities.Response{data=models.ComplainFeeSettlementReport}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/reports/complain-fees [get]
+func (rc *ReportController) GetComplainFeeReport(c *gin.Context) {
+	month := utilities.Now()
+	if value := c.Query("month"); value != "" {
+		parsed, err := models.ParseSettlementMonth(value)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid month", "month must be in YYYY-MM format")
+			return
+		}
+		month = parsed
+	}
+
+	export := c.Query("export")
+	if export != "" && export != "csv" {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid export format", "export must be csv")
+		return
+	}
+
+	report, err := models.BuildComplainFeeSettlement(rc.DB.WithContext(c), month)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build complain fee report", err.Error())
+		return
+	}
+
+	if c.Query("approved_only") == "true" {
+		approved := []models.ComplainFeeOperator{}
+		for _, operator := range report.Operators {
+			if operator.Approved {
+				approved = append(approved, operator)
+			}
+		}
+		report.Operators = approved
+	}
+
+	if export == "csv" {
+		sendComplainFeesCSV(c, report)
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, utilities.Messagef(c, "Complain fee report for %s retrieved successfully", report.Month), report)
+}
+
+// ApproveComplainFees godoc
+// @Summary Approve complain fees
+// @Description Approve the current complain fee totals of operators for a month, all operators of the month when operator_ids is empty. Set approved to false to withdraw approvals (finance and superadmin only)
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ApproveComplainFeesRequest true "Month and operators"
+// @Success 200 {object} utilities.Response{data=models.ComplainFeeSettlementReport}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/reports/complain-fees/approve [post]
+func (rc *ReportController) ApproveComplainFees(c *gin.Context) {
+	var req ApproveComplainFeesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	month, err := models.ParseSettlementMonth(req.Month)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid month", "month must be in YYYY-MM format")
+		return
+	}
+
+	approve := req.Approved == nil || *req.Approved
+	var message string
+	err = utilities.WithTransaction(rc.DB.WithContext(c), func(tx *gorm.DB) error {
+		if !approve {
+			revoked, err := models.RevokeComplainFeeApprovals(tx, month.Format("2006-01"), req.OperatorIDs)
+			message = utilities.Messagef(c, "%d complain fee approvals withdrawn", revoked)
+			return err
+		}
+
+		report, err := models.BuildComplainFeeSettlement(tx, month)
+		if err != nil {
+			return err
+		}
+		approved, err := models.ApproveComplainFees(tx, report, req.OperatorIDs, c.GetUint("user_id"), time.Now())
+		message = utilities.Messagef(c, "Complain fees of %d operators approved", approved)
+		return err
+	})
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to approve complain fees", err.Error())
+		return
+	}
+
+	report, err := models.BuildComplainFeeSettlement(rc.DB.WithContext(c), month)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build complain fee report", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, message, report)
+}
+
+// GetPickerProductivityReport godoc
+// @Summary Get picker productivity report
+// @Description Get each picker's rolling average pick time, picking error rate and the resulting weight used to hand out urgent orders when grabbing the next order (coordinator and superadmin only)
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utilities.Response{data=PickerProductivityReportResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/reports/picker-productivity [get]
+func (rc *ReportController) GetPickerProductivityReport(c *gin.Context) {
+	scores, err := models.PickerScores(rc.DB.WithContext(c), time.Now())
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build picker productivity report", err.Error())
+		return
+	}
+
+	policy := models.CurrentSettings().PickerProductivity
+	response := PickerProductivityReportResponse{
+		Days:                policy.Days,
+		MinPicks:            policy.MinPicks,
+		UrgentWindowMinutes: int(policy.UrgentWindow / time.Minute),
+		Pickers:             scores,
+	}
+	if response.Pickers == nil {
+		response.Pickers = []models.PickerScore{}
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Picker productivity report retrieved successfully", response)
+}
+
+// GetOperatorFaultScoresReport godoc
+// @Summary Get operator fault scores
+// @Description Get, per operator, the complains raised in a date range that were linked to them (through picking, pending, QC, outbound or returns), how many marked them at fault and the parcels they handled, with a fault score of weighted complains per 1000 parcels handled, overall and per week, worst first. Complains with the operator at fault weigh 1, the others 0.5. Drafts and rejected complains do not count. For coaching (coordinator and superadmin only)
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string true "Start date (YYYY-MM-DD format)"
+// @Param end_date query string true "End date (YYYY-MM-DD format)"
+// @Success 200 {object} utilities.Response{data=OperatorFaultScoresReportResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/reports/operator-fault-scores [get]
+func (rc *ReportController) GetOperatorFaultScoresReport(c *gin.Context) {
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+	if startDate == "" || endDate == "" {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDateRangeInvalid, "Date range is required", "start_date and end_date are required")
+		return
+	}
+
+	parsedStartDate, err := utilities.ParseDate(startDate)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
+		return
+	}
+	parsedEndDate, err := utilities.ParseDate(endDate)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
+		return
+	}
+	if parsedEndDate.Before(parsedStartDate) {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDateRangeInvalid, "Invalid date range", "end_date must not be before start_date")
+		return
+	}
+
+	start, _ := utilities.DayRange(parsedStartDate)
+	_, end := utilities.DayRange(parsedEndDate)
+	operators, err := models.OperatorFaultScores(rc.DB.WithContext(c), start, end)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build operator fault scores", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Operator fault scores retrieved successfully", OperatorFaultScoresReportResponse{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Operators: operators,
+	})
+}
+
+// GetOrderEventsReport godoc
+// @Summary Get order events report
+// @Description Get the orders changed, duplicated, cancelled or merged by hand in a date range: counts per event, counts per operator (who edited, duplicated or merged the order, or who cancelled it) and the orders themselves, latest first. Each order counts once, under its current event status, dated by that event. (coordinator and superadmin only)
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string true "Start date (YYYY-MM-DD format)"
+// @Param end_date query string true "End date (YYYY-MM-DD format)"
+// @Param event query string false "Limit to one event: changed, duplicated, cancelled or merged"
+// @Param operator_id query int false "Limit to one operator"
+// @Param page query int false "Page of the order rows" default(1)
+// @Param limit query int false "Order rows per page" default(50)
+// @Success 200 {object} utilities.Response{data=OrderEventsReportResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/reports/order-events [get]
+func (rc *ReportController) GetOrderEventsReport(c *gin.Context) {
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+	if startDate == "" || endDate == "" {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDateRangeInvalid, "Date range is required", "start_date and end_date are required")
+		return
+	}
+
+	parsedStartDate, err := utilities.ParseDate(startDate)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
+		return
+	}
+	parsedEndDate, err := utilities.ParseDate(endDate)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
+		return
+	}
+	if parsedEndDate.Before(parsedStartDate) {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDateRangeInvalid, "Invalid date range", "end_date must not be before start_date")
+		return
+	}
+
+	filter := models.OrderEventFilter{Event: c.Query("event")}
+	filter.Start, _ = utilities.DayRange(parsedStartDate)
+	_, filter.End = utilities.DayRange(parsedEndDate)
+	if filter.Event != "" && !slices.Contains(models.OrderEvents, filter.Event) {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid event", fmt.Sprintf("event must be one of %s", strings.Join(models.OrderEvents, ", ")))
+		return
+	}
+	if operator := c.Query("operator_id"); operator != "" {
+		operatorID, err := strconv.ParseUint(operator, 10, 32)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid operator_id", "operator_id must be a user ID")
+			return
+		}
+		filter.OperatorID = uint(operatorID)
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset := (page - 1) * limit
+
+	totals, operators, err := models.CountOrderEvents(rc.DB.WithContext(c), filter)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build order events report", err.Error())
+		return
+	}
+	rows, total, err := models.FindOrderEvents(rc.DB.WithContext(c), filter, limit, offset)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build order events report", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Order events report retrieved successfully", OrderEventsReportResponse{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Events:    totals,
+		Operators: operators,
+		Orders:    rows,
+		Pagination: utilities.PaginationResponse{
+			Page:  page,
+			Limit: limit,
+			Total: int(total),
+		},
+	})
+}
+
+// GetQcDashboard godoc
+// @Summary Get QC station dashboard
+// @Description Get today's QC Ribbon and QC Online counts per operator, the average time between picking complete and QC, and the number of picked orders still waiting for QC. Built from aggregate queries so the QC station screens can poll it every few seconds.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utilities.Response{data=models.QcDashboard}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/reports/qc-dashboard [get]
+func (rc *ReportController) GetQcDashboard(c *gin.Context) {
+	dashboard, err := models.BuildQcDashboard(rc.DB.WithContext(c), time.Now())
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build QC dashboard", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "QC dashboard retrieved successfully", dashboard)
+}
+
+// GetReturnsAgingReport godoc
+// @Summary Get returns aging report
+// @Description Get open returns bucketed by days since they were received (0-7, 8-14, 15-30, 30+) and by disposition (restock, scrap, pending), overall and per channel and store, so the oldest returns get processed first. A return is open while it has neither a return nor a scrap number, or while the putaway of its restocked items is not done; set include_closed to count every return.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param include_closed query bool false "Also count scrapped returns and restocked returns put away entirely"
+// @Success 200 {object} utilities.Response{data=models.ReturnAging}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/reports/returns-aging [get]
+func (rc *ReportController) GetReturnsAgingReport(c *gin.Context) {
+	includeClosed := false
+	if value := c.Query("include_closed"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid include_closed filter", "include_closed must be true or false")
+			return
+		}
+		includeClosed = parsed
+	}
+
+	report, err := models.BuildReturnAging(rc.DB.WithContext(c), time.Now(), includeClosed)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build returns aging report", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Returns aging report retrieved successfully", report)
+}
+
+// GetDestinationsReport godoc
+// @Summary Get shipping destinations report
+// @Description Get the orders created in a date range by shipping destination: counts and shares per province and per city of the orders whose address was validated, and how many orders the geocoder could not place or never checked. Cancelled and merged orders are left out.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string true "Start date (YYYY-MM-DD format)"
+// @Param end_date query string true "End date (YYYY-MM-DD format)"
+// @Param channel query string false "Filter by channel"
+// @Param store query string false "Filter by store"
+// @Success 200 {object} utilities.Response{data=models.DestinationStats}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/reports/destinations [get]
+func (rc *ReportController) GetDestinationsReport(c *gin.Context) {
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+	if startDate == "" || endDate == "" {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDateRangeInvalid, "Date range is required", "start_date and end_date are required")
+		return
+	}
+
+	parsedStartDate, err := utilities.ParseDate(startDate)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
+		return
+	}
+	parsedEndDate, err := utilities.ParseDate(endDate)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
+		return
+	}
+	if parsedEndDate.Before(parsedStartDate) {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDateRangeInvalid, "Invalid date range", "end_date must not be before start_date")
+		return
+	}
+
+	filter := models.DestinationFilter{Channel: c.Query("channel"), Store: c.Query("store")}
+	filter.Start, _ = utilities.DayRange(parsedStartDate)
+	_, filter.End = utilities.DayRange(parsedEndDate)
+
+	stats, err := models.BuildDestinationStats(rc.DB.WithContext(c), filter)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build destinations report", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Destinations report retrieved successfully", stats)
+}
+
+// GetCycleTimesReport godoc
+// @Summary Get order cycle times report
+// @Description Get how long the orders created in a date range took through each stage, from creation to assignment, pick complete, QC (last parcel) and outbound, and in total: the average, median (p50) and 90th percentile in seconds. Orders count in the stages they completed. The bottleneck is the stage with the longest median. Cancelled and merged orders are left out.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string true "Start date (YYYY-MM-DD format)"
+// @Param end_date query string true "End date (YYYY-MM-DD format)"
+// @Param channel query string false "Filter by channel"
+// @Param picker_id query int false "Filter by the picker who picked the orders"
+// @Success 200 {object} utilities.Response{data=CycleTimesReportResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/reports/cycle-times [get]
+func (rc *ReportController) GetCycleTimesReport(c *gin.Context) {
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+	if startDate == "" || endDate == "" {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDateRangeInvalid, "Date range is required", "start_date and end_date are required")
+		return
+	}
+
+	parsedStartDate, err := utilities.ParseDate(startDate)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
+		return
+	}
+	parsedEndDate, err := utilities.ParseDate(endDate)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
+		return
+	}
+	if parsedEndDate.Before(parsedStartDate) {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDateRangeInvalid, "Invalid date range", "end_date must not be before start_date")
+		return
+	}
+
+	filter := models.CycleTimeFilter{Channel: c.Query("channel")}
+	filter.Start, _ = utilities.DayRange(parsedStartDate)
+	_, filter.End = utilities.DayRange(parsedEndDate)
+	if picker := c.Query("picker_id"); picker != "" {
+		pickerID, err := strconv.ParseUint(picker, 10, 32)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid picker_id", "picker_id must be a user ID")
+			return
+		}
+		filter.PickerID = uint(pickerID)
+	}
+
+	cycleTimes, err := models.BuildCycleTimes(rc.DB.WithContext(c), filter)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to build cycle times report", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Cycle times report retrieved successfully", CycleTimesReportResponse{
+		StartDate:  startDate,
+		EndDate:    endDate,
+		CycleTimes: cycleTimes,
+	})
+}
+
+// ExportFlows godoc
+// @Summary Export flows for a date range
+// @Description Download a CSV spreadsheet with one row per tracking that was picked, QC'd or sent out in the date range, with the pick, QC and outbound timestamps and operators. Multi-parcel orders show the last parcel QC'd and how many parcels each QC station recorded.
+// @Tags reports
+// @Produce text/csv
+// @Security BearerAuth
+// @Param start_date query string true "Start date (YYYY-MM-DD format)"
+// @Param end_date query string true "End date (YYYY-MM-DD format)"
+// @Success 200 {file} file
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/flows/export [get]
+func (rc *ReportController) ExportFlows(c *gin.Context) {
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+	if startDate == "" || endDate == "" {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDateRangeInvalid, "Date range is required", "start_date and end_date are required")
+		return
+	}
+
+	start, err := utilities.ParseDate(startDate)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
+		return
+	}
+	end, err := utilities.ParseDate(endDate)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
+		return
+	}
+	if end.Before(start) {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDateRangeInvalid, "Invalid date range", "end_date must not be before start_date")
+		return
+	}
+	if end.Sub(start) > maxFlowExportDays*24*time.Hour {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDateRangeInvalid, "Date range too large", fmt.Sprintf("date range must not exceed %d days", maxFlowExportDays))
+		return
+	}
+	// End date is inclusive
+	end = end.AddDate(0, 0, 1)
+
+	// Every tracking with a pick, QC or outbound scan inside the range
+	trackings := rc.DB.WithContext(c).Raw(`
+		SELECT tracking FROM orders WHERE deleted_at IS NULL AND picked_at >= @start AND picked_at < @end
+		UNION SELECT tracking FROM qc_ribbons WHERE deleted_at IS NULL AND created_at >= @start AND created_at < @end
+		UNION SELECT tracking FROM qc_onlines WHERE deleted_at IS NULL AND created_at >= @start AND created_at < @end
+		UNION SELECT tracking FROM outbounds WHERE deleted_at IS NULL AND created_at >= @start AND created_at < @end
+	`, map[string]interface{}{"start": start, "end": end})
+
+	rows, err := rc.DB.WithContext(c).Table("(?) AS flows", trackings).
+		Select(`
+			flows.tracking,
+			orders.order_ginee_id,
+			orders.channel,
+			orders.store,
+			orders.processing_status,
+			orders.parcel_count,
+			orders.picked_at,
+			pickers.full_name AS picked_by,
+			qc_ribbons.created_at AS qc_ribbon_at,
+			qc_ribbon_operators.full_name AS qc_ribbon_by,
+			qc_ribbons.parcels AS qc_ribbon_parcels,
+			qc_onlines.created_at AS qc_online_at,
+			qc_online_operators.full_name AS qc_online_by,
+			qc_onlines.parcels AS qc_online_parcels,
+			outbounds.created_at AS outbound_at,
+			outbound_operators.full_name AS outbound_by,
+			outbounds.expedition
+		`).
+		Joins("LEFT JOIN orders ON orders.tracking = flows.tracking AND orders.deleted_at IS NULL").
+		Joins("LEFT JOIN users pickers ON pickers.id = orders.picked_by").
+		Joins("LEFT JOIN (?) AS qc_ribbons ON qc_ribbons.tracking = flows.tracking", qcParcelsQuery(rc.DB.WithContext(c), "qc_ribbons")).
+		Joins("LEFT JOIN users qc_ribbon_operators ON qc_ribbon_operators.id = qc_ribbons.qc_by").
+		Joins("LEFT JOIN (?) AS qc_onlines ON qc_onlines.tracking = flows.tracking", qcParcelsQuery(rc.DB.WithContext(c), "qc_onlines")).
+		Joins("LEFT JOIN users qc_online_operators ON qc_online_operators.id = qc_onlines.qc_by").
+		Joins("LEFT JOIN outbounds ON outbounds.tracking = flows.tracking AND outbounds.deleted_at IS NULL").
+		Joins("LEFT JOIN users outbound_operators ON outbound_operators.id = outbounds.outbound_by").
+		Order("flows.tracking").
+		Rows()
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to export flows", err.Error())
+		return
+	}
+	defer rows.Close()
+
+	filename := fmt.Sprintf("flows_%s_%s.csv", startDate, endDate)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Status(http.StatusOK)
+
+	// Byte order mark so spreadsheet apps open the file as UTF-8
+	c.Writer.WriteString("\ufeff")
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{
+		"Tracking", "Order Ginee ID", "Channel", "Store", "Processing Status", "Parcels",
+		"Picked At", "Picked By",
+		"QC Ribbon At", "QC Ribbon By", "QC Ribbon Parcels",
+		"QC Online At", "QC Online By", "QC Online Parcels",
+		"Outbound At", "Outbound By", "Expedition",
+	})
+
+	for rows.Next() {
+		var row FlowExportRow
+		if err := rc.DB.WithContext(c).ScanRows(rows, &row); err != nil {
+			// Headers are already sent, the truncated file is all we can signal
+			log.Printf("⚠️ Flow export stopped: %v", err)
+			break
+		}
+
+		writer.Write([]string{
+			row.Tracking, row.OrderGineeID, row.Channel, row.Store, row.ProcessingStatus, formatExportCount(row.ParcelCount),
+			formatExportTime(row.PickedAt), row.PickedBy,
+			formatExportTime(row.QcRibbonAt), row.QcRibbonBy, formatExportCount(row.QcRibbonParcels),
+			formatExportTime(row.QcOnlineAt), row.QcOnlineBy, formatExportCount(row.QcOnlineParcels),
+			formatExportTime(row.OutboundAt), row.OutboundBy, row.Expedition,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("⚠️ Flow export stopped: %v", err)
+	}
+
+	writer.Flush()
+}
+
+// maxFlowExportDays caps the flow export so a single request can't scan the whole history
+const maxFlowExportDays = 93
+
+// qcParcelsQuery folds the QC records of table, qc_ribbons or qc_onlines, into one row per
+// tracking: the last parcel QC'd, its operator and the number of parcels QC'd
+func qcParcelsQuery(db *gorm.DB, table string) *gorm.DB {
+	return db.Raw(`SELECT DISTINCT ON (tracking) tracking, created_at, qc_by,
+		COUNT(*) OVER (PARTITION BY tracking) AS parcels
+		FROM ` + table + ` WHERE deleted_at IS NULL
+		ORDER BY tracking, created_at DESC`)
+}
+
+// formatExportCount formats a nullable count for spreadsheet cells
+func formatExportCount(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
+
+// formatExportTime formats a nullable timestamp for spreadsheet cells
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// wantsXLSXExport reads the export query option. It answers 400 and returns ok false for formats
+// other than xlsx.
+func wantsXLSXExport(c *gin.Context) (export bool, ok bool) {
+	switch c.Query("export") {
+	case "":
+		return false, true
+	case "xlsx":
+		return true, true
+	default:
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid export format", "export must be xlsx")
+		return false, false
+	}
+}
+
+// sendXLSX renders a workbook and sends it as a download named after the report and its filter
+func sendXLSX(c *gin.Context, workbook *utilities.XLSXWorkbook, name, filter string) {
+	data, err := workbook.Bytes()
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to export report", err.Error())
+		return
+	}
+
+	filename := name
+	if filter != "" {
+		filename += "_" + filter
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.xlsx", filename))
+	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", data)
+}
+
+func sendBoxReportsXLSX(c *gin.Context, reports []BoxCountReport, startDate, endDate string) {
+	workbook := utilities.NewXLSXWorkbook()
+
+	summary := workbook.AddSheet("Box Count", "Box Code", "Box Name", "Total", "QC Ribbon", "QC Online")
+	details := workbook.AddSheet("Details", "Box Code", "Box Name", "Source", "Tracking", "Parcel", "Order Ginee ID", "Quantity", "QC By", "Created At")
+	for _, report := range reports {
+		summary.AddRow(report.BoxCode, report.BoxName, report.TotalCount, report.RibbonCount, report.OnlineCount)
+		for _, detail := range report.Details {
+			details.AddRow(report.BoxCode, report.BoxName, detail.Source, detail.Tracking, detail.ParcelNumber, detail.OrderID, detail.Quantity, detail.FullName, detail.CreatedAt)
+		}
+	}
+
+	filter := strings.Trim(startDate+"_"+endDate, "_")
+	sendXLSX(c, workbook, "box_count", filter)
+}
+
+func sendPackingBillingXLSX(c *gin.Context, report PackingBillingReportResponse, store string) {
+	workbook := utilities.NewXLSXWorkbook()
+
+	summary := workbook.AddSheet("Stores", "Store", "Boxes", "Amount", "Unpriced Boxes")
+	details := workbook.AddSheet("Boxes", "Store", "Box Code", "Box Name", "QC Ribbon", "QC Online", "Total", "Unit Cost", "Amount")
+	for _, billing := range report.Stores {
+		summary.AddRow(billing.Store, billing.TotalCount, billing.Amount, billing.UnpricedCount)
+		for _, box := range billing.Boxes {
+			details.AddRow(billing.Store, box.BoxCode, box.BoxName, box.RibbonCount, box.OnlineCount, box.TotalCount, box.UnitCost, box.Amount)
+		}
+	}
+	summary.AddRow("Total", report.Total.TotalCount, report.Total.Amount, report.Total.UnpricedCount)
+
+	filter := report.StartDate + "_" + report.EndDate
+	if store != "" {
+		filter = store + "_" + filter
+	}
+	sendXLSX(c, workbook, "packing_billing", filter)
+}
+
+func sendOutboundReportsXLSX(c *gin.Context, outbounds []models.Outbound, date string) {
+	workbook := utilities.NewXLSXWorkbook()
+
+	sheet := workbook.AddSheet("Handout Outbounds", "Tracking", "Expedition", "Outbound By", "Complained", "Created At")
+	for _, outbound := range outbounds {
+		operator := ""
+		if outbound.OutboundOperator != nil {
+			operator = outbound.OutboundOperator.FullName
+		}
+		sheet.AddRow(outbound.Tracking, outbound.Expedition, operator, yesNo(outbound.Complained), outbound.CreatedAt)
+	}
+
+	sendXLSX(c, workbook, "handout_outbounds", date)
+}
+
+func sendReturnReportsXLSX(c *gin.Context, returns []models.Return, date string) {
+	workbook := utilities.NewXLSXWorkbook()
+
+	sheet := workbook.AddSheet("Handout Returns", "New Tracking", "Old Tracking", "Order Ginee ID", "Channel", "Store",
+		"Return Type", "Return Reason", "Return Number", "Scrap Number", "Created By", "Updated At",
+		"Replacement Order", "Replacement Tracking")
+	items := workbook.AddSheet("Return Items", "New Tracking", "SKU", "Product", "Variant", "Quantity")
+	for _, ret := range returns {
+		channel, store, creator := "", "", ""
+		if ret.Channel != nil {
+			channel = ret.Channel.Name
+		}
+		if ret.Store != nil {
+			store = ret.Store.Name
+		}
+		if ret.CreateOperator != nil {
+			creator = ret.CreateOperator.FullName
+		}
+		replacementOrder, replacementTracking := replacementColumns(&ret)
+		sheet.AddRow(ret.NewTracking, ret.OldTracking, ret.OrderGineeID, channel, store,
+			ret.ReturnType, ret.ReturnReason, ret.ReturnNumber, ret.ScrapNumber, creator, ret.UpdatedAt,
+			replacementOrder, replacementTracking)
+
+		for _, detail := range ret.ReturnDetails {
+			items.AddRow(ret.NewTracking, detail.Product.Sku, detail.Product.Name, detail.Product.Variant, detail.Quantity)
+		}
+	}
+
+	sendXLSX(c, workbook, "handout_returns", date)
+}
+
+func sendComplainReportsXLSX(c *gin.Context, complains []models.Complain, date string) {
+	workbook := utilities.NewXLSXWorkbook()
+
+	sheet := workbook.AddSheet("Handout Complains", "Code", "Tracking", "Order Ginee ID", "Channel", "Store",
+		"Description", "Solution", "Total Fee", "Checked", "Updated At", "Return Tracking", "Replacement Order", "Replacement Tracking")
+	charges := workbook.AddSheet("Fee Charges", "Code", "Tracking", "Operator", "Stages", "At Fault", "Fee Charge")
+	for _, complain := range complains {
+		channel, store := "", ""
+		if complain.Channel != nil {
+			channel = complain.Channel.Name
+		}
+		if complain.Store != nil {
+			store = complain.Store.Name
+		}
+		returnTracking, replacementOrder, replacementTracking := "", "", ""
+		if complain.Return != nil {
+			returnTracking = complain.Return.NewTracking
+			replacementOrder, replacementTracking = replacementColumns(complain.Return)
+		}
+		sheet.AddRow(complain.Code, complain.Tracking, complain.OrderGineeID, channel, store,
+			complain.Description, complain.Solution, complain.TotalFee, yesNo(complain.Checked), complain.UpdatedAt,
+			returnTracking, replacementOrder, replacementTracking)
+
+		for _, userDetail := range complain.UserDetails {
+			operator := ""
+			if userDetail.Operator != nil {
+				operator = userDetail.Operator.FullName
+			}
+			charges.AddRow(complain.Code, complain.Tracking, operator, strings.Join(userDetail.StageList(), ", "), yesNo(userDetail.AtFault), userDetail.FeeCharge)
+		}
+	}
+
+	sendXLSX(c, workbook, "handout_complains", date)
+}
+
+// replacementColumns returns the Ginee order ID and tracking of the order replacing a return, blank
+// when the return was not replaced
+func replacementColumns(ret *models.Return) (string, string) {
+	replacement := ret.Replacement()
+	if replacement == nil {
+		return "", ""
+	}
+	return replacement.ReplacementOrderGineeID, replacement.ReplacementTracking
+}
+
+// sendComplainFeesCSV sends one row per operator for payroll import, with the complain codes the
+// fee comes from
+func sendComplainFeesCSV(c *gin.Context, report models.ComplainFeeSettlementReport) {
+	filename := fmt.Sprintf("complain_fees_%s.csv", report.Month)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Status(http.StatusOK)
+
+	// Byte order mark so spreadsheet apps open the file as UTF-8
+	c.Writer.WriteString("\ufeff")
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{
+		"Month", "Operator ID", "Username", "Full Name", "Complaints", "Fee Charge",
+		"Approved", "Approved By", "Approved At", "Complain Codes",
+	})
+
+	for _, operator := range report.Operators {
+		codes := make([]string, len(operator.Complains))
+		for i, complain := range operator.Complains {
+			codes[i] = complain.Code
+		}
+
+		writer.Write([]string{
+			report.Month, strconv.FormatUint(uint64(operator.OperatorID), 10), operator.Username, operator.FullName,
+			strconv.Itoa(operator.Complaints), strconv.FormatUint(operator.TotalFee, 10),
+			yesNo(operator.Approved), operator.ApprovedBy, operator.ApprovedAt, strings.Join(codes, "; "),
+		})
+	}
+
+	writer.Flush()
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
+
+// reportDayStart returns the start of an already validated YYYY-MM-DD date in the warehouse timezone,
+// for the date filters built into report SQL
+func reportDayStart(date string) string {
+	start, _ := utilities.ParseDate(date)
+	return start.Format(time.RFC3339)
+}
+
+// Request/Response structs
+// BoxUsageDetail represents individual box usage record
+type BoxUsageDetail struct {
+	Tracking     string    `json:"tracking"`
+	OrderID      string    `json:"order_ginee_id"`
+	BoxName      string    `json:"box_name"`
+	Quantity     int       `json:"quantity"`
+	ParcelNumber int       `json:"parcel_number"` // Parcel of a multi-parcel order
+	QcBy         uint      `json:"qc_by"`         // Added QC By user ID
+	Username     string    `json:"username"`      // Added username
+	FullName     string    `json:"full_name"`     // Added full name
+	CreatedAt    time.Time `json:"created_at"`
+	Source       string    `json:"source"` // "QC Ribbon" or "QC Online"
+}
+
+// BoxCountReport represents box count report
+type BoxCountReport struct {
+	BoxID       uint             `json:"box_id"`
+	BoxCode     string           `json:"box_code"`
+	BoxName     string           `json:"box_name"`
+	TotalCount  int              `json:"total_count"`
+	RibbonCount int              `json:"ribbon_count"`
+	OnlineCount int              `json:"online_count"`
+	Details     []BoxUsageDetail `json:"details" gorm:"-"` // Added gorm:"-" to ignore this field
+}
+
+// BoxCountReportsListResponse represents the response for box count reports
+type BoxCountReportsListResponse struct {
+	Reports    []BoxCountReport             `json:"reports"`
+	Pagination utilities.PaginationResponse `json:"pagination"`
+}
+
+// OutboundReportsListResponse represents the response for outbound reports
+type OutboundReportsListResponse struct {
+	Outbounds []models.OutboundResponse `json:"outbounds"`
+	Total     int                       `json:"total"`
+}
+
+// ReturnReportsListResponse represents the response for return reports
+type ReturnReportsListResponse struct {
+	Returns []models.ReturnResponse `json:"returns"`
+	Total   int                     `json:"total"`
+}
+
+// ComplainReportsListResponse represents the response for complain reports
+type ComplainReportsListResponse struct {
+	Complains []models.ComplainResponse `json:"complains"`
+	Total     int                       `json:"total"`
+}
+
+// UserFeeReportSummary represents user fee report summary data (without details)
+type UserFeeReportSummary struct {
+	UserID          uint   `json:"user_id"`
+	Username        string `json:"username"`
+	FullName        string `json:"full_name"`
+	Email           string `json:"email"`
+	TotalComplaints int    `json:"total_complaints"`
+	TotalFeeCharge  uint   `json:"total_fee_charge"`
+}
+
+// ComplainDetailInReport represents individual complain details in user fee report
+type ComplainDetailInReport struct {
+	ComplainID        uint      `json:"complain_id"`
+	ComplainCode      string    `json:"complain_code"`
+	Tracking          string    `json:"tracking"`
+	OrderGineeID      string    `json:"order_ginee_id"`
+	FeeCharge         uint      `json:"fee_charge"`
+	ComplainUpdatedAt time.Time `json:"complain_updated_at"`
+}
+
+// UserFeeReportWithDetails represents user fee report data with detailed records
+type UserFeeReportWithDetails struct {
+	UserID          uint                     `json:"user_id"`
+	Username        string                   `json:"username"`
+	FullName        string                   `json:"full_name"`
+	Email           string                   `json:"email"`
+	TotalComplaints int                      `json:"total_complaints"`
+	TotalFeeCharge  uint                     `json:"total_fee_charge"`
+	ComplainDetails []ComplainDetailInReport `json:"complain_details"`
+}
+
+// UserFeeReportsWithDetailsListResponse represents the response for user fee reports with details
+type UserFeeReportsWithDetailsListResponse struct {
+	Reports    []UserFeeReportWithDetails   `json:"reports"`
+	Pagination utilities.PaginationResponse `json:"pagination"`
+}
+
+// ComplainEscalationTierReport represents escalation volume and time spent in one tier
+type ComplainEscalationTierReport struct {
+	Level           string  `json:"level"`
+	Entered         int     `json:"entered"`
+	AutoEscalated   int     `json:"auto_escalated"`
+	ManualEscalated int     `json:"manual_escalated"`
+	Resolved        int     `json:"resolved"`
+	Open            int     `json:"open"`
+	TotalHours      float64 `json:"total_hours"`
+	AverageHours    float64 `json:"average_hours"`
+}
+
+// ComplainEscalationReportResponse represents the response for complain escalation reports
+type ComplainEscalationReportResponse struct {
+	Tiers          []ComplainEscalationTierReport `json:"tiers"`
+	TotalComplains int                            `json:"total_complains"`
+}
+
+// BoxForecastReportResponse represents the projected stock of the tracked boxes
+type BoxForecastReportResponse struct {
+	UsageDays int                  `json:"usage_days"` // Days the daily usage is averaged over
+	AlertDays int                  `json:"alert_days"` // Boxes running out within these days are low
+	Low       int                  `json:"low"`
+	Boxes     []models.BoxForecast `json:"boxes"`
+}
+
+// BoxSuggestionReportResponse represents box suggestion acceptance over a period
+type BoxSuggestionReportResponse struct {
+	TotalQc        int                 `json:"total_qc"`
+	Suggested      int                 `json:"suggested"` // QCs that had a suggestion
+	Accepted       int                 `json:"accepted"`  // QCs that used the top suggestion
+	AcceptanceRate float64             `json:"acceptance_rate"`
+	Boxes          []BoxSuggestionStat `json:"boxes" gorm:"-"`
+}
+
+// BoxSuggestionStat represents acceptance of one suggested box
+type BoxSuggestionStat struct {
+	BoxID          uint    `json:"box_id"`
+	Code           string  `json:"code"`
+	Name           string  `json:"name"`
+	Suggested      int     `json:"suggested"`
+	Accepted       int     `json:"accepted"`
+	AcceptanceRate float64 `json:"acceptance_rate" gorm:"-"`
+}
+
+// FlowExportRow is one tracking of the flow export
+type FlowExportRow struct {
+	Tracking         string
+	OrderGineeID     string
+	Channel          string
+	Store            string
+	ProcessingStatus string
+	ParcelCount      *int
+	PickedAt         *time.Time
+	PickedBy         string
+	QcRibbonAt       *time.Time
+	QcRibbonBy       string
+	QcRibbonParcels  *int
+	QcOnlineAt       *time.Time
+	QcOnlineBy       string
+	QcOnlineParcels  *int
+	OutboundAt       *time.Time
+	OutboundBy       string
+	Expedition       string
+}
+
+// ReturnValuationRow is the quantity and value of returned items of one disposition in one period
+type ReturnValuationRow struct {
+	Period           string
+	Disposition      string
+	Quantity         int
+	CostValue        int64
+	ScrapValue       int64
+	UnvaluedQuantity int
+}
+
+// ReturnValuationTotals represents returned item quantities and values in rupiah
+type ReturnValuationTotals struct {
+	RestockedQuantity int   `json:"restocked_quantity"`
+	RestockedValue    int64 `json:"restocked_value"` // Inventory recovered, at cost
+	ScrappedQuantity  int   `json:"scrapped_quantity"`
+	ScrappedCost      int64 `json:"scrapped_cost"`    // Cost of scrapped items
+	ScrapRecovery     int64 `json:"scrap_recovery"`   // Salvage value of scrapped items
+	WriteOff          int64 `json:"write_off"`        // Scrapped cost less salvage value
+	PendingQuantity   int   `json:"pending_quantity"` // Items of returns not yet restocked or scrapped
+	PendingValue      int64 `json:"pending_value"`
+	UnvaluedQuantity  int   `json:"unvalued_quantity"` // Items whose product has no cost set
+}
+
+// ReturnValuationPeriod represents return valuation for one day or month
+type ReturnValuationPeriod struct {
+	Period string `json:"period" example:"2025-09"`
+	ReturnValuationTotals
+}
+
+// ReturnValuationReportResponse represents the response for the return valuation report
+type ReturnValuationReportResponse struct {
+	StartDate string                  `json:"start_date"`
+	EndDate   string                  `json:"end_date"`
+	Period    string                  `json:"period"`
+	Periods   []ReturnValuationPeriod `json:"periods"`
+	Total     ReturnValuationTotals   `json:"total"`
+}
+
+// PackingBillingItem is the usage of one box type by one store, priced in rupiah
+type PackingBillingItem struct {
+	Store       string `json:"-"`
+	BoxID       uint   `json:"box_id"`
+	BoxCode     string `json:"box_code"`
+	BoxName     string `json:"box_name"`
+	RibbonCount int    `json:"ribbon_count"`
+	OnlineCount int    `json:"online_count"`
+	TotalCount  int    `json:"total_count"`
+	UnitCost    int    `json:"unit_cost"` // Current cost of the box
+	Amount      int64  `json:"amount"`
+}
+
+// PackingBillingTotals represents billed box counts and amounts in rupiah
+type PackingBillingTotals struct {
+	TotalCount    int   `json:"total_count"`
+	Amount        int64 `json:"amount"`
+	UnpricedCount int   `json:"unpriced_count"` // Boxes whose type has no cost set
+}
+
+// StorePackingBilling represents the packing material bill of one store
+type StorePackingBilling struct {
+	Store string               `json:"store" example:"AXON"`
+	Boxes []PackingBillingItem `json:"boxes"`
+	PackingBillingTotals
+}
+
+// PackingBillingReportResponse represents the response for the packing billing report
+type PackingBillingReportResponse struct {
+	StartDate string                `json:"start_date"`
+	EndDate   string                `json:"end_date"`
+	Stores    []StorePackingBilling `json:"stores"`
+	Total     PackingBillingTotals  `json:"total"`
+}
+
+// ApproveComplainFeesRequest represents the request to approve the complain fees of a month
+type ApproveComplainFeesRequest struct {
+	Month       string `json:"month" binding:"required,month" example:"2025-01"`
+	OperatorIDs []uint `json:"operator_ids"`            // Operators to approve, every operator of the month when empty
+	Approved    *bool  `json:"approved" example:"true"` // false withdraws the approvals, defaults to true
+}
+
+// PickerProductivityReportResponse represents the response for the picker productivity report
+type PickerProductivityReportResponse struct {
+	Days                int                  `json:"days" example:"14"`
+	MinPicks            int                  `json:"min_picks" example:"20"`
+	UrgentWindowMinutes int                  `json:"urgent_window_minutes" example:"120"`
+	Pickers             []models.PickerScore `json:"pickers"`
+}
+
+type OperatorFaultScoresReportResponse struct {
+	StartDate string                      `json:"start_date" example:"2025-10-01"`
+	EndDate   string                      `json:"end_date" example:"2025-10-31"`
+	Operators []models.OperatorFaultScore `json:"operators"`
+}
+
+type OrderEventsReportResponse struct {
+	StartDate  string                       `json:"start_date" example:"2025-10-01"`
+	EndDate    string                       `json:"end_date" example:"2025-10-31"`
+	Events     []models.OrderEventCount     `json:"events"`
+	Operators  []models.OrderEventOperator  `json:"operators"`
+	Orders     []models.OrderEventRow       `json:"orders"`
+	Pagination utilities.PaginationResponse `json:"pagination"` // Of the order rows
+}
+
+type ChannelIntakeReportResponse struct {
+	Date         string                              `json:"date" example:"2025-01-31"`
+	BaselineDays int                                 `json:"baseline_days" example:"14"`
+	Channels     []models.ChannelIntakeStat          `json:"channels"`
+	Alerts       []models.ChannelIntakeAlertResponse `json:"alerts"`
+}
+
+type CycleTimesReportResponse struct {
+	StartDate string `json:"start_date" example:"2025-10-01"`
+	EndDate   string `json:"end_date" example:"2025-10-31"`
+	models.CycleTimes
+}
+
+func (t *PackingBillingTotals) add(item PackingBillingItem) {
+	t.TotalCount += item.TotalCount
+	t.Amount += item.Amount
+	if item.UnitCost == 0 {
+		t.UnpricedCount += item.TotalCount
+	}
+}
+
+func (t *ReturnValuationTotals) add(row ReturnValuationRow) {
+	switch row.Disposition {
+	case "restocked":
+		t.RestockedQuantity += row.Quantity
+		t.RestockedValue += row.CostValue
+	case "scrapped":
+		t.ScrappedQuantity += row.Quantity
+		t.ScrappedCost += row.CostValue
+		t.ScrapRecovery += row.ScrapValue
+		t.WriteOff += row.CostValue - row.ScrapValue
+	default:
+		t.PendingQuantity += row.Quantity
+		t.PendingValue += row.CostValue
+	}
+	t.UnvaluedQuantity += row.UnvaluedQuantity
 }
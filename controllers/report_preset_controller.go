@@ -0,0 +1,421 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ReportPresetController struct {
+	DB *gorm.DB
+}
+
+// NewReportPresetController creates a new report preset controller
+func NewReportPresetController(db *gorm.DB) *ReportPresetController {
+	return &ReportPresetController{DB: db}
+}
+
+// reportPresetExports are the report handlers exporting the targets of scheduled presets
+var reportPresetExports = map[string]func(*ReportController, *gin.Context){
+	"reports/boxes-count":       (*ReportController).GetBoxReports,
+	"reports/handout-outbounds": (*ReportController).GetOutboundReports,
+	"reports/handout-returns":   (*ReportController).GetReturnReports,
+	"reports/handout-complains": (*ReportController).GetComplainReports,
+	"reports/packing-billing":   (*ReportController).GetPackingBillingReport,
+}
+
+// GetReportPresets godoc
+// @Summary Get report presets
+// @Description Get the filter presets the current user saved for orders and reports, optionally of one target
+// @Tags report-presets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param target query string false "Only presets of this target (orders, reports/boxes-count, ...)"
+// @Success 200 {object} utilities.Response{data=ReportPresetsListResponse}
+// @Failure 401 {object} utilities.Response
+// @Router /api/report-presets [get]
+func (rpc *ReportPresetController) GetReportPresets(c *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	query := rpc.DB.WithContext(c).Model(&models.ReportPreset{}).Where("user_id = ?", c.GetUint("user_id"))
+	if target := c.Query("target"); target != "" {
+		query = query.Where("target = ?", target)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count report presets", err.Error())
+		return
+	}
+
+	var presets []models.ReportPreset
+	if err := query.Order("name").Limit(limit).Offset(offset).Find(&presets).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve report presets", err.Error())
+		return
+	}
+
+	presetResponses := make([]models.ReportPresetResponse, len(presets))
+	for i, preset := range presets {
+		presetResponses[i] = preset.ToReportPresetResponse()
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Report presets retrieved successfully", ReportPresetsListResponse{
+		ReportPresets: presetResponses,
+		Pagination: utilities.PaginationResponse{
+			Page:  page,
+			Limit: limit,
+			Total: int(total),
+		},
+	})
+}
+
+// GetReportPreset godoc
+// @Summary Get report preset
+// @Description Get a filter preset of the current user
+// @Tags report-presets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Report preset ID"
+// @Success 200 {object} utilities.Response{data=models.ReportPresetResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/report-presets/{id} [get]
+func (rpc *ReportPresetController) GetReportPreset(c *gin.Context) {
+	preset, ok := rpc.findPreset(c)
+	if !ok {
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Report preset retrieved successfully", preset.ToReportPresetResponse())
+}
+
+// CreateReportPreset godoc
+// @Summary Create report preset
+// @Description Save named filters (search, channel, store, dates or a relative date range) for orders or a report. Presets of exportable reports can be scheduled daily or weekly, the export is then emailed as a spreadsheet to the recipients (the user by default).
+// @Tags report-presets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ReportPresetRequest true "Report preset data"
+// @Success 201 {object} utilities.Response{data=models.ReportPresetResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/report-presets [post]
+func (rpc *ReportPresetController) CreateReportPreset(c *gin.Context) {
+	var req ReportPresetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	preset := models.ReportPreset{UserID: c.GetUint("user_id")}
+	if !rpc.applyRequest(c, &preset, req) {
+		return
+	}
+
+	if err := rpc.DB.WithContext(c).Create(&preset).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create report preset", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusCreated, "Report preset created successfully", preset.ToReportPresetResponse())
+}
+
+// UpdateReportPreset godoc
+// @Summary Update report preset
+// @Description Replace the name, filters and schedule of a preset of the current user
+// @Tags report-presets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Report preset ID"
+// @Param request body ReportPresetRequest true "Report preset data"
+// @Success 200 {object} utilities.Response{data=models.ReportPresetResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/report-presets/{id} [put]
+func (rpc *ReportPresetController) UpdateReportPreset(c *gin.Context) {
+	var req ReportPresetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	preset, ok := rpc.findPreset(c)
+	if !ok {
+		return
+	}
+	if !rpc.applyRequest(c, &preset, req) {
+		return
+	}
+
+	if err := rpc.DB.WithContext(c).Save(&preset).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update report preset", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Report preset updated successfully", preset.ToReportPresetResponse())
+}
+
+// DeleteReportPreset godoc
+// @Summary Delete report preset
+// @Description Delete a preset of the current user, which also stops its scheduled export
+// @Tags report-presets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Report preset ID"
+// @Success 200 {object} utilities.Response
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/report-presets/{id} [delete]
+func (rpc *ReportPresetController) DeleteReportPreset(c *gin.Context) {
+	preset, ok := rpc.findPreset(c)
+	if !ok {
+		return
+	}
+
+	if err := rpc.DB.WithContext(c).Delete(&preset).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete report preset", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Report preset deleted successfully", nil)
+}
+
+// ApplyReportPreset godoc
+// @Summary Apply report preset
+// @Description Get the request applying a preset today: the target path and its query parameters, with the relative date range resolved to dates
+// @Tags report-presets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Report preset ID"
+// @Success 200 {object} utilities.Response{data=ApplyReportPresetResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/report-presets/{id}/apply [get]
+func (rpc *ReportPresetController) ApplyReportPreset(c *gin.Context) {
+	preset, ok := rpc.findPreset(c)
+	if !ok {
+		return
+	}
+
+	query := preset.Query(utilities.Now())
+	params := make(map[string]string, len(query))
+	for name := range query {
+		params[name] = query.Get(name)
+	}
+
+	path := "/api/" + preset.Target
+	url := path
+	if len(query) > 0 {
+		url += "?" + query.Encode()
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Report preset applied successfully", ApplyReportPresetResponse{
+		Target: preset.Target,
+		Path:   path,
+		Query:  params,
+		URL:    url,
+	})
+}
+
+// ExportReportPreset godoc
+// @Summary Export report preset
+// @Description Download the spreadsheet a scheduled preset sends, applying the preset today
+// @Tags report-presets
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security BearerAuth
+// @Param id path int true "Report preset ID"
+// @Success 200 {file} file
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/report-presets/{id}/export [get]
+func (rpc *ReportPresetController) ExportReportPreset(c *gin.Context) {
+	preset, ok := rpc.findPreset(c)
+	if !ok {
+		return
+	}
+	if !models.ReportPresetTargets[preset.Target].Exportable {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeActionNotAllowed, "Report cannot be exported", preset.Target+" has no spreadsheet export")
+		return
+	}
+
+	filename, data, err := ExportReportPreset(rpc.DB.WithContext(c), &preset, utilities.Now())
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusBadRequest, "Failed to export report", err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", data)
+}
+
+// findPreset loads the preset of the id parameter owned by the current user, answering 400 for an
+// invalid id and 404 otherwise
+func (rpc *ReportPresetController) findPreset(c *gin.Context) (models.ReportPreset, bool) {
+	var preset models.ReportPreset
+	presetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid report preset ID", err.Error())
+		return preset, false
+	}
+	if err := rpc.DB.WithContext(c).Where("user_id = ?", c.GetUint("user_id")).First(&preset, uint(presetID)).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeReportPresetNotFound, "Report preset not found", "Failed to retrieve report preset")
+		return preset, false
+	}
+	return preset, true
+}
+
+// applyRequest validates a preset request and applies it to preset, answering with the error otherwise
+func (rpc *ReportPresetController) applyRequest(c *gin.Context, preset *models.ReportPreset, req ReportPresetRequest) bool {
+	target := strings.Trim(strings.TrimSpace(req.Target), "/")
+	if _, known := models.ReportPresetTargets[target]; !known {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid report preset target", fmt.Sprintf("target %q cannot have presets", req.Target))
+		return false
+	}
+	if !models.CanUseReportPresetTarget(target, c.GetStringSlice("roles")) {
+		utilities.ErrorResponseCode(c, http.StatusForbidden, utilities.ErrCodeInsufficientAccess, "Insufficient permissions", "your roles cannot use "+target)
+		return false
+	}
+	if err := models.ValidateReportPresetDateRange(target, req.DateRange); err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDateRangeInvalid, "Invalid date range", err.Error())
+		return false
+	}
+	if err := models.ValidateReportPresetSchedule(target, req.Schedule, req.ScheduleTime, req.ScheduleWeekday); err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid schedule", err.Error())
+		return false
+	}
+
+	name := strings.TrimSpace(req.Name)
+	var existing int64
+	if err := rpc.DB.WithContext(c).Model(&models.ReportPreset{}).
+		Where("user_id = ? AND LOWER(name) = LOWER(?) AND id <> ?", preset.UserID, name, preset.ID).
+		Count(&existing).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to check report preset name", err.Error())
+		return false
+	}
+	if existing > 0 {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeDuplicateName, "Report preset name already exists", "you already have a preset named "+name)
+		return false
+	}
+
+	// A changed schedule starts with the next slot
+	if req.Schedule != preset.Schedule || req.ScheduleTime != preset.ScheduleTime || req.ScheduleWeekday != preset.ScheduleWeekday || preset.ScheduledSince == nil {
+		now := time.Now()
+		preset.ScheduledSince = &now
+	}
+	if req.Schedule == models.ReportPresetScheduleNone {
+		preset.ScheduledSince = nil
+	}
+
+	preset.Name = name
+	preset.Target = target
+	preset.DateRange = req.DateRange
+	preset.Schedule = req.Schedule
+	preset.ScheduleTime = req.ScheduleTime
+	preset.ScheduleWeekday = req.ScheduleWeekday
+	preset.Recipients = strings.Join(req.Recipients, ",")
+	if err := preset.SetFilters(req.Filters); err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid filters", err.Error())
+		return false
+	}
+	return true
+}
+
+// ExportReportPreset runs the spreadsheet export of a preset's report on the day of now as the
+// preset's owner, and returns the file name and content
+func ExportReportPreset(db *gorm.DB, preset *models.ReportPreset, now time.Time) (string, []byte, error) {
+	handler, ok := reportPresetExports[preset.Target]
+	if !ok {
+		return "", nil, fmt.Errorf("%s has no spreadsheet export", preset.Target)
+	}
+
+	var owner models.User
+	if err := db.Preload("UserRoles.Role").First(&owner, preset.UserID).Error; err != nil {
+		return "", nil, fmt.Errorf("owner of the preset not found: %v", err)
+	}
+	if !owner.IsActive {
+		return "", nil, fmt.Errorf("owner of the preset is inactive")
+	}
+	roles := make([]string, 0, len(owner.UserRoles))
+	for _, userRole := range owner.UserRoles {
+		roles = append(roles, userRole.Role.Name)
+	}
+	if !models.CanUseReportPresetTarget(preset.Target, roles) {
+		return "", nil, fmt.Errorf("owner of the preset may no longer use %s", preset.Target)
+	}
+
+	query := preset.Query(now)
+	query.Set("export", "xlsx")
+
+	// Run the report handler in process, as the owner would request it
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/"+preset.Target+"?"+query.Encode(), nil)
+	c.Set("user_id", owner.ID)
+	c.Set("roles", roles)
+	handler(NewReportController(db), c)
+
+	if recorder.Code != http.StatusOK {
+		var response utilities.Response
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+		return "", nil, fmt.Errorf("export failed with status %d: %s %s", recorder.Code, response.Message, response.Error)
+	}
+
+	filename := strings.ReplaceAll(preset.Target, "/", "_") + ".xlsx"
+	if _, params, err := mime.ParseMediaType(recorder.Header().Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		filename = params["filename"]
+	}
+	return filename, recorder.Body.Bytes(), nil
+}
+
+type ReportPresetRequest struct {
+	Name            string            `json:"name" binding:"required,max=100" example:"Yesterday's outbounds"`
+	Target          string            `json:"target" binding:"required" example:"reports/handout-outbounds"` // orders or a report path under /api
+	Filters         map[string]string `json:"filters"`                                                       // Query parameters of the target, e.g. search, channel, store, start_date
+	DateRange       string            `json:"date_range" example:"yesterday"`                                // today, yesterday, last_7_days, last_30_days, this_month or last_month
+	Schedule        string            `json:"schedule" example:"daily"`                                      // daily, weekly or empty for no export
+	ScheduleTime    string            `json:"schedule_time" example:"07:00"`                                 // HH:MM warehouse time
+	ScheduleWeekday int               `json:"schedule_weekday" example:"1"`                                  // Weekly schedules, 0 is Sunday
+	Recipients      []string          `json:"recipients" binding:"omitempty,dive,email" example:"ops@livotech.com"`
+}
+
+type ReportPresetsListResponse struct {
+	ReportPresets []models.ReportPresetResponse `json:"report_presets"`
+	Pagination    utilities.PaginationResponse  `json:"pagination"`
+}
+
+type ApplyReportPresetResponse struct {
+	Target string            `json:"target" example:"reports/handout-outbounds"`
+	Path   string            `json:"path" example:"/api/reports/handout-outbounds"`
+	Query  map[string]string `json:"query"`
+	URL    string            `json:"url" example:"/api/reports/handout-outbounds?date=2025-01-30"`
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"fmt"
 	"livo-backend/models"
+	"livo-backend/repositories"
 	"livo-backend/utilities"
 	"net/http"
 	"strconv"
@@ -14,12 +15,13 @@ import (
 )
 
 type ReturnController struct {
-	DB *gorm.DB
+	DB      *gorm.DB
+	Returns *repositories.ReturnRepository
 }
 
 // NewReturnController creates a new return controller
 func NewReturnController(db *gorm.DB) *ReturnController {
-	return &ReturnController{DB: db}
+	return &ReturnController{DB: db, Returns: repositories.NewReturnRepository(db)}
 }
 
 // GetReturns godoc
@@ -34,6 +36,10 @@ func NewReturnController(db *gorm.DB) *ReturnController {
 // @Param start_date query string false "Start date (YYYY-MM-DD format)"
 // @Param end_date query string false "End date (YYYY-MM-DD format)"
 // @Param search query string false "Search by return new tracking (partial match)"
+// @Param include query string false "Relations to load, comma separated (return_details, channel, store, operators, order, user_roles, replacement_chain). Defaults to all"
+// @Param fields query string false "Return fields to return, comma separated (id is always returned)"
+// @Param sort_by query string false "Sort keys, comma separated, each optionally suffixed with :asc or :desc (created_at, updated_at, tracking, old_tracking, order_ginee_id)" default(created_at:desc)
+// @Param sort_dir query string false "Direction of sort keys without a suffix: asc or desc" default(asc)
 // @Success 200 {object} utilities.Response{data=ReturnsListResponse}
 // @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
@@ -56,13 +62,13 @@ func (rc *ReturnController) GetReturns(c *gin.Context) {
 	var total int64
 
 	// Build query with optional search
-	query := rc.DB.Model(&models.Return{})
+	query := rc.DB.WithContext(c).Model(&models.Return{})
 
 	// Apply date range filters if provided
 	if startDate != "" {
 		// Parse start date and set time to beginning of day
 		if parsedStartDate, err := time.Parse("2006-01-02", startDate); err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
 			return
 		} else {
 			startOfDay := parsedStartDate.Format("2006-01-02 00:00:00")
@@ -73,7 +79,7 @@ func (rc *ReturnController) GetReturns(c *gin.Context) {
 	if endDate != "" {
 		// Parse end date and set time to end of day
 		if parsedEndDate, err := time.Parse("2006-01-02", endDate); err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
 			return
 		} else {
 			// Add 24 hours to get the start of next day, then use < instead of <=
@@ -87,33 +93,29 @@ func (rc *ReturnController) GetReturns(c *gin.Context) {
 		query = query.Where("new_tracking ILIKE ? OR old_tracking ILIKE ? OR order_ginee_id ILIKE ?", "%"+search+"%", "%"+search+"%", "%"+search+"%")
 	}
 
+	sortScope, err := utilities.ApplySort(c, models.ReturnSortKeys, "created_at:desc", "returns.id")
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid sort", err.Error())
+		return
+	}
+
 	// Get total count with search filter
 	if err := query.Count(&total).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count returns", err.Error())
 		return
 	}
 
-	// Get returns with pagination, search filter, and order by ID desc
-	if err := query.Preload("ReturnDetails.Product").
-		Preload("Channel").
-		Preload("Store").
-		Preload("CreateOperator").
-		Preload("UpdateOperator").Order("id DESC").Limit(limit).Offset(offset).Find(&rets).Error; err != nil {
+	// Get returns with pagination, search filter, and sorting
+	serializer := utilities.NewSerializer(c)
+	if err := preloadReturnRelations(query, serializer).Scopes(sortScope).Limit(limit).Offset(offset).Find(&rets).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve returns", err.Error())
 		return
 	}
 
-	// Load order data for each return
+	// Load order data and replacement chain for each return
 	for i := range rets {
-		if rets[i].OldTracking != "" {
-			var order models.Order
-			if err := rc.DB.Preload("OrderDetails").
-				Preload("PickOperator.UserRoles.Role").
-				Preload("PickOperator.UserRoles.Assigner").
-				Where("tracking = ?", rets[i].OldTracking).First(&order).Error; err == nil {
-				rets[i].Order = &order
-			}
-		}
+		rc.loadReturnOrder(c, &rets[i], serializer)
+		rc.loadReplacementChain(c, &rets[i], serializer)
 	}
 
 	// Convert to response format
@@ -132,7 +134,7 @@ func (rc *ReturnController) GetReturns(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Returns retrieved successfully"
+	message := utilities.Messagef(c, "Returns retrieved successfully")
 	var filters []string
 
 	if startDate != "" || endDate != "" {
@@ -151,10 +153,10 @@ func (rc *ReturnController) GetReturns(c *gin.Context) {
 	}
 
 	if len(filters) > 0 {
-		message += fmt.Sprintf(" (filtered by %s)", strings.Join(filters, " | "))
+		message += utilities.Messagef(c, " (filtered by %s)", strings.Join(filters, " | "))
 	}
 
-	utilities.SuccessResponse(c, http.StatusOK, message, response)
+	utilities.SuccessResponse(c, http.StatusOK, message, serializer.Serialize(response, "returns"))
 }
 
 // GetReturn godoc
@@ -165,6 +167,8 @@ func (rc *ReturnController) GetReturns(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Return ID"
+// @Param include query string false "Relations to load, comma separated (return_details, channel, store, operators, order, user_roles, replacement_chain). Defaults to all"
+// @Param fields query string false "Return fields to return, comma separated (id is always returned)"
 // @Success 200 {object} utilities.Response{data=models.ReturnResponse}
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
@@ -174,28 +178,52 @@ func (rc *ReturnController) GetReturn(c *gin.Context) {
 	returnID := c.Param("id")
 
 	var ret models.Return
-	if err := rc.DB.Preload("ReturnDetails.Product").
-		Preload("Channel").
-		Preload("Store").
-		Preload("CreateOperator").
-		Preload("UpdateOperator").
+	serializer := utilities.NewSerializer(c)
+	if err := preloadReturnRelations(rc.DB.WithContext(c), serializer).
 		First(&ret, returnID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Return not found", err.Error())
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeReturnNotFound, "Return not found", "Failed to retrieve return")
 		return
 	}
 
 	// Load order data if old_tracking exists
-	if ret.OldTracking != "" {
-		var order models.Order
-		if err := rc.DB.Preload("OrderDetails").
-			Preload("PickOperator.UserRoles.Role").
-			Preload("PickOperator.UserRoles.Assigner").
-			Where("tracking = ?", ret.OldTracking).First(&order).Error; err == nil {
-			ret.Order = &order
-		}
+	rc.loadReturnOrder(c, &ret, serializer)
+	rc.loadReplacementChain(c, &ret, serializer)
+
+	utilities.SuccessResponse(c, http.StatusOK, "Return retrieved successfully", serializer.Serialize(ret.ToReturnResponse(), ""))
+}
+
+// preloadReturnRelations preloads the return relations selected with include=
+func preloadReturnRelations(query *gorm.DB, serializer *utilities.Serializer) *gorm.DB {
+	query = serializer.Preload(query, "return_details", "ReturnDetails.Product")
+	query = serializer.Preload(query, "channel", "Channel")
+	query = serializer.Preload(query, "store", "Store")
+	query = serializer.Preload(query, "operators", "CreateOperator", "UpdateOperator")
+	return query
+}
+
+// loadReturnOrder attaches the original order of a return (matched by old tracking) when included
+func (rc *ReturnController) loadReturnOrder(c *gin.Context, ret *models.Return, serializer *utilities.Serializer) {
+	if ret.OldTracking == "" || !serializer.Includes("order") {
+		return
+	}
+
+	var relations []string
+	if serializer.Includes("user_roles") {
+		relations = append(relations, repositories.OrderPickerRoles)
 	}
+	if order, err := rc.Returns.Orders.FindByTracking(c, ret.OldTracking, relations...); err == nil {
+		ret.Order = &order
+	}
+}
 
-	utilities.SuccessResponse(c, http.StatusOK, "Return retrieved successfully", ret.ToReturnResponse())
+// loadReplacementChain attaches the exchanges the return is part of when included
+func (rc *ReturnController) loadReplacementChain(c *gin.Context, ret *models.Return, serializer *utilities.Serializer) {
+	if !serializer.Includes("replacement_chain") {
+		return
+	}
+	if chain, err := models.LoadReplacementChain(rc.DB.WithContext(c), ret); err == nil {
+		ret.ReplacementChain = chain
+	}
 }
 
 // CreateReturn godoc
@@ -215,7 +243,7 @@ func (rc *ReturnController) CreateReturn(c *gin.Context) {
 	// Get user ID from JWT token
 	userID, exists := c.Get("user_id")
 	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User not authenticated")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeAuthRequired, "Unauthorized", "User not authenticated")
 		return
 	}
 
@@ -237,8 +265,8 @@ func (rc *ReturnController) CreateReturn(c *gin.Context) {
 
 	// Check for duplicate new tracking
 	var existingReturn models.Return
-	if err := rc.DB.Where("new_tracking = ?", req.NewTracking).First(&existingReturn).Error; err == nil {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Tracking already exists", "Return with this new tracking already exists")
+	if err := rc.DB.WithContext(c).Where("new_tracking = ?", req.NewTracking).First(&existingReturn).Error; err == nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDuplicateTracking, "Tracking already exists", "Return with this new tracking already exists")
 		return
 	}
 
@@ -247,108 +275,89 @@ func (rc *ReturnController) CreateReturn(c *gin.Context) {
 
 	// Find order by old_tracking to get order_ginee_id and details (before transaction)
 	var order models.Order
-	if err := rc.DB.Preload("OrderDetails").Where("tracking = ?", req.OldTracking).First(&order).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Order not found", "No order found with the specified old tracking number")
+	if err := rc.DB.WithContext(c).Preload("OrderDetails").Where("tracking = ?", req.OldTracking).First(&order).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeOrderNotFound, "Order not found", "No order found with the specified old tracking number")
+			return
+		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order", err.Error())
 		return
 	}
 
 	// Check if order has details
 	if len(order.OrderDetails) == 0 {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Order has no details", "The order has no order details to copy")
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeOrderStateInvalid, "Order has no details", "The order has no order details to copy")
 		return
 	}
 
-	// Start database transaction
-	tx := rc.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
-	ret := models.Return{
-		NewTracking:  req.NewTracking,
-		OldTracking:  req.OldTracking,
-		ReturnType:   req.ReturnType,
-		ChannelID:    req.ChannelID,
-		StoreID:      req.StoreID,
-		ReturnReason: req.ReturnReason,
-		CreatedBy:    userIDUint,
-		OrderGineeID: order.OrderGineeID,
-	}
-
-	// Create return within transaction
-	if err := tx.Create(&ret).Error; err != nil {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create return", err.Error())
-		return
-	}
+	var ret models.Return
 
 	// Track products not found and created count
 	var productsNotFound []string
 	var createdCount int
 
-	// Create return details based on order details
-	for _, orderDetail := range order.OrderDetails {
-		// Find product by SKU from order detail
-		var product models.Product
-		if err := tx.Where("sku = ?", orderDetail.Sku).First(&product).Error; err != nil {
-			// Track products not found
-			productsNotFound = append(productsNotFound, orderDetail.Sku)
-			continue
+	err := utilities.WithTransaction(rc.DB.WithContext(c), func(tx *gorm.DB) error {
+		ret = models.Return{
+			NewTracking:  req.NewTracking,
+			OldTracking:  req.OldTracking,
+			ReturnType:   req.ReturnType,
+			ChannelID:    req.ChannelID,
+			StoreID:      req.StoreID,
+			ReturnReason: req.ReturnReason,
+			CreatedBy:    userIDUint,
+			OrderGineeID: order.OrderGineeID,
 		}
 
-		returnDetail := models.ReturnDetail{
-			ReturnID:  ret.ID,
-			ProductID: product.ID,
-			Quantity:  orderDetail.Quantity,
+		// Create return within transaction
+		if err := tx.Create(&ret).Error; err != nil {
+			return fmt.Errorf("failed to create return: %w", err)
 		}
 
-		if err := tx.Create(&returnDetail).Error; err != nil {
-			tx.Rollback()
-			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create return detail", err.Error())
-			return
+		// Create return details based on order details
+		for _, orderDetail := range order.OrderDetails {
+			// Find product by SKU from order detail
+			var product models.Product
+			if err := tx.Where("sku = ?", orderDetail.Sku).First(&product).Error; err != nil {
+				// Track products not found
+				productsNotFound = append(productsNotFound, orderDetail.Sku)
+				continue
+			}
+
+			returnDetail := models.ReturnDetail{
+				ReturnID:  ret.ID,
+				ProductID: product.ID,
+				Quantity:  orderDetail.Quantity,
+			}
+
+			if err := tx.Create(&returnDetail).Error; err != nil {
+				return fmt.Errorf("failed to create return detail: %w", err)
+			}
+			createdCount++
 		}
-		createdCount++
-	}
 
-	// If no return details were created, return an error
-	if createdCount == 0 {
-		tx.Rollback()
-		errorMsg := fmt.Sprintf("No return details created. Products not found in database: %s", strings.Join(productsNotFound, ", "))
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Failed to create return details", errorMsg)
-		return
-	}
+		// If no return details were created, return an error
+		if createdCount == 0 {
+			errorMsg := fmt.Sprintf("No return details created. Products not found in database: %s", strings.Join(productsNotFound, ", "))
+			return utilities.NewStatusErrorCode(http.StatusBadRequest, utilities.ErrCodeReturnDetailsInvalid, "Failed to create return details", errorMsg)
+		}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction", err.Error())
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to create return")
 		return
 	}
 
-	// Reload return with relationships
-	rc.DB.Preload("ReturnDetails.Product").
-		Preload("Channel").
-		Preload("Store").
-		Preload("CreateOperator").
-		Preload("UpdateOperator").
-		First(&ret, ret.ID)
-
-	// Load order data
-	if ret.OldTracking != "" {
-		var order models.Order
-		if err := rc.DB.Preload("OrderDetails").
-			Preload("PickOperator.UserRoles.Role").
-			Preload("PickOperator.UserRoles.Assigner").
-			Where("tracking = ?", ret.OldTracking).First(&order).Error; err == nil {
-			ret.Order = &order
-		}
+	// Reload return with relationships and order data
+	if err := rc.Returns.Reload(c, &ret); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload return", err.Error())
+		return
 	}
 
 	// Build success message with warning if some products weren't found
-	message := fmt.Sprintf("Return created successfully (%d of %d products synced)", createdCount, len(order.OrderDetails))
+	message := utilities.Messagef(c, "Return created successfully (%d of %d products synced)", createdCount, len(order.OrderDetails))
 	if len(productsNotFound) > 0 {
-		message += fmt.Sprintf(". Warning: %d product(s) not found - SKU: %s", len(productsNotFound), strings.Join(productsNotFound, ", "))
+		message += utilities.Messagef(c, ". Warning: %d product(s) not found - SKU: %s", len(productsNotFound), strings.Join(productsNotFound, ", "))
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, ret.ToReturnResponse())
@@ -356,7 +365,7 @@ func (rc *ReturnController) CreateReturn(c *gin.Context) {
 
 // UpdateDataReturn godoc
 // @Summary Update return data
-// @Description Update return data.
+// @Description Update return data. Setting a return number without a scrap number approves the return for restock and generates its putaway tasks.
 // @Tags returns
 // @Accept json
 // @Produce json
@@ -373,7 +382,7 @@ func (rc *ReturnController) UpdateDataReturn(c *gin.Context) {
 	// Get user ID from JWT token
 	userID, exists := c.Get("user_id")
 	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User not authenticated")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeAuthRequired, "Unauthorized", "User not authenticated")
 		return
 	}
 
@@ -386,8 +395,8 @@ func (rc *ReturnController) UpdateDataReturn(c *gin.Context) {
 	}
 
 	var ret models.Return
-	if err := rc.DB.First(&ret, returnID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Return not found", err.Error())
+	if err := rc.DB.WithContext(c).First(&ret, returnID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeReturnNotFound, "Return not found", "Failed to retrieve return")
 		return
 	}
 
@@ -406,67 +415,149 @@ func (rc *ReturnController) UpdateDataReturn(c *gin.Context) {
 	ret.ScrapNumber = req.ScrapNumber
 	ret.UpdatedBy = &userIDUint
 
-	// Save the updated return
-	if err := rc.DB.Save(&ret).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update return", err.Error())
-		return
-	}
+	err := utilities.WithTransaction(rc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Save the updated return
+		if err := tx.Save(&ret).Error; err != nil {
+			return fmt.Errorf("failed to update return: %w", err)
+		}
 
-	// Check if return details are empty
-	var detailCount int64
-	rc.DB.Model(&models.ReturnDetail{}).Where("return_id = ?", ret.ID).Count(&detailCount)
+		// Check if return details are empty
+		var detailCount int64
+		if err := tx.Model(&models.ReturnDetail{}).Where("return_id = ?", ret.ID).Count(&detailCount).Error; err != nil {
+			return fmt.Errorf("failed to count return details: %w", err)
+		}
 
-	// If return details are empty, copy from order details
-	if detailCount == 0 && ret.OldTracking != "" {
-		// Find order by old_tracking
-		var order models.Order
-		if err := rc.DB.Preload("OrderDetails").Where("tracking = ?", ret.OldTracking).First(&order).Error; err == nil {
-			// Create return details based on order details
-			for _, orderDetail := range order.OrderDetails {
-				// Find product by SKU from order detail
-				var product models.Product
-				if err := rc.DB.Where("sku = ?", orderDetail.Sku).First(&product).Error; err != nil {
-					// Skip products not found
-					continue
+		// If return details are empty, copy from order details
+		if detailCount == 0 && ret.OldTracking != "" {
+			// Find order by old_tracking
+			var order models.Order
+			if err := tx.Preload("OrderDetails").Where("tracking = ?", ret.OldTracking).First(&order).Error; err == nil {
+				// Create return details based on order details
+				for _, orderDetail := range order.OrderDetails {
+					// Find product by SKU from order detail
+					var product models.Product
+					if err := tx.Where("sku = ?", orderDetail.Sku).First(&product).Error; err != nil {
+						// Skip products not found
+						continue
+					}
+
+					returnDetail := models.ReturnDetail{
+						ReturnID:  ret.ID,
+						ProductID: product.ID,
+						Quantity:  orderDetail.Quantity,
+					}
+
+					if err := tx.Create(&returnDetail).Error; err != nil {
+						return fmt.Errorf("failed to create return detail: %w", err)
+					}
 				}
+			}
 
-				returnDetail := models.ReturnDetail{
-					ReturnID:  ret.ID,
-					ProductID: product.ID,
-					Quantity:  orderDetail.Quantity,
+			// Update order_ginee_id from the order
+			if order.ID != 0 {
+				ret.OrderGineeID = order.OrderGineeID
+				if err := tx.Save(&ret).Error; err != nil {
+					return fmt.Errorf("failed to update return: %w", err)
 				}
-
-				rc.DB.Create(&returnDetail)
 			}
 		}
 
-		// Update order_ginee_id from the order
-		if order.ID != 0 {
-			ret.OrderGineeID = order.OrderGineeID
-			rc.DB.Save(&ret)
-		}
+		// Approved returns get putaway tasks for their items
+		return models.GeneratePutawayTasks(tx, &ret)
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to update return")
+		return
 	}
 
-	// Load updated return with relationships
-	rc.DB.Preload("ReturnDetails.Product").
-		Preload("Channel").
-		Preload("Store").
-		Preload("CreateOperator").
-		Preload("UpdateOperator").
-		First(&ret, ret.ID)
+	// Load updated return with relationships and order data
+	if err := rc.Returns.Reload(c, &ret); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reload return", err.Error())
+		return
+	}
 
-	// Load order data if old_tracking matches
-	if ret.OldTracking != "" {
+	utilities.SuccessResponse(c, http.StatusOK, "Return data updated successfully", ret.ToReturnResponse())
+}
+
+// LinkReplacementOrder godoc
+// @Summary Link a return to its replacement order
+// @Description Link the order shipped to the buyer in exchange for the returned one, by order ID or Ginee order ID. The order must not be cancelled, the returned order itself, the replacement of another return or earlier in the replacement chain. Sending neither unlinks the replacement.
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Return ID"
+// @Param request body LinkReplacementRequest true "Link Replacement Request"
+// @Success 200 {object} utilities.Response{data=models.ReturnResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/returns/{id}/link-replacement [put]
+func (rc *ReturnController) LinkReplacementOrder(c *gin.Context) {
+	returnID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid return ID", err.Error())
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	var req LinkReplacementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+	req.OrderGineeID = strings.TrimSpace(req.OrderGineeID)
+
+	var ret models.Return
+	if err := rc.DB.WithContext(c).First(&ret, uint(returnID)).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeReturnNotFound, "Return not found", "Failed to retrieve return")
+		return
+	}
+
+	var replacementOrderID *uint
+	if req.OrderID != 0 || req.OrderGineeID != "" {
 		var order models.Order
-		if err := rc.DB.Preload("OrderDetails").
-			Preload("PickOperator.UserRoles.Role").
-			Preload("PickOperator.UserRoles.Assigner").
-			Where("tracking = ?", ret.OldTracking).First(&order).Error; err == nil {
-			ret.Order = &order
+		query := rc.DB.WithContext(c)
+		if req.OrderID != 0 {
+			query = query.Where("id = ?", req.OrderID)
+		} else {
+			query = query.Where("order_ginee_id = ?", req.OrderGineeID)
+		}
+		if err := query.First(&order).Error; err != nil {
+			utilities.LookupErrorResponse(c, err, utilities.ErrCodeOrderNotFound, "Order not found", "Failed to retrieve order")
+			return
+		}
+
+		if err := models.ValidateReplacementOrder(rc.DB.WithContext(c), &ret, &order); err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeReplacementInvalid, "Invalid replacement order", err.Error())
+			return
 		}
+		replacementOrderID = &order.ID
 	}
 
-	utilities.SuccessResponse(c, http.StatusOK, "Return data updated successfully", ret.ToReturnResponse())
+	if err := rc.DB.WithContext(c).Model(&ret).Updates(map[string]interface{}{
+		"replacement_order_id": replacementOrderID,
+		"updated_by":           userID,
+	}).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update return", err.Error())
+		return
+	}
+
+	serializer := utilities.NewSerializer(c)
+	if err := preloadReturnRelations(rc.DB.WithContext(c), serializer).First(&ret, ret.ID).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve return", err.Error())
+		return
+	}
+	rc.loadReturnOrder(c, &ret, serializer)
+	rc.loadReplacementChain(c, &ret, serializer)
+
+	message := "Replacement order linked successfully"
+	if replacementOrderID == nil {
+		message = "Replacement order unlinked successfully"
+	}
+	utilities.SuccessResponse(c, http.StatusOK, message, ret.ToReturnResponse())
 }
 
 // Request/Response structs
@@ -493,3 +584,10 @@ type UpdateReturnRequest struct {
 	ReturnNumber string `json:"return_number"`
 	ScrapNumber  string `json:"scrap_number"`
 }
+
+// LinkReplacementRequest names the replacement order of a return, by ID or Ginee order ID. Neither
+// unlinks the replacement.
+type LinkReplacementRequest struct {
+	OrderID      uint   `json:"order_id" example:"1024"`
+	OrderGineeID string `json:"order_ginee_id" example:"2510026HB12XK9"`
+}
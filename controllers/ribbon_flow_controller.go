@@ -1,13 +1,11 @@
 package controllers
 
 import (
-	"fmt"
 	"livo-backend/models"
 	"livo-backend/utilities"
 	"net/http"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -56,28 +54,28 @@ func (rfc *RibbonFlowController) GetRibbonFlows(c *gin.Context) {
 	var total int64
 
 	// Get tracking numbers primarily from qc_ribbons
-	query := rfc.DB.Model(&models.QcRibbon{}).Select("DISTINCT tracking").Where("tracking IS NOT NULL AND tracking != ''")
+	query := rfc.DB.WithContext(c).Model(&models.QcRibbon{}).Select("DISTINCT tracking").Where("tracking IS NOT NULL AND tracking != ''")
 
 	// Apply date range filters if provided
 	if startDate != "" {
 		// Parse start date and set time to beginning of day
-		if parsedStartDate, err := time.Parse("2006-01-02", startDate); err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
+		if parsedStartDate, err := utilities.ParseDate(startDate); err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid start_date format", "start_date must be in YYYY-MM-DD format")
 			return
 		} else {
-			startOfDay := parsedStartDate.Format("2006-01-02 00:00:00")
+			startOfDay, _ := utilities.DayRange(parsedStartDate)
 			query = query.Where("created_at >= ?", startOfDay)
 		}
 	}
 
 	if endDate != "" {
 		// Parse end date and set time to end of day
-		if parsedEndDate, err := time.Parse("2006-01-02", endDate); err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
+		if parsedEndDate, err := utilities.ParseDate(endDate); err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidDateFormat, "Invalid end_date format", "end_date must be in YYYY-MM-DD format")
 			return
 		} else {
 			// Add 24 hours to get the start of next day, then use < instead of <=
-			nextDay := parsedEndDate.AddDate(0, 0, 1).Format("2006-01-02 00:00:00")
+			_, nextDay := utilities.DayRange(parsedEndDate)
 			query = query.Where("created_at < ?", nextDay)
 		}
 	}
@@ -102,7 +100,7 @@ func (rfc *RibbonFlowController) GetRibbonFlows(c *gin.Context) {
 	// Build ribbon flows for each tracking
 	var ribbonFlows []RibbonFlowResponse
 	for _, tracking := range trackingNumbers {
-		flow := rfc.buildRibbonFlow(tracking)
+		flow := rfc.buildRibbonFlow(c, tracking)
 		ribbonFlows = append(ribbonFlows, flow)
 	}
 
@@ -116,7 +114,7 @@ func (rfc *RibbonFlowController) GetRibbonFlows(c *gin.Context) {
 	}
 
 	// Build success message with date filters
-	message := "Ribbon flows retrieved successfully"
+	message := utilities.Messagef(c, "Ribbon flows retrieved successfully")
 	var filters []string
 
 	if startDate != "" || endDate != "" {
@@ -135,7 +133,7 @@ func (rfc *RibbonFlowController) GetRibbonFlows(c *gin.Context) {
 	}
 
 	if len(filters) > 0 {
-		message += fmt.Sprintf(" (filtered by %s)", strings.Join(filters, " | "))
+		message += utilities.Messagef(c, " (filtered by %s)", strings.Join(filters, " | "))
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
@@ -143,7 +141,7 @@ func (rfc *RibbonFlowController) GetRibbonFlows(c *gin.Context) {
 
 // GetRibbonFlow godoc
 // @Summary Get ribbon flow tracking
-// @Description Get the complete flow tracking through ribbon process (qc-ribbon -> outbound -> order).
+// @Description Get the complete flow tracking through ribbon process (qc-ribbon -> outbound -> order). Deprecated: use GET /api/orders/{id}/timeline, which also covers picking, returns and complains.
 // @Tags ribbons
 // @Accept json
 // @Produce json
@@ -153,20 +151,21 @@ func (rfc *RibbonFlowController) GetRibbonFlows(c *gin.Context) {
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Failure 404 {object} utilities.Response
+// @Deprecated
 // @Router /api/ribbons/ribbon-flows/{tracking} [get]
 func (rfc *RibbonFlowController) GetRibbonFlow(c *gin.Context) {
 	tracking := c.Param("tracking")
 
 	if tracking == "" {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid tracking", "Tracking number is required")
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidTracking, "Invalid tracking", "Tracking number is required")
 		return
 	}
 
-	flow := rfc.buildRibbonFlow(tracking)
+	flow := rfc.buildRibbonFlow(c, tracking)
 
 	// CHANGED: Check if qc-ribbon exists (since it's the primary source)
 	if flow.QcRibbon == nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Tracking not found", "No qc-ribbon record found for the specified tracking number")
+		utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeTrackingNotFound, "Tracking not found", "No qc-ribbon record found for the specified tracking number")
 		return
 	}
 
@@ -174,31 +173,39 @@ func (rfc *RibbonFlowController) GetRibbonFlow(c *gin.Context) {
 }
 
 // Helper function to build ribbon flow for a tracking number
-func (rfc *RibbonFlowController) buildRibbonFlow(tracking string) RibbonFlowResponse {
+func (rfc *RibbonFlowController) buildRibbonFlow(c *gin.Context, tracking string) RibbonFlowResponse {
 	var response RibbonFlowResponse
 	response.Tracking = tracking
 
 	// 1. Query QC Ribbon (PRIMARY SOURCE)
-	var qcRibbon models.QcRibbon
-	if err := rfc.DB.Preload("QcOperator").Where("tracking = ?", tracking).First(&qcRibbon).Error; err == nil {
-		var operator *RibbonOperatorFlowInfo
-		if qcRibbon.QcOperator != nil {
-			operator = &RibbonOperatorFlowInfo{
-				ID:       qcRibbon.QcOperator.ID,
-				Username: qcRibbon.QcOperator.Username,
-				FullName: qcRibbon.QcOperator.FullName,
+	// Multi-parcel orders have one QC per parcel, the first parcel stands for the whole QC
+	var qcRibbons []models.QcRibbon
+	if err := rfc.DB.WithContext(c).Preload("QcOperator").Where("tracking = ?", tracking).Order("parcel_number ASC").Find(&qcRibbons).Error; err == nil && len(qcRibbons) > 0 {
+		info := &QcRibbonFlowInfo{Parcels: make([]QcRibbonParcelFlowInfo, len(qcRibbons))}
+		for i, qcRibbon := range qcRibbons {
+			var operator *RibbonOperatorFlowInfo
+			if qcRibbon.QcOperator != nil {
+				operator = &RibbonOperatorFlowInfo{
+					ID:       qcRibbon.QcOperator.ID,
+					Username: qcRibbon.QcOperator.Username,
+					FullName: qcRibbon.QcOperator.FullName,
+				}
 			}
-		}
 
-		response.QcRibbon = &QcRibbonFlowInfo{
-			Operator:  operator,
-			CreatedAt: qcRibbon.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			info.Parcels[i] = QcRibbonParcelFlowInfo{
+				ParcelNumber: qcRibbon.ParcelNumber,
+				Operator:     operator,
+				CreatedAt:    qcRibbon.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			}
 		}
+		info.Operator = info.Parcels[0].Operator
+		info.CreatedAt = info.Parcels[0].CreatedAt
+		response.QcRibbon = info
 	}
 
 	// 2. Query Outbound
 	var outbound models.Outbound
-	if err := rfc.DB.Preload("OutboundOperator").Where("tracking = ?", tracking).First(&outbound).Error; err == nil {
+	if err := rfc.DB.WithContext(c).Preload("OutboundOperator").Where("tracking = ?", tracking).First(&outbound).Error; err == nil {
 		var operator *RibbonOperatorFlowInfo
 		if outbound.OutboundOperator != nil {
 			operator = &RibbonOperatorFlowInfo{
@@ -218,7 +225,7 @@ func (rfc *RibbonFlowController) buildRibbonFlow(tracking string) RibbonFlowResp
 
 	// 3. Query Order (LAST)
 	var order models.Order
-	if err := rfc.DB.Preload("AssignOperator").
+	if err := rfc.DB.WithContext(c).Preload("AssignOperator").
 		Preload("PickOperator").
 		Preload("PendingOperator").
 		Preload("ChangeOperator").
@@ -229,6 +236,7 @@ func (rfc *RibbonFlowController) buildRibbonFlow(tracking string) RibbonFlowResp
 			ProcessingStatus: order.ProcessingStatus,
 			OrderGineeID:     order.OrderGineeID,
 			Complained:       order.Complained,
+			ParcelCount:      order.ParcelCount,
 			CreatedAt:        order.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		}
 
@@ -300,6 +308,21 @@ func (rfc *RibbonFlowController) buildRibbonFlow(tracking string) RibbonFlowResp
 		response.Order = &orderInfo
 	}
 
+	// QC is complete once every parcel the order declares is QC'd
+	if response.QcRibbon != nil {
+		parcelCount := 1
+		if response.Order != nil {
+			parcelCount = response.Order.ParcelCount
+		}
+		parcels := make([]int, len(response.QcRibbon.Parcels))
+		for i, parcel := range response.QcRibbon.Parcels {
+			parcels[i] = parcel.ParcelNumber
+		}
+		progress := models.NewQcParcelProgress(parcelCount, parcels)
+		response.QcRibbon.ParcelCount = progress.ParcelCount
+		response.QcRibbon.Complete = progress.Complete
+	}
+
 	return response
 }
 
@@ -325,8 +348,17 @@ type RibbonFlowResponse struct {
 }
 
 type QcRibbonFlowInfo struct {
-	Operator  *RibbonOperatorFlowInfo `json:"operator,omitempty"`
-	CreatedAt string                  `json:"created_at"`
+	Operator    *RibbonOperatorFlowInfo  `json:"operator,omitempty"` // Operator of the first parcel
+	CreatedAt   string                   `json:"created_at"`
+	ParcelCount int                      `json:"parcel_count" example:"1"` // Parcels the order declares
+	Complete    bool                     `json:"complete"`                 // Every declared parcel is QC'd
+	Parcels     []QcRibbonParcelFlowInfo `json:"parcels"`
+}
+
+type QcRibbonParcelFlowInfo struct {
+	ParcelNumber int                     `json:"parcel_number" example:"1"`
+	Operator     *RibbonOperatorFlowInfo `json:"operator,omitempty"`
+	CreatedAt    string                  `json:"created_at"`
 }
 
 type OutboundFlowInfo struct {
@@ -348,6 +380,7 @@ type RibbonOrderFlowInfo struct {
 	ProcessingStatus string                  `json:"processing_status"`
 	OrderGineeID     string                  `json:"order_ginee_id"`
 	Complained       bool                    `json:"complained"`
+	ParcelCount      int                     `json:"parcel_count"`
 	CreatedAt        string                  `json:"created_at"`
 	AssignedBy       *RibbonOperatorFlowInfo `json:"assigned_by,omitempty"`
 	AssignedAt       *string                 `json:"assigned_at,omitempty"`
@@ -0,0 +1,211 @@
+package controllers
+
+import (
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RoleTemplateController struct {
+	DB *gorm.DB
+}
+
+// NewRoleTemplateController creates a new role template controller
+func NewRoleTemplateController(db *gorm.DB) *RoleTemplateController {
+	return &RoleTemplateController{DB: db}
+}
+
+// GetRoleTemplates godoc
+// @Summary Get role templates
+// @Description Get the onboarding role templates by name. A template bundles the role, the zone and the active flag users created from it get.
+// @Tags user-manager
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utilities.Response{data=[]models.RoleTemplateResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/user-manager/role-templates [get]
+func (rtc *RoleTemplateController) GetRoleTemplates(c *gin.Context) {
+	var templates []models.RoleTemplate
+	if err := rtc.DB.WithContext(c).Preload("Role").Preload("Zone").Preload("Creator").Order("name ASC").Find(&templates).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve role templates", err.Error())
+		return
+	}
+
+	templateResponses := make([]models.RoleTemplateResponse, len(templates))
+	for i := range templates {
+		templateResponses[i] = templates[i].ToRoleTemplateResponse()
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Role templates retrieved successfully", templateResponses)
+}
+
+// CreateRoleTemplate godoc
+// @Summary Create role template
+// @Description Create an onboarding role template. Only roles the current user may assign can be bundled.
+// @Tags user-manager
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RoleTemplateRequest true "Create role template request"
+// @Success 201 {object} utilities.Response{data=models.RoleTemplateResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/user-manager/role-templates [post]
+func (rtc *RoleTemplateController) CreateRoleTemplate(c *gin.Context) {
+	var req RoleTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	template := models.RoleTemplate{CreatedBy: c.GetUint("user_id"), IsActive: true}
+	if !rtc.applyRoleTemplateRequest(c, &template, req) {
+		return
+	}
+
+	if err := rtc.DB.WithContext(c).Create(&template).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create role template", err.Error())
+		return
+	}
+
+	rtc.DB.WithContext(c).Preload("Role").Preload("Zone").Preload("Creator").First(&template, template.ID)
+
+	utilities.SuccessResponse(c, http.StatusCreated, "Role template created successfully", template.ToRoleTemplateResponse())
+}
+
+// UpdateRoleTemplate godoc
+// @Summary Update role template
+// @Description Update an onboarding role template. Users created from it earlier keep their roles and zones.
+// @Tags user-manager
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Role template ID"
+// @Param request body RoleTemplateRequest true "Update role template request"
+// @Success 200 {object} utilities.Response{data=models.RoleTemplateResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/user-manager/role-templates/{id} [put]
+func (rtc *RoleTemplateController) UpdateRoleTemplate(c *gin.Context) {
+	templateID := c.Param("id")
+
+	var req RoleTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	var template models.RoleTemplate
+	if err := rtc.DB.WithContext(c).First(&template, templateID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeRoleTemplateNotFound, "Role template not found", "Failed to retrieve role template")
+		return
+	}
+
+	if !rtc.applyRoleTemplateRequest(c, &template, req) {
+		return
+	}
+
+	if err := rtc.DB.WithContext(c).Save(&template).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update role template", err.Error())
+		return
+	}
+
+	rtc.DB.WithContext(c).Preload("Role").Preload("Zone").Preload("Creator").First(&template, template.ID)
+
+	utilities.SuccessResponse(c, http.StatusOK, "Role template updated successfully", template.ToRoleTemplateResponse())
+}
+
+// RemoveRoleTemplate godoc
+// @Summary Remove role template
+// @Description Soft delete an onboarding role template. Users created from it keep their roles and zones.
+// @Tags user-manager
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Role template ID"
+// @Success 200 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/user-manager/role-templates/{id} [delete]
+func (rtc *RoleTemplateController) RemoveRoleTemplate(c *gin.Context) {
+	templateID := c.Param("id")
+
+	var template models.RoleTemplate
+	if err := rtc.DB.WithContext(c).First(&template, templateID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeRoleTemplateNotFound, "Role template not found", "Failed to retrieve role template")
+		return
+	}
+
+	if err := rtc.DB.WithContext(c).Delete(&template).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to remove role template", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Role template removed successfully", nil)
+}
+
+// applyRoleTemplateRequest validates req and copies it onto template, responding with the error and
+// returning false when it is invalid
+func (rtc *RoleTemplateController) applyRoleTemplateRequest(c *gin.Context, template *models.RoleTemplate, req RoleTemplateRequest) bool {
+	req.Name = strings.ToLower(strings.TrimSpace(req.Name))
+
+	var existing models.RoleTemplate
+	if err := rtc.DB.WithContext(c).Where("name = ? AND id != ?", req.Name, template.ID).First(&existing).Error; err == nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDuplicateName, "Role template name already exists", "a role template with this name already exists")
+		return false
+	}
+
+	// Only roles the current user may assign can be handed out through a template
+	targetRoleLevel, exists := models.GetRoleHierarchy()[req.Role]
+	if !exists {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidRole, "Invalid role specified", "role not found")
+		return false
+	}
+	if c.GetInt("role_level") < targetRoleLevel {
+		utilities.ErrorResponseCode(c, http.StatusForbidden, utilities.ErrCodeInsufficientAccess, "Insufficient permissions to assign this role", "permission denied")
+		return false
+	}
+
+	var role models.Role
+	if err := rtc.DB.WithContext(c).Where("name = ?", req.Role).First(&role).Error; err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeRoleNotFound, "Role not found", err.Error())
+		return false
+	}
+
+	if req.ZoneID != nil {
+		var zone models.Zone
+		if err := rtc.DB.WithContext(c).First(&zone, *req.ZoneID).Error; err != nil {
+			utilities.LookupErrorResponse(c, err, utilities.ErrCodeZoneNotFound, "Zone not found", "Failed to retrieve zone")
+			return false
+		}
+	}
+
+	template.Name = req.Name
+	template.Description = req.Description
+	template.RoleID = role.ID
+	template.ZoneID = req.ZoneID
+	if req.IsActive != nil {
+		template.IsActive = *req.IsActive
+	}
+	return true
+}
+
+// Request/Response structs
+type RoleTemplateRequest struct {
+	Name        string `json:"name" binding:"required,max=50" example:"seasonal-picker"`
+	Description string `json:"description" example:"Seasonal pickers for the sale period"`
+	Role        string `json:"role" binding:"required" example:"picker"`
+	ZoneID      *uint  `json:"zone_id" example:"1"`      // Zone users created from the template are assigned to, none when left out
+	IsActive    *bool  `json:"is_active" example:"true"` // Whether users start active, true when left out on create
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"fmt"
 	"livo-backend/models"
 	"livo-backend/utilities"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type StoreController struct {
@@ -30,7 +32,10 @@ func NewStoreController(db *gorm.DB) *StoreController {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param search query string false "Search by Code or Name (partial match)"
+// @Param sort_by query string false "Sort keys, comma separated, each optionally suffixed with :asc or :desc (created_at, code, name)"
+// @Param sort_dir query string false "Direction of sort keys without a suffix: asc or desc" default(asc)
 // @Success 200 {object} utilities.Response{data=StoresListResponse}
+// @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Router /api/stores [get]
@@ -47,21 +52,27 @@ func (sc *StoreController) GetStores(c *gin.Context) {
 	var total int64
 
 	// Build query with optional search
-	query := sc.DB.Model(&models.Store{})
+	query := sc.DB.WithContext(c).Model(&models.Store{})
 
 	if search != "" {
 		// Search by Code or Name with partial match
 		query = query.Where("code ILIKE ? OR name ILIKE ?", "%"+search+"%", "%"+search+"%")
 	}
 
+	sortScope, err := utilities.ApplySort(c, models.StoreSortKeys, "", "stores.id")
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid sort", err.Error())
+		return
+	}
+
 	// Get total count with search filter
 	if err := query.Count(&total).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count stores", err.Error())
 		return
 	}
 
-	// Get stores with pagination, search filter, and order by ID ascending
-	if err := query.Order("id ASC").Limit(limit).Offset(offset).Find(&stores).Error; err != nil {
+	// Get stores with pagination, search filter, and sorting
+	if err := query.Scopes(sortScope).Limit(limit).Offset(offset).Find(&stores).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve stores", err.Error())
 		return
 	}
@@ -82,9 +93,9 @@ func (sc *StoreController) GetStores(c *gin.Context) {
 	}
 
 	// Build success message
-	message := "Stores retrieved successfully"
+	message := utilities.Messagef(c, "Stores retrieved successfully")
 	if search != "" {
-		message += " (filtered by code or name: " + search + ")"
+		message += utilities.Messagef(c, " (filtered by code or name: %s)", search)
 	}
 
 	utilities.SuccessResponse(c, http.StatusOK, message, response)
@@ -107,8 +118,8 @@ func (sc *StoreController) GetStore(c *gin.Context) {
 	storeID := c.Param("id")
 
 	var store models.Store
-	if err := sc.DB.First(&store, storeID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Store not found", err.Error())
+	if err := sc.DB.WithContext(c).First(&store, storeID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeStoreNotFound, "Store not found", "Failed to retrieve store")
 		return
 	}
 
@@ -140,15 +151,15 @@ func (sc *StoreController) UpdateStore(c *gin.Context) {
 	}
 
 	var store models.Store
-	if err := sc.DB.First(&store, storeID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Store not found", err.Error())
+	if err := sc.DB.WithContext(c).First(&store, storeID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeStoreNotFound, "Store not found", "Failed to retrieve store")
 		return
 	}
 
 	// Check for duplicate store code (excluding current store)
 	var existingStore models.Store
-	if err := sc.DB.Where("code = ? AND id <> ?", req.Code, store.ID).First(&existingStore).Error; err == nil {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Store code already exists", "A store with this code already exists")
+	if err := sc.DB.WithContext(c).Where("code = ? AND id <> ?", req.Code, store.ID).First(&existingStore).Error; err == nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDuplicateCode, "Store code already exists", "A store with this code already exists")
 		return
 	}
 
@@ -156,7 +167,7 @@ func (sc *StoreController) UpdateStore(c *gin.Context) {
 	store.Code = req.Code
 	store.Name = req.Name
 
-	if err := sc.DB.Save(&store).Error; err != nil {
+	if err := sc.DB.WithContext(c).Save(&store).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update store", err.Error())
 		return
 	}
@@ -166,7 +177,7 @@ func (sc *StoreController) UpdateStore(c *gin.Context) {
 
 // RemoveStore godoc
 // @Summary Remove store
-// @Description Soft delete a store.
+// @Description Soft delete a store. Stores referenced by orders or complains of the last MASTER_DELETE_GUARD_DAYS days cannot be deleted. Deleted stores are listed in the trash and can be restored.
 // @Tags stores
 // @Accept json
 // @Produce json
@@ -176,17 +187,29 @@ func (sc *StoreController) UpdateStore(c *gin.Context) {
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
 // @Router /api/stores/{id} [delete]
 func (sc *StoreController) RemoveStore(c *gin.Context) {
 	storeID := c.Param("id")
 
 	var store models.Store
-	if err := sc.DB.First(&store, storeID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Store not found", err.Error())
+	if err := sc.DB.WithContext(c).First(&store, storeID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeStoreNotFound, "Store not found", "Failed to retrieve store")
+		return
+	}
+
+	// Keep stores that recent transactions still point to
+	references, err := store.RecentReferences(sc.DB.WithContext(c))
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to check store references", err.Error())
+		return
+	}
+	if len(references) > 0 {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeMasterInUse, "Store is still in use", models.DescribeMasterReferences(references))
 		return
 	}
 
-	if err := sc.DB.Delete(&store).Error; err != nil {
+	if err := sc.DB.WithContext(c).Delete(&store).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to remove store", err.Error())
 		return
 	}
@@ -194,6 +217,105 @@ func (sc *StoreController) RemoveStore(c *gin.Context) {
 	utilities.SuccessResponse(c, http.StatusOK, "Store removed successfully", nil)
 }
 
+// GetStoreTrash godoc
+// @Summary Get deleted stores
+// @Description Get the soft deleted stores, most recently deleted first.
+// @Tags stores
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param search query string false "Search by Code or Name (partial match)"
+// @Success 200 {object} utilities.Response{data=StoresListResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/stores/trash [get]
+func (sc *StoreController) GetStoreTrash(c *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	search := c.Query("search")
+
+	query := sc.DB.WithContext(c).Unscoped().Model(&models.Store{}).Where("deleted_at IS NOT NULL")
+	if search != "" {
+		query = query.Where("code ILIKE ? OR name ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count deleted stores", err.Error())
+		return
+	}
+
+	var stores []models.Store
+	if err := query.Order("deleted_at DESC, id DESC").Limit(limit).Offset(offset).Find(&stores).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve deleted stores", err.Error())
+		return
+	}
+
+	storeResponses := make([]models.StoreResponse, len(stores))
+	for i := range stores {
+		storeResponses[i] = stores[i].ToStoreResponse()
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Deleted stores retrieved successfully", StoresListResponse{
+		Stores: storeResponses,
+		Pagination: utilities.PaginationResponse{
+			Page:  page,
+			Limit: limit,
+			Total: int(total),
+		},
+	})
+}
+
+// RestoreStore godoc
+// @Summary Restore a deleted store
+// @Description Restore a soft deleted store from the trash.
+// @Tags stores
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Store ID"
+// @Success 200 {object} utilities.Response{data=models.StoreResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/stores/{id}/restore [post]
+func (sc *StoreController) RestoreStore(c *gin.Context) {
+	storeID := c.Param("id")
+
+	var store models.Store
+	err := utilities.WithTransaction(sc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Find the store, deleted or not, locked until commit
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&store, storeID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utilities.NewStatusErrorCode(http.StatusNotFound, utilities.ErrCodeStoreNotFound, "Store not found", "no store found with the specified ID")
+			}
+			return err
+		}
+
+		if !store.DeletedAt.Valid {
+			return utilities.NewStatusErrorCode(http.StatusConflict, utilities.ErrCodeStateInvalid, "Store is not deleted", "only deleted stores can be restored")
+		}
+
+		if err := tx.Unscoped().Model(&store).Update("deleted_at", nil).Error; err != nil {
+			return fmt.Errorf("failed to restore store: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to restore store")
+		return
+	}
+
+	store.DeletedAt = gorm.DeletedAt{}
+	utilities.SuccessResponse(c, http.StatusOK, "Store restored successfully", store.ToStoreResponse())
+}
+
 // CreateStore godoc
 // @Summary Create new store
 // @Description Create a new store.
@@ -223,13 +345,13 @@ func (sc *StoreController) CreateStore(c *gin.Context) {
 	}
 	// Check for duplicate store code
 	var existingStore models.Store
-	if err := sc.DB.Where("code = ?", req.Code).First(&existingStore).Error; err == nil {
-		utilities.ErrorResponse(c, http.StatusBadRequest, "Store code already exists", "A store with this code already exists")
+	if err := sc.DB.WithContext(c).Where("code = ?", req.Code).First(&existingStore).Error; err == nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDuplicateCode, "Store code already exists", "A store with this code already exists")
 		return
 	}
 
 	// Create new store and return response
-	if err := sc.DB.Create(&store).Error; err != nil {
+	if err := sc.DB.WithContext(c).Create(&store).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create store", err.Error())
 		return
 	}
@@ -237,6 +359,77 @@ func (sc *StoreController) CreateStore(c *gin.Context) {
 	utilities.SuccessResponse(c, http.StatusCreated, "Store created successfully", store.ToStoreResponse())
 }
 
+// GetStoreSettings godoc
+// @Summary Get store settings
+// @Description Get the settings of a store: the label header, the complain SLA, the default courier and the document footer. Empty settings keep the global behaviour.
+// @Tags stores
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Store ID"
+// @Success 200 {object} utilities.Response{data=models.StoreSettings}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/stores/{id}/settings [get]
+func (sc *StoreController) GetStoreSettings(c *gin.Context) {
+	storeID := c.Param("id")
+
+	var store models.Store
+	if err := sc.DB.WithContext(c).First(&store, storeID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeStoreNotFound, "Store not found", "Failed to retrieve store")
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Store settings retrieved successfully", store.GetSettings())
+}
+
+// UpdateStoreSettings godoc
+// @Summary Update store settings
+// @Description Replace the settings of a store. The label header is printed at the top of its shipping labels instead of the store name, the complain SLA (hours) replaces the global one for new complains of the store the default courier is given to its orders arriving without one and the document footer closes its complain reports.
+// @Tags stores
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Store ID"
+// @Param settings body models.StoreSettings true "Store settings"
+// @Success 200 {object} utilities.Response{data=models.StoreSettings}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/stores/{id}/settings [put]
+func (sc *StoreController) UpdateStoreSettings(c *gin.Context) {
+	storeID := c.Param("id")
+
+	var settings models.StoreSettings
+	if err := c.ShouldBindJSON(&settings); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	var store models.Store
+	if err := sc.DB.WithContext(c).First(&store, storeID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeStoreNotFound, "Store not found", "Failed to retrieve store")
+		return
+	}
+
+	settings.LabelHeader = strings.TrimSpace(settings.LabelHeader)
+	settings.DefaultCourier = strings.TrimSpace(settings.DefaultCourier)
+	settings.DocumentFooter = strings.TrimSpace(settings.DocumentFooter)
+
+	if err := store.SetSettings(settings); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update store settings", err.Error())
+		return
+	}
+	if err := sc.DB.WithContext(c).Model(&store).Update("settings", store.Settings).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update store settings", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Store settings updated successfully", store.GetSettings())
+}
+
 // Request/Response structs
 type StoresListResponse struct {
 	Stores     []models.StoreResponse       `json:"stores"`
@@ -1,9 +1,12 @@
 package controllers
 
 import (
+	"errors"
 	"livo-backend/models"
 	"livo-backend/utilities"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -27,13 +30,14 @@ func NewUserController(db *gorm.DB) *UserController {
 // @Security BearerAuth
 // @Success 200 {object} utilities.Response{data=models.UserResponse}
 // @Failure 401 {object} utilities.Response
+// @Router /api/me [get]
 // @Router /api/user/profile [get]
 func (uc *UserController) GetProfile(c *gin.Context) {
 	userID := c.GetUint("user_id")
 
 	var user models.User
-	if err := uc.DB.Preload("UserRoles.Role").First(&user, userID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "User not found", err.Error())
+	if err := uc.DB.WithContext(c).Preload("UserRoles.Role").First(&user, userID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeUserNotFound, "User not found", "Failed to retrieve user")
 		return
 	}
 
@@ -42,7 +46,7 @@ func (uc *UserController) GetProfile(c *gin.Context) {
 
 // UpdateProfile godoc
 // @Summary Update user profile
-// @Description Update current user's profile information
+// @Description Update current user's full name and email. Use PUT /api/me/password to change the password.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -51,6 +55,8 @@ func (uc *UserController) GetProfile(c *gin.Context) {
 // @Success 200 {object} utilities.Response{data=models.UserResponse}
 // @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/me [put]
 // @Router /api/user/profile [put]
 func (uc *UserController) UpdateProfile(c *gin.Context) {
 	userID := c.GetUint("user_id")
@@ -62,8 +68,8 @@ func (uc *UserController) UpdateProfile(c *gin.Context) {
 	}
 
 	var user models.User
-	if err := uc.DB.First(&user, userID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "User not found", err.Error())
+	if err := uc.DB.WithContext(c).First(&user, userID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeUserNotFound, "User not found", "Failed to retrieve user")
 		return
 	}
 
@@ -74,37 +80,201 @@ func (uc *UserController) UpdateProfile(c *gin.Context) {
 	if req.Email != "" {
 		// Check if email is already taken by another user
 		var existingUser models.User
-		if err := uc.DB.Where("email = ? AND id != ?", req.Email, userID).First(&existingUser).Error; err == nil {
-			utilities.ErrorResponse(c, http.StatusConflict, "Email already taken", "email already exists")
+		if err := uc.DB.WithContext(c).Where("email = ? AND id != ?", req.Email, userID).First(&existingUser).Error; err == nil {
+			utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeEmailTaken, "Email already taken", "email already exists")
 			return
 		}
 		user.Email = req.Email
 	}
 
-	// Update password if provided
-	if req.Password != "" {
-		hashedPassword, err := utilities.HashPassword(req.Password)
-		if err != nil {
-			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to hash password", err.Error())
-			return
-		}
-		user.Password = hashedPassword
-	}
-
-	if err := uc.DB.Save(&user).Error; err != nil {
+	if err := uc.DB.WithContext(c).Save(&user).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update profile", err.Error())
 		return
 	}
 
 	// Load user with roles
-	uc.DB.Preload("UserRoles.Role").Preload("UserRoles.Assigner").First(&user, user.ID)
+	uc.DB.WithContext(c).Preload("UserRoles.Role").Preload("UserRoles.Assigner").First(&user, user.ID)
 
 	utilities.SuccessResponse(c, http.StatusOK, "Profile updated successfully", user.ToUserResponse())
 }
 
+// ChangePassword godoc
+// @Summary Change own password
+// @Description Change current user's password. The current password is required and every session of the user is logged out afterwards.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ChangePasswordRequest true "Change password request"
+// @Success 200 {object} utilities.Response
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Router /api/me/password [put]
+func (uc *UserController) ChangePassword(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	var user models.User
+	if err := uc.DB.WithContext(c).First(&user, userID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeUserNotFound, "User not found", "Failed to retrieve user")
+		return
+	}
+
+	// Check current password
+	if !utilities.CheckPasswordHash(req.CurrentPassword, user.Password) {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidCredentials, "Current password is incorrect", "incorrect password")
+		return
+	}
+
+	if req.CurrentPassword == req.NewPassword {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodePasswordReused, "New password must be different from the current password", "password unchanged")
+		return
+	}
+	if err := utilities.CurrentSettings().PasswordRules.Validate(req.NewPassword); err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodePasswordWeak, "Password does not meet the policy", err.Error())
+		return
+	}
+
+	hashedPassword, err := utilities.HashPassword(req.NewPassword)
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to hash password", err.Error())
+		return
+	}
+
+	// Clear the refresh token and revoke issued tokens so every device has to login again
+	now := time.Now()
+	user.Password = hashedPassword
+	user.RefreshToken = ""
+	user.SessionsRevokedAt = &now
+	if err := uc.DB.WithContext(c).Save(&user).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to change password", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Password changed successfully, please login again", nil)
+}
+
+// GetMySessions godoc
+// @Summary Get my sessions
+// @Description List the devices logged in as the current user: device name sent at login, user agent, IP and last activity, most recently seen first. The session of the calling token is marked current.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utilities.Response{data=[]models.SessionResponse}
+// @Failure 401 {object} utilities.Response
+// @Router /api/me/sessions [get]
+func (uc *UserController) GetMySessions(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var user models.User
+	if err := uc.DB.WithContext(c).First(&user, userID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeUserNotFound, "User not found", "Failed to retrieve user")
+		return
+	}
+
+	sessions, err := models.ActiveSessions(uc.DB.WithContext(c), &user, time.Now())
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve sessions", err.Error())
+		return
+	}
+
+	currentID := c.GetUint("session_id")
+	responses := make([]models.SessionResponse, len(sessions))
+	for i := range sessions {
+		responses[i] = sessions[i].ToSessionResponse(currentID)
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Sessions retrieved successfully", responses)
+}
+
+// RevokeMySession godoc
+// @Summary Revoke one of my sessions
+// @Description Log a device of the current user out. Its refresh token stops working at once and its access token is rejected on the next request. Revoking the current session is the same as logging out.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Session ID"
+// @Success 200 {object} utilities.Response
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/me/sessions/{id} [delete]
+func (uc *UserController) RevokeMySession(c *gin.Context) {
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid session ID", "id must be a number")
+		return
+	}
+
+	if err := models.RevokeSession(uc.DB.WithContext(c), c.GetUint("user_id"), uint(sessionID), time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utilities.ErrorResponseCode(c, http.StatusNotFound, utilities.ErrCodeSessionNotFound, "Session not found", "no active session with this ID")
+			return
+		}
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to revoke session", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Session revoked successfully", nil)
+}
+
+// GetMyQuotas godoc
+// @Summary Get my request quotas
+// @Description Get today's usage of the current user's daily request quotas (reports, exports, bulk imports). Limit and remaining are -1 for unlimited operations.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utilities.Response{data=[]models.QuotaUsageResponse}
+// @Failure 401 {object} utilities.Response
+// @Router /api/me/quotas [get]
+func (uc *UserController) GetMyQuotas(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	roles, _ := c.Get("roles")
+	userRoles, _ := roles.([]string)
+
+	day, resetsAt := models.QuotaDay(time.Now())
+
+	quotas := make([]models.QuotaUsageResponse, len(models.QuotaOperations))
+	for i, operation := range models.QuotaOperations {
+		used, err := models.QuotaUsed(uc.DB.WithContext(c), userID, operation, day)
+		if err != nil {
+			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve quotas", err.Error())
+			return
+		}
+
+		quota := models.QuotaUsageResponse{
+			Operation: operation,
+			Used:      used,
+			Limit:     -1,
+			Remaining: -1,
+			ResetsAt:  resetsAt.Format("2006-01-02 15:04:05"),
+		}
+		if limit, limited := models.CurrentSettings().RequestQuotas[operation].LimitFor(userRoles); limited {
+			quota.Limit = limit
+			quota.Remaining = max(limit-used, 0)
+		}
+		quotas[i] = quota
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Quotas retrieved successfully", quotas)
+}
+
 // UpdateProfileRequest represents the update profile request
 type UpdateProfileRequest struct {
 	FullName string `json:"full_name,omitempty" example:"John Doe"`
-	Email    string `json:"email,omitempty" example:"john@example.com"`
-	Password string `json:"password,omitempty" example:"newpassword123"`
+	Email    string `json:"email,omitempty" binding:"omitempty,email" example:"john@example.com"`
+}
+
+// ChangePasswordRequest represents the change password request
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required" example:"oldpassword123"`
+	NewPassword     string `json:"new_password" binding:"required" example:"newpassword123"`
 }
@@ -1,11 +1,17 @@
 package controllers
 
 import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
 	"livo-backend/models"
 	"livo-backend/utilities"
 	"net/http"
+	"net/mail"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -30,7 +36,10 @@ func NewUserManagerController(db *gorm.DB) *UserManagerController {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param search query string false "Search by username or full name"
+// @Param sort_by query string false "Sort keys, comma separated, each optionally suffixed with :asc or :desc (created_at, username, full_name, status)"
+// @Param sort_dir query string false "Direction of sort keys without a suffix: asc or desc" default(asc)
 // @Success 200 {object} utilities.Response{data=UsersListResponse}
+// @Failure 400 {object} utilities.Response
 // @Failure 401 {object} utilities.Response
 // @Failure 403 {object} utilities.Response
 // @Router /api/user-manager/users [get]
@@ -47,7 +56,7 @@ func (umc *UserManagerController) GetUsers(c *gin.Context) {
 	var total int64
 
 	// Build base query
-	query := umc.DB.Model(&models.User{})
+	query := umc.DB.WithContext(c).Model(&models.User{})
 
 	// Add search conditions if search parameter is provided
 	if search != "" {
@@ -56,14 +65,20 @@ func (umc *UserManagerController) GetUsers(c *gin.Context) {
 		query = query.Where(searchCondition, searchPattern, searchPattern)
 	}
 
+	sortScope, err := utilities.ApplySort(c, models.UserSortKeys, "", "users.id")
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid sort", err.Error())
+		return
+	}
+
 	// Get total count
 	if err := query.Count(&total).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to count users", err.Error())
 		return
 	}
 
-	// Get users with pagination and order by ID ascending
-	if err := query.Order("id ASC").Preload("UserRoles.Role").Preload("UserRoles.Assigner").Limit(limit).Offset(offset).Find(&users).Error; err != nil {
+	// Get users with pagination and sorting
+	if err := query.Scopes(sortScope).Preload("UserRoles.Role").Preload("UserRoles.Assigner").Limit(limit).Offset(offset).Find(&users).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve users", err.Error())
 		return
 	}
@@ -103,8 +118,8 @@ func (umc *UserManagerController) GetUser(c *gin.Context) {
 	userID := c.Param("id")
 
 	var user models.User
-	if err := umc.DB.Preload("UserRoles.Role").First(&user, userID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "User not found", err.Error())
+	if err := umc.DB.WithContext(c).Preload("UserRoles.Role").First(&user, userID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeUserNotFound, "User not found", "Failed to retrieve user")
 		return
 	}
 
@@ -136,19 +151,23 @@ func (umc *UserManagerController) UpdateUserStatus(c *gin.Context) {
 	}
 
 	var user models.User
-	if err := umc.DB.First(&user, userID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "User not found", err.Error())
+	if err := umc.DB.WithContext(c).First(&user, userID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeUserNotFound, "User not found", "Failed to retrieve user")
 		return
 	}
 
 	user.IsActive = req.IsActive
-	if err := umc.DB.Save(&user).Error; err != nil {
+	// Deactivating a user also ends their sessions
+	if !req.IsActive {
+		user.TokenVersion++
+	}
+	if err := umc.DB.WithContext(c).Save(&user).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update user status", err.Error())
 		return
 	}
 
 	// Load user with roles
-	umc.DB.Preload("UserRoles.Role").First(&user, user.ID)
+	umc.DB.WithContext(c).Preload("UserRoles.Role").First(&user, user.ID)
 
 	utilities.SuccessResponse(c, http.StatusOK, "User status updated successfully", user.ToUserResponse())
 }
@@ -179,50 +198,41 @@ func (umc *UserManagerController) AssignRole(c *gin.Context) {
 
 	// Find target user
 	var user models.User
-	if err := umc.DB.Preload("UserRoles.Role").First(&user, userID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "User not found", err.Error())
+	if err := umc.DB.WithContext(c).Preload("UserRoles.Role").First(&user, userID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeUserNotFound, "User not found", "Failed to retrieve user")
 		return
 	}
 
 	// Find role
 	var role models.Role
-	if err := umc.DB.Where("name = ?", req.RoleName).First(&role).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Role not found", err.Error())
+	if err := umc.DB.WithContext(c).Where("name = ?", req.RoleName).First(&role).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeRoleNotFound, "Role not found", "Failed to retrieve role")
 		return
 	}
 
 	// Check if user already has this role
 	for _, userRole := range user.UserRoles {
 		if userRole.RoleID == role.ID {
-			utilities.ErrorResponse(c, http.StatusConflict, "User already has this role", "role already assigned")
+			utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeRoleAlreadyAssigned, "User already has this role", "role already assigned")
 			return
 		}
 	}
 
-	// Check permission hierarchy (get current user's roles)
-	currentUserRoles, _ := c.Get("roles")
-	currentRoles := currentUserRoles.([]string)
-
-	// Get current user's highest role level
+	// Check permission hierarchy (current user's level comes from the token)
 	hierarchy := models.GetRoleHierarchy()
-	currentMaxLevel := 0
-	for _, roleName := range currentRoles {
-		if level, exists := hierarchy[roleName]; exists && level > currentMaxLevel {
-			currentMaxLevel = level
-		}
-	}
+	currentMaxLevel := c.GetInt("role_level")
 
 	// Check if current user can assign this role
 	targetRoleLevel, exists := hierarchy[req.RoleName]
 	if !exists || currentMaxLevel < targetRoleLevel {
-		utilities.ErrorResponse(c, http.StatusForbidden, "Insufficient permissions to assign this role", "permission denied")
+		utilities.ErrorResponseCode(c, http.StatusForbidden, utilities.ErrCodeInsufficientAccess, "Insufficient permissions to assign this role", "permission denied")
 		return
 	}
 
 	// Get current user ID from context
 	currentUserID, exists := c.Get("user_id")
 	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated", "user_id not found in context")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeAuthRequired, "User not authenticated", "user_id not found in context")
 		return
 	}
 
@@ -233,13 +243,19 @@ func (umc *UserManagerController) AssignRole(c *gin.Context) {
 		AssignedBy: currentUserID.(uint),
 	}
 
-	if err := umc.DB.Create(&userRole).Error; err != nil {
+	if err := umc.DB.WithContext(c).Create(&userRole).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to assign role", err.Error())
 		return
 	}
 
+	// Tokens carrying the old roles stop working
+	if err := models.BumpTokenVersion(umc.DB.WithContext(c), user.ID); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to invalidate user tokens", err.Error())
+		return
+	}
+
 	// Reload user with updated roles
-	umc.DB.Preload("UserRoles.Role").First(&user, user.ID)
+	umc.DB.WithContext(c).Preload("UserRoles.Role").First(&user, user.ID)
 
 	utilities.SuccessResponse(c, http.StatusOK, "Role assigned successfully", user.ToUserResponse())
 }
@@ -270,45 +286,43 @@ func (umc *UserManagerController) RemoveRole(c *gin.Context) {
 
 	// Find role
 	var role models.Role
-	if err := umc.DB.Where("name = ?", req.RoleName).First(&role).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "Role not found", err.Error())
+	if err := umc.DB.WithContext(c).Where("name = ?", req.RoleName).First(&role).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeRoleNotFound, "Role not found", "Failed to retrieve role")
 		return
 	}
 
 	// Check permission hierarchy
-	currentUserRoles, _ := c.Get("roles")
-	currentRoles := currentUserRoles.([]string)
-
 	hierarchy := models.GetRoleHierarchy()
-	currentMaxLevel := 0
-	for _, roleName := range currentRoles {
-		if level, exists := hierarchy[roleName]; exists && level > currentMaxLevel {
-			currentMaxLevel = level
-		}
-	}
+	currentMaxLevel := c.GetInt("role_level")
 
 	targetRoleLevel, exists := hierarchy[req.RoleName]
 	if !exists || currentMaxLevel < targetRoleLevel {
-		utilities.ErrorResponse(c, http.StatusForbidden, "Insufficient permissions to remove this role", "permission denied")
+		utilities.ErrorResponseCode(c, http.StatusForbidden, utilities.ErrCodeInsufficientAccess, "Insufficient permissions to remove this role", "permission denied")
 		return
 	}
 
 	// Remove role
-	if err := umc.DB.Where("user_id = ? AND role_id = ?", userID, role.ID).Delete(&models.UserRole{}).Error; err != nil {
+	if err := umc.DB.WithContext(c).Where("user_id = ? AND role_id = ?", userID, role.ID).Delete(&models.UserRole{}).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to remove role", err.Error())
 		return
 	}
 
+	// Tokens carrying the old roles stop working
+	if err := models.BumpTokenVersion(umc.DB.WithContext(c), userID); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to invalidate user tokens", err.Error())
+		return
+	}
+
 	// Reload user with updated roles
 	var user models.User
-	umc.DB.Preload("UserRoles.Role").Preload("UserRoles.Assigner").First(&user, userID)
+	umc.DB.WithContext(c).Preload("UserRoles.Role").Preload("UserRoles.Assigner").First(&user, userID)
 
 	utilities.SuccessResponse(c, http.StatusOK, "Role removed successfully", user.ToUserResponse())
 }
 
 // CreateUser godoc
 // @Summary Create a new user
-// @Description Create a new user account.
+// @Description Create a new user account. The password must meet the configured password policy.
 // @Tags user-manager
 // @Accept json
 // @Produce json
@@ -326,11 +340,15 @@ func (umc *UserManagerController) CreateUser(c *gin.Context) {
 		utilities.ValidationErrorResponse(c, err)
 		return
 	}
+	if err := utilities.CurrentSettings().PasswordRules.Validate(req.Password); err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodePasswordWeak, "Password does not meet the policy", err.Error())
+		return
+	}
 
 	// Check if user already exists
 	var existingUser models.User
-	if err := umc.DB.Where("username = ? OR email = ?", req.Username, req.Email).First(&existingUser).Error; err == nil {
-		utilities.ErrorResponse(c, http.StatusConflict, "User already exists", "username or email already taken")
+	if err := umc.DB.WithContext(c).Where("username = ? OR email = ?", req.Username, req.Email).First(&existingUser).Error; err == nil {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeUserExists, "User already exists", "username or email already taken")
 		return
 	}
 
@@ -344,56 +362,56 @@ func (umc *UserManagerController) CreateUser(c *gin.Context) {
 	// Get current user ID for audit trail
 	currentUserID, exists := c.Get("user_id")
 	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated", "user_id not found in context")
-		return
-	}
-
-	// Create user
-	user := models.User{
-		Username: req.Username,
-		Email:    req.Email,
-		Password: hashedPassword,
-		FullName: req.FullName,
-		IsActive: req.IsActive,
-	}
-
-	if err := umc.DB.Create(&user).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create user", err.Error())
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeAuthRequired, "User not authenticated", "user_id not found in context")
 		return
 	}
 
-	// Assign initial role if specified
+	// Resolve the role to assign before creating anything
+	var role models.Role
 	if req.InitialRole != "" {
 		// Check permission hierarchy for role assignment
-		currentUserRoles, _ := c.Get("roles")
-		currentRoles := currentUserRoles.([]string)
-
 		hierarchy := models.GetRoleHierarchy()
-		currentMaxLevel := 0
-		for _, roleName := range currentRoles {
-			if level, exists := hierarchy[roleName]; exists && level > currentMaxLevel {
-				currentMaxLevel = level
-			}
-		}
+		currentMaxLevel := c.GetInt("role_level")
 
 		// Check if current user can assign this role
 		targetRoleLevel, exists := hierarchy[req.InitialRole]
 		if !exists {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Invalid role specified", "role not found")
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidRole, "Invalid role specified", "role not found")
 			return
 		}
 
 		if currentMaxLevel < targetRoleLevel {
-			utilities.ErrorResponse(c, http.StatusForbidden, "Insufficient permissions to assign this role", "permission denied")
+			utilities.ErrorResponseCode(c, http.StatusForbidden, utilities.ErrCodeInsufficientAccess, "Insufficient permissions to assign this role", "permission denied")
 			return
 		}
 
-		// Find and assign the role
-		var role models.Role
-		if err := umc.DB.Where("name = ?", req.InitialRole).First(&role).Error; err != nil {
-			utilities.ErrorResponse(c, http.StatusBadRequest, "Role not found", err.Error())
+		// Find the role
+		if err := umc.DB.WithContext(c).Where("name = ?", req.InitialRole).First(&role).Error; err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeRoleNotFound, "Role not found", err.Error())
 			return
 		}
+	} else {
+		// Assign guest role by default
+		umc.DB.WithContext(c).Where("name = ?", "guest").First(&role)
+	}
+
+	// Create user
+	user := models.User{
+		Username: req.Username,
+		Email:    req.Email,
+		Password: hashedPassword,
+		FullName: req.FullName,
+		IsActive: req.IsActive,
+	}
+
+	err = utilities.WithTransaction(umc.DB.WithContext(c), func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+
+		if role.ID == 0 {
+			return nil
+		}
 
 		userRole := models.UserRole{
 			UserID:     user.ID,
@@ -401,29 +419,264 @@ func (umc *UserManagerController) CreateUser(c *gin.Context) {
 			AssignedBy: currentUserID.(uint),
 		}
 
-		if err := umc.DB.Create(&userRole).Error; err != nil {
-			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to assign role", err.Error())
-			return
-		}
-	} else {
-		// Assign guest role by default
-		var guestRole models.Role
-		if err := umc.DB.Where("name = ?", "guest").First(&guestRole).Error; err == nil {
-			userRole := models.UserRole{
-				UserID:     user.ID,
-				RoleID:     guestRole.ID,
-				AssignedBy: currentUserID.(uint),
-			}
-			umc.DB.Create(&userRole)
+		if err := tx.Create(&userRole).Error; err != nil {
+			return fmt.Errorf("failed to assign role: %w", err)
 		}
+
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to create user")
+		return
 	}
 
 	// Load user with roles
-	umc.DB.Preload("UserRoles.Role").Preload("UserRoles.Assigner").First(&user, user.ID)
+	umc.DB.WithContext(c).Preload("UserRoles.Role").Preload("UserRoles.Assigner").First(&user, user.ID)
 
 	utilities.SuccessResponse(c, http.StatusCreated, "User created successfully", user.ToUserResponse())
 }
 
+// maxBulkUsers is the most users a bulk import creates at once
+const maxBulkUsers = 200
+
+// BulkCreateUsers godoc
+// @Summary Bulk import users
+// @Description Create several users at once from a JSON body, a CSV body (Content-Type text/csv) or an uploaded CSV file (multipart field "file"). CSV files have a header row with the columns username, full_name, email and template. Every user gets the role, zone and active flag of their role template (or of the template query parameter when the row has none) and a generated initial password, returned once in the per-row results. Users without an email get a placeholder one. Rows fail on their own, the others are still created.
+// @Tags user-manager
+// @Accept json
+// @Accept text/csv
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param request body BulkCreateUsersRequest false "Bulk create users request"
+// @Param template query string false "Role template of rows without one"
+// @Param file formData file false "CSV file of users"
+// @Success 201 {object} utilities.Response{data=BulkCreateUsersResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/user-manager/users/bulk [post]
+func (umc *UserManagerController) BulkCreateUsers(c *gin.Context) {
+	var req BulkCreateUsersRequest
+	switch c.ContentType() {
+	case "text/csv":
+		rows, err := parseBulkUsersCSV(c.Request.Body)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidFile, "Invalid CSV file", err.Error())
+			return
+		}
+		req.Users = rows
+	case "multipart/form-data":
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidFile, "CSV file is required", err.Error())
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidFile, "Invalid CSV file", err.Error())
+			return
+		}
+		defer file.Close()
+		rows, err := parseBulkUsersCSV(file)
+		if err != nil {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidFile, "Invalid CSV file", err.Error())
+			return
+		}
+		req.Users = rows
+	default:
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utilities.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+	if req.Template == "" {
+		req.Template = c.Query("template")
+	}
+
+	if len(req.Users) == 0 {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeValidationFailed, "No users to import", "the request has no user rows")
+		return
+	}
+	if len(req.Users) > maxBulkUsers {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeValidationFailed, "Too many users", fmt.Sprintf("at most %d users can be imported at once", maxBulkUsers))
+		return
+	}
+
+	response := BulkCreateUsersResponse{Results: make([]BulkUserResult, len(req.Users))}
+	templates := make(map[string]*models.RoleTemplate)
+	for i, row := range req.Users {
+		result := umc.bulkCreateUser(c, row, req.Template, templates)
+		result.Index = i
+		response.Results[i] = result
+		if result.Status == BulkUserCreated {
+			response.Summary.Created++
+		} else {
+			response.Summary.Failed++
+		}
+	}
+	response.Summary.Total = len(req.Users)
+
+	statusCode := http.StatusCreated
+	message := "Bulk user import completed"
+	if response.Summary.Created == 0 {
+		statusCode = http.StatusBadRequest
+		message = "No users could be created"
+	} else if response.Summary.Failed > 0 {
+		message = "Bulk user import completed with some issues"
+	}
+
+	utilities.SuccessResponse(c, statusCode, message, response)
+}
+
+// Statuses of bulk imported user rows
+const (
+	BulkUserCreated = "created"
+	BulkUserFailed  = "failed"
+)
+
+// bulkCreateUser creates the user of one bulk import row from its role template, or
+// defaultTemplate when the row has none. Templates are looked up once per request through
+// templates.
+func (umc *UserManagerController) bulkCreateUser(c *gin.Context, row BulkUserRow, defaultTemplate string, templates map[string]*models.RoleTemplate) BulkUserResult {
+	row.Username = strings.TrimSpace(row.Username)
+	row.FullName = strings.TrimSpace(row.FullName)
+	row.Email = strings.TrimSpace(row.Email)
+	row.Template = strings.ToLower(strings.TrimSpace(row.Template))
+	if row.Template == "" {
+		row.Template = strings.ToLower(strings.TrimSpace(defaultTemplate))
+	}
+
+	result := BulkUserResult{Username: row.Username, Template: row.Template, Status: BulkUserFailed}
+	fail := func(message string) BulkUserResult {
+		result.Error = message
+		return result
+	}
+
+	if length := len([]rune(row.Username)); length < 3 || length > 50 {
+		return fail("username must have 3 to 50 characters")
+	}
+	if row.FullName == "" {
+		return fail("full_name is required")
+	}
+	if row.Template == "" {
+		return fail("template is required")
+	}
+	if row.Email == "" {
+		row.Email = strings.ToLower(row.Username) + "@" + models.CurrentSettings().ImportedUserEmailDomain
+	} else if _, err := mail.ParseAddress(row.Email); err != nil {
+		return fail("email must be a valid email address")
+	}
+
+	template, found := templates[row.Template]
+	if !found {
+		template = &models.RoleTemplate{}
+		if err := umc.DB.WithContext(c).Preload("Role").Where("name = ?", row.Template).First(template).Error; err != nil {
+			template = nil
+		}
+		templates[row.Template] = template
+	}
+	if template == nil || template.Role == nil {
+		return fail(fmt.Sprintf("role template %q not found", row.Template))
+	}
+
+	// Templates may bundle roles the current user is not allowed to assign
+	if targetRoleLevel := models.GetRoleHierarchy()[template.Role.Name]; c.GetInt("role_level") < targetRoleLevel {
+		return fail(fmt.Sprintf("insufficient permissions to assign role %q", template.Role.Name))
+	}
+
+	var existingUser models.User
+	if err := umc.DB.WithContext(c).Where("username = ? OR email = ?", row.Username, row.Email).First(&existingUser).Error; err == nil {
+		return fail("username or email already taken")
+	}
+
+	password, err := utilities.CurrentSettings().PasswordRules.Generate()
+	if err != nil {
+		return fail(fmt.Sprintf("failed to generate password: %v", err))
+	}
+	hashedPassword, err := utilities.HashPassword(password)
+	if err != nil {
+		return fail(fmt.Sprintf("failed to hash password: %v", err))
+	}
+
+	user := models.User{
+		Username: row.Username,
+		Email:    row.Email,
+		Password: hashedPassword,
+		FullName: row.FullName,
+		IsActive: template.IsActive,
+	}
+	err = utilities.WithTransaction(umc.DB.WithContext(c), func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		return models.ApplyRoleTemplate(tx, &user, template, c.GetUint("user_id"))
+	})
+	if err != nil {
+		return fail(err.Error())
+	}
+
+	result.Status = BulkUserCreated
+	result.UserID = user.ID
+	result.Email = user.Email
+	result.Password = password
+	return result
+}
+
+// parseBulkUsersCSV reads bulk import rows from a CSV file with a header row. Columns are matched by
+// name, case-insensitively; unknown columns are ignored.
+func parseBulkUsersCSV(r io.Reader) ([]BulkUserRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("the file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		name = strings.ReplaceAll(name, " ", "_")
+		columns[name] = i
+	}
+	if _, found := columns["username"]; !found {
+		return nil, errors.New("the header row has no username column")
+	}
+
+	value := func(record []string, column string) string {
+		if i, found := columns[column]; found && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	var rows []BulkUserRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		rows = append(rows, BulkUserRow{
+			Username: value(record, "username"),
+			FullName: value(record, "full_name"),
+			Email:    value(record, "email"),
+			Template: value(record, "template"),
+		})
+	}
+	return rows, nil
+}
+
 // DeleteUser godoc
 // @Summary Delete a user
 // @Description Delete a user account.
@@ -442,34 +695,26 @@ func (umc *UserManagerController) DeleteUser(c *gin.Context) {
 
 	// Find user to be deleted
 	var user models.User
-	if err := umc.DB.Preload("UserRoles.Role").First(&user, userID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "User not found", err.Error())
+	if err := umc.DB.WithContext(c).Preload("UserRoles.Role").First(&user, userID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeUserNotFound, "User not found", "Failed to retrieve user")
 		return
 	}
 
 	// Prevent deletion of current user
 	currentUserID, exists := c.Get("user_id")
 	if !exists {
-		utilities.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated", "user_id not found in context")
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeAuthRequired, "User not authenticated", "user_id not found in context")
 		return
 	}
 
 	if user.ID == currentUserID.(uint) {
-		utilities.ErrorResponse(c, http.StatusForbidden, "Cannot delete your own account", "self-deletion not allowed")
+		utilities.ErrorResponseCode(c, http.StatusForbidden, utilities.ErrCodeActionNotAllowed, "Cannot delete your own account", "self-deletion not allowed")
 		return
 	}
 
 	// Check permission hierarchy - can only delete users with lower roles
-	currentUserRoles, _ := c.Get("roles")
-	currentRoles := currentUserRoles.([]string)
-
 	hierarchy := models.GetRoleHierarchy()
-	currentMaxLevel := 0
-	for _, roleName := range currentRoles {
-		if level, exists := hierarchy[roleName]; exists && level > currentMaxLevel {
-			currentMaxLevel = level
-		}
-	}
+	currentMaxLevel := c.GetInt("role_level")
 
 	// Get target user's highest role level
 	targetMaxLevel := 0
@@ -481,30 +726,26 @@ func (umc *UserManagerController) DeleteUser(c *gin.Context) {
 
 	// Check if current user has permission to delete target user
 	if currentMaxLevel <= targetMaxLevel {
-		utilities.ErrorResponse(c, http.StatusForbidden, "Insufficient permissions to delete this user", "permission denied")
+		utilities.ErrorResponseCode(c, http.StatusForbidden, utilities.ErrCodeInsufficientAccess, "Insufficient permissions to delete this user", "permission denied")
 		return
 	}
 
 	// Start transaction to ensure data consistency
-	tx := umc.DB.Begin()
-
-	// Delete all user roles first (due to foreign key constraints)
-	if err := tx.Where("user_id = ?", user.ID).Delete(&models.UserRole{}).Error; err != nil {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete user roles", err.Error())
-		return
-	}
+	err := utilities.WithTransaction(umc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Delete all user roles first (due to foreign key constraints)
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.UserRole{}).Error; err != nil {
+			return fmt.Errorf("failed to delete user roles: %w", err)
+		}
 
-	// Delete the user (soft delete)
-	if err := tx.Delete(&user).Error; err != nil {
-		tx.Rollback()
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete user", err.Error())
-		return
-	}
+		// Delete the user (soft delete)
+		if err := tx.Delete(&user).Error; err != nil {
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction", err.Error())
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to delete user")
 		return
 	}
 
@@ -513,7 +754,7 @@ func (umc *UserManagerController) DeleteUser(c *gin.Context) {
 
 // UpdateUserPassword godoc
 // @Summary Update user password
-// @Description Update a user's password.
+// @Description Update a user's password. The password must meet the configured password policy; setting it also lifts a login lock.
 // @Tags user-manager
 // @Accept json
 // @Produce json
@@ -534,25 +775,21 @@ func (umc *UserManagerController) UpdateUserPassword(c *gin.Context) {
 		utilities.ValidationErrorResponse(c, err)
 		return
 	}
+	if err := utilities.CurrentSettings().PasswordRules.Validate(req.NewPassword); err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodePasswordWeak, "Password does not meet the policy", err.Error())
+		return
+	}
 
 	// Find user to be updated
 	var user models.User
-	if err := umc.DB.Preload("UserRoles.Role").First(&user, userID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "User not found", err.Error())
+	if err := umc.DB.WithContext(c).Preload("UserRoles.Role").First(&user, userID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeUserNotFound, "User not found", "Failed to retrieve user")
 		return
 	}
 
 	// Check permission hierarchy - can only update users with lower or equal roles
-	currentUserRoles, _ := c.Get("roles")
-	currentRoles := currentUserRoles.([]string)
-
 	hierarchy := models.GetRoleHierarchy()
-	currentMaxLevel := 0
-	for _, roleName := range currentRoles {
-		if level, exists := hierarchy[roleName]; exists && level > currentMaxLevel {
-			currentMaxLevel = level
-		}
-	}
+	currentMaxLevel := c.GetInt("role_level")
 
 	// Get target user's highest role level
 	targetMaxLevel := 0
@@ -564,7 +801,7 @@ func (umc *UserManagerController) UpdateUserPassword(c *gin.Context) {
 
 	// Check if current user has permission to update target user
 	if currentMaxLevel < targetMaxLevel {
-		utilities.ErrorResponse(c, http.StatusForbidden, "Insufficient permissions to update this user", "permission denied")
+		utilities.ErrorResponseCode(c, http.StatusForbidden, utilities.ErrCodeInsufficientAccess, "Insufficient permissions to update this user", "permission denied")
 		return
 	}
 
@@ -575,19 +812,25 @@ func (umc *UserManagerController) UpdateUserPassword(c *gin.Context) {
 		return
 	}
 
-	// Update password
+	// Update password and revoke the user's sessions to force re-login on every device. A new
+	// password also lifts a login lock.
 	user.Password = hashedPassword
-	if err := umc.DB.Save(&user).Error; err != nil {
+	user.RefreshToken = ""
+	user.LoginFailures = 0
+	user.LockedUntil = nil
+	err = utilities.WithTransaction(umc.DB.WithContext(c), func(tx *gorm.DB) error {
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+		return models.RevokeUserSessions(tx, user.ID, time.Now())
+	})
+	if err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update password", err.Error())
 		return
 	}
 
-	// Clear refresh token to force re-login
-	user.RefreshToken = ""
-	umc.DB.Save(&user)
-
 	// Load user with roles for response
-	umc.DB.Preload("UserRoles.Role").Preload("UserRoles.Assigner").First(&user, user.ID)
+	umc.DB.WithContext(c).Preload("UserRoles.Role").Preload("UserRoles.Assigner").First(&user, user.ID)
 
 	utilities.SuccessResponse(c, http.StatusOK, "Password updated successfully", user.ToUserResponse())
 }
@@ -619,31 +862,23 @@ func (umc *UserManagerController) UpdateUserProfile(c *gin.Context) {
 
 	// Find user to be updated
 	var user models.User
-	if err := umc.DB.Preload("UserRoles.Role").First(&user, userID).Error; err != nil {
-		utilities.ErrorResponse(c, http.StatusNotFound, "User not found", err.Error())
+	if err := umc.DB.WithContext(c).Preload("UserRoles.Role").First(&user, userID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeUserNotFound, "User not found", "Failed to retrieve user")
 		return
 	}
 
 	// Check if email already exists (if email is being changed)
 	if req.Email != "" && req.Email != user.Email {
 		var existingUser models.User
-		if err := umc.DB.Where("email = ? AND id != ?", req.Email, user.ID).First(&existingUser).Error; err == nil {
-			utilities.ErrorResponse(c, http.StatusConflict, "Email already exists", "email already taken by another user")
+		if err := umc.DB.WithContext(c).Where("email = ? AND id != ?", req.Email, user.ID).First(&existingUser).Error; err == nil {
+			utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeEmailTaken, "Email already exists", "email already taken by another user")
 			return
 		}
 	}
 
 	// Check permission hierarchy - can only update users with lower or equal roles
-	currentUserRoles, _ := c.Get("roles")
-	currentRoles := currentUserRoles.([]string)
-
 	hierarchy := models.GetRoleHierarchy()
-	currentMaxLevel := 0
-	for _, roleName := range currentRoles {
-		if level, exists := hierarchy[roleName]; exists && level > currentMaxLevel {
-			currentMaxLevel = level
-		}
-	}
+	currentMaxLevel := c.GetInt("role_level")
 
 	// Get target user's highest role level
 	targetMaxLevel := 0
@@ -655,7 +890,7 @@ func (umc *UserManagerController) UpdateUserProfile(c *gin.Context) {
 
 	// Check if current user has permission to update target user
 	if currentMaxLevel < targetMaxLevel {
-		utilities.ErrorResponse(c, http.StatusForbidden, "Insufficient permissions to update this user", "permission denied")
+		utilities.ErrorResponseCode(c, http.StatusForbidden, utilities.ErrCodeInsufficientAccess, "Insufficient permissions to update this user", "permission denied")
 		return
 	}
 
@@ -668,13 +903,13 @@ func (umc *UserManagerController) UpdateUserProfile(c *gin.Context) {
 	}
 
 	// Save changes
-	if err := umc.DB.Save(&user).Error; err != nil {
+	if err := umc.DB.WithContext(c).Save(&user).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update user profile", err.Error())
 		return
 	}
 
 	// Load user with roles for response
-	umc.DB.Preload("UserRoles.Role").Preload("UserRoles.Assigner").First(&user, user.ID)
+	umc.DB.WithContext(c).Preload("UserRoles.Role").Preload("UserRoles.Assigner").First(&user, user.ID)
 
 	utilities.SuccessResponse(c, http.StatusOK, "User profile updated successfully", user.ToUserResponse())
 }
@@ -701,9 +936,9 @@ func (umc *UserManagerController) GetRoles(c *gin.Context) {
 	var total int64
 
 	// Get total count
-	umc.DB.Model(&models.Role{}).Count(&total)
+	umc.DB.WithContext(c).Model(&models.Role{}).Count(&total)
 
-	if err := umc.DB.Limit(limit).Offset(offset).Find(&roles).Error; err != nil {
+	if err := umc.DB.WithContext(c).Limit(limit).Offset(offset).Find(&roles).Error; err != nil {
 		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve roles", err.Error())
 		return
 	}
@@ -726,7 +961,192 @@ func (umc *UserManagerController) GetRoles(c *gin.Context) {
 	utilities.SuccessResponse(c, http.StatusOK, "Roles retrieved successfully", response)
 }
 
+// UpdateRoleTwoFactor godoc
+// @Summary Enforce two-factor authentication for a role
+// @Description Require (or stop requiring) two-factor authentication for every user with the role. Users who have not enrolled yet cannot log in until they enroll, from a logged-in session or with an enrollment token issued by a superadmin.
+// @Tags user-manager
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Param request body UpdateRoleTwoFactorRequest true "Two-factor requirement"
+// @Success 200 {object} utilities.Response{data=models.RoleListResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/user-manager/roles/{id}/two-factor [put]
+func (umc *UserManagerController) UpdateRoleTwoFactor(c *gin.Context) {
+	var req UpdateRoleTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	roleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid role ID", err.Error())
+		return
+	}
+
+	var role models.Role
+	if err := umc.DB.WithContext(c).First(&role, uint(roleID)).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeRoleNotFound, "Role not found", "Failed to retrieve role")
+		return
+	}
+
+	if err := umc.DB.WithContext(c).Model(&role).Update("require_two_factor", req.RequireTwoFactor).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update role", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Role two-factor requirement updated successfully", role.ToRoleListResponse())
+}
+
+// ResetUserTwoFactor godoc
+// @Summary Reset a user's two-factor authentication
+// @Description Remove a user's TOTP secret, for a lost or replaced phone. If a role of the user enforces two-factor authentication they need an enrollment token to log in again.
+// @Tags user-manager
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} utilities.Response{data=models.UserResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/user-manager/users/{id}/two-factor [delete]
+func (umc *UserManagerController) ResetUserTwoFactor(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid user ID parameter", err.Error())
+		return
+	}
+
+	var user models.User
+	if err := umc.DB.WithContext(c).Preload("UserRoles.Role").Preload("UserRoles.Assigner").First(&user, uint(userID)).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeUserNotFound, "User not found", "Failed to retrieve user")
+		return
+	}
+
+	if err := models.ResetTwoFactor(umc.DB.WithContext(c), user.ID); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to reset two-factor authentication", err.Error())
+		return
+	}
+	user.TwoFactorEnabledAt = nil
+
+	utilities.SuccessResponse(c, http.StatusOK, "Two-factor authentication reset successfully", user.ToUserResponse())
+}
+
+// IssueTwoFactorEnrollment godoc
+// @Summary Issue a two-factor enrollment token
+// @Description Issue a single-use token, valid for 24 hours, with which a user whose role requires two-factor authentication but who has not enrolled can enroll (POST /api/auth/2fa/enroll/token, together with their password). Issuing again replaces an unredeemed token. Hand the token to the user over a separate channel.
+// @Tags user-manager
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} utilities.Response{data=TwoFactorEnrollmentTokenResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Failure 409 {object} utilities.Response
+// @Router /api/user-manager/users/{id}/two-factor/enrollment-token [post]
+func (umc *UserManagerController) IssueTwoFactorEnrollment(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid user ID parameter", err.Error())
+		return
+	}
+
+	var user models.User
+	if err := umc.DB.WithContext(c).First(&user, uint(userID)).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeUserNotFound, "User not found", "Failed to retrieve user")
+		return
+	}
+
+	if user.TwoFactorEnabled() {
+		utilities.ErrorResponseCode(c, http.StatusConflict, utilities.ErrCodeTwoFactorEnabled, "Two-factor authentication already enabled", "reset the user's two-factor authentication first")
+		return
+	}
+
+	token, expiresAt, err := models.IssueTwoFactorEnrollment(umc.DB.WithContext(c), user.ID, time.Now())
+	if err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to issue enrollment token", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Enrollment token issued successfully", TwoFactorEnrollmentTokenResponse{
+		EnrollmentToken: token,
+		ExpiresAt:       expiresAt.Format("2006-01-02 15:04:05"),
+	})
+}
+
+// UnlockUser godoc
+// @Summary Unlock a user's account
+// @Description Lift the login lock of a user locked after too many wrong passwords, without waiting for it to expire, and clear their wrong password count. Only users with lower or equal roles can be unlocked.
+// @Tags user-manager
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} utilities.Response{data=models.UserResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/user-manager/users/{id}/unlock [put]
+func (umc *UserManagerController) UnlockUser(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeInvalidParameter, "Invalid user ID parameter", err.Error())
+		return
+	}
+
+	var user models.User
+	if err := umc.DB.WithContext(c).Preload("UserRoles.Role").Preload("UserRoles.Assigner").First(&user, uint(userID)).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeUserNotFound, "User not found", "Failed to retrieve user")
+		return
+	}
+
+	// Check permission hierarchy - can only update users with lower or equal roles
+	hierarchy := models.GetRoleHierarchy()
+	currentMaxLevel := c.GetInt("role_level")
+
+	// Get target user's highest role level
+	targetMaxLevel := 0
+	for _, userRole := range user.UserRoles {
+		if level, exists := hierarchy[userRole.Role.Name]; exists && level > targetMaxLevel {
+			targetMaxLevel = level
+		}
+	}
+
+	// Check if current user has permission to update target user
+	if currentMaxLevel < targetMaxLevel {
+		utilities.ErrorResponseCode(c, http.StatusForbidden, utilities.ErrCodeInsufficientAccess, "Insufficient permissions to update this user", "permission denied")
+		return
+	}
+
+	if err := models.UnlockUser(umc.DB.WithContext(c), user.ID); err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to unlock user", err.Error())
+		return
+	}
+	user.LoginFailures = 0
+	user.LockedUntil = nil
+
+	utilities.SuccessResponse(c, http.StatusOK, "User unlocked successfully", user.ToUserResponse())
+}
+
 // Request/Response structs
+
+// TwoFactorEnrollmentTokenResponse holds a new enrollment token, shown only once
+type TwoFactorEnrollmentTokenResponse struct {
+	EnrollmentToken string `json:"enrollment_token" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822c"`
+	ExpiresAt       string `json:"expires_at" example:"2025-01-02 15:04:05"`
+}
+
 type UsersListResponse struct {
 	Users      []models.UserResponse    `json:"users"`
 	Pagination utilities.PaginationResponse `json:"pagination"`
@@ -740,18 +1160,52 @@ type RoleListResponse struct {
 type CreateUserRequest struct {
 	Username    string `json:"username" binding:"required,min=3,max=50" example:"john_doe"`
 	Email       string `json:"email" binding:"required,email" example:"john@example.com"`
-	Password    string `json:"password" binding:"required,min=6" example:"password123"`
+	Password    string `json:"password" binding:"required" example:"password123"`
 	FullName    string `json:"full_name" binding:"required" example:"John Doe"`
 	IsActive    bool   `json:"is_active" example:"true"`
 	InitialRole string `json:"initial_role,omitempty" example:"picker"`
 }
 
+type BulkCreateUsersRequest struct {
+	Template string        `json:"template" example:"seasonal-picker"` // Role template of rows without one
+	Users    []BulkUserRow `json:"users" binding:"required,min=1"`
+}
+
+type BulkUserRow struct {
+	Username string `json:"username" example:"picker_budi"`
+	FullName string `json:"full_name" example:"Budi Santoso"`
+	Email    string `json:"email,omitempty" example:"budi@example.com"` // Placeholder <username>@<import domain> when left out
+	Template string `json:"template,omitempty" example:"seasonal-picker"`
+}
+
+type BulkCreateUsersResponse struct {
+	Summary BulkCreateUsersSummary `json:"summary"`
+	Results []BulkUserResult       `json:"results"`
+}
+
+type BulkCreateUsersSummary struct {
+	Total   int `json:"total"`
+	Created int `json:"created"`
+	Failed  int `json:"failed"`
+}
+
+type BulkUserResult struct {
+	Index    int    `json:"index"`
+	Username string `json:"username"`
+	Template string `json:"template"`
+	Status   string `json:"status" example:"created"` // created or failed
+	UserID   uint   `json:"user_id,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Password string `json:"password,omitempty"` // Initial password, only shown in this response
+	Error    string `json:"error,omitempty"`
+}
+
 type UpdateUserStatusRequest struct {
 	IsActive bool `json:"is_active" example:"true"`
 }
 
 type UpdateUserPasswordRequest struct {
-	NewPassword string `json:"new_password" binding:"required,min=6" example:"newpassword123"`
+	NewPassword string `json:"new_password" binding:"required" example:"newpassword123"`
 }
 
 type UpdateUserProfileRequest struct {
@@ -766,3 +1220,7 @@ type AssignRoleRequest struct {
 type RemoveRoleRequest struct {
 	RoleName string `json:"role_name" binding:"required" example:"manager"`
 }
+
+type UpdateRoleTwoFactorRequest struct {
+	RequireTwoFactor bool `json:"require_two_factor" example:"true"`
+}
@@ -0,0 +1,276 @@
+package controllers
+
+import (
+	"fmt"
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ZoneController struct {
+	DB *gorm.DB
+}
+
+// NewZoneController creates a new zone controller
+func NewZoneController(db *gorm.DB) *ZoneController {
+	return &ZoneController{DB: db}
+}
+
+// GetZones godoc
+// @Summary Get all zones
+// @Description Get list of all warehouse zones with their assigned pickers, in pick sequence.
+// @Tags zones
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utilities.Response{data=[]models.ZoneResponse}
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/zones [get]
+func (zc *ZoneController) GetZones(c *gin.Context) {
+	var zones []models.Zone
+	if err := zc.DB.WithContext(c).Preload("PickerZones.User").Order("pick_sequence ASC, code ASC").Find(&zones).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve zones", err.Error())
+		return
+	}
+
+	zoneResponses := make([]models.ZoneResponse, len(zones))
+	for i, zone := range zones {
+		zoneResponses[i] = zone.ToZoneResponse()
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Zones retrieved successfully", zoneResponses)
+}
+
+// CreateZone godoc
+// @Summary Create new zone
+// @Description Create a new warehouse zone. Products whose location starts with the location prefix belong to the zone. Pickers walk zones in ascending pick sequence, racks in ascending number unless rack_descending is set.
+// @Tags zones
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ZoneRequest true "Create zone request"
+// @Success 201 {object} utilities.Response{data=models.ZoneResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Router /api/zones [post]
+func (zc *ZoneController) CreateZone(c *gin.Context) {
+	var req ZoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	// Convert code to uppercase and trim spaces
+	req.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+
+	// Check for duplicate zone code
+	var existingZone models.Zone
+	if err := zc.DB.WithContext(c).Where("code = ?", req.Code).First(&existingZone).Error; err == nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDuplicateCode, "Zone code already exists", "A zone with this code already exists")
+		return
+	}
+
+	zone := models.Zone{
+		Code:           req.Code,
+		Name:           req.Name,
+		LocationPrefix: strings.TrimSpace(req.LocationPrefix),
+		PickSequence:   req.PickSequence,
+		RackDescending: req.RackDescending,
+	}
+
+	if err := zc.DB.WithContext(c).Create(&zone).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to create zone", err.Error())
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusCreated, "Zone created successfully", zone.ToZoneResponse())
+}
+
+// UpdateZone godoc
+// @Summary Update zone
+// @Description Update warehouse zone information.
+// @Tags zones
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Zone ID"
+// @Param request body ZoneRequest true "Update zone request"
+// @Success 200 {object} utilities.Response{data=models.ZoneResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/zones/{id} [put]
+func (zc *ZoneController) UpdateZone(c *gin.Context) {
+	zoneID := c.Param("id")
+
+	var req ZoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	var zone models.Zone
+	if err := zc.DB.WithContext(c).First(&zone, zoneID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeZoneNotFound, "Zone not found", "Failed to retrieve zone")
+		return
+	}
+
+	req.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+
+	// Check for duplicate zone code (excluding current zone)
+	var existingZone models.Zone
+	if err := zc.DB.WithContext(c).Where("code = ? AND id != ?", req.Code, zone.ID).First(&existingZone).Error; err == nil {
+		utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodeDuplicateCode, "Zone code already exists", "A zone with this code already exists")
+		return
+	}
+
+	zone.Code = req.Code
+	zone.Name = req.Name
+	zone.LocationPrefix = strings.TrimSpace(req.LocationPrefix)
+	zone.PickSequence = req.PickSequence
+	zone.RackDescending = req.RackDescending
+
+	if err := zc.DB.WithContext(c).Save(&zone).Error; err != nil {
+		utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to update zone", err.Error())
+		return
+	}
+
+	zc.DB.WithContext(c).Preload("PickerZones.User").First(&zone, zone.ID)
+
+	utilities.SuccessResponse(c, http.StatusOK, "Zone updated successfully", zone.ToZoneResponse())
+}
+
+// RemoveZone godoc
+// @Summary Remove zone
+// @Description Soft delete a warehouse zone and its picker assignments.
+// @Tags zones
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Zone ID"
+// @Success 200 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/zones/{id} [delete]
+func (zc *ZoneController) RemoveZone(c *gin.Context) {
+	zoneID := c.Param("id")
+
+	var zone models.Zone
+	if err := zc.DB.WithContext(c).First(&zone, zoneID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeZoneNotFound, "Zone not found", "Failed to retrieve zone")
+		return
+	}
+
+	err := utilities.WithTransaction(zc.DB.WithContext(c), func(tx *gorm.DB) error {
+		if err := tx.Where("zone_id = ?", zone.ID).Delete(&models.PickerZone{}).Error; err != nil {
+			return fmt.Errorf("failed to remove zone pickers: %w", err)
+		}
+
+		if err := tx.Delete(&zone).Error; err != nil {
+			return fmt.Errorf("failed to remove zone: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to remove zone")
+		return
+	}
+
+	utilities.SuccessResponse(c, http.StatusOK, "Zone removed successfully", nil)
+}
+
+// SetZonePickers godoc
+// @Summary Set zone pickers
+// @Description Replace the list of pickers assigned to a zone.
+// @Tags zones
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Zone ID"
+// @Param request body SetZonePickersRequest true "Set zone pickers request"
+// @Success 200 {object} utilities.Response{data=models.ZoneResponse}
+// @Failure 400 {object} utilities.Response
+// @Failure 401 {object} utilities.Response
+// @Failure 403 {object} utilities.Response
+// @Failure 404 {object} utilities.Response
+// @Router /api/zones/{id}/pickers [put]
+func (zc *ZoneController) SetZonePickers(c *gin.Context) {
+	zoneID := c.Param("id")
+
+	var req SetZonePickersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utilities.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	var zone models.Zone
+	if err := zc.DB.WithContext(c).First(&zone, zoneID).Error; err != nil {
+		utilities.LookupErrorResponse(c, err, utilities.ErrCodeZoneNotFound, "Zone not found", "Failed to retrieve zone")
+		return
+	}
+
+	// Verify every picker exists
+	if len(req.PickerIDs) > 0 {
+		var count int64
+		if err := zc.DB.WithContext(c).Model(&models.User{}).Where("id IN ?", req.PickerIDs).Count(&count).Error; err != nil {
+			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to verify pickers", err.Error())
+			return
+		}
+		if int(count) != len(req.PickerIDs) {
+			utilities.ErrorResponseCode(c, http.StatusBadRequest, utilities.ErrCodePickerNotFound, "Picker not found", "one or more picker IDs do not exist")
+			return
+		}
+	}
+
+	err := utilities.WithTransaction(zc.DB.WithContext(c), func(tx *gorm.DB) error {
+		// Hard delete so a picker can be re-added to the same zone later
+		if err := tx.Unscoped().Where("zone_id = ?", zone.ID).Delete(&models.PickerZone{}).Error; err != nil {
+			return fmt.Errorf("failed to clear zone pickers: %w", err)
+		}
+
+		for _, pickerID := range req.PickerIDs {
+			pickerZone := models.PickerZone{
+				UserID:     pickerID,
+				ZoneID:     zone.ID,
+				AssignedBy: userID,
+			}
+			if err := tx.Create(&pickerZone).Error; err != nil {
+				return fmt.Errorf("failed to assign picker to zone: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		utilities.TransactionErrorResponse(c, err, "Failed to update zone pickers")
+		return
+	}
+
+	zc.DB.WithContext(c).Preload("PickerZones.User").First(&zone, zone.ID)
+
+	utilities.SuccessResponse(c, http.StatusOK, "Zone pickers updated successfully", zone.ToZoneResponse())
+}
+
+// Request/Response structs
+type ZoneRequest struct {
+	Code           string `json:"code" binding:"required" example:"A"`
+	Name           string `json:"name" binding:"required" example:"Zone A"`
+	LocationPrefix string `json:"location_prefix" binding:"required" example:"Rak A"`
+	PickSequence   int    `json:"pick_sequence" example:"1"`
+	RackDescending bool   `json:"rack_descending" example:"false"`
+}
+
+type SetZonePickersRequest struct {
+	PickerIDs []uint `json:"picker_ids" example:"2,3"`
+}
@@ -0,0 +1,46 @@
+package graphql
+
+import (
+	"fmt"
+	"livo-backend/utilities"
+	"time"
+)
+
+// Page caps of the list fields
+const (
+	defaultListLimit = 20
+	maxListLimit     = 200
+)
+
+// pageArgs returns the page and limit arguments of a list field, the limit capped at maxListLimit
+func pageArgs(page, limit *int) (int, int) {
+	p, l := 1, defaultListLimit
+	if page != nil && *page > 0 {
+		p = *page
+	}
+	if limit != nil && *limit > 0 {
+		l = *limit
+	}
+	return p, min(l, maxListLimit)
+}
+
+// stringArg returns a string argument, an empty string when it is absent or null
+func stringArg(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// dateArg returns a YYYY-MM-DD argument as midnight in the warehouse timezone, def when it is
+// absent
+func dateArg(name string, value *string, def time.Time) (time.Time, error) {
+	if value == nil || *value == "" {
+		return def, nil
+	}
+	date, err := utilities.ParseDate(*value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("argument %s must be in YYYY-MM-DD format", name)
+	}
+	return date, nil
+}
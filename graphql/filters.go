@@ -0,0 +1,56 @@
+package graphql
+
+import (
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// flowQuery applies the day, operator and tracking search arguments shared by the flow lists
+func flowQuery(query *gorm.DB, table, operatorColumn string, date *string, operatorID *int, search *string) (*gorm.DB, error) {
+	day, err := dateArg("date", date, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	start, end := utilities.DayRange(day)
+	query = query.Where(table+".created_at >= ? AND "+table+".created_at < ?", start, end)
+
+	if operatorID != nil && *operatorID > 0 {
+		query = query.Where(table+"."+operatorColumn+" = ?", *operatorID)
+	}
+	if search := stringArg(search); search != "" {
+		query = query.Where(table+".tracking ILIKE ?", "%"+search+"%")
+	}
+
+	return query, nil
+}
+
+// flowOrders batch loads the orders of the flow trackings when order is selected in items
+func flowOrders(loaders *Loaders, items *Field, trackings []string) (map[string]*models.Order, error) {
+	order := items.Selected("order")
+	if order == nil || len(trackings) == 0 {
+		return nil, nil
+	}
+	return loaders.OrdersByTracking(trackings, order)
+}
+
+// createdBetween applies the start_date and end_date arguments, whole days in the warehouse timezone
+func createdBetween(query *gorm.DB, column string, startDate, endDate *string) (*gorm.DB, error) {
+	start, err := dateArg("start_date", startDate, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if !start.IsZero() {
+		query = query.Where(column+" >= ?", start)
+	}
+	end, err := dateArg("end_date", endDate, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if !end.IsZero() {
+		query = query.Where(column+" < ?", end.AddDate(0, 0, 1))
+	}
+	return query, nil
+}
@@ -0,0 +1,127 @@
+package graphql
+
+import (
+	"livo-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// Loaders batch the lookups of related records over all rows of a list, one query per relation
+// instead of one per row, and only for the relations the query selected
+type Loaders struct {
+	db     *gorm.DB
+	orders map[*Field]map[string]*models.Order
+}
+
+// NewLoaders returns empty loaders for one query field
+func NewLoaders(db *gorm.DB) *Loaders {
+	return &Loaders{db: db, orders: make(map[*Field]map[string]*models.Order)}
+}
+
+// OrdersByTracking loads the orders with the given trackings in one query, with the relations
+// selected in field. Orders already loaded for the same selection are not loaded again.
+func (l *Loaders) OrdersByTracking(trackings []string, field *Field) (map[string]*models.Order, error) {
+	cache, found := l.orders[field]
+	if !found {
+		cache = make(map[string]*models.Order)
+		l.orders[field] = cache
+	}
+
+	var missing []string
+	seen := make(map[string]bool)
+	for _, tracking := range trackings {
+		if _, loaded := cache[tracking]; !loaded && tracking != "" && !seen[tracking] {
+			missing = append(missing, tracking)
+			seen[tracking] = true
+		}
+	}
+
+	if len(missing) > 0 {
+		var orders []models.Order
+		if err := PreloadOrder(l.db, field).Where("tracking IN ?", missing).Find(&orders).Error; err != nil {
+			return nil, err
+		}
+		if err := l.AttachProducts(orders, field); err != nil {
+			return nil, err
+		}
+		for i := range orders {
+			cache[orders[i].Tracking] = &orders[i]
+		}
+	}
+
+	return cache, nil
+}
+
+// AttachProducts loads the products of the order details in one query when order_details.product
+// is selected in field
+func (l *Loaders) AttachProducts(orders []models.Order, field *Field) error {
+	details := field.Selected("order_details")
+	if details == nil || details.Selected("product") == nil {
+		return nil
+	}
+
+	var skus []string
+	for _, order := range orders {
+		for _, detail := range order.OrderDetails {
+			skus = append(skus, detail.Sku)
+		}
+	}
+	if len(skus) == 0 {
+		return nil
+	}
+
+	var products []models.Product
+	if err := l.db.Where("sku IN ?", skus).Find(&products).Error; err != nil {
+		return err
+	}
+	bySku := make(map[string]*models.Product, len(products))
+	for i := range products {
+		bySku[products[i].Sku] = &products[i]
+	}
+
+	for i := range orders {
+		for j := range orders[i].OrderDetails {
+			orders[i].OrderDetails[j].Product = bySku[orders[i].OrderDetails[j].Sku]
+		}
+	}
+	return nil
+}
+
+// orderOperatorFields maps the operator name fields of an order response to their relation
+var orderOperatorFields = map[string]string{
+	"picked_by":    "PickOperator",
+	"pending_by":   "PendingOperator",
+	"changed_by":   "ChangeOperator",
+	"cancelled_by": "CancelOperator",
+	"assigned_by":  "AssignOperator",
+}
+
+// PreloadOrder adds the preloads of the order relations selected in field
+func PreloadOrder(query *gorm.DB, field *Field) *gorm.DB {
+	if field.Selected("order_details") != nil {
+		query = query.Preload("OrderDetails")
+	}
+	for name, relation := range orderOperatorFields {
+		if field.Selected(name) != nil {
+			query = query.Preload(relation)
+		}
+	}
+	return query
+}
+
+// PreloadUser adds the preloads of a user relation when it is selected in field, with the roles
+// when those are selected too
+func PreloadUser(query *gorm.DB, field *Field, name, relation string) *gorm.DB {
+	user := field.Selected(name)
+	if user == nil {
+		return query
+	}
+	query = query.Preload(relation)
+	if roles := user.Selected("roles"); roles != nil {
+		query = query.Preload(relation + ".UserRoles.Role")
+		if roles.Selected("assigned_by") != nil {
+			query = query.Preload(relation + ".UserRoles.Assigner")
+		}
+	}
+	return query
+}
@@ -0,0 +1,45 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package graphql
+
+import (
+	"livo-backend/models"
+)
+
+type OrderList struct {
+	Total int                    `json:"total"`
+	Page  int                    `json:"page"`
+	Limit int                    `json:"limit"`
+	Items []models.OrderResponse `json:"items"`
+}
+
+type OutboundList struct {
+	Total int                       `json:"total"`
+	Page  int                       `json:"page"`
+	Limit int                       `json:"limit"`
+	Items []models.OutboundResponse `json:"items"`
+}
+
+type QcOnlineList struct {
+	Total int                       `json:"total"`
+	Page  int                       `json:"page"`
+	Limit int                       `json:"limit"`
+	Items []models.QcOnlineResponse `json:"items"`
+}
+
+type QcRibbonList struct {
+	Total int                       `json:"total"`
+	Page  int                       `json:"page"`
+	Limit int                       `json:"limit"`
+	Items []models.QcRibbonResponse `json:"items"`
+}
+
+type Query struct {
+}
+
+type UserList struct {
+	Total int                   `json:"total"`
+	Page  int                   `json:"page"`
+	Limit int                   `json:"limit"`
+	Items []models.UserResponse `json:"items"`
+}
@@ -0,0 +1,64 @@
+package graphql
+
+//go:generate go tool gqlgen generate
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"gorm.io/gorm"
+)
+
+// Resolver resolves the queries of the dashboard gateway. The executor and the resolver
+// signatures are generated from schema.graphqls.
+type Resolver struct {
+	DB *gorm.DB
+}
+
+// Caller is the logged-in user running a query
+type Caller struct {
+	UserID uint
+	Roles  []string
+}
+
+type callerKey struct{}
+
+// WithCaller returns ctx carrying the caller the resolvers check and filter by
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+func callerFrom(ctx context.Context) Caller {
+	caller, _ := ctx.Value(callerKey{}).(Caller)
+	return caller
+}
+
+var errInsufficientPermissions = errors.New("insufficient permissions")
+
+// hasAnyRole reports whether the caller has any of allowed
+func hasAnyRole(userRoles, allowed []string) bool {
+	for _, role := range userRoles {
+		for _, allowedRole := range allowed {
+			if role == allowedRole {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Schema is the parsed schema of the gateway
+var Schema = NewExecutableSchema(Config{}).Schema()
+
+// NewServer returns the HTTP handler of the gateway. Queries are read over GET and POST, the
+// schema has no mutations.
+func NewServer(db *gorm.DB) *handler.Server {
+	server := handler.New(NewExecutableSchema(Config{Resolvers: &Resolver{DB: db}}))
+	server.AddTransport(transport.GET{})
+	server.AddTransport(transport.POST{})
+	server.Use(extension.Introspection{})
+	return server
+}
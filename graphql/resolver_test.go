@@ -0,0 +1,72 @@
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/client"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestClient returns a client running queries as a caller with roles, against a connection
+// that builds queries without running them
+func newTestClient(t *testing.T, roles ...string) *client.Client {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DisableAutomaticPing: true,
+		DryRun:               true,
+		Logger:               logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("open dry run database: %v", err)
+	}
+	server := NewServer(db)
+	return client.New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.ServeHTTP(w, r.WithContext(WithCaller(r.Context(), Caller{UserID: 1, Roles: roles})))
+	}))
+}
+
+func TestUsersRequiresCoordinator(t *testing.T) {
+	tests := []struct {
+		roles   []string
+		allowed bool
+	}{
+		{roles: []string{"picker"}},
+		{roles: []string{"admin", "finance"}},
+		{roles: []string{"coordinator"}, allowed: true},
+		{roles: []string{"picker", "superadmin"}, allowed: true},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.roles, ","), func(t *testing.T) {
+			response, err := newTestClient(t, tt.roles...).RawPost(`{ users { total items { username email } } }`)
+			if err != nil {
+				t.Fatalf("query: %v", err)
+			}
+
+			var errs []struct{ Message string }
+			if len(response.Errors) > 0 {
+				if err := json.Unmarshal(response.Errors, &errs); err != nil {
+					t.Fatalf("decode errors: %v", err)
+				}
+			}
+			denied := len(errs) == 1 && errs[0].Message == errInsufficientPermissions.Error()
+			if tt.allowed && len(errs) > 0 {
+				t.Errorf("errors = %+v, want none", errs)
+			}
+			if !tt.allowed && !denied {
+				t.Errorf("errors = %+v, want %q", errs, errInsufficientPermissions)
+			}
+		})
+	}
+}
+
+func TestQueryRejectsMutations(t *testing.T) {
+	_, err := newTestClient(t, "superadmin").RawPost(`mutation { orders { total } }`)
+	if err == nil || !strings.Contains(err.Error(), `does not support operation type \"mutation\"`) {
+		t.Errorf("error = %v, want the mutation rejected", err)
+	}
+}
@@ -0,0 +1,296 @@
+package graphql
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.70
+
+import (
+	"context"
+	"fmt"
+	"livo-backend/middleware"
+	"livo-backend/models"
+	"time"
+)
+
+// Orders is the resolver for the orders field.
+func (r *queryResolver) Orders(ctx context.Context, page *int, limit *int, search *string, processingStatus *string, priority *string, startDate *string, endDate *string) (*OrderList, error) {
+	caller := callerFrom(ctx)
+	p, l := pageArgs(page, limit)
+
+	db := r.DB.WithContext(ctx)
+	query := db.Model(&models.Order{}).Scopes(models.VisibleOrders(caller.UserID, caller.Roles))
+	if search := stringArg(search); search != "" {
+		query = query.Where("order_ginee_id ILIKE ? OR tracking ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+	if status := stringArg(processingStatus); status != "" {
+		query = query.Where("processing_status = ?", status)
+	}
+	if priority := stringArg(priority); priority != "" {
+		query = query.Where("priority = ?", priority)
+	}
+	query, err := createdBetween(query, "orders.created_at", startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	items := selection(ctx).Selected("items")
+	var orders []models.Order
+	if items != nil {
+		if err := PreloadOrder(query, items).Order("id DESC").Limit(l).Offset((p - 1) * l).Find(&orders).Error; err != nil {
+			return nil, err
+		}
+		if err := NewLoaders(db).AttachProducts(orders, items); err != nil {
+			return nil, err
+		}
+	}
+
+	responses := make([]models.OrderResponse, len(orders))
+	for i := range orders {
+		responses[i] = orders[i].ToOrderResponse()
+	}
+
+	return &OrderList{Total: int(total), Page: p, Limit: l, Items: responses}, nil
+}
+
+// Order is the resolver for the order field.
+func (r *queryResolver) Order(ctx context.Context, id *int, tracking *string) (*models.OrderResponse, error) {
+	caller := callerFrom(ctx)
+	field := selection(ctx)
+
+	db := r.DB.WithContext(ctx)
+	query := PreloadOrder(db.Scopes(models.VisibleOrders(caller.UserID, caller.Roles)), field)
+	switch {
+	case id != nil && *id > 0:
+		query = query.Where("id = ?", *id)
+	case stringArg(tracking) != "":
+		query = query.Where("tracking = ?", *tracking)
+	default:
+		return nil, fmt.Errorf("argument id or tracking is required")
+	}
+
+	var orders []models.Order
+	if err := query.Limit(1).Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, nil
+	}
+	if err := NewLoaders(db).AttachProducts(orders, field); err != nil {
+		return nil, err
+	}
+
+	response := orders[0].ToOrderResponse()
+	return &response, nil
+}
+
+// QcRibbons is the resolver for the qc_ribbons field.
+func (r *queryResolver) QcRibbons(ctx context.Context, date *string, operatorID *int, search *string, page *int, limit *int) (*QcRibbonList, error) {
+	p, l := pageArgs(page, limit)
+
+	db := r.DB.WithContext(ctx)
+	query, err := flowQuery(db.Model(&models.QcRibbon{}), "qc_ribbons", "qc_by", date, operatorID, search)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	items := selection(ctx).Selected("items")
+	var qcRibbons []models.QcRibbon
+	if items != nil {
+		if details := items.Selected("qc_ribbon_details"); details != nil {
+			query = query.Preload("QcRibbonDetails")
+			if details.Selected("box") != nil {
+				query = query.Preload("QcRibbonDetails.Box")
+			}
+		}
+		query = PreloadUser(query, items, "qc_operator", "QcOperator")
+		if err := query.Order("id DESC").Limit(l).Offset((p - 1) * l).Find(&qcRibbons).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	trackings := make([]string, len(qcRibbons))
+	for i, qcRibbon := range qcRibbons {
+		trackings[i] = qcRibbon.Tracking
+	}
+	orders, err := flowOrders(NewLoaders(db), items, trackings)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.QcRibbonResponse, len(qcRibbons))
+	for i := range qcRibbons {
+		qcRibbons[i].Order = orders[qcRibbons[i].Tracking]
+		responses[i] = qcRibbons[i].ToQcRibbonResponse()
+	}
+
+	return &QcRibbonList{Total: int(total), Page: p, Limit: l, Items: responses}, nil
+}
+
+// QcOnlines is the resolver for the qc_onlines field.
+func (r *queryResolver) QcOnlines(ctx context.Context, date *string, operatorID *int, search *string, page *int, limit *int) (*QcOnlineList, error) {
+	p, l := pageArgs(page, limit)
+
+	db := r.DB.WithContext(ctx)
+	query, err := flowQuery(db.Model(&models.QcOnline{}), "qc_onlines", "qc_by", date, operatorID, search)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	items := selection(ctx).Selected("items")
+	var qcOnlines []models.QcOnline
+	if items != nil {
+		if details := items.Selected("qc_online_details"); details != nil {
+			query = query.Preload("QcOnlineDetails")
+			if details.Selected("box") != nil {
+				query = query.Preload("QcOnlineDetails.Box")
+			}
+		}
+		query = PreloadUser(query, items, "qc_operator", "QcOperator")
+		if err := query.Order("id DESC").Limit(l).Offset((p - 1) * l).Find(&qcOnlines).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	trackings := make([]string, len(qcOnlines))
+	for i, qcOnline := range qcOnlines {
+		trackings[i] = qcOnline.Tracking
+	}
+	orders, err := flowOrders(NewLoaders(db), items, trackings)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.QcOnlineResponse, len(qcOnlines))
+	for i := range qcOnlines {
+		qcOnlines[i].Order = orders[qcOnlines[i].Tracking]
+		responses[i] = qcOnlines[i].ToQcOnlineResponse()
+	}
+
+	return &QcOnlineList{Total: int(total), Page: p, Limit: l, Items: responses}, nil
+}
+
+// Outbounds is the resolver for the outbounds field.
+func (r *queryResolver) Outbounds(ctx context.Context, date *string, operatorID *int, search *string, expedition *string, page *int, limit *int) (*OutboundList, error) {
+	p, l := pageArgs(page, limit)
+
+	db := r.DB.WithContext(ctx)
+	query, err := flowQuery(db.Model(&models.Outbound{}), "outbounds", "outbound_by", date, operatorID, search)
+	if err != nil {
+		return nil, err
+	}
+	if expedition := stringArg(expedition); expedition != "" {
+		query = query.Where("outbounds.expedition_slug = ? OR outbounds.expedition = ?", expedition, expedition)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	items := selection(ctx).Selected("items")
+	var outbounds []models.Outbound
+	if items != nil {
+		query = PreloadUser(query, items, "outbound_operator", "OutboundOperator")
+		if err := query.Order("id DESC").Limit(l).Offset((p - 1) * l).Find(&outbounds).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	trackings := make([]string, len(outbounds))
+	for i, outbound := range outbounds {
+		trackings[i] = outbound.Tracking
+	}
+	orders, err := flowOrders(NewLoaders(db), items, trackings)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.OutboundResponse, len(outbounds))
+	for i := range outbounds {
+		outbounds[i].Order = orders[outbounds[i].Tracking]
+		responses[i] = outbounds[i].ToOutboundResponse()
+	}
+
+	return &OutboundList{Total: int(total), Page: p, Limit: l, Items: responses}, nil
+}
+
+// Users is the resolver for the users field.
+func (r *queryResolver) Users(ctx context.Context, search *string, page *int, limit *int) (*UserList, error) {
+	if !hasAnyRole(callerFrom(ctx).Roles, middleware.CoordinatorRoles) {
+		return nil, errInsufficientPermissions
+	}
+	p, l := pageArgs(page, limit)
+
+	query := r.DB.WithContext(ctx).Model(&models.User{})
+	if search := stringArg(search); search != "" {
+		query = query.Where("username ILIKE ? OR full_name ILIKE ? OR email ILIKE ?", "%"+search+"%", "%"+search+"%", "%"+search+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	items := selection(ctx).Selected("items")
+	var users []models.User
+	if items != nil {
+		if roles := items.Selected("roles"); roles != nil {
+			query = query.Preload("UserRoles.Role")
+			if roles.Selected("assigned_by") != nil {
+				query = query.Preload("UserRoles.Assigner")
+			}
+		}
+		if err := query.Order("id ASC").Limit(l).Offset((p - 1) * l).Find(&users).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	responses := make([]models.UserResponse, len(users))
+	for i := range users {
+		responses[i] = users[i].ToUserResponse()
+	}
+
+	return &UserList{Total: int(total), Page: p, Limit: l, Items: responses}, nil
+}
+
+// DailySummary is the resolver for the daily_summary field.
+func (r *queryResolver) DailySummary(ctx context.Context, date *string) (*models.DailySummary, error) {
+	day, err := dateArg("date", date, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	summary, err := models.BuildDailySummary(r.DB.WithContext(ctx), day)
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// QcDashboard is the resolver for the qc_dashboard field.
+func (r *queryResolver) QcDashboard(ctx context.Context) (*models.QcDashboard, error) {
+	dashboard, err := models.BuildQcDashboard(r.DB.WithContext(ctx), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &dashboard, nil
+}
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+type queryResolver struct{ *Resolver }
@@ -0,0 +1,62 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Field is a selected field with the fields selected under it, used to load only the relations
+// a query selects
+type Field struct {
+	Name       string
+	Selections []*Field
+}
+
+// Selected returns the selected subfield with the given name, nil when it was not selected
+func (f *Field) Selected(name string) *Field {
+	if f == nil {
+		return nil
+	}
+	for _, selection := range f.Selections {
+		if selection.Name == name {
+			return selection
+		}
+	}
+	return nil
+}
+
+// selection returns the field being resolved with its selections, fragments expanded
+func selection(ctx context.Context) *Field {
+	opCtx := graphql.GetOperationContext(ctx)
+	field := graphql.GetFieldContext(ctx).Field
+	return &Field{Name: field.Name, Selections: collect(opCtx, field.Selections, field.Definition.Type.Name())}
+}
+
+// collect resolves a selection set on typeName. A field selected more than once, under
+// different aliases, gets the selections of all of them.
+func collect(opCtx *graphql.OperationContext, set ast.SelectionSet, typeName string) []*Field {
+	var fields []*Field
+	for _, collected := range graphql.CollectFields(opCtx, set, []string{typeName}) {
+		var selections []*Field
+		if len(collected.Selections) > 0 {
+			selections = collect(opCtx, collected.Selections, collected.Definition.Type.Name())
+		}
+		fields = merge(fields, &Field{Name: collected.Name, Selections: selections})
+	}
+	return fields
+}
+
+// merge adds field to fields, merging its selections into a field of the same name
+func merge(fields []*Field, field *Field) []*Field {
+	for _, existing := range fields {
+		if existing.Name == field.Name {
+			for _, selection := range field.Selections {
+				existing.Selections = merge(existing.Selections, selection)
+			}
+			return fields
+		}
+	}
+	return append(fields, field)
+}
@@ -0,0 +1,1469 @@
+// Read-only streams of the analytics gRPC API, served on GRPC_PORT when GRPC_ENABLED is set.
+//
+// Calls need an API key (created with POST /api/api-keys) sent as the x-api-key metadata or as
+// "authorization: Bearer <key>", allowed to read the stream's resource: orders, outbounds,
+// ribbons (QC ribbons) or onlines (QC onlines).
+//
+// Records are streamed in ID order, with the fields of the REST response of the record
+// (OrderResponse, OutboundResponse, QcRibbonResponse, QcOnlineResponse). Dates and times the REST
+// responses give as text are kept as text, in the same format.
+//
+// analytics.pb.go and analytics_grpc.pb.go are generated from this file, see go:generate in server.go.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: analytics.proto
+
+package grpcapi
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// StreamRequest selects the records of a stream, every field is optional
+type StreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartDate     string                 `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`          // YYYY-MM-DD, records created on or after the day
+	EndDate       string                 `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`                // YYYY-MM-DD, records created on or before the day
+	UpdatedSince  string                 `protobuf:"bytes,3,opt,name=updated_since,json=updatedSince,proto3" json:"updated_since,omitempty"` // RFC 3339 timestamp, records updated at or after it
+	AfterId       uint64                 `protobuf:"varint,4,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`               // Records with a higher ID, to resume an interrupted stream
+	BatchSize     uint32                 `protobuf:"varint,5,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`         // Records read from the database at a time, 1 to 5000 (default 500)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamRequest) Reset() {
+	*x = StreamRequest{}
+	mi := &file_analytics_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRequest) ProtoMessage() {}
+
+func (x *StreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRequest.ProtoReflect.Descriptor instead.
+func (*StreamRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StreamRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *StreamRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *StreamRequest) GetUpdatedSince() string {
+	if x != nil {
+		return x.UpdatedSince
+	}
+	return ""
+}
+
+func (x *StreamRequest) GetAfterId() uint64 {
+	if x != nil {
+		return x.AfterId
+	}
+	return 0
+}
+
+func (x *StreamRequest) GetBatchSize() uint32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+// Operator is the user who handled a record
+type Operator struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	FullName      string                 `protobuf:"bytes,3,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Operator) Reset() {
+	*x = Operator{}
+	mi := &file_analytics_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Operator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Operator) ProtoMessage() {}
+
+func (x *Operator) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Operator.ProtoReflect.Descriptor instead.
+func (*Operator) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Operator) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Operator) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *Operator) GetFullName() string {
+	if x != nil {
+		return x.FullName
+	}
+	return ""
+}
+
+type Order struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderGineeId     string                 `protobuf:"bytes,2,opt,name=order_ginee_id,json=orderGineeId,proto3" json:"order_ginee_id,omitempty"`
+	ProcessingStatus string                 `protobuf:"bytes,3,opt,name=processing_status,json=processingStatus,proto3" json:"processing_status,omitempty"`
+	EventStatus      *string                `protobuf:"bytes,4,opt,name=event_status,json=eventStatus,proto3,oneof" json:"event_status,omitempty"`
+	Channel          string                 `protobuf:"bytes,5,opt,name=channel,proto3" json:"channel,omitempty"`
+	Store            string                 `protobuf:"bytes,6,opt,name=store,proto3" json:"store,omitempty"`
+	Buyer            string                 `protobuf:"bytes,7,opt,name=buyer,proto3" json:"buyer,omitempty"`
+	BuyerPhone       string                 `protobuf:"bytes,8,opt,name=buyer_phone,json=buyerPhone,proto3" json:"buyer_phone,omitempty"` // Masked like in the REST response
+	Address          string                 `protobuf:"bytes,9,opt,name=address,proto3" json:"address,omitempty"`
+	Province         string                 `protobuf:"bytes,10,opt,name=province,proto3" json:"province,omitempty"`
+	City             string                 `protobuf:"bytes,11,opt,name=city,proto3" json:"city,omitempty"`
+	PostalCode       string                 `protobuf:"bytes,12,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	AddressStatus    string                 `protobuf:"bytes,13,opt,name=address_status,json=addressStatus,proto3" json:"address_status,omitempty"`
+	Courier          string                 `protobuf:"bytes,14,opt,name=courier,proto3" json:"courier,omitempty"`
+	Tracking         string                 `protobuf:"bytes,15,opt,name=tracking,proto3" json:"tracking,omitempty"`
+	SentBefore       string                 `protobuf:"bytes,16,opt,name=sent_before,json=sentBefore,proto3" json:"sent_before,omitempty"`
+	Complained       bool                   `protobuf:"varint,17,opt,name=complained,proto3" json:"complained,omitempty"`
+	AssignedBy       string                 `protobuf:"bytes,18,opt,name=assigned_by,json=assignedBy,proto3" json:"assigned_by,omitempty"`
+	AssignedAt       string                 `protobuf:"bytes,19,opt,name=assigned_at,json=assignedAt,proto3" json:"assigned_at,omitempty"`
+	PickedBy         string                 `protobuf:"bytes,20,opt,name=picked_by,json=pickedBy,proto3" json:"picked_by,omitempty"`
+	PickedAt         string                 `protobuf:"bytes,21,opt,name=picked_at,json=pickedAt,proto3" json:"picked_at,omitempty"`
+	PendingBy        string                 `protobuf:"bytes,22,opt,name=pending_by,json=pendingBy,proto3" json:"pending_by,omitempty"`
+	PendingAt        string                 `protobuf:"bytes,23,opt,name=pending_at,json=pendingAt,proto3" json:"pending_at,omitempty"`
+	ChangedBy        string                 `protobuf:"bytes,24,opt,name=changed_by,json=changedBy,proto3" json:"changed_by,omitempty"`
+	ChangedAt        string                 `protobuf:"bytes,25,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	CancelledBy      string                 `protobuf:"bytes,26,opt,name=cancelled_by,json=cancelledBy,proto3" json:"cancelled_by,omitempty"`
+	CancelledAt      string                 `protobuf:"bytes,27,opt,name=cancelled_at,json=cancelledAt,proto3" json:"cancelled_at,omitempty"`
+	DeliveryStatus   string                 `protobuf:"bytes,28,opt,name=delivery_status,json=deliveryStatus,proto3" json:"delivery_status,omitempty"`
+	DeliveryStatusAt string                 `protobuf:"bytes,29,opt,name=delivery_status_at,json=deliveryStatusAt,proto3" json:"delivery_status_at,omitempty"`
+	Instant          bool                   `protobuf:"varint,30,opt,name=instant,proto3" json:"instant,omitempty"`
+	SlaDueAt         string                 `protobuf:"bytes,31,opt,name=sla_due_at,json=slaDueAt,proto3" json:"sla_due_at,omitempty"`
+	SlaBreached      bool                   `protobuf:"varint,32,opt,name=sla_breached,json=slaBreached,proto3" json:"sla_breached,omitempty"`
+	Priority         string                 `protobuf:"bytes,33,opt,name=priority,proto3" json:"priority,omitempty"`
+	AtRisk           bool                   `protobuf:"varint,34,opt,name=at_risk,json=atRisk,proto3" json:"at_risk,omitempty"`
+	AtRiskAt         string                 `protobuf:"bytes,35,opt,name=at_risk_at,json=atRiskAt,proto3" json:"at_risk_at,omitempty"`
+	ParcelCount      int32                  `protobuf:"varint,36,opt,name=parcel_count,json=parcelCount,proto3" json:"parcel_count,omitempty"`
+	MergedIntoId     *uint64                `protobuf:"varint,37,opt,name=merged_into_id,json=mergedIntoId,proto3,oneof" json:"merged_into_id,omitempty"`
+	MergedAt         string                 `protobuf:"bytes,38,opt,name=merged_at,json=mergedAt,proto3" json:"merged_at,omitempty"`
+	Held             bool                   `protobuf:"varint,39,opt,name=held,proto3" json:"held,omitempty"`
+	HeldAt           string                 `protobuf:"bytes,40,opt,name=held_at,json=heldAt,proto3" json:"held_at,omitempty"`
+	HoldReason       string                 `protobuf:"bytes,41,opt,name=hold_reason,json=holdReason,proto3" json:"hold_reason,omitempty"`
+	Version          uint64                 `protobuf:"varint,42,opt,name=version,proto3" json:"version,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,43,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt        *timestamppb.Timestamp `protobuf:"bytes,44,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	OrderDetails     []*OrderDetail         `protobuf:"bytes,45,rep,name=order_details,json=orderDetails,proto3" json:"order_details,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_analytics_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Order) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Order) GetOrderGineeId() string {
+	if x != nil {
+		return x.OrderGineeId
+	}
+	return ""
+}
+
+func (x *Order) GetProcessingStatus() string {
+	if x != nil {
+		return x.ProcessingStatus
+	}
+	return ""
+}
+
+func (x *Order) GetEventStatus() string {
+	if x != nil && x.EventStatus != nil {
+		return *x.EventStatus
+	}
+	return ""
+}
+
+func (x *Order) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *Order) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+func (x *Order) GetBuyer() string {
+	if x != nil {
+		return x.Buyer
+	}
+	return ""
+}
+
+func (x *Order) GetBuyerPhone() string {
+	if x != nil {
+		return x.BuyerPhone
+	}
+	return ""
+}
+
+func (x *Order) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Order) GetProvince() string {
+	if x != nil {
+		return x.Province
+	}
+	return ""
+}
+
+func (x *Order) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Order) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *Order) GetAddressStatus() string {
+	if x != nil {
+		return x.AddressStatus
+	}
+	return ""
+}
+
+func (x *Order) GetCourier() string {
+	if x != nil {
+		return x.Courier
+	}
+	return ""
+}
+
+func (x *Order) GetTracking() string {
+	if x != nil {
+		return x.Tracking
+	}
+	return ""
+}
+
+func (x *Order) GetSentBefore() string {
+	if x != nil {
+		return x.SentBefore
+	}
+	return ""
+}
+
+func (x *Order) GetComplained() bool {
+	if x != nil {
+		return x.Complained
+	}
+	return false
+}
+
+func (x *Order) GetAssignedBy() string {
+	if x != nil {
+		return x.AssignedBy
+	}
+	return ""
+}
+
+func (x *Order) GetAssignedAt() string {
+	if x != nil {
+		return x.AssignedAt
+	}
+	return ""
+}
+
+func (x *Order) GetPickedBy() string {
+	if x != nil {
+		return x.PickedBy
+	}
+	return ""
+}
+
+func (x *Order) GetPickedAt() string {
+	if x != nil {
+		return x.PickedAt
+	}
+	return ""
+}
+
+func (x *Order) GetPendingBy() string {
+	if x != nil {
+		return x.PendingBy
+	}
+	return ""
+}
+
+func (x *Order) GetPendingAt() string {
+	if x != nil {
+		return x.PendingAt
+	}
+	return ""
+}
+
+func (x *Order) GetChangedBy() string {
+	if x != nil {
+		return x.ChangedBy
+	}
+	return ""
+}
+
+func (x *Order) GetChangedAt() string {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return ""
+}
+
+func (x *Order) GetCancelledBy() string {
+	if x != nil {
+		return x.CancelledBy
+	}
+	return ""
+}
+
+func (x *Order) GetCancelledAt() string {
+	if x != nil {
+		return x.CancelledAt
+	}
+	return ""
+}
+
+func (x *Order) GetDeliveryStatus() string {
+	if x != nil {
+		return x.DeliveryStatus
+	}
+	return ""
+}
+
+func (x *Order) GetDeliveryStatusAt() string {
+	if x != nil {
+		return x.DeliveryStatusAt
+	}
+	return ""
+}
+
+func (x *Order) GetInstant() bool {
+	if x != nil {
+		return x.Instant
+	}
+	return false
+}
+
+func (x *Order) GetSlaDueAt() string {
+	if x != nil {
+		return x.SlaDueAt
+	}
+	return ""
+}
+
+func (x *Order) GetSlaBreached() bool {
+	if x != nil {
+		return x.SlaBreached
+	}
+	return false
+}
+
+func (x *Order) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *Order) GetAtRisk() bool {
+	if x != nil {
+		return x.AtRisk
+	}
+	return false
+}
+
+func (x *Order) GetAtRiskAt() string {
+	if x != nil {
+		return x.AtRiskAt
+	}
+	return ""
+}
+
+func (x *Order) GetParcelCount() int32 {
+	if x != nil {
+		return x.ParcelCount
+	}
+	return 0
+}
+
+func (x *Order) GetMergedIntoId() uint64 {
+	if x != nil && x.MergedIntoId != nil {
+		return *x.MergedIntoId
+	}
+	return 0
+}
+
+func (x *Order) GetMergedAt() string {
+	if x != nil {
+		return x.MergedAt
+	}
+	return ""
+}
+
+func (x *Order) GetHeld() bool {
+	if x != nil {
+		return x.Held
+	}
+	return false
+}
+
+func (x *Order) GetHeldAt() string {
+	if x != nil {
+		return x.HeldAt
+	}
+	return ""
+}
+
+func (x *Order) GetHoldReason() string {
+	if x != nil {
+		return x.HoldReason
+	}
+	return ""
+}
+
+func (x *Order) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Order) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Order) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Order) GetOrderDetails() []*OrderDetail {
+	if x != nil {
+		return x.OrderDetails
+	}
+	return nil
+}
+
+type OrderDetail struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Sku             string                 `protobuf:"bytes,2,opt,name=sku,proto3" json:"sku,omitempty"`
+	ProductName     string                 `protobuf:"bytes,3,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	Variant         string                 `protobuf:"bytes,4,opt,name=variant,proto3" json:"variant,omitempty"`
+	Quantity        int32                  `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price           int64                  `protobuf:"varint,6,opt,name=price,proto3" json:"price,omitempty"`
+	ScannedQuantity int32                  `protobuf:"varint,7,opt,name=scanned_quantity,json=scannedQuantity,proto3" json:"scanned_quantity,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *OrderDetail) Reset() {
+	*x = OrderDetail{}
+	mi := &file_analytics_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderDetail) ProtoMessage() {}
+
+func (x *OrderDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderDetail.ProtoReflect.Descriptor instead.
+func (*OrderDetail) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OrderDetail) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *OrderDetail) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *OrderDetail) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+func (x *OrderDetail) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *OrderDetail) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderDetail) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *OrderDetail) GetScannedQuantity() int32 {
+	if x != nil {
+		return x.ScannedQuantity
+	}
+	return 0
+}
+
+type Outbound struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Tracking         string                 `protobuf:"bytes,2,opt,name=tracking,proto3" json:"tracking,omitempty"`
+	OutboundBy       *uint64                `protobuf:"varint,3,opt,name=outbound_by,json=outboundBy,proto3,oneof" json:"outbound_by,omitempty"`
+	Expedition       string                 `protobuf:"bytes,4,opt,name=expedition,proto3" json:"expedition,omitempty"`
+	ExpeditionColor  string                 `protobuf:"bytes,5,opt,name=expedition_color,json=expeditionColor,proto3" json:"expedition_color,omitempty"`
+	ExpeditionSlug   string                 `protobuf:"bytes,6,opt,name=expedition_slug,json=expeditionSlug,proto3" json:"expedition_slug,omitempty"`
+	Complained       bool                   `protobuf:"varint,7,opt,name=complained,proto3" json:"complained,omitempty"`
+	PickupSlotId     *uint64                `protobuf:"varint,8,opt,name=pickup_slot_id,json=pickupSlotId,proto3,oneof" json:"pickup_slot_id,omitempty"`
+	PickupAt         *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=pickup_at,json=pickupAt,proto3" json:"pickup_at,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt        *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	OutboundOperator *Operator              `protobuf:"bytes,12,opt,name=outbound_operator,json=outboundOperator,proto3" json:"outbound_operator,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Outbound) Reset() {
+	*x = Outbound{}
+	mi := &file_analytics_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Outbound) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Outbound) ProtoMessage() {}
+
+func (x *Outbound) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Outbound.ProtoReflect.Descriptor instead.
+func (*Outbound) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Outbound) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Outbound) GetTracking() string {
+	if x != nil {
+		return x.Tracking
+	}
+	return ""
+}
+
+func (x *Outbound) GetOutboundBy() uint64 {
+	if x != nil && x.OutboundBy != nil {
+		return *x.OutboundBy
+	}
+	return 0
+}
+
+func (x *Outbound) GetExpedition() string {
+	if x != nil {
+		return x.Expedition
+	}
+	return ""
+}
+
+func (x *Outbound) GetExpeditionColor() string {
+	if x != nil {
+		return x.ExpeditionColor
+	}
+	return ""
+}
+
+func (x *Outbound) GetExpeditionSlug() string {
+	if x != nil {
+		return x.ExpeditionSlug
+	}
+	return ""
+}
+
+func (x *Outbound) GetComplained() bool {
+	if x != nil {
+		return x.Complained
+	}
+	return false
+}
+
+func (x *Outbound) GetPickupSlotId() uint64 {
+	if x != nil && x.PickupSlotId != nil {
+		return *x.PickupSlotId
+	}
+	return 0
+}
+
+func (x *Outbound) GetPickupAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PickupAt
+	}
+	return nil
+}
+
+func (x *Outbound) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Outbound) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Outbound) GetOutboundOperator() *Operator {
+	if x != nil {
+		return x.OutboundOperator
+	}
+	return nil
+}
+
+type Box struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Box) Reset() {
+	*x = Box{}
+	mi := &file_analytics_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Box) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Box) ProtoMessage() {}
+
+func (x *Box) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Box.ProtoReflect.Descriptor instead.
+func (*Box) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Box) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Box) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Box) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// QcDetail is a box used to pack a QC'd parcel
+type QcDetail struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	BoxId         uint64                 `protobuf:"varint,2,opt,name=box_id,json=boxId,proto3" json:"box_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Box           *Box                   `protobuf:"bytes,4,opt,name=box,proto3" json:"box,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QcDetail) Reset() {
+	*x = QcDetail{}
+	mi := &file_analytics_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QcDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QcDetail) ProtoMessage() {}
+
+func (x *QcDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QcDetail.ProtoReflect.Descriptor instead.
+func (*QcDetail) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *QcDetail) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *QcDetail) GetBoxId() uint64 {
+	if x != nil {
+		return x.BoxId
+	}
+	return 0
+}
+
+func (x *QcDetail) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *QcDetail) GetBox() *Box {
+	if x != nil {
+		return x.Box
+	}
+	return nil
+}
+
+func (x *QcDetail) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *QcDetail) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type QcRibbon struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Tracking           string                 `protobuf:"bytes,2,opt,name=tracking,proto3" json:"tracking,omitempty"`
+	ParcelNumber       int32                  `protobuf:"varint,3,opt,name=parcel_number,json=parcelNumber,proto3" json:"parcel_number,omitempty"`
+	QcBy               *uint64                `protobuf:"varint,4,opt,name=qc_by,json=qcBy,proto3,oneof" json:"qc_by,omitempty"`
+	Complained         bool                   `protobuf:"varint,5,opt,name=complained,proto3" json:"complained,omitempty"`
+	SuggestedBoxId     *uint64                `protobuf:"varint,6,opt,name=suggested_box_id,json=suggestedBoxId,proto3,oneof" json:"suggested_box_id,omitempty"`
+	SuggestionAccepted *bool                  `protobuf:"varint,7,opt,name=suggestion_accepted,json=suggestionAccepted,proto3,oneof" json:"suggestion_accepted,omitempty"`
+	CancelledAt        *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=cancelled_at,json=cancelledAt,proto3" json:"cancelled_at,omitempty"`
+	CreatedAt          *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt          *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	QcRibbonDetails    []*QcDetail            `protobuf:"bytes,11,rep,name=qc_ribbon_details,json=qcRibbonDetails,proto3" json:"qc_ribbon_details,omitempty"`
+	QcOperator         *Operator              `protobuf:"bytes,12,opt,name=qc_operator,json=qcOperator,proto3" json:"qc_operator,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *QcRibbon) Reset() {
+	*x = QcRibbon{}
+	mi := &file_analytics_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QcRibbon) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QcRibbon) ProtoMessage() {}
+
+func (x *QcRibbon) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QcRibbon.ProtoReflect.Descriptor instead.
+func (*QcRibbon) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *QcRibbon) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *QcRibbon) GetTracking() string {
+	if x != nil {
+		return x.Tracking
+	}
+	return ""
+}
+
+func (x *QcRibbon) GetParcelNumber() int32 {
+	if x != nil {
+		return x.ParcelNumber
+	}
+	return 0
+}
+
+func (x *QcRibbon) GetQcBy() uint64 {
+	if x != nil && x.QcBy != nil {
+		return *x.QcBy
+	}
+	return 0
+}
+
+func (x *QcRibbon) GetComplained() bool {
+	if x != nil {
+		return x.Complained
+	}
+	return false
+}
+
+func (x *QcRibbon) GetSuggestedBoxId() uint64 {
+	if x != nil && x.SuggestedBoxId != nil {
+		return *x.SuggestedBoxId
+	}
+	return 0
+}
+
+func (x *QcRibbon) GetSuggestionAccepted() bool {
+	if x != nil && x.SuggestionAccepted != nil {
+		return *x.SuggestionAccepted
+	}
+	return false
+}
+
+func (x *QcRibbon) GetCancelledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CancelledAt
+	}
+	return nil
+}
+
+func (x *QcRibbon) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *QcRibbon) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *QcRibbon) GetQcRibbonDetails() []*QcDetail {
+	if x != nil {
+		return x.QcRibbonDetails
+	}
+	return nil
+}
+
+func (x *QcRibbon) GetQcOperator() *Operator {
+	if x != nil {
+		return x.QcOperator
+	}
+	return nil
+}
+
+type QcOnline struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Tracking           string                 `protobuf:"bytes,2,opt,name=tracking,proto3" json:"tracking,omitempty"`
+	ParcelNumber       int32                  `protobuf:"varint,3,opt,name=parcel_number,json=parcelNumber,proto3" json:"parcel_number,omitempty"`
+	QcBy               *uint64                `protobuf:"varint,4,opt,name=qc_by,json=qcBy,proto3,oneof" json:"qc_by,omitempty"`
+	Complained         bool                   `protobuf:"varint,5,opt,name=complained,proto3" json:"complained,omitempty"`
+	SuggestedBoxId     *uint64                `protobuf:"varint,6,opt,name=suggested_box_id,json=suggestedBoxId,proto3,oneof" json:"suggested_box_id,omitempty"`
+	SuggestionAccepted *bool                  `protobuf:"varint,7,opt,name=suggestion_accepted,json=suggestionAccepted,proto3,oneof" json:"suggestion_accepted,omitempty"`
+	CancelledAt        *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=cancelled_at,json=cancelledAt,proto3" json:"cancelled_at,omitempty"`
+	CreatedAt          *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt          *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	QcOnlineDetails    []*QcDetail            `protobuf:"bytes,11,rep,name=qc_online_details,json=qcOnlineDetails,proto3" json:"qc_online_details,omitempty"`
+	QcOperator         *Operator              `protobuf:"bytes,12,opt,name=qc_operator,json=qcOperator,proto3" json:"qc_operator,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *QcOnline) Reset() {
+	*x = QcOnline{}
+	mi := &file_analytics_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QcOnline) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QcOnline) ProtoMessage() {}
+
+func (x *QcOnline) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QcOnline.ProtoReflect.Descriptor instead.
+func (*QcOnline) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *QcOnline) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *QcOnline) GetTracking() string {
+	if x != nil {
+		return x.Tracking
+	}
+	return ""
+}
+
+func (x *QcOnline) GetParcelNumber() int32 {
+	if x != nil {
+		return x.ParcelNumber
+	}
+	return 0
+}
+
+func (x *QcOnline) GetQcBy() uint64 {
+	if x != nil && x.QcBy != nil {
+		return *x.QcBy
+	}
+	return 0
+}
+
+func (x *QcOnline) GetComplained() bool {
+	if x != nil {
+		return x.Complained
+	}
+	return false
+}
+
+func (x *QcOnline) GetSuggestedBoxId() uint64 {
+	if x != nil && x.SuggestedBoxId != nil {
+		return *x.SuggestedBoxId
+	}
+	return 0
+}
+
+func (x *QcOnline) GetSuggestionAccepted() bool {
+	if x != nil && x.SuggestionAccepted != nil {
+		return *x.SuggestionAccepted
+	}
+	return false
+}
+
+func (x *QcOnline) GetCancelledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CancelledAt
+	}
+	return nil
+}
+
+func (x *QcOnline) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *QcOnline) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *QcOnline) GetQcOnlineDetails() []*QcDetail {
+	if x != nil {
+		return x.QcOnlineDetails
+	}
+	return nil
+}
+
+func (x *QcOnline) GetQcOperator() *Operator {
+	if x != nil {
+		return x.QcOperator
+	}
+	return nil
+}
+
+var File_analytics_proto protoreflect.FileDescriptor
+
+const file_analytics_proto_rawDesc = "" +
+	"\n" +
+	"\x0fanalytics.proto\x12\x11livo.analytics.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa8\x01\n" +
+	"\rStreamRequest\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x02 \x01(\tR\aendDate\x12#\n" +
+	"\rupdated_since\x18\x03 \x01(\tR\fupdatedSince\x12\x19\n" +
+	"\bafter_id\x18\x04 \x01(\x04R\aafterId\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x05 \x01(\rR\tbatchSize\"S\n" +
+	"\bOperator\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x1b\n" +
+	"\tfull_name\x18\x03 \x01(\tR\bfullName\"\xf7\v\n" +
+	"\x05Order\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12$\n" +
+	"\x0eorder_ginee_id\x18\x02 \x01(\tR\forderGineeId\x12+\n" +
+	"\x11processing_status\x18\x03 \x01(\tR\x10processingStatus\x12&\n" +
+	"\fevent_status\x18\x04 \x01(\tH\x00R\veventStatus\x88\x01\x01\x12\x18\n" +
+	"\achannel\x18\x05 \x01(\tR\achannel\x12\x14\n" +
+	"\x05store\x18\x06 \x01(\tR\x05store\x12\x14\n" +
+	"\x05buyer\x18\a \x01(\tR\x05buyer\x12\x1f\n" +
+	"\vbuyer_phone\x18\b \x01(\tR\n" +
+	"buyerPhone\x12\x18\n" +
+	"\aaddress\x18\t \x01(\tR\aaddress\x12\x1a\n" +
+	"\bprovince\x18\n" +
+	" \x01(\tR\bprovince\x12\x12\n" +
+	"\x04city\x18\v \x01(\tR\x04city\x12\x1f\n" +
+	"\vpostal_code\x18\f \x01(\tR\n" +
+	"postalCode\x12%\n" +
+	"\x0eaddress_status\x18\r \x01(\tR\raddressStatus\x12\x18\n" +
+	"\acourier\x18\x0e \x01(\tR\acourier\x12\x1a\n" +
+	"\btracking\x18\x0f \x01(\tR\btracking\x12\x1f\n" +
+	"\vsent_before\x18\x10 \x01(\tR\n" +
+	"sentBefore\x12\x1e\n" +
+	"\n" +
+	"complained\x18\x11 \x01(\bR\n" +
+	"complained\x12\x1f\n" +
+	"\vassigned_by\x18\x12 \x01(\tR\n" +
+	"assignedBy\x12\x1f\n" +
+	"\vassigned_at\x18\x13 \x01(\tR\n" +
+	"assignedAt\x12\x1b\n" +
+	"\tpicked_by\x18\x14 \x01(\tR\bpickedBy\x12\x1b\n" +
+	"\tpicked_at\x18\x15 \x01(\tR\bpickedAt\x12\x1d\n" +
+	"\n" +
+	"pending_by\x18\x16 \x01(\tR\tpendingBy\x12\x1d\n" +
+	"\n" +
+	"pending_at\x18\x17 \x01(\tR\tpendingAt\x12\x1d\n" +
+	"\n" +
+	"changed_by\x18\x18 \x01(\tR\tchangedBy\x12\x1d\n" +
+	"\n" +
+	"changed_at\x18\x19 \x01(\tR\tchangedAt\x12!\n" +
+	"\fcancelled_by\x18\x1a \x01(\tR\vcancelledBy\x12!\n" +
+	"\fcancelled_at\x18\x1b \x01(\tR\vcancelledAt\x12'\n" +
+	"\x0fdelivery_status\x18\x1c \x01(\tR\x0edeliveryStatus\x12,\n" +
+	"\x12delivery_status_at\x18\x1d \x01(\tR\x10deliveryStatusAt\x12\x18\n" +
+	"\ainstant\x18\x1e \x01(\bR\ainstant\x12\x1c\n" +
+	"\n" +
+	"sla_due_at\x18\x1f \x01(\tR\bslaDueAt\x12!\n" +
+	"\fsla_breached\x18  \x01(\bR\vslaBreached\x12\x1a\n" +
+	"\bpriority\x18! \x01(\tR\bpriority\x12\x17\n" +
+	"\aat_risk\x18\" \x01(\bR\x06atRisk\x12\x1c\n" +
+	"\n" +
+	"at_risk_at\x18# \x01(\tR\batRiskAt\x12!\n" +
+	"\fparcel_count\x18$ \x01(\x05R\vparcelCount\x12)\n" +
+	"\x0emerged_into_id\x18% \x01(\x04H\x01R\fmergedIntoId\x88\x01\x01\x12\x1b\n" +
+	"\tmerged_at\x18& \x01(\tR\bmergedAt\x12\x12\n" +
+	"\x04held\x18' \x01(\bR\x04held\x12\x17\n" +
+	"\aheld_at\x18( \x01(\tR\x06heldAt\x12\x1f\n" +
+	"\vhold_reason\x18) \x01(\tR\n" +
+	"holdReason\x12\x18\n" +
+	"\aversion\x18* \x01(\x04R\aversion\x129\n" +
+	"\n" +
+	"created_at\x18+ \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18, \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12C\n" +
+	"\rorder_details\x18- \x03(\v2\x1e.livo.analytics.v1.OrderDetailR\forderDetailsB\x0f\n" +
+	"\r_event_statusB\x11\n" +
+	"\x0f_merged_into_id\"\xc9\x01\n" +
+	"\vOrderDetail\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x10\n" +
+	"\x03sku\x18\x02 \x01(\tR\x03sku\x12!\n" +
+	"\fproduct_name\x18\x03 \x01(\tR\vproductName\x12\x18\n" +
+	"\avariant\x18\x04 \x01(\tR\avariant\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\x05R\bquantity\x12\x14\n" +
+	"\x05price\x18\x06 \x01(\x03R\x05price\x12)\n" +
+	"\x10scanned_quantity\x18\a \x01(\x05R\x0fscannedQuantity\"\xb7\x04\n" +
+	"\bOutbound\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1a\n" +
+	"\btracking\x18\x02 \x01(\tR\btracking\x12$\n" +
+	"\voutbound_by\x18\x03 \x01(\x04H\x00R\n" +
+	"outboundBy\x88\x01\x01\x12\x1e\n" +
+	"\n" +
+	"expedition\x18\x04 \x01(\tR\n" +
+	"expedition\x12)\n" +
+	"\x10expedition_color\x18\x05 \x01(\tR\x0fexpeditionColor\x12'\n" +
+	"\x0fexpedition_slug\x18\x06 \x01(\tR\x0eexpeditionSlug\x12\x1e\n" +
+	"\n" +
+	"complained\x18\a \x01(\bR\n" +
+	"complained\x12)\n" +
+	"\x0epickup_slot_id\x18\b \x01(\x04H\x01R\fpickupSlotId\x88\x01\x01\x127\n" +
+	"\tpickup_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\bpickupAt\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12H\n" +
+	"\x11outbound_operator\x18\f \x01(\v2\x1b.livo.analytics.v1.OperatorR\x10outboundOperatorB\x0e\n" +
+	"\f_outbound_byB\x11\n" +
+	"\x0f_pickup_slot_id\"=\n" +
+	"\x03Box\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"\xed\x01\n" +
+	"\bQcDetail\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x15\n" +
+	"\x06box_id\x18\x02 \x01(\x04R\x05boxId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12(\n" +
+	"\x03box\x18\x04 \x01(\v2\x16.livo.analytics.v1.BoxR\x03box\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xed\x04\n" +
+	"\bQcRibbon\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1a\n" +
+	"\btracking\x18\x02 \x01(\tR\btracking\x12#\n" +
+	"\rparcel_number\x18\x03 \x01(\x05R\fparcelNumber\x12\x18\n" +
+	"\x05qc_by\x18\x04 \x01(\x04H\x00R\x04qcBy\x88\x01\x01\x12\x1e\n" +
+	"\n" +
+	"complained\x18\x05 \x01(\bR\n" +
+	"complained\x12-\n" +
+	"\x10suggested_box_id\x18\x06 \x01(\x04H\x01R\x0esuggestedBoxId\x88\x01\x01\x124\n" +
+	"\x13suggestion_accepted\x18\a \x01(\bH\x02R\x12suggestionAccepted\x88\x01\x01\x12=\n" +
+	"\fcancelled_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vcancelledAt\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12G\n" +
+	"\x11qc_ribbon_details\x18\v \x03(\v2\x1b.livo.analytics.v1.QcDetailR\x0fqcRibbonDetails\x12<\n" +
+	"\vqc_operator\x18\f \x01(\v2\x1b.livo.analytics.v1.OperatorR\n" +
+	"qcOperatorB\b\n" +
+	"\x06_qc_byB\x13\n" +
+	"\x11_suggested_box_idB\x16\n" +
+	"\x14_suggestion_accepted\"\xed\x04\n" +
+	"\bQcOnline\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1a\n" +
+	"\btracking\x18\x02 \x01(\tR\btracking\x12#\n" +
+	"\rparcel_number\x18\x03 \x01(\x05R\fparcelNumber\x12\x18\n" +
+	"\x05qc_by\x18\x04 \x01(\x04H\x00R\x04qcBy\x88\x01\x01\x12\x1e\n" +
+	"\n" +
+	"complained\x18\x05 \x01(\bR\n" +
+	"complained\x12-\n" +
+	"\x10suggested_box_id\x18\x06 \x01(\x04H\x01R\x0esuggestedBoxId\x88\x01\x01\x124\n" +
+	"\x13suggestion_accepted\x18\a \x01(\bH\x02R\x12suggestionAccepted\x88\x01\x01\x12=\n" +
+	"\fcancelled_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vcancelledAt\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12G\n" +
+	"\x11qc_online_details\x18\v \x03(\v2\x1b.livo.analytics.v1.QcDetailR\x0fqcOnlineDetails\x12<\n" +
+	"\vqc_operator\x18\f \x01(\v2\x1b.livo.analytics.v1.OperatorR\n" +
+	"qcOperatorB\b\n" +
+	"\x06_qc_byB\x13\n" +
+	"\x11_suggested_box_idB\x16\n" +
+	"\x14_suggestion_accepted2\\\n" +
+	"\fOrderService\x12L\n" +
+	"\fStreamOrders\x12 .livo.analytics.v1.StreamRequest\x1a\x18.livo.analytics.v1.Order0\x012e\n" +
+	"\x0fOutboundService\x12R\n" +
+	"\x0fStreamOutbounds\x12 .livo.analytics.v1.StreamRequest\x1a\x1b.livo.analytics.v1.Outbound0\x012\xb3\x01\n" +
+	"\tQcService\x12R\n" +
+	"\x0fStreamQcRibbons\x12 .livo.analytics.v1.StreamRequest\x1a\x1b.livo.analytics.v1.QcRibbon0\x01\x12R\n" +
+	"\x0fStreamQcOnlines\x12 .livo.analytics.v1.StreamRequest\x1a\x1b.livo.analytics.v1.QcOnline0\x01B\x1eZ\x1clivo-backend/grpcapi;grpcapib\x06proto3"
+
+var (
+	file_analytics_proto_rawDescOnce sync.Once
+	file_analytics_proto_rawDescData []byte
+)
+
+func file_analytics_proto_rawDescGZIP() []byte {
+	file_analytics_proto_rawDescOnce.Do(func() {
+		file_analytics_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_analytics_proto_rawDesc), len(file_analytics_proto_rawDesc)))
+	})
+	return file_analytics_proto_rawDescData
+}
+
+var file_analytics_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_analytics_proto_goTypes = []any{
+	(*StreamRequest)(nil),         // 0: livo.analytics.v1.StreamRequest
+	(*Operator)(nil),              // 1: livo.analytics.v1.Operator
+	(*Order)(nil),                 // 2: livo.analytics.v1.Order
+	(*OrderDetail)(nil),           // 3: livo.analytics.v1.OrderDetail
+	(*Outbound)(nil),              // 4: livo.analytics.v1.Outbound
+	(*Box)(nil),                   // 5: livo.analytics.v1.Box
+	(*QcDetail)(nil),              // 6: livo.analytics.v1.QcDetail
+	(*QcRibbon)(nil),              // 7: livo.analytics.v1.QcRibbon
+	(*QcOnline)(nil),              // 8: livo.analytics.v1.QcOnline
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+}
+var file_analytics_proto_depIdxs = []int32{
+	9,  // 0: livo.analytics.v1.Order.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 1: livo.analytics.v1.Order.updated_at:type_name -> google.protobuf.Timestamp
+	3,  // 2: livo.analytics.v1.Order.order_details:type_name -> livo.analytics.v1.OrderDetail
+	9,  // 3: livo.analytics.v1.Outbound.pickup_at:type_name -> google.protobuf.Timestamp
+	9,  // 4: livo.analytics.v1.Outbound.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 5: livo.analytics.v1.Outbound.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 6: livo.analytics.v1.Outbound.outbound_operator:type_name -> livo.analytics.v1.Operator
+	5,  // 7: livo.analytics.v1.QcDetail.box:type_name -> livo.analytics.v1.Box
+	9,  // 8: livo.analytics.v1.QcDetail.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 9: livo.analytics.v1.QcDetail.updated_at:type_name -> google.protobuf.Timestamp
+	9,  // 10: livo.analytics.v1.QcRibbon.cancelled_at:type_name -> google.protobuf.Timestamp
+	9,  // 11: livo.analytics.v1.QcRibbon.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 12: livo.analytics.v1.QcRibbon.updated_at:type_name -> google.protobuf.Timestamp
+	6,  // 13: livo.analytics.v1.QcRibbon.qc_ribbon_details:type_name -> livo.analytics.v1.QcDetail
+	1,  // 14: livo.analytics.v1.QcRibbon.qc_operator:type_name -> livo.analytics.v1.Operator
+	9,  // 15: livo.analytics.v1.QcOnline.cancelled_at:type_name -> google.protobuf.Timestamp
+	9,  // 16: livo.analytics.v1.QcOnline.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 17: livo.analytics.v1.QcOnline.updated_at:type_name -> google.protobuf.Timestamp
+	6,  // 18: livo.analytics.v1.QcOnline.qc_online_details:type_name -> livo.analytics.v1.QcDetail
+	1,  // 19: livo.analytics.v1.QcOnline.qc_operator:type_name -> livo.analytics.v1.Operator
+	0,  // 20: livo.analytics.v1.OrderService.StreamOrders:input_type -> livo.analytics.v1.StreamRequest
+	0,  // 21: livo.analytics.v1.OutboundService.StreamOutbounds:input_type -> livo.analytics.v1.StreamRequest
+	0,  // 22: livo.analytics.v1.QcService.StreamQcRibbons:input_type -> livo.analytics.v1.StreamRequest
+	0,  // 23: livo.analytics.v1.QcService.StreamQcOnlines:input_type -> livo.analytics.v1.StreamRequest
+	2,  // 24: livo.analytics.v1.OrderService.StreamOrders:output_type -> livo.analytics.v1.Order
+	4,  // 25: livo.analytics.v1.OutboundService.StreamOutbounds:output_type -> livo.analytics.v1.Outbound
+	7,  // 26: livo.analytics.v1.QcService.StreamQcRibbons:output_type -> livo.analytics.v1.QcRibbon
+	8,  // 27: livo.analytics.v1.QcService.StreamQcOnlines:output_type -> livo.analytics.v1.QcOnline
+	24, // [24:28] is the sub-list for method output_type
+	20, // [20:24] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
+}
+
+func init() { file_analytics_proto_init() }
+func file_analytics_proto_init() {
+	if File_analytics_proto != nil {
+		return
+	}
+	file_analytics_proto_msgTypes[2].OneofWrappers = []any{}
+	file_analytics_proto_msgTypes[4].OneofWrappers = []any{}
+	file_analytics_proto_msgTypes[7].OneofWrappers = []any{}
+	file_analytics_proto_msgTypes[8].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_analytics_proto_rawDesc), len(file_analytics_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   3,
+		},
+		GoTypes:           file_analytics_proto_goTypes,
+		DependencyIndexes: file_analytics_proto_depIdxs,
+		MessageInfos:      file_analytics_proto_msgTypes,
+	}.Build()
+	File_analytics_proto = out.File
+	file_analytics_proto_goTypes = nil
+	file_analytics_proto_depIdxs = nil
+}
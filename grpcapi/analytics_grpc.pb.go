@@ -0,0 +1,387 @@
+// Read-only streams of the analytics gRPC API, served on GRPC_PORT when GRPC_ENABLED is set.
+//
+// Calls need an API key (created with POST /api/api-keys) sent as the x-api-key metadata or as
+// "authorization: Bearer <key>", allowed to read the stream's resource: orders, outbounds,
+// ribbons (QC ribbons) or onlines (QC onlines).
+//
+// Records are streamed in ID order, with the fields of the REST response of the record
+// (OrderResponse, OutboundResponse, QcRibbonResponse, QcOnlineResponse). Dates and times the REST
+// responses give as text are kept as text, in the same format.
+//
+// analytics.pb.go and analytics_grpc.pb.go are generated from this file, see go:generate in server.go.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: analytics.proto
+
+package grpcapi
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	OrderService_StreamOrders_FullMethodName = "/livo.analytics.v1.OrderService/StreamOrders"
+)
+
+// OrderServiceClient is the client API for OrderService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OrderServiceClient interface {
+	StreamOrders(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Order], error)
+}
+
+type orderServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOrderServiceClient(cc grpc.ClientConnInterface) OrderServiceClient {
+	return &orderServiceClient{cc}
+}
+
+func (c *orderServiceClient) StreamOrders(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Order], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[0], OrderService_StreamOrders_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamRequest, Order]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderService_StreamOrdersClient = grpc.ServerStreamingClient[Order]
+
+// OrderServiceServer is the server API for OrderService service.
+// All implementations must embed UnimplementedOrderServiceServer
+// for forward compatibility.
+type OrderServiceServer interface {
+	StreamOrders(*StreamRequest, grpc.ServerStreamingServer[Order]) error
+	mustEmbedUnimplementedOrderServiceServer()
+}
+
+// UnimplementedOrderServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOrderServiceServer struct{}
+
+func (UnimplementedOrderServiceServer) StreamOrders(*StreamRequest, grpc.ServerStreamingServer[Order]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamOrders not implemented")
+}
+func (UnimplementedOrderServiceServer) mustEmbedUnimplementedOrderServiceServer() {}
+func (UnimplementedOrderServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeOrderServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OrderServiceServer will
+// result in compilation errors.
+type UnsafeOrderServiceServer interface {
+	mustEmbedUnimplementedOrderServiceServer()
+}
+
+func RegisterOrderServiceServer(s grpc.ServiceRegistrar, srv OrderServiceServer) {
+	// If the following call pancis, it indicates UnimplementedOrderServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OrderService_ServiceDesc, srv)
+}
+
+func _OrderService_StreamOrders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderServiceServer).StreamOrders(m, &grpc.GenericServerStream[StreamRequest, Order]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderService_StreamOrdersServer = grpc.ServerStreamingServer[Order]
+
+// OrderService_ServiceDesc is the grpc.ServiceDesc for OrderService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OrderService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "livo.analytics.v1.OrderService",
+	HandlerType: (*OrderServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamOrders",
+			Handler:       _OrderService_StreamOrders_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "analytics.proto",
+}
+
+const (
+	OutboundService_StreamOutbounds_FullMethodName = "/livo.analytics.v1.OutboundService/StreamOutbounds"
+)
+
+// OutboundServiceClient is the client API for OutboundService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OutboundServiceClient interface {
+	StreamOutbounds(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Outbound], error)
+}
+
+type outboundServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOutboundServiceClient(cc grpc.ClientConnInterface) OutboundServiceClient {
+	return &outboundServiceClient{cc}
+}
+
+func (c *outboundServiceClient) StreamOutbounds(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Outbound], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OutboundService_ServiceDesc.Streams[0], OutboundService_StreamOutbounds_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamRequest, Outbound]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OutboundService_StreamOutboundsClient = grpc.ServerStreamingClient[Outbound]
+
+// OutboundServiceServer is the server API for OutboundService service.
+// All implementations must embed UnimplementedOutboundServiceServer
+// for forward compatibility.
+type OutboundServiceServer interface {
+	StreamOutbounds(*StreamRequest, grpc.ServerStreamingServer[Outbound]) error
+	mustEmbedUnimplementedOutboundServiceServer()
+}
+
+// UnimplementedOutboundServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOutboundServiceServer struct{}
+
+func (UnimplementedOutboundServiceServer) StreamOutbounds(*StreamRequest, grpc.ServerStreamingServer[Outbound]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamOutbounds not implemented")
+}
+func (UnimplementedOutboundServiceServer) mustEmbedUnimplementedOutboundServiceServer() {}
+func (UnimplementedOutboundServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeOutboundServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OutboundServiceServer will
+// result in compilation errors.
+type UnsafeOutboundServiceServer interface {
+	mustEmbedUnimplementedOutboundServiceServer()
+}
+
+func RegisterOutboundServiceServer(s grpc.ServiceRegistrar, srv OutboundServiceServer) {
+	// If the following call pancis, it indicates UnimplementedOutboundServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OutboundService_ServiceDesc, srv)
+}
+
+func _OutboundService_StreamOutbounds_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OutboundServiceServer).StreamOutbounds(m, &grpc.GenericServerStream[StreamRequest, Outbound]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OutboundService_StreamOutboundsServer = grpc.ServerStreamingServer[Outbound]
+
+// OutboundService_ServiceDesc is the grpc.ServiceDesc for OutboundService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OutboundService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "livo.analytics.v1.OutboundService",
+	HandlerType: (*OutboundServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamOutbounds",
+			Handler:       _OutboundService_StreamOutbounds_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "analytics.proto",
+}
+
+const (
+	QcService_StreamQcRibbons_FullMethodName = "/livo.analytics.v1.QcService/StreamQcRibbons"
+	QcService_StreamQcOnlines_FullMethodName = "/livo.analytics.v1.QcService/StreamQcOnlines"
+)
+
+// QcServiceClient is the client API for QcService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type QcServiceClient interface {
+	StreamQcRibbons(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[QcRibbon], error)
+	StreamQcOnlines(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[QcOnline], error)
+}
+
+type qcServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewQcServiceClient(cc grpc.ClientConnInterface) QcServiceClient {
+	return &qcServiceClient{cc}
+}
+
+func (c *qcServiceClient) StreamQcRibbons(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[QcRibbon], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &QcService_ServiceDesc.Streams[0], QcService_StreamQcRibbons_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamRequest, QcRibbon]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type QcService_StreamQcRibbonsClient = grpc.ServerStreamingClient[QcRibbon]
+
+func (c *qcServiceClient) StreamQcOnlines(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[QcOnline], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &QcService_ServiceDesc.Streams[1], QcService_StreamQcOnlines_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamRequest, QcOnline]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type QcService_StreamQcOnlinesClient = grpc.ServerStreamingClient[QcOnline]
+
+// QcServiceServer is the server API for QcService service.
+// All implementations must embed UnimplementedQcServiceServer
+// for forward compatibility.
+type QcServiceServer interface {
+	StreamQcRibbons(*StreamRequest, grpc.ServerStreamingServer[QcRibbon]) error
+	StreamQcOnlines(*StreamRequest, grpc.ServerStreamingServer[QcOnline]) error
+	mustEmbedUnimplementedQcServiceServer()
+}
+
+// UnimplementedQcServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedQcServiceServer struct{}
+
+func (UnimplementedQcServiceServer) StreamQcRibbons(*StreamRequest, grpc.ServerStreamingServer[QcRibbon]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamQcRibbons not implemented")
+}
+func (UnimplementedQcServiceServer) StreamQcOnlines(*StreamRequest, grpc.ServerStreamingServer[QcOnline]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamQcOnlines not implemented")
+}
+func (UnimplementedQcServiceServer) mustEmbedUnimplementedQcServiceServer() {}
+func (UnimplementedQcServiceServer) testEmbeddedByValue()                   {}
+
+// UnsafeQcServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to QcServiceServer will
+// result in compilation errors.
+type UnsafeQcServiceServer interface {
+	mustEmbedUnimplementedQcServiceServer()
+}
+
+func RegisterQcServiceServer(s grpc.ServiceRegistrar, srv QcServiceServer) {
+	// If the following call pancis, it indicates UnimplementedQcServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&QcService_ServiceDesc, srv)
+}
+
+func _QcService_StreamQcRibbons_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QcServiceServer).StreamQcRibbons(m, &grpc.GenericServerStream[StreamRequest, QcRibbon]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type QcService_StreamQcRibbonsServer = grpc.ServerStreamingServer[QcRibbon]
+
+func _QcService_StreamQcOnlines_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QcServiceServer).StreamQcOnlines(m, &grpc.GenericServerStream[StreamRequest, QcOnline]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type QcService_StreamQcOnlinesServer = grpc.ServerStreamingServer[QcOnline]
+
+// QcService_ServiceDesc is the grpc.ServiceDesc for QcService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var QcService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "livo.analytics.v1.QcService",
+	HandlerType: (*QcServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamQcRibbons",
+			Handler:       _QcService_StreamQcRibbons_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamQcOnlines",
+			Handler:       _QcService_StreamQcOnlines_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "analytics.proto",
+}
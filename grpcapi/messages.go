@@ -0,0 +1,167 @@
+package grpcapi
+
+import (
+	"livo-backend/models"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func orderMessage(order models.OrderResponse) *Order {
+	details := make([]*OrderDetail, len(order.OrderDetails))
+	for i, detail := range order.OrderDetails {
+		details[i] = &OrderDetail{
+			Id:              uint64(detail.ID),
+			Sku:             detail.Sku,
+			ProductName:     detail.ProductName,
+			Variant:         detail.Variant,
+			Quantity:        int32(detail.Quantity),
+			Price:           int64(detail.Price),
+			ScannedQuantity: int32(detail.ScannedQuantity),
+		}
+	}
+
+	return &Order{
+		Id:               uint64(order.ID),
+		OrderGineeId:     order.OrderGineeID,
+		ProcessingStatus: order.ProcessingStatus,
+		EventStatus:      order.EventStatus,
+		Channel:          order.Channel,
+		Store:            order.Store,
+		Buyer:            order.Buyer,
+		BuyerPhone:       order.BuyerPhone,
+		Address:          order.Address,
+		Province:         order.Province,
+		City:             order.City,
+		PostalCode:       order.PostalCode,
+		AddressStatus:    order.AddressStatus,
+		Courier:          order.Courier,
+		Tracking:         order.Tracking,
+		SentBefore:       order.SentBefore,
+		Complained:       order.Complained,
+		AssignedBy:       order.AssignedBy,
+		AssignedAt:       order.AssignedAt,
+		PickedBy:         order.PickedBy,
+		PickedAt:         order.PickedAt,
+		PendingBy:        order.PendingBy,
+		PendingAt:        order.PendingAt,
+		ChangedBy:        order.ChangedBy,
+		ChangedAt:        order.ChangedAt,
+		CancelledBy:      order.CancelledBy,
+		CancelledAt:      order.CancelledAt,
+		DeliveryStatus:   order.DeliveryStatus,
+		DeliveryStatusAt: order.DeliveryStatusAt,
+		Instant:          order.Instant,
+		SlaDueAt:         order.SLADueAt,
+		SlaBreached:      order.SLABreached,
+		Priority:         order.Priority,
+		AtRisk:           order.AtRisk,
+		AtRiskAt:         order.AtRiskAt,
+		ParcelCount:      int32(order.ParcelCount),
+		MergedIntoId:     optionalID(order.MergedIntoID),
+		MergedAt:         order.MergedAt,
+		Held:             order.Held,
+		HeldAt:           order.HeldAt,
+		HoldReason:       order.HoldReason,
+		Version:          uint64(order.Version),
+		CreatedAt:        timestamppb.New(order.CreatedAt),
+		UpdatedAt:        timestamppb.New(order.UpdatedAt),
+		OrderDetails:     details,
+	}
+}
+
+func outboundMessage(outbound models.OutboundResponse) *Outbound {
+	return &Outbound{
+		Id:               uint64(outbound.ID),
+		Tracking:         outbound.Tracking,
+		OutboundBy:       optionalID(outbound.OutboundBy),
+		Expedition:       outbound.Expedition,
+		ExpeditionColor:  outbound.ExpeditionColor,
+		ExpeditionSlug:   outbound.ExpeditionSlug,
+		Complained:       outbound.Complained,
+		PickupSlotId:     optionalID(outbound.PickupSlotID),
+		PickupAt:         optionalTimestamp(outbound.PickupAt),
+		CreatedAt:        timestamppb.New(outbound.CreatedAt),
+		UpdatedAt:        timestamppb.New(outbound.UpdatedAt),
+		OutboundOperator: operatorMessage(outbound.OutboundOperator),
+	}
+}
+
+func qcRibbonMessage(qc models.QcRibbonResponse) *QcRibbon {
+	details := make([]*QcDetail, len(qc.QcRibbonDetails))
+	for i, detail := range qc.QcRibbonDetails {
+		details[i] = qcDetailMessage(detail.ID, detail.BoxID, detail.Quantity, detail.Box, detail.CreatedAt, detail.UpdatedAt)
+	}
+
+	return &QcRibbon{
+		Id:                 uint64(qc.ID),
+		Tracking:           qc.Tracking,
+		ParcelNumber:       int32(qc.ParcelNumber),
+		QcBy:               optionalID(qc.QcBy),
+		Complained:         qc.Complained,
+		SuggestedBoxId:     optionalID(qc.SuggestedBoxID),
+		SuggestionAccepted: qc.SuggestionAccepted,
+		CancelledAt:        optionalTimestamp(qc.CancelledAt),
+		CreatedAt:          timestamppb.New(qc.CreatedAt),
+		UpdatedAt:          timestamppb.New(qc.UpdatedAt),
+		QcRibbonDetails:    details,
+		QcOperator:         operatorMessage(qc.QcOperator),
+	}
+}
+
+func qcOnlineMessage(qc models.QcOnlineResponse) *QcOnline {
+	details := make([]*QcDetail, len(qc.QcOnlineDetails))
+	for i, detail := range qc.QcOnlineDetails {
+		details[i] = qcDetailMessage(detail.ID, detail.BoxID, detail.Quantity, detail.Box, detail.CreatedAt, detail.UpdatedAt)
+	}
+
+	return &QcOnline{
+		Id:                 uint64(qc.ID),
+		Tracking:           qc.Tracking,
+		ParcelNumber:       int32(qc.ParcelNumber),
+		QcBy:               optionalID(qc.QcBy),
+		Complained:         qc.Complained,
+		SuggestedBoxId:     optionalID(qc.SuggestedBoxID),
+		SuggestionAccepted: qc.SuggestionAccepted,
+		CancelledAt:        optionalTimestamp(qc.CancelledAt),
+		CreatedAt:          timestamppb.New(qc.CreatedAt),
+		UpdatedAt:          timestamppb.New(qc.UpdatedAt),
+		QcOnlineDetails:    details,
+		QcOperator:         operatorMessage(qc.QcOperator),
+	}
+}
+
+// qcDetailMessage converts a box line of a QC ribbon or QC online
+func qcDetailMessage(id, boxID uint, quantity int, box models.BoxResponse, createdAt, updatedAt time.Time) *QcDetail {
+	return &QcDetail{
+		Id:        uint64(id),
+		BoxId:     uint64(boxID),
+		Quantity:  int32(quantity),
+		Box:       &Box{Id: uint64(box.ID), Code: box.Code, Name: box.Name},
+		CreatedAt: timestamppb.New(createdAt),
+		UpdatedAt: timestamppb.New(updatedAt),
+	}
+}
+
+// operatorMessage converts the user of a record, nil when it was not loaded
+func operatorMessage(user *models.UserResponse) *Operator {
+	if user == nil {
+		return nil
+	}
+	return &Operator{Id: uint64(user.ID), Username: user.Username, FullName: user.FullName}
+}
+
+func optionalID(id *uint) *uint64 {
+	if id == nil {
+		return nil
+	}
+	value := uint64(*id)
+	return &value
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
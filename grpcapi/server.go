@@ -0,0 +1,93 @@
+// Package grpcapi serves the read-only gRPC API the analytics pipeline streams bulk data from.
+//
+// The services and their messages are declared in analytics.proto, the Go code for them is generated
+// with protoc-gen-go and protoc-gen-go-grpc. Clients generate their stubs from the same file. Every
+// streamed message is one record with the fields of its REST response.
+package grpcapi
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative analytics.proto
+
+import (
+	"context"
+	"errors"
+	"livo-backend/models"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// Server streams records from the database
+type Server struct {
+	UnimplementedOrderServiceServer
+	UnimplementedOutboundServiceServer
+	UnimplementedQcServiceServer
+	DB *gorm.DB
+}
+
+// NewServer returns a gRPC server with the analytics services registered. Callers authenticate
+// with an API key (the x-api-key metadata or "authorization: Bearer <key>") that may read the
+// resource of the stream: orders, outbounds, ribbons or onlines.
+func NewServer(db *gorm.DB) *grpc.Server {
+	s := &Server{DB: db}
+	server := grpc.NewServer(grpc.StreamInterceptor(s.authenticate))
+	RegisterOrderServiceServer(server, s)
+	RegisterOutboundServiceServer(server, s)
+	RegisterQcServiceServer(server, s)
+	return server
+}
+
+// authenticate rejects calls without an active API key allowed to read the stream's resource
+func (s *Server) authenticate(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	resource, found := streamResources[info.FullMethod]
+	if !found {
+		return status.Errorf(codes.Unimplemented, "unknown method %s", info.FullMethod)
+	}
+
+	key := apiKeyFromMetadata(stream.Context())
+	if key == "" {
+		return status.Error(codes.Unauthenticated, "api key required")
+	}
+
+	apiKey, err := models.AuthenticateAPIKey(s.DB.WithContext(stream.Context()), key, clientIP(stream.Context()))
+	if err != nil {
+		if errors.Is(err, models.ErrAPIKeyInvalid) || errors.Is(err, models.ErrAPIKeyRevoked) || errors.Is(err, models.ErrAPIKeyExpired) {
+			return status.Error(codes.Unauthenticated, err.Error())
+		}
+		return status.Errorf(codes.Internal, "failed to authenticate api key: %v", err)
+	}
+	if !apiKey.Allows(resource, models.APIKeyActionRead) {
+		return status.Errorf(codes.PermissionDenied, "api key may not read %s", resource)
+	}
+
+	return handler(srv, stream)
+}
+
+func apiKeyFromMetadata(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("x-api-key"); len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+	if values := md.Get("authorization"); len(values) > 0 {
+		if key, found := strings.CutPrefix(values[0], "Bearer "); found {
+			return strings.TrimSpace(key)
+		}
+	}
+	return ""
+}
+
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	address := p.Addr.String()
+	if i := strings.LastIndex(address, ":"); i > 0 {
+		address = address[:i]
+	}
+	return strings.Trim(address, "[]")
+}
@@ -0,0 +1,158 @@
+package grpcapi
+
+import (
+	"fmt"
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// Batch sizes of the streams: records are read from the database this many at a time
+const (
+	defaultBatchSize = 500
+	maxBatchSize     = 5000
+)
+
+// streamResources maps each stream to the API key resource its callers must be allowed to read
+var streamResources = map[string]string{
+	OrderService_StreamOrders_FullMethodName:       "orders",
+	OutboundService_StreamOutbounds_FullMethodName: "outbounds",
+	QcService_StreamQcRibbons_FullMethodName:       "ribbons",
+	QcService_StreamQcOnlines_FullMethodName:       "onlines",
+}
+
+// streamFilter selects the records of a stream, see StreamRequest in analytics.proto
+type streamFilter struct {
+	Start        time.Time // Created at or after, zero for no bound
+	End          time.Time // Created before, zero for no bound
+	UpdatedSince time.Time // Updated at or after, zero for no bound
+	AfterID      uint
+	BatchSize    int
+}
+
+// parseStreamFilter reads the filter from the request: start_date and end_date (YYYY-MM-DD, both
+// inclusive), updated_since (RFC 3339), after_id and batch_size
+func parseStreamFilter(request *StreamRequest) (streamFilter, error) {
+	filter := streamFilter{BatchSize: defaultBatchSize, AfterID: uint(request.GetAfterId())}
+
+	if value := request.GetStartDate(); value != "" {
+		date, err := utilities.ParseDate(value)
+		if err != nil {
+			return filter, fmt.Errorf("start_date must be in YYYY-MM-DD format")
+		}
+		filter.Start, _ = utilities.DayRange(date)
+	}
+	if value := request.GetEndDate(); value != "" {
+		date, err := utilities.ParseDate(value)
+		if err != nil {
+			return filter, fmt.Errorf("end_date must be in YYYY-MM-DD format")
+		}
+		_, filter.End = utilities.DayRange(date)
+	}
+	if value := request.GetUpdatedSince(); value != "" {
+		since, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("updated_since must be an RFC 3339 timestamp")
+		}
+		filter.UpdatedSince = since
+	}
+	if size := request.GetBatchSize(); size != 0 {
+		if size > maxBatchSize {
+			return filter, fmt.Errorf("batch_size must be between 1 and %d", maxBatchSize)
+		}
+		filter.BatchSize = int(size)
+	}
+
+	return filter, nil
+}
+
+// scope applies the filter to the query of table
+func (f streamFilter) scope(table string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if !f.Start.IsZero() {
+			db = db.Where(table+".created_at >= ?", f.Start)
+		}
+		if !f.End.IsZero() {
+			db = db.Where(table+".created_at < ?", f.End)
+		}
+		if !f.UpdatedSince.IsZero() {
+			db = db.Where(table+".updated_at >= ?", f.UpdatedSince)
+		}
+		return db
+	}
+}
+
+// StreamOrders streams the orders with their details, picker and assigner
+func (s *Server) StreamOrders(request *StreamRequest, stream grpc.ServerStreamingServer[Order]) error {
+	return streamRecords(s, request, stream, "orders",
+		func(db *gorm.DB) *gorm.DB {
+			return db.Preload("OrderDetails").Preload("PickOperator").Preload("AssignOperator")
+		},
+		func(order *models.Order) (uint, *Order) { return order.ID, orderMessage(order.ToOrderResponse()) })
+}
+
+// StreamOutbounds streams the outbounds with their operator
+func (s *Server) StreamOutbounds(request *StreamRequest, stream grpc.ServerStreamingServer[Outbound]) error {
+	return streamRecords(s, request, stream, "outbounds",
+		func(db *gorm.DB) *gorm.DB { return db.Preload("OutboundOperator") },
+		func(outbound *models.Outbound) (uint, *Outbound) {
+			return outbound.ID, outboundMessage(outbound.ToOutboundResponse())
+		})
+}
+
+// StreamQcRibbons streams the QC ribbons with their boxes and operator
+func (s *Server) StreamQcRibbons(request *StreamRequest, stream grpc.ServerStreamingServer[QcRibbon]) error {
+	return streamRecords(s, request, stream, "qc_ribbons",
+		func(db *gorm.DB) *gorm.DB { return db.Preload("QcRibbonDetails.Box").Preload("QcOperator") },
+		func(qc *models.QcRibbon) (uint, *QcRibbon) { return qc.ID, qcRibbonMessage(qc.ToQcRibbonResponse()) })
+}
+
+// StreamQcOnlines streams the QC onlines with their boxes and operator
+func (s *Server) StreamQcOnlines(request *StreamRequest, stream grpc.ServerStreamingServer[QcOnline]) error {
+	return streamRecords(s, request, stream, "qc_onlines",
+		func(db *gorm.DB) *gorm.DB { return db.Preload("QcOnlineDetails.Box").Preload("QcOperator") },
+		func(qc *models.QcOnline) (uint, *QcOnline) { return qc.ID, qcOnlineMessage(qc.ToQcOnlineResponse()) })
+}
+
+// streamRecords sends every record of table matching the request in ID order, batch by batch,
+// converted to its message. Clients resume an interrupted stream with the ID of the last record
+// they received as after_id.
+func streamRecords[T any, M any](s *Server, request *StreamRequest, stream grpc.ServerStreamingServer[M], table string, preload func(*gorm.DB) *gorm.DB, convert func(*T) (uint, *M)) error {
+	filter, err := parseStreamFilter(request)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	db := s.DB.WithContext(stream.Context())
+	afterID := filter.AfterID
+	for {
+		var records []T
+		if err := db.Scopes(filter.scope(table), preload).
+			Where(table+".id > ?", afterID).
+			Order(table + ".id").
+			Limit(filter.BatchSize).
+			Find(&records).Error; err != nil {
+			if stream.Context().Err() != nil {
+				return status.FromContextError(stream.Context().Err()).Err()
+			}
+			return status.Errorf(codes.Internal, "failed to read %s: %v", table, err)
+		}
+
+		for i := range records {
+			id, message := convert(&records[i])
+			if err := stream.Send(message); err != nil {
+				return err
+			}
+			afterID = id
+		}
+
+		if len(records) < filter.BatchSize {
+			return nil
+		}
+	}
+}
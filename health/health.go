@@ -0,0 +1,198 @@
+package health
+
+import (
+	"fmt"
+	"livo-backend/backup"
+	"livo-backend/config"
+	"livo-backend/migrations"
+	"livo-backend/models"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Overall and per check statuses
+const (
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+	StatusFailing     = "failing"
+	StatusSkipped     = "skipped"
+)
+
+// Check is the result of one readiness probe
+type Check struct {
+	Status     string      `json:"status" example:"ok"`
+	Message    string      `json:"message,omitempty"`
+	DurationMs int64       `json:"duration_ms" example:"3"`
+	Details    interface{} `json:"details,omitempty"`
+}
+
+// Report is the body of the health endpoints
+type Report struct {
+	Status    string           `json:"status" example:"ok"`
+	Checks    map[string]Check `json:"checks,omitempty"`
+	Timestamp string           `json:"timestamp"`
+}
+
+// Ready reports whether the service can take traffic
+func (r Report) Ready() bool {
+	return r.Status != StatusUnavailable
+}
+
+// Live reports that the process is up, without touching any dependency
+func Live() Report {
+	return Report{Status: StatusOK, Timestamp: time.Now().Format("02 January 2006 - 15:04:05")}
+}
+
+// Ready probes the dependencies needed to serve requests. The service is unavailable when the
+// database is unreachable, migrations are pending or the upload disk is almost full, and degraded
+// (still ready) when backups are stale or failing or an integration's error rate is too high.
+func Ready(db *gorm.DB, cfg *config.Config) Report {
+	report := Report{Status: StatusOK, Checks: make(map[string]Check)}
+	fail := func(name string, check Check) {
+		report.Checks[name] = check
+		if check.Status == StatusFailing {
+			report.Status = StatusUnavailable
+		}
+	}
+
+	database := timed(func() Check { return checkDatabase(db) })
+	fail("database", database)
+
+	if database.Status == StatusOK {
+		fail("migrations", timed(func() Check { return checkMigrations(db) }))
+
+		backupCheck := timed(func() Check {
+			health := backup.CheckHealth(db, cfg)
+			return Check{Status: health.Status, Message: health.Message, Details: health}
+		})
+		report.Checks["backup"] = backupCheck
+		if (backupCheck.Status == backup.HealthStale || backupCheck.Status == backup.HealthFailing) && report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+
+		integrations := timed(func() Check { return checkIntegrations(db) })
+		report.Checks["integrations"] = integrations
+		if integrations.Status == StatusDegraded && report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	} else {
+		report.Checks["migrations"] = Check{Status: StatusSkipped, Message: "database unreachable"}
+		report.Checks["backup"] = Check{Status: StatusSkipped, Message: "database unreachable"}
+		report.Checks["integrations"] = Check{Status: StatusSkipped, Message: "database unreachable"}
+	}
+
+	fail("disk", timed(func() Check { return checkDisk(cfg.UploadDir, cfg.HealthMinFreeDiskMB) }))
+
+	report.Timestamp = time.Now().Format("02 January 2006 - 15:04:05")
+	return report
+}
+
+func timed(probe func() Check) Check {
+	start := time.Now()
+	check := probe()
+	check.DurationMs = time.Since(start).Milliseconds()
+	return check
+}
+
+func checkDatabase(db *gorm.DB) Check {
+	sqlDB, err := db.DB()
+	if err == nil {
+		err = sqlDB.Ping()
+	}
+	if err != nil {
+		return Check{Status: StatusFailing, Message: err.Error()}
+	}
+	return Check{Status: StatusOK}
+}
+
+// checkIntegrations reports the error rates of the integrations called in the health window
+func checkIntegrations(db *gorm.DB) Check {
+	statuses, err := models.IntegrationStatuses(db, time.Now())
+	if err != nil {
+		return Check{Status: StatusDegraded, Message: err.Error()}
+	}
+
+	var degraded []string
+	for _, status := range statuses {
+		if status.Status == models.IntegrationStatusDegraded {
+			degraded = append(degraded, status.Integration)
+		}
+	}
+	if len(degraded) > 0 {
+		return Check{Status: StatusDegraded, Message: "degraded: " + strings.Join(degraded, ", "), Details: statuses}
+	}
+	return Check{Status: StatusOK, Details: statuses}
+}
+
+// Migrations only change on deploy, so once the schema is complete it is not checked again for the
+// life of the process
+var (
+	migrationsMu       sync.Mutex
+	migrationsComplete bool
+)
+
+func checkMigrations(db *gorm.DB) Check {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+	if migrationsComplete {
+		return Check{Status: StatusOK}
+	}
+
+	pending, err := migrations.PendingMigrations(db)
+	if err != nil {
+		return Check{Status: StatusFailing, Message: err.Error()}
+	}
+	if len(pending) > 0 {
+		return Check{
+			Status:  StatusFailing,
+			Message: fmt.Sprintf("%d pending migration(s): %s", len(pending), strings.Join(pending, ", ")),
+			Details: pending,
+		}
+	}
+
+	migrationsComplete = true
+	return Check{Status: StatusOK}
+}
+
+// DiskSpace is the space left on the disk holding a directory
+type DiskSpace struct {
+	Path        string `json:"path" example:"./uploads"`
+	FreeMB      uint64 `json:"free_mb" example:"10240"`
+	TotalMB     uint64 `json:"total_mb" example:"51200"`
+	MinimumMB   int    `json:"minimum_mb" example:"500"`
+	UsedPercent int    `json:"used_percent" example:"80"`
+}
+
+func checkDisk(dir string, minFreeMB int) Check {
+	if minFreeMB <= 0 {
+		return Check{Status: StatusSkipped, Message: "disk check disabled"}
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return Check{Status: StatusFailing, Message: fmt.Sprintf("upload directory unavailable: %v", err)}
+	}
+
+	free, total, err := diskUsage(dir)
+	if err != nil {
+		return Check{Status: StatusSkipped, Message: err.Error()}
+	}
+
+	space := DiskSpace{
+		Path:      dir,
+		FreeMB:    free / (1 << 20),
+		TotalMB:   total / (1 << 20),
+		MinimumMB: minFreeMB,
+	}
+	if total > 0 {
+		space.UsedPercent = int((total - free) * 100 / total)
+	}
+
+	if space.FreeMB < uint64(minFreeMB) {
+		return Check{Status: StatusFailing, Message: fmt.Sprintf("only %d MB free for uploads, %d MB required", space.FreeMB, minFreeMB), Details: space}
+	}
+	return Check{Status: StatusOK, Details: space}
+}
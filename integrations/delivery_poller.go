@@ -0,0 +1,98 @@
+package integrations
+
+import (
+	"context"
+	"livo-backend/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PollDeliveryStatuses pulls delivery status for every outbound tracking whose expedition
+// has a tracking provider configured and whose order has not reached a final status yet
+func PollDeliveryStatuses(ctx context.Context, db *gorm.DB, maxAge time.Duration, batchSize int) error {
+	var expeditions []models.Expedition
+	if err := db.Where("tracking_provider <> ''").Find(&expeditions).Error; err != nil {
+		return err
+	}
+
+	for _, expedition := range expeditions {
+		provider, ok := GetTrackingProvider(expedition.TrackingProvider)
+		if !ok {
+			log.Printf("⚠️ Unknown tracking provider %q on expedition %s", expedition.TrackingProvider, expedition.Code)
+			continue
+		}
+
+		var orders []models.Order
+		if err := db.Model(&models.Order{}).
+			Joins("JOIN outbounds ON outbounds.tracking = orders.tracking AND outbounds.deleted_at IS NULL").
+			Where("outbounds.expedition = ?", expedition.Code).
+			Where("outbounds.created_at >= ?", time.Now().Add(-maxAge)).
+			Where("orders.delivery_status IS NULL OR orders.delivery_status NOT IN ?", models.FinalDeliveryStatuses).
+			Order("orders.delivery_status_at ASC NULLS FIRST").
+			Limit(batchSize).
+			Find(&orders).Error; err != nil {
+			return err
+		}
+
+		for i := range orders {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			if err := pullOrderDeliveryStatus(ctx, db, provider, expedition, &orders[i]); err != nil {
+				log.Printf("⚠️ Failed to pull delivery status for %s: %v", orders[i].Tracking, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func pullOrderDeliveryStatus(ctx context.Context, db *gorm.DB, provider TrackingProvider, expedition models.Expedition, order *models.Order) error {
+	result, err := provider.FetchTracking(ctx, expedition, order.Tracking)
+	if ctx.Err() == nil {
+		if recordErr := models.RecordIntegrationCall(db, models.IntegrationCourierAPI, err); recordErr != nil {
+			log.Printf("⚠️ Failed to record courier API call: %v", recordErr)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	status := result.Status
+	if status == "" && len(result.Events) > 0 {
+		status = result.Events[len(result.Events)-1].Status
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if len(result.Events) > 0 {
+			histories := make([]models.DeliveryHistory, len(result.Events))
+			for i, event := range result.Events {
+				histories[i] = models.DeliveryHistory{
+					OrderID:     order.ID,
+					Tracking:    order.Tracking,
+					Provider:    expedition.TrackingProvider,
+					Status:      event.Status,
+					Description: event.Description,
+					Location:    event.Location,
+					EventAt:     event.Time,
+				}
+			}
+
+			// Providers return the full history on every call, keep only unseen checkpoints
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&histories).Error; err != nil {
+				return err
+			}
+		}
+
+		updates := map[string]interface{}{"delivery_status_at": now}
+		if status != "" {
+			updates["delivery_status"] = status
+		}
+		return tx.Model(order).Updates(updates).Error
+	})
+}
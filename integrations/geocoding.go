@@ -0,0 +1,101 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"livo-backend/models"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// geocoderFactories build the geocoding providers order addresses can be validated with, by name
+var geocoderFactories = map[string]func(endpoint, apiKey string) (models.AddressGeocoder, error){
+	"generic": newHTTPGeocoder,
+}
+
+// NewGeocoder returns the geocoding provider registered under name, calling endpoint with apiKey. An
+// empty name disables address validation and returns nil.
+func NewGeocoder(name, endpoint, apiKey string) (models.AddressGeocoder, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return nil, nil
+	}
+
+	factory, ok := geocoderFactories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown geocoding provider %q", name)
+	}
+	return factory(endpoint, apiKey)
+}
+
+// httpGeocoder calls endpoint with ?address= and decodes the flat payload
+// {"province": "...", "city": "...", "postal_code": "..."}. A 404 or a payload without a city means
+// the address could not be placed.
+type httpGeocoder struct {
+	client   *http.Client
+	endpoint *url.URL
+	apiKey   string
+}
+
+func newHTTPGeocoder(endpoint, apiKey string) (models.AddressGeocoder, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid geocoding endpoint %q", endpoint)
+	}
+	return &httpGeocoder{client: defaultHTTPClient, endpoint: parsed, apiKey: apiKey}, nil
+}
+
+func (g *httpGeocoder) Geocode(ctx context.Context, address string) (*models.GeocodedAddress, error) {
+	endpoint := *g.endpoint
+	query := endpoint.Query()
+	query.Set("address", address)
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if g.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+g.apiKey)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, models.ErrAddressNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoding endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		Province   string `json:"province"`
+		City       string `json:"city"`
+		PostalCode string `json:"postal_code"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(payload.City) == "" {
+		return nil, models.ErrAddressNotFound
+	}
+
+	return &models.GeocodedAddress{
+		Province:   strings.TrimSpace(payload.Province),
+		City:       strings.TrimSpace(payload.City),
+		PostalCode: strings.TrimSpace(payload.PostalCode),
+	}, nil
+}
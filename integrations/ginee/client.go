@@ -0,0 +1,131 @@
+package ginee
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"livo-backend/config"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Ginee OpenAPI paths
+const listOrdersPath = "/openapi/order/v1/list"
+
+// paidOrderStatuses are the Ginee order statuses of orders paid and waiting to be shipped
+var paidOrderStatuses = []string{"PAID", "READY_TO_SHIP"}
+
+// Client calls the Ginee OpenAPI with the keys of an OpenAPI app
+type Client struct {
+	baseURL   string
+	accessKey string
+	secretKey string
+	country   string
+	pageSize  int
+	http      *http.Client
+}
+
+// NewClient returns a client for the Ginee OpenAPI configured in cfg
+func NewClient(cfg *config.Config) *Client {
+	pageSize := cfg.GineePageSize
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 100
+	}
+	return &Client{
+		baseURL:   strings.TrimRight(cfg.GineeAPIURL, "/"),
+		accessKey: cfg.GineeAccessKey,
+		secretKey: cfg.GineeSecretKey,
+		country:   cfg.GineeCountry,
+		pageSize:  pageSize,
+		http:      &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Configured reports whether the client has the keys it needs to authenticate
+func (c *Client) Configured() bool {
+	return c.baseURL != "" && c.accessKey != "" && c.secretKey != ""
+}
+
+// OrderPage is one page of the order list
+type OrderPage struct {
+	Orders []Order `json:"content"`
+	Page   int     `json:"page"`
+	Size   int     `json:"size"`
+	Total  int     `json:"total"`
+}
+
+// ListPaidOrders returns page (from 0) of the paid orders updated between since and until
+func (c *Client) ListPaidOrders(ctx context.Context, since, until time.Time, page int) (*OrderPage, error) {
+	body := map[string]interface{}{
+		"page":            page,
+		"size":            c.pageSize,
+		"orderStatus":     paidOrderStatuses,
+		"lastUpdateSince": since.UTC().Format(time.RFC3339),
+		"lastUpdateTo":    until.UTC().Format(time.RFC3339),
+	}
+	var result OrderPage
+	if err := c.post(ctx, listOrdersPath, body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// post calls path with body signed with the app keys and decodes the data of the answer into out
+func (c *Client) post(ctx context.Context, path string, body interface{}, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", c.accessKey+":"+c.sign(http.MethodPost, path))
+	req.Header.Set("X-Advai-Country", c.country)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ginee %s returned %d: %s", path, resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+
+	var envelope struct {
+		Code    string          `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return fmt.Errorf("invalid ginee %s response: %w", path, err)
+	}
+	if envelope.Code != "SUCCESS" {
+		return fmt.Errorf("ginee %s failed: %s %s", path, envelope.Code, envelope.Message)
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("invalid ginee %s data: %w", path, err)
+	}
+	return nil
+}
+
+// sign returns the request signature: the base64 HMAC-SHA256 of "METHOD$path$" keyed with the secret key
+func (c *Client) sign(method, path string) string {
+	mac := hmac.New(sha256.New, []byte(c.secretKey))
+	mac.Write([]byte(method + "$" + path + "$"))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
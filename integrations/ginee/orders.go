@@ -0,0 +1,116 @@
+package ginee
+
+import (
+	"errors"
+	"fmt"
+	"livo-backend/models"
+	"strings"
+	"time"
+)
+
+// errNoTracking marks orders without an airway bill yet. They are skipped and picked up by a later
+// run, getting the airway bill updates the order on Ginee.
+var errNoTracking = errors.New("no tracking number yet")
+
+// Order is an order of the Ginee order list, with the fields the intake reads
+type Order struct {
+	OrderID         string `json:"orderId"`
+	Channel         string `json:"channel"`
+	ShopName        string `json:"shopName"`
+	OrderStatus     string `json:"orderStatus"`
+	ShipByDate      string `json:"shipByDate"`
+	CustomerName    string `json:"customerName"`
+	ShippingAddress struct {
+		Name        string `json:"name"`
+		Mobile      string `json:"mobile"`
+		FullAddress string `json:"fullAddress"`
+	} `json:"shippingAddressInfo"`
+	LogisticsInfos []struct {
+		LogisticsProviderName   string `json:"logisticsProviderName"`
+		LogisticsTrackingNumber string `json:"logisticsTrackingNumber"`
+	} `json:"logisticsInfos"`
+	Items []struct {
+		MasterSku     string  `json:"masterSku"`
+		Sku           string  `json:"sku"`
+		ProductName   string  `json:"productName"`
+		VariationName string  `json:"variationName"`
+		Quantity      int     `json:"quantity"`
+		ActualPrice   float64 `json:"actualPrice"`
+	} `json:"items"`
+}
+
+// channelNames maps Ginee channel codes to the channel names used by the warehouse
+var channelNames = map[string]string{
+	"SHOPEE_ID":    "Shopee",
+	"TOKOPEDIA_ID": "Tokopedia",
+	"LAZADA_ID":    "Lazada",
+	"TIKTOK_ID":    "TikTok",
+	"BLIBLI_ID":    "Blibli",
+}
+
+// channelName returns the warehouse name of a Ginee channel code, the code itself when unknown
+func channelName(code string) string {
+	if name, found := channelNames[strings.ToUpper(code)]; found {
+		return name
+	}
+	return code
+}
+
+// toOrder maps a Ginee order to an order ready to pick, as the bulk order upload creates them
+func toOrder(o Order) (models.Order, error) {
+	order := models.Order{
+		OrderGineeID:     strings.TrimSpace(o.OrderID),
+		ProcessingStatus: "ready to pick",
+		Channel:          channelName(o.Channel),
+		Store:            o.ShopName,
+		Buyer:            o.ShippingAddress.Name,
+		BuyerPhone:       o.ShippingAddress.Mobile,
+		Address:          o.ShippingAddress.FullAddress,
+	}
+	if order.OrderGineeID == "" {
+		return order, fmt.Errorf("order has no ID")
+	}
+	if order.Buyer == "" {
+		order.Buyer = o.CustomerName
+	}
+
+	for _, logistics := range o.LogisticsInfos {
+		if tracking := strings.TrimSpace(logistics.LogisticsTrackingNumber); tracking != "" {
+			order.Courier = logistics.LogisticsProviderName
+			order.Tracking = tracking
+			break
+		}
+	}
+	if order.Tracking == "" {
+		return order, errNoTracking
+	}
+
+	if o.ShipByDate != "" {
+		shipBy, err := time.Parse(time.RFC3339, o.ShipByDate)
+		if err != nil {
+			return order, fmt.Errorf("invalid ship by date %q: %w", o.ShipByDate, err)
+		}
+		order.SentBefore = shipBy
+	}
+
+	for _, item := range o.Items {
+		sku := item.MasterSku
+		if sku == "" {
+			sku = item.Sku
+		}
+		if sku == "" || item.Quantity <= 0 {
+			return order, fmt.Errorf("item %q has no SKU or quantity", item.ProductName)
+		}
+		order.OrderDetails = append(order.OrderDetails, models.OrderDetail{
+			Sku:         sku,
+			ProductName: item.ProductName,
+			Variant:     item.VariationName,
+			Quantity:    item.Quantity,
+			Price:       int(item.ActualPrice),
+		})
+	}
+	if len(order.OrderDetails) == 0 {
+		return order, fmt.Errorf("order has no items")
+	}
+	return order, nil
+}
@@ -0,0 +1,154 @@
+package ginee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"livo-backend/config"
+	"livo-backend/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrSyncRunning is returned by Start while another run is in progress
+var ErrSyncRunning = errors.New("another ginee sync is still running")
+
+// ErrSyncDisabled is returned by Start when the sync is disabled or the keys are missing
+var ErrSyncDisabled = errors.New("ginee sync is disabled or not configured")
+
+// staleAfter is how long a running record may go without progress before its process is assumed dead
+const staleAfter = 15 * time.Minute
+
+// windowOverlap starts every window a little before the end of the previous one, so orders updated
+// while it was polling are not missed. Orders fetched twice are skipped as known.
+const windowOverlap = 5 * time.Minute
+
+// Start records a new running sync covering the orders updated since the end of the last completed
+// run, or the configured lookback on the first run. Running records without progress for a while are
+// assumed dead and marked as failed first.
+func Start(db *gorm.DB, cfg *config.Config, triggeredBy *uint) (*models.GineeSyncRun, error) {
+	if !cfg.GineeSyncEnabled || !NewClient(cfg).Configured() {
+		return nil, ErrSyncDisabled
+	}
+
+	now := time.Now()
+	if err := db.Model(&models.GineeSyncRun{}).
+		Where("status = ? AND updated_at < ?", models.GineeSyncStatusRunning, now.Add(-staleAfter)).
+		Updates(map[string]interface{}{"status": models.GineeSyncStatusFailed, "error": "sync stopped reporting progress", "finished_at": now}).Error; err != nil {
+		return nil, err
+	}
+
+	var running int64
+	if err := db.Model(&models.GineeSyncRun{}).Where("status = ?", models.GineeSyncStatusRunning).Count(&running).Error; err != nil {
+		return nil, err
+	}
+	if running > 0 {
+		return nil, ErrSyncRunning
+	}
+
+	windowStart := now.Add(-time.Duration(cfg.GineeLookbackHours) * time.Hour)
+	var last models.GineeSyncRun
+	err := db.Where("status = ?", models.GineeSyncStatusCompleted).Order("window_end DESC").First(&last).Error
+	if err == nil {
+		windowStart = last.WindowEnd.Add(-windowOverlap)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	run := models.GineeSyncRun{
+		Status:      models.GineeSyncStatusRunning,
+		WindowStart: windowStart,
+		WindowEnd:   now,
+		StartedAt:   now,
+		TriggeredBy: triggeredBy,
+	}
+	if err := db.Create(&run).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// Execute fetches the paid orders of the window of run page by page and creates the ones not known
+// yet, saving the counts on the record after every page
+func Execute(ctx context.Context, db *gorm.DB, cfg *config.Config, run *models.GineeSyncRun) error {
+	err := execute(ctx, db, NewClient(cfg), run)
+
+	now := time.Now()
+	run.FinishedAt = &now
+	run.Status = models.GineeSyncStatusCompleted
+	if err != nil {
+		run.Status = models.GineeSyncStatusFailed
+		run.Error = err.Error()
+	}
+
+	if saveErr := db.Save(run).Error; saveErr != nil {
+		return saveErr
+	}
+	return err
+}
+
+// Run starts and executes a sync, as the scheduled job does
+func Run(ctx context.Context, db *gorm.DB, cfg *config.Config, triggeredBy *uint) (*models.GineeSyncRun, error) {
+	run, err := Start(db, cfg, triggeredBy)
+	if err != nil {
+		return nil, err
+	}
+	return run, Execute(ctx, db, cfg, run)
+}
+
+func execute(ctx context.Context, db *gorm.DB, client *Client, run *models.GineeSyncRun) error {
+	for page := 0; ; page++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		result, err := client.ListPaidOrders(ctx, run.WindowStart, run.WindowEnd, page)
+		if ctx.Err() == nil {
+			if recordErr := models.RecordIntegrationCall(db, models.IntegrationGinee, err); recordErr != nil {
+				log.Printf("⚠️ Failed to record Ginee API call: %v", recordErr)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list orders (page %d): %w", page, err)
+		}
+
+		for _, gineeOrder := range result.Orders {
+			run.Fetched++
+			importOrder(db, run, gineeOrder)
+		}
+
+		// Progress doubles as the heartbeat of the run
+		if err := db.Save(run).Error; err != nil {
+			return err
+		}
+
+		if len(result.Orders) == 0 || run.Fetched >= result.Total {
+			return nil
+		}
+	}
+}
+
+// importOrder creates one fetched order, counting it on run as created, skipped or failed
+func importOrder(db *gorm.DB, run *models.GineeSyncRun, gineeOrder Order) {
+	order, err := toOrder(gineeOrder)
+	if errors.Is(err, errNoTracking) {
+		run.Skipped++
+		return
+	}
+	if err != nil {
+		run.AddError(fmt.Sprintf("%s: %v", gineeOrder.OrderID, err))
+		return
+	}
+
+	err = models.CreateIntakeOrder(db, &order)
+	switch {
+	case errors.Is(err, models.ErrOrderExists):
+		run.Skipped++
+	case err != nil:
+		run.AddError(fmt.Sprintf("%s: %v", gineeOrder.OrderID, err))
+	default:
+		run.Created++
+	}
+}
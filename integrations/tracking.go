@@ -0,0 +1,172 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"livo-backend/models"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TrackingEvent is one courier checkpoint returned by a provider
+type TrackingEvent struct {
+	Status      string
+	Description string
+	Location    string
+	Time        time.Time
+}
+
+// TrackingResult is the normalised answer of a provider for one tracking number
+type TrackingResult struct {
+	Status string
+	Events []TrackingEvent
+}
+
+// TrackingProvider pulls delivery status for a tracking number from a courier or marketplace API
+type TrackingProvider interface {
+	FetchTracking(ctx context.Context, expedition models.Expedition, tracking string) (*TrackingResult, error)
+}
+
+var trackingProviders = map[string]TrackingProvider{
+	"shopee":    &httpTrackingProvider{client: defaultHTTPClient, decode: decodeShopeeTracking},
+	"tokopedia": &httpTrackingProvider{client: defaultHTTPClient, decode: decodeGenericTracking},
+	"generic":   &httpTrackingProvider{client: defaultHTTPClient, decode: decodeGenericTracking},
+}
+
+var defaultHTTPClient = &http.Client{Timeout: 15 * time.Second}
+
+// GetTrackingProvider returns the provider registered under name
+func GetTrackingProvider(name string) (TrackingProvider, bool) {
+	provider, ok := trackingProviders[strings.ToLower(name)]
+	return provider, ok
+}
+
+// httpTrackingProvider calls the expedition's configured endpoint with ?tracking=
+// and decodes the body with a provider specific decoder
+type httpTrackingProvider struct {
+	client *http.Client
+	decode func(body []byte) (*TrackingResult, error)
+}
+
+func (p *httpTrackingProvider) FetchTracking(ctx context.Context, expedition models.Expedition, tracking string) (*TrackingResult, error) {
+	if expedition.TrackingEndpoint == "" {
+		return nil, fmt.Errorf("expedition %s has no tracking endpoint configured", expedition.Code)
+	}
+
+	endpoint, err := url.Parse(expedition.TrackingEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tracking endpoint for expedition %s: %w", expedition.Code, err)
+	}
+	query := endpoint.Query()
+	query.Set("tracking", tracking)
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if expedition.TrackingAPIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+expedition.TrackingAPIKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tracking endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return p.decode(body)
+}
+
+// decodeShopeeTracking decodes the logistics tracking_info payload used by Shopee
+func decodeShopeeTracking(body []byte) (*TrackingResult, error) {
+	var payload struct {
+		Error    string `json:"error"`
+		Message  string `json:"message"`
+		Response struct {
+			LogisticsStatus string `json:"logistics_status"`
+			TrackingInfo    []struct {
+				UpdateTime      int64  `json:"update_time"`
+				Description     string `json:"description"`
+				LogisticsStatus string `json:"logistics_status"`
+			} `json:"tracking_info"`
+		} `json:"response"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	if payload.Error != "" {
+		return nil, fmt.Errorf("shopee: %s %s", payload.Error, payload.Message)
+	}
+
+	result := &TrackingResult{Status: NormaliseDeliveryStatus(payload.Response.LogisticsStatus)}
+	for _, info := range payload.Response.TrackingInfo {
+		result.Events = append(result.Events, TrackingEvent{
+			Status:      NormaliseDeliveryStatus(info.LogisticsStatus),
+			Description: info.Description,
+			Time:        time.Unix(info.UpdateTime, 0),
+		})
+	}
+
+	return result, nil
+}
+
+// decodeGenericTracking decodes the flat payload
+// {"status": "...", "events": [{"status", "description", "location", "time"}]}
+func decodeGenericTracking(body []byte) (*TrackingResult, error) {
+	var payload struct {
+		Status string `json:"status"`
+		Events []struct {
+			Status      string    `json:"status"`
+			Description string    `json:"description"`
+			Location    string    `json:"location"`
+			Time        time.Time `json:"time"`
+		} `json:"events"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	result := &TrackingResult{Status: NormaliseDeliveryStatus(payload.Status)}
+	for _, event := range payload.Events {
+		result.Events = append(result.Events, TrackingEvent{
+			Status:      NormaliseDeliveryStatus(event.Status),
+			Description: event.Description,
+			Location:    event.Location,
+			Time:        event.Time,
+		})
+	}
+
+	return result, nil
+}
+
+// NormaliseDeliveryStatus maps provider specific status names onto the statuses stored on orders
+func NormaliseDeliveryStatus(status string) string {
+	s := strings.ToLower(status)
+	switch {
+	case s == "":
+		return ""
+	case strings.Contains(s, "return"):
+		return models.DeliveryStatusReturned
+	case strings.Contains(s, "fail"), strings.Contains(s, "lost"), strings.Contains(s, "cancel"):
+		return models.DeliveryStatusFailed
+	case strings.Contains(s, "deliver") && (strings.Contains(s, "done") || strings.Contains(s, "success") || strings.HasSuffix(s, "delivered")):
+		return models.DeliveryStatusDelivered
+	default:
+		return models.DeliveryStatusInTransit
+	}
+}
@@ -0,0 +1,49 @@
+package jobs
+
+import (
+	"context"
+	"livo-backend/backup"
+	"livo-backend/config"
+	"livo-backend/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NewBackupJob takes a database backup with the configured method
+func NewBackupJob(cfg *config.Config) Job {
+	interval := time.Duration(cfg.BackupIntervalHours) * time.Hour
+	if cfg.BackupMethod == models.BackupMethodDisabled {
+		interval = 0
+	}
+
+	return Job{
+		Name:     "database-backup",
+		Interval: interval,
+		Run: func(db *gorm.DB) error {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.BackupTimeoutMinutes)*time.Minute)
+			defer cancel()
+
+			return backup.Run(ctx, db, cfg)
+		},
+	}
+}
+
+// NewBackupVerificationJob restores the latest unverified dump into the scratch schema
+func NewBackupVerificationJob(cfg *config.Config) Job {
+	interval := time.Duration(cfg.BackupVerifyIntervalHours) * time.Hour
+	if cfg.BackupMethod != models.BackupMethodPgDump {
+		interval = 0
+	}
+
+	return Job{
+		Name:     "backup-verification",
+		Interval: interval,
+		Run: func(db *gorm.DB) error {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.BackupTimeoutMinutes)*time.Minute)
+			defer cancel()
+
+			return backup.VerifyLatest(ctx, db, cfg)
+		},
+	}
+}
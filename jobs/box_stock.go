@@ -0,0 +1,49 @@
+package jobs
+
+import (
+	"fmt"
+	"livo-backend/config"
+	"livo-backend/models"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NewBoxStockJob alerts coordinators, and the box stock webhook when configured, about boxes
+// projected to run out within the alert window at their recent usage
+func NewBoxStockJob(cfg *config.Config) Job {
+	interval := time.Duration(cfg.BoxStockCheckMinutes) * time.Minute
+	if cfg.BoxStockAlertDays <= 0 {
+		interval = 0
+	}
+
+	return Job{
+		Name:     "box-stock",
+		Interval: interval,
+		Run: func(db *gorm.DB) error {
+			alerted, err := models.AlertLowBoxStock(db, time.Now())
+			if len(alerted) > 0 {
+				codes := make([]string, len(alerted))
+				for i, forecast := range alerted {
+					codes[i] = forecast.Code
+				}
+				log.Printf("✓ Low box stock alerted: %s", strings.Join(codes, ", "))
+
+				if cfg.BoxStockWebhookURL != "" {
+					webhookErr := postWebhook(cfg.BoxStockWebhookURL, map[string]interface{}{
+						"event":      "box_stock_low",
+						"alert_days": models.CurrentSettings().BoxStock.AlertDays,
+						"boxes":      alerted,
+					})
+					recordIntegrationCall(db, models.IntegrationWebhook, webhookErr)
+					if webhookErr != nil && err == nil {
+						err = fmt.Errorf("box stock webhook: %w", webhookErr)
+					}
+				}
+			}
+			return err
+		},
+	}
+}
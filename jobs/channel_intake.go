@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"fmt"
+	"livo-backend/config"
+	"livo-backend/models"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NewChannelIntakeJob alerts coordinators, and the channel intake webhook when configured, about
+// channels over their daily quota or whose orders dropped far below their baseline today
+func NewChannelIntakeJob(cfg *config.Config) Job {
+	return Job{
+		Name:     "channel-intake",
+		Interval: time.Duration(cfg.ChannelIntakeCheckMinutes) * time.Minute,
+		Run: func(db *gorm.DB) error {
+			alerted, err := models.AlertChannelIntake(db, time.Now())
+			if len(alerted) == 0 {
+				return err
+			}
+
+			lines := make([]string, len(alerted))
+			for i, stat := range alerted {
+				lines[i] = fmt.Sprintf("%s %s (%d orders)", stat.Channel, stat.Status, stat.Orders)
+			}
+			log.Printf("✓ Channel intake alerted: %s", strings.Join(lines, ", "))
+
+			if cfg.ChannelIntakeWebhookURL != "" {
+				webhookErr := postWebhook(cfg.ChannelIntakeWebhookURL, map[string]interface{}{
+					"event":         "channel_intake_alert",
+					"text":          "Channel intake alert: " + strings.Join(lines, ", "),
+					"baseline_days": models.CurrentSettings().ChannelIntake.BaselineDays,
+					"channels":      alerted,
+				})
+				recordIntegrationCall(db, models.IntegrationWebhook, webhookErr)
+				if webhookErr != nil && err == nil {
+					err = fmt.Errorf("channel intake webhook: %w", webhookErr)
+				}
+			}
+			return err
+		},
+	}
+}
@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"fmt"
+	"livo-backend/config"
+	"livo-backend/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NewComplainEscalationJob escalates unchecked complains that stayed in a tier longer than its SLA threshold
+func NewComplainEscalationJob(cfg *config.Config) Job {
+	thresholds := map[string]time.Duration{
+		models.ComplainEscalationCS:          time.Duration(cfg.ComplainEscalationCSHours) * time.Hour,
+		models.ComplainEscalationCoordinator: time.Duration(cfg.ComplainEscalationCoordinatorHours) * time.Hour,
+	}
+
+	return Job{
+		Name:     "complain-escalation",
+		Interval: time.Duration(cfg.ComplainEscalationCheckMinutes) * time.Minute,
+		Run: func(db *gorm.DB) error {
+			return escalateOverdueComplains(db, thresholds)
+		},
+	}
+}
+
+func escalateOverdueComplains(db *gorm.DB, thresholds map[string]time.Duration) error {
+	now := time.Now()
+
+	for level, threshold := range thresholds {
+		if threshold <= 0 {
+			continue
+		}
+
+		var complains []models.Complain
+		if err := db.Where("checked = ? AND escalation_level = ?", false, level).
+			Where("status IS DISTINCT FROM ?", models.ComplainStatusDraft).
+			Where("COALESCE(escalated_at, created_at) < ?", now.Add(-threshold)).
+			Find(&complains).Error; err != nil {
+			return err
+		}
+
+		for i := range complains {
+			complain := &complains[i]
+			reason := fmt.Sprintf("No resolution within %s at %s level", threshold, level)
+
+			err := db.Transaction(func(tx *gorm.DB) error {
+				_, err := complain.Escalate(tx, nil, reason)
+				return err
+			})
+			if err != nil {
+				log.Printf("⚠️ Failed to escalate complain %s: %v", complain.Code, err)
+				continue
+			}
+
+			log.Printf("✓ Complain %s escalated to %s", complain.Code, complain.EscalationLevel)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package jobs
+
+import (
+	"fmt"
+	"livo-backend/config"
+	"livo-backend/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NewComplainSLAJob flags unchecked complains whose SLA due date has passed and notifies their
+// assigned coordinator, or every coordinator when nobody is assigned
+func NewComplainSLAJob(cfg *config.Config) Job {
+	return Job{
+		Name:     "complain-sla",
+		Interval: time.Duration(cfg.ComplainSLACheckMinutes) * time.Minute,
+		Run: func(db *gorm.DB) error {
+			return flagOverdueComplains(db, models.CurrentSettings().ComplainSLAHours)
+		},
+	}
+}
+
+func flagOverdueComplains(db *gorm.DB, slaHours int) error {
+	// Complains created before SLA tracking existed get a due date from their creation time and the
+	// SLA of their store. Drafts get theirs once they are opened.
+	if err := db.Model(&models.Complain{}).
+		Where("due_at IS NULL AND status IS DISTINCT FROM ?", models.ComplainStatusDraft).
+		Update("due_at", gorm.Expr("created_at + make_interval(hours => "+models.StoreComplainSLAHoursSQL+")", slaHours)).Error; err != nil {
+		return err
+	}
+
+	var complains []models.Complain
+	if err := db.Where("checked = ? AND sla_status = ? AND due_at < ?", false, models.ComplainSLAOnTrack, time.Now()).
+		Find(&complains).Error; err != nil {
+		return err
+	}
+
+	for i := range complains {
+		complain := &complains[i]
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(complain).Update("sla_status", models.ComplainSLAOverdue).Error; err != nil {
+				return err
+			}
+
+			message := fmt.Sprintf("Complain %s (tracking %s) passed its due date %s without being checked", complain.Code, complain.Tracking, complain.DueAt.Format("2006-01-02 15:04"))
+
+			// The assigned coordinator is responsible for the complain, all coordinators only while
+			// nobody is assigned
+			if complain.AssignedTo != nil {
+				notification := models.Notification{
+					UserID:        *complain.AssignedTo,
+					Type:          "complain_sla_breached",
+					Title:         "Complain SLA breached",
+					Message:       message,
+					ReferenceType: "complain",
+					ReferenceID:   complain.ID,
+				}
+				return tx.Create(&notification).Error
+			}
+
+			return models.NotifyUsersWithRoles(tx,
+				[]string{"coordinator"},
+				"complain_sla_breached",
+				"Complain SLA breached",
+				message,
+				"complain",
+				complain.ID,
+			)
+		})
+		if err != nil {
+			log.Printf("⚠️ Failed to flag complain %s as overdue: %v", complain.Code, err)
+			continue
+		}
+
+		log.Printf("✓ Complain %s flagged as overdue", complain.Code)
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package jobs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"livo-backend/config"
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const dailySummaryReport = "daily-summary"
+
+var webhookClient = &http.Client{Timeout: 30 * time.Second}
+
+// NewDailySummaryJob sends the daily summary report on the configured cron schedule. The job
+// checks every minute whether a schedule slot has passed that was not delivered yet.
+func NewDailySummaryJob(cfg *config.Config) Job {
+	interval := time.Minute
+
+	schedule, err := ParseCron(cfg.DailySummaryCron)
+	if err != nil {
+		log.Printf("⚠️ Invalid DAILY_SUMMARY_CRON: %v", err)
+		interval = 0
+	}
+
+	recipients := splitList(cfg.DailySummaryRecipients)
+	if len(recipients) == 0 && cfg.DailySummaryWebhookURL == "" {
+		interval = 0
+	}
+
+	return Job{
+		Name:     "daily-summary",
+		Interval: interval,
+		Run: func(db *gorm.DB) error {
+			now := time.Now()
+			slot := schedule.Prev(now)
+
+			// Missed slots are only caught up within a day, older ones are skipped
+			if slot.IsZero() || now.Sub(slot) > 24*time.Hour {
+				return nil
+			}
+
+			reportDate := slot
+			if cfg.DailySummaryDay != "current" {
+				reportDate = slot.AddDate(0, 0, -1)
+			}
+
+			// Claim the slot, another instance or an earlier run may already have delivered it
+			delivery := models.ReportDelivery{
+				Report:       dailySummaryReport,
+				ScheduledFor: slot,
+				ReportDate:   reportDate.Format("2006-01-02"),
+				Status:       models.ReportDeliverySending,
+			}
+			result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&delivery)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return nil
+			}
+
+			sendErr := sendDailySummary(db, cfg, recipients, reportDate)
+
+			sentAt := time.Now()
+			updates := map[string]interface{}{"status": models.ReportDeliverySent, "sent_at": sentAt, "error": ""}
+			if sendErr != nil {
+				updates = map[string]interface{}{"status": models.ReportDeliveryFailed, "error": sendErr.Error()}
+			}
+			if err := db.Model(&delivery).Updates(updates).Error; err != nil {
+				return err
+			}
+
+			if sendErr != nil {
+				return sendErr
+			}
+
+			log.Printf("✓ Daily summary for %s delivered", delivery.ReportDate)
+			return nil
+		},
+	}
+}
+
+func sendDailySummary(db *gorm.DB, cfg *config.Config, recipients []string, date time.Time) error {
+	summary, err := models.BuildDailySummary(db, date)
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+
+	if len(recipients) > 0 {
+		settings := utilities.SMTPSettings{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		}
+		err := utilities.SendMail(settings, recipients, "Daily summary "+summary.Date, summary.Text())
+		recordIntegrationCall(db, models.IntegrationEmail, err)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("email: %v", err))
+		}
+	}
+
+	if cfg.DailySummaryWebhookURL != "" {
+		err := postWebhook(cfg.DailySummaryWebhookURL, map[string]interface{}{
+			"report":  dailySummaryReport,
+			"summary": summary,
+			"text":    summary.Text(),
+		})
+		recordIntegrationCall(db, models.IntegrationWebhook, err)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("webhook: %v", err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// postWebhook posts a JSON payload and treats any non 2xx answer as a failure
+func postWebhook(url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := webhookClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook returned %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// splitList splits a comma separated setting, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
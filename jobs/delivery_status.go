@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"context"
+	"livo-backend/config"
+	"livo-backend/integrations"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NewDeliveryStatusJob polls the configured tracking providers for outbound trackings
+func NewDeliveryStatusJob(cfg *config.Config) Job {
+	interval := time.Duration(cfg.DeliveryTrackingPollMinutes) * time.Minute
+	maxAge := time.Duration(cfg.DeliveryTrackingMaxDays) * 24 * time.Hour
+
+	return Job{
+		Name:     "delivery-status",
+		Interval: interval,
+		Run: func(db *gorm.DB) error {
+			// Never let one poll run into the next one
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			defer cancel()
+
+			return integrations.PollDeliveryStatuses(ctx, db, maxAge, cfg.DeliveryTrackingBatchSize)
+		},
+	}
+}
@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"livo-backend/config"
+	"livo-backend/integrations/ginee"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NewGineeSyncJob creates the paid orders polled from the Ginee OpenAPI
+func NewGineeSyncJob(cfg *config.Config) Job {
+	interval := time.Duration(cfg.GineePollMinutes) * time.Minute
+	if !cfg.GineeSyncEnabled {
+		interval = 0
+	}
+
+	return Job{
+		Name:     "ginee-sync",
+		Interval: interval,
+		Run: func(db *gorm.DB) error {
+			// Never let one poll run into the next one
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			defer cancel()
+
+			_, err := ginee.Run(ctx, db, cfg, nil)
+			if errors.Is(err, ginee.ErrSyncRunning) {
+				// A manual run is polling already
+				return nil
+			}
+			return err
+		},
+	}
+}
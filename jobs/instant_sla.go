@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"fmt"
+	"livo-backend/config"
+	"livo-backend/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NewInstantSLAJob alerts coordinators about instant courier orders that were not sent out in time
+func NewInstantSLAJob(cfg *config.Config) Job {
+	return Job{
+		Name:     "instant-sla",
+		Interval: time.Duration(cfg.InstantSLACheckMinutes) * time.Minute,
+		Run:      flagOverdueInstantOrders,
+	}
+}
+
+func flagOverdueInstantOrders(db *gorm.DB) error {
+	now := time.Now()
+
+	orders, err := models.OverdueInstantOrders(db, now)
+	if err != nil {
+		return err
+	}
+
+	for i := range orders {
+		order := &orders[i]
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			// Bulk update keeps the order version, the alert is not an edit
+			if err := tx.Model(&models.Order{}).Where("id = ?", order.ID).Update("sla_breached_at", now).Error; err != nil {
+				return err
+			}
+
+			return models.NotifyUsersWithRoles(tx,
+				[]string{"coordinator"},
+				"instant_sla_breached",
+				"Instant order SLA breached",
+				fmt.Sprintf("Instant courier order %s (tracking %s) was due to be sent out by %s and is still %s", order.OrderGineeID, order.Tracking, order.SLADueAt.Format("2006-01-02 15:04"), order.ProcessingStatus),
+				"order",
+				order.ID,
+			)
+		})
+		if err != nil {
+			log.Printf("⚠️ Failed to flag instant order %s as overdue: %v", order.OrderGineeID, err)
+			continue
+		}
+
+		log.Printf("✓ Instant order %s flagged as overdue", order.OrderGineeID)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package jobs
+
+import (
+	"fmt"
+	"livo-backend/config"
+	"livo-backend/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// integrationCallRetention is how long per minute integration call counts are kept
+const integrationCallRetention = 7 * 24 * time.Hour
+
+// NewIntegrationHealthJob alerts admins when an integration's error rate crosses the threshold and
+// again when it recovers
+func NewIntegrationHealthJob(cfg *config.Config) Job {
+	return Job{
+		Name:     "integration-health",
+		Interval: time.Duration(cfg.IntegrationHealthCheckMinutes) * time.Minute,
+		Run:      checkIntegrationHealth,
+	}
+}
+
+func checkIntegrationHealth(db *gorm.DB) error {
+	now := time.Now()
+
+	statuses, err := models.IntegrationStatuses(db, now)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		// Without calls there is nothing to judge, keep the last known status
+		if status.Status == models.IntegrationStatusIdle {
+			continue
+		}
+		if err := updateIntegrationState(db, status, now); err != nil {
+			log.Printf("⚠️ Failed to update %s integration health: %v", status.Integration, err)
+		}
+	}
+
+	return db.Where("minute < ?", now.Add(-integrationCallRetention)).Delete(&models.IntegrationCall{}).Error
+}
+
+func updateIntegrationState(db *gorm.DB, status models.IntegrationStatus, now time.Time) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var state models.IntegrationState
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("integration = ?", status.Integration).First(&state).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+		previous := state.Status
+		if previous == "" {
+			previous = models.IntegrationStatusOK
+		}
+		if err == nil && previous == status.Status {
+			return nil
+		}
+
+		state.Integration = status.Integration
+		state.Status = status.Status
+		state.ChangedAt = now
+
+		var title, message, notificationType string
+		switch {
+		case status.Status == models.IntegrationStatusDegraded:
+			notificationType = "integration_degraded"
+			title = "Integration degraded"
+			message = fmt.Sprintf("%s failed %d of %d calls in the last %s (%.0f%%), last error: %s",
+				status.Integration, status.Failures, status.Calls, models.CurrentSettings().IntegrationHealth.Window, status.ErrorRate*100, status.LastError)
+		case previous == models.IntegrationStatusDegraded:
+			notificationType = "integration_recovered"
+			title = "Integration recovered"
+			message = fmt.Sprintf("%s is healthy again, %d of %d calls failed in the last %s",
+				status.Integration, status.Failures, status.Calls, models.CurrentSettings().IntegrationHealth.Window)
+		}
+		if notificationType != "" {
+			state.AlertedAt = &now
+		}
+
+		if err := tx.Save(&state).Error; err != nil {
+			return err
+		}
+		if notificationType == "" {
+			return nil
+		}
+
+		log.Printf("⚠️ %s: %s", title, message)
+		return models.NotifyUsersWithRoles(tx, []string{"superadmin", "admin"}, notificationType, title, message, "integration", 0)
+	})
+}
+
+// recordIntegrationCall counts a call for the integration health check, only logging when it cannot
+func recordIntegrationCall(db *gorm.DB, integration string, err error) {
+	if recordErr := models.RecordIntegrationCall(db, integration, err); recordErr != nil {
+		log.Printf("⚠️ Failed to record %s call: %v", integration, recordErr)
+	}
+}
@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"livo-backend/config"
+	"livo-backend/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NewOrderPriorityJob escalates orders to urgent or late as their sent before comes near or passes
+func NewOrderPriorityJob(cfg *config.Config) Job {
+	return Job{
+		Name:     "order-priority",
+		Interval: time.Duration(cfg.OrderPriorityCheckMinutes) * time.Minute,
+		Run:      escalateOrderPriorities,
+	}
+}
+
+func escalateOrderPriorities(db *gorm.DB) error {
+	urgent, late, err := models.EscalateOrderPriorities(db, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if urgent > 0 || late > 0 {
+		log.Printf("✓ Order priorities escalated: %d urgent, %d late", urgent, late)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package jobs
+
+import (
+	"fmt"
+	"livo-backend/config"
+	"livo-backend/models"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// maxStuckOrdersListed caps the orders listed in the stuck order summary, the counts cover the rest
+const maxStuckOrdersListed = 20
+
+// NewOrderStuckJob flags orders stuck in a processing status beyond its threshold as at risk and
+// posts a summary of newly flagged orders to the stuck order webhook when configured
+func NewOrderStuckJob(cfg *config.Config) Job {
+	interval := time.Duration(cfg.OrderStuckCheckMinutes) * time.Minute
+	if len(models.CurrentSettings().OrderStuckThresholds) == 0 {
+		interval = 0
+	}
+
+	return Job{
+		Name:     "order-stuck",
+		Interval: interval,
+		Run: func(db *gorm.DB) error {
+			flagged, cleared, err := models.FlagStuckOrders(db, time.Now())
+			if cleared > 0 {
+				log.Printf("✓ %d orders no longer at risk", cleared)
+			}
+			if len(flagged) == 0 {
+				return err
+			}
+			log.Printf("✓ %d stuck orders flagged at risk", len(flagged))
+
+			if cfg.OrderStuckWebhookURL == "" {
+				return err
+			}
+
+			counts, countErr := models.AtRiskOrderCounts(db)
+			if countErr != nil {
+				return countErr
+			}
+
+			listed := flagged
+			if len(listed) > maxStuckOrdersListed {
+				listed = listed[:maxStuckOrdersListed]
+			}
+			webhookErr := postWebhook(cfg.OrderStuckWebhookURL, map[string]interface{}{
+				"event":   "orders_stuck",
+				"text":    stuckOrdersText(flagged, listed, counts),
+				"flagged": len(flagged),
+				"orders":  listed,
+				"at_risk": counts,
+			})
+			recordIntegrationCall(db, models.IntegrationWebhook, webhookErr)
+			if webhookErr != nil && err == nil {
+				err = fmt.Errorf("stuck order webhook: %w", webhookErr)
+			}
+			return err
+		},
+	}
+}
+
+// stuckOrdersText is the chat message of the stuck order summary
+func stuckOrdersText(flagged, listed []models.StuckOrder, counts map[string]int64) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "%d orders are stuck and now at risk\n", len(flagged))
+	for _, order := range listed {
+		fmt.Fprintf(&text, "• %s (%s) in %s for %dh%02dm\n", order.OrderGineeID, order.Tracking, order.Status, order.StuckMinutes/60, order.StuckMinutes%60)
+	}
+	if len(flagged) > len(listed) {
+		fmt.Fprintf(&text, "…and %d more\n", len(flagged)-len(listed))
+	}
+
+	statuses := make([]string, 0, len(counts))
+	for status := range counts {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	parts := make([]string, len(statuses))
+	for i, status := range statuses {
+		parts[i] = fmt.Sprintf("%s: %d", status, counts[status])
+	}
+	fmt.Fprintf(&text, "At risk in total: %s", strings.Join(parts, ", "))
+	return text.String()
+}
@@ -0,0 +1,22 @@
+package jobs
+
+import "livo-backend/config"
+
+// RegisterAll registers every background job with the scheduler
+func RegisterAll(scheduler *Scheduler, cfg *config.Config) {
+	scheduler.Register(NewComplainEscalationJob(cfg))
+	scheduler.Register(NewComplainSLAJob(cfg))
+	scheduler.Register(NewInstantSLAJob(cfg))
+	scheduler.Register(NewDeliveryStatusJob(cfg))
+	scheduler.Register(NewBackupJob(cfg))
+	scheduler.Register(NewBackupVerificationJob(cfg))
+	scheduler.Register(NewShiftLogoutJob(cfg))
+	scheduler.Register(NewDailySummaryJob(cfg))
+	scheduler.Register(NewIntegrationHealthJob(cfg))
+	scheduler.Register(NewOrderPriorityJob(cfg))
+	scheduler.Register(NewBoxStockJob(cfg))
+	scheduler.Register(NewChannelIntakeJob(cfg))
+	scheduler.Register(NewReportPresetJob(cfg))
+	scheduler.Register(NewOrderStuckJob(cfg))
+	scheduler.Register(NewGineeSyncJob(cfg))
+}
@@ -0,0 +1,147 @@
+package jobs
+
+import (
+	"fmt"
+	"livo-backend/config"
+	"livo-backend/controllers"
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NewReportPresetJob exports scheduled report presets and emails the spreadsheets. The job checks
+// every minute whether a preset's daily or weekly slot has passed that was not delivered yet.
+func NewReportPresetJob(cfg *config.Config) Job {
+	interval := time.Minute
+	if cfg.SMTPHost == "" {
+		interval = 0
+	}
+
+	settings := utilities.SMTPSettings{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.SMTPFrom,
+	}
+
+	return Job{
+		Name:     "report-presets",
+		Interval: interval,
+		Run: func(db *gorm.DB) error {
+			now := utilities.Now()
+
+			var presets []models.ReportPreset
+			if err := db.Preload("User").Where("schedule <> ''").Find(&presets).Error; err != nil {
+				return err
+			}
+
+			var errs []string
+			for i := range presets {
+				if err := deliverReportPreset(db, settings, &presets[i], now); err != nil {
+					errs = append(errs, fmt.Sprintf("preset %d: %v", presets[i].ID, err))
+				}
+			}
+
+			if len(errs) > 0 {
+				return fmt.Errorf("%s", strings.Join(errs, "; "))
+			}
+			return nil
+		},
+	}
+}
+
+// deliverReportPreset emails the export of the last schedule slot of a preset unless it was delivered
+func deliverReportPreset(db *gorm.DB, settings utilities.SMTPSettings, preset *models.ReportPreset, now time.Time) error {
+	slot := preset.LastScheduleSlot(now)
+
+	// Missed slots are only caught up within a day, slots before the schedule was set are skipped
+	if slot.IsZero() || now.Sub(slot) > 24*time.Hour {
+		return nil
+	}
+	if preset.ScheduledSince == nil || slot.Before(*preset.ScheduledSince) {
+		return nil
+	}
+
+	// Claim the slot, another instance or an earlier run may already have delivered it
+	delivery := models.ReportDelivery{
+		Report:       fmt.Sprintf("report-preset:%d", preset.ID),
+		ScheduledFor: slot,
+		ReportDate:   slot.Format("2006-01-02"),
+		Status:       models.ReportDeliverySending,
+	}
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&delivery)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	sendErr := sendReportPreset(db, settings, preset, slot)
+
+	sentAt := time.Now()
+	updates := map[string]interface{}{"status": models.ReportDeliverySent, "sent_at": sentAt, "error": ""}
+	presetUpdates := map[string]interface{}{"last_sent_at": sentAt, "last_error": ""}
+	if sendErr != nil {
+		updates = map[string]interface{}{"status": models.ReportDeliveryFailed, "error": sendErr.Error()}
+		presetUpdates = map[string]interface{}{"last_error": sendErr.Error()}
+	}
+	if err := db.Model(&delivery).Updates(updates).Error; err != nil {
+		return err
+	}
+	if err := db.Model(preset).UpdateColumns(presetUpdates).Error; err != nil {
+		return err
+	}
+
+	if sendErr != nil {
+		return sendErr
+	}
+
+	log.Printf("✓ Report preset %q for %s delivered", preset.Name, delivery.ReportDate)
+	return nil
+}
+
+func sendReportPreset(db *gorm.DB, settings utilities.SMTPSettings, preset *models.ReportPreset, slot time.Time) error {
+	recipients := preset.RecipientList()
+	if len(recipients) == 0 && preset.User != nil {
+		recipients = []string{preset.User.Email}
+	}
+
+	filename, data, err := controllers.ExportReportPreset(db, preset, slot)
+	if err != nil {
+		return err
+	}
+
+	query := preset.Query(slot)
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Scheduled export of your report preset %q.\n\n", preset.Name)
+	fmt.Fprintf(&body, "Report: %s\n", preset.Target)
+	for _, name := range names {
+		fmt.Fprintf(&body, "%s: %s\n", name, query.Get(name))
+	}
+
+	err = utilities.SendMailWithAttachments(settings, recipients,
+		fmt.Sprintf("%s %s", preset.Name, slot.Format("2006-01-02")),
+		body.String(),
+		utilities.MailAttachment{
+			Filename:    filename,
+			ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			Data:        data,
+		},
+	)
+	recordIntegrationCall(db, models.IntegrationEmail, err)
+	return err
+}
@@ -0,0 +1,82 @@
+package jobs
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Job is a unit of background work that runs on a fixed interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(db *gorm.DB) error
+}
+
+// Scheduler runs registered jobs in their own goroutines until stopped
+type Scheduler struct {
+	DB   *gorm.DB
+	jobs []Job
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewScheduler creates a new job scheduler
+func NewScheduler(db *gorm.DB) *Scheduler {
+	return &Scheduler{DB: db, stop: make(chan struct{})}
+}
+
+// Register adds a job to the scheduler. Jobs with a non-positive interval are ignored.
+func (s *Scheduler) Register(job Job) {
+	if job.Interval <= 0 {
+		log.Printf("⚠️ Job %s has no interval, skipping", job.Name)
+		return
+	}
+	s.jobs = append(s.jobs, job)
+}
+
+// Start launches every registered job
+func (s *Scheduler) Start() {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(job)
+		log.Printf("✓ Job %s scheduled every %s", job.Name, job.Interval)
+	}
+}
+
+// Stop signals all jobs to finish and waits for them
+func (s *Scheduler) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.runOnce(job)
+		}
+	}
+}
+
+// runOnce executes a job and keeps a panic in one job from taking down the process
+func (s *Scheduler) runOnce(job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("❌ Job %s panicked: %v", job.Name, r)
+		}
+	}()
+
+	if err := job.Run(s.DB); err != nil {
+		log.Printf("⚠️ Job %s failed: %v", job.Name, err)
+	}
+}
@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"fmt"
+	"livo-backend/config"
+	"livo-backend/models"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NewShiftLogoutJob logs out operator sessions once a shift has ended so shared tablets
+// do not stay signed in between shifts
+func NewShiftLogoutJob(cfg *config.Config) Job {
+	interval := time.Duration(cfg.ShiftLogoutCheckMinutes) * time.Minute
+
+	shiftEnds, err := parseShiftEndTimes(cfg.ShiftEndTimes)
+	if err != nil {
+		log.Printf("⚠️ Invalid SHIFT_END_TIMES: %v", err)
+		interval = 0
+	}
+	if len(shiftEnds) == 0 {
+		interval = 0
+	}
+
+	roles := splitList(cfg.ShiftLogoutRoles)
+
+	return Job{
+		Name:     "shift-logout",
+		Interval: interval,
+		Run: func(db *gorm.DB) error {
+			if len(roles) == 0 {
+				return nil
+			}
+
+			shiftEnd := latestShiftEnd(shiftEnds, time.Now())
+			revoked, err := models.RevokeSessionsForRoles(db, roles, shiftEnd)
+			if err != nil {
+				return err
+			}
+
+			if revoked > 0 {
+				log.Printf("✓ Logged out %d operator(s) at shift end %s", revoked, shiftEnd.Format("2006-01-02 15:04"))
+			}
+			return nil
+		},
+	}
+}
+
+// parseShiftEndTimes parses comma separated "HH:MM" values into minutes after midnight
+func parseShiftEndTimes(value string) ([]int, error) {
+	var minutes []int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		t, err := time.Parse("15:04", part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid shift end time %q", part)
+		}
+		minutes = append(minutes, t.Hour()*60+t.Minute())
+	}
+
+	return minutes, nil
+}
+
+// latestShiftEnd returns the most recent shift end at or before now
+func latestShiftEnd(shiftEnds []int, now time.Time) time.Time {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var latest time.Time
+	for _, day := range []time.Time{midnight, midnight.AddDate(0, 0, -1)} {
+		for _, minute := range shiftEnds {
+			end := day.Add(time.Duration(minute) * time.Minute)
+			if !end.After(now) && end.After(latest) {
+				latest = end
+			}
+		}
+	}
+
+	return latest
+}
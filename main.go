@@ -2,12 +2,18 @@ package main
 
 import (
 	"fmt"
+	"livo-backend/bootstrap"
 	"livo-backend/config"
 	"livo-backend/controllers"
 	_ "livo-backend/docs" // This is required for Swagger
+	"livo-backend/grpcapi"
+	"livo-backend/jobs"
 	"livo-backend/migrations"
 	"livo-backend/routes"
+	"livo-backend/storage"
 	"log"
+	"net"
+	"time"
 )
 
 // @title Livotech Backend Service API
@@ -20,6 +26,10 @@ import (
 // @in header
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description Scoped API key for machine clients, accepted wherever BearerAuth is. Create keys with POST /api/api-keys.
 func main() {
 	log.Println("🚀 Starting Livotech Backend Service...")
 
@@ -28,6 +38,9 @@ func main() {
 	cfg := config.LoadConfig()
 	log.Println("✓ Configuration loaded successfully")
 
+	// Apply settings shared with the worker
+	bootstrap.ApplySettings(cfg)
+
 	// Connect to database with retry logic
 	log.Println("🔌 Connecting to database...")
 	config.ConnectDatabase(cfg)
@@ -37,45 +50,104 @@ func main() {
 	db := config.GetDB()
 	migrations.AutoMigrate(db) // No error handling needed, it's handled inside the function
 
+	// Apply the settings overridden through the admin config API, and pick up later changes
+	configService := bootstrap.NewConfigService(db, cfg)
+	if err := configService.Reload(); err != nil {
+		log.Printf("⚠️ Failed to load config overrides, using the environment: %v", err)
+	}
+	configService.Start(time.Duration(cfg.ConfigReloadSeconds) * time.Second)
+	defer configService.Stop()
+
+	// Storage of uploaded files
+	fileStorage, err := storage.New(cfg)
+	if err != nil {
+		log.Fatalf("❌ Invalid file storage configuration: %v", err)
+	}
+
 	// Initialize controllers
 	log.Println("🎮 Initializing controllers...")
-	authController := controllers.NewAuthController(db, cfg)
-	userManagerController := controllers.NewUserManagerController(db)
-	boxController := controllers.NewBoxController(db)
-	channelController := controllers.NewChannelController(db)
-	mobileChannelController := controllers.NewMobileChannelController(db)
-	expeditionController := controllers.NewExpeditionController(db)
-	productController := controllers.NewProductController(db)
-	storeController := controllers.NewStoreController(db)
-	mobileStoreController := controllers.NewMobileStoreController(db)
-	qcRibbonController := controllers.NewQcRibbonController(db)
-	ribbonFlowController := controllers.NewRibbonFlowController(db)
-	qcOnlineController := controllers.NewQcOnlineController(db)
-	onlineFlowController := controllers.NewOnlineFlowController(db)
-	outboundController := controllers.NewOutboundController(db)
-	returnController := controllers.NewReturnController(db)
-	mobileReturnController := controllers.NewMobileReturnController(db)
-	complainController := controllers.NewComplainController(db)
-	orderController := controllers.NewOrderController(db)
-	mobileOrderController := controllers.NewMobileOrderController(db)
-	userController := controllers.NewUserController(db)
-	lostFoundController := controllers.NewLostFoundController(db)
-	reportController := controllers.NewReportController(db)
-	pickedOrderController := controllers.NewPickedOrderController(db)
+	appControllers := &routes.Controllers{
+		Auth:          controllers.NewAuthController(db, cfg),
+		UserManager:   controllers.NewUserManagerController(db),
+		Box:           controllers.NewBoxController(db),
+		Channel:       controllers.NewChannelController(db),
+		MobileChannel: controllers.NewMobileChannelController(db),
+		Expedition:    controllers.NewExpeditionController(db),
+		Product:       controllers.NewProductController(db, cfg, fileStorage),
+		Store:         controllers.NewStoreController(db),
+		MobileStore:   controllers.NewMobileStoreController(db),
+		QcRibbon:      controllers.NewQcRibbonController(db),
+		RibbonFlow:    controllers.NewRibbonFlowController(db),
+		QcOnline:      controllers.NewQcOnlineController(db),
+		OnlineFlow:    controllers.NewOnlineFlowController(db),
+		Outbound:      controllers.NewOutboundController(db),
+		Return:        controllers.NewReturnController(db),
+		MobileReturn:  controllers.NewMobileReturnController(db),
+		Complain:      controllers.NewComplainController(db, cfg),
+		Order:         controllers.NewOrderController(db),
+		MobileOrder:   controllers.NewMobileOrderController(db, cfg),
+		User:          controllers.NewUserController(db),
+		LostFound:     controllers.NewLostFoundController(db),
+		Report:        controllers.NewReportController(db),
+		PickedOrder:   controllers.NewPickedOrderController(db),
+		Notification:  controllers.NewNotificationController(db),
+		Zone:          controllers.NewZoneController(db),
+		Backup:        controllers.NewBackupController(db, cfg),
+		Backfill:      controllers.NewBackfillController(db),
+		APIKey:        controllers.NewAPIKeyController(db),
+		BulkFailure:   controllers.NewBulkFailureController(db, cfg),
+		GraphQL:       controllers.NewGraphQLController(db),
+		ReportPreset:  controllers.NewReportPresetController(db),
+		Inbound:       controllers.NewInboundController(db),
+		MobileInbound: controllers.NewMobileInboundController(db),
+		MobileQc:      controllers.NewMobileQcController(db),
+		Ginee:         controllers.NewGineeController(db, cfg),
+		Pickup:        controllers.NewPickupController(db),
+		RoleTemplate:  controllers.NewRoleTemplateController(db),
+		Config:        controllers.NewConfigController(db, configService),
+	}
 	log.Println("✓ Controllers initialized successfully")
 
 	// Setup routes
 	log.Println("🛣️  Setting up routes...")
-	router := routes.SetupRoutes(cfg, authController, userManagerController, boxController, channelController, mobileChannelController, expeditionController, productController, storeController, mobileStoreController, qcRibbonController, ribbonFlowController, qcOnlineController, onlineFlowController, outboundController, returnController, mobileReturnController, complainController, orderController, mobileOrderController, userController, lostFoundController, reportController, pickedOrderController)
+	router := routes.SetupRoutes(cfg, appControllers)
 	log.Println("✓ Routes configured successfully")
 
+	// Start background jobs unless the standalone worker runs them
+	if cfg.RunBackgroundJobs {
+		log.Println("⏱️  Starting background jobs...")
+		scheduler := jobs.NewScheduler(db)
+		jobs.RegisterAll(scheduler, cfg)
+		scheduler.Start()
+		defer scheduler.Stop()
+	} else {
+		log.Println("⏱️  Background jobs disabled, run the worker to process them")
+	}
+
+	// Serve the analytics gRPC API on its own port
+	if cfg.GRPCEnabled {
+		listener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			log.Fatalf("❌ Failed to listen on gRPC port %s: %v", cfg.GRPCPort, err)
+		}
+		grpcServer := grpcapi.NewServer(db)
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Printf("⚠️ gRPC server stopped: %v", err)
+			}
+		}()
+		defer grpcServer.GracefulStop()
+		log.Printf("✓ gRPC analytics API on port %s", cfg.GRPCPort)
+	}
+
 	// Build API URL from config
 	apiURL := fmt.Sprintf("http://%s:%s", cfg.APIHost, cfg.Port)
 
 	// Start server
 	log.Println("════════════════════════════════════════════════════════════")
 	log.Printf("✓ Server ready on port %s", cfg.Port)
-	log.Printf("📊 Health check: %s/health", apiURL)
+	log.Printf("📊 Health check: %s/health/live", apiURL)
+	log.Printf("📊 Readiness check: %s/health/ready", apiURL)
 	log.Printf("📚 API documentation: %s/docs", apiURL)
 	log.Printf("📖 Swagger UI: %s/swagger/index.html", apiURL)
 	log.Println("════════════════════════════════════════════════════════════")
@@ -1,34 +1,60 @@
 package middleware
 
 import (
+	"fmt"
 	"livo-backend/config"
+	"livo-backend/models"
 	"livo-backend/utilities"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware validates JWT token
+// AuthMiddleware validates JWT token, or the X-API-Key header for machine clients
 func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if key := c.GetHeader("X-API-Key"); key != "" {
+			authenticateAPIKey(c, key)
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			utilities.ErrorResponse(c, http.StatusUnauthorized, "Authorization header is required", "missing authorization header")
+			utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeAuthRequired, "Authorization header is required", "missing authorization header")
 			c.Abort()
 			return
 		}
 
 		bearerToken := strings.Split(authHeader, " ")
 		if len(bearerToken) != 2 || bearerToken[0] != "Bearer" {
-			utilities.ErrorResponse(c, http.StatusUnauthorized, "Invalid authorization header format", "invalid bearer token format")
+			utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidToken, "Invalid authorization header format", "invalid bearer token format")
 			c.Abort()
 			return
 		}
 
 		claims, err := utilities.ValidateToken(bearerToken[1], cfg.JWTSecret)
 		if err != nil {
-			utilities.ErrorResponse(c, http.StatusUnauthorized, "Invalid token", err.Error())
+			utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidToken, "Invalid token", err.Error())
+			c.Abort()
+			return
+		}
+
+		// Reject tokens revoked by shift end or inactivity, or issued before a role change
+		var issuedAt time.Time
+		if claims.IssuedAt != nil {
+			issuedAt = claims.IssuedAt.Time
+		}
+		if err := models.CheckUserSession(config.GetDB().WithContext(c), claims.UserID, issuedAt, claims.TokenVersion, time.Duration(cfg.SessionIdleTimeoutHours)*time.Hour); err != nil {
+			utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeSessionExpired, "Session expired", err.Error())
+			c.Abort()
+			return
+		}
+
+		// Reject tokens of a device logged out from the session list
+		if err := models.CheckDeviceSession(config.GetDB().WithContext(c), claims.SessionID, c.ClientIP(), time.Now()); err != nil {
+			utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeSessionExpired, "Session expired", err.Error())
 			c.Abort()
 			return
 		}
@@ -37,6 +63,47 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("roles", claims.Roles)
+		c.Set("role_level", tokenRoleLevel(claims))
+		c.Set("session_id", claims.SessionID)
 		c.Next()
 	}
 }
+
+// tokenRoleLevel returns the role level the token acts with. Tokens issued before the role_level
+// claim carry none, their level comes from the roles they carry.
+func tokenRoleLevel(claims *utilities.JWTClaims) int {
+	if claims.RoleLevel == 0 {
+		return models.HighestRoleLevel(claims.Roles)
+	}
+	return claims.RoleLevel
+}
+
+// authenticateAPIKey lets the request act as the key's user when the key's scopes cover the route
+func authenticateAPIKey(c *gin.Context, key string) {
+	apiKey, err := models.AuthenticateAPIKey(config.GetDB().WithContext(c), key, c.ClientIP())
+	if err != nil {
+		utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidAPIKey, "Invalid API key", err.Error())
+		c.Abort()
+		return
+	}
+
+	resource, action := models.APIKeyScopeForRoute(c.FullPath(), c.Request.Method)
+	if !apiKey.Allows(resource, action) {
+		utilities.ErrorResponseCode(c, http.StatusForbidden, utilities.ErrCodeAPIKeyScopeDenied, "API key scope does not allow this request", fmt.Sprintf("requires scope %s:%s", resource, action))
+		c.Abort()
+		return
+	}
+
+	roles := make([]string, len(apiKey.User.UserRoles))
+	for i, userRole := range apiKey.User.UserRoles {
+		roles[i] = userRole.Role.Name
+	}
+
+	// Set the key's user in context, handlers treat it like a logged-in user
+	c.Set("user_id", apiKey.UserID)
+	c.Set("username", apiKey.User.Username)
+	c.Set("roles", roles)
+	c.Set("role_level", apiKey.User.GetHighestRoleLevel())
+	c.Set("api_key_id", apiKey.ID)
+	c.Next()
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"livo-backend/models"
+	"livo-backend/utilities"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testJWTSecret = "test-secret"
+
+// legacyToken signs an access token the way tokens were issued before the role_level and
+// token_version claims
+func legacyToken(t *testing.T, roles []string) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id":  7,
+		"username": "legacy",
+		"roles":    roles,
+		"exp":      time.Now().Add(time.Hour).Unix(),
+		"iat":      time.Now().Unix(),
+	}).SignedString([]byte(testJWTSecret))
+	if err != nil {
+		t.Fatalf("sign legacy token: %v", err)
+	}
+	return token
+}
+
+func TestTokenRoleLevel(t *testing.T) {
+	current, _, err := utilities.GenerateTokens(7, "current", []string{"picker"}, 4, 2, 0, testJWTSecret, 1, 1)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{name: "legacy token takes the level of its roles", token: legacyToken(t, []string{"picker", "admin"}), want: 3},
+		{name: "legacy token without known roles", token: legacyToken(t, []string{"unknown"}), want: 0},
+		{name: "role_level claim", token: current, want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := utilities.ValidateToken(tt.token, testJWTSecret)
+			if err != nil {
+				t.Fatalf("ValidateToken: %v", err)
+			}
+			if got := tokenRoleLevel(claims); got != tt.want {
+				t.Errorf("role level = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLegacyTokenCanAssignRole(t *testing.T) {
+	claims, err := utilities.ValidateToken(legacyToken(t, []string{"admin"}), testJWTSecret)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+
+	// The hierarchy check of assigning a role, an admin assigns picker
+	if level, target := tokenRoleLevel(claims), models.GetRoleHierarchy()["picker"]; level < target {
+		t.Errorf("legacy admin token has level %d, cannot assign picker of level %d", level, target)
+	}
+}
@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// gzipWriters reuses gzip writers between responses, they hold sizeable buffers
+var gzipWriters = sync.Pool{New: func() interface{} { return gzip.NewWriter(nil) }}
+
+// Compression gzips and tags the responses of GET requests below the route groups given as path
+// prefixes: responses of gzipRoutes of at least minBytes are compressed for clients accepting
+// gzip, 200 responses of etagRoutes get an ETag of their body and an If-None-Match matching it
+// gets 304 without a body. The handlers still run, the savings are on the wire.
+func Compression(gzipRoutes, etagRoutes []string, minBytes int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		path := c.Request.URL.Path
+		useGzip := matchesRouteGroup(path, gzipRoutes)
+		useETag := matchesRouteGroup(path, etagRoutes)
+		if !useGzip && !useETag {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		buffered := &bufferedWriter{ResponseWriter: original, status: http.StatusOK}
+		c.Writer = buffered
+		c.Next()
+		c.Writer = original
+
+		header := original.Header()
+		body := buffered.body.Bytes()
+		if useGzip {
+			header.Add("Vary", "Accept-Encoding")
+		}
+
+		if useETag && buffered.status == http.StatusOK {
+			sum := sha256.Sum256(body)
+			etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+			header.Set("ETag", etag)
+			if header.Get("Cache-Control") == "" {
+				// Responses depend on the user, browsers may keep them but must check back every time
+				header.Set("Cache-Control", "private, no-cache")
+			}
+
+			if etagMatches(c.GetHeader("If-None-Match"), etag) {
+				header.Del("Content-Type")
+				header.Del("Content-Length")
+				original.WriteHeader(http.StatusNotModified)
+				original.WriteHeaderNow()
+				return
+			}
+		}
+
+		if useGzip && len(body) >= minBytes && acceptsGzip(c.GetHeader("Accept-Encoding")) &&
+			header.Get("Content-Encoding") == "" && compressible(header.Get("Content-Type")) {
+			var compressed bytes.Buffer
+			gz := gzipWriters.Get().(*gzip.Writer)
+			gz.Reset(&compressed)
+			_, err := gz.Write(body)
+			if err == nil {
+				err = gz.Close()
+			}
+			gzipWriters.Put(gz)
+
+			if err == nil {
+				body = compressed.Bytes()
+				header.Set("Content-Encoding", "gzip")
+			}
+		}
+
+		if buffered.status != http.StatusNoContent && buffered.status != http.StatusNotModified {
+			header.Set("Content-Length", strconv.Itoa(len(body)))
+		}
+		original.WriteHeader(buffered.status)
+		original.WriteHeaderNow()
+		original.Write(body)
+	}
+}
+
+// bufferedWriter holds the response of the handlers back so it can be tagged and compressed whole
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	if status > 0 && !w.written {
+		w.status = status
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.written
+}
+
+// Flush is a no-op, the response goes out once the handlers are done
+func (w *bufferedWriter) Flush() {}
+
+// matchesRouteGroup reports whether path is one of prefixes or below it
+func matchesRouteGroup(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		prefix = strings.TrimRight(prefix, "/")
+		if prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, comparing weakly as RFC 9110
+// requires for If-None-Match
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if q, found := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q="); found {
+			if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// compressible reports whether a content type is text worth compressing. Spreadsheets, PDFs,
+// images and archives are compressed already.
+func compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") || mediaType == "application/json" ||
+		mediaType == "application/xml" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueryTimeout bounds the database queries of a request. Handlers run their queries with the request
+// context, which is cancelled when the client disconnects and, with this middleware, once timeout
+// has passed, or longTimeout for requests below the route groups longRoutes (reports and exports).
+// A zero timeout leaves the requests it applies to unbounded.
+func QueryTimeout(timeout, longTimeout time.Duration, longRoutes []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := timeout
+		if matchesRouteGroup(c.Request.URL.Path, longRoutes) {
+			limit = longTimeout
+		}
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), limit)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
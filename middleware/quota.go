@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"fmt"
+	"livo-backend/config"
+	"livo-backend/models"
+	"livo-backend/utilities"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireQuota counts the request against the user's daily quota of operation and rejects it with
+// 429 once the quota of the user's roles is used up. Must run after AuthMiddleware.
+func RequireQuota(operation string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roles, _ := c.Get("roles")
+		userRoles, _ := roles.([]string)
+
+		limit, limited := models.CurrentSettings().RequestQuotas[operation].LimitFor(userRoles)
+		if !limited {
+			c.Next()
+			return
+		}
+
+		now := time.Now()
+		day, resetsAt := models.QuotaDay(now)
+		used, allowed, err := models.ConsumeQuota(config.GetDB().WithContext(c), c.GetUint("user_id"), operation, day, limit)
+		if err != nil {
+			utilities.ErrorResponse(c, http.StatusInternalServerError, "Failed to check request quota", err.Error())
+			c.Abort()
+			return
+		}
+
+		c.Header("X-Quota-Limit", strconv.Itoa(limit))
+		c.Header("X-Quota-Remaining", strconv.Itoa(max(limit-used, 0)))
+		c.Header("X-Quota-Reset", resetsAt.Format(time.RFC3339))
+
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(resetsAt.Sub(now).Seconds())+1))
+			utilities.ErrorResponseCode(c, http.StatusTooManyRequests, utilities.ErrCodeQuotaExceeded, "Daily quota exceeded",
+				fmt.Sprintf("the daily %s quota of %d requests is used up, it resets at %s", operation, limit, resetsAt.Format("2006-01-02 15:04:05")))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
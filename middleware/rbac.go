@@ -12,7 +12,7 @@ func RequireRoles(requiredRoles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		roles, exists := c.Get("roles")
 		if !exists {
-			utilities.ErrorResponse(c, http.StatusUnauthorized, "No roles found in token", "missing roles")
+			utilities.ErrorResponseCode(c, http.StatusUnauthorized, utilities.ErrCodeInvalidToken, "No roles found in token", "missing roles")
 			c.Abort()
 			return
 		}
@@ -39,7 +39,7 @@ func RequireRoles(requiredRoles ...string) gin.HandlerFunc {
 		}
 
 		if !hasRole {
-			utilities.ErrorResponse(c, http.StatusForbidden, "Insufficient permissions", "access denied")
+			utilities.ErrorResponseCode(c, http.StatusForbidden, utilities.ErrCodeInsufficientAccess, "Insufficient permissions", "access denied")
 			c.Abort()
 			return
 		}
@@ -48,12 +48,24 @@ func RequireRoles(requiredRoles ...string) gin.HandlerFunc {
 	}
 }
 
+// Roles allowed by the role groups below
+var (
+	CoordinatorRoles = []string{"superadmin", "coordinator"}
+	AdminRoles       = []string{"superadmin", "admin"}
+	FinanceRoles     = []string{"superadmin", "finance"}
+)
+
 // RequireCoordinatorRoles for endpoints that require coordinator role
 func RequireCoordinatorRoles() gin.HandlerFunc {
-	return RequireRoles("superadmin", "coordinator")
+	return RequireRoles(CoordinatorRoles...)
 }
 
 // RequireAdminRoles for endpoints that require admin role
 func RequireAdminRoles() gin.HandlerFunc {
-	return RequireRoles("superadmin", "admin")
+	return RequireRoles(AdminRoles...)
+}
+
+// RequireFinanceRoles for endpoints that require finance role
+func RequireFinanceRoles() gin.HandlerFunc {
+	return RequireRoles(FinanceRoles...)
 }
@@ -8,33 +8,73 @@ import (
 	"gorm.io/gorm"
 )
 
+// Models lists every model managed by AutoMigrate
+var Models = []interface{}{
+	&models.Role{},
+	&models.User{},
+	&models.UserRole{},
+	&models.Box{},
+	&models.Channel{},
+	&models.Expedition{},
+	&models.Store{},
+	&models.Product{},
+	&models.PickedOrder{},
+	&models.Order{},
+	&models.OrderDetail{},
+	&models.OrderDetailScan{},
+	&models.DeliveryHistory{},
+	&models.QcRibbon{},
+	&models.QcRibbonDetail{},
+	&models.QcOnline{},
+	&models.QcOnlineDetail{},
+	&models.Outbound{},
+	&models.Return{},
+	&models.ReturnDetail{},
+	&models.Complain{},
+	&models.ComplainProductDetail{},
+	&models.ComplainUserDetail{},
+	&models.ComplainEscalation{},
+	&models.LostFound{},
+	&models.Zone{},
+	&models.PickerZone{},
+	&models.Notification{},
+	&models.Backup{},
+	&models.OutboundAlert{},
+	&models.ReportDelivery{},
+	&models.ContactReveal{},
+	&models.OrderChange{},
+	&models.OrderRevision{},
+	&models.PutawayTask{},
+	&models.ProductLocationStat{},
+	&models.QuotaUsage{},
+	&models.IntegrationCall{},
+	&models.IntegrationState{},
+	&models.BackfillRun{},
+	&models.APIKey{},
+	&models.BulkFailure{},
+	&models.SyncMutation{},
+	&models.ReportPreset{},
+	&models.RoleTemplate{},
+	&models.ConfigOverride{},
+	&models.ConfigChange{},
+	&models.ComplainFeeSettlement{},
+	&models.Session{},
+	&models.Inbound{},
+	&models.InboundDetail{},
+	&models.QcVerification{},
+	&models.QcDiscrepancy{},
+	&models.GineeSyncRun{},
+	&models.PickupSlot{},
+	&models.ChannelIntakeAlert{},
+}
+
 // AutoMigrate runs database migrations
 func AutoMigrate(db *gorm.DB) {
+	// Drop constraints replaced by indexes before AutoMigrate compares them
+	dropReplacedConstraints(db)
+
 	// Run migrations
-	err := db.AutoMigrate(
-		&models.Role{},
-		&models.User{},
-		&models.UserRole{},
-		&models.Box{},
-		&models.Channel{},
-		&models.Expedition{},
-		&models.Store{},
-		&models.Product{},
-		&models.PickedOrder{},
-		&models.Order{},
-		&models.OrderDetail{},
-		&models.QcRibbon{},
-		&models.QcRibbonDetail{},
-		&models.QcOnline{},
-		&models.QcOnlineDetail{},
-		&models.Outbound{},
-		&models.Return{},
-		&models.ReturnDetail{},
-		&models.Complain{},
-		&models.ComplainProductDetail{},
-		&models.ComplainUserDetail{},
-		&models.LostFound{},
-	)
+	err := db.AutoMigrate(Models...)
 	if err != nil {
 		log.Printf("⚠️ Warning: Failed to migrate some tables: %v", err)
 	} else {
@@ -61,6 +101,37 @@ func AutoMigrate(db *gorm.DB) {
 
 	// Fix column types
 	fixColumnTypes(db)
+
+	// Keep order trackings in step with the records referencing them
+	if err := models.InstallTrackingRenameGuard(db); err != nil {
+		log.Printf("⚠️ Warning: Failed to install tracking rename guard: %v", err)
+	} else {
+		log.Println("✓ Tracking rename guard installed")
+	}
+}
+
+// PendingMigrations returns the tables and columns of Models missing from the database, as "table"
+// or "table.column", which AutoMigrate has yet to create
+func PendingMigrations(db *gorm.DB) ([]string, error) {
+	var pending []string
+	migrator := db.Migrator()
+	for _, model := range Models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, err
+		}
+
+		if !migrator.HasTable(model) {
+			pending = append(pending, stmt.Schema.Table)
+			continue
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && !migrator.HasColumn(model, field.DBName) {
+				pending = append(pending, stmt.Schema.Table+"."+field.DBName)
+			}
+		}
+	}
+	return pending, nil
 }
 
 // fixColumnTypes fixes column types that GORM auto migrate might miss or handle incorrectly
@@ -83,6 +154,22 @@ func fixColumnTypes(db *gorm.DB) {
 	}
 }
 
+// dropReplacedConstraints drops unique constraints the models replaced with wider unique indexes,
+// which AutoMigrate cannot drop itself when they were created under another name
+func dropReplacedConstraints(db *gorm.DB) {
+	// QC records are unique per tracking and parcel since multi-parcel orders
+	for _, table := range []string{"qc_ribbons", "qc_onlines"} {
+		if !db.Migrator().HasTable(table) {
+			continue
+		}
+		for _, constraint := range []string{table + "_tracking_key", "uni_" + table + "_tracking"} {
+			if err := db.Exec("ALTER TABLE " + table + " DROP CONSTRAINT IF EXISTS " + constraint).Error; err != nil {
+				log.Printf("⚠️ Warning: Failed to drop %s: %v", constraint, err)
+			}
+		}
+	}
+}
+
 // seedDefaultRoles creates default roles if they don't exist
 func seedDefaultRoles(db *gorm.DB) {
 	roles := []models.Role{
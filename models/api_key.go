@@ -0,0 +1,265 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// API key scope actions, write implies read
+const (
+	APIKeyActionRead  = "read"
+	APIKeyActionWrite = "write"
+)
+
+// APIKeyScopeAll grants every resource of APIKeyResources
+const APIKeyScopeAll = "*"
+
+// APIKeyResources are the resources a key can be scoped to, named after the first path segment of
+// their routes under /api (or /api/mobile)
+var APIKeyResources = []string{
+	"orders", "picked-orders", "products", "stores", "channels", "expeditions", "boxes", "zones",
+	"ribbons", "onlines", "outbounds", "returns", "putaway-tasks", "complains", "lost-founds", "reports", "flows",
+	"inbounds",
+}
+
+// apiKeyPrefix marks livo keys so leaked keys are easy to recognise in logs and secret scanners
+const apiKeyPrefix = "livo_"
+
+// Errors returned by AuthenticateAPIKey
+var (
+	ErrAPIKeyInvalid = errors.New("invalid api key")
+	ErrAPIKeyRevoked = errors.New("api key was revoked")
+	ErrAPIKeyExpired = errors.New("api key expired")
+)
+
+// APIKey lets machine clients (the Ginee sync, integrations) call the API without a user login.
+// Requests act as the key's user, limited to the key's scopes ("orders:write", "products:read",
+// "*"). Only a hash of the key is stored, the key itself is shown once on creation.
+type APIKey struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	Name       string         `gorm:"not null" json:"name" example:"Ginee sync"`
+	Prefix     string         `gorm:"not null" json:"prefix" example:"livo_3f9a1c2"` // Start of the key, to tell keys apart
+	KeyHash    string         `gorm:"uniqueIndex;not null" json:"-"`
+	Scopes     string         `gorm:"not null" json:"scopes" example:"orders:write,products:read"` // Comma separated
+	UserID     uint           `gorm:"not null;index" json:"user_id"`                               // User the key acts as
+	CreatedBy  uint           `gorm:"not null" json:"created_by"`
+	ExpiresAt  *time.Time     `gorm:"default:null" json:"expires_at"`
+	LastUsedAt *time.Time     `gorm:"default:null" json:"last_used_at"`
+	LastUsedIP string         `json:"last_used_ip"`
+	RevokedAt  *time.Time     `gorm:"default:null" json:"revoked_at"`
+	RevokedBy  *uint          `gorm:"default:null" json:"revoked_by"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationships
+	User    *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Creator *User `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
+}
+
+type APIKeyResponse struct {
+	ID         uint     `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	UserID     uint     `json:"user_id"`
+	User       string   `json:"user"`
+	CreatedBy  string   `json:"created_by"`
+	Status     string   `json:"status" example:"active"` // active, expired or revoked
+	ExpiresAt  string   `json:"expires_at"`
+	LastUsedAt string   `json:"last_used_at"`
+	LastUsedIP string   `json:"last_used_ip"`
+	RevokedAt  string   `json:"revoked_at"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+// ToAPIKeyResponse converts APIKey model to APIKeyResponse
+func (k *APIKey) ToAPIKeyResponse() APIKeyResponse {
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return "-"
+		}
+		return t.Format("2006-01-02 15:04:05")
+	}
+
+	user := "-"
+	if k.User != nil {
+		user = k.User.FullName
+	}
+	createdBy := "-"
+	if k.Creator != nil {
+		createdBy = k.Creator.FullName
+	}
+
+	status := "active"
+	switch {
+	case k.RevokedAt != nil:
+		status = "revoked"
+	case k.ExpiresAt != nil && k.ExpiresAt.Before(time.Now()):
+		status = "expired"
+	}
+
+	return APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.ScopeList(),
+		UserID:     k.UserID,
+		User:       user,
+		CreatedBy:  createdBy,
+		Status:     status,
+		ExpiresAt:  formatTime(k.ExpiresAt),
+		LastUsedAt: formatTime(k.LastUsedAt),
+		LastUsedIP: k.LastUsedIP,
+		RevokedAt:  formatTime(k.RevokedAt),
+		CreatedAt:  k.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// ScopeList returns the scopes of the key
+func (k *APIKey) ScopeList() []string {
+	scopes := []string{}
+	for _, scope := range strings.Split(k.Scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// NormalizeAPIKeyScopes validates scopes and returns them deduplicated and comma separated
+func NormalizeAPIKeyScopes(scopes []string) (string, error) {
+	seen := make(map[string]bool, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if scope != APIKeyScopeAll {
+			resource, action, found := strings.Cut(scope, ":")
+			if !found || (action != APIKeyActionRead && action != APIKeyActionWrite) {
+				return "", fmt.Errorf("scope %q must be <resource>:read, <resource>:write or *", scope)
+			}
+			if !isAPIKeyResource(resource) {
+				return "", fmt.Errorf("unknown scope resource %q, expected one of %s", resource, strings.Join(APIKeyResources, ", "))
+			}
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+	if len(normalized) == 0 {
+		return "", fmt.Errorf("at least one scope is required")
+	}
+
+	return strings.Join(normalized, ","), nil
+}
+
+func isAPIKeyResource(resource string) bool {
+	for _, known := range APIKeyResources {
+		if known == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows reports whether the key may perform action on resource. Routes outside APIKeyResources
+// (logins, user and key management) are never open to keys.
+func (k *APIKey) Allows(resource, action string) bool {
+	if !isAPIKeyResource(resource) {
+		return false
+	}
+	for _, scope := range k.ScopeList() {
+		if scope == APIKeyScopeAll {
+			return true
+		}
+		scopeResource, scopeAction, _ := strings.Cut(scope, ":")
+		if scopeResource == resource && (scopeAction == action || scopeAction == APIKeyActionWrite) {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyScopeForRoute returns the resource and action a request needs, from its route pattern
+// ("/api/mobile/orders/:id") and method. Safe methods read, all others write.
+func APIKeyScopeForRoute(route, method string) (resource, action string) {
+	segments := strings.Split(strings.Trim(route, "/"), "/")
+	if len(segments) > 0 && segments[0] == "api" {
+		segments = segments[1:]
+	}
+	if len(segments) > 0 && segments[0] == "mobile" {
+		segments = segments[1:]
+	}
+	if len(segments) > 0 {
+		resource = segments[0]
+	}
+
+	action = APIKeyActionWrite
+	if method == http.MethodGet || method == http.MethodHead {
+		action = APIKeyActionRead
+	}
+	return resource, action
+}
+
+// GenerateAPIKey creates a new random key and returns it with its display prefix and hash
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+
+	key = apiKeyPrefix + hex.EncodeToString(secret)
+	return key, key[:len(apiKeyPrefix)+7], HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the stored form of a key. Keys are long random strings, so a plain SHA-256 is
+// enough and keeps lookups to one indexed query.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// AuthenticateAPIKey finds the active key and loads its user with roles. Last use is recorded at
+// most once a minute per key.
+func AuthenticateAPIKey(db *gorm.DB, key, clientIP string) (*APIKey, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	var apiKey APIKey
+	if err := db.Preload("User.UserRoles.Role").Where("key_hash = ?", HashAPIKey(key)).First(&apiKey).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrAPIKeyInvalid
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if apiKey.RevokedAt != nil {
+		return nil, ErrAPIKeyRevoked
+	}
+	if apiKey.ExpiresAt != nil && apiKey.ExpiresAt.Before(now) {
+		return nil, ErrAPIKeyExpired
+	}
+	if apiKey.User == nil || !apiKey.User.IsActive {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > lastActiveResolution || apiKey.LastUsedIP != clientIP {
+		if err := db.Model(&APIKey{}).Where("id = ?", apiKey.ID).
+			UpdateColumns(map[string]interface{}{"last_used_at": now, "last_used_ip": clientIP}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return &apiKey, nil
+}
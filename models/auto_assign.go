@@ -0,0 +1,236 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AutoAssignPolicy configures how ready to pick orders are distributed among pickers. Pickers count as
+// active when they made a request within the last ActiveMinutes minutes. A picker gets no more orders
+// once MaxOpenOrders orders are in their picking process. With ZoneAffinity an order goes to the picker
+// assigned to most of the zones its products live in, with RestrictZones pickers with zones only get
+// orders in their zones. Ties go to the picker with the fewest open orders.
+type AutoAssignPolicy struct {
+	ActiveMinutes int // 0 counts every active picker account
+	MaxOpenOrders int // 0 for no limit
+	ZoneAffinity  bool
+	RestrictZones bool
+}
+
+// AutoAssignPicker is an active picker and the orders they would hold after the assignment
+type AutoAssignPicker struct {
+	PickerID   uint   `json:"picker_id" example:"7"`
+	FullName   string `json:"full_name" example:"Jane Doe"`
+	ZoneIDs    []uint `gorm:"-" json:"zone_ids"`
+	OpenOrders int    `json:"open_orders" example:"2"` // Orders already in picking process
+	Assigned   int    `json:"assigned" example:"3"`    // Orders given by this assignment
+}
+
+// AutoAssignment proposes an order for a picker. Version is the order version it is based on.
+type AutoAssignment struct {
+	OrderID      uint   `json:"order_id" example:"1"`
+	OrderGineeID string `json:"order_ginee_id" example:"2509116GA36VM5"`
+	Tracking     string `json:"tracking" example:"JNE1234567890"`
+	Version      uint   `json:"version" example:"1"`
+	Priority     string `json:"priority" example:"urgent"`
+	PickerID     uint   `json:"picker_id" example:"7"`
+	PickerName   string `json:"picker_name" example:"Jane Doe"`
+	ZoneMatches  int    `json:"zone_matches" example:"1"` // Zones of the order the picker is assigned to
+}
+
+// AutoAssignUnassigned is a ready to pick order no picker could take
+type AutoAssignUnassigned struct {
+	OrderID  uint   `json:"order_id" example:"2"`
+	Tracking string `json:"tracking" example:"JNE1234567891"`
+	Reason   string `json:"reason" example:"every active picker is at the maximum of open orders"`
+}
+
+// AutoAssignPlan is the proposed distribution of ready to pick orders
+type AutoAssignPlan struct {
+	Pickers     []AutoAssignPicker     `json:"pickers"`
+	Assignments []AutoAssignment       `json:"assignments"`
+	Unassigned  []AutoAssignUnassigned `json:"unassigned"`
+}
+
+// ActiveAutoAssignPickers returns the active picker accounts seen within the policy's active
+// minutes, with their zones and the orders in their picking process
+func ActiveAutoAssignPickers(db *gorm.DB, policy AutoAssignPolicy, now time.Time) ([]AutoAssignPicker, error) {
+	query := db.Table("users").
+		Select("DISTINCT users.id AS picker_id, users.full_name").
+		Joins("JOIN user_roles ON user_roles.user_id = users.id AND user_roles.deleted_at IS NULL").
+		Joins("JOIN roles ON roles.id = user_roles.role_id").
+		Where("roles.name = ? AND users.is_active = ? AND users.deleted_at IS NULL", "picker", true)
+	if policy.ActiveMinutes > 0 {
+		query = query.Where("users.last_active_at >= ?", now.Add(-time.Duration(policy.ActiveMinutes)*time.Minute))
+	}
+
+	var pickers []AutoAssignPicker
+	if err := query.Order("users.id").Scan(&pickers).Error; err != nil {
+		return nil, fmt.Errorf("failed to load active pickers: %w", err)
+	}
+	if len(pickers) == 0 {
+		return pickers, nil
+	}
+
+	ids := make([]uint, len(pickers))
+	for i := range pickers {
+		ids[i] = pickers[i].PickerID
+	}
+
+	var zones []PickerZone
+	if err := db.Where("user_id IN ?", ids).Find(&zones).Error; err != nil {
+		return nil, fmt.Errorf("failed to load picker zones: %w", err)
+	}
+	var open []struct {
+		PickedBy uint
+		Orders   int
+	}
+	if err := db.Model(&Order{}).Select("picked_by, COUNT(*) AS orders").
+		Where("processing_status = ? AND picked_by IN ?", "picking process", ids).
+		Group("picked_by").Scan(&open).Error; err != nil {
+		return nil, fmt.Errorf("failed to count open orders: %w", err)
+	}
+
+	byID := make(map[uint]*AutoAssignPicker, len(pickers))
+	for i := range pickers {
+		pickers[i].ZoneIDs = []uint{}
+		byID[pickers[i].PickerID] = &pickers[i]
+	}
+	for _, zone := range zones {
+		byID[zone.UserID].ZoneIDs = append(byID[zone.UserID].ZoneIDs, zone.ZoneID)
+	}
+	for _, count := range open {
+		byID[count.PickedBy].OpenOrders = count.Orders
+	}
+	return pickers, nil
+}
+
+// PlanAutoAssignment distributes up to limit ready to pick orders among the active pickers,
+// late and urgent orders first, then instant courier orders, then by ship deadline. Cancelled,
+// merged and held orders are left out. Nothing is saved.
+func PlanAutoAssignment(db *gorm.DB, policy AutoAssignPolicy, limit int, now time.Time) (AutoAssignPlan, error) {
+	plan := AutoAssignPlan{Assignments: []AutoAssignment{}, Unassigned: []AutoAssignUnassigned{}}
+
+	pickers, err := ActiveAutoAssignPickers(db, policy, now)
+	if err != nil {
+		return plan, err
+	}
+	plan.Pickers = pickers
+
+	var orders []Order
+	if err := db.Where("processing_status = ?", "ready to pick").
+		Where("event_status IS NULL OR event_status <> ?", "cancelled").
+		Where("merged_into_id IS NULL AND held_at IS NULL").
+		Order(OrderPriorityRank + ", instant DESC, sent_before ASC, id ASC").
+		Limit(limit).Find(&orders).Error; err != nil {
+		return plan, fmt.Errorf("failed to load ready to pick orders: %w", err)
+	}
+	if len(orders) == 0 {
+		return plan, nil
+	}
+
+	orderZones, err := orderZoneIDs(db, orders)
+	if err != nil {
+		return plan, err
+	}
+
+	for _, order := range orders {
+		best, matches, reason := pickAutoAssignPicker(plan.Pickers, orderZones[order.ID], policy)
+		if best == nil {
+			plan.Unassigned = append(plan.Unassigned, AutoAssignUnassigned{OrderID: order.ID, Tracking: order.Tracking, Reason: reason})
+			continue
+		}
+
+		best.Assigned++
+		plan.Assignments = append(plan.Assignments, AutoAssignment{
+			OrderID:      order.ID,
+			OrderGineeID: order.OrderGineeID,
+			Tracking:     order.Tracking,
+			Version:      order.Version,
+			Priority:     order.Priority,
+			PickerID:     best.PickerID,
+			PickerName:   best.FullName,
+			ZoneMatches:  matches,
+		})
+	}
+
+	return plan, nil
+}
+
+// pickAutoAssignPicker returns the picker an order with the given zones goes to and how many of
+// its zones they cover, or the reason no picker can take it
+func pickAutoAssignPicker(pickers []AutoAssignPicker, zoneIDs map[uint]bool, policy AutoAssignPolicy) (*AutoAssignPicker, int, string) {
+	if len(pickers) == 0 {
+		return nil, 0, "no active picker"
+	}
+
+	var best *AutoAssignPicker
+	bestMatches := 0
+	outOfZone := 0
+	for i := range pickers {
+		picker := &pickers[i]
+		load := picker.OpenOrders + picker.Assigned
+		if policy.MaxOpenOrders > 0 && load >= policy.MaxOpenOrders {
+			continue
+		}
+
+		matches := 0
+		for _, zoneID := range picker.ZoneIDs {
+			if zoneIDs[zoneID] {
+				matches++
+			}
+		}
+		if policy.RestrictZones && len(picker.ZoneIDs) > 0 && matches == 0 {
+			outOfZone++
+			continue
+		}
+		if !policy.ZoneAffinity {
+			matches = 0
+		}
+
+		if best == nil || matches > bestMatches ||
+			(matches == bestMatches && load < best.OpenOrders+best.Assigned) {
+			best, bestMatches = picker, matches
+		}
+	}
+
+	if best == nil {
+		if outOfZone > 0 {
+			return nil, 0, "no active picker with room left works in the zones of the order"
+		}
+		return nil, 0, fmt.Sprintf("every active picker is at the maximum of %d open orders", policy.MaxOpenOrders)
+	}
+	return best, bestMatches, ""
+}
+
+// orderZoneIDs returns the zones the products of each order live in
+func orderZoneIDs(db *gorm.DB, orders []Order) (map[uint]map[uint]bool, error) {
+	ids := make([]uint, len(orders))
+	for i := range orders {
+		ids[i] = orders[i].ID
+	}
+
+	var rows []struct {
+		OrderID uint
+		ZoneID  uint
+	}
+	if err := db.Table("order_details").
+		Select("DISTINCT order_details.order_id, zones.id AS zone_id").
+		Joins("JOIN products ON products.sku = order_details.sku AND products.deleted_at IS NULL").
+		Joins("JOIN zones ON products.location ILIKE zones.location_prefix || '%' AND zones.deleted_at IS NULL").
+		Where("order_details.order_id IN ?", ids).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load order zones: %w", err)
+	}
+
+	zones := make(map[uint]map[uint]bool, len(orders))
+	for _, row := range rows {
+		if zones[row.OrderID] == nil {
+			zones[row.OrderID] = make(map[uint]bool)
+		}
+		zones[row.OrderID][row.ZoneID] = true
+	}
+	return zones, nil
+}
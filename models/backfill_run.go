@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Backfill run statuses
+const (
+	BackfillStatusRunning   = "running"
+	BackfillStatusCompleted = "completed"
+	BackfillStatusFailed    = "failed"
+)
+
+// BackfillRun records one run of a backfill and its progress. Progress is saved after every batch, so
+// the record doubles as a heartbeat: a running record that stops being updated belongs to a dead
+// process.
+type BackfillRun struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Name        string         `gorm:"not null;index" json:"name" example:"order-courier-lane"`
+	Status      string         `gorm:"not null;index" json:"status" example:"running"`
+	BatchSize   int            `gorm:"not null" json:"batch_size" example:"500"`
+	Total       int64          `json:"total" example:"12000"`   // Rows pending when the run started
+	Scanned     int64          `json:"scanned" example:"3500"`  // Rows looked at so far
+	Updated     int64          `json:"updated" example:"3400"`  // Rows that got a value
+	LastID      uint           `json:"last_id" example:"48211"` // Cursor, rows up to this ID are done
+	Error       string         `json:"error"`
+	StartedAt   time.Time      `gorm:"not null" json:"started_at"`
+	FinishedAt  *time.Time     `gorm:"default:null" json:"finished_at"`
+	TriggeredBy *uint          `gorm:"default:null" json:"triggered_by"` // Null when run from the command line
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationship
+	Trigger *User `gorm:"foreignKey:TriggeredBy" json:"trigger,omitempty"`
+}
+
+type BackfillRunResponse struct {
+	ID          uint    `json:"id"`
+	Name        string  `json:"name"`
+	Status      string  `json:"status"`
+	BatchSize   int     `json:"batch_size"`
+	Total       int64   `json:"total"`
+	Scanned     int64   `json:"scanned"`
+	Updated     int64   `json:"updated"`
+	Progress    float64 `json:"progress" example:"29.2"` // Percentage of the pending rows scanned
+	LastID      uint    `json:"last_id"`
+	Error       string  `json:"error"`
+	StartedAt   string  `json:"started_at"`
+	FinishedAt  string  `json:"finished_at"`
+	TriggeredBy string  `json:"triggered_by"`
+}
+
+// ToBackfillRunResponse converts BackfillRun model to BackfillRunResponse
+func (r *BackfillRun) ToBackfillRunResponse() BackfillRunResponse {
+	finishedAt := "-"
+	if r.FinishedAt != nil {
+		finishedAt = r.FinishedAt.Format("2006-01-02 15:04:05")
+	}
+
+	triggeredBy := "command line"
+	if r.Trigger != nil {
+		triggeredBy = r.Trigger.FullName
+	}
+
+	progress := 100.0
+	if r.Status != BackfillStatusCompleted && r.Total > 0 {
+		progress = min(float64(r.Scanned)*100/float64(r.Total), 100)
+	}
+
+	return BackfillRunResponse{
+		ID:          r.ID,
+		Name:        r.Name,
+		Status:      r.Status,
+		BatchSize:   r.BatchSize,
+		Total:       r.Total,
+		Scanned:     r.Scanned,
+		Updated:     r.Updated,
+		Progress:    progress,
+		LastID:      r.LastID,
+		Error:       r.Error,
+		StartedAt:   r.StartedAt.Format("2006-01-02 15:04:05"),
+		FinishedAt:  finishedAt,
+		TriggeredBy: triggeredBy,
+	}
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Backup methods
+const (
+	BackupMethodPgDump   = "pg_dump"
+	BackupMethodManaged  = "managed"
+	BackupMethodDisabled = "disabled"
+)
+
+// Backup run statuses
+const (
+	BackupStatusRunning = "running"
+	BackupStatusSuccess = "success"
+	BackupStatusFailed  = "failed"
+)
+
+// Restore verification statuses
+const (
+	BackupVerificationPending  = "pending"
+	BackupVerificationRunning  = "running"
+	BackupVerificationVerified = "verified"
+	BackupVerificationFailed   = "failed"
+	BackupVerificationSkipped  = "skipped"
+)
+
+// Backup records one database backup run and the result of its restore verification
+type Backup struct {
+	ID                 uint           `gorm:"primaryKey" json:"id"`
+	Method             string         `gorm:"not null" json:"method" example:"pg_dump"`
+	Status             string         `gorm:"not null;index" json:"status" example:"success"`
+	Location           string         `json:"location" example:"backups/livo-20250101-020000.dump"` // Dump file path or managed backup ID
+	SizeBytes          int64          `json:"size_bytes" example:"10485760"`
+	Error              string         `json:"error"`
+	StartedAt          time.Time      `gorm:"not null" json:"started_at"`
+	FinishedAt         *time.Time     `gorm:"default:null" json:"finished_at"`
+	TriggeredBy        *uint          `gorm:"default:null" json:"triggered_by"` // Null for scheduled backups
+	VerificationStatus string         `gorm:"default:'pending'" json:"verification_status" example:"verified"`
+	VerificationError  string         `json:"verification_error"`
+	VerifiedAt         *time.Time     `gorm:"default:null" json:"verified_at"`
+	RestoredTables     int            `json:"restored_tables" example:"28"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationship
+	Trigger *User `gorm:"foreignKey:TriggeredBy" json:"trigger,omitempty"`
+}
+
+type BackupResponse struct {
+	ID                 uint   `json:"id"`
+	Method             string `json:"method"`
+	Status             string `json:"status"`
+	Location           string `json:"location"`
+	SizeBytes          int64  `json:"size_bytes"`
+	Error              string `json:"error"`
+	StartedAt          string `json:"started_at"`
+	FinishedAt         string `json:"finished_at"`
+	TriggeredBy        string `json:"triggered_by"`
+	VerificationStatus string `json:"verification_status"`
+	VerificationError  string `json:"verification_error"`
+	VerifiedAt         string `json:"verified_at"`
+	RestoredTables     int    `json:"restored_tables"`
+}
+
+// ToBackupResponse converts Backup model to BackupResponse
+func (b *Backup) ToBackupResponse() BackupResponse {
+	finishedAt := "-"
+	if b.FinishedAt != nil {
+		finishedAt = b.FinishedAt.Format("2006-01-02 15:04:05")
+	}
+
+	verifiedAt := "-"
+	if b.VerifiedAt != nil {
+		verifiedAt = b.VerifiedAt.Format("2006-01-02 15:04:05")
+	}
+
+	triggeredBy := "scheduler"
+	if b.Trigger != nil {
+		triggeredBy = b.Trigger.FullName
+	}
+
+	return BackupResponse{
+		ID:                 b.ID,
+		Method:             b.Method,
+		Status:             b.Status,
+		Location:           b.Location,
+		SizeBytes:          b.SizeBytes,
+		Error:              b.Error,
+		StartedAt:          b.StartedAt.Format("2006-01-02 15:04:05"),
+		FinishedAt:         finishedAt,
+		TriggeredBy:        triggeredBy,
+		VerificationStatus: b.VerificationStatus,
+		VerificationError:  b.VerificationError,
+		VerifiedAt:         verifiedAt,
+		RestoredTables:     b.RestoredTables,
+	}
+}
@@ -7,29 +7,70 @@ import (
 )
 
 type Box struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Code      string         `gorm:"unique;not null" json:"code" example:"PB"`
-	Name      string         `gorm:"not null" json:"name" example:"Panjang Besar"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	Code           string         `gorm:"unique;not null" json:"code" example:"PB"`
+	Name           string         `gorm:"not null" json:"name" example:"Panjang Besar"`
+	InnerLength    float64        `json:"inner_length" example:"40"` // Inner dimensions in cm, 0 when unknown
+	InnerWidth     float64        `json:"inner_width" example:"20"`
+	InnerHeight    float64        `json:"inner_height" example:"15"`
+	MaxWeight      int            `json:"max_weight" example:"5000"`            // Grams, 0 for no limit
+	Cost           int            `json:"cost" example:"3500"`                  // Packing material cost in rupiah billed to stores per box used
+	Stock          int            `gorm:"default:0" json:"stock" example:"250"` // Boxes on hand, tracked from the first stock update
+	StockUpdatedAt *time.Time     `gorm:"default:null" json:"stock_updated_at"` // Last replenishment or count, nil when stock is not tracked
+	StockAlertedAt *time.Time     `gorm:"default:null" json:"stock_alerted_at"` // Low stock alert sent since the last stock update
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 type BoxResponse struct {
-	ID      uint      `json:"id"`
-	Code    string    `json:"code"`
-	Name    string    `json:"name"`
-	Created time.Time `json:"created_at"`
-	Updated time.Time `json:"updated_at"`
+	ID             uint      `json:"id"`
+	Code           string    `json:"code"`
+	Name           string    `json:"name"`
+	InnerLength    float64   `json:"inner_length"`
+	InnerWidth     float64   `json:"inner_width"`
+	InnerHeight    float64   `json:"inner_height"`
+	MaxWeight      int       `json:"max_weight"`
+	Cost           int       `json:"cost"`
+	Stock          int       `json:"stock"`
+	StockTracked   bool      `json:"stock_tracked"`
+	StockUpdatedAt string    `json:"stock_updated_at"`
+	Created        time.Time `json:"created_at"`
+	Updated        time.Time `json:"updated_at"`
+	DeletedAt      string    `json:"deleted_at,omitempty"` // Only set on deleted boxes listed in the trash
 }
 
 // ToBoxResponse converts Box model to BoxResponse
 func (b *Box) ToBoxResponse() BoxResponse {
+	deletedAt := ""
+	if b.DeletedAt.Valid {
+		deletedAt = b.DeletedAt.Time.Format("2006-01-02 15:04:05")
+	}
+
+	stockUpdatedAt := "-"
+	if b.StockUpdatedAt != nil {
+		stockUpdatedAt = b.StockUpdatedAt.Format("2006-01-02 15:04:05")
+	}
+
 	return BoxResponse{
-		ID:      b.ID,
-		Code:    b.Code,
-		Name:    b.Name,
-		Created: b.CreatedAt,
-		Updated: b.UpdatedAt,
+		ID:             b.ID,
+		Code:           b.Code,
+		Name:           b.Name,
+		InnerLength:    b.InnerLength,
+		InnerWidth:     b.InnerWidth,
+		InnerHeight:    b.InnerHeight,
+		MaxWeight:      b.MaxWeight,
+		Cost:           b.Cost,
+		Stock:          b.Stock,
+		StockTracked:   b.StockUpdatedAt != nil,
+		StockUpdatedAt: stockUpdatedAt,
+		Created:        b.CreatedAt,
+		Updated:        b.UpdatedAt,
+		DeletedAt:      deletedAt,
 	}
 }
+
+// Volume returns the inner volume of the box in cm³, 0 when the dimensions are unknown
+func (b *Box) Volume() float64 {
+	return b.InnerLength * b.InnerWidth * b.InnerHeight
+}
@@ -0,0 +1,192 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BoxStockPolicy configures the box usage forecast. Daily usage is the average over the last
+// UsageDays days of QC, a box is low when its stock runs out within AlertDays days at that rate.
+type BoxStockPolicy struct {
+	UsageDays int
+	AlertDays int
+}
+
+// Box stock update modes
+const (
+	BoxStockAdd = "add" // Replenishment, the quantity is added to the stock
+	BoxStockSet = "set" // Stock count, the quantity replaces the stock
+)
+
+// BoxForecast is the projected stock of a box at its recent usage rate
+type BoxForecast struct {
+	BoxID      uint     `json:"box_id"`
+	Code       string   `json:"code" example:"PB"`
+	Name       string   `json:"name" example:"Panjang Besar"`
+	Stock      int      `json:"stock" example:"120"`
+	Used       int      `json:"used" example:"280"`                // Boxes used by QC over the usage window
+	DailyUsage float64  `json:"daily_usage" example:"20"`          // Average boxes used per day
+	DaysLeft   *float64 `json:"days_left" example:"6"`             // Days until the stock runs out, nil when the box is not used
+	RunOutDate string   `json:"run_out_date" example:"2025-01-21"` // Projected day the stock runs out, "-" when the box is not used
+	Low        bool     `json:"low"`                               // Runs out within the alert window or is out already
+}
+
+// ConsumeBoxStock takes boxes used at QC off the stock. Boxes whose stock is not tracked are left
+// alone, and the stock may go negative when QC used boxes that were never booked in.
+func ConsumeBoxStock(tx *gorm.DB, boxID uint, quantity int) error {
+	return tx.Model(&Box{}).
+		Where("id = ? AND stock_updated_at IS NOT NULL", boxID).
+		UpdateColumn("stock", gorm.Expr("stock - ?", quantity)).Error
+}
+
+// UpdateBoxStock replenishes or counts the stock of a box. Updating the stock starts tracking it and
+// rearms the low stock alert.
+func UpdateBoxStock(db *gorm.DB, box *Box, mode string, quantity int) error {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"stock_updated_at": now,
+		"stock_alerted_at": nil,
+	}
+	if mode == BoxStockSet || box.StockUpdatedAt == nil {
+		updates["stock"] = quantity
+	} else {
+		updates["stock"] = gorm.Expr("stock + ?", quantity)
+	}
+
+	if err := db.Model(box).Updates(updates).Error; err != nil {
+		return err
+	}
+	return db.First(box, box.ID).Error
+}
+
+// ForecastBoxStock projects when the tracked boxes run out at their usage over the last
+// BoxStock.UsageDays days, boxes running out first listed first
+func ForecastBoxStock(db *gorm.DB, now time.Time) ([]BoxForecast, error) {
+	policy := CurrentSettings().BoxStock
+	usageDays := max(policy.UsageDays, 1)
+	since := now.AddDate(0, 0, -usageDays)
+
+	var boxes []Box
+	if err := db.Where("stock_updated_at IS NOT NULL").Order("code").Find(&boxes).Error; err != nil {
+		return nil, err
+	}
+	if len(boxes) == 0 {
+		return []BoxForecast{}, nil
+	}
+
+	type boxUsage struct {
+		BoxID uint
+		Used  int
+	}
+	var usages []boxUsage
+	if err := db.Raw(`
+		SELECT box_id, SUM(quantity) AS used FROM (
+			SELECT box_id, quantity FROM qc_ribbon_details WHERE deleted_at IS NULL AND created_at >= ?
+			UNION ALL
+			SELECT box_id, quantity FROM qc_online_details WHERE deleted_at IS NULL AND created_at >= ?
+		) details
+		GROUP BY box_id`, since, since).Scan(&usages).Error; err != nil {
+		return nil, err
+	}
+	used := make(map[uint]int, len(usages))
+	for _, usage := range usages {
+		used[usage.BoxID] = usage.Used
+	}
+
+	forecasts := make([]BoxForecast, 0, len(boxes))
+	for _, box := range boxes {
+		forecast := BoxForecast{
+			BoxID:      box.ID,
+			Code:       box.Code,
+			Name:       box.Name,
+			Stock:      box.Stock,
+			Used:       used[box.ID],
+			RunOutDate: "-",
+			Low:        box.Stock <= 0,
+		}
+		forecast.DailyUsage = math.Round(float64(forecast.Used)/float64(usageDays)*100) / 100
+
+		if forecast.Used > 0 {
+			daysLeft := math.Max(float64(box.Stock)*float64(usageDays)/float64(forecast.Used), 0)
+			daysLeft = math.Round(daysLeft*10) / 10
+			forecast.DaysLeft = &daysLeft
+			forecast.RunOutDate = now.Add(time.Duration(daysLeft * float64(24*time.Hour))).Format("2006-01-02")
+			forecast.Low = forecast.Low || daysLeft <= float64(policy.AlertDays)
+		}
+
+		forecasts = append(forecasts, forecast)
+	}
+
+	sortBoxForecasts(forecasts)
+	return forecasts, nil
+}
+
+// sortBoxForecasts orders forecasts by days left, out of stock first and unused boxes last
+func sortBoxForecasts(forecasts []BoxForecast) {
+	daysLeft := func(f BoxForecast) float64 {
+		if f.Stock <= 0 {
+			return -1
+		}
+		if f.DaysLeft == nil {
+			return math.Inf(1)
+		}
+		return *f.DaysLeft
+	}
+	sort.SliceStable(forecasts, func(i, j int) bool {
+		return daysLeft(forecasts[i]) < daysLeft(forecasts[j])
+	})
+}
+
+// AlertLowBoxStock notifies coordinators about boxes projected to run out within BoxStock.AlertDays
+// days. A box is alerted once until its stock is updated again. Returns the boxes alerted.
+func AlertLowBoxStock(db *gorm.DB, now time.Time) ([]BoxForecast, error) {
+	forecasts, err := ForecastBoxStock(db, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerted []BoxForecast
+	for _, forecast := range forecasts {
+		if !forecast.Low {
+			continue
+		}
+
+		claimed := false
+		err := db.Transaction(func(tx *gorm.DB) error {
+			// Claim the alert, an earlier run may already have sent it
+			claim := tx.Model(&Box{}).
+				Where("id = ? AND stock_alerted_at IS NULL", forecast.BoxID).
+				UpdateColumn("stock_alerted_at", now)
+			if claim.Error != nil || claim.RowsAffected == 0 {
+				return claim.Error
+			}
+
+			message := fmt.Sprintf("Box %s (%s) is out of stock (%d on hand)", forecast.Code, forecast.Name, forecast.Stock)
+			if forecast.Stock > 0 {
+				message = fmt.Sprintf("Box %s (%s) has %d left and runs out in %.1f days (around %s) at %.1f boxes a day", forecast.Code, forecast.Name, forecast.Stock, *forecast.DaysLeft, forecast.RunOutDate, forecast.DailyUsage)
+			}
+
+			claimed = true
+			return NotifyUsersWithRoles(tx,
+				[]string{"coordinator"},
+				"box_stock_low",
+				"Box stock running low",
+				message,
+				"box",
+				forecast.BoxID,
+			)
+		})
+		if err != nil {
+			return alerted, err
+		}
+		if claimed {
+			alerted = append(alerted, forecast)
+		}
+	}
+
+	return alerted, nil
+}
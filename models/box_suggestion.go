@@ -0,0 +1,327 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Box suggestion sources
+const (
+	BoxSuggestionHistory    = "history"    // Boxes used for earlier orders with the same contents
+	BoxSuggestionCategory   = "category"   // Boxes used for earlier orders with as many products of the same categories
+	BoxSuggestionDimensions = "dimensions" // Smallest box the order's products fit in
+)
+
+const (
+	boxSuggestionLimit       = 3
+	boxSuggestionHistoryDays = 180
+	boxFillRatio             = 0.85 // Share of a box's volume that can actually be filled
+)
+
+// BoxSuggestion is a box QC is likely to use for an order
+type BoxSuggestion struct {
+	BoxID      uint    `json:"box_id"`
+	Code       string  `json:"code" example:"PB"`
+	Name       string  `json:"name" example:"Panjang Besar"`
+	Confidence float64 `json:"confidence" example:"0.8"` // Share of similar orders packed in this box, 0..1
+	Source     string  `json:"source" example:"history"`
+}
+
+// SuggestBoxes suggests boxes for order, most likely first. Earlier QC choices for orders with the
+// same SKUs and quantities come first, then earlier choices for orders with as many products of the
+// same categories; the smallest box the products fit in by dimensions and weight is added when the
+// order's products and the boxes have their dimensions filled in.
+// order.OrderDetails must be loaded.
+func SuggestBoxes(db *gorm.DB, order *Order) ([]BoxSuggestion, error) {
+	if len(order.OrderDetails) == 0 {
+		return nil, nil
+	}
+
+	suggestions, err := suggestBoxesFromHistory(db, order)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(suggestions) < boxSuggestionLimit {
+		byCategory, err := suggestBoxesFromCategories(db, order)
+		if err != nil {
+			return nil, err
+		}
+		suggestions = appendBoxSuggestions(suggestions, byCategory...)
+	}
+
+	fitting, err := suggestBoxByDimensions(db, order)
+	if err != nil {
+		return nil, err
+	}
+	if fitting != nil {
+		suggestions = appendBoxSuggestions(suggestions, *fitting)
+	}
+
+	if len(suggestions) > boxSuggestionLimit {
+		suggestions = suggestions[:boxSuggestionLimit]
+	}
+
+	return suggestions, nil
+}
+
+// appendBoxSuggestions appends the suggestions of boxes not suggested yet
+func appendBoxSuggestions(suggestions []BoxSuggestion, more ...BoxSuggestion) []BoxSuggestion {
+	for _, suggestion := range more {
+		known := false
+		for _, existing := range suggestions {
+			if existing.BoxID == suggestion.BoxID {
+				known = true
+				break
+			}
+		}
+		if !known {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	return suggestions
+}
+
+// orderContentSignature identifies orders with the same contents, e.g. "SKU-A:1,SKU-B:2",
+// SKUs in byte order to match the signature built by suggestBoxesFromHistory
+func orderContentSignature(details []OrderDetail) string {
+	quantities := make(map[string]int)
+	for _, detail := range details {
+		quantities[detail.Sku] += detail.Quantity
+	}
+
+	skus := make([]string, 0, len(quantities))
+	for sku := range quantities {
+		skus = append(skus, sku)
+	}
+	sort.Strings(skus)
+
+	parts := make([]string, len(skus))
+	for i, sku := range skus {
+		parts[i] = fmt.Sprintf("%s:%d", sku, quantities[sku])
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func suggestBoxesFromHistory(db *gorm.DB, order *Order) ([]BoxSuggestion, error) {
+	signature := orderContentSignature(order.OrderDetails)
+
+	// Only orders sharing the first SKU can have the same contents
+	candidates := db.Table("order_details").Select("order_id").Where("sku = ?", order.OrderDetails[0].Sku)
+
+	contents := db.Table("order_details").
+		Select("order_id, sku, SUM(quantity) AS quantity").
+		Where("order_id IN (?)", candidates).
+		Group("order_id, sku")
+
+	similar := db.Table("(?) AS contents", contents).
+		Select("orders.tracking").
+		Joins("JOIN orders ON orders.id = contents.order_id").
+		Where("orders.deleted_at IS NULL AND orders.id <> ? AND orders.created_at >= ?",
+			order.ID, time.Now().AddDate(0, 0, -boxSuggestionHistoryDays)).
+		Group("orders.tracking").
+		Having(`string_agg(contents.sku || ':' || contents.quantity, ',' ORDER BY contents.sku COLLATE "C") = ?`, signature)
+
+	return countBoxChoices(db, similar, BoxSuggestionHistory)
+}
+
+// orderCategorySignature identifies orders with as many products of the same categories, e.g.
+// "Craft Supplies:3,Stationery:1". Empty when a product has no category.
+func orderCategorySignature(db *gorm.DB, details []OrderDetail) (string, string, error) {
+	skus := make([]string, 0, len(details))
+	for _, detail := range details {
+		skus = append(skus, detail.Sku)
+	}
+
+	var products []Product
+	if err := db.Where("sku IN ?", skus).Find(&products).Error; err != nil {
+		return "", "", err
+	}
+	categoriesBySku := make(map[string]string, len(products))
+	for _, product := range products {
+		categoriesBySku[product.Sku] = product.Category
+	}
+
+	quantities := make(map[string]int)
+	for _, detail := range details {
+		category := categoriesBySku[detail.Sku]
+		if category == "" {
+			return "", "", nil
+		}
+		quantities[category] += detail.Quantity
+	}
+
+	categories := make([]string, 0, len(quantities))
+	for category := range quantities {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	parts := make([]string, len(categories))
+	for i, category := range categories {
+		parts[i] = fmt.Sprintf("%s:%d", category, quantities[category])
+	}
+
+	return strings.Join(parts, ","), categories[0], nil
+}
+
+func suggestBoxesFromCategories(db *gorm.DB, order *Order) ([]BoxSuggestion, error) {
+	signature, firstCategory, err := orderCategorySignature(db, order.OrderDetails)
+	if err != nil || signature == "" {
+		return nil, err
+	}
+
+	// Only orders with a product of the first category can have the same categories
+	candidates := db.Table("order_details").
+		Select("order_details.order_id").
+		Joins("JOIN products ON products.sku = order_details.sku AND products.deleted_at IS NULL").
+		Where("products.category = ?", firstCategory)
+
+	contents := db.Table("order_details").
+		Select("order_details.order_id, COALESCE(products.category, '') AS category, SUM(order_details.quantity) AS quantity").
+		Joins("LEFT JOIN products ON products.sku = order_details.sku AND products.deleted_at IS NULL").
+		Where("order_details.order_id IN (?)", candidates).
+		Group("order_details.order_id, COALESCE(products.category, '')")
+
+	similar := db.Table("(?) AS contents", contents).
+		Select("orders.tracking").
+		Joins("JOIN orders ON orders.id = contents.order_id").
+		Where("orders.deleted_at IS NULL AND orders.id <> ? AND orders.created_at >= ?",
+			order.ID, time.Now().AddDate(0, 0, -boxSuggestionHistoryDays)).
+		Group("orders.tracking").
+		Having(`string_agg(contents.category || ':' || contents.quantity, ',' ORDER BY contents.category COLLATE "C") = ?`, signature)
+
+	return countBoxChoices(db, similar, BoxSuggestionCategory)
+}
+
+// countBoxChoices suggests the boxes QC chose for the parcels of the trackings selected by similar,
+// the most used first, with the share of the trackings packed in each as confidence
+func countBoxChoices(db *gorm.DB, similar *gorm.DB, source string) ([]BoxSuggestion, error) {
+	choices := db.Raw(`
+		SELECT qc_ribbons.tracking, qc_ribbon_details.box_id FROM qc_ribbons
+		JOIN qc_ribbon_details ON qc_ribbon_details.qc_ribbon_id = qc_ribbons.id AND qc_ribbon_details.deleted_at IS NULL
+		WHERE qc_ribbons.deleted_at IS NULL AND qc_ribbons.tracking IN (?)
+		UNION ALL
+		SELECT qc_onlines.tracking, qc_online_details.box_id FROM qc_onlines
+		JOIN qc_online_details ON qc_online_details.qc_online_id = qc_onlines.id AND qc_online_details.deleted_at IS NULL
+		WHERE qc_onlines.deleted_at IS NULL AND qc_onlines.tracking IN (?)
+	`, similar, similar)
+
+	var counts []struct {
+		BoxID uint
+		Code  string
+		Name  string
+		Uses  int
+		Total int
+	}
+	if err := db.Table("(?) AS choices", choices).
+		Select("boxes.id AS box_id, boxes.code, boxes.name, COUNT(DISTINCT choices.tracking) AS uses, (SELECT COUNT(DISTINCT tracking) FROM (?) AS packed) AS total", choices).
+		Joins("JOIN boxes ON boxes.id = choices.box_id AND boxes.deleted_at IS NULL").
+		Group("boxes.id, boxes.code, boxes.name").
+		Order("uses DESC, boxes.code").
+		Limit(boxSuggestionLimit).
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]BoxSuggestion, 0, len(counts))
+	for _, count := range counts {
+		if count.Total == 0 {
+			continue
+		}
+		suggestions = append(suggestions, BoxSuggestion{
+			BoxID:      count.BoxID,
+			Code:       count.Code,
+			Name:       count.Name,
+			Confidence: float64(count.Uses) / float64(count.Total),
+			Source:     source,
+		})
+	}
+
+	return suggestions, nil
+}
+
+func suggestBoxByDimensions(db *gorm.DB, order *Order) (*BoxSuggestion, error) {
+	skus := make([]string, 0, len(order.OrderDetails))
+	for _, detail := range order.OrderDetails {
+		skus = append(skus, detail.Sku)
+	}
+
+	var products []Product
+	if err := db.Where("sku IN ?", skus).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	productsBySku := make(map[string]Product, len(products))
+	for _, product := range products {
+		productsBySku[product.Sku] = product
+	}
+
+	// Every product needs its dimensions, a partial estimate would suggest boxes that are too small
+	var volume, longest float64
+	weight := 0
+	for _, detail := range order.OrderDetails {
+		product, ok := productsBySku[detail.Sku]
+		if !ok || product.Length <= 0 || product.Width <= 0 || product.Height <= 0 {
+			return nil, nil
+		}
+		volume += product.Length * product.Width * product.Height * float64(detail.Quantity)
+		weight += product.Weight * detail.Quantity
+		longest = max(longest, product.Length, product.Width, product.Height)
+	}
+
+	var boxes []Box
+	if err := db.Where("inner_length > 0 AND inner_width > 0 AND inner_height > 0").Find(&boxes).Error; err != nil {
+		return nil, err
+	}
+
+	var best *Box
+	for i := range boxes {
+		box := &boxes[i]
+		if box.Volume()*boxFillRatio < volume {
+			continue
+		}
+		if max(box.InnerLength, box.InnerWidth, box.InnerHeight) < longest {
+			continue
+		}
+		if box.MaxWeight > 0 && box.MaxWeight < weight {
+			continue
+		}
+		if best == nil || box.Volume() < best.Volume() {
+			best = box
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+
+	// A snug fit is more convincing than a box with lots of room left
+	return &BoxSuggestion{
+		BoxID:      best.ID,
+		Code:       best.Code,
+		Name:       best.Name,
+		Confidence: volume / (best.Volume() * boxFillRatio),
+		Source:     BoxSuggestionDimensions,
+	}, nil
+}
+
+// BoxSuggestionAccepted reports whether the boxes chosen at QC include the top suggestion
+func BoxSuggestionAccepted(suggestions []BoxSuggestion, chosenBoxIDs []uint) *bool {
+	if len(suggestions) == 0 {
+		return nil
+	}
+
+	accepted := false
+	for _, boxID := range chosenBoxIDs {
+		if boxID == suggestions[0].BoxID {
+			accepted = true
+			break
+		}
+	}
+
+	return &accepted
+}
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Bulk operations whose failed rows are kept
+const (
+	BulkOperationOrderCreate    = "order_create"
+	BulkOperationPickerAssign   = "picker_assign"
+	BulkOperationOutboundCreate = "outbound_create"
+)
+
+// BulkOperations lists the bulk operations that record failures
+var BulkOperations = []string{BulkOperationOrderCreate, BulkOperationPickerAssign, BulkOperationOutboundCreate}
+
+// Bulk failure statuses
+const (
+	BulkFailureOpen     = "open"     // Waiting to be retried
+	BulkFailureResolved = "resolved" // A retry went through or found nothing left to do
+)
+
+// BulkFailure keeps a row that failed in a bulk request so it can be retried after the response is
+// gone. Payload is the bulk request trimmed to the failed row, a retry sends it through the same bulk
+// processing again.
+type BulkFailure struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Operation   string         `gorm:"not null;index" json:"operation" example:"outbound_create"`
+	ItemIndex   int            `json:"item_index" example:"3"`                        // Position of the row in the original request
+	ItemKey     string         `gorm:"index" json:"item_key" example:"JNE1234567890"` // Tracking or Ginee order ID of the row
+	Payload     string         `gorm:"type:jsonb;not null" json:"payload"`            // Single row bulk request
+	Error       string         `gorm:"not null" json:"error"`                         // Latest error
+	Status      string         `gorm:"not null;index;default:'open'" json:"status" example:"open"`
+	Resolution  string         `json:"resolution" example:"Order already exists"` // Why the failure was resolved
+	Attempts    int            `gorm:"not null;default:1" json:"attempts" example:"1"`
+	LastRetryAt *time.Time     `gorm:"default:null" json:"last_retry_at"`
+	ResolvedAt  *time.Time     `gorm:"default:null" json:"resolved_at"`
+	ResolvedBy  *uint          `gorm:"default:null" json:"resolved_by"`
+	CreatedBy   uint           `gorm:"not null" json:"created_by"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationships
+	Creator  *User `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
+	Resolver *User `gorm:"foreignKey:ResolvedBy" json:"resolver,omitempty"`
+}
+
+type BulkFailureResponse struct {
+	ID          uint            `json:"id"`
+	Operation   string          `json:"operation"`
+	ItemIndex   int             `json:"item_index"`
+	ItemKey     string          `json:"item_key"`
+	Payload     json.RawMessage `json:"payload" swaggertype:"object"`
+	Error       string          `json:"error"`
+	Status      string          `json:"status"`
+	Resolution  string          `json:"resolution"`
+	Attempts    int             `json:"attempts"`
+	LastRetryAt string          `json:"last_retry_at"`
+	ResolvedAt  string          `json:"resolved_at"`
+	ResolvedBy  string          `json:"resolved_by"`
+	CreatedBy   string          `json:"created_by"`
+	CreatedAt   string          `json:"created_at"`
+}
+
+// ToBulkFailureResponse converts BulkFailure model to BulkFailureResponse
+func (f *BulkFailure) ToBulkFailureResponse() BulkFailureResponse {
+	lastRetryAt := "-"
+	if f.LastRetryAt != nil {
+		lastRetryAt = f.LastRetryAt.Format("2006-01-02 15:04:05")
+	}
+	resolvedAt := "-"
+	if f.ResolvedAt != nil {
+		resolvedAt = f.ResolvedAt.Format("2006-01-02 15:04:05")
+	}
+	resolvedBy := "-"
+	if f.Resolver != nil {
+		resolvedBy = f.Resolver.FullName
+	}
+	createdBy := "-"
+	if f.Creator != nil {
+		createdBy = f.Creator.FullName
+	}
+
+	return BulkFailureResponse{
+		ID:          f.ID,
+		Operation:   f.Operation,
+		ItemIndex:   f.ItemIndex,
+		ItemKey:     f.ItemKey,
+		Payload:     json.RawMessage(f.Payload),
+		Error:       f.Error,
+		Status:      f.Status,
+		Resolution:  f.Resolution,
+		Attempts:    f.Attempts,
+		LastRetryAt: lastRetryAt,
+		ResolvedAt:  resolvedAt,
+		ResolvedBy:  resolvedBy,
+		CreatedBy:   createdBy,
+		CreatedAt:   f.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// NewBulkFailure builds a failure record for one row, payload is the single row bulk request
+func NewBulkFailure(operation string, index int, key string, payload interface{}, errMessage string, createdBy uint) BulkFailure {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		encoded = []byte("{}")
+	}
+
+	return BulkFailure{
+		Operation: operation,
+		ItemIndex: index,
+		ItemKey:   key,
+		Payload:   string(encoded),
+		Error:     errMessage,
+		Status:    BulkFailureOpen,
+		Attempts:  1,
+		CreatedBy: createdBy,
+	}
+}
+
+// RecordBulkFailures stores the failed rows of a bulk request. Failing to store them is logged and
+// does not fail the request, the rows are still in the response.
+func RecordBulkFailures(db *gorm.DB, failures []BulkFailure) {
+	if len(failures) == 0 {
+		return
+	}
+	if err := db.Create(&failures).Error; err != nil {
+		log.Printf("⚠️ Failed to record %d bulk failure(s): %v", len(failures), err)
+	}
+}
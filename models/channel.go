@@ -7,30 +7,45 @@ import (
 )
 
 type Channel struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Code      string         `gorm:"unique;not null" json:"code" example:"SP"`
-	Name      string         `gorm:"not null;unique" json:"name" example:"Shopee"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID   uint   `gorm:"primaryKey" json:"id"`
+	Code string `gorm:"unique;not null" json:"code" example:"SP"`
+	Name string `gorm:"not null;unique" json:"name" example:"Shopee"`
+	// Intake monitor thresholds, see ChannelIntakePolicy. A quota of 0 means no quota, a drop percent
+	// of 0 keeps CHANNEL_INTAKE_DROP_PERCENT.
+	IntakeQuota       int            `gorm:"not null;default:0" json:"intake_quota" example:"3000"`
+	IntakeDropPercent int            `gorm:"not null;default:0" json:"intake_drop_percent" example:"50"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 type ChannelResponse struct {
-	ID      uint      `json:"id"`
-	Code    string    `json:"code" example:"SP"`
-	Name    string    `json:"name"`
-	Created time.Time `json:"created_at"`
-	Updated time.Time `json:"updated_at"`
+	ID                uint      `json:"id"`
+	Code              string    `json:"code" example:"SP"`
+	Name              string    `json:"name"`
+	IntakeQuota       int       `json:"intake_quota"`
+	IntakeDropPercent int       `json:"intake_drop_percent"`
+	Created           time.Time `json:"created_at"`
+	Updated           time.Time `json:"updated_at"`
+	DeletedAt         string    `json:"deleted_at,omitempty"` // Only set on deleted channels listed in the trash
 }
 
 // ToChannelResponse converts Channel model to ChannelResponse
 func (c *Channel) ToChannelResponse() ChannelResponse {
+	deletedAt := ""
+	if c.DeletedAt.Valid {
+		deletedAt = c.DeletedAt.Time.Format("2006-01-02 15:04:05")
+	}
+
 	return ChannelResponse{
-		ID:      c.ID,
-		Code:    c.Code,
-		Name:    c.Name,
-		Created: c.CreatedAt,
-		Updated: c.UpdatedAt,
+		ID:                c.ID,
+		Code:              c.Code,
+		Name:              c.Name,
+		IntakeQuota:       c.IntakeQuota,
+		IntakeDropPercent: c.IntakeDropPercent,
+		Created:           c.CreatedAt,
+		Updated:           c.UpdatedAt,
+		DeletedAt:         deletedAt,
 	}
 }
 
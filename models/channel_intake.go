@@ -0,0 +1,248 @@
+package models
+
+import (
+	"fmt"
+	"livo-backend/utilities"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ChannelIntakePolicy configures the channel intake monitor. The baseline of a channel is its average
+// daily orders over the last BaselineDays days. A channel drops when its orders today stay below
+// DropPercent of the baseline expected by this time of day, checked once MinExpected orders are
+// expected so quiet mornings do not raise alerts. Channels may set their own drop percent.
+type ChannelIntakePolicy struct {
+	BaselineDays int
+	DropPercent  int // 0 disables drop alerts of channels without their own drop percent
+	MinExpected  int
+}
+
+// Channel intake statuses, the non normal ones raise alerts
+const (
+	ChannelIntakeNormal    = "normal"
+	ChannelIntakeOverQuota = "over_quota" // More orders today than the channel's daily quota
+	ChannelIntakeDrop      = "drop"       // Far fewer orders than the baseline expects by now
+)
+
+// ChannelIntakeStat is the intake of one channel on one day against its quota and baseline. Orders
+// of channel names without a channel record are listed with channel ID 0.
+type ChannelIntakeStat struct {
+	ChannelID     uint    `json:"channel_id" example:"1"`
+	Code          string  `json:"code" example:"SP"`
+	Channel       string  `json:"channel" example:"Shopee"`
+	Orders        int64   `json:"orders" example:"5200"`
+	Quota         int     `json:"quota" example:"3000"`            // Daily order quota, 0 for none
+	QuotaPercent  float64 `json:"quota_percent" example:"173.33"`  // Orders as a share of the quota, 0 without quota
+	Baseline      float64 `json:"baseline" example:"2400.5"`       // Average daily orders over the baseline days
+	Expected      float64 `json:"expected" example:"1800.38"`      // Baseline orders expected by the end of the day or now, whichever is earlier
+	ChangePercent float64 `json:"change_percent" example:"188.82"` // Orders against the expected orders, 0 when none are expected
+	DropPercent   int     `json:"drop_percent" example:"50"`       // Share of the expected orders below which the channel drops, 0 when disabled
+	Status        string  `json:"status" example:"over_quota"`
+}
+
+// ChannelIntakeAlert records an intake alert raised for a channel, at most one per channel, day and
+// status
+type ChannelIntakeAlert struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Channel   string    `gorm:"not null;uniqueIndex:idx_channel_intake_alert" json:"channel" example:"Shopee"`
+	Day       string    `gorm:"not null;size:10;uniqueIndex:idx_channel_intake_alert" json:"day" example:"2025-01-31"` // AppLocation date
+	Status    string    `gorm:"not null;uniqueIndex:idx_channel_intake_alert" json:"status" example:"over_quota"`
+	Orders    int64     `json:"orders" example:"3001"`
+	Quota     int       `json:"quota" example:"3000"`
+	Expected  float64   `json:"expected" example:"1800.38"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ChannelIntakeAlertResponse is an intake alert raised on the report's day
+type ChannelIntakeAlertResponse struct {
+	Channel  string  `json:"channel" example:"Shopee"`
+	Status   string  `json:"status" example:"over_quota"`
+	Orders   int64   `json:"orders" example:"3001"`
+	Quota    int     `json:"quota" example:"3000"`
+	Expected float64 `json:"expected" example:"1800.38"`
+	RaisedAt string  `json:"raised_at" example:"2025-01-31 10:15:00"`
+}
+
+// ToChannelIntakeAlertResponse converts ChannelIntakeAlert model to ChannelIntakeAlertResponse
+func (a *ChannelIntakeAlert) ToChannelIntakeAlertResponse() ChannelIntakeAlertResponse {
+	return ChannelIntakeAlertResponse{
+		Channel:  a.Channel,
+		Status:   a.Status,
+		Orders:   a.Orders,
+		Quota:    a.Quota,
+		Expected: a.Expected,
+		RaisedAt: a.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// BuildChannelIntake counts the orders each channel took in on date against its daily quota and its
+// baseline over the ChannelIntake.BaselineDays days before. For today the expected orders cover the
+// part of the day up to now. Channels with the most orders are listed first.
+func BuildChannelIntake(db *gorm.DB, date time.Time, now time.Time) ([]ChannelIntakeStat, error) {
+	start, end := utilities.DayRange(date)
+	policy := CurrentSettings().ChannelIntake
+	baselineDays := max(policy.BaselineDays, 1)
+	baselineStart := start.AddDate(0, 0, -baselineDays)
+
+	type channelCount struct {
+		Channel string
+		Orders  int64
+	}
+	var dayCounts, baselineCounts []channelCount
+	if err := db.Model(&Order{}).Select("channel, COUNT(*) AS orders").
+		Where("created_at >= ? AND created_at < ?", start, end).
+		Group("channel").Scan(&dayCounts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count orders per channel: %w", err)
+	}
+	if err := db.Model(&Order{}).Select("channel, COUNT(*) AS orders").
+		Where("created_at >= ? AND created_at < ?", baselineStart, start).
+		Group("channel").Scan(&baselineCounts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count baseline orders per channel: %w", err)
+	}
+
+	var channels []Channel
+	if err := db.Order("name").Find(&channels).Error; err != nil {
+		return nil, fmt.Errorf("failed to load channels: %w", err)
+	}
+
+	// Share of the day passed, the whole day once it is over
+	elapsed := 1.0
+	if now.Before(end) {
+		elapsed = math.Max(now.Sub(start).Hours()/24, 0)
+	}
+
+	stats := make(map[string]*ChannelIntakeStat, len(channels))
+	for i := range channels {
+		stats[channels[i].Name] = &ChannelIntakeStat{
+			ChannelID:   channels[i].ID,
+			Code:        channels[i].Code,
+			Channel:     channels[i].Name,
+			Quota:       channels[i].IntakeQuota,
+			DropPercent: channels[i].IntakeDropPercent,
+		}
+	}
+	stat := func(channel string) *ChannelIntakeStat {
+		if stats[channel] == nil {
+			stats[channel] = &ChannelIntakeStat{Code: "-", Channel: channel}
+		}
+		return stats[channel]
+	}
+	for _, count := range dayCounts {
+		stat(count.Channel).Orders = count.Orders
+	}
+	for _, count := range baselineCounts {
+		stat(count.Channel).Baseline = float64(count.Orders) / float64(baselineDays)
+	}
+
+	result := make([]ChannelIntakeStat, 0, len(stats))
+	for _, s := range stats {
+		if s.DropPercent == 0 {
+			s.DropPercent = policy.DropPercent
+		}
+		s.Expected = s.Baseline * elapsed
+		if s.Quota > 0 {
+			s.QuotaPercent = math.Round(float64(s.Orders)*10000/float64(s.Quota)) / 100
+		}
+		if s.Expected > 0 {
+			s.ChangePercent = math.Round((float64(s.Orders)-s.Expected)*10000/s.Expected) / 100
+		}
+
+		s.Status = ChannelIntakeNormal
+		switch {
+		case s.Quota > 0 && s.Orders > int64(s.Quota):
+			s.Status = ChannelIntakeOverQuota
+		case s.DropPercent > 0 && s.Expected >= float64(policy.MinExpected) &&
+			float64(s.Orders) < s.Expected*float64(s.DropPercent)/100:
+			s.Status = ChannelIntakeDrop
+		}
+
+		s.Baseline = math.Round(s.Baseline*100) / 100
+		s.Expected = math.Round(s.Expected*100) / 100
+		result = append(result, *s)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Orders != result[j].Orders {
+			return result[i].Orders > result[j].Orders
+		}
+		return result[i].Channel < result[j].Channel
+	})
+	return result, nil
+}
+
+// AlertChannelIntake notifies coordinators about channels over their quota or dropping below their
+// baseline today. Each channel is alerted once per day and status. Returns the channels alerted.
+func AlertChannelIntake(db *gorm.DB, now time.Time) ([]ChannelIntakeStat, error) {
+	stats, err := BuildChannelIntake(db, now, now)
+	if err != nil {
+		return nil, err
+	}
+
+	day := now.In(utilities.AppLocation()).Format("2006-01-02")
+	var alerted []ChannelIntakeStat
+	for _, stat := range stats {
+		if stat.Status == ChannelIntakeNormal {
+			continue
+		}
+
+		claimed := false
+		err := db.Transaction(func(tx *gorm.DB) error {
+			// Claim the alert, an earlier run may already have sent it
+			alert := ChannelIntakeAlert{
+				Channel:  stat.Channel,
+				Day:      day,
+				Status:   stat.Status,
+				Orders:   stat.Orders,
+				Quota:    stat.Quota,
+				Expected: stat.Expected,
+			}
+			claim := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&alert)
+			if claim.Error != nil || claim.RowsAffected == 0 {
+				return claim.Error
+			}
+
+			title := "Channel over its intake quota"
+			message := fmt.Sprintf("%s took in %d orders today, over its daily quota of %d", stat.Channel, stat.Orders, stat.Quota)
+			if stat.Status == ChannelIntakeDrop {
+				title = "Channel intake dropped"
+				message = fmt.Sprintf("%s took in %d orders today where %.0f were expected by now (baseline %.0f a day)", stat.Channel, stat.Orders, stat.Expected, stat.Baseline)
+			}
+
+			claimed = true
+			return NotifyUsersWithRoles(tx,
+				[]string{"coordinator"},
+				"channel_intake_"+stat.Status,
+				title,
+				message,
+				"channel",
+				stat.ChannelID,
+			)
+		})
+		if err != nil {
+			return alerted, err
+		}
+		if claimed {
+			alerted = append(alerted, stat)
+		}
+	}
+
+	return alerted, nil
+}
+
+// ChannelIntakeAlertsOn returns the intake alerts raised on the AppLocation day of date, oldest first
+func ChannelIntakeAlertsOn(db *gorm.DB, date time.Time) ([]ChannelIntakeAlertResponse, error) {
+	var alerts []ChannelIntakeAlert
+	if err := db.Where("day = ?", date.In(utilities.AppLocation()).Format("2006-01-02")).Order("id").Find(&alerts).Error; err != nil {
+		return nil, fmt.Errorf("failed to load channel intake alerts: %w", err)
+	}
+
+	responses := make([]ChannelIntakeAlertResponse, len(alerts))
+	for i := range alerts {
+		responses[i] = alerts[i].ToChannelIntakeAlertResponse()
+	}
+	return responses, nil
+}
@@ -1,26 +1,39 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
 )
 
 type Complain struct {
-	ID           uint           `gorm:"primaryKey" json:"id"`
-	Code         string         `gorm:"unique;not null" json:"code" example:"CMP123456"`
-	Tracking     string         `gorm:"unique;not null" json:"tracking" example:"JNE1234567890"`
-	OrderGineeID string         `gorm:"unique;not null" json:"order_ginee_id" example:"2509116GA36VM5"`
-	ChannelID    uint           `gorm:"not null" json:"channel_id"`
-	StoreID      uint           `gorm:"not null" json:"store_id"`
-	CreatedBy    uint           `gorm:"not null" json:"created_by"`
-	Description  string         `json:"description" example:"Item damaged during shipping"`
-	Solution     string         `json:"solution" example:"Refund issued"`
-	TotalFee     uint           `json:"total_fee" example:"15000"`
-	Checked      bool           `gorm:"default:false" json:"checked" example:"false"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	Code         string `gorm:"unique;not null" json:"code" example:"CMP123456"`
+	Tracking     string `gorm:"unique;not null" json:"tracking" example:"JNE1234567890"`
+	OrderGineeID string `gorm:"unique;not null" json:"order_ginee_id" example:"2509116GA36VM5"`
+	ChannelID    uint   `gorm:"not null" json:"channel_id"`
+	StoreID      uint   `gorm:"not null" json:"store_id"`
+	CreatedBy    uint   `gorm:"not null" json:"created_by"`
+	Description  string `json:"description" example:"Item damaged during shipping"`
+	Solution     string `json:"solution" example:"Refund issued"`
+	TotalFee     uint   `json:"total_fee" example:"15000"`
+	Checked      bool   `gorm:"default:false" json:"checked" example:"false"`
+	// Escalation tier the complain currently sits in (cs -> coordinator -> management)
+	EscalationLevel string     `gorm:"default:'cs';not null;index" json:"escalation_level" example:"cs"`
+	EscalatedAt     *time.Time `json:"escalated_at"`
+	DueAt           *time.Time `gorm:"default:null;index" json:"due_at"`
+	SlaStatus       string     `gorm:"default:'on_track';index" json:"sla_status" example:"on_track"`
+	// Resolution status (see ComplainStatuses) with the time the complain last entered each status
+	Status          string         `gorm:"index" json:"status" example:"open"`
+	AssignedTo      *uint          `gorm:"index" json:"assigned_to"` // User responsible for resolving the complain
+	InvestigatingAt *time.Time     `json:"investigating_at"`
+	AwaitingBuyerAt *time.Time     `json:"awaiting_buyer_at"`
+	ResolvedAt      *time.Time     `json:"resolved_at"`
+	RejectedAt      *time.Time     `json:"rejected_at"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationship
 	ProductDetails []ComplainProductDetail `gorm:"foreignKey:ComplainID" json:"product_details"`
@@ -30,6 +43,124 @@ type Complain struct {
 	Channel        *Channel                `gorm:"foreignKey:ChannelID" json:"channel,omitempty"`
 	Store          *Store                  `gorm:"foreignKey:StoreID" json:"store,omitempty"`
 	Creator        *User                   `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
+	Assignee       *User                   `gorm:"foreignKey:AssignedTo" json:"assignee,omitempty"`
+	Escalations    []ComplainEscalation    `gorm:"foreignKey:ComplainID" json:"escalations,omitempty"`
+}
+
+// Complain escalation tiers, in escalation order
+const (
+	ComplainEscalationCS          = "cs"
+	ComplainEscalationCoordinator = "coordinator"
+	ComplainEscalationManagement  = "management"
+)
+
+// ComplainEscalationLevels lists every escalation tier from lowest to highest
+var ComplainEscalationLevels = []string{
+	ComplainEscalationCS,
+	ComplainEscalationCoordinator,
+	ComplainEscalationManagement,
+}
+
+// ComplainEscalationRoles maps each escalation tier to the roles that handle it
+var ComplainEscalationRoles = map[string][]string{
+	ComplainEscalationCS:          {"admin"},
+	ComplainEscalationCoordinator: {"coordinator"},
+	ComplainEscalationManagement:  {"superadmin"},
+}
+
+// NextComplainEscalationLevel returns the tier above level, or an empty string when level is the top tier
+func NextComplainEscalationLevel(level string) string {
+	for i, l := range ComplainEscalationLevels {
+		if l == level && i+1 < len(ComplainEscalationLevels) {
+			return ComplainEscalationLevels[i+1]
+		}
+	}
+	return ""
+}
+
+// Complain SLA statuses
+const (
+	ComplainSLAOnTrack = "on_track"
+	ComplainSLAOverdue = "overdue"
+	ComplainSLAMet     = "met"
+	ComplainSLAMissed  = "missed"
+)
+
+// RefreshSLAStatus recomputes SlaStatus from DueAt and the checked flag at the given time
+func (c *Complain) RefreshSLAStatus(now time.Time) {
+	switch {
+	case c.DueAt == nil:
+		c.SlaStatus = ComplainSLAOnTrack
+	case c.Checked && now.After(*c.DueAt):
+		c.SlaStatus = ComplainSLAMissed
+	case c.Checked:
+		c.SlaStatus = ComplainSLAMet
+	case now.After(*c.DueAt):
+		c.SlaStatus = ComplainSLAOverdue
+	default:
+		c.SlaStatus = ComplainSLAOnTrack
+	}
+}
+
+// ComplainEscalation records every move of a complain from one tier to the next
+type ComplainEscalation struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	ComplainID  uint           `gorm:"not null;index" json:"complain_id"`
+	FromLevel   string         `gorm:"not null" json:"from_level" example:"cs"`
+	ToLevel     string         `gorm:"not null" json:"to_level" example:"coordinator"`
+	EscalatedBy *uint          `json:"escalated_by"` // nil when escalated automatically by the SLA job
+	Reason      string         `json:"reason" example:"SLA threshold exceeded"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationship
+	Complain  Complain `gorm:"foreignKey:ComplainID" json:"-"`
+	Escalator *User    `gorm:"foreignKey:EscalatedBy" json:"escalator,omitempty"`
+}
+
+// Escalate moves the complain to the next tier, records the history row and
+// notifies the users handling the new tier. escalatedBy is nil for automatic escalations.
+func (c *Complain) Escalate(tx *gorm.DB, escalatedBy *uint, reason string) (*ComplainEscalation, error) {
+	next := NextComplainEscalationLevel(c.EscalationLevel)
+	if next == "" {
+		return nil, fmt.Errorf("complain %s is already at the highest escalation level", c.Code)
+	}
+
+	now := time.Now()
+	escalation := ComplainEscalation{
+		ComplainID:  c.ID,
+		FromLevel:   c.EscalationLevel,
+		ToLevel:     next,
+		EscalatedBy: escalatedBy,
+		Reason:      reason,
+	}
+	if err := tx.Create(&escalation).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Model(c).Updates(map[string]interface{}{
+		"escalation_level": next,
+		"escalated_at":     now,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	c.EscalationLevel = next
+	c.EscalatedAt = &now
+
+	if err := NotifyUsersWithRoles(tx,
+		ComplainEscalationRoles[next],
+		"complain_escalated",
+		"Complain escalated",
+		fmt.Sprintf("Complain %s (tracking %s) was escalated to %s", c.Code, c.Tracking, next),
+		"complain",
+		c.ID,
+	); err != nil {
+		return nil, err
+	}
+
+	return &escalation, nil
 }
 
 type ComplainProductDetail struct {
@@ -51,6 +182,8 @@ type ComplainUserDetail struct {
 	ComplainID uint           `gorm:"not null" json:"complain_id"`
 	OperatorID uint           `gorm:"not null" json:"operator_id"` // User being complained about
 	FeeCharge  uint           `json:"fee_charge" example:"5000"`
+	Stages     string         `json:"stages" example:"picking,outbound"` // Comma separated handling stages of the operator, see ComplainStages
+	AtFault    bool           `gorm:"default:false" json:"at_fault" example:"false"`
 	CreatedAt  time.Time      `json:"created_at"`
 	UpdatedAt  time.Time      `json:"updated_at"`
 	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
@@ -78,6 +211,8 @@ type ComplainUserDetailResponse struct {
 	ComplainID uint      `json:"complain_id"`
 	OperatorID uint      `json:"operator_id"`
 	FeeCharge  uint      `json:"fee_charge"`
+	Stages     []string  `json:"stages"`
+	AtFault    bool      `json:"at_fault"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 
@@ -86,19 +221,29 @@ type ComplainUserDetailResponse struct {
 }
 
 type ComplainResponse struct {
-	ID           uint      `json:"id"`
-	Code         string    `json:"code"`
-	Tracking     string    `json:"tracking"`
-	OrderGineeID string    `json:"order_ginee_id"`
-	ChannelID    uint      `json:"channel_id"`
-	StoreID      uint      `json:"store_id"`
-	CreatedBy    uint      `json:"created_by"`
-	Description  string    `json:"description"`
-	Solution     string    `json:"solution"`
-	TotalFee     uint      `json:"total_fee"`
-	Checked      bool      `json:"checked"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID              uint      `json:"id"`
+	Code            string    `json:"code"`
+	Tracking        string    `json:"tracking"`
+	OrderGineeID    string    `json:"order_ginee_id"`
+	ChannelID       uint      `json:"channel_id"`
+	StoreID         uint      `json:"store_id"`
+	CreatedBy       uint      `json:"created_by"`
+	Description     string    `json:"description"`
+	Solution        string    `json:"solution"`
+	TotalFee        uint      `json:"total_fee"`
+	Checked         bool      `json:"checked"`
+	EscalationLevel string    `json:"escalation_level"`
+	EscalatedAt     string    `json:"escalated_at"`
+	DueAt           string    `json:"due_at"`
+	SlaStatus       string    `json:"sla_status"`
+	Status          string    `json:"status"`
+	AssignedTo      string    `json:"assigned_to"`
+	InvestigatingAt string    `json:"investigating_at"`
+	AwaitingBuyerAt string    `json:"awaiting_buyer_at"`
+	ResolvedAt      string    `json:"resolved_at"`
+	RejectedAt      string    `json:"rejected_at"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 
 	// Related data
 	ProductDetails []ComplainProductDetailResponse `json:"product_details"`
@@ -140,6 +285,8 @@ func (c *Complain) ToComplainResponse() ComplainResponse {
 			ComplainID: ud.ComplainID,
 			OperatorID: ud.OperatorID,
 			FeeCharge:  ud.FeeCharge,
+			Stages:     ud.StageList(),
+			AtFault:    ud.AtFault,
 			CreatedAt:  ud.CreatedAt,
 			UpdatedAt:  ud.UpdatedAt,
 		}
@@ -153,21 +300,59 @@ func (c *Complain) ToComplainResponse() ComplainResponse {
 	}
 
 	response := ComplainResponse{
-		ID:             c.ID,
-		Code:           c.Code,
-		Tracking:       c.Tracking,
-		OrderGineeID:   c.OrderGineeID,
-		ChannelID:      c.ChannelID,
-		StoreID:        c.StoreID,
-		CreatedBy:      c.CreatedBy,
-		Description:    c.Description,
-		Solution:       c.Solution,
-		TotalFee:       c.TotalFee,
-		Checked:        c.Checked,
-		CreatedAt:      c.CreatedAt,
-		UpdatedAt:      c.UpdatedAt,
-		ProductDetails: productDetailResponses,
-		UserDetails:    userDetailResponses,
+		ID:              c.ID,
+		Code:            c.Code,
+		Tracking:        c.Tracking,
+		OrderGineeID:    c.OrderGineeID,
+		ChannelID:       c.ChannelID,
+		StoreID:         c.StoreID,
+		CreatedBy:       c.CreatedBy,
+		Description:     c.Description,
+		Solution:        c.Solution,
+		TotalFee:        c.TotalFee,
+		Checked:         c.Checked,
+		EscalationLevel: c.EscalationLevel,
+		EscalatedAt:     "-",
+		DueAt:           "-",
+		SlaStatus:       c.SlaStatus,
+		Status:          c.Status,
+		AssignedTo:      "-",
+		InvestigatingAt: "-",
+		AwaitingBuyerAt: "-",
+		ResolvedAt:      "-",
+		RejectedAt:      "-",
+		CreatedAt:       c.CreatedAt,
+		UpdatedAt:       c.UpdatedAt,
+		ProductDetails:  productDetailResponses,
+		UserDetails:     userDetailResponses,
+	}
+
+	if c.EscalatedAt != nil {
+		response.EscalatedAt = c.EscalatedAt.Format("2006-01-02 15:04:05")
+	}
+
+	if c.DueAt != nil {
+		response.DueAt = c.DueAt.Format("2006-01-02 15:04:05")
+	}
+
+	if c.Assignee != nil {
+		response.AssignedTo = c.Assignee.FullName
+	}
+
+	if c.InvestigatingAt != nil {
+		response.InvestigatingAt = c.InvestigatingAt.Format("2006-01-02 15:04:05")
+	}
+
+	if c.AwaitingBuyerAt != nil {
+		response.AwaitingBuyerAt = c.AwaitingBuyerAt.Format("2006-01-02 15:04:05")
+	}
+
+	if c.ResolvedAt != nil {
+		response.ResolvedAt = c.ResolvedAt.Format("2006-01-02 15:04:05")
+	}
+
+	if c.RejectedAt != nil {
+		response.RejectedAt = c.RejectedAt.Format("2006-01-02 15:04:05")
 	}
 
 	// Include order data if loaded (this will include OrderGineeID)
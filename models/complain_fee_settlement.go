@@ -0,0 +1,205 @@
+package models
+
+import (
+	"livo-backend/utilities"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ComplainFeeSettlement is finance's approval of the complain fees an operator pays for a month,
+// deducted from their salary. The approved amount is kept so later fee changes stand out.
+type ComplainFeeSettlement struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	Month      string         `gorm:"not null;uniqueIndex:idx_complain_fee_settlement" json:"month" example:"2025-01"`
+	OperatorID uint           `gorm:"not null;uniqueIndex:idx_complain_fee_settlement" json:"operator_id"`
+	Complaints int            `gorm:"not null" json:"complaints" example:"3"`
+	Amount     uint64         `gorm:"not null" json:"amount" example:"15000"` // Fee total when approved
+	ApprovedBy uint           `gorm:"not null" json:"approved_by"`
+	ApprovedAt time.Time      `json:"approved_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationship
+	Approver *User `gorm:"foreignKey:ApprovedBy" json:"approver,omitempty"`
+}
+
+// ComplainFeeReference is one complain fee charged to an operator
+type ComplainFeeReference struct {
+	ComplainID   uint   `json:"complain_id"`
+	Code         string `json:"code" example:"CMP123456"`
+	Tracking     string `json:"tracking" example:"JNE1234567890"`
+	OrderGineeID string `json:"order_ginee_id" example:"2509116GA36VM5"`
+	Status       string `json:"status" example:"resolved"`
+	FeeCharge    uint64 `json:"fee_charge" example:"5000"`
+	CreatedAt    string `json:"created_at" example:"2025-01-14 10:20:00"`
+}
+
+// ComplainFeeOperator is the monthly complain fee total of an operator with its settlement state
+type ComplainFeeOperator struct {
+	OperatorID     uint                   `json:"operator_id"`
+	Username       string                 `json:"username" example:"john_doe"`
+	FullName       string                 `json:"full_name" example:"John Doe"`
+	Complaints     int                    `json:"complaints" example:"3"`
+	TotalFee       uint64                 `json:"total_fee" example:"15000"`
+	Approved       bool                   `json:"approved"`
+	ApprovedAmount uint64                 `json:"approved_amount" example:"15000"`
+	ApprovedBy     string                 `json:"approved_by" example:"Finance Staff"`
+	ApprovedAt     string                 `json:"approved_at" example:"2025-02-01 09:00:00"`
+	Changed        bool                   `json:"changed"` // Fees changed after the approval, approve again to settle the new total
+	Complains      []ComplainFeeReference `json:"complains"`
+}
+
+// ComplainFeeSettlementReport is the complain fees charged to operators in a month
+type ComplainFeeSettlementReport struct {
+	Month         string                `json:"month" example:"2025-01"`
+	Operators     []ComplainFeeOperator `json:"operators"`
+	TotalFee      uint64                `json:"total_fee" example:"250000"`
+	ApprovedFee   uint64                `json:"approved_fee" example:"200000"`
+	PendingCount  int                   `json:"pending_count" example:"2"` // Operators not approved or changed since
+	ApprovedCount int                   `json:"approved_count" example:"8"`
+}
+
+// ParseSettlementMonth parses a YYYY-MM month as its first day in the warehouse timezone
+func ParseSettlementMonth(value string) (time.Time, error) {
+	return time.ParseInLocation("2006-01", value, utilities.AppLocation())
+}
+
+// BuildComplainFeeSettlement collects the fee charges of complains created in the month of month,
+// per operator with their complain references. Rejected complains charge nobody.
+func BuildComplainFeeSettlement(db *gorm.DB, month time.Time) (ComplainFeeSettlementReport, error) {
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, utilities.AppLocation())
+	end := start.AddDate(0, 1, 0)
+	report := ComplainFeeSettlementReport{Month: start.Format("2006-01"), Operators: []ComplainFeeOperator{}}
+
+	type feeRow struct {
+		OperatorID   uint
+		Username     string
+		FullName     string
+		ComplainID   uint
+		Code         string
+		Tracking     string
+		OrderGineeID string
+		Status       string
+		FeeCharge    uint64
+		CreatedAt    time.Time
+	}
+	var rows []feeRow
+	if err := db.Table("complain_user_details").
+		Select(`complain_user_details.operator_id, users.username, users.full_name,
+			complains.id AS complain_id, complains.code, complains.tracking, complains.order_ginee_id,
+			complains.status, complain_user_details.fee_charge, complains.created_at`).
+		Joins("JOIN complains ON complains.id = complain_user_details.complain_id AND complains.deleted_at IS NULL").
+		Joins("JOIN users ON users.id = complain_user_details.operator_id").
+		Where("complain_user_details.deleted_at IS NULL AND complain_user_details.fee_charge > 0").
+		Where("complains.created_at >= ? AND complains.created_at < ?", start, end).
+		Where("COALESCE(complains.status, '') <> ?", ComplainStatusRejected).
+		Order("users.full_name, complain_user_details.operator_id, complains.created_at").
+		Scan(&rows).Error; err != nil {
+		return report, err
+	}
+
+	var settlements []ComplainFeeSettlement
+	if err := db.Preload("Approver").Where("month = ?", report.Month).Find(&settlements).Error; err != nil {
+		return report, err
+	}
+	settled := make(map[uint]ComplainFeeSettlement, len(settlements))
+	for _, settlement := range settlements {
+		settled[settlement.OperatorID] = settlement
+	}
+
+	for _, row := range rows {
+		if len(report.Operators) == 0 || report.Operators[len(report.Operators)-1].OperatorID != row.OperatorID {
+			report.Operators = append(report.Operators, ComplainFeeOperator{
+				OperatorID: row.OperatorID,
+				Username:   row.Username,
+				FullName:   row.FullName,
+				ApprovedBy: "-",
+				ApprovedAt: "-",
+				Complains:  []ComplainFeeReference{},
+			})
+		}
+		operator := &report.Operators[len(report.Operators)-1]
+		operator.Complaints++
+		operator.TotalFee += row.FeeCharge
+		operator.Complains = append(operator.Complains, ComplainFeeReference{
+			ComplainID:   row.ComplainID,
+			Code:         row.Code,
+			Tracking:     row.Tracking,
+			OrderGineeID: row.OrderGineeID,
+			Status:       row.Status,
+			FeeCharge:    row.FeeCharge,
+			CreatedAt:    row.CreatedAt.In(utilities.AppLocation()).Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	for i := range report.Operators {
+		operator := &report.Operators[i]
+		if settlement, found := settled[operator.OperatorID]; found {
+			operator.ApprovedAmount = settlement.Amount
+			operator.ApprovedAt = settlement.ApprovedAt.In(utilities.AppLocation()).Format("2006-01-02 15:04:05")
+			if settlement.Approver != nil {
+				operator.ApprovedBy = settlement.Approver.FullName
+			}
+			operator.Changed = settlement.Amount != operator.TotalFee || settlement.Complaints != operator.Complaints
+			operator.Approved = !operator.Changed
+		}
+
+		report.TotalFee += operator.TotalFee
+		if operator.Approved {
+			report.ApprovedFee += operator.TotalFee
+			report.ApprovedCount++
+		} else {
+			report.PendingCount++
+		}
+	}
+
+	return report, nil
+}
+
+// ApproveComplainFees records finance's approval of the current fee totals of operators in the
+// report, every operator of the report when operatorIDs is empty. Approving again replaces the
+// approved amount. Returns the number of operators approved.
+func ApproveComplainFees(db *gorm.DB, report ComplainFeeSettlementReport, operatorIDs []uint, approvedBy uint, at time.Time) (int, error) {
+	selected := make(map[uint]bool, len(operatorIDs))
+	for _, id := range operatorIDs {
+		selected[id] = true
+	}
+
+	var settlements []ComplainFeeSettlement
+	for _, operator := range report.Operators {
+		if len(selected) > 0 && !selected[operator.OperatorID] {
+			continue
+		}
+		settlements = append(settlements, ComplainFeeSettlement{
+			Month:      report.Month,
+			OperatorID: operator.OperatorID,
+			Complaints: operator.Complaints,
+			Amount:     operator.TotalFee,
+			ApprovedBy: approvedBy,
+			ApprovedAt: at,
+		})
+	}
+	if len(settlements) == 0 {
+		return 0, nil
+	}
+
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "month"}, {Name: "operator_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"complaints", "amount", "approved_by", "approved_at", "updated_at"}),
+	}).Create(&settlements).Error
+	return len(settlements), err
+}
+
+// RevokeComplainFeeApprovals withdraws the approvals of operators for month, every operator when
+// operatorIDs is empty. Returns the number of approvals withdrawn.
+func RevokeComplainFeeApprovals(db *gorm.DB, month string, operatorIDs []uint) (int64, error) {
+	query := db.Unscoped().Where("month = ?", month)
+	if len(operatorIDs) > 0 {
+		query = query.Where("operator_id IN ?", operatorIDs)
+	}
+	result := query.Delete(&ComplainFeeSettlement{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,160 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Handling stages an operator can be charged for
+const (
+	ComplainStagePicking  = "picking"
+	ComplainStageQcRibbon = "qc_ribbon"
+	ComplainStageQcOnline = "qc_online"
+	ComplainStageOutbound = "outbound"
+	ComplainStagePending  = "pending" // Put the order on hold while picking
+	ComplainStageReturn   = "return"  // Received the parcel back as a return
+)
+
+// ComplainStages lists every handling stage in flow order
+var ComplainStages = []string{
+	ComplainStagePicking,
+	ComplainStagePending,
+	ComplainStageQcRibbon,
+	ComplainStageQcOnline,
+	ComplainStageOutbound,
+	ComplainStageReturn,
+}
+
+// Complain fee split policies
+const (
+	ComplainFeeSplitEqual       = "equal"        // Every operator pays the same share
+	ComplainFeeSplitStageWeight = "stage_weight" // Shares follow the weights of the stages each operator handled
+	ComplainFeeSplitFault       = "fault"        // Operators marked at fault share the fee equally, the others pay nothing
+)
+
+// ComplainFeeSplitPolicies lists every fee split policy
+var ComplainFeeSplitPolicies = []string{ComplainFeeSplitEqual, ComplainFeeSplitStageWeight, ComplainFeeSplitFault}
+
+// ParseComplainStageWeights parses "stage=weight" pairs separated by commas, e.g. "picking=40,outbound=30".
+// Stages left out weigh 0.
+func ParseComplainStageWeights(value string) (map[string]int, error) {
+	weights := make(map[string]int)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		stage, weight, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("stage weight %q must look like stage=weight", pair)
+		}
+		stage = strings.TrimSpace(stage)
+		if !isComplainStage(stage) {
+			return nil, fmt.Errorf("unknown stage %q, must be one of %s", stage, strings.Join(ComplainStages, ", "))
+		}
+
+		parsed, err := strconv.Atoi(strings.TrimSpace(weight))
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("weight of stage %s must be a non-negative number", stage)
+		}
+		weights[stage] = parsed
+	}
+
+	return weights, nil
+}
+
+// StageList returns the handling stages of the operator
+func (d *ComplainUserDetail) StageList() []string {
+	stages := []string{}
+	for _, stage := range strings.Split(d.Stages, ",") {
+		if stage != "" {
+			stages = append(stages, stage)
+		}
+	}
+	return stages
+}
+
+// AddStage records that the operator handled stage
+func (d *ComplainUserDetail) AddStage(stage string) {
+	for _, existing := range d.StageList() {
+		if existing == stage {
+			return
+		}
+	}
+	d.Stages = strings.Join(append(d.StageList(), stage), ",")
+}
+
+// SplitComplainFee divides total over the user details according to policy and returns the fee charge
+// of each detail, in the same order. Shares are rounded down and the rupiah left over go to the
+// operators with the largest shares, so the charges always add up to total.
+func SplitComplainFee(total uint, details []ComplainUserDetail, policy string) ([]uint, error) {
+	if len(details) == 0 {
+		return nil, fmt.Errorf("complain has no operators to charge")
+	}
+
+	weights := make([]uint, len(details))
+	for i := range details {
+		switch policy {
+		case ComplainFeeSplitEqual:
+			weights[i] = 1
+		case ComplainFeeSplitStageWeight:
+			for _, stage := range details[i].StageList() {
+				weights[i] += uint(CurrentSettings().ComplainStageWeights[stage])
+			}
+		case ComplainFeeSplitFault:
+			if details[i].AtFault {
+				weights[i] = 1
+			}
+		default:
+			return nil, fmt.Errorf("policy must be one of %s", strings.Join(ComplainFeeSplitPolicies, ", "))
+		}
+	}
+
+	var totalWeight uint
+	for _, weight := range weights {
+		totalWeight += weight
+	}
+	if totalWeight == 0 {
+		if policy == ComplainFeeSplitFault {
+			return nil, fmt.Errorf("mark at least one operator at fault to split by fault")
+		}
+		return nil, fmt.Errorf("none of the operators handled a weighted stage")
+	}
+
+	charges := make([]uint, len(details))
+	var assigned uint
+	for i, weight := range weights {
+		charges[i] = uint(uint64(total) * uint64(weight) / uint64(totalWeight))
+		assigned += charges[i]
+	}
+
+	// Hand out the rounding remainder, heaviest shares first
+	order := make([]int, len(details))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return weights[order[a]] > weights[order[b]] })
+	for i := 0; assigned < total; i++ {
+		index := order[i%len(order)]
+		if weights[index] == 0 {
+			i = -1
+			continue
+		}
+		charges[index]++
+		assigned++
+	}
+
+	return charges, nil
+}
+
+func isComplainStage(stage string) bool {
+	for _, known := range ComplainStages {
+		if known == stage {
+			return true
+		}
+	}
+	return false
+}
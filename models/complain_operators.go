@@ -0,0 +1,267 @@
+package models
+
+import (
+	"fmt"
+	"livo-backend/utilities"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ComplainOperators finds the operators who handled tracking on its way out of the warehouse, one
+// user detail per operator with every stage they handled, in the order they were first found. The
+// picking stage covers every pick of the order, re-picks after a cancelled pick included, the pending
+// stage the operator who put the order on hold and the return stage the operators who received the
+// parcel back (or its replacement) as a return.
+func ComplainOperators(tx *gorm.DB, complainID uint, tracking string) ([]*ComplainUserDetail, error) {
+	var userDetails []*ComplainUserDetail
+	byOperator := make(map[uint]*ComplainUserDetail)
+	addStage := func(operatorID uint, stage string) {
+		if operatorID == 0 {
+			return
+		}
+		userDetail, found := byOperator[operatorID]
+		if !found {
+			userDetail = &ComplainUserDetail{
+				ComplainID: complainID,
+				OperatorID: operatorID,
+				FeeCharge:  0, // Default fee, can be updated later
+			}
+			byOperator[operatorID] = userDetail
+			userDetails = append(userDetails, userDetail)
+		}
+		userDetail.AddStage(stage)
+	}
+
+	var qcRibbon QcRibbon
+	if err := tx.Where("tracking = ?", tracking).Limit(1).Find(&qcRibbon).Error; err != nil {
+		return nil, fmt.Errorf("failed to find QC ribbon: %w", err)
+	}
+	if qcRibbon.QcBy != nil {
+		addStage(*qcRibbon.QcBy, ComplainStageQcRibbon)
+	}
+
+	var qcOnline QcOnline
+	if err := tx.Where("tracking = ?", tracking).Limit(1).Find(&qcOnline).Error; err != nil {
+		return nil, fmt.Errorf("failed to find QC online: %w", err)
+	}
+	if qcOnline.QcBy != nil {
+		addStage(*qcOnline.QcBy, ComplainStageQcOnline)
+	}
+
+	var outbound Outbound
+	if err := tx.Where("tracking = ?", tracking).Limit(1).Find(&outbound).Error; err != nil {
+		return nil, fmt.Errorf("failed to find outbound: %w", err)
+	}
+	if outbound.OutboundBy != nil {
+		addStage(*outbound.OutboundBy, ComplainStageOutbound)
+	}
+
+	var order Order
+	if err := tx.Where("tracking = ?", tracking).Limit(1).Find(&order).Error; err != nil {
+		return nil, fmt.Errorf("failed to find order: %w", err)
+	}
+	if order.ID != 0 {
+		if order.PickedBy != nil {
+			addStage(*order.PickedBy, ComplainStagePicking)
+		}
+
+		var pickers []uint
+		if err := tx.Model(&PickedOrder{}).
+			Where("order_id = ?", order.ID).
+			Order("created_at ASC").
+			Pluck("picked_by", &pickers).Error; err != nil {
+			return nil, fmt.Errorf("failed to find pickers: %w", err)
+		}
+		for _, picker := range pickers {
+			addStage(picker, ComplainStagePicking)
+		}
+
+		if order.PendingBy != nil {
+			addStage(*order.PendingBy, ComplainStagePending)
+		}
+	}
+
+	var returns []Return
+	if err := tx.Where("old_tracking = ? OR new_tracking = ?", tracking, tracking).
+		Order("created_at ASC").
+		Find(&returns).Error; err != nil {
+		return nil, fmt.Errorf("failed to find returns: %w", err)
+	}
+	for _, ret := range returns {
+		addStage(ret.CreatedBy, ComplainStageReturn)
+	}
+
+	return userDetails, nil
+}
+
+// faultScoreInvolvedWeight is what a complain counts towards the fault score of an operator who
+// handled the parcel but was not marked at fault; complains with the operator at fault count 1
+const faultScoreInvolvedWeight = 0.5
+
+// OperatorFaultScore sums up the complains an operator was linked to against the parcels they
+// handled. Score is the weighted complains per 1000 parcels handled, lower is better.
+type OperatorFaultScore struct {
+	OperatorID uint                `json:"operator_id"`
+	FullName   string              `json:"full_name" example:"Jane Doe"`
+	Handled    int                 `json:"handled" example:"4200"` // Picks, QCs, outbounds and returns in the range
+	Complains  int                 `json:"complains" example:"6"`  // Complains linked to the operator
+	AtFault    int                 `json:"at_fault" example:"2"`   // Complains with the operator marked at fault
+	Stages     map[string]int      `json:"stages"`                 // Complains per stage the operator handled
+	Score      float64             `json:"score" example:"0.95"`   // Weighted complains per 1000 parcels handled
+	Weeks      []OperatorFaultWeek `json:"weeks"`                  // Score per week the operator was active, oldest first
+}
+
+// OperatorFaultWeek is the fault score of an operator in the week starting on WeekStart (a Monday)
+type OperatorFaultWeek struct {
+	WeekStart string  `json:"week_start" example:"2025-10-06"`
+	Handled   int     `json:"handled" example:"980"`
+	Complains int     `json:"complains" example:"1"`
+	AtFault   int     `json:"at_fault" example:"1"`
+	Score     float64 `json:"score" example:"1.02"`
+}
+
+// operatorHandledSources are the tables counting parcels handled per operator, with the operator
+// column
+var operatorHandledSources = []struct {
+	Table    string
+	Operator string
+}{
+	{"picked_orders", "picked_by"},
+	{"qc_ribbons", "qc_by"},
+	{"qc_onlines", "qc_by"},
+	{"outbounds", "outbound_by"},
+	{"returns", "created_by"},
+}
+
+// OperatorFaultScores computes the fault score of every operator who handled parcels or was linked
+// to a complain between start and end, week by week, worst score first. Complains count in the week
+// they were raised; drafts and rejected complains are left out.
+func OperatorFaultScores(db *gorm.DB, start, end time.Time) ([]OperatorFaultScore, error) {
+	scores := make(map[uint]*OperatorFaultScore)
+	score := func(operatorID uint) *OperatorFaultScore {
+		if existing, found := scores[operatorID]; found {
+			return existing
+		}
+		created := &OperatorFaultScore{OperatorID: operatorID, FullName: "-", Stages: map[string]int{}}
+		scores[operatorID] = created
+		return created
+	}
+
+	weekStart, _ := utilities.DayRange(start)
+	weekStart = weekStart.AddDate(0, 0, -((int(weekStart.Weekday()) + 6) % 7))
+	for ; weekStart.Before(end); weekStart = weekStart.AddDate(0, 0, 7) {
+		from := weekStart
+		if from.Before(start) {
+			from = start
+		}
+		to := weekStart.AddDate(0, 0, 7)
+		if to.After(end) {
+			to = end
+		}
+
+		weeks := make(map[uint]*OperatorFaultWeek)
+		week := func(operatorID uint) *OperatorFaultWeek {
+			if existing, found := weeks[operatorID]; found {
+				return existing
+			}
+			created := &OperatorFaultWeek{WeekStart: weekStart.Format("2006-01-02")}
+			weeks[operatorID] = created
+			return created
+		}
+
+		for _, source := range operatorHandledSources {
+			var counts []struct {
+				OperatorID uint
+				Count      int
+			}
+			if err := db.Table(source.Table).
+				Select(source.Operator+" AS operator_id, COUNT(*) AS count").
+				Where("deleted_at IS NULL AND "+source.Operator+" IS NOT NULL").
+				Where("created_at >= ? AND created_at < ?", from, to).
+				Group(source.Operator).
+				Scan(&counts).Error; err != nil {
+				return nil, fmt.Errorf("failed to count %s: %w", source.Table, err)
+			}
+			for _, count := range counts {
+				week(count.OperatorID).Handled += count.Count
+			}
+		}
+
+		var details []struct {
+			OperatorID uint
+			Stages     string
+			AtFault    bool
+		}
+		if err := db.Table("complain_user_details").
+			Select("complain_user_details.operator_id, complain_user_details.stages, complain_user_details.at_fault").
+			Joins("JOIN complains ON complains.id = complain_user_details.complain_id AND complains.deleted_at IS NULL").
+			Where("complain_user_details.deleted_at IS NULL").
+			Where("complains.status NOT IN ?", []string{ComplainStatusDraft, ComplainStatusRejected}).
+			Where("complains.created_at >= ? AND complains.created_at < ?", from, to).
+			Scan(&details).Error; err != nil {
+			return nil, fmt.Errorf("failed to load complain operators: %w", err)
+		}
+		for _, detail := range details {
+			week(detail.OperatorID).Complains++
+			if detail.AtFault {
+				week(detail.OperatorID).AtFault++
+			}
+			for _, stage := range (&ComplainUserDetail{Stages: detail.Stages}).StageList() {
+				score(detail.OperatorID).Stages[stage]++
+			}
+		}
+
+		for operatorID, operatorWeek := range weeks {
+			operatorWeek.Score = faultScore(operatorWeek.Complains, operatorWeek.AtFault, operatorWeek.Handled)
+			operatorScore := score(operatorID)
+			operatorScore.Handled += operatorWeek.Handled
+			operatorScore.Complains += operatorWeek.Complains
+			operatorScore.AtFault += operatorWeek.AtFault
+			operatorScore.Weeks = append(operatorScore.Weeks, *operatorWeek)
+		}
+	}
+
+	if len(scores) == 0 {
+		return []OperatorFaultScore{}, nil
+	}
+
+	operatorIDs := make([]uint, 0, len(scores))
+	for operatorID := range scores {
+		operatorIDs = append(operatorIDs, operatorID)
+	}
+	var users []User
+	if err := db.Select("id, full_name").Where("id IN ?", operatorIDs).Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to load operators: %w", err)
+	}
+	for _, user := range users {
+		scores[user.ID].FullName = user.FullName
+	}
+
+	result := make([]OperatorFaultScore, 0, len(scores))
+	for _, operatorScore := range scores {
+		operatorScore.Score = faultScore(operatorScore.Complains, operatorScore.AtFault, operatorScore.Handled)
+		result = append(result, *operatorScore)
+	}
+	sort.Slice(result, func(a, b int) bool {
+		if result[a].Score != result[b].Score {
+			return result[a].Score > result[b].Score
+		}
+		if result[a].Complains != result[b].Complains {
+			return result[a].Complains > result[b].Complains
+		}
+		return result[a].OperatorID < result[b].OperatorID
+	})
+	return result, nil
+}
+
+// faultScore weighs complains per 1000 parcels handled. Operators linked to complains without
+// handling a parcel in the period score as if they handled one.
+func faultScore(complains, atFault, handled int) float64 {
+	if complains == 0 {
+		return 0
+	}
+	points := float64(atFault) + float64(complains-atFault)*faultScoreInvolvedWeight
+	return points * 1000 / float64(max(handled, 1))
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Complain resolution statuses
+const (
+	ComplainStatusDraft         = "draft" // Raised automatically, e.g. by a QC discrepancy, until someone opens it
+	ComplainStatusOpen          = "open"
+	ComplainStatusInvestigating = "investigating"
+	ComplainStatusAwaitingBuyer = "awaiting_buyer"
+	ComplainStatusResolved      = "resolved"
+	ComplainStatusRejected      = "rejected"
+)
+
+// ComplainStatuses lists every resolution status in workflow order
+var ComplainStatuses = []string{
+	ComplainStatusDraft,
+	ComplainStatusOpen,
+	ComplainStatusInvestigating,
+	ComplainStatusAwaitingBuyer,
+	ComplainStatusResolved,
+	ComplainStatusRejected,
+}
+
+// complainStatusTransitions lists the statuses each status may move to. Drafts are opened or
+// rejected. Resolved and rejected complains can only be reopened by moving them back to investigating.
+var complainStatusTransitions = map[string][]string{
+	ComplainStatusDraft:         {ComplainStatusOpen, ComplainStatusRejected},
+	ComplainStatusOpen:          {ComplainStatusInvestigating, ComplainStatusAwaitingBuyer, ComplainStatusResolved, ComplainStatusRejected},
+	ComplainStatusInvestigating: {ComplainStatusAwaitingBuyer, ComplainStatusResolved, ComplainStatusRejected},
+	ComplainStatusAwaitingBuyer: {ComplainStatusInvestigating, ComplainStatusResolved, ComplainStatusRejected},
+	ComplainStatusResolved:      {ComplainStatusInvestigating},
+	ComplainStatusRejected:      {ComplainStatusInvestigating},
+}
+
+// IsFinalComplainStatus reports whether status closes the complain
+func IsFinalComplainStatus(status string) bool {
+	return status == ComplainStatusResolved || status == ComplainStatusRejected
+}
+
+// CanTransitionComplainStatus reports whether a complain may move from one status to another
+func CanTransitionComplainStatus(from, to string) bool {
+	if from == "" {
+		from = ComplainStatusOpen
+	}
+	return slices.Contains(complainStatusTransitions[from], to)
+}
+
+// TransitionStatus moves the complain to status, stamping the time it entered the status. Final
+// statuses mark the complain checked, which stops its SLA and escalation timers, and reopening
+// unchecks it again. assignedTo, when set, makes that user responsible for the complain and notifies them.
+func (c *Complain) TransitionStatus(tx *gorm.DB, status string, assignedTo *uint) error {
+	if !CanTransitionComplainStatus(c.Status, status) {
+		return fmt.Errorf("complain %s cannot move from %s to %s", c.Code, c.Status, status)
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{"status": status}
+	switch status {
+	case ComplainStatusInvestigating:
+		c.InvestigatingAt = &now
+		updates["investigating_at"] = now
+	case ComplainStatusAwaitingBuyer:
+		c.AwaitingBuyerAt = &now
+		updates["awaiting_buyer_at"] = now
+	case ComplainStatusResolved:
+		c.ResolvedAt = &now
+		updates["resolved_at"] = now
+	case ComplainStatusRejected:
+		c.RejectedAt = &now
+		updates["rejected_at"] = now
+	}
+
+	c.Status = status
+	c.Checked = IsFinalComplainStatus(status)
+	c.RefreshSLAStatus(now)
+	updates["checked"] = c.Checked
+	updates["sla_status"] = c.SlaStatus
+
+	assigned := assignedTo != nil && (c.AssignedTo == nil || *c.AssignedTo != *assignedTo)
+	if assigned {
+		c.AssignedTo = assignedTo
+		updates["assigned_to"] = *assignedTo
+	}
+
+	if err := tx.Model(c).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	if assigned {
+		notification := Notification{
+			UserID:        *assignedTo,
+			Type:          "complain_assigned",
+			Title:         "Complain assigned",
+			Message:       fmt.Sprintf("Complain %s (tracking %s) was assigned to you, status %s", c.Code, c.Tracking, status),
+			ReferenceType: "complain",
+			ReferenceID:   c.ID,
+		}
+		if err := tx.Create(&notification).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Config change actions
+const (
+	ConfigChangeSet   = "set"   // The override was created or changed
+	ConfigChangeReset = "reset" // The override was removed, the setting is back to its environment value
+)
+
+// ConfigOverride replaces the environment value of a reloadable setting (see config.ReloadableSettings)
+// until it is removed
+type ConfigOverride struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Key       string    `gorm:"unique;not null" json:"key" example:"INSTANT_SLA_MINUTES"`
+	Value     string    `gorm:"not null" json:"value" example:"90"`
+	UpdatedBy uint      `gorm:"not null" json:"updated_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationship
+	Updater *User `gorm:"foreignKey:UpdatedBy" json:"updater,omitempty"`
+}
+
+// ConfigChange is the audit trail of config overrides, one row per change
+type ConfigChange struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Key       string    `gorm:"not null;index" json:"key" example:"INSTANT_SLA_MINUTES"`
+	Action    string    `gorm:"not null" json:"action" example:"set"`
+	OldValue  string    `json:"old_value" example:"60"` // Effective value before the change
+	NewValue  string    `json:"new_value" example:"90"` // Effective value after the change
+	ChangedBy uint      `gorm:"not null" json:"changed_by"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relationship
+	Changer *User `gorm:"foreignKey:ChangedBy" json:"changer,omitempty"`
+}
+
+type ConfigChangeResponse struct {
+	ID        uint   `json:"id"`
+	Key       string `json:"key"`
+	Action    string `json:"action"`
+	OldValue  string `json:"old_value"`
+	NewValue  string `json:"new_value"`
+	ChangedBy string `json:"changed_by"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ToConfigChangeResponse converts ConfigChange model to ConfigChangeResponse
+func (c *ConfigChange) ToConfigChangeResponse() ConfigChangeResponse {
+	changedBy := "-"
+	if c.Changer != nil {
+		changedBy = c.Changer.FullName
+	}
+
+	return ConfigChangeResponse{
+		ID:        c.ID,
+		Key:       c.Key,
+		Action:    c.Action,
+		OldValue:  c.OldValue,
+		NewValue:  c.NewValue,
+		ChangedBy: changedBy,
+		CreatedAt: c.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// LoadConfigOverrides returns the config overrides by key
+func LoadConfigOverrides(db *gorm.DB) (map[string]ConfigOverride, error) {
+	var overrides []ConfigOverride
+	if err := db.Preload("Updater").Find(&overrides).Error; err != nil {
+		return nil, fmt.Errorf("failed to load config overrides: %w", err)
+	}
+
+	byKey := make(map[string]ConfigOverride, len(overrides))
+	for _, override := range overrides {
+		byKey[override.Key] = override
+	}
+	return byKey, nil
+}
+
+// LatestConfigChangeID returns the ID of the last config change, 0 when the config was never changed.
+// Processes compare it to tell whether another process changed the config.
+func LatestConfigChangeID(db *gorm.DB) (uint, error) {
+	var id uint
+	if err := db.Model(&ConfigChange{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error; err != nil {
+		return 0, fmt.Errorf("failed to load latest config change: %w", err)
+	}
+	return id, nil
+}
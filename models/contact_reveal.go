@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ContactReveal records who viewed the unmasked contact details of an order and why
+type ContactReveal struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	OrderID   uint           `gorm:"not null;index" json:"order_id"`
+	UserID    uint           `gorm:"not null;index" json:"user_id"`
+	Reason    string         `gorm:"not null" json:"reason" example:"Courier could not find the address"`
+	IPAddress string         `json:"ip_address" example:"192.168.31.52"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationship
+	Order *Order `gorm:"foreignKey:OrderID" json:"order,omitempty"`
+	User  *User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+type ContactRevealResponse struct {
+	ID        uint   `json:"id"`
+	OrderID   uint   `json:"order_id"`
+	Tracking  string `json:"tracking"`
+	UserID    uint   `json:"user_id"`
+	Username  string `json:"username"`
+	FullName  string `json:"full_name"`
+	Reason    string `json:"reason"`
+	IPAddress string `json:"ip_address"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ToContactRevealResponse converts ContactReveal model to ContactRevealResponse
+func (r *ContactReveal) ToContactRevealResponse() ContactRevealResponse {
+	tracking := "-"
+	if r.Order != nil {
+		tracking = r.Order.Tracking
+	}
+
+	username, fullName := "-", "-"
+	if r.User != nil {
+		username = r.User.Username
+		fullName = r.User.FullName
+	}
+
+	return ContactRevealResponse{
+		ID:        r.ID,
+		OrderID:   r.OrderID,
+		Tracking:  tracking,
+		UserID:    r.UserID,
+		Username:  username,
+		FullName:  fullName,
+		Reason:    r.Reason,
+		IPAddress: r.IPAddress,
+		CreatedAt: r.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
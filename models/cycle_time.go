@@ -0,0 +1,135 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CycleTimeStages are the stages of the order cycle, in order, with the events they run between.
+// QC ends with the last parcel QC'd, outbound with the first outbound scan.
+var CycleTimeStages = []struct {
+	Name string
+	From string
+	To   string
+}{
+	{"assignment", "created", "assigned"},
+	{"pick", "assigned", "picking complete"},
+	{"qc", "picking complete", "qc"},
+	{"outbound", "qc", "outbound"},
+	{"total", "created", "outbound"},
+}
+
+// CycleTimeStage is the distribution of the time orders spent in one stage. Orders that have not
+// reached the end of the stage yet are not counted.
+type CycleTimeStage struct {
+	Stage      string  `json:"stage" example:"pick"`
+	From       string  `json:"from" example:"assigned"`
+	To         string  `json:"to" example:"picking complete"`
+	Orders     int64   `json:"orders" example:"420"`
+	AvgSeconds float64 `json:"avg_seconds" example:"540.5"`
+	P50Seconds float64 `json:"p50_seconds" example:"420"`
+	P90Seconds float64 `json:"p90_seconds" example:"1260"`
+}
+
+// CycleTimes is the pick-to-outbound cycle time report
+type CycleTimes struct {
+	Orders     int64            `json:"orders" example:"500"` // Orders created in the range
+	Stages     []CycleTimeStage `json:"stages"`
+	Bottleneck string           `json:"bottleneck" example:"qc"` // Stage with the longest median, "-" without data
+}
+
+// CycleTimeFilter selects the orders of the cycle time report
+type CycleTimeFilter struct {
+	Start    time.Time
+	End      time.Time
+	Channel  string
+	PickerID uint
+}
+
+// BuildCycleTimes computes how long the orders created in the filter's range took from creation to
+// assignment, pick complete, QC and outbound: the average, median and 90th percentile of each stage.
+// Cancelled and merged orders are left out, as are stage times running backwards (e.g. an order
+// re-assigned after it was picked).
+func BuildCycleTimes(db *gorm.DB, filter CycleTimeFilter) (CycleTimes, error) {
+	conditions := []string{
+		"orders.deleted_at IS NULL",
+		"orders.created_at BETWEEN ? AND ?",
+		"(orders.event_status IS NULL OR orders.event_status <> 'cancelled')",
+		"orders.merged_into_id IS NULL",
+	}
+	// The first three arguments bound the QC and outbound scans, the others go with conditions
+	args := []interface{}{filter.Start, filter.Start, filter.Start, filter.Start, filter.End}
+	if filter.Channel != "" {
+		conditions = append(conditions, "orders.channel = ?")
+		args = append(args, filter.Channel)
+	}
+	if filter.PickerID != 0 {
+		conditions = append(conditions, "orders.picked_by = ?")
+		args = append(args, filter.PickerID)
+	}
+
+	// QC and outbound records cannot predate the orders, which bounds the scans of their tables
+	query := fmt.Sprintf(`
+		WITH qc AS (
+			SELECT tracking, MAX(created_at) AS qc_at FROM (
+				SELECT tracking, created_at FROM qc_ribbons WHERE deleted_at IS NULL AND created_at >= ?
+				UNION ALL
+				SELECT tracking, created_at FROM qc_onlines WHERE deleted_at IS NULL AND created_at >= ?
+			) parcels
+			GROUP BY tracking
+		), outbound AS (
+			SELECT tracking, MIN(created_at) AS outbound_at FROM outbounds
+			WHERE deleted_at IS NULL AND created_at >= ?
+			GROUP BY tracking
+		), cycles AS (
+			SELECT orders.created_at, orders.assigned_at, orders.picked_at, qc.qc_at, outbound.outbound_at
+			FROM orders
+			LEFT JOIN qc ON qc.tracking = orders.tracking
+			LEFT JOIN outbound ON outbound.tracking = orders.tracking
+			WHERE %s
+		)
+		SELECT stage, COUNT(*) AS orders, AVG(seconds) AS avg_seconds,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY seconds) AS p50_seconds,
+			percentile_cont(0.9) WITHIN GROUP (ORDER BY seconds) AS p90_seconds
+		FROM cycles CROSS JOIN LATERAL (VALUES
+			('assignment', EXTRACT(EPOCH FROM assigned_at - created_at)),
+			('pick', EXTRACT(EPOCH FROM picked_at - assigned_at)),
+			('qc', EXTRACT(EPOCH FROM qc_at - picked_at)),
+			('outbound', EXTRACT(EPOCH FROM outbound_at - qc_at)),
+			('total', EXTRACT(EPOCH FROM outbound_at - created_at))
+		) AS durations(stage, seconds)
+		WHERE seconds >= 0
+		GROUP BY stage`, strings.Join(conditions, " AND "))
+
+	var rows []CycleTimeStage
+	if err := db.Raw(query, args...).Scan(&rows).Error; err != nil {
+		return CycleTimes{}, fmt.Errorf("failed to compute cycle times: %w", err)
+	}
+	byStage := make(map[string]CycleTimeStage, len(rows))
+	for _, row := range rows {
+		byStage[row.Stage] = row
+	}
+
+	report := CycleTimes{Bottleneck: "-"}
+	countQuery := "SELECT COUNT(*) FROM orders WHERE " + strings.Join(conditions, " AND ")
+	if err := db.Raw(countQuery, args[3:]...).Scan(&report.Orders).Error; err != nil {
+		return CycleTimes{}, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	var slowest float64
+	for _, stage := range CycleTimeStages {
+		row := byStage[stage.Name]
+		row.Stage, row.From, row.To = stage.Name, stage.From, stage.To
+		report.Stages = append(report.Stages, row)
+
+		if stage.Name != "total" && row.Orders > 0 && row.P50Seconds > slowest {
+			slowest = row.P50Seconds
+			report.Bottleneck = stage.Name
+		}
+	}
+
+	return report, nil
+}
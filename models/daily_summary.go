@@ -0,0 +1,92 @@
+package models
+
+import (
+	"fmt"
+	"livo-backend/utilities"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ExpeditionCount is the number of outbounds handed to one expedition
+type ExpeditionCount struct {
+	Expedition string `json:"expedition" example:"JNE"`
+	Count      int64  `json:"count" example:"120"`
+}
+
+// DailySummary holds the warehouse throughput of a single day
+type DailySummary struct {
+	Date                  string            `json:"date" example:"2025-01-31"`
+	OrdersCreated         int64             `json:"orders_created"`
+	OrdersPicked          int64             `json:"orders_picked"`
+	OrdersCancelled       int64             `json:"orders_cancelled"`
+	QcRibbon              int64             `json:"qc_ribbon"`
+	QcOnline              int64             `json:"qc_online"`
+	Outbounds             int64             `json:"outbounds"`
+	OutboundsByExpedition []ExpeditionCount `json:"outbounds_by_expedition"`
+	Returns               int64             `json:"returns"`
+	Complains             int64             `json:"complains"`
+}
+
+// BuildDailySummary counts the work done between midnight of date and the next midnight, in the
+// warehouse timezone
+func BuildDailySummary(db *gorm.DB, date time.Time) (DailySummary, error) {
+	start, end := utilities.DayRange(date)
+
+	summary := DailySummary{
+		Date:                  start.Format("2006-01-02"),
+		OutboundsByExpedition: []ExpeditionCount{},
+	}
+
+	counts := []struct {
+		model  interface{}
+		column string
+		target *int64
+	}{
+		{&Order{}, "created_at", &summary.OrdersCreated},
+		{&Order{}, "picked_at", &summary.OrdersPicked},
+		{&Order{}, "cancelled_at", &summary.OrdersCancelled},
+		{&QcRibbon{}, "created_at", &summary.QcRibbon},
+		{&QcOnline{}, "created_at", &summary.QcOnline},
+		{&Outbound{}, "created_at", &summary.Outbounds},
+		{&Return{}, "created_at", &summary.Returns},
+		{&Complain{}, "created_at", &summary.Complains},
+	}
+	for _, count := range counts {
+		if err := db.Model(count.model).
+			Where(count.column+" >= ? AND "+count.column+" < ?", start, end).
+			Count(count.target).Error; err != nil {
+			return summary, err
+		}
+	}
+
+	if err := db.Model(&Outbound{}).
+		Select("expedition, COUNT(*) AS count").
+		Where("created_at >= ? AND created_at < ?", start, end).
+		Group("expedition").
+		Order("count DESC").
+		Scan(&summary.OutboundsByExpedition).Error; err != nil {
+		return summary, err
+	}
+
+	return summary, nil
+}
+
+// Text renders the summary as a plain text report for email
+func (s *DailySummary) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Daily summary for %s\n\n", s.Date)
+	fmt.Fprintf(&b, "Orders created:    %d\n", s.OrdersCreated)
+	fmt.Fprintf(&b, "Orders picked:     %d\n", s.OrdersPicked)
+	fmt.Fprintf(&b, "Orders cancelled:  %d\n", s.OrdersCancelled)
+	fmt.Fprintf(&b, "QC ribbon:         %d\n", s.QcRibbon)
+	fmt.Fprintf(&b, "QC online:         %d\n", s.QcOnline)
+	fmt.Fprintf(&b, "Outbounds:         %d\n", s.Outbounds)
+	for _, expedition := range s.OutboundsByExpedition {
+		fmt.Fprintf(&b, "  %-16s %d\n", expedition.Expedition, expedition.Count)
+	}
+	fmt.Fprintf(&b, "Returns:           %d\n", s.Returns)
+	fmt.Fprintf(&b, "Complains:         %d\n", s.Complains)
+	return b.String()
+}
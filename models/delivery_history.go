@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Normalised delivery statuses stored on orders
+const (
+	DeliveryStatusInTransit = "in_transit"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusReturned  = "returned"
+	DeliveryStatusFailed    = "failed"
+)
+
+// FinalDeliveryStatuses are statuses after which a tracking is no longer polled
+var FinalDeliveryStatuses = []string{DeliveryStatusDelivered, DeliveryStatusReturned}
+
+// DeliveryHistory is a single courier checkpoint pulled from a tracking integration
+type DeliveryHistory struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	OrderID     uint           `gorm:"not null;uniqueIndex:idx_delivery_history_event" json:"order_id"`
+	Tracking    string         `gorm:"not null;index" json:"tracking" example:"SPXID056205885386"`
+	Provider    string         `gorm:"not null" json:"provider" example:"shopee"`
+	Status      string         `gorm:"not null;uniqueIndex:idx_delivery_history_event" json:"status" example:"in_transit"`
+	Description string         `json:"description" example:"Parcel has arrived at sorting center"`
+	Location    string         `json:"location" example:"Jakarta Hub"`
+	EventAt     time.Time      `gorm:"not null;uniqueIndex:idx_delivery_history_event" json:"event_at"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type DeliveryHistoryResponse struct {
+	ID          uint   `json:"id"`
+	Tracking    string `json:"tracking"`
+	Provider    string `json:"provider"`
+	Status      string `json:"status"`
+	Description string `json:"description"`
+	Location    string `json:"location"`
+	EventAt     string `json:"event_at"`
+}
+
+// ToDeliveryHistoryResponse converts DeliveryHistory model to DeliveryHistoryResponse
+func (d *DeliveryHistory) ToDeliveryHistoryResponse() DeliveryHistoryResponse {
+	return DeliveryHistoryResponse{
+		ID:          d.ID,
+		Tracking:    d.Tracking,
+		Provider:    d.Provider,
+		Status:      d.Status,
+		Description: d.Description,
+		Location:    d.Location,
+		EventAt:     d.EventAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -1,41 +1,121 @@
 package models
 
 import (
+	"fmt"
+	"livo-backend/utilities"
+	"regexp"
 	"time"
 
 	"gorm.io/gorm"
 )
 
 type Expedition struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Code      string         `gorm:"unique;not null" json:"code" example:"JNE"`
-	Name      string         `gorm:"not null" json:"name" example:"J&T Express"`
-	Slug      string         `gorm:"not null" json:"slug" example:"j&t-express"`
-	Color     string         `json:"color" example:"#FF5733"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID    uint   `gorm:"primaryKey" json:"id"`
+	Code  string `gorm:"unique;not null" json:"code" example:"JNE"`
+	Name  string `gorm:"not null" json:"name" example:"J&T Express"`
+	Slug  string `gorm:"not null" json:"slug" example:"j&t-express"`
+	Color string `json:"color" example:"#FF5733"`
+	// Instant couriers (Grab, Gojek) pick up within hours, their orders are picked first and have an SLA in minutes
+	Instant bool `gorm:"default:false" json:"instant" example:"false"`
+	// Daily pickup time ("HH:MM", server local time), orders must be ready by the last pickup before their
+	// sent before. Empty when the courier picks up on demand.
+	PickupCutoff string `json:"pickup_cutoff" example:"16:00"`
+	// Tracking auto-detection, see ExpeditionMatcher. A match pattern (regular expression) replaces the
+	// code prefix, a higher match priority wins over longer matches.
+	MatchPattern  string `json:"match_pattern" example:"^JP[0-9]{10}$"`
+	MatchPriority int    `gorm:"not null;default:0" json:"match_priority" example:"0"`
+	// Delivery status pull integration, an empty provider disables polling for this expedition
+	TrackingProvider string `json:"tracking_provider" example:"shopee"`
+	TrackingEndpoint string `json:"tracking_endpoint" example:"https://tracking.example.com/status"`
+	TrackingAPIKey   string `json:"-"`
+	// Scan validation, zero values disable the corresponding check
+	TrackingMinLength int            `json:"tracking_min_length" example:"12"`
+	TrackingMaxLength int            `json:"tracking_max_length" example:"16"`
+	TrackingPattern   string         `json:"tracking_pattern" example:"^JNE[0-9]{10}$"`
+	TrackingChecksum  string         `json:"tracking_checksum" example:"luhn"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 type ExpeditionResponse struct {
-	ID      uint      `json:"id"`
-	Code    string    `json:"code"`
-	Name    string    `json:"name"`
-	Slug    string    `json:"slug"`
-	Color   string    `json:"color"`
-	Created time.Time `json:"created_at"`
-	Updated time.Time `json:"updated_at"`
+	ID                uint      `json:"id"`
+	Code              string    `json:"code"`
+	Name              string    `json:"name"`
+	Slug              string    `json:"slug"`
+	Color             string    `json:"color"`
+	Instant           bool      `json:"instant"`
+	PickupCutoff      string    `json:"pickup_cutoff"`
+	MatchPattern      string    `json:"match_pattern"`
+	MatchPriority     int       `json:"match_priority"`
+	TrackingProvider  string    `json:"tracking_provider"`
+	TrackingEndpoint  string    `json:"tracking_endpoint"`
+	TrackingMinLength int       `json:"tracking_min_length"`
+	TrackingMaxLength int       `json:"tracking_max_length"`
+	TrackingPattern   string    `json:"tracking_pattern"`
+	TrackingChecksum  string    `json:"tracking_checksum"`
+	Created           time.Time `json:"created_at"`
+	Updated           time.Time `json:"updated_at"`
+	DeletedAt         string    `json:"deleted_at,omitempty"` // Only set on deleted expeditions listed in the trash
 }
 
 // ToExpeditionResponse converts Expedition model to ExpeditionResponse
 func (e *Expedition) ToExpeditionResponse() ExpeditionResponse {
+	deletedAt := ""
+	if e.DeletedAt.Valid {
+		deletedAt = e.DeletedAt.Time.Format("2006-01-02 15:04:05")
+	}
+
 	return ExpeditionResponse{
-		ID:      e.ID,
-		Code:    e.Code,
-		Name:    e.Name,
-		Slug:    e.Slug,
-		Color:   e.Color,
-		Created: e.CreatedAt,
-		Updated: e.UpdatedAt,
+		ID:                e.ID,
+		Code:              e.Code,
+		Name:              e.Name,
+		Slug:              e.Slug,
+		Color:             e.Color,
+		Instant:           e.Instant,
+		PickupCutoff:      e.PickupCutoff,
+		MatchPattern:      e.MatchPattern,
+		MatchPriority:     e.MatchPriority,
+		TrackingProvider:  e.TrackingProvider,
+		TrackingEndpoint:  e.TrackingEndpoint,
+		TrackingMinLength: e.TrackingMinLength,
+		TrackingMaxLength: e.TrackingMaxLength,
+		TrackingPattern:   e.TrackingPattern,
+		TrackingChecksum:  e.TrackingChecksum,
+		Created:           e.CreatedAt,
+		Updated:           e.UpdatedAt,
+		DeletedAt:         deletedAt,
 	}
 }
+
+// ValidateTracking checks a scanned tracking number against the expedition's length, pattern and checksum rules
+func (e *Expedition) ValidateTracking(tracking string) error {
+	if e.TrackingMinLength > 0 && len(tracking) < e.TrackingMinLength {
+		return fmt.Errorf("%s tracking must be at least %d characters, got %d", e.Name, e.TrackingMinLength, len(tracking))
+	}
+	if e.TrackingMaxLength > 0 && len(tracking) > e.TrackingMaxLength {
+		return fmt.Errorf("%s tracking must be at most %d characters, got %d", e.Name, e.TrackingMaxLength, len(tracking))
+	}
+
+	if e.TrackingPattern != "" {
+		pattern, err := regexp.Compile(e.TrackingPattern)
+		if err != nil {
+			return fmt.Errorf("%s tracking pattern is invalid: %v", e.Name, err)
+		}
+		if !pattern.MatchString(tracking) {
+			return fmt.Errorf("tracking %s does not match the %s format", tracking, e.Name)
+		}
+	}
+
+	return utilities.ValidateTrackingChecksum(e.TrackingChecksum, tracking)
+}
+
+// FindExpeditionByTracking returns the expedition the tracking resolves to, or nil when none matches
+func FindExpeditionByTracking(db *gorm.DB, tracking string) (*Expedition, error) {
+	matcher, err := LoadExpeditionMatcher(db)
+	if err != nil {
+		return nil, err
+	}
+
+	return matcher.Match(tracking), nil
+}
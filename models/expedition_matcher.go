@@ -0,0 +1,154 @@
+package models
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// How an expedition matched a tracking
+const (
+	ExpeditionMatchPattern = "pattern"
+	ExpeditionMatchPrefix  = "prefix"
+)
+
+// expeditionMatcherTTL bounds how long a cached matcher is used. Saves in this process invalidate it
+// right away, the TTL covers edits made through another process (the worker or another replica).
+const expeditionMatcherTTL = time.Minute
+
+// ExpeditionMatch is an expedition a tracking resolves to and why
+type ExpeditionMatch struct {
+	Expedition Expedition `json:"-"`
+	Rule       string     `json:"rule" example:"prefix"` // pattern or prefix
+	Matched    string     `json:"matched" example:"JP"`  // Part of the tracking the rule matched
+	Priority   int        `json:"priority" example:"0"`
+}
+
+type expeditionRule struct {
+	expedition Expedition
+	pattern    *regexp.Regexp
+}
+
+// ExpeditionMatcher resolves trackings to expeditions. An expedition with a match pattern matches the
+// trackings its regular expression finds, any other expedition the trackings starting with its code.
+// When several match, the highest match priority wins, then the longest matched part, then the oldest
+// expedition, so "JP" beats "J" for JP trackings whatever order the expeditions were created in.
+type ExpeditionMatcher struct {
+	rules []expeditionRule
+}
+
+// NewExpeditionMatcher compiles the match rules of the expeditions. Expeditions with an invalid pattern
+// are left out, patterns are validated when expeditions are saved.
+func NewExpeditionMatcher(expeditions []Expedition) *ExpeditionMatcher {
+	matcher := &ExpeditionMatcher{rules: make([]expeditionRule, 0, len(expeditions))}
+	for _, expedition := range expeditions {
+		rule := expeditionRule{expedition: expedition}
+		if expedition.MatchPattern != "" {
+			pattern, err := regexp.Compile(expedition.MatchPattern)
+			if err != nil {
+				continue
+			}
+			rule.pattern = pattern
+		} else if expedition.Code == "" {
+			continue
+		}
+		matcher.rules = append(matcher.rules, rule)
+	}
+
+	return matcher
+}
+
+// Candidates returns every expedition matching the tracking, best match first
+func (m *ExpeditionMatcher) Candidates(tracking string) []ExpeditionMatch {
+	var matches []ExpeditionMatch
+	for _, rule := range m.rules {
+		match := ExpeditionMatch{Expedition: rule.expedition, Priority: rule.expedition.MatchPriority}
+		if rule.pattern != nil {
+			loc := rule.pattern.FindStringIndex(tracking)
+			if loc == nil {
+				continue
+			}
+			match.Rule = ExpeditionMatchPattern
+			match.Matched = tracking[loc[0]:loc[1]]
+		} else {
+			if !strings.HasPrefix(tracking, rule.expedition.Code) {
+				continue
+			}
+			match.Rule = ExpeditionMatchPrefix
+			match.Matched = rule.expedition.Code
+		}
+		matches = append(matches, match)
+	}
+
+	sort.SliceStable(matches, func(a, b int) bool {
+		if matches[a].Priority != matches[b].Priority {
+			return matches[a].Priority > matches[b].Priority
+		}
+		if len(matches[a].Matched) != len(matches[b].Matched) {
+			return len(matches[a].Matched) > len(matches[b].Matched)
+		}
+		return matches[a].Expedition.ID < matches[b].Expedition.ID
+	})
+
+	return matches
+}
+
+// Match returns the expedition the tracking resolves to, or nil when none matches. The expedition is
+// a copy, callers may change it freely.
+func (m *ExpeditionMatcher) Match(tracking string) *Expedition {
+	matches := m.Candidates(tracking)
+	if len(matches) == 0 {
+		return nil
+	}
+	expedition := matches[0].Expedition
+	return &expedition
+}
+
+var expeditionMatcherCache struct {
+	sync.Mutex
+	matcher  *ExpeditionMatcher
+	loadedAt time.Time
+}
+
+// LoadExpeditionMatcher returns the cached matcher, loading the expeditions when the cache is empty or
+// older than its TTL
+func LoadExpeditionMatcher(db *gorm.DB) (*ExpeditionMatcher, error) {
+	expeditionMatcherCache.Lock()
+	defer expeditionMatcherCache.Unlock()
+
+	if expeditionMatcherCache.matcher != nil && time.Since(expeditionMatcherCache.loadedAt) < expeditionMatcherTTL {
+		return expeditionMatcherCache.matcher, nil
+	}
+
+	var expeditions []Expedition
+	if err := db.Session(&gorm.Session{NewDB: true}).Order("id ASC").Find(&expeditions).Error; err != nil {
+		return nil, err
+	}
+
+	expeditionMatcherCache.matcher = NewExpeditionMatcher(expeditions)
+	expeditionMatcherCache.loadedAt = time.Now()
+	return expeditionMatcherCache.matcher, nil
+}
+
+// InvalidateExpeditionMatcher drops the cached matcher so the next lookup reloads the expeditions
+func InvalidateExpeditionMatcher() {
+	expeditionMatcherCache.Lock()
+	expeditionMatcherCache.matcher = nil
+	expeditionMatcherCache.Unlock()
+}
+
+// AfterSave drops the cached matcher when an expedition is created or updated
+func (e *Expedition) AfterSave(tx *gorm.DB) error {
+	InvalidateExpeditionMatcher()
+	return nil
+}
+
+// AfterDelete drops the cached matcher when an expedition is removed
+func (e *Expedition) AfterDelete(tx *gorm.DB) error {
+	InvalidateExpeditionMatcher()
+	return nil
+}
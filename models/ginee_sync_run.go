@@ -0,0 +1,105 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Ginee sync run statuses
+const (
+	GineeSyncStatusRunning   = "running"
+	GineeSyncStatusCompleted = "completed"
+	GineeSyncStatusFailed    = "failed"
+)
+
+// GineeSyncRun records one poll of the Ginee OpenAPI for paid orders updated in a window, with what
+// became of the orders fetched. Counts are saved after every page, so a running record that stops
+// being updated belongs to a dead process.
+type GineeSyncRun struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Status      string         `gorm:"not null;index" json:"status" example:"completed"`
+	WindowStart time.Time      `gorm:"not null" json:"window_start"` // Orders updated from here
+	WindowEnd   time.Time      `gorm:"not null" json:"window_end"`   // Up to here, the next run starts from it
+	Fetched     int            `gorm:"not null;default:0" json:"fetched" example:"42"`
+	Created     int            `gorm:"not null;default:0" json:"created" example:"38"`
+	Skipped     int            `gorm:"not null;default:0" json:"skipped" example:"3"` // Known already or no tracking yet
+	Failed      int            `gorm:"not null;default:0" json:"failed" example:"1"`
+	Errors      string         `json:"errors"` // One line per failed order, capped
+	Error       string         `json:"error"`  // Why the run itself failed
+	StartedAt   time.Time      `gorm:"not null" json:"started_at"`
+	FinishedAt  *time.Time     `gorm:"default:null" json:"finished_at"`
+	TriggeredBy *uint          `gorm:"default:null" json:"triggered_by"` // Null when run on schedule
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationship
+	Trigger *User `gorm:"foreignKey:TriggeredBy" json:"trigger,omitempty"`
+}
+
+// GineeSyncErrorLimit caps the order errors kept on a run
+const GineeSyncErrorLimit = 50
+
+// AddError records why an order of the run failed, up to GineeSyncErrorLimit errors
+func (r *GineeSyncRun) AddError(message string) {
+	r.Failed++
+	if r.Failed > GineeSyncErrorLimit {
+		return
+	}
+	if r.Errors != "" {
+		r.Errors += "\n"
+	}
+	r.Errors += message
+}
+
+type GineeSyncRunResponse struct {
+	ID          uint     `json:"id"`
+	Status      string   `json:"status"`
+	WindowStart string   `json:"window_start"`
+	WindowEnd   string   `json:"window_end"`
+	Fetched     int      `json:"fetched"`
+	Created     int      `json:"created"`
+	Skipped     int      `json:"skipped"`
+	Failed      int      `json:"failed"`
+	Errors      []string `json:"errors"`
+	Error       string   `json:"error"`
+	StartedAt   string   `json:"started_at"`
+	FinishedAt  string   `json:"finished_at"`
+	TriggeredBy string   `json:"triggered_by"`
+}
+
+// ToGineeSyncRunResponse converts GineeSyncRun model to GineeSyncRunResponse
+func (r *GineeSyncRun) ToGineeSyncRunResponse() GineeSyncRunResponse {
+	finishedAt := "-"
+	if r.FinishedAt != nil {
+		finishedAt = r.FinishedAt.Format("2006-01-02 15:04:05")
+	}
+
+	triggeredBy := "schedule"
+	if r.Trigger != nil {
+		triggeredBy = r.Trigger.FullName
+	}
+
+	errors := []string{}
+	if r.Errors != "" {
+		errors = strings.Split(r.Errors, "\n")
+	}
+
+	return GineeSyncRunResponse{
+		ID:          r.ID,
+		Status:      r.Status,
+		WindowStart: r.WindowStart.Format("2006-01-02 15:04:05"),
+		WindowEnd:   r.WindowEnd.Format("2006-01-02 15:04:05"),
+		Fetched:     r.Fetched,
+		Created:     r.Created,
+		Skipped:     r.Skipped,
+		Failed:      r.Failed,
+		Errors:      errors,
+		Error:       r.Error,
+		StartedAt:   r.StartedAt.Format("2006-01-02 15:04:05"),
+		FinishedAt:  finishedAt,
+		TriggeredBy: triggeredBy,
+	}
+}
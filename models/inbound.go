@@ -0,0 +1,215 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Inbound statuses
+const (
+	InboundStatusExpected  = "expected"  // Announced, nothing counted yet
+	InboundStatusReceiving = "receiving" // Lines are being counted
+	InboundStatusReceived  = "received"  // Receiving completed
+)
+
+// Inbound is a shipment from a supplier arriving at the warehouse, with the SKUs and quantities the
+// supplier announced. Receiving staff count each line on the mobile checklist and complete the
+// inbound once every line is checked.
+type Inbound struct {
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	Reference     string         `gorm:"unique;not null" json:"reference" example:"PO-2510-0042"` // Supplier delivery note or purchase order number
+	Supplier      string         `gorm:"not null;index" json:"supplier" example:"CV Sumber Makmur"`
+	ExpectedAt    *time.Time     `gorm:"default:null" json:"expected_at"`
+	Status        string         `gorm:"not null;default:'expected';index" json:"status" example:"expected"`
+	Notes         string         `json:"notes" example:"Two pallets, call before unloading"`
+	CreatedBy     *uint          `gorm:"default:null" json:"created_by"`
+	ReceivedBy    *uint          `gorm:"default:null" json:"received_by"`
+	ReceivedAt    *time.Time     `gorm:"default:null" json:"received_at"`
+	StockPostedAt *time.Time     `gorm:"default:null" json:"stock_posted_at"` // Empty until an inventory subsystem adds the accepted quantities to stock
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationship
+	InboundDetails []InboundDetail `gorm:"foreignKey:InboundID" json:"details"`
+	Creator        *User           `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
+	Receiver       *User           `gorm:"foreignKey:ReceivedBy" json:"receiver,omitempty"`
+}
+
+// InboundDetail is one SKU of an inbound with the quantity announced and, once checked, the
+// quantity counted on arrival
+type InboundDetail struct {
+	ID               uint           `gorm:"primaryKey" json:"id"`
+	InboundID        uint           `gorm:"not null;index" json:"inbound_id"`
+	ProductSKU       string         `gorm:"not null" json:"product_sku" example:"LY-GLIPOW-128-HL705-30G"`
+	ExpectedQuantity int            `gorm:"not null" json:"expected_quantity" example:"100"`
+	ReceivedQuantity int            `gorm:"not null;default:0" json:"received_quantity" example:"98"`
+	DamagedQuantity  int            `gorm:"not null;default:0" json:"damaged_quantity" example:"2"` // Part of the received quantity unfit for stock
+	Note             string         `json:"note" example:"Two boxes crushed"`
+	CheckedBy        *uint          `gorm:"default:null" json:"checked_by"`
+	CheckedAt        *time.Time     `gorm:"default:null" json:"checked_at"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relations
+	Inbound Inbound  `gorm:"foreignKey:InboundID" json:"-"`
+	Product *Product `gorm:"foreignKey:ProductSKU;references:Sku" json:"product,omitempty"`
+	Checker *User    `gorm:"foreignKey:CheckedBy" json:"checker,omitempty"`
+}
+
+// AcceptedQuantity is the received quantity fit for stock
+func (d *InboundDetail) AcceptedQuantity() int {
+	return d.ReceivedQuantity - d.DamagedQuantity
+}
+
+// Response structures
+type InboundDetailResponse struct {
+	ID               uint             `json:"id"`
+	ProductSKU       string           `json:"product_sku"`
+	ExpectedQuantity int              `json:"expected_quantity"`
+	ReceivedQuantity int              `json:"received_quantity"`
+	DamagedQuantity  int              `json:"damaged_quantity"`
+	AcceptedQuantity int              `json:"accepted_quantity"`
+	Discrepancy      int              `json:"discrepancy" example:"-2"` // Received minus expected, once checked
+	Checked          bool             `json:"checked"`
+	Note             string           `json:"note"`
+	CheckedBy        string           `json:"checked_by"`
+	CheckedAt        string           `json:"checked_at"`
+	Product          *ProductResponse `json:"product,omitempty"`
+}
+
+type InboundResponse struct {
+	ID            uint                    `json:"id"`
+	Reference     string                  `json:"reference"`
+	Supplier      string                  `json:"supplier"`
+	ExpectedAt    string                  `json:"expected_at"`
+	Status        string                  `json:"status"`
+	Notes         string                  `json:"notes"`
+	CreatedBy     string                  `json:"created_by"`
+	ReceivedBy    string                  `json:"received_by"`
+	ReceivedAt    string                  `json:"received_at"`
+	StockPostedAt string                  `json:"stock_posted_at"`
+	LineCount     int                     `json:"line_count" example:"12"`
+	CheckedCount  int                     `json:"checked_count" example:"5"`
+	ExpectedTotal int                     `json:"expected_total" example:"500"`
+	ReceivedTotal int                     `json:"received_total" example:"240"`
+	CreatedAt     time.Time               `json:"created_at"`
+	UpdatedAt     time.Time               `json:"updated_at"`
+	Details       []InboundDetailResponse `json:"details"`
+}
+
+// ToInboundDetailResponse converts InboundDetail model to InboundDetailResponse
+func (d *InboundDetail) ToInboundDetailResponse() InboundDetailResponse {
+	response := InboundDetailResponse{
+		ID:               d.ID,
+		ProductSKU:       d.ProductSKU,
+		ExpectedQuantity: d.ExpectedQuantity,
+		ReceivedQuantity: d.ReceivedQuantity,
+		DamagedQuantity:  d.DamagedQuantity,
+		AcceptedQuantity: d.AcceptedQuantity(),
+		Checked:          d.CheckedAt != nil,
+		Note:             d.Note,
+		CheckedBy:        "-",
+		CheckedAt:        "-",
+	}
+	if d.CheckedAt != nil {
+		response.Discrepancy = d.ReceivedQuantity - d.ExpectedQuantity
+		response.CheckedAt = d.CheckedAt.Format("2006-01-02 15:04:05")
+	}
+	if d.Checker != nil {
+		response.CheckedBy = d.Checker.FullName
+	}
+	if d.Product != nil {
+		productResponse := d.Product.ToProductResponse()
+		response.Product = &productResponse
+	}
+	return response
+}
+
+// ToInboundResponse converts Inbound model to InboundResponse
+func (i *Inbound) ToInboundResponse() InboundResponse {
+	response := InboundResponse{
+		ID:            i.ID,
+		Reference:     i.Reference,
+		Supplier:      i.Supplier,
+		ExpectedAt:    "-",
+		Status:        i.Status,
+		Notes:         i.Notes,
+		CreatedBy:     "-",
+		ReceivedBy:    "-",
+		ReceivedAt:    "-",
+		StockPostedAt: "-",
+		LineCount:     len(i.InboundDetails),
+		CreatedAt:     i.CreatedAt,
+		UpdatedAt:     i.UpdatedAt,
+		Details:       make([]InboundDetailResponse, len(i.InboundDetails)),
+	}
+	if i.ExpectedAt != nil {
+		response.ExpectedAt = i.ExpectedAt.Format("2006-01-02")
+	}
+	if i.Creator != nil {
+		response.CreatedBy = i.Creator.FullName
+	}
+	if i.Receiver != nil {
+		response.ReceivedBy = i.Receiver.FullName
+	}
+	if i.ReceivedAt != nil {
+		response.ReceivedAt = i.ReceivedAt.Format("2006-01-02 15:04:05")
+	}
+	if i.StockPostedAt != nil {
+		response.StockPostedAt = i.StockPostedAt.Format("2006-01-02 15:04:05")
+	}
+
+	for j := range i.InboundDetails {
+		detail := &i.InboundDetails[j]
+		response.Details[j] = detail.ToInboundDetailResponse()
+		response.ExpectedTotal += detail.ExpectedQuantity
+		if detail.CheckedAt != nil {
+			response.CheckedCount++
+			response.ReceivedTotal += detail.ReceivedQuantity
+		}
+	}
+	return response
+}
+
+// DetailByCode returns the line of a scanned SKU or product barcode, nil when the inbound has none.
+// Products must be attached to the lines to match barcodes.
+func (i *Inbound) DetailByCode(code string) *InboundDetail {
+	code = strings.TrimSpace(code)
+	for j := range i.InboundDetails {
+		detail := &i.InboundDetails[j]
+		if strings.EqualFold(detail.ProductSKU, code) {
+			return detail
+		}
+		if detail.Product != nil && detail.Product.Barcode != "" && detail.Product.Barcode == code {
+			return detail
+		}
+	}
+	return nil
+}
+
+// UncheckedSKUs returns the SKUs of the lines not counted yet
+func (i *Inbound) UncheckedSKUs() []string {
+	var skus []string
+	for _, detail := range i.InboundDetails {
+		if detail.CheckedAt == nil {
+			skus = append(skus, detail.ProductSKU)
+		}
+	}
+	return skus
+}
+
+// ValidateInboundCount checks the quantities counted for a line
+func ValidateInboundCount(received, damaged int) error {
+	if received < 0 || damaged < 0 {
+		return fmt.Errorf("quantities cannot be negative")
+	}
+	if damaged > received {
+		return fmt.Errorf("damaged quantity %d exceeds the received quantity %d", damaged, received)
+	}
+	return nil
+}
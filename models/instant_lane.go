@@ -0,0 +1,154 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FindOrderExpedition returns the expedition an order ships with: the one whose code, name or slug
+// matches the order's courier, falling back to the tracking prefix. Nil when none matches.
+func FindOrderExpedition(db *gorm.DB, order *Order) (*Expedition, error) {
+	expedition, err := FindCourierExpedition(db, order.Courier)
+	if err != nil || expedition != nil {
+		return expedition, err
+	}
+
+	if order.Tracking == "" {
+		return nil, nil
+	}
+
+	return FindExpeditionByTracking(db, order.Tracking)
+}
+
+// FindCourierExpedition returns the expedition whose code, name or slug is courier, nil when none is
+func FindCourierExpedition(db *gorm.DB, courier string) (*Expedition, error) {
+	courier = strings.TrimSpace(courier)
+	if courier == "" {
+		return nil, nil
+	}
+
+	var expedition Expedition
+	err := db.Where("LOWER(code) = LOWER(?) OR LOWER(name) = LOWER(?) OR slug = LOWER(?)", courier, courier, courier).
+		First(&expedition).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &expedition, nil
+}
+
+// ParsePickupCutoff parses an expedition pickup cutoff ("HH:MM"), returning the minutes after
+// midnight or -1 when the cutoff is empty
+func ParsePickupCutoff(cutoff string) (int, error) {
+	if cutoff == "" {
+		return -1, nil
+	}
+	parsed, err := time.Parse("15:04", cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("pickup_cutoff must be a time in HH:MM format, got %q", cutoff)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// ShipByDeadline returns when an order with the given sent before must be ready for the expedition:
+// the last pickup cutoff at or before sent before, or sent before itself without a cutoff
+func (e *Expedition) ShipByDeadline(sentBefore time.Time) time.Time {
+	minutes, err := ParsePickupCutoff(e.PickupCutoff)
+	if err != nil || minutes < 0 {
+		return sentBefore
+	}
+
+	year, month, day := sentBefore.Date()
+	deadline := time.Date(year, month, day, minutes/60, minutes%60, 0, 0, sentBefore.Location())
+	if deadline.After(sentBefore) {
+		deadline = deadline.AddDate(0, 0, -1)
+	}
+	return deadline
+}
+
+// ApplyCourierLane puts order in the instant lane when it ships with an instant courier, starting
+// its SLA from the order's creation, and takes it out again when the courier changes. Other orders
+// are due by their ship-by deadline, recalculated here from sent before and the expedition's pickup
+// cutoff; a new deadline clears an earlier breach.
+func (o *Order) ApplyCourierLane(db *gorm.DB) error {
+	expedition, err := FindOrderExpedition(db, o)
+	if err != nil {
+		return err
+	}
+
+	wasInstant := o.Instant
+	o.Instant = expedition != nil && expedition.Instant
+	if o.Instant {
+		if o.SLADueAt == nil || !wasInstant {
+			start := o.CreatedAt
+			if start.IsZero() {
+				start = time.Now()
+			}
+			dueAt := start.Add(CurrentSettings().InstantSLA)
+			o.SLADueAt = &dueAt
+			o.SLABreachedAt = nil
+		}
+		return nil
+	}
+
+	var dueAt *time.Time
+	if !o.SentBefore.IsZero() {
+		deadline := o.SentBefore
+		if expedition != nil {
+			deadline = expedition.ShipByDeadline(o.SentBefore)
+		}
+		dueAt = &deadline
+	}
+	if dueAt == nil || o.SLADueAt == nil || !dueAt.Equal(*o.SLADueAt) {
+		o.SLABreachedAt = nil
+	}
+	o.SLADueAt = dueAt
+
+	return nil
+}
+
+// FlagMissedDeadline marks an order still in the warehouse whose recalculated deadline has already
+// passed as breached and alerts coordinators. The caller saves the order. Reports whether it flagged.
+func (o *Order) FlagMissedDeadline(tx *gorm.DB, now time.Time) (bool, error) {
+	if o.SLADueAt == nil || o.SLABreachedAt != nil || !o.SLADueAt.Before(now) {
+		return false, nil
+	}
+	if o.ProcessingStatus == "outbound completed" || (o.EventStatus != nil && *o.EventStatus == "cancelled") {
+		return false, nil
+	}
+
+	o.SLABreachedAt = &now
+	err := NotifyUsersWithRoles(tx,
+		[]string{"coordinator"},
+		"order_deadline_breached",
+		"Order deadline already breached",
+		fmt.Sprintf("Order %s (tracking %s, courier %s) had to be ready by %s and is still %s", o.OrderGineeID, o.Tracking, o.Courier, o.SLADueAt.Format("2006-01-02 15:04"), o.ProcessingStatus),
+		"order",
+		o.ID,
+	)
+	return true, err
+}
+
+// BeforeCreate puts new orders from every source (manual, bulk, duplicates) in their courier lane
+// with the priority of their sent before
+func (o *Order) BeforeCreate(tx *gorm.DB) error {
+	o.ApplyPriority(time.Now())
+	return o.ApplyCourierLane(tx.Session(&gorm.Session{NewDB: true}))
+}
+
+// OverdueInstantOrders returns instant orders still in the warehouse past their SLA that
+// coordinators have not been alerted about yet
+func OverdueInstantOrders(db *gorm.DB, now time.Time) ([]Order, error) {
+	var orders []Order
+	err := db.Where("instant = ? AND sla_breached_at IS NULL AND sla_due_at < ?", true, now).
+		Where("processing_status <> ?", "outbound completed").
+		Where("event_status IS NULL OR event_status <> ?", "cancelled").
+		Order("sla_due_at ASC").
+		Find(&orders).Error
+	return orders, err
+}
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Monitored integrations
+const (
+	IntegrationCourierAPI = "courier_api" // Delivery status pulls from the expeditions' tracking providers
+	IntegrationGinee      = "ginee_api"   // Order intake from the Ginee OpenAPI
+	IntegrationEmail      = "email"       // Outgoing mail through SMTP
+	IntegrationWebhook    = "webhook"     // Report webhooks
+	IntegrationGeocoding  = "geocoding"   // Address validation of created and edited orders
+)
+
+// Integration health statuses
+const (
+	IntegrationStatusOK       = "ok"
+	IntegrationStatusDegraded = "degraded"
+	IntegrationStatusIdle     = "idle" // No calls in the window
+)
+
+// IntegrationHealthPolicy decides when an integration counts as degraded: at least MinCalls calls in
+// the last Window, of which at least ErrorRate (0-1) failed
+type IntegrationHealthPolicy struct {
+	Window    time.Duration
+	ErrorRate float64
+	MinCalls  int
+}
+
+// IntegrationCall counts the calls made to an integration in one minute. Both the API and the worker
+// process record into it, so health reflects whichever one runs the integration.
+type IntegrationCall struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	Integration   string     `gorm:"not null;uniqueIndex:idx_integration_minute" json:"integration" example:"courier_api"`
+	Minute        time.Time  `gorm:"not null;uniqueIndex:idx_integration_minute;index" json:"minute"`
+	Successes     int        `gorm:"not null;default:0" json:"successes"`
+	Failures      int        `gorm:"not null;default:0" json:"failures"`
+	LastError     string     `json:"last_error"`
+	LastErrorAt   *time.Time `json:"last_error_at"`
+	LastSuccessAt *time.Time `json:"last_success_at"`
+}
+
+// IntegrationState remembers the last reported status of an integration so alerts are raised once
+// per degradation and once on recovery
+type IntegrationState struct {
+	Integration string     `gorm:"primaryKey" json:"integration"`
+	Status      string     `gorm:"not null" json:"status" example:"ok"`
+	ChangedAt   time.Time  `json:"changed_at"`
+	AlertedAt   *time.Time `json:"alerted_at"`
+}
+
+// IntegrationStatus is the health of one integration over the policy window
+type IntegrationStatus struct {
+	Integration   string  `json:"integration" example:"courier_api"`
+	Status        string  `json:"status" example:"ok"`
+	Calls         int     `json:"calls" example:"120"`
+	Failures      int     `json:"failures" example:"3"`
+	ErrorRate     float64 `json:"error_rate" example:"0.025"`
+	LastError     string  `json:"last_error"`
+	LastErrorAt   string  `json:"last_error_at"`
+	LastSuccessAt string  `json:"last_success_at"`
+}
+
+// RecordIntegrationCall counts one call to an integration, failed when err is not nil. Callers log a
+// returned error rather than failing the call, health tracking is best effort.
+func RecordIntegrationCall(db *gorm.DB, integration string, err error) error {
+	now := time.Now()
+	call := IntegrationCall{Integration: integration, Minute: now.Truncate(time.Minute)}
+	updates := map[string]interface{}{}
+	if err != nil {
+		call.Failures = 1
+		call.LastError = err.Error()
+		call.LastErrorAt = &now
+		updates["failures"] = gorm.Expr("integration_calls.failures + 1")
+		updates["last_error"] = call.LastError
+		updates["last_error_at"] = now
+	} else {
+		call.Successes = 1
+		call.LastSuccessAt = &now
+		updates["successes"] = gorm.Expr("integration_calls.successes + 1")
+		updates["last_success_at"] = now
+	}
+
+	return db.Session(&gorm.Session{NewDB: true}).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "integration"}, {Name: "minute"}},
+		DoUpdates: clause.Assignments(updates),
+	}).Create(&call).Error
+}
+
+// IntegrationStatuses returns the health of every integration with calls in the policy window
+func IntegrationStatuses(db *gorm.DB, now time.Time) ([]IntegrationStatus, error) {
+	policy := CurrentSettings().IntegrationHealth
+	var rows []struct {
+		Integration   string
+		Successes     int
+		Failures      int
+		LastErrorAt   *time.Time
+		LastSuccessAt *time.Time
+	}
+	if err := db.Model(&IntegrationCall{}).
+		Select("integration, SUM(successes) AS successes, SUM(failures) AS failures, MAX(last_error_at) AS last_error_at, MAX(last_success_at) AS last_success_at").
+		Where("minute >= ?", now.Add(-policy.Window)).
+		Group("integration").
+		Order("integration").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load integration calls: %w", err)
+	}
+
+	statuses := make([]IntegrationStatus, 0, len(rows))
+	for _, row := range rows {
+		status := IntegrationStatus{
+			Integration:   row.Integration,
+			Status:        IntegrationStatusOK,
+			Calls:         row.Successes + row.Failures,
+			Failures:      row.Failures,
+			LastError:     "-",
+			LastErrorAt:   "-",
+			LastSuccessAt: "-",
+		}
+		if status.Calls == 0 {
+			status.Status = IntegrationStatusIdle
+		} else {
+			status.ErrorRate = float64(row.Failures) / float64(status.Calls)
+			if status.Calls >= policy.MinCalls && status.ErrorRate >= policy.ErrorRate {
+				status.Status = IntegrationStatusDegraded
+			}
+		}
+		if row.LastSuccessAt != nil {
+			status.LastSuccessAt = row.LastSuccessAt.Format("2006-01-02 15:04:05")
+		}
+		if row.LastErrorAt != nil {
+			status.LastErrorAt = row.LastErrorAt.Format("2006-01-02 15:04:05")
+
+			var last IntegrationCall
+			if err := db.Where("integration = ? AND last_error_at IS NOT NULL", row.Integration).
+				Order("minute DESC").First(&last).Error; err == nil {
+				status.LastError = last.LastError
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
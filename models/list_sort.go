@@ -0,0 +1,87 @@
+package models
+
+import "livo-backend/utilities"
+
+// Sort keys of the list endpoints, applied with utilities.ApplySort. Order lists have their own in
+// order_sort.go.
+var (
+	QcRibbonSortKeys = utilities.SortKeys{
+		"created_at": {"qc_ribbons.created_at"},
+		"tracking":   {"qc_ribbons.tracking"},
+	}
+	QcOnlineSortKeys = utilities.SortKeys{
+		"created_at": {"qc_onlines.created_at"},
+		"tracking":   {"qc_onlines.tracking"},
+	}
+	OutboundSortKeys = utilities.SortKeys{
+		"created_at": {"outbounds.created_at"},
+		"tracking":   {"outbounds.tracking"},
+		"expedition": {"outbounds.expedition"},
+		"pickup_at":  {"outbounds.pickup_at"},
+	}
+	ReturnSortKeys = utilities.SortKeys{
+		"created_at":     {"returns.created_at"},
+		"updated_at":     {"returns.updated_at"},
+		"tracking":       {"returns.new_tracking"},
+		"old_tracking":   {"returns.old_tracking"},
+		"order_ginee_id": {"returns.order_ginee_id"},
+	}
+	ComplainSortKeys = utilities.SortKeys{
+		"created_at": {"complains.created_at"},
+		"tracking":   {"complains.tracking"},
+		"code":       {"complains.code"},
+		"status":     {"complains.status"},
+		"due_at":     {"complains.due_at"},
+		"total_fee":  {"complains.total_fee"},
+	}
+	LostFoundSortKeys = utilities.SortKeys{
+		"created_at":  {"lost_founds.created_at"},
+		"product_sku": {"lost_founds.product_sku"},
+		"quantity":    {"lost_founds.quantity"},
+		"status":      {"lost_founds.status"},
+	}
+	ProductSortKeys = utilities.SortKeys{
+		"created_at": {"products.created_at"},
+		"sku":        {"products.sku"},
+		"name":       {"products.name"},
+		"location":   {"products.location"},
+		"category":   {"products.category"},
+	}
+	BoxSortKeys = utilities.SortKeys{
+		"created_at": {"boxes.created_at"},
+		"code":       {"boxes.code"},
+		"name":       {"boxes.name"},
+		"stock":      {"boxes.stock"},
+	}
+	ChannelSortKeys = utilities.SortKeys{
+		"created_at": {"channels.created_at"},
+		"code":       {"channels.code"},
+		"name":       {"channels.name"},
+	}
+	StoreSortKeys = utilities.SortKeys{
+		"created_at": {"stores.created_at"},
+		"code":       {"stores.code"},
+		"name":       {"stores.name"},
+	}
+	ExpeditionSortKeys = utilities.SortKeys{
+		"created_at": {"expeditions.created_at"},
+		"code":       {"expeditions.code"},
+		"name":       {"expeditions.name"},
+	}
+	UserSortKeys = utilities.SortKeys{
+		"created_at": {"users.created_at"},
+		"username":   {"users.username"},
+		"full_name":  {"users.full_name"},
+		"status":     {"users.is_active"},
+	}
+	InboundSortKeys = utilities.SortKeys{
+		"created_at":  {"inbounds.created_at"},
+		"expected_at": {"inbounds.expected_at"},
+		"reference":   {"inbounds.reference"},
+		"supplier":    {"inbounds.supplier"},
+		"status":      {"inbounds.status"},
+	}
+	PickedOrderListSortKeys = utilities.SortKeys{
+		"created_at": {"picked_orders.created_at"},
+	}
+)
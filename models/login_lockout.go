@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginLockoutPolicy is how many wrong passwords in a row lock an account and for how long. Zero
+// max failures disables the lockout.
+type LoginLockoutPolicy struct {
+	MaxFailures int
+	Duration    time.Duration
+}
+
+// LoginLocked reports whether logins of the user are refused at now. The lock lifts by itself once
+// its duration passed.
+func (u *User) LoginLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
+// RecordLoginFailure counts a wrong password for the user and locks the account once the policy's
+// maximum is reached. Failures before an expired lock no longer count, a running lock is left as it
+// is. The count is kept in the database in one statement, so concurrent wrong passwords all count.
+func RecordLoginFailure(db *gorm.DB, user *User, now time.Time) error {
+	// Failures after this one, starting over after an expired lock
+	policy := CurrentSettings().LoginLockout
+	next := "CASE WHEN locked_until IS NOT NULL THEN 1 ELSE login_failures + 1 END"
+	failures := gorm.Expr("CASE WHEN locked_until > ? THEN login_failures ELSE "+next+" END", now)
+	lockedUntil := gorm.Expr("CASE WHEN locked_until > ? THEN locked_until ELSE NULL END", now)
+	if policy.MaxFailures > 0 {
+		failures = gorm.Expr("CASE WHEN locked_until > ? THEN login_failures WHEN "+next+" >= ? THEN 0 ELSE "+next+" END",
+			now, policy.MaxFailures)
+		lockedUntil = gorm.Expr("CASE WHEN locked_until > ? THEN locked_until WHEN "+next+" >= ? THEN ? ELSE NULL END",
+			now, policy.MaxFailures, now.Add(policy.Duration))
+	}
+
+	var counted User
+	if err := db.Model(&counted).Clauses(clause.Returning{Columns: []clause.Column{{Name: "login_failures"}, {Name: "locked_until"}}}).
+		Where("id = ?", user.ID).
+		UpdateColumns(map[string]interface{}{"login_failures": failures, "locked_until": lockedUntil}).Error; err != nil {
+		return err
+	}
+	user.LoginFailures = counted.LoginFailures
+	user.LockedUntil = counted.LockedUntil
+	return nil
+}
+
+// ResetLoginFailures clears the wrong passwords counted for the user after a successful login
+func ResetLoginFailures(db *gorm.DB, user *User) error {
+	if user.LoginFailures == 0 && user.LockedUntil == nil {
+		return nil
+	}
+	user.LoginFailures = 0
+	user.LockedUntil = nil
+	return UnlockUser(db, user.ID)
+}
+
+// UnlockUser lifts the login lock of a user and clears their wrong password count
+func UnlockUser(db *gorm.DB, userID uint) error {
+	return db.Model(&User{}).Where("id = ?", userID).
+		UpdateColumns(map[string]interface{}{"login_failures": 0, "locked_until": nil}).Error
+}
@@ -6,19 +6,49 @@ import (
 	"gorm.io/gorm"
 )
 
+// Lost and found statuses. An item is open until it turns up again or is written off.
+const (
+	LostFoundOpen       = "open"
+	LostFoundFound      = "found"
+	LostFoundWrittenOff = "written_off"
+)
+
 type LostFound struct {
-	ID         uint           `gorm:"primaryKey" json:"id"`
-	ProductSKU string         `json:"product_sku" example:"SKU12345"`
-	Quantity   int            `gorm:"not null" json:"quantity" example:"10"`
-	Reason     string         `gorm:"not null" json:"reason" example:"Damaged during transit"`
-	CreatedBy  *uint          `gorm:"not null" json:"created_by" example:"john.doe"`
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	ProductSKU string `json:"product_sku" example:"SKU12345"`
+	Quantity   int    `gorm:"not null" json:"quantity" example:"10"`
+	Reason     string `gorm:"not null" json:"reason" example:"Damaged during transit"`
+	CreatedBy  *uint  `gorm:"not null" json:"created_by" example:"john.doe"`
+	// Resolution: the status, the complain or return the item is accounted for by, and who resolved it
+	Status         string         `gorm:"default:'open';not null;index" json:"status" example:"open"`
+	ComplainID     *uint          `gorm:"default:null;index" json:"complain_id"`
+	ReturnID       *uint          `gorm:"default:null;index" json:"return_id"`
+	ResolutionNote string         `json:"resolution_note" example:"Found behind rack B-12"`
+	ResolvedBy     *uint          `gorm:"default:null" json:"resolved_by"`
+	ResolvedAt     *time.Time     `gorm:"default:null" json:"resolved_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationship
-	CreateOperator *User    `gorm:"foreignKey:CreatedBy" json:"create_operator,omitempty"`
-	Product        *Product `gorm:"foreignKey:ProductSKU;references:Sku" json:"product,omitempty"`
+	CreateOperator *User     `gorm:"foreignKey:CreatedBy" json:"create_operator,omitempty"`
+	Resolver       *User     `gorm:"foreignKey:ResolvedBy" json:"resolver,omitempty"`
+	Product        *Product  `gorm:"foreignKey:ProductSKU;references:Sku" json:"product,omitempty"`
+	Complain       *Complain `gorm:"foreignKey:ComplainID" json:"complain,omitempty"`
+	Return         *Return   `gorm:"foreignKey:ReturnID" json:"return,omitempty"`
+}
+
+// StockAdjustment is the quantity the item moves the product's stock by once it is resolved: found
+// items come back into stock and written off items leave it, open items do not move it yet
+func (lf *LostFound) StockAdjustment() int {
+	switch lf.Status {
+	case LostFoundFound:
+		return lf.Quantity
+	case LostFoundWrittenOff:
+		return -lf.Quantity
+	default:
+		return 0
+	}
 }
 
 // LostFoundResponse represents lost and found data for API responses
@@ -31,8 +61,19 @@ type LostFoundResponse struct {
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 
+	Status          string     `json:"status"`
+	ComplainID      *uint      `json:"complain_id"`
+	ComplainCode    string     `json:"complain_code"`
+	ReturnID        *uint      `json:"return_id"`
+	ReturnTracking  string     `json:"return_tracking"`
+	ResolutionNote  string     `json:"resolution_note"`
+	ResolvedBy      *uint      `json:"resolved_by"`
+	ResolvedAt      *time.Time `json:"resolved_at"`
+	StockAdjustment int        `json:"stock_adjustment" example:"10"` // Quantity the resolution adds to (or takes off) the product's stock
+
 	// Related data
 	CreateOperator *UserResponse    `json:"create_operator,omitempty"`
+	Resolver       *UserResponse    `json:"resolver,omitempty"`
 	Product        *ProductResponse `json:"product,omitempty"`
 }
 
@@ -46,6 +87,22 @@ func (lf *LostFound) ToLostFoundResponse() LostFoundResponse {
 		CreatedBy:  lf.CreatedBy,
 		CreatedAt:  lf.CreatedAt,
 		UpdatedAt:  lf.UpdatedAt,
+
+		Status:          lf.Status,
+		ComplainID:      lf.ComplainID,
+		ComplainCode:    "-",
+		ReturnID:        lf.ReturnID,
+		ReturnTracking:  "-",
+		ResolutionNote:  lf.ResolutionNote,
+		ResolvedBy:      lf.ResolvedBy,
+		ResolvedAt:      lf.ResolvedAt,
+		StockAdjustment: lf.StockAdjustment(),
+	}
+	if lf.Complain != nil {
+		response.ComplainCode = lf.Complain.Code
+	}
+	if lf.Return != nil {
+		response.ReturnTracking = lf.Return.NewTracking
 	}
 
 	// Include product details if loaded
@@ -60,5 +117,11 @@ func (lf *LostFound) ToLostFoundResponse() LostFoundResponse {
 		response.CreateOperator = &userResponse
 	}
 
+	// Include resolver details if loaded
+	if lf.Resolver != nil {
+		userResponse := lf.Resolver.ToUserResponse()
+		response.Resolver = &userResponse
+	}
+
 	return response
 }
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MasterReference counts the recent transactions of one kind that reference a master record
+type MasterReference struct {
+	Source string `json:"source" example:"orders"`
+	Count  int64  `json:"count" example:"12"`
+}
+
+// masterReferenceQuery counts the records of source created since the time it is given
+type masterReferenceQuery struct {
+	source string
+	count  func(db *gorm.DB, since time.Time) *gorm.DB
+}
+
+// RecentReferences counts the QC details that used the box within MasterDeleteGuardDays
+func (b *Box) RecentReferences(db *gorm.DB) ([]MasterReference, error) {
+	return countMasterReferences(db, []masterReferenceQuery{
+		{"QC ribbon details", func(db *gorm.DB, since time.Time) *gorm.DB {
+			return db.Model(&QcRibbonDetail{}).Where("box_id = ? AND created_at >= ?", b.ID, since)
+		}},
+		{"QC online details", func(db *gorm.DB, since time.Time) *gorm.DB {
+			return db.Model(&QcOnlineDetail{}).Where("box_id = ? AND created_at >= ?", b.ID, since)
+		}},
+	})
+}
+
+// RecentReferences counts the orders and complains of the channel created within
+// MasterDeleteGuardDays. Orders name their channel.
+func (c *Channel) RecentReferences(db *gorm.DB) ([]MasterReference, error) {
+	return countMasterReferences(db, []masterReferenceQuery{
+		{"orders", func(db *gorm.DB, since time.Time) *gorm.DB {
+			return db.Model(&Order{}).Where("channel = ? AND created_at >= ?", c.Name, since)
+		}},
+		{"complains", func(db *gorm.DB, since time.Time) *gorm.DB {
+			return db.Model(&Complain{}).Where("channel_id = ? AND created_at >= ?", c.ID, since)
+		}},
+	})
+}
+
+// RecentReferences counts the orders and complains of the store created within
+// MasterDeleteGuardDays. Orders name their store.
+func (s *Store) RecentReferences(db *gorm.DB) ([]MasterReference, error) {
+	return countMasterReferences(db, []masterReferenceQuery{
+		{"orders", func(db *gorm.DB, since time.Time) *gorm.DB {
+			return db.Model(&Order{}).Where("store = ? AND created_at >= ?", s.Name, since)
+		}},
+		{"complains", func(db *gorm.DB, since time.Time) *gorm.DB {
+			return db.Model(&Complain{}).Where("store_id = ? AND created_at >= ?", s.ID, since)
+		}},
+	})
+}
+
+// RecentReferences counts the orders shipping with the expedition and its outbound scans within
+// MasterDeleteGuardDays. Orders name their courier by code, name or slug, outbounds by name.
+func (e *Expedition) RecentReferences(db *gorm.DB) ([]MasterReference, error) {
+	return countMasterReferences(db, []masterReferenceQuery{
+		{"orders", func(db *gorm.DB, since time.Time) *gorm.DB {
+			return db.Model(&Order{}).
+				Where("LOWER(courier) IN (LOWER(?), LOWER(?), LOWER(?))", e.Code, e.Name, e.Slug).
+				Where("created_at >= ?", since)
+		}},
+		{"outbounds", func(db *gorm.DB, since time.Time) *gorm.DB {
+			return db.Model(&Outbound{}).Where("expedition = ? AND created_at >= ?", e.Name, since)
+		}},
+	})
+}
+
+func countMasterReferences(db *gorm.DB, queries []masterReferenceQuery) ([]MasterReference, error) {
+	days := CurrentSettings().MasterDeleteGuardDays
+	if days <= 0 {
+		return nil, nil
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	var references []MasterReference
+	for _, query := range queries {
+		var count int64
+		if err := query.count(db, since).Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", query.source, err)
+		}
+		if count > 0 {
+			references = append(references, MasterReference{Source: query.source, Count: count})
+		}
+	}
+	return references, nil
+}
+
+// DescribeMasterReferences explains why a master record cannot be deleted, e.g.
+// "referenced by 12 orders and 1 complains in the last 90 days"
+func DescribeMasterReferences(references []MasterReference) string {
+	parts := make([]string, len(references))
+	for i, reference := range references {
+		parts[i] = fmt.Sprintf("%d %s", reference.Count, reference.Source)
+	}
+	return fmt.Sprintf("referenced by %s in the last %d days", strings.Join(parts, " and "), CurrentSettings().MasterDeleteGuardDays)
+}
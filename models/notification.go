@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Notification struct {
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	UserID        uint           `gorm:"not null;index" json:"user_id"`
+	Type          string         `gorm:"not null;index" json:"type" example:"complain_escalated"`
+	Title         string         `gorm:"not null" json:"title" example:"Complain escalated"`
+	Message       string         `json:"message" example:"Complain CMP123456 escalated to coordinator"`
+	ReferenceType string         `json:"reference_type" example:"complain"`
+	ReferenceID   uint           `json:"reference_id" example:"1"`
+	ReadAt        *time.Time     `gorm:"default:null" json:"read_at"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationship
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+type NotificationResponse struct {
+	ID            uint      `json:"id"`
+	UserID        uint      `json:"user_id"`
+	Type          string    `json:"type"`
+	Title         string    `json:"title"`
+	Message       string    `json:"message"`
+	ReferenceType string    `json:"reference_type"`
+	ReferenceID   uint      `json:"reference_id"`
+	Read          bool      `json:"read"`
+	ReadAt        string    `json:"read_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ToNotificationResponse converts Notification model to NotificationResponse
+func (n *Notification) ToNotificationResponse() NotificationResponse {
+	readAt := "-"
+	if n.ReadAt != nil {
+		readAt = n.ReadAt.Format("2006-01-02 15:04:05")
+	}
+
+	return NotificationResponse{
+		ID:            n.ID,
+		UserID:        n.UserID,
+		Type:          n.Type,
+		Title:         n.Title,
+		Message:       n.Message,
+		ReferenceType: n.ReferenceType,
+		ReferenceID:   n.ReferenceID,
+		Read:          n.ReadAt != nil,
+		ReadAt:        readAt,
+		CreatedAt:     n.CreatedAt,
+	}
+}
+
+// NotifyUsersWithRoles creates one notification for every active user holding any of the given roles
+func NotifyUsersWithRoles(db *gorm.DB, roles []string, notificationType, title, message, referenceType string, referenceID uint) error {
+	var userIDs []uint
+	if err := db.Table("user_roles").
+		Select("DISTINCT user_roles.user_id").
+		Joins("JOIN roles ON roles.id = user_roles.role_id").
+		Joins("JOIN users ON users.id = user_roles.user_id").
+		Where("roles.name IN ? AND users.is_active = ? AND users.deleted_at IS NULL AND user_roles.deleted_at IS NULL", roles, true).
+		Pluck("user_roles.user_id", &userIDs).Error; err != nil {
+		return err
+	}
+
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	notifications := make([]Notification, len(userIDs))
+	for i, userID := range userIDs {
+		notifications[i] = Notification{
+			UserID:        userID,
+			Type:          notificationType,
+			Title:         title,
+			Message:       message,
+			ReferenceType: referenceType,
+			ReferenceID:   referenceID,
+		}
+	}
+
+	return db.Create(&notifications).Error
+}
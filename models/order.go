@@ -1,6 +1,7 @@
 package models
 
 import (
+	"livo-backend/utilities"
 	"time"
 
 	"gorm.io/gorm"
@@ -14,7 +15,12 @@ type Order struct {
 	Channel          string         `json:"channel" example:"Shopee"`
 	Store            string         `json:"store" example:"SP deParcelRibbon"`
 	Buyer            string         `json:"buyer" example:"John Doe"`
+	BuyerPhone       string         `json:"-"` // Only exposed masked, see ContactReveal
 	Address          string         `json:"address" example:"123 Main St, Cityville, Country"`
+	Province         string         `gorm:"index" json:"province" example:"Jawa Barat"` // Normalized destination, see CheckAddress
+	City             string         `json:"city" example:"Bandung"`
+	PostalCode       string         `json:"postal_code" example:"40115"`
+	AddressStatus    string         `gorm:"index" json:"address_status" example:"validated"` // Empty until the address is validated
 	Courier          string         `json:"courier" example:"JNE"`
 	Tracking         string         `gorm:"unique;not null" json:"tracking" example:"JNE1234567890"`
 	SentBefore       time.Time      `json:"sent_before"`
@@ -28,7 +34,23 @@ type Order struct {
 	ChangedAt        *time.Time     `gorm:"default:null" json:"changed_at"`
 	CancelledBy      *uint          `gorm:"default:null" json:"cancelled_by"`
 	CancelledAt      *time.Time     `gorm:"default:null" json:"cancelled_at"`
+	DeletedBy        *uint          `gorm:"default:null" json:"deleted_by"` // Superadmin who soft deleted the order
 	Complained       bool           `gorm:"default:false" json:"complained" example:"false"`
+	DeliveryStatus   string         `gorm:"index" json:"delivery_status" example:"in_transit"`
+	DeliveryStatusAt *time.Time     `gorm:"default:null" json:"delivery_status_at"`                           // Last time the delivery status was pulled
+	Instant          bool           `gorm:"index;default:false" json:"instant" example:"false"`               // Ships with an instant courier, see ApplyCourierLane
+	SLADueAt         *time.Time     `gorm:"default:null" json:"sla_due_at"`                                   // Must be sent out by then: instant SLA or ship-by deadline
+	SLABreachedAt    *time.Time     `gorm:"default:null" json:"sla_breached_at"`                              // Set when coordinators were alerted about a missed deadline
+	Priority         string         `gorm:"index;not null;default:'normal'" json:"priority" example:"normal"` // normal, urgent or late, see ApplyPriority
+	AtRiskAt         *time.Time     `gorm:"default:null;index" json:"at_risk_at"`                             // Set while the order is stuck in a status, see FlagStuckOrders
+	AtRiskStatus     string         `json:"at_risk_status" example:"picking process"`                         // Processing status the order got stuck in
+	ParcelCount      int            `gorm:"not null;default:1" json:"parcel_count" example:"1"`               // Parcels the order ships in, QC is complete once each is QC'd
+	MergedIntoID     *uint          `gorm:"default:null;index" json:"merged_into_id"`                         // Order this one was merged into, it is never picked
+	MergedAt         *time.Time     `gorm:"default:null" json:"merged_at"`                                    // Set with MergedIntoID
+	HeldAt           *time.Time     `gorm:"default:null;index" json:"held_at"`                                // Set while the order is on hold, see IsHeld
+	HeldBy           *uint          `gorm:"default:null" json:"held_by"`
+	HoldReason       string         `json:"hold_reason" example:"Buyer is changing the address"`
+	Version          uint           `gorm:"not null;default:1" json:"version" example:"1"` // Bumped on every update, clients send it back to detect stale edits
 	CreatedAt        time.Time      `json:"created_at"`
 	UpdatedAt        time.Time      `json:"updated_at"`
 	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
@@ -42,17 +64,35 @@ type Order struct {
 	AssignOperator  *User         `gorm:"foreignKey:AssignedBy" json:"assigner,omitempty"`
 }
 
+// BeforeUpdate bumps the version of a loaded order on every update, so a client holding an
+// older copy gets a conflict instead of overwriting the change. Bulk updates through an empty
+// model (Model(&Order{}).Where(...)) are left alone.
+func (o *Order) BeforeUpdate(tx *gorm.DB) error {
+	if o.ID == 0 {
+		return nil
+	}
+	tx.Statement.SetColumn("Version", o.Version+1)
+	return nil
+}
+
 type OrderDetail struct {
-	ID          uint      `json:"id" gorm:"primaryKey"`
-	OrderID     uint      `json:"order_id"`
-	Sku         string    `json:"sku" gorm:"index"`
-	ProductName string    `json:"product_name"`
-	Variant     string    `json:"variant"`
-	Quantity    int       `json:"quantity"`
-	Price       int       `json:"price"`
-	Product     *Product  `json:"product,omitempty" gorm:"-"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	OrderID         uint      `json:"order_id"`
+	Sku             string    `json:"sku" gorm:"index"`
+	ProductName     string    `json:"product_name"`
+	Variant         string    `json:"variant"`
+	Quantity        int       `json:"quantity"`
+	Price           int       `json:"price"`
+	ScannedQuantity int       `json:"scanned_quantity" gorm:"default:0"`
+	Product         *Product  `json:"product,omitempty" gorm:"-"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// ResetOrderScans clears what was scanned of the lines of an order, for an order picked again from
+// the start
+func ResetOrderScans(db *gorm.DB, orderID uint) error {
+	return db.Model(&OrderDetail{}).Where("order_id = ?", orderID).Update("scanned_quantity", 0).Error
 }
 
 // OrderResponse represents order data for API responses
@@ -64,7 +104,12 @@ type OrderResponse struct {
 	Channel          string    `json:"channel"`
 	Store            string    `json:"store"`
 	Buyer            string    `json:"buyer"`
+	BuyerPhone       string    `json:"buyer_phone"` // Masked, use the contact reveal endpoint for the full number
 	Address          string    `json:"address"`
+	Province         string    `json:"province"`
+	City             string    `json:"city"`
+	PostalCode       string    `json:"postal_code"`
+	AddressStatus    string    `json:"address_status"` // validated, unresolved or unchecked
 	Courier          string    `json:"courier"`
 	Tracking         string    `json:"tracking"`
 	SentBefore       string    `json:"sent_before"`
@@ -79,20 +124,38 @@ type OrderResponse struct {
 	ChangedAt        string    `json:"changed_at"`
 	CancelledBy      string    `json:"cancelled_by"`
 	CancelledAt      string    `json:"cancelled_at"`
+	DeletedAt        string    `json:"deleted_at"` // Only set on deleted orders listed with include_deleted
+	DeliveryStatus   string    `json:"delivery_status"`
+	DeliveryStatusAt string    `json:"delivery_status_at"`
+	Instant          bool      `json:"instant"`
+	SLADueAt         string    `json:"sla_due_at"`
+	SLABreached      bool      `json:"sla_breached"`
+	Priority         string    `json:"priority"`
+	AtRisk           bool      `json:"at_risk"`
+	AtRiskAt         string    `json:"at_risk_at"`
+	ParcelCount      int       `json:"parcel_count"`
+	MergedIntoID     *uint     `json:"merged_into_id"` // Set on orders merged into another one, which are never picked
+	MergedAt         string    `json:"merged_at"`
+	Held             bool      `json:"held"` // On hold: not assigned, QC'd or sent out until released
+	HeldAt           string    `json:"held_at"`
+	HoldReason       string    `json:"hold_reason"`
+	Version          uint      `json:"version"`
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
 
 	// Related data
 	OrderDetails []OrderDetailResponse `json:"order_details"`
+	Changes      []OrderChangeResponse `json:"changes,omitempty"` // Only returned by order updates
 }
 
 type OrderDetailResponse struct {
-	ID          uint   `json:"id"`
-	Sku         string `json:"sku"`
-	ProductName string `json:"product_name"`
-	Variant     string `json:"variant"`
-	Quantity    int    `json:"quantity"`
-	Price       int    `json:"price"`
+	ID              uint   `json:"id"`
+	Sku             string `json:"sku"`
+	ProductName     string `json:"product_name"`
+	Variant         string `json:"variant"`
+	Quantity        int    `json:"quantity"`
+	Price           int    `json:"price"`
+	ScannedQuantity int    `json:"scanned_quantity"`
 
 	// Related data
 	Product *ProductResponse `json:"product,omitempty"`
@@ -103,21 +166,23 @@ func (o *Order) ToOrderResponse() OrderResponse {
 	details := make([]OrderDetailResponse, len(o.OrderDetails))
 	for i, detail := range o.OrderDetails {
 		detailResp := OrderDetailResponse{
-			ID:          detail.ID,
-			Sku:         detail.Sku,
-			ProductName: detail.ProductName,
-			Variant:     detail.Variant,
-			Quantity:    detail.Quantity,
-			Price:       detail.Price,
+			ID:              detail.ID,
+			Sku:             detail.Sku,
+			ProductName:     detail.ProductName,
+			Variant:         detail.Variant,
+			Quantity:        detail.Quantity,
+			Price:           detail.Price,
+			ScannedQuantity: detail.ScannedQuantity,
 		}
 
 		// Include product data if exists
 		if detail.Product != nil {
 			detailResp.Product = &ProductResponse{
-				ID:    detail.Product.ID,
-				Sku:   detail.Product.Sku,
-				Name:  detail.Product.Name,
-				Image: detail.Product.Image,
+				ID:        detail.Product.ID,
+				Sku:       detail.Product.Sku,
+				Name:      detail.Product.Name,
+				Image:     detail.Product.Image,
+				Thumbnail: detail.Product.Thumbnail,
 			}
 		}
 
@@ -195,6 +260,45 @@ func (o *Order) ToOrderResponse() OrderResponse {
 		assignedAt = "-"
 	}
 
+	var slaDueAt string
+	if o.SLADueAt != nil {
+		slaDueAt = o.SLADueAt.Format("2006-01-02 15:04:05")
+	} else {
+		slaDueAt = "-"
+	}
+
+	deletedAt := "-"
+	if o.DeletedAt.Valid {
+		deletedAt = o.DeletedAt.Time.Format("2006-01-02 15:04:05")
+	}
+
+	var deliveryStatusAt string
+	if o.DeliveryStatusAt != nil {
+		deliveryStatusAt = o.DeliveryStatusAt.Format("2006-01-02 15:04:05")
+	} else {
+		deliveryStatusAt = "-"
+	}
+
+	addressStatus := o.AddressStatus
+	if addressStatus == AddressUnchecked {
+		addressStatus = "unchecked"
+	}
+
+	atRiskAt := "-"
+	if o.AtRiskAt != nil {
+		atRiskAt = o.AtRiskAt.Format("2006-01-02 15:04:05")
+	}
+
+	heldAt := "-"
+	if o.HeldAt != nil {
+		heldAt = o.HeldAt.Format("2006-01-02 15:04:05")
+	}
+
+	mergedAt := "-"
+	if o.MergedAt != nil {
+		mergedAt = o.MergedAt.Format("2006-01-02 15:04:05")
+	}
+
 	return OrderResponse{
 		ID:               o.ID,
 		OrderGineeID:     o.OrderGineeID,
@@ -203,7 +307,12 @@ func (o *Order) ToOrderResponse() OrderResponse {
 		Channel:          o.Channel,
 		Store:            o.Store,
 		Buyer:            o.Buyer,
+		BuyerPhone:       utilities.MaskPhone(o.BuyerPhone),
 		Address:          o.Address,
+		Province:         o.Province,
+		City:             o.City,
+		PostalCode:       o.PostalCode,
+		AddressStatus:    addressStatus,
 		Courier:          o.Courier,
 		Tracking:         o.Tracking,
 		SentBefore:       o.SentBefore.Format("2006-01-02 15:04:05"),
@@ -220,6 +329,38 @@ func (o *Order) ToOrderResponse() OrderResponse {
 		PendingAt:        pendingAt,
 		CancelledBy:      cancelledBy,
 		CancelledAt:      cancelledAt,
+		DeletedAt:        deletedAt,
+		DeliveryStatus:   o.DeliveryStatus,
+		DeliveryStatusAt: deliveryStatusAt,
+		Instant:          o.Instant,
+		SLADueAt:         slaDueAt,
+		SLABreached:      o.SLABreachedAt != nil,
+		Priority:         o.Priority,
+		AtRisk:           o.AtRiskAt != nil,
+		AtRiskAt:         atRiskAt,
+		ParcelCount:      o.ParcelCount,
+		MergedIntoID:     o.MergedIntoID,
+		MergedAt:         mergedAt,
+		Held:             o.IsHeld(),
+		HeldAt:           heldAt,
+		HoldReason:       o.HoldReason,
+		Version:          o.Version,
 		OrderDetails:     details,
 	}
 }
+
+// OrderDetailScan records one barcode scan made by a picker against an order line
+type OrderDetailScan struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	OrderID       uint      `gorm:"not null;index" json:"order_id"`
+	OrderDetailID uint      `gorm:"not null;index" json:"order_detail_id"`
+	Barcode       string    `gorm:"not null" json:"barcode" example:"8999999000012"`
+	Quantity      int       `gorm:"not null" json:"quantity" example:"1"`
+	ScannedBy     uint      `gorm:"not null" json:"scanned_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// Relationship
+	OrderDetail *OrderDetail `gorm:"foreignKey:OrderDetailID" json:"-"`
+	Scanner     *User        `gorm:"foreignKey:ScannedBy" json:"scanner,omitempty"`
+}
@@ -0,0 +1,184 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Address statuses of orders. Orders created or edited while no geocoder is configured, or while it
+// fails, stay unchecked.
+const (
+	AddressUnchecked  = ""
+	AddressValidated  = "validated"
+	AddressUnresolved = "unresolved" // The geocoder could not place the address
+)
+
+// ErrAddressNotFound is returned by geocoders for an address they cannot place
+var ErrAddressNotFound = errors.New("address not found")
+
+// ErrAddressUnresolved is returned by CheckAddress for an address the geocoder cannot place while
+// AddressValidationRequired is set
+var ErrAddressUnresolved = errors.New("address could not be resolved")
+
+// GeocodedAddress is the normalized destination of an address
+type GeocodedAddress struct {
+	Province   string
+	City       string
+	PostalCode string
+}
+
+// AddressGeocoder resolves a free-form shipping address to its province, city and postal code,
+// returning ErrAddressNotFound for addresses it cannot place
+type AddressGeocoder interface {
+	Geocode(ctx context.Context, address string) (*GeocodedAddress, error)
+}
+
+// AddressCheck is the outcome of validating an address
+type AddressCheck struct {
+	Status  string
+	Address GeocodedAddress
+}
+
+// CheckAddress validates address with the Geocoder, recording the call in the integration health.
+// It only fails with ErrAddressUnresolved, for an address the geocoder cannot place while
+// AddressValidationRequired is set.
+func CheckAddress(db *gorm.DB, address string) (AddressCheck, error) {
+	settings := CurrentSettings()
+	if settings.Geocoder == nil || strings.TrimSpace(address) == "" {
+		return AddressCheck{Status: AddressUnchecked}, nil
+	}
+
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	geocoded, err := settings.Geocoder.Geocode(ctx, address)
+	if errors.Is(err, ErrAddressNotFound) {
+		recordGeocodingCall(db, nil)
+		if settings.AddressValidationRequired {
+			return AddressCheck{}, fmt.Errorf("%w: %q", ErrAddressUnresolved, address)
+		}
+		return AddressCheck{Status: AddressUnresolved}, nil
+	}
+	recordGeocodingCall(db, err)
+	if err != nil {
+		log.Printf("⚠️ Failed to geocode address, leaving it unchecked: %v", err)
+		return AddressCheck{Status: AddressUnchecked}, nil
+	}
+
+	return AddressCheck{Status: AddressValidated, Address: *geocoded}, nil
+}
+
+// ApplyTo stores the outcome on the order, clearing the destination unless the address was validated
+func (a AddressCheck) ApplyTo(order *Order) {
+	order.Province = a.Address.Province
+	order.City = a.Address.City
+	order.PostalCode = a.Address.PostalCode
+	order.AddressStatus = a.Status
+}
+
+func recordGeocodingCall(db *gorm.DB, err error) {
+	if recordErr := RecordIntegrationCall(db, IntegrationGeocoding, err); recordErr != nil {
+		log.Printf("⚠️ Failed to record geocoding call: %v", recordErr)
+	}
+}
+
+// DestinationStat counts the orders shipped to one province or city
+type DestinationStat struct {
+	Province string  `json:"province" example:"Jawa Barat"`
+	City     string  `json:"city" example:"Bandung"`
+	Orders   int64   `json:"orders" example:"120"`
+	Percent  float64 `json:"percent" example:"12.5"` // Share of the orders with a known destination
+}
+
+// DestinationStats aggregates the shipping destinations of orders
+type DestinationStats struct {
+	Total      int64             `json:"total" example:"1000"`    // Orders in the range
+	Validated  int64             `json:"validated" example:"960"` // Orders with a known destination
+	Unresolved int64             `json:"unresolved" example:"25"` // Orders the geocoder could not place
+	Unchecked  int64             `json:"unchecked" example:"15"`  // Orders never validated
+	Provinces  []DestinationStat `json:"provinces"`               // Per province, City left empty, most orders first
+	Cities     []DestinationStat `json:"cities"`                  // Per city, most orders first
+}
+
+// DestinationFilter selects the orders of the destination stats
+type DestinationFilter struct {
+	Start   time.Time
+	End     time.Time
+	Channel string
+	Store   string
+}
+
+// BuildDestinationStats counts the orders created in the filter's range by address status and by
+// province and city. Cancelled and merged orders are left out.
+func BuildDestinationStats(db *gorm.DB, filter DestinationFilter) (DestinationStats, error) {
+	base := func() *gorm.DB {
+		query := db.Model(&Order{}).
+			Where("orders.created_at BETWEEN ? AND ?", filter.Start, filter.End).
+			Where("(orders.event_status IS NULL OR orders.event_status != ?)", "cancelled").
+			Where("orders.merged_into_id IS NULL")
+		if filter.Channel != "" {
+			query = query.Where("orders.channel = ?", filter.Channel)
+		}
+		if filter.Store != "" {
+			query = query.Where("orders.store = ?", filter.Store)
+		}
+		return query
+	}
+
+	var stats DestinationStats
+	var statuses []struct {
+		AddressStatus string
+		Orders        int64
+	}
+	if err := base().Select("address_status, COUNT(*) AS orders").Group("address_status").Scan(&statuses).Error; err != nil {
+		return stats, fmt.Errorf("failed to count orders by address status: %w", err)
+	}
+	for _, row := range statuses {
+		stats.Total += row.Orders
+		switch row.AddressStatus {
+		case AddressValidated:
+			stats.Validated += row.Orders
+		case AddressUnresolved:
+			stats.Unresolved += row.Orders
+		default:
+			stats.Unchecked += row.Orders
+		}
+	}
+
+	stats.Provinces = []DestinationStat{}
+	if err := base().Select("province, COUNT(*) AS orders").
+		Where("address_status = ?", AddressValidated).
+		Group("province").Order("orders DESC, province").
+		Scan(&stats.Provinces).Error; err != nil {
+		return stats, fmt.Errorf("failed to count orders by province: %w", err)
+	}
+
+	stats.Cities = []DestinationStat{}
+	if err := base().Select("province, city, COUNT(*) AS orders").
+		Where("address_status = ?", AddressValidated).
+		Group("province, city").Order("orders DESC, province, city").
+		Scan(&stats.Cities).Error; err != nil {
+		return stats, fmt.Errorf("failed to count orders by city: %w", err)
+	}
+
+	for _, rows := range [][]DestinationStat{stats.Provinces, stats.Cities} {
+		for i := range rows {
+			if stats.Validated > 0 {
+				rows[i].Percent = math.Round(float64(rows[i].Orders)*10000/float64(stats.Validated)) / 100
+			}
+		}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrOrderCancelled is returned when a cancelled order would be sent out
+var ErrOrderCancelled = errors.New("order has been cancelled")
+
+// OrderCancellation counts the downstream records a cancellation flagged
+type OrderCancellation struct {
+	PickedOrders int64 `json:"picked_orders"`
+	QcRibbons    int64 `json:"qc_ribbons"`
+	QcOnlines    int64 `json:"qc_onlines"`
+}
+
+// IsCancelled reports whether the order was cancelled
+func (o *Order) IsCancelled() bool {
+	return o.EventStatus != nil && *o.EventStatus == "cancelled"
+}
+
+// CascadeOrderCancellation flags the pick and QC records of a cancelled order with the cancellation
+// time, so they no longer count as work on a live order. Run it in the transaction cancelling the order.
+func CascadeOrderCancellation(tx *gorm.DB, order *Order, at time.Time) (OrderCancellation, error) {
+	var cascade OrderCancellation
+
+	picked := tx.Model(&PickedOrder{}).Where("order_id = ? AND cancelled_at IS NULL", order.ID).UpdateColumn("cancelled_at", at)
+	if picked.Error != nil {
+		return cascade, picked.Error
+	}
+	cascade.PickedOrders = picked.RowsAffected
+
+	if order.Tracking == "" {
+		return cascade, nil
+	}
+
+	ribbons := tx.Model(&QcRibbon{}).Where("tracking = ? AND cancelled_at IS NULL", order.Tracking).UpdateColumn("cancelled_at", at)
+	if ribbons.Error != nil {
+		return cascade, ribbons.Error
+	}
+	cascade.QcRibbons = ribbons.RowsAffected
+
+	onlines := tx.Model(&QcOnline{}).Where("tracking = ? AND cancelled_at IS NULL", order.Tracking).UpdateColumn("cancelled_at", at)
+	if onlines.Error != nil {
+		return cascade, onlines.Error
+	}
+	cascade.QcOnlines = onlines.RowsAffected
+
+	return cascade, nil
+}
+
+// LockOrderForOutbound locks the order of a tracking until the transaction ends and returns
+// ErrOrderCancelled when it was cancelled, or ErrOrderHeld when it is on hold. Cancelling and holding
+// lock the order too, so an outbound is never created for an order cancelled or held in the meantime.
+func LockOrderForOutbound(tx *gorm.DB, tracking string) error {
+	var order Order
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "event_status", "held_at").Where("tracking = ?", tracking).First(&order).Error
+	if err != nil {
+		return err
+	}
+	if order.IsCancelled() {
+		return ErrOrderCancelled
+	}
+	if order.IsHeld() {
+		return ErrOrderHeld
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"fmt"
+	"livo-backend/utilities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OrderChange records one field changed by an order edit. Changes to order details carry the
+// detail's SKU; added and removed details are recorded under the "order_detail" field.
+type OrderChange struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	OrderID   uint           `gorm:"not null;index" json:"order_id"`
+	Version   uint           `gorm:"not null" json:"version" example:"3"` // Order version produced by the edit
+	Field     string         `gorm:"not null" json:"field" example:"quantity"`
+	Sku       string         `json:"sku" example:"LY-GLIPOW-128-HL705-30G"`
+	OldValue  string         `json:"old_value" example:"1"`
+	NewValue  string         `json:"new_value" example:"2"`
+	ChangedBy uint           `gorm:"not null" json:"changed_by"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationship
+	Changer *User `gorm:"foreignKey:ChangedBy" json:"changer,omitempty"`
+}
+
+type OrderChangeResponse struct {
+	Field    string `json:"field"`
+	Sku      string `json:"sku,omitempty"`
+	OldValue string `json:"old_value"`
+	NewValue string `json:"new_value"`
+}
+
+// OrderEditResponse groups the changes of a single order edit
+type OrderEditResponse struct {
+	Version   uint                  `json:"version"`
+	ChangedBy string                `json:"changed_by"`
+	ChangedAt string                `json:"changed_at"`
+	Changes   []OrderChangeResponse `json:"changes"`
+}
+
+// ToOrderChangeResponse converts OrderChange model to OrderChangeResponse
+func (c *OrderChange) ToOrderChangeResponse() OrderChangeResponse {
+	return OrderChangeResponse{
+		Field:    c.Field,
+		Sku:      c.Sku,
+		OldValue: c.OldValue,
+		NewValue: c.NewValue,
+	}
+}
+
+// DiffOrder lists the fields and order details that differ between two copies of an order.
+// Both copies need their OrderDetails loaded. Buyer phone numbers are recorded masked.
+func DiffOrder(before, after *Order) []OrderChange {
+	var changes []OrderChange
+	field := func(name, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, OrderChange{OrderID: after.ID, Field: name, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+
+	field("channel", before.Channel, after.Channel)
+	field("store", before.Store, after.Store)
+	field("buyer", before.Buyer, after.Buyer)
+	field("buyer_phone", utilities.MaskPhone(before.BuyerPhone), utilities.MaskPhone(after.BuyerPhone))
+	field("address", before.Address, after.Address)
+	field("courier", before.Courier, after.Courier)
+	field("tracking", before.Tracking, after.Tracking)
+	field("sent_before", before.SentBefore.Format("2006-01-02 15:04:05"), after.SentBefore.Format("2006-01-02 15:04:05"))
+
+	afterDetails := make(map[uint]OrderDetail, len(after.OrderDetails))
+	for _, detail := range after.OrderDetails {
+		afterDetails[detail.ID] = detail
+	}
+
+	beforeIDs := make(map[uint]bool, len(before.OrderDetails))
+	for _, old := range before.OrderDetails {
+		beforeIDs[old.ID] = true
+
+		current, kept := afterDetails[old.ID]
+		if !kept {
+			changes = append(changes, OrderChange{OrderID: after.ID, Field: "order_detail", Sku: old.Sku, OldValue: describeOrderDetail(old)})
+			continue
+		}
+
+		detailField := func(name, oldValue, newValue string) {
+			if oldValue != newValue {
+				changes = append(changes, OrderChange{OrderID: after.ID, Field: name, Sku: current.Sku, OldValue: oldValue, NewValue: newValue})
+			}
+		}
+		detailField("sku", old.Sku, current.Sku)
+		detailField("product_name", old.ProductName, current.ProductName)
+		detailField("variant", old.Variant, current.Variant)
+		detailField("quantity", fmt.Sprint(old.Quantity), fmt.Sprint(current.Quantity))
+		detailField("price", fmt.Sprint(old.Price), fmt.Sprint(current.Price))
+	}
+
+	for _, detail := range after.OrderDetails {
+		if !beforeIDs[detail.ID] {
+			changes = append(changes, OrderChange{OrderID: after.ID, Field: "order_detail", Sku: detail.Sku, NewValue: describeOrderDetail(detail)})
+		}
+	}
+
+	return changes
+}
+
+// describeOrderDetail summarises an added or removed order detail, e.g. "Glitter Serbuk (Biru Tua) x2 @ 15000"
+func describeOrderDetail(detail OrderDetail) string {
+	name := detail.ProductName
+	if detail.Variant != "" {
+		name += " (" + detail.Variant + ")"
+	}
+	return fmt.Sprintf("%s x%d @ %d", name, detail.Quantity, detail.Price)
+}
+
+// GroupOrderEdits groups changes ordered by version into one entry per edit
+func GroupOrderEdits(changes []OrderChange) []OrderEditResponse {
+	edits := []OrderEditResponse{}
+	for i := range changes {
+		change := &changes[i]
+		if len(edits) == 0 || edits[len(edits)-1].Version != change.Version {
+			changedBy := "-"
+			if change.Changer != nil {
+				changedBy = change.Changer.FullName
+			}
+			edits = append(edits, OrderEditResponse{
+				Version:   change.Version,
+				ChangedBy: changedBy,
+				ChangedAt: change.CreatedAt.Format("2006-01-02 15:04:05"),
+			})
+		}
+
+		edit := &edits[len(edits)-1]
+		edit.Changes = append(edit.Changes, change.ToOrderChangeResponse())
+	}
+
+	return edits
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// DuplicatePolicy controls the identifiers given to copies of a duplicated order. A copy's Ginee ID
+// gets GineeIDSuffix appended and its tracking gets TrackingPrefix prepended. "{n}" in either is
+// replaced with the copy's ordinal, counting the first order of the chain as 1; without "{n}" the
+// affix is repeated once per duplicate instead ("X-", "X-X-", ...).
+type DuplicatePolicy struct {
+	GineeIDSuffix  string
+	TrackingPrefix string
+	MaxDuplicates  int  // Copies allowed per order chain, the first order not included
+	ModifyOriginal bool // Retire the original under the new identifiers and let the copy keep the marketplace ones
+}
+
+// GineeID returns the Ginee ID of copy n of the chain with the given base Ginee ID
+func (p DuplicatePolicy) GineeID(base string, n int) string {
+	return base + renderDuplicateAffix(p.GineeIDSuffix, n)
+}
+
+// Tracking returns the tracking of copy n of the chain with the given base tracking
+func (p DuplicatePolicy) Tracking(base string, n int) string {
+	return renderDuplicateAffix(p.TrackingPrefix, n) + base
+}
+
+// BaseGineeID strips every duplicate suffix from a Ginee ID
+func (p DuplicatePolicy) BaseGineeID(gineeID string) string {
+	pattern := regexp.MustCompile("(?:" + duplicateAffixPattern(p.GineeIDSuffix) + ")+$")
+	return pattern.ReplaceAllString(gineeID, "")
+}
+
+// BaseTracking strips every duplicate prefix from a tracking
+func (p DuplicatePolicy) BaseTracking(tracking string) string {
+	pattern := regexp.MustCompile("^(?:" + duplicateAffixPattern(p.TrackingPrefix) + ")+")
+	return pattern.ReplaceAllString(tracking, "")
+}
+
+// FindDuplicateChain returns every order that shares the base Ginee ID or base tracking of order,
+// order itself included
+func (p DuplicatePolicy) FindDuplicateChain(db *gorm.DB, order *Order) ([]Order, error) {
+	baseGineeID := p.BaseGineeID(order.OrderGineeID)
+	baseTracking := p.BaseTracking(order.Tracking)
+
+	var candidates []Order
+	if err := db.Select("id", "tracking", "order_ginee_id", "event_status", "created_at").
+		Where("order_ginee_id LIKE ? OR tracking LIKE ?", baseGineeID+"%", "%"+baseTracking).
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	var chain []Order
+	for _, candidate := range candidates {
+		if p.BaseGineeID(candidate.OrderGineeID) == baseGineeID || p.BaseTracking(candidate.Tracking) == baseTracking {
+			chain = append(chain, candidate)
+		}
+	}
+
+	return chain, nil
+}
+
+func renderDuplicateAffix(affix string, n int) string {
+	if strings.Contains(affix, "{n}") {
+		return strings.ReplaceAll(affix, "{n}", strconv.Itoa(n))
+	}
+	return strings.Repeat(affix, n-1)
+}
+
+func duplicateAffixPattern(affix string) string {
+	return strings.ReplaceAll(regexp.QuoteMeta(affix), `\{n\}`, `\d+`)
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OrderEvents are the manual order events reported by the order events report, as recorded in the
+// event status of the order
+var OrderEvents = []string{"changed", "duplicated", "cancelled", "merged"}
+
+// orderEventsQuery selects one row per order whose event status is a manual event, with the time
+// and operator of the event: the cancellation for cancelled orders, the merge for merged orders
+// and the last edit otherwise. Duplicated orders are the copies, their original is retired under
+// "old duplicated" and not counted again.
+const orderEventsQuery = `
+	SELECT orders.id AS order_id, orders.order_ginee_id, orders.tracking, orders.channel, orders.store,
+		orders.processing_status, orders.event_status AS event,
+		CASE orders.event_status WHEN 'cancelled' THEN orders.cancelled_at WHEN 'merged' THEN orders.merged_at ELSE orders.changed_at END AS event_at,
+		CASE orders.event_status WHEN 'cancelled' THEN orders.cancelled_by ELSE orders.changed_by END AS operator_id
+	FROM orders
+	WHERE orders.deleted_at IS NULL AND orders.event_status IN ?`
+
+// OrderEventFilter narrows the order events report
+type OrderEventFilter struct {
+	Start      time.Time // Inclusive
+	End        time.Time // Exclusive
+	Event      string    // One of OrderEvents, all when empty
+	OperatorID uint      // All operators when zero
+}
+
+// OrderEventCount is the number of orders with one event
+type OrderEventCount struct {
+	Event string `json:"event" example:"changed"`
+	Count int64  `json:"count" example:"12"`
+}
+
+// OrderEventOperator is the number of events an operator caused, per event
+type OrderEventOperator struct {
+	OperatorID uint   `json:"operator_id" example:"7"`
+	Operator   string `json:"operator" example:"Jane Doe"`
+	Changed    int64  `json:"changed" example:"9"`
+	Duplicated int64  `json:"duplicated" example:"1"`
+	Cancelled  int64  `json:"cancelled" example:"2"`
+	Merged     int64  `json:"merged" example:"0"`
+	Total      int64  `json:"total" example:"12"`
+}
+
+// OrderEventRow is one order of the order events report
+type OrderEventRow struct {
+	OrderID          uint      `json:"order_id"`
+	OrderGineeID     string    `json:"order_ginee_id" example:"2509116GA36VM5"`
+	Tracking         string    `json:"tracking" example:"JNE1234567890"`
+	Channel          string    `json:"channel" example:"Shopee"`
+	Store            string    `json:"store" example:"SP deParcelRibbon"`
+	ProcessingStatus string    `json:"processing_status" example:"ready to pick"`
+	Event            string    `json:"event" example:"changed"`
+	EventAt          time.Time `json:"event_at"`
+	OperatorID       *uint     `json:"operator_id"`
+	Operator         string    `json:"operator" example:"Jane Doe"`
+}
+
+// orderEvents returns the order events matching filter as a subquery aliased events
+func orderEvents(db *gorm.DB, filter OrderEventFilter) *gorm.DB {
+	events := OrderEvents
+	if filter.Event != "" {
+		events = []string{filter.Event}
+	}
+	query := db.Table("(?) AS events", db.Raw(orderEventsQuery, events)).
+		Where("events.event_at >= ? AND events.event_at < ?", filter.Start, filter.End)
+	if filter.OperatorID != 0 {
+		query = query.Where("events.operator_id = ?", filter.OperatorID)
+	}
+	return query
+}
+
+// CountOrderEvents counts the order events matching filter per event, every event listed even when
+// none happened, and per operator, most events first
+func CountOrderEvents(db *gorm.DB, filter OrderEventFilter) ([]OrderEventCount, []OrderEventOperator, error) {
+	var counted []OrderEventCount
+	if err := orderEvents(db, filter).
+		Select("events.event, COUNT(*) AS count").
+		Group("events.event").
+		Scan(&counted).Error; err != nil {
+		return nil, nil, err
+	}
+	byEvent := make(map[string]int64, len(counted))
+	for _, count := range counted {
+		byEvent[count.Event] = count.Count
+	}
+	totals := make([]OrderEventCount, 0, len(OrderEvents))
+	for _, event := range OrderEvents {
+		if filter.Event == "" || filter.Event == event {
+			totals = append(totals, OrderEventCount{Event: event, Count: byEvent[event]})
+		}
+	}
+
+	operators := []OrderEventOperator{}
+	if err := orderEvents(db, filter).
+		Select(`events.operator_id, COALESCE(users.full_name, '-') AS operator,
+			COUNT(*) FILTER (WHERE events.event = 'changed') AS changed,
+			COUNT(*) FILTER (WHERE events.event = 'duplicated') AS duplicated,
+			COUNT(*) FILTER (WHERE events.event = 'cancelled') AS cancelled,
+			COUNT(*) FILTER (WHERE events.event = 'merged') AS merged,
+			COUNT(*) AS total`).
+		Joins("LEFT JOIN users ON users.id = events.operator_id").
+		Where("events.operator_id IS NOT NULL").
+		Group("events.operator_id, users.full_name").
+		Order("total DESC, operator ASC").
+		Scan(&operators).Error; err != nil {
+		return nil, nil, err
+	}
+	return totals, operators, nil
+}
+
+// FindOrderEvents lists the order events matching filter, latest first. Total is the number of
+// rows before limit and offset.
+func FindOrderEvents(db *gorm.DB, filter OrderEventFilter, limit, offset int) ([]OrderEventRow, int64, error) {
+	var total int64
+	if err := orderEvents(db, filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	rows := []OrderEventRow{}
+	err := orderEvents(db, filter).
+		Select("events.*, COALESCE(users.full_name, '-') AS operator").
+		Joins("LEFT JOIN users ON users.id = events.operator_id").
+		Order("events.event_at DESC, events.order_id DESC").
+		Limit(limit).Offset(offset).
+		Scan(&rows).Error
+	return rows, total, err
+}
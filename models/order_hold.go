@@ -0,0 +1,37 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrOrderHeld is returned when an order on hold would be sent out
+var ErrOrderHeld = errors.New("order is on hold")
+
+// IsHeld reports whether the order is on hold. Held orders are not assigned, QC'd or sent out until
+// they are released, e.g. while the buyer changes the address.
+func (o *Order) IsHeld() bool {
+	return o.HeldAt != nil
+}
+
+// HoldOrder puts a loaded order on hold with a reason and records the change. Run it in a
+// transaction holding the order's lock.
+func HoldOrder(tx *gorm.DB, order *Order, reason string, userID uint, at time.Time) error {
+	change := OrderChange{OrderID: order.ID, Version: order.Version + 1, Field: "hold", OldValue: "", NewValue: reason, ChangedBy: userID}
+	if err := tx.Model(order).Updates(map[string]interface{}{"held_at": at, "held_by": userID, "hold_reason": reason}).Error; err != nil {
+		return err
+	}
+	return tx.Create(&change).Error
+}
+
+// UnholdOrder releases a loaded order from hold and records the change. Run it in a transaction
+// holding the order's lock.
+func UnholdOrder(tx *gorm.DB, order *Order, userID uint) error {
+	change := OrderChange{OrderID: order.ID, Version: order.Version + 1, Field: "hold", OldValue: order.HoldReason, NewValue: "", ChangedBy: userID}
+	if err := tx.Model(order).Updates(map[string]interface{}{"held_at": nil, "held_by": nil, "hold_reason": ""}).Error; err != nil {
+		return err
+	}
+	return tx.Create(&change).Error
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrOrderExists is returned by CreateIntakeOrder for an order whose Ginee ID is taken already
+var ErrOrderExists = errors.New("order already exists")
+
+// CreateIntakeOrder creates an order received from the marketplaces with its details. An order with
+// the same Ginee ID is left alone and ErrOrderExists returned, so every intake (bulk upload, Ginee
+// polling) can send the same order again. Orders without a courier get the default courier of their
+// store, and the address is validated with the Geocoder when one is configured.
+func CreateIntakeOrder(db *gorm.DB, order *Order) error {
+	var existing Order
+	err := db.Where("order_ginee_id = ?", order.OrderGineeID).First(&existing).Error
+	if err == nil {
+		return ErrOrderExists
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if order.Courier == "" && order.Store != "" {
+		settings, err := FindStoreSettings(db, order.Store)
+		if err != nil {
+			return err
+		}
+		order.Courier = settings.DefaultCourier
+	}
+
+	check, err := CheckAddress(db, order.Address)
+	if err != nil {
+		return err
+	}
+	check.ApplyTo(order)
+
+	return db.Create(order).Error
+}
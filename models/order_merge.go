@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// How lines of the same SKU and variant on both merged orders are combined
+const (
+	OrderMergeQuantityMax = "max" // A resent order repeats the same items, keep the larger quantity
+	OrderMergeQuantitySum = "sum" // The shipment was split across both orders, add the quantities up
+)
+
+// MergedAway reports whether the order was merged into another order. Merged orders keep their
+// details for the record but are never picked.
+func (o *Order) MergedAway() bool {
+	return o.MergedIntoID != nil
+}
+
+// ValidateOrderMerge checks that from can be merged into into: two different orders, neither
+// cancelled nor merged already, and neither picked yet. Into may have been paused while picking,
+// from must not have been started.
+func ValidateOrderMerge(into, from *Order) error {
+	if into.ID == from.ID {
+		return fmt.Errorf("an order cannot be merged into itself")
+	}
+	for _, order := range []*Order{into, from} {
+		if order.EventStatus != nil && *order.EventStatus == "cancelled" {
+			return fmt.Errorf("order %s is cancelled", order.OrderGineeID)
+		}
+		if order.MergedAway() {
+			return fmt.Errorf("order %s is already merged into order %d", order.OrderGineeID, *order.MergedIntoID)
+		}
+	}
+	if into.ProcessingStatus != "ready to pick" && into.ProcessingStatus != "pending picking" {
+		return fmt.Errorf("order %s is %s, only orders not picked yet can be merged", into.OrderGineeID, into.ProcessingStatus)
+	}
+	if from.ProcessingStatus != "ready to pick" {
+		return fmt.Errorf("order %s is %s, only orders whose picking has not started can be merged away", from.OrderGineeID, from.ProcessingStatus)
+	}
+	return nil
+}
+
+// MergeOrderDetails folds the lines of from into the lines of into, combining lines of the same SKU
+// and variant according to mode. Returns the lines of into to save: existing lines whose quantity
+// changed and new lines copied from from. Both orders need their OrderDetails loaded.
+func MergeOrderDetails(into, from *Order, mode string) []OrderDetail {
+	key := func(detail OrderDetail) string {
+		return strings.ToUpper(detail.Sku) + "\x00" + detail.Variant
+	}
+	existing := make(map[string]int, len(into.OrderDetails))
+	for i, detail := range into.OrderDetails {
+		existing[key(detail)] = i
+	}
+
+	combine := func(quantity, other int) int {
+		if mode == OrderMergeQuantitySum {
+			return quantity + other
+		}
+		if other > quantity {
+			return other
+		}
+		return quantity
+	}
+
+	var changed []OrderDetail
+	pending := make(map[string]int) // Index in changed of lines already combined
+	for _, detail := range from.OrderDetails {
+		k := key(detail)
+		if j, found := pending[k]; found {
+			changed[j].Quantity = combine(changed[j].Quantity, detail.Quantity)
+			continue
+		}
+
+		i, found := existing[k]
+		if !found {
+			pending[k] = len(changed)
+			changed = append(changed, OrderDetail{
+				OrderID:     into.ID,
+				Sku:         detail.Sku,
+				ProductName: detail.ProductName,
+				Variant:     detail.Variant,
+				Quantity:    detail.Quantity,
+				Price:       detail.Price,
+			})
+			continue
+		}
+
+		line := into.OrderDetails[i]
+		if quantity := combine(line.Quantity, detail.Quantity); quantity != line.Quantity {
+			line.Quantity = quantity
+			pending[k] = len(changed)
+			changed = append(changed, line)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Order priorities, from the sent before deadline
+const (
+	OrderPriorityNormal = "normal"
+	OrderPriorityUrgent = "urgent" // Sent before is within OrderUrgentWindow
+	OrderPriorityLate   = "late"   // Sent before has passed and the order is still in the warehouse
+)
+
+// OrderPriorityRank orders late orders first, then urgent, then normal
+const OrderPriorityRank = "CASE orders.priority WHEN 'late' THEN 0 WHEN 'urgent' THEN 1 ELSE 2 END"
+
+// OrderPriorityFor returns the priority of an order due by sentBefore. Orders without a sent before
+// stay normal.
+func OrderPriorityFor(sentBefore, now time.Time) string {
+	switch {
+	case sentBefore.IsZero():
+		return OrderPriorityNormal
+	case sentBefore.Before(now):
+		return OrderPriorityLate
+	case sentBefore.Before(now.Add(CurrentSettings().OrderUrgentWindow)):
+		return OrderPriorityUrgent
+	}
+	return OrderPriorityNormal
+}
+
+// ApplyPriority sets the priority from the current sent before. The caller saves the order.
+func (o *Order) ApplyPriority(now time.Time) {
+	o.Priority = OrderPriorityFor(o.SentBefore, now)
+}
+
+// EscalateOrderPriorities raises the priority of orders still in the warehouse whose sent before is
+// near or over. Priorities are only raised, an order that got a later sent before is recalculated
+// when it is edited. Bulk updates keep the order version, escalation is not an edit.
+func EscalateOrderPriorities(db *gorm.DB, now time.Time) (urgent, late int64, err error) {
+	active := func() *gorm.DB {
+		return db.Model(&Order{}).
+			Where("sent_before > ?", time.Time{}).
+			Where("processing_status <> ?", "outbound completed").
+			Where("event_status IS NULL OR event_status <> ?", "cancelled")
+	}
+
+	result := active().Where("priority <> ? AND sent_before < ?", OrderPriorityLate, now).
+		Update("priority", OrderPriorityLate)
+	if result.Error != nil {
+		return 0, 0, result.Error
+	}
+	late = result.RowsAffected
+
+	result = active().Where("priority = ? AND sent_before >= ? AND sent_before < ?", OrderPriorityNormal, now, now.Add(CurrentSettings().OrderUrgentWindow)).
+		Update("priority", OrderPriorityUrgent)
+	if result.Error != nil {
+		return 0, late, result.Error
+	}
+
+	return result.RowsAffected, late, nil
+}
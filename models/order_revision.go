@@ -0,0 +1,130 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"livo-backend/utilities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Actions that store an order revision before changing the order
+const (
+	OrderRevisionUpdate    = "update"
+	OrderRevisionCancel    = "cancel"
+	OrderRevisionDuplicate = "duplicate"
+)
+
+// OrderRevision keeps the whole order, order details included, as it was right before an update,
+// cancellation or duplication, so a dispute over who changed what can be settled from the stored
+// copies. Snapshot is the order in JSON with the buyer phone masked.
+type OrderRevision struct {
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	OrderID      uint           `gorm:"not null;index" json:"order_id"`
+	OrderVersion uint           `gorm:"not null" json:"order_version" example:"3"` // Version of the order in the snapshot
+	Action       string         `gorm:"not null" json:"action" example:"update"`   // What was about to change the order: update, cancel or duplicate
+	Snapshot     string         `gorm:"type:jsonb;not null" json:"-"`
+	CreatedBy    uint           `gorm:"not null" json:"created_by"` // User whose action stored the revision
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationship
+	Creator *User `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
+}
+
+type OrderRevisionResponse struct {
+	ID           uint            `json:"id"`
+	OrderID      uint            `json:"order_id"`
+	OrderVersion uint            `json:"order_version"`
+	Action       string          `json:"action"`
+	CreatedBy    string          `json:"created_by"`
+	CreatedAt    string          `json:"created_at"`
+	Snapshot     json.RawMessage `json:"snapshot" swaggertype:"object"`
+}
+
+// orderSnapshot is the stored form of an order, the buyer phone is left out of the order's own JSON
+type orderSnapshot struct {
+	Order
+	BuyerPhone string `json:"buyer_phone"` // Masked
+}
+
+// ToOrderRevisionResponse converts OrderRevision model to OrderRevisionResponse
+func (r *OrderRevision) ToOrderRevisionResponse() OrderRevisionResponse {
+	createdBy := "-"
+	if r.Creator != nil {
+		createdBy = r.Creator.FullName
+	}
+
+	return OrderRevisionResponse{
+		ID:           r.ID,
+		OrderID:      r.OrderID,
+		OrderVersion: r.OrderVersion,
+		Action:       r.Action,
+		CreatedBy:    createdBy,
+		CreatedAt:    r.CreatedAt.Format("2006-01-02 15:04:05"),
+		Snapshot:     json.RawMessage(r.Snapshot),
+	}
+}
+
+// SnapshotOrder serializes an order with its order details the way revisions store it. The order
+// needs its OrderDetails loaded.
+func SnapshotOrder(order *Order) (string, error) {
+	snapshot := orderSnapshot{Order: *order, BuyerPhone: utilities.MaskPhone(order.BuyerPhone)}
+	snapshot.PickOperator = nil
+	snapshot.PendingOperator = nil
+	snapshot.CancelOperator = nil
+	snapshot.ChangeOperator = nil
+	snapshot.AssignOperator = nil
+	snapshot.OrderDetails = make([]OrderDetail, len(order.OrderDetails))
+	for i, detail := range order.OrderDetails {
+		detail.Product = nil
+		snapshot.OrderDetails[i] = detail
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Order restores the order stored in the revision, with its order details and masked buyer phone
+func (r *OrderRevision) Order() (*Order, error) {
+	var snapshot orderSnapshot
+	if err := json.Unmarshal([]byte(r.Snapshot), &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to read order revision %d: %w", r.ID, err)
+	}
+	order := snapshot.Order
+	order.BuyerPhone = snapshot.BuyerPhone
+	return &order, nil
+}
+
+// RecordOrderRevision stores the order as it is before action changes it. Order details are loaded
+// from tx when the order comes without them.
+func RecordOrderRevision(tx *gorm.DB, order *Order, action string, userID uint) error {
+	snapshotted := *order
+	if snapshotted.OrderDetails == nil {
+		if err := tx.Where("order_id = ?", order.ID).Order("id ASC").Find(&snapshotted.OrderDetails).Error; err != nil {
+			return fmt.Errorf("failed to load order details: %w", err)
+		}
+	}
+
+	snapshot, err := SnapshotOrder(&snapshotted)
+	if err != nil {
+		return fmt.Errorf("failed to serialize order: %w", err)
+	}
+
+	revision := OrderRevision{
+		OrderID:      order.ID,
+		OrderVersion: order.Version,
+		Action:       action,
+		Snapshot:     snapshot,
+		CreatedBy:    userID,
+	}
+	if err := tx.Create(&revision).Error; err != nil {
+		return fmt.Errorf("failed to record order revision: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package models
+
+import "livo-backend/utilities"
+
+// OrderSortKeys are the sort keys of the order lists. Priority is the picking order: late and urgent
+// orders first, then instant courier orders, then the earliest sent before.
+var OrderSortKeys = utilities.SortKeys{
+	"sent_before": {"orders.sent_before"},
+	"created_at":  {"orders.created_at"},
+	"priority":    {OrderPriorityRank, "-orders.instant", "orders.sent_before"},
+	"buyer":       {"orders.buyer"},
+	"tracking":    {"orders.tracking"},
+	"status":      {"orders.processing_status"},
+}
+
+// PickedOrderSortKeys are the sort keys of the coordinators' picked orders list
+var PickedOrderSortKeys = utilities.SortKeys{
+	"sent_before": {"orders.sent_before"},
+	"created_at":  {"orders.created_at"},
+	"assigned_at": {"orders.assigned_at"},
+	"instant":     {"orders.instant"},
+	"priority":    {OrderPriorityRank, "-orders.instant", "orders.sent_before"},
+	"buyer":       {"orders.buyer"},
+	"tracking":    {"orders.tracking"},
+}
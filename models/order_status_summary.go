@@ -0,0 +1,98 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// OrderEventStatusNone stands for orders without an event status in the status summary
+const OrderEventStatusNone = "none"
+
+// OrderStatusCount counts the orders in one status
+type OrderStatusCount struct {
+	Status string `json:"status" example:"ready to pick"`
+	Count  int64  `json:"count" example:"120"`
+}
+
+// OrderStatusPairCount counts the orders in one processing and event status
+type OrderStatusPairCount struct {
+	ProcessingStatus string `json:"processing_status" example:"ready to pick"`
+	EventStatus      string `json:"event_status" example:"none"`
+	Count            int64  `json:"count" example:"118"`
+}
+
+// OrderStatusSummary counts orders per processing status and per event status, for dashboard tiles
+type OrderStatusSummary struct {
+	Total              int64                  `json:"total" example:"640"`
+	Held               int64                  `json:"held" example:"3"` // Orders on hold, whatever their status
+	ProcessingStatuses []OrderStatusCount     `json:"processing_statuses"`
+	EventStatuses      []OrderStatusCount     `json:"event_statuses"` // Orders without one are counted as "none"
+	Combinations       []OrderStatusPairCount `json:"combinations"`
+}
+
+// BuildOrderStatusSummary counts the orders of query, an orders query with its filters applied, in a
+// single grouped query. Statuses with the most orders come first.
+func BuildOrderStatusSummary(query *gorm.DB) (OrderStatusSummary, error) {
+	summary := OrderStatusSummary{
+		ProcessingStatuses: []OrderStatusCount{},
+		EventStatuses:      []OrderStatusCount{},
+		Combinations:       []OrderStatusPairCount{},
+	}
+
+	var rows []struct {
+		ProcessingStatus string
+		EventStatus      string
+		Orders           int64
+		Held             int64
+	}
+	eventStatus := "COALESCE(orders.event_status, '" + OrderEventStatusNone + "')"
+	if err := query.Select("orders.processing_status AS processing_status, " + eventStatus + " AS event_status, COUNT(*) AS orders, COUNT(orders.held_at) AS held").
+		Group("orders.processing_status, " + eventStatus).
+		Scan(&rows).Error; err != nil {
+		return summary, fmt.Errorf("failed to count orders per status: %w", err)
+	}
+
+	processing := make(map[string]int64)
+	events := make(map[string]int64)
+	for _, row := range rows {
+		summary.Total += row.Orders
+		summary.Held += row.Held
+		processing[row.ProcessingStatus] += row.Orders
+		events[row.EventStatus] += row.Orders
+		summary.Combinations = append(summary.Combinations, OrderStatusPairCount{
+			ProcessingStatus: row.ProcessingStatus,
+			EventStatus:      row.EventStatus,
+			Count:            row.Orders,
+		})
+	}
+
+	summary.ProcessingStatuses = sortedStatusCounts(processing)
+	summary.EventStatuses = sortedStatusCounts(events)
+	sort.Slice(summary.Combinations, func(i, j int) bool {
+		a, b := summary.Combinations[i], summary.Combinations[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.ProcessingStatus != b.ProcessingStatus {
+			return a.ProcessingStatus < b.ProcessingStatus
+		}
+		return a.EventStatus < b.EventStatus
+	})
+	return summary, nil
+}
+
+func sortedStatusCounts(counts map[string]int64) []OrderStatusCount {
+	result := make([]OrderStatusCount, 0, len(counts))
+	for status, count := range counts {
+		result = append(result, OrderStatusCount{Status: status, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Status < result[j].Status
+	})
+	return result
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// orderStatusSince is the SQL expression of the time an order entered a processing status. Statuses
+// without a timestamp of their own fall back to the last update of the order.
+var orderStatusSince = map[string]string{
+	"ready to pick":     "orders.created_at",
+	"pending picking":   "orders.pending_at",
+	"picking process":   "orders.assigned_at",
+	"picking complete":  "orders.picked_at",
+	"picking completed": "orders.picked_at",
+	"qc complete": `COALESCE(GREATEST(
+		(SELECT MAX(qc_ribbons.created_at) FROM qc_ribbons WHERE qc_ribbons.tracking = orders.tracking AND qc_ribbons.deleted_at IS NULL),
+		(SELECT MAX(qc_onlines.created_at) FROM qc_onlines WHERE qc_onlines.tracking = orders.tracking AND qc_onlines.deleted_at IS NULL)
+	), orders.updated_at)`,
+}
+
+// StuckOrder is an order flagged at risk for staying too long in a processing status
+type StuckOrder struct {
+	OrderID      uint      `json:"order_id"`
+	OrderGineeID string    `json:"order_ginee_id" example:"2509116GA36VM5"`
+	Tracking     string    `json:"tracking" example:"JNE1234567890"`
+	Status       string    `json:"status" example:"picking process"`
+	Since        time.Time `json:"since"`
+	StuckMinutes int       `json:"stuck_minutes" example:"275"`
+}
+
+// ParseOrderStuckThresholds parses "status=duration" pairs separated by commas, e.g.
+// "picking process=4h,picking complete=12h". A duration of 0 leaves the status unmonitored.
+func ParseOrderStuckThresholds(value string) (map[string]time.Duration, error) {
+	thresholds := make(map[string]time.Duration)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		status, duration, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("threshold %q must look like status=duration", pair)
+		}
+		status = strings.ToLower(strings.TrimSpace(status))
+
+		parsed, err := time.ParseDuration(strings.TrimSpace(duration))
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("threshold of status %s must be a duration such as 4h or 90m", status)
+		}
+		if parsed > 0 {
+			thresholds[status] = parsed
+		}
+	}
+
+	return thresholds, nil
+}
+
+// FlagStuckOrders flags orders that stayed in a monitored status beyond its threshold as at risk and
+// clears the flag of orders that moved on. Cancelled orders are left alone. Returns the orders
+// flagged by this run, oldest first, and the number of orders cleared.
+func FlagStuckOrders(db *gorm.DB, now time.Time) ([]StuckOrder, int64, error) {
+	thresholds := CurrentSettings().OrderStuckThresholds
+	statuses := make([]string, 0, len(thresholds))
+	for status := range thresholds {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	// Bulk updates keep the order version, the flag is not an edit
+	unflag := db.Model(&Order{}).Where("at_risk_at IS NOT NULL")
+	if len(statuses) > 0 {
+		unflag = unflag.Where("(processing_status <> at_risk_status OR processing_status NOT IN ? OR cancelled_at IS NOT NULL)", statuses)
+	}
+	cleared := unflag.UpdateColumns(map[string]interface{}{"at_risk_at": nil, "at_risk_status": ""})
+	if cleared.Error != nil {
+		return nil, 0, cleared.Error
+	}
+
+	flagged := []StuckOrder{}
+	for _, status := range statuses {
+		since, found := orderStatusSince[status]
+		if !found {
+			since = "orders.updated_at"
+		}
+
+		var stuck []StuckOrder
+		if err := db.Model(&Order{}).
+			Select("orders.id AS order_id, orders.order_ginee_id, orders.tracking, orders.processing_status AS status, "+since+" AS since").
+			Where("orders.processing_status = ? AND orders.at_risk_at IS NULL AND orders.cancelled_at IS NULL", status).
+			Where(since+" < ?", now.Add(-thresholds[status])).
+			Order("since").
+			Scan(&stuck).Error; err != nil {
+			return flagged, cleared.RowsAffected, err
+		}
+		if len(stuck) == 0 {
+			continue
+		}
+
+		ids := make([]uint, len(stuck))
+		for i := range stuck {
+			ids[i] = stuck[i].OrderID
+			stuck[i].StuckMinutes = int(now.Sub(stuck[i].Since).Minutes())
+		}
+		if err := db.Model(&Order{}).
+			Where("id IN ? AND at_risk_at IS NULL", ids).
+			UpdateColumns(map[string]interface{}{"at_risk_at": now, "at_risk_status": status}).Error; err != nil {
+			return flagged, cleared.RowsAffected, err
+		}
+
+		flagged = append(flagged, stuck...)
+	}
+
+	sort.SliceStable(flagged, func(i, j int) bool {
+		return flagged[i].Since.Before(flagged[j].Since)
+	})
+	return flagged, cleared.RowsAffected, nil
+}
+
+// AtRiskOrderCounts returns the number of orders currently flagged at risk per status
+func AtRiskOrderCounts(db *gorm.DB) (map[string]int64, error) {
+	var rows []struct {
+		AtRiskStatus string
+		Count        int64
+	}
+	if err := db.Model(&Order{}).
+		Select("at_risk_status, COUNT(*) AS count").
+		Where("at_risk_at IS NOT NULL").
+		Group("at_risk_status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.AtRiskStatus] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,205 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Sources of order timeline events
+const (
+	TimelineSourceOrder    = "order"
+	TimelineSourcePicking  = "picking"
+	TimelineSourceQcRibbon = "qc-ribbon"
+	TimelineSourceQcOnline = "qc-online"
+	TimelineSourceOutbound = "outbound"
+	TimelineSourceDelivery = "delivery"
+	TimelineSourceReturn   = "return"
+	TimelineSourceComplain = "complain"
+)
+
+// TimelineEvent is one step in the life of an order
+type TimelineEvent struct {
+	At      time.Time `json:"at"`
+	Source  string    `json:"source" example:"qc-ribbon"`
+	Event   string    `json:"event" example:"QC ribbon completed"`
+	ActorID *uint     `json:"actor_id"`
+	Actor   string    `json:"actor" example:"Jane Doe"` // "-" for system and courier events
+	Detail  string    `json:"detail" example:"2 box(es)"`
+}
+
+// BuildOrderTimeline merges the audit fields of an order with its picking, QC, outbound, delivery,
+// return and complain records into one chronological timeline, oldest first
+func BuildOrderTimeline(db *gorm.DB, order *Order) ([]TimelineEvent, error) {
+	var events []TimelineEvent
+	add := func(at *time.Time, source, event string, actorID *uint, detail string) {
+		if at == nil || at.IsZero() {
+			return
+		}
+		events = append(events, TimelineEvent{At: *at, Source: source, Event: event, ActorID: actorID, Detail: detail})
+	}
+
+	// Order audit fields
+	add(&order.CreatedAt, TimelineSourceOrder, "Order created", nil, fmt.Sprintf("%s / %s via %s", order.Channel, order.Store, order.Courier))
+	add(order.AssignedAt, TimelineSourcePicking, "Picker assigned", order.AssignedBy, "")
+	add(order.PendingAt, TimelineSourcePicking, "Picking pending", order.PendingBy, "")
+	add(order.CancelledAt, TimelineSourceOrder, "Order cancelled", order.CancelledBy, "")
+
+	// Edits, one event per version
+	var changes []OrderChange
+	if err := db.Where("order_id = ?", order.ID).Order("version ASC, id ASC").Find(&changes).Error; err != nil {
+		return nil, fmt.Errorf("failed to load order changes: %w", err)
+	}
+	for i := 0; i < len(changes); {
+		j := i
+		var fields []string
+		for ; j < len(changes) && changes[j].Version == changes[i].Version; j++ {
+			fields = append(fields, changes[j].Field)
+		}
+		changedBy := changes[i].ChangedBy
+		switch {
+		case changes[i].Field == "hold" && changes[i].NewValue != "":
+			add(&changes[i].CreatedAt, TimelineSourceOrder, "Order held", &changedBy, changes[i].NewValue)
+		case changes[i].Field == "hold":
+			add(&changes[i].CreatedAt, TimelineSourceOrder, "Order released", &changedBy, changes[i].OldValue)
+		default:
+			add(&changes[i].CreatedAt, TimelineSourceOrder, "Order edited", &changedBy, strings.Join(uniqueStrings(fields), ", "))
+		}
+		i = j
+	}
+
+	// Completed picks
+	var picks []PickedOrder
+	if err := db.Where("order_id = ?", order.ID).Order("created_at ASC").Find(&picks).Error; err != nil {
+		return nil, fmt.Errorf("failed to load picked orders: %w", err)
+	}
+	for i := range picks {
+		pickedBy := picks[i].PickedBy
+		detail := ""
+		if picks[i].OverrideReason != "" {
+			detail = "Completed without full scan: " + picks[i].OverrideReason
+		}
+		add(&picks[i].CreatedAt, TimelineSourcePicking, "Picking completed", &pickedBy, detail)
+	}
+
+	// QC
+	var qcRibbons []QcRibbon
+	if err := db.Preload("QcRibbonDetails").Where("tracking = ?", order.Tracking).Find(&qcRibbons).Error; err != nil {
+		return nil, fmt.Errorf("failed to load qc-ribbons: %w", err)
+	}
+	for i := range qcRibbons {
+		add(&qcRibbons[i].CreatedAt, TimelineSourceQcRibbon, "QC ribbon completed", qcRibbons[i].QcBy, qcTimelineDetail(order, qcRibbons[i].ParcelNumber, len(qcRibbons[i].QcRibbonDetails)))
+	}
+
+	var qcOnlines []QcOnline
+	if err := db.Preload("QcOnlineDetails").Where("tracking = ?", order.Tracking).Find(&qcOnlines).Error; err != nil {
+		return nil, fmt.Errorf("failed to load qc-onlines: %w", err)
+	}
+	for i := range qcOnlines {
+		add(&qcOnlines[i].CreatedAt, TimelineSourceQcOnline, "QC online completed", qcOnlines[i].QcBy, qcTimelineDetail(order, qcOnlines[i].ParcelNumber, len(qcOnlines[i].QcOnlineDetails)))
+	}
+
+	// Outbound and courier checkpoints
+	var outbounds []Outbound
+	if err := db.Where("tracking = ?", order.Tracking).Find(&outbounds).Error; err != nil {
+		return nil, fmt.Errorf("failed to load outbounds: %w", err)
+	}
+	for i := range outbounds {
+		add(&outbounds[i].CreatedAt, TimelineSourceOutbound, "Handed to courier", outbounds[i].OutboundBy, outbounds[i].Expedition)
+	}
+
+	var histories []DeliveryHistory
+	if err := db.Where("order_id = ?", order.ID).Find(&histories).Error; err != nil {
+		return nil, fmt.Errorf("failed to load delivery history: %w", err)
+	}
+	for i := range histories {
+		detail := histories[i].Description
+		if histories[i].Location != "" {
+			detail += " (" + histories[i].Location + ")"
+		}
+		add(&histories[i].EventAt, TimelineSourceDelivery, "Delivery "+strings.ReplaceAll(histories[i].Status, "_", " "), nil, detail)
+	}
+
+	// Returns of the parcel
+	var returns []Return
+	if err := db.Where("old_tracking = ?", order.Tracking).Find(&returns).Error; err != nil {
+		return nil, fmt.Errorf("failed to load returns: %w", err)
+	}
+	for i := range returns {
+		createdBy := returns[i].CreatedBy
+		add(&returns[i].CreatedAt, TimelineSourceReturn, "Return received", &createdBy, fmt.Sprintf("%s: %s", returns[i].ReturnType, returns[i].ReturnReason))
+		switch {
+		case returns[i].ScrapNumber != "":
+			add(&returns[i].UpdatedAt, TimelineSourceReturn, "Return scrapped", returns[i].UpdatedBy, returns[i].ScrapNumber)
+		case returns[i].ReturnNumber != "":
+			add(&returns[i].UpdatedAt, TimelineSourceReturn, "Return restocked", returns[i].UpdatedBy, returns[i].ReturnNumber)
+		}
+	}
+
+	// Complains and their escalations
+	var complains []Complain
+	if err := db.Preload("Escalations").Where("tracking = ?", order.Tracking).Find(&complains).Error; err != nil {
+		return nil, fmt.Errorf("failed to load complains: %w", err)
+	}
+	for i := range complains {
+		createdBy := complains[i].CreatedBy
+		add(&complains[i].CreatedAt, TimelineSourceComplain, "Complain filed", &createdBy, complains[i].Code+": "+complains[i].Description)
+		for j := range complains[i].Escalations {
+			escalation := &complains[i].Escalations[j]
+			add(&escalation.CreatedAt, TimelineSourceComplain, "Complain escalated", escalation.EscalatedBy, fmt.Sprintf("%s to %s: %s", escalation.FromLevel, escalation.ToLevel, escalation.Reason))
+		}
+	}
+
+	sort.SliceStable(events, func(a, b int) bool { return events[a].At.Before(events[b].At) })
+
+	// Resolve actor names in one query
+	actorIDs := make([]uint, 0, len(events))
+	for _, event := range events {
+		if event.ActorID != nil {
+			actorIDs = append(actorIDs, *event.ActorID)
+		}
+	}
+	names := make(map[uint]string)
+	if len(actorIDs) > 0 {
+		var users []User
+		if err := db.Unscoped().Select("id", "full_name").Where("id IN ?", actorIDs).Find(&users).Error; err != nil {
+			return nil, fmt.Errorf("failed to load actors: %w", err)
+		}
+		for _, user := range users {
+			names[user.ID] = user.FullName
+		}
+	}
+	for i := range events {
+		events[i].Actor = "-"
+		if events[i].ActorID != nil {
+			if name, found := names[*events[i].ActorID]; found {
+				events[i].Actor = name
+			}
+		}
+	}
+
+	return events, nil
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := values[:0]
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// qcTimelineDetail describes a QC record of order, naming the parcel when the order ships in several
+func qcTimelineDetail(order *Order, parcelNumber, boxes int) string {
+	if order.ParcelCount > 1 {
+		return fmt.Sprintf("Parcel %d of %d, %d box(es)", parcelNumber, order.ParcelCount, boxes)
+	}
+	return fmt.Sprintf("%d box(es)", boxes)
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Roles that see every order
+var orderVisibleToAllRoles = map[string]bool{
+	"superadmin":  true,
+	"admin":       true,
+	"coordinator": true,
+	"finance":     true,
+	"retur":       true,
+}
+
+// Processing statuses of orders waiting for or going through QC
+var qcStageStatuses = []string{"picking complete", "picking completed", "qc process", "qc complete"}
+
+// Processing statuses of orders waiting for or handed to outbound
+var outboundStageStatuses = []string{"qc complete", "outbound completed"}
+
+// VisibleOrders scopes an orders query to the orders a user with the given JWT roles may see:
+// pickers their own assigned orders, QC roles orders in the QC stages, outbound orders ready to be
+// sent out, and admin roles every order. Roles are combined, a user sees the orders of any of them;
+// a user without a matching role sees none.
+func VisibleOrders(userID uint, roles []string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		var conditions []string
+		var args []interface{}
+
+		for _, role := range roles {
+			if orderVisibleToAllRoles[role] {
+				return db
+			}
+
+			switch role {
+			case "picker":
+				conditions = append(conditions, "orders.picked_by = ?")
+				args = append(args, userID)
+			case "qc-ribbon", "qc-online":
+				conditions = append(conditions, "orders.processing_status IN ?")
+				args = append(args, qcStageStatuses)
+			case "outbound":
+				conditions = append(conditions, "orders.processing_status IN ?")
+				args = append(args, outboundStageStatuses)
+			}
+		}
+
+		if len(conditions) == 0 {
+			return db.Where("1 = 0")
+		}
+
+		return db.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+}
@@ -14,6 +14,8 @@ type Outbound struct {
 	ExpeditionColor string         `gorm:"not null" json:"expedition_color" example:"#FF5733"`
 	ExpeditionSlug  string         `gorm:"not null" json:"expedition_slug" example:"jne"`
 	Complained      bool           `gorm:"default:false" json:"complained" example:"false"`
+	PickupSlotID    *uint          `gorm:"index" json:"pickup_slot_id" example:"1"` // Courier pickup the parcel leaves with, see AssignPickupSlot
+	PickupAt        *time.Time     `gorm:"index" json:"pickup_at"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
 	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
@@ -24,15 +26,17 @@ type Outbound struct {
 }
 
 type OutboundResponse struct {
-	ID              uint      `json:"id"`
-	Tracking        string    `json:"tracking"`
-	OutboundBy      *uint     `json:"outbound_by"`
-	Expedition      string    `json:"expedition"`
-	ExpeditionColor string    `json:"expedition_color"`
-	ExpeditionSlug  string    `json:"expedition_slug"`
-	Complained      bool      `json:"complained"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID              uint       `json:"id"`
+	Tracking        string     `json:"tracking"`
+	OutboundBy      *uint      `json:"outbound_by"`
+	Expedition      string     `json:"expedition"`
+	ExpeditionColor string     `json:"expedition_color"`
+	ExpeditionSlug  string     `json:"expedition_slug"`
+	Complained      bool       `json:"complained"`
+	PickupSlotID    *uint      `json:"pickup_slot_id"`
+	PickupAt        *time.Time `json:"pickup_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 
 	// Related data
 	Order            *OrderResponse `json:"order,omitempty"`
@@ -49,6 +53,8 @@ func (ob *Outbound) ToOutboundResponse() OutboundResponse {
 		ExpeditionColor: ob.ExpeditionColor,
 		ExpeditionSlug:  ob.ExpeditionSlug,
 		Complained:      ob.Complained,
+		PickupSlotID:    ob.PickupSlotID,
+		PickupAt:        ob.PickupAt,
 		CreatedAt:       ob.CreatedAt,
 		UpdatedAt:       ob.UpdatedAt,
 	}
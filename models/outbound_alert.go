@@ -0,0 +1,244 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Outbound alert reasons
+const (
+	OutboundAlertAlreadyShipped  = "already_shipped"  // The tracking already has an outbound
+	OutboundAlertSiblingShipped  = "sibling_shipped"  // Another order of the duplicate chain already has an outbound
+	OutboundAlertCourierMismatch = "courier_mismatch" // The tracking belongs to another courier than the order ships with
+)
+
+// Outbound alert statuses
+const (
+	OutboundAlertOpen       = "open"
+	OutboundAlertOverridden = "overridden"
+	OutboundAlertDismissed  = "dismissed"
+)
+
+// OutboundAlert records an outbound scan that was blocked because the parcel would be shipped twice
+// or with the wrong courier
+type OutboundAlert struct {
+	ID                 uint           `gorm:"primaryKey" json:"id"`
+	Tracking           string         `gorm:"not null;index" json:"tracking" example:"JNE1234567890"`
+	OrderID            uint           `gorm:"not null;index" json:"order_id"`
+	Reason             string         `gorm:"not null" json:"reason" example:"sibling_shipped"`
+	ConflictTracking   string         `json:"conflict_tracking" example:"X-JNE1234567890"` // Tracking of the outbound that already left
+	ConflictOutboundID uint           `json:"conflict_outbound_id"`
+	Detail             string         `json:"detail" example:"Tracking is J&T Express, the order ships with JNE"`
+	ScanCount          int            `gorm:"default:1" json:"scan_count"`
+	ScannedBy          *uint          `gorm:"default:null" json:"scanned_by"`
+	Status             string         `gorm:"not null;index;default:'open'" json:"status" example:"open"`
+	ResolvedBy         *uint          `gorm:"default:null" json:"resolved_by"`
+	ResolvedAt         *time.Time     `gorm:"default:null" json:"resolved_at"`
+	ResolutionNote     string         `json:"resolution_note"`
+	OverrideToken      string         `json:"-"`                               // Given to the outbound station when a courier mismatch is overridden
+	OutboundID         *uint          `gorm:"default:null" json:"outbound_id"` // Outbound created under an override
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationship
+	Scanner  *User `gorm:"foreignKey:ScannedBy" json:"scanner,omitempty"`
+	Resolver *User `gorm:"foreignKey:ResolvedBy" json:"resolver,omitempty"`
+}
+
+type OutboundAlertResponse struct {
+	ID                 uint   `json:"id"`
+	Tracking           string `json:"tracking"`
+	OrderID            uint   `json:"order_id"`
+	Reason             string `json:"reason"`
+	ConflictTracking   string `json:"conflict_tracking"`
+	ConflictOutboundID uint   `json:"conflict_outbound_id"`
+	Detail             string `json:"detail"`
+	ScanCount          int    `json:"scan_count"`
+	ScannedBy          string `json:"scanned_by"`
+	Status             string `json:"status"`
+	ResolvedBy         string `json:"resolved_by"`
+	ResolvedAt         string `json:"resolved_at"`
+	ResolutionNote     string `json:"resolution_note"`
+	OverrideToken      string `json:"override_token,omitempty"` // Token the outbound scan of an overridden courier mismatch must carry
+	OutboundID         *uint  `json:"outbound_id"`
+	CreatedAt          string `json:"created_at"`
+}
+
+// ToOutboundAlertResponse converts OutboundAlert model to OutboundAlertResponse
+func (a *OutboundAlert) ToOutboundAlertResponse() OutboundAlertResponse {
+	scannedBy := "-"
+	if a.Scanner != nil {
+		scannedBy = a.Scanner.FullName
+	}
+
+	resolvedBy := "-"
+	if a.Resolver != nil {
+		resolvedBy = a.Resolver.FullName
+	}
+
+	resolvedAt := "-"
+	if a.ResolvedAt != nil {
+		resolvedAt = a.ResolvedAt.Format("2006-01-02 15:04:05")
+	}
+
+	return OutboundAlertResponse{
+		ID:                 a.ID,
+		Tracking:           a.Tracking,
+		OrderID:            a.OrderID,
+		Reason:             a.Reason,
+		ConflictTracking:   a.ConflictTracking,
+		ConflictOutboundID: a.ConflictOutboundID,
+		Detail:             a.Detail,
+		ScanCount:          a.ScanCount,
+		ScannedBy:          scannedBy,
+		Status:             a.Status,
+		ResolvedBy:         resolvedBy,
+		ResolvedAt:         resolvedAt,
+		ResolutionNote:     a.ResolutionNote,
+		OverrideToken:      a.OverrideToken,
+		OutboundID:         a.OutboundID,
+		CreatedAt:          a.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// FindShippedSibling returns the outbound of another order in the duplicate chain of order, or nil.
+// Every order of a chain shares the same base tracking or base Ginee ID, see DuplicatePolicy.
+func FindShippedSibling(db *gorm.DB, order *Order) (*Outbound, error) {
+	if order.Tracking == "" {
+		return nil, nil
+	}
+
+	chain, err := CurrentSettings().OrderDuplicatePolicy.FindDuplicateChain(db, order)
+	if err != nil {
+		return nil, err
+	}
+
+	var siblingTrackings []string
+	for _, sibling := range chain {
+		if sibling.ID != order.ID {
+			siblingTrackings = append(siblingTrackings, sibling.Tracking)
+		}
+	}
+
+	if len(siblingTrackings) == 0 {
+		return nil, nil
+	}
+
+	var outbound Outbound
+	err = db.Where("tracking IN ?", siblingTrackings).Order("created_at").First(&outbound).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &outbound, nil
+}
+
+// RaiseOutboundAlert records a blocked outbound scan and notifies coordinators. Repeated scans of
+// the same parcel bump the open alert instead of creating a new one.
+func RaiseOutboundAlert(db *gorm.DB, order *Order, reason string, conflict *Outbound, scannedBy uint) (*OutboundAlert, error) {
+	alert := OutboundAlert{
+		Tracking:           order.Tracking,
+		OrderID:            order.ID,
+		Reason:             reason,
+		ConflictTracking:   conflict.Tracking,
+		ConflictOutboundID: conflict.ID,
+		ScanCount:          1,
+		ScannedBy:          &scannedBy,
+		Status:             OutboundAlertOpen,
+	}
+
+	message := fmt.Sprintf("Outbound scan of %s was blocked, it already left as %s on %s", order.Tracking, conflict.Tracking, conflict.CreatedAt.Format("2006-01-02 15:04"))
+	if reason == OutboundAlertSiblingShipped {
+		message = fmt.Sprintf("Outbound scan of %s was blocked, duplicate order %s already left on %s. Override the alert to ship it anyway.", order.Tracking, conflict.Tracking, conflict.CreatedAt.Format("2006-01-02 15:04"))
+	}
+
+	return raiseOutboundAlert(db, alert, "duplicate_parcel", "Duplicate parcel blocked at outbound", message)
+}
+
+// RaiseCourierMismatchAlert records an outbound scan blocked because the tracking was detected as
+// scannedExpedition while the order ships with courier, and notifies coordinators
+func RaiseCourierMismatchAlert(db *gorm.DB, order *Order, scannedExpedition string, courier string, scannedBy uint) (*OutboundAlert, error) {
+	alert := OutboundAlert{
+		Tracking:  order.Tracking,
+		OrderID:   order.ID,
+		Reason:    OutboundAlertCourierMismatch,
+		Detail:    fmt.Sprintf("Tracking is %s, the order ships with %s", scannedExpedition, courier),
+		ScanCount: 1,
+		ScannedBy: &scannedBy,
+		Status:    OutboundAlertOpen,
+	}
+
+	message := fmt.Sprintf("Outbound scan of %s was blocked: tracking is %s, the order ships with %s. Override the alert and give the token to the outbound station to ship it anyway.", order.Tracking, scannedExpedition, courier)
+	return raiseOutboundAlert(db, alert, "courier_mismatch", "Courier mismatch blocked at outbound", message)
+}
+
+// raiseOutboundAlert creates alert, or bumps the open alert of the tracking with the same reason,
+// and notifies coordinators of new alerts
+func raiseOutboundAlert(db *gorm.DB, alert OutboundAlert, notificationType, title, message string) (*OutboundAlert, error) {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var open OutboundAlert
+		err := tx.Where("tracking = ? AND reason = ? AND status = ?", alert.Tracking, alert.Reason, OutboundAlertOpen).First(&open).Error
+		if err == nil {
+			scannedBy := alert.ScannedBy
+			alert = open
+			return tx.Model(&alert).Updates(map[string]interface{}{
+				"scan_count": gorm.Expr("scan_count + 1"),
+				"scanned_by": scannedBy,
+			}).Error
+		}
+		if err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		if err := tx.Create(&alert).Error; err != nil {
+			return err
+		}
+
+		return NotifyUsersWithRoles(tx,
+			[]string{"coordinator"},
+			notificationType,
+			title,
+			message,
+			"outbound_alert",
+			alert.ID,
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &alert, nil
+}
+
+// FindCourierMismatch returns the expedition the courier of order names when it is not scannedExpedition,
+// the expedition detected from the scanned tracking. Nil when they agree or the courier names no
+// known expedition, e.g. a store's own courier.
+func FindCourierMismatch(db *gorm.DB, order *Order, scannedExpedition string) (*Expedition, error) {
+	courierExpedition, err := FindCourierExpedition(db, order.Courier)
+	if err != nil || courierExpedition == nil {
+		return nil, err
+	}
+	if strings.EqualFold(courierExpedition.Name, scannedExpedition) {
+		return nil, nil
+	}
+	return courierExpedition, nil
+}
+
+// GenerateOverrideToken creates the token a coordinator gives to the outbound station to let an
+// overridden courier mismatch through
+func GenerateOverrideToken() (string, error) {
+	token := make([]byte, 4)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return strings.ToUpper(hex.EncodeToString(token)), nil
+}
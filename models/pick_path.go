@@ -0,0 +1,124 @@
+package models
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// PickLocation is a product location split for pick path ordering, e.g. "Rak A1-4" is aisle
+// "RAK A", rack 1, level 4
+type PickLocation struct {
+	Aisle   string // Text before the first number, upper case
+	Numbers []int  // Rack, then level and bin when present
+}
+
+// ParsePickLocation splits location into its aisle and the numbers after it. ok is false when the
+// location has no number to order by.
+func ParsePickLocation(location string) (PickLocation, bool) {
+	location = strings.ToUpper(strings.TrimSpace(location))
+	start := strings.IndexFunc(location, unicode.IsDigit)
+	if start < 0 {
+		return PickLocation{}, false
+	}
+
+	parsed := PickLocation{Aisle: strings.TrimRight(location[:start], " -_/.")}
+	for _, field := range strings.FieldsFunc(location[start:], func(r rune) bool { return !unicode.IsDigit(r) }) {
+		number, err := strconv.Atoi(field)
+		if err != nil {
+			return PickLocation{}, false
+		}
+		parsed.Numbers = append(parsed.Numbers, number)
+	}
+	return parsed, true
+}
+
+// PickPath orders product locations the way a picker walks the warehouse: zones in their pick
+// sequence, then aisle by aisle and rack by rack within a zone. Locations outside every zone come
+// after the zones, alphabetically, and items without a location come last.
+type PickPath struct {
+	zones []Zone
+}
+
+// LoadPickPath loads the zones in pick sequence
+func LoadPickPath(db *gorm.DB) (*PickPath, error) {
+	var zones []Zone
+	if err := db.Order("pick_sequence ASC, code ASC").Find(&zones).Error; err != nil {
+		return nil, err
+	}
+	return &PickPath{zones: zones}, nil
+}
+
+// zoneOf returns the rank of the zone whose prefix matches location, the longest prefix when
+// several match, and len(zones) when none does
+func (p *PickPath) zoneOf(location string) int {
+	rank, matched := len(p.zones), 0
+	upper := strings.ToUpper(location)
+	for i, zone := range p.zones {
+		prefix := strings.ToUpper(zone.LocationPrefix)
+		if prefix != "" && strings.HasPrefix(upper, prefix) && len(prefix) > matched {
+			rank, matched = i, len(prefix)
+		}
+	}
+	return rank
+}
+
+// Less reports whether location a is picked before location b
+func (p *PickPath) Less(a, b string) bool {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	if a == "" || b == "" {
+		return a != "" && b == ""
+	}
+
+	zoneA, zoneB := p.zoneOf(a), p.zoneOf(b)
+	if zoneA != zoneB {
+		return zoneA < zoneB
+	}
+	if zoneA == len(p.zones) {
+		return a < b
+	}
+
+	parsedA, okA := ParsePickLocation(a)
+	parsedB, okB := ParsePickLocation(b)
+	if !okA || !okB {
+		if okA != okB {
+			return okA
+		}
+		return a < b
+	}
+	if parsedA.Aisle != parsedB.Aisle {
+		return parsedA.Aisle < parsedB.Aisle
+	}
+
+	descending := p.zones[zoneA].RackDescending
+	for i := 0; i < len(parsedA.Numbers) && i < len(parsedB.Numbers); i++ {
+		if parsedA.Numbers[i] == parsedB.Numbers[i] {
+			continue
+		}
+		if i == 0 && descending {
+			return parsedA.Numbers[i] > parsedB.Numbers[i]
+		}
+		return parsedA.Numbers[i] < parsedB.Numbers[i]
+	}
+	if len(parsedA.Numbers) != len(parsedB.Numbers) {
+		return len(parsedA.Numbers) < len(parsedB.Numbers)
+	}
+	return a < b
+}
+
+// SortOrderDetails sorts order lines in pick path order of their product locations. Products must
+// be attached to the lines.
+func (p *PickPath) SortOrderDetails(details []OrderDetail) {
+	location := func(detail OrderDetail) string {
+		if detail.Product == nil {
+			return ""
+		}
+		return detail.Product.Location
+	}
+	sort.SliceStable(details, func(i, j int) bool {
+		return p.Less(location(details[i]), location(details[j]))
+	})
+}
@@ -7,12 +7,14 @@ import (
 )
 
 type PickedOrder struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	OrderID   uint           `gorm:"not null;index" json:"order_id"`
-	PickedBy  uint           `gorm:"not null;index" json:"picked_by"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	OrderID        uint           `gorm:"not null;index" json:"order_id"`
+	PickedBy       uint           `gorm:"not null;index" json:"picked_by"`
+	OverrideReason string         `json:"override_reason"`                  // Filled when picking was completed before every line was fully scanned
+	CancelledAt    *time.Time     `gorm:"default:null" json:"cancelled_at"` // Set when the order was cancelled after it was picked
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationship
 	Order        *Order `gorm:"foreignKey:OrderID" json:"order,omitempty"`
@@ -20,11 +22,13 @@ type PickedOrder struct {
 }
 
 type PickedOrderResponse struct {
-	ID        uint           `json:"id"`
-	OrderID   uint           `json:"order_id"`
-	PickedBy  uint           `json:"picked_by"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
+	ID             uint       `json:"id"`
+	OrderID        uint       `json:"order_id"`
+	PickedBy       uint       `json:"picked_by"`
+	OverrideReason string     `json:"override_reason"`
+	CancelledAt    *time.Time `json:"cancelled_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 
 	// Related data
 	Order        *OrderResponse `json:"order,omitempty"`
@@ -34,11 +38,13 @@ type PickedOrderResponse struct {
 // ToPickedOrderResponse converts PickedOrder model to PickedOrderResponse
 func (po *PickedOrder) ToPickedOrderResponse() PickedOrderResponse {
 	response := PickedOrderResponse{
-		ID:        po.ID,
-		OrderID:   po.OrderID,
-		PickedBy:  po.PickedBy,
-		CreatedAt: po.CreatedAt,
-		UpdatedAt: po.UpdatedAt,
+		ID:             po.ID,
+		OrderID:        po.OrderID,
+		PickedBy:       po.PickedBy,
+		OverrideReason: po.OverrideReason,
+		CancelledAt:    po.CancelledAt,
+		CreatedAt:      po.CreatedAt,
+		UpdatedAt:      po.UpdatedAt,
 	}
 
 	// Include order data if loaded
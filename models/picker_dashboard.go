@@ -0,0 +1,74 @@
+package models
+
+import (
+	"livo-backend/utilities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PickerDashboard holds the counts shown on the home screen of the picker app
+type PickerDashboard struct {
+	Date           string  `json:"date" example:"2025-01-31"`
+	Assigned       int64   `json:"assigned" example:"3"`            // Orders in hand, in picking process
+	Completed      int64   `json:"completed" example:"48"`          // Orders picked today, cancelled picks left out
+	Pending        int64   `json:"pending" example:"1"`             // Orders set back to pending picking today
+	AvgPickSeconds float64 `json:"avg_pick_seconds" example:"95.5"` // Average time from assignment to completed pick today
+	Streak         int64   `json:"streak" example:"12"`             // Latest picks in a row completed fully scanned, without a pending pick since
+	GeneratedAt    string  `json:"generated_at"`
+}
+
+// BuildPickerDashboard counts today's work of a picker
+func BuildPickerDashboard(db *gorm.DB, pickerID uint, now time.Time) (PickerDashboard, error) {
+	start, end := utilities.DayRange(now)
+
+	dashboard := PickerDashboard{
+		Date:        start.Format("2006-01-02"),
+		GeneratedAt: now.In(utilities.AppLocation()).Format("2006-01-02 15:04:05"),
+	}
+
+	if err := db.Model(&Order{}).
+		Where("picked_by = ? AND processing_status = ?", pickerID, "picking process").
+		Count(&dashboard.Assigned).Error; err != nil {
+		return dashboard, err
+	}
+
+	if err := db.Model(&Order{}).
+		Where("pending_by = ? AND pending_at >= ? AND pending_at < ?", pickerID, start, end).
+		Count(&dashboard.Pending).Error; err != nil {
+		return dashboard, err
+	}
+
+	var picks struct {
+		Completed      int64
+		AvgPickSeconds *float64
+	}
+	if err := db.Table("picked_orders").
+		Select(`COUNT(*) AS completed,
+			AVG(EXTRACT(EPOCH FROM picked_orders.created_at - orders.assigned_at))
+				FILTER (WHERE orders.assigned_at IS NOT NULL AND orders.assigned_at <= picked_orders.created_at) AS avg_pick_seconds`).
+		Joins("JOIN orders ON orders.id = picked_orders.order_id").
+		Where("picked_orders.picked_by = ? AND picked_orders.deleted_at IS NULL AND picked_orders.cancelled_at IS NULL", pickerID).
+		Where("picked_orders.created_at >= ? AND picked_orders.created_at < ?", start, end).
+		Scan(&picks).Error; err != nil {
+		return dashboard, err
+	}
+	dashboard.Completed = picks.Completed
+	if picks.AvgPickSeconds != nil {
+		dashboard.AvgPickSeconds = *picks.AvgPickSeconds
+	}
+
+	// The streak breaks on the latest pick completed with an override reason or the latest pending pick
+	if err := db.Raw(`
+		SELECT COUNT(*) FROM picked_orders
+		WHERE picked_by = ? AND deleted_at IS NULL AND COALESCE(override_reason, '') = ''
+			AND created_at > GREATEST(
+				COALESCE((SELECT MAX(created_at) FROM picked_orders WHERE picked_by = ? AND deleted_at IS NULL AND COALESCE(override_reason, '') <> ''), '-infinity'),
+				COALESCE((SELECT MAX(pending_at) FROM orders WHERE pending_by = ?), '-infinity')
+			)`, pickerID, pickerID, pickerID).
+		Scan(&dashboard.Streak).Error; err != nil {
+		return dashboard, err
+	}
+
+	return dashboard, nil
+}
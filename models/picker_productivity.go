@@ -0,0 +1,143 @@
+package models
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PickerProductivityPolicy configures how picker productivity weighs order assignment. Speed is the
+// average time from assignment to completed pick, errors are complains attributing the picking stage
+// to the picker, both over the last Days days. Pickers with fewer than MinPicks picks weigh 1.
+type PickerProductivityPolicy struct {
+	Days           int
+	MinPicks       int
+	SpeedWeight    int
+	AccuracyWeight int
+	UrgentWindow   time.Duration // Orders due within this window (and instant orders) are urgent
+}
+
+// Picker weights are kept in this range so one very fast or very slow week cannot lock a picker in
+// or out of urgent orders
+const (
+	minPickerWeight = 0.2
+	maxPickerWeight = 3
+)
+
+// PickerScore is the rolling productivity of a picker relative to the team, a weight above 1 means
+// faster and more accurate than average
+type PickerScore struct {
+	PickerID       uint    `json:"picker_id"`
+	FullName       string  `json:"full_name" example:"Jane Doe"`
+	Picks          int     `json:"picks" example:"240"`
+	AvgPickSeconds float64 `json:"avg_pick_seconds" example:"95.5"`
+	Errors         int     `json:"errors" example:"2"`
+	ErrorRate      float64 `json:"error_rate" example:"0.008"`
+	Weight         float64 `json:"weight" example:"1.2"`
+}
+
+// UrgentOrderCondition matches instant orders, orders escalated to urgent or late and orders due
+// within the urgent window, takes the end of the window as argument
+const UrgentOrderCondition = "(orders.instant = true OR orders.priority <> 'normal' OR COALESCE(orders.sla_due_at < ?, false))"
+
+// PickerScores computes the productivity of every picker who completed a pick in the policy window,
+// fastest first
+func PickerScores(db *gorm.DB, now time.Time) ([]PickerScore, error) {
+	policy := CurrentSettings().PickerProductivity
+	since := now.AddDate(0, 0, -policy.Days)
+
+	var scores []PickerScore
+	if err := db.Table("picked_orders").
+		Select(`picked_orders.picked_by AS picker_id, users.full_name AS full_name, COUNT(*) AS picks,
+			AVG(EXTRACT(EPOCH FROM picked_orders.created_at - orders.assigned_at)) AS avg_pick_seconds`).
+		Joins("JOIN orders ON orders.id = picked_orders.order_id").
+		Joins("JOIN users ON users.id = picked_orders.picked_by").
+		Where("picked_orders.deleted_at IS NULL AND picked_orders.created_at >= ?", since).
+		Where("orders.assigned_at IS NOT NULL AND orders.assigned_at <= picked_orders.created_at").
+		Group("picked_orders.picked_by, users.full_name").
+		Order("avg_pick_seconds ASC").
+		Scan(&scores).Error; err != nil {
+		return nil, fmt.Errorf("failed to load pick speeds: %w", err)
+	}
+
+	var faults []struct {
+		OperatorID uint
+		Errors     int
+	}
+	if err := db.Table("complain_user_details").
+		Select("complain_user_details.operator_id, COUNT(DISTINCT complain_user_details.complain_id) AS errors").
+		Joins("JOIN complains ON complains.id = complain_user_details.complain_id AND complains.deleted_at IS NULL").
+		Where("complain_user_details.deleted_at IS NULL AND complains.created_at >= ?", since).
+		Where("? = ANY(string_to_array(complain_user_details.stages, ','))", ComplainStagePicking).
+		Group("complain_user_details.operator_id").
+		Scan(&faults).Error; err != nil {
+		return nil, fmt.Errorf("failed to load pick errors: %w", err)
+	}
+	errorsByPicker := make(map[uint]int, len(faults))
+	for _, row := range faults {
+		errorsByPicker[row.OperatorID] = row.Errors
+	}
+
+	// Team averages over the pickers with enough picks to judge
+	var teamPicks, teamErrors int
+	var teamSeconds float64
+	for i := range scores {
+		score := &scores[i]
+		score.Errors = min(errorsByPicker[score.PickerID], score.Picks)
+		score.ErrorRate = float64(score.Errors) / float64(score.Picks)
+		if score.Picks >= policy.MinPicks {
+			teamPicks += score.Picks
+			teamErrors += score.Errors
+			teamSeconds += score.AvgPickSeconds * float64(score.Picks)
+		}
+	}
+
+	for i := range scores {
+		scores[i].Weight = pickerWeight(policy, scores[i], teamPicks, teamErrors, teamSeconds)
+	}
+
+	return scores, nil
+}
+
+func pickerWeight(policy PickerProductivityPolicy, score PickerScore, teamPicks, teamErrors int, teamSeconds float64) float64 {
+	totalWeight := policy.SpeedWeight + policy.AccuracyWeight
+	if score.Picks < policy.MinPicks || teamPicks == 0 || totalWeight <= 0 {
+		return 1
+	}
+
+	speed := 1.0
+	if score.AvgPickSeconds > 0 {
+		speed = teamSeconds / float64(teamPicks) / score.AvgPickSeconds
+	}
+
+	accuracy := 1.0
+	if teamErrorRate := float64(teamErrors) / float64(teamPicks); teamErrorRate < 1 {
+		accuracy = (1 - score.ErrorRate) / (1 - teamErrorRate)
+	}
+
+	weight := (float64(policy.SpeedWeight)*speed + float64(policy.AccuracyWeight)*accuracy) / float64(totalWeight)
+	return min(max(weight, minPickerWeight), maxPickerWeight)
+}
+
+// PickerWeight returns the productivity weight of one picker, 1 when there is not enough history
+func PickerWeight(db *gorm.DB, pickerID uint, now time.Time) (float64, error) {
+	scores, err := PickerScores(db, now)
+	if err != nil {
+		return 0, err
+	}
+	for _, score := range scores {
+		if score.PickerID == pickerID {
+			return score.Weight, nil
+		}
+	}
+	return 1, nil
+}
+
+// ServeUrgentFirst decides whether a picker grabbing an order gets urgent orders first. Pickers at or
+// above the team average always do, the others with a probability equal to their weight, so their
+// share of urgent orders is proportional to their productivity.
+func ServeUrgentFirst(weight float64) bool {
+	return weight >= 1 || rand.Float64() < weight
+}
@@ -0,0 +1,224 @@
+package models
+
+import (
+	"livo-backend/utilities"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// pickupSearchDays is how many days ahead outbounds look for an open pickup slot when today's
+// slots are full or gone
+const pickupSearchDays = 7
+
+// Pickup slot states on a given day
+const (
+	PickupSlotStatusOpen     = "open"
+	PickupSlotStatusFull     = "full"
+	PickupSlotStatusDeparted = "departed"
+)
+
+// PickupSlot is a daily courier pickup: the van of the expedition comes every day at the pickup time
+// and takes at most capacity parcels. Outbounds are assigned to the next slot with room left.
+type PickupSlot struct {
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	ExpeditionID uint           `gorm:"not null;index" json:"expedition_id" example:"1"`
+	PickupTime   string         `gorm:"not null" json:"pickup_time" example:"15:00"` // "HH:MM", AppLocation time
+	Capacity     int            `gorm:"not null" json:"capacity" example:"300"`
+	Active       bool           `gorm:"not null;default:true" json:"active" example:"true"`
+	Notes        string         `json:"notes" example:"Large van on weekdays"`
+	CreatedBy    uint           `gorm:"not null" json:"created_by"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationship
+	Expedition *Expedition `gorm:"foreignKey:ExpeditionID" json:"expedition,omitempty"`
+	Creator    *User       `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
+}
+
+type PickupSlotResponse struct {
+	ID           uint      `json:"id"`
+	ExpeditionID uint      `json:"expedition_id"`
+	Expedition   string    `json:"expedition"`
+	PickupTime   string    `json:"pickup_time"`
+	Capacity     int       `json:"capacity"`
+	Active       bool      `json:"active"`
+	Notes        string    `json:"notes"`
+	CreatedBy    string    `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ToPickupSlotResponse converts PickupSlot model to PickupSlotResponse
+func (s *PickupSlot) ToPickupSlotResponse() PickupSlotResponse {
+	response := PickupSlotResponse{
+		ID:           s.ID,
+		ExpeditionID: s.ExpeditionID,
+		Expedition:   "-",
+		PickupTime:   s.PickupTime,
+		Capacity:     s.Capacity,
+		Active:       s.Active,
+		Notes:        s.Notes,
+		CreatedBy:    "-",
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
+	}
+	if s.Expedition != nil {
+		response.Expedition = s.Expedition.Name
+	}
+	if s.Creator != nil {
+		response.CreatedBy = s.Creator.Username
+	}
+	return response
+}
+
+// PickupAt returns when the slot is picked up on the day of date
+func (s *PickupSlot) PickupAt(date time.Time) time.Time {
+	minutes, err := ParsePickupCutoff(s.PickupTime)
+	if err != nil || minutes < 0 {
+		minutes = 0
+	}
+	start, _ := utilities.DayRange(date)
+	return start.Add(time.Duration(minutes) * time.Minute)
+}
+
+// AssignPickupSlot assigns outbound to the earliest pickup of its expedition, from now on, that has
+// room left. The slots of the expedition are locked until the transaction ends so concurrent scans
+// cannot overfill a van. Outbounds of expeditions without slots, or with every slot of the coming
+// days full, stay unassigned.
+func AssignPickupSlot(tx *gorm.DB, outbound *Outbound, now time.Time) error {
+	// A failed attempt may have assigned a slot before rolling back
+	outbound.PickupSlotID = nil
+	outbound.PickupAt = nil
+
+	var slots []PickupSlot
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "pickup_slots"}}).
+		Joins("JOIN expeditions ON expeditions.id = pickup_slots.expedition_id AND expeditions.deleted_at IS NULL").
+		Where("expeditions.name = ? AND pickup_slots.active = ?", outbound.Expedition, true).
+		Order("pickup_slots.pickup_time ASC").
+		Find(&slots).Error; err != nil {
+		return err
+	}
+	if len(slots) == 0 {
+		return nil
+	}
+
+	for day := 0; day < pickupSearchDays; day++ {
+		date := now.AddDate(0, 0, day)
+		for i := range slots {
+			pickupAt := slots[i].PickupAt(date)
+			if !pickupAt.After(now) {
+				continue
+			}
+
+			var assigned int64
+			if err := tx.Model(&Outbound{}).
+				Where("pickup_slot_id = ? AND pickup_at = ?", slots[i].ID, pickupAt).
+				Count(&assigned).Error; err != nil {
+				return err
+			}
+			if int(assigned) >= slots[i].Capacity {
+				continue
+			}
+
+			outbound.PickupSlotID = &slots[i].ID
+			outbound.PickupAt = &pickupAt
+			return nil
+		}
+	}
+	return nil
+}
+
+// PickupSlotFill is the load of a pickup slot on one day
+type PickupSlotFill struct {
+	SlotID       uint      `json:"slot_id"`
+	ExpeditionID uint      `json:"expedition_id"`
+	Expedition   string    `json:"expedition"`
+	PickupTime   string    `json:"pickup_time"`
+	PickupAt     time.Time `json:"pickup_at"`
+	Capacity     int       `json:"capacity"`
+	Assigned     int       `json:"assigned"`
+	Remaining    int       `json:"remaining"`
+	FillRate     float64   `json:"fill_rate"` // Percentage of the capacity assigned
+	Status       string    `json:"status"`    // open, full or departed
+}
+
+// PickupUnassigned counts the outbounds of the day that got no pickup slot, per expedition
+type PickupUnassigned struct {
+	Expedition string `json:"expedition"`
+	Count      int    `json:"count"`
+}
+
+// GetPickupFill returns the load of every active pickup slot on the day of date, in pickup order,
+// and the outbounds created that day without a slot
+func GetPickupFill(db *gorm.DB, date time.Time, now time.Time) ([]PickupSlotFill, []PickupUnassigned, error) {
+	var slots []PickupSlot
+	if err := db.Preload("Expedition").
+		Joins("JOIN expeditions ON expeditions.id = pickup_slots.expedition_id AND expeditions.deleted_at IS NULL").
+		Where("pickup_slots.active = ?", true).
+		Order("pickup_slots.pickup_time ASC, expeditions.name ASC").
+		Find(&slots).Error; err != nil {
+		return nil, nil, err
+	}
+
+	start, end := utilities.DayRange(date)
+
+	var counts []struct {
+		PickupSlotID uint
+		Count        int
+	}
+	if err := db.Model(&Outbound{}).
+		Select("pickup_slot_id, COUNT(*) AS count").
+		Where("pickup_slot_id IS NOT NULL AND pickup_at >= ? AND pickup_at < ?", start, end).
+		Group("pickup_slot_id").
+		Scan(&counts).Error; err != nil {
+		return nil, nil, err
+	}
+	assignedBySlot := make(map[uint]int, len(counts))
+	for _, count := range counts {
+		assignedBySlot[count.PickupSlotID] = count.Count
+	}
+
+	fills := make([]PickupSlotFill, len(slots))
+	for i := range slots {
+		fill := PickupSlotFill{
+			SlotID:       slots[i].ID,
+			ExpeditionID: slots[i].ExpeditionID,
+			Expedition:   "-",
+			PickupTime:   slots[i].PickupTime,
+			PickupAt:     slots[i].PickupAt(date),
+			Capacity:     slots[i].Capacity,
+			Assigned:     assignedBySlot[slots[i].ID],
+			Status:       PickupSlotStatusOpen,
+		}
+		if slots[i].Expedition != nil {
+			fill.Expedition = slots[i].Expedition.Name
+		}
+		fill.Remaining = max(fill.Capacity-fill.Assigned, 0)
+		if fill.Capacity > 0 {
+			fill.FillRate = float64(fill.Assigned) * 100 / float64(fill.Capacity)
+		}
+
+		switch {
+		case !fill.PickupAt.After(now):
+			fill.Status = PickupSlotStatusDeparted
+		case fill.Assigned >= fill.Capacity:
+			fill.Status = PickupSlotStatusFull
+		}
+		fills[i] = fill
+	}
+
+	unassigned := []PickupUnassigned{}
+	if err := db.Model(&Outbound{}).
+		Select("expedition, COUNT(*) AS count").
+		Where("pickup_slot_id IS NULL AND created_at >= ? AND created_at < ?", start, end).
+		Group("expedition").
+		Order("expedition ASC").
+		Scan(&unassigned).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return fills, unassigned, nil
+}
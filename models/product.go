@@ -7,41 +7,67 @@ import (
 )
 
 type Product struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Sku       string         `gorm:"unique;not null" json:"sku" example:"LY-GLIPOW-128-HL705-30G"`
-	Name      string         `gorm:"not null" json:"name" example:"Glitter Serbuk 3 Gram Powder Gliter Kelap Kelip 3 gr Bubuk Berkilau Blink Sparkle Kerajinan Tangan Craft"`
-	Image     string         `json:"image" example:"https://cf.shopee.co.id/file/id-11134207-7rbk5-maibgarivyxe75"`
-	Variant   string         `json:"variant" example:"Biru Tua"`
-	Location  string         `json:"location" example:"Rak A1-3"`
-	Barcode   string         `json:"barcode" example:"8999999000012"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	Sku          string         `gorm:"unique;not null" json:"sku" example:"LY-GLIPOW-128-HL705-30G"`
+	Name         string         `gorm:"not null" json:"name" example:"Glitter Serbuk 3 Gram Powder Gliter Kelap Kelip 3 gr Bubuk Berkilau Blink Sparkle Kerajinan Tangan Craft"`
+	Image        string         `json:"image" example:"https://cf.shopee.co.id/file/id-11134207-7rbk5-maibgarivyxe75"`
+	Thumbnail    string         `json:"thumbnail" example:"/uploads/products/12/9f86d081884c7d65_thumb.jpg"` // Set when the image was uploaded
+	ImageKey     string         `json:"-"`                                                                   // Storage keys of an uploaded image and its thumbnail
+	ThumbnailKey string         `json:"-"`
+	Variant      string         `json:"variant" example:"Biru Tua"`
+	Location     string         `json:"location" example:"Rak A1-3"`
+	Barcode      string         `json:"barcode" example:"8999999000012"`
+	Category     string         `gorm:"index" json:"category" example:"Craft Supplies"` // Groups similar products for box suggestions
+	Length       float64        `json:"length" example:"10"`                            // Packed dimensions in cm, 0 when unknown
+	Width        float64        `json:"width" example:"5"`
+	Height       float64        `json:"height" example:"2"`
+	Weight       int            `json:"weight" example:"30"`        // Grams
+	Cost         int            `json:"cost" example:"12500"`       // Unit purchase cost in rupiah, 0 when unknown
+	ScrapValue   int            `json:"scrap_value" example:"2000"` // Unit salvage value in rupiah when scrapped
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 type ProductResponse struct {
-	ID       uint      `json:"id"`
-	Sku      string    `json:"sku"`
-	Name     string    `json:"name"`
-	Image    string    `json:"image"`
-	Variant  string    `json:"variant"`
-	Location string    `json:"location"`
-	Barcode  string    `json:"barcode"`
-	Created  time.Time `json:"created_at"`
-	Updated  time.Time `json:"updated_at"`
+	ID         uint      `json:"id"`
+	Sku        string    `json:"sku"`
+	Name       string    `json:"name"`
+	Image      string    `json:"image"`
+	Thumbnail  string    `json:"thumbnail"`
+	Variant    string    `json:"variant"`
+	Location   string    `json:"location"`
+	Barcode    string    `json:"barcode"`
+	Category   string    `json:"category"`
+	Length     float64   `json:"length"`
+	Width      float64   `json:"width"`
+	Height     float64   `json:"height"`
+	Weight     int       `json:"weight"`
+	Cost       int       `json:"cost"`
+	ScrapValue int       `json:"scrap_value"`
+	Created    time.Time `json:"created_at"`
+	Updated    time.Time `json:"updated_at"`
 }
 
 // ToProductResponse converts Product model to ProductResponse
 func (p *Product) ToProductResponse() ProductResponse {
 	return ProductResponse{
-		ID:       p.ID,
-		Sku:      p.Sku,
-		Name:     p.Name,
-		Image:    p.Image,
-		Variant:  p.Variant,
-		Location: p.Location,
-		Barcode:  p.Barcode,
-		Created:  p.CreatedAt,
-		Updated:  p.UpdatedAt,
+		ID:         p.ID,
+		Sku:        p.Sku,
+		Name:       p.Name,
+		Image:      p.Image,
+		Thumbnail:  p.Thumbnail,
+		Variant:    p.Variant,
+		Location:   p.Location,
+		Barcode:    p.Barcode,
+		Category:   p.Category,
+		Length:     p.Length,
+		Width:      p.Width,
+		Height:     p.Height,
+		Weight:     p.Weight,
+		Cost:       p.Cost,
+		ScrapValue: p.ScrapValue,
+		Created:    p.CreatedAt,
+		Updated:    p.UpdatedAt,
 	}
 }
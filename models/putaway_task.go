@@ -0,0 +1,162 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Putaway task statuses
+const (
+	PutawayStatusPending   = "pending"
+	PutawayStatusCompleted = "completed"
+)
+
+// PutawayTask tells warehouse staff where to put back the items of one return detail once the
+// return is approved for restock
+type PutawayTask struct {
+	ID                uint           `gorm:"primaryKey" json:"id"`
+	ReturnID          uint           `gorm:"not null;index" json:"return_id"`
+	ReturnDetailID    uint           `gorm:"not null;uniqueIndex" json:"return_detail_id"`
+	ProductID         uint           `gorm:"not null;index" json:"product_id"`
+	Quantity          int            `gorm:"not null" json:"quantity" example:"2"`
+	SuggestedLocation string         `json:"suggested_location" example:"Rak A1-3"` // Product location when the task was generated
+	Location          string         `json:"location" example:"Rak A1-4"`           // Location scanned on completion
+	Status            string         `gorm:"not null;default:'pending';index" json:"status" example:"pending"`
+	CompletedBy       *uint          `json:"completed_by"`
+	CompletedAt       *time.Time     `json:"completed_at"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationship
+	Return    *Return  `gorm:"foreignKey:ReturnID" json:"return,omitempty"`
+	Product   *Product `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+	Completer *User    `gorm:"foreignKey:CompletedBy" json:"completer,omitempty"`
+}
+
+// ProductLocationStat tracks how many units of a product have been put away at a location
+type ProductLocationStat struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ProductID     uint      `gorm:"not null;uniqueIndex:idx_product_location" json:"product_id"`
+	Location      string    `gorm:"not null;uniqueIndex:idx_product_location" json:"location" example:"Rak A1-3"`
+	Quantity      int       `gorm:"not null;default:0" json:"quantity" example:"12"`
+	PutawayCount  int       `gorm:"not null;default:0" json:"putaway_count" example:"5"`
+	LastPutawayAt time.Time `json:"last_putaway_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type PutawayTaskResponse struct {
+	ID                uint             `json:"id"`
+	ReturnID          uint             `json:"return_id"`
+	ReturnTracking    string           `json:"return_tracking"`
+	ProductID         uint             `json:"product_id"`
+	Quantity          int              `json:"quantity"`
+	SuggestedLocation string           `json:"suggested_location"`
+	Location          string           `json:"location"`
+	Status            string           `json:"status"`
+	CompletedBy       string           `json:"completed_by"`
+	CompletedAt       string           `json:"completed_at"`
+	CreatedAt         time.Time        `json:"created_at"`
+	Product           *ProductResponse `json:"product,omitempty"`
+}
+
+// ToPutawayTaskResponse converts PutawayTask model to PutawayTaskResponse
+func (t *PutawayTask) ToPutawayTaskResponse() PutawayTaskResponse {
+	response := PutawayTaskResponse{
+		ID:                t.ID,
+		ReturnID:          t.ReturnID,
+		ReturnTracking:    "-",
+		ProductID:         t.ProductID,
+		Quantity:          t.Quantity,
+		SuggestedLocation: t.SuggestedLocation,
+		Location:          "-",
+		Status:            t.Status,
+		CompletedBy:       "-",
+		CompletedAt:       "-",
+		CreatedAt:         t.CreatedAt,
+	}
+
+	if t.Return != nil {
+		response.ReturnTracking = t.Return.NewTracking
+	}
+	if t.Location != "" {
+		response.Location = t.Location
+	}
+	if t.Completer != nil {
+		response.CompletedBy = t.Completer.FullName
+	}
+	if t.CompletedAt != nil {
+		response.CompletedAt = t.CompletedAt.Format("2006-01-02 15:04:05")
+	}
+	if t.Product != nil {
+		productResponse := t.Product.ToProductResponse()
+		response.Product = &productResponse
+	}
+
+	return response
+}
+
+// GeneratePutawayTasks creates a pending putaway task for every detail of an approved return that
+// has none yet. Returns are approved for restock once they get a return number; scrapped returns
+// are not put away.
+func GeneratePutawayTasks(tx *gorm.DB, ret *Return) error {
+	if ret.ReturnNumber == "" || ret.ScrapNumber != "" {
+		return nil
+	}
+
+	var details []ReturnDetail
+	if err := tx.Preload("Product").Where("return_id = ?", ret.ID).Find(&details).Error; err != nil {
+		return fmt.Errorf("failed to load return details: %w", err)
+	}
+
+	for _, detail := range details {
+		task := PutawayTask{
+			ReturnID:          ret.ID,
+			ReturnDetailID:    detail.ID,
+			ProductID:         detail.ProductID,
+			Quantity:          detail.Quantity,
+			SuggestedLocation: detail.Product.Location,
+			Status:            PutawayStatusPending,
+		}
+		if err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "return_detail_id"}}, DoNothing: true}).Create(&task).Error; err != nil {
+			return fmt.Errorf("failed to create putaway task: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// RecordPutaway adds quantity units of a product to its stats at location and sets the location
+// as the product's location when it has none yet
+func RecordPutaway(tx *gorm.DB, productID uint, location string, quantity int, at time.Time) error {
+	stat := ProductLocationStat{
+		ProductID:     productID,
+		Location:      location,
+		Quantity:      quantity,
+		PutawayCount:  1,
+		LastPutawayAt: at,
+	}
+	err := tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "product_id"}, {Name: "location"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"quantity":        gorm.Expr("product_location_stats.quantity + ?", quantity),
+			"putaway_count":   gorm.Expr("product_location_stats.putaway_count + 1"),
+			"last_putaway_at": at,
+			"updated_at":      at,
+		}),
+	}).Create(&stat).Error
+	if err != nil {
+		return fmt.Errorf("failed to update product location stats: %w", err)
+	}
+
+	if err := tx.Model(&Product{}).Where("id = ? AND (location IS NULL OR location = '')", productID).
+		Update("location", location).Error; err != nil {
+		return fmt.Errorf("failed to update product location: %w", err)
+	}
+
+	return nil
+}
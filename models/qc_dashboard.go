@@ -0,0 +1,107 @@
+package models
+
+import (
+	"livo-backend/utilities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// QcOperatorCount is the QC work one operator did today
+type QcOperatorCount struct {
+	UserID   uint   `json:"user_id" example:"7"`
+	FullName string `json:"full_name" example:"Jane Doe"`
+	QcRibbon int64  `json:"qc_ribbon" example:"85"`
+	QcOnline int64  `json:"qc_online" example:"40"`
+	Total    int64  `json:"total" example:"125"`
+}
+
+// QcDashboard holds the counts shown on the QC station screens
+type QcDashboard struct {
+	Date            string            `json:"date" example:"2025-01-31"`
+	QcRibbon        int64             `json:"qc_ribbon" example:"310"`
+	QcOnline        int64             `json:"qc_online" example:"150"`
+	Operators       []QcOperatorCount `json:"operators"`
+	AvgWaitSeconds  float64           `json:"avg_wait_seconds" example:"840.5"` // Average time from picking complete to QC for today's QC
+	Backlog         int64             `json:"backlog" example:"42"`             // Orders picked and waiting for QC
+	OldestBacklogAt string            `json:"oldest_backlog_at"`                // Picking complete time of the longest waiting order
+	GeneratedAt     string            `json:"generated_at"`
+}
+
+// qcBacklogStatuses are the statuses of picked orders that did not reach QC yet
+var qcBacklogStatuses = []string{"picking complete", "picking completed"}
+
+// BuildQcDashboard counts today's QC per operator, the wait between picking and QC, and the orders
+// still waiting for QC. Three aggregate queries cover it, so screens can poll it often.
+func BuildQcDashboard(db *gorm.DB, now time.Time) (QcDashboard, error) {
+	start, end := utilities.DayRange(now)
+
+	dashboard := QcDashboard{
+		Date:            start.Format("2006-01-02"),
+		Operators:       []QcOperatorCount{},
+		OldestBacklogAt: "-",
+		GeneratedAt:     now.In(utilities.AppLocation()).Format("2006-01-02 15:04:05"),
+	}
+
+	// Per operator counts of both QC stations in one pass
+	if err := db.Raw(`
+		SELECT qc.qc_by AS user_id, COALESCE(users.full_name, '-') AS full_name,
+			COUNT(*) FILTER (WHERE qc.station = 'ribbon') AS qc_ribbon,
+			COUNT(*) FILTER (WHERE qc.station = 'online') AS qc_online,
+			COUNT(*) AS total
+		FROM (
+			SELECT qc_by, 'ribbon' AS station FROM qc_ribbons WHERE created_at >= ? AND created_at < ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT qc_by, 'online' AS station FROM qc_onlines WHERE created_at >= ? AND created_at < ? AND deleted_at IS NULL
+		) qc
+		LEFT JOIN users ON users.id = qc.qc_by
+		WHERE qc.qc_by IS NOT NULL
+		GROUP BY qc.qc_by, users.full_name
+		ORDER BY total DESC`, start, end, start, end).
+		Scan(&dashboard.Operators).Error; err != nil {
+		return dashboard, err
+	}
+
+	var totals struct {
+		QcRibbon       int64
+		QcOnline       int64
+		AvgWaitSeconds *float64
+	}
+	if err := db.Raw(`
+		SELECT
+			COUNT(*) FILTER (WHERE qc.station = 'ribbon') AS qc_ribbon,
+			COUNT(*) FILTER (WHERE qc.station = 'online') AS qc_online,
+			AVG(EXTRACT(EPOCH FROM (qc.created_at - orders.picked_at))) FILTER (WHERE orders.picked_at IS NOT NULL AND orders.picked_at <= qc.created_at) AS avg_wait_seconds
+		FROM (
+			SELECT tracking, created_at, 'ribbon' AS station FROM qc_ribbons WHERE created_at >= ? AND created_at < ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT tracking, created_at, 'online' AS station FROM qc_onlines WHERE created_at >= ? AND created_at < ? AND deleted_at IS NULL
+		) qc
+		LEFT JOIN orders ON orders.tracking = qc.tracking AND orders.deleted_at IS NULL`, start, end, start, end).
+		Scan(&totals).Error; err != nil {
+		return dashboard, err
+	}
+	dashboard.QcRibbon = totals.QcRibbon
+	dashboard.QcOnline = totals.QcOnline
+	if totals.AvgWaitSeconds != nil {
+		dashboard.AvgWaitSeconds = *totals.AvgWaitSeconds
+	}
+
+	var backlog struct {
+		Backlog  int64
+		OldestAt *time.Time
+	}
+	if err := db.Model(&Order{}).
+		Select("COUNT(*) AS backlog, MIN(picked_at) AS oldest_at").
+		Where("processing_status IN ?", qcBacklogStatuses).
+		Where("event_status IS NULL OR event_status <> ?", "cancelled").
+		Scan(&backlog).Error; err != nil {
+		return dashboard, err
+	}
+	dashboard.Backlog = backlog.Backlog
+	if backlog.OldestAt != nil {
+		dashboard.OldestBacklogAt = backlog.OldestAt.Format("2006-01-02 15:04:05")
+	}
+
+	return dashboard, nil
+}
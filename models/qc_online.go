@@ -7,13 +7,17 @@ import (
 )
 
 type QcOnline struct {
-	ID         uint           `gorm:"primaryKey" json:"id"`
-	Tracking   string         `gorm:"unique;not null" json:"tracking" example:"QC1234567890"`
-	QcBy       *uint          `gorm:"default:null" json:"qc_by"`
-	Complained bool           `gorm:"default:false" json:"complained"`
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                 uint           `gorm:"primaryKey" json:"id"`
+	Tracking           string         `gorm:"not null;uniqueIndex:idx_qc_online_parcel" json:"tracking" example:"QC1234567890"`
+	ParcelNumber       int            `gorm:"not null;default:1;uniqueIndex:idx_qc_online_parcel" json:"parcel_number" example:"1"` // Parcel of a multi-parcel order, from 1
+	QcBy               *uint          `gorm:"default:null" json:"qc_by"`
+	Complained         bool           `gorm:"default:false" json:"complained"`
+	SuggestedBoxID     *uint          `gorm:"default:null" json:"suggested_box_id"`    // Top box suggestion shown when the QC was created
+	SuggestionAccepted *bool          `gorm:"default:null" json:"suggestion_accepted"` // Whether the chosen boxes included it
+	CancelledAt        *time.Time     `gorm:"default:null" json:"cancelled_at"`        // Set when the order was cancelled after QC
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationship
 	QcOnlineDetails []QcOnlineDetail `gorm:"foreignKey:QcOnlineID" json:"details"`
@@ -47,17 +51,23 @@ type QcOnlineDetailResponse struct {
 }
 
 type QcOnlineResponse struct {
-	ID         uint      `json:"id"`
-	Tracking   string    `json:"tracking"`
-	QcBy       *uint     `json:"qc_by"`
-	Complained bool      `json:"complained"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID                 uint       `json:"id"`
+	Tracking           string     `json:"tracking"`
+	ParcelNumber       int        `json:"parcel_number"`
+	QcBy               *uint      `json:"qc_by"`
+	Complained         bool       `json:"complained"`
+	SuggestedBoxID     *uint      `json:"suggested_box_id"`
+	SuggestionAccepted *bool      `json:"suggestion_accepted"`
+	CancelledAt        *time.Time `json:"cancelled_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
 
 	// Related data
 	QcOnlineDetails []QcOnlineDetailResponse `json:"qc_online_details"`
 	Order           *OrderResponse           `json:"order,omitempty"`
 	QcOperator      *UserResponse            `json:"qc_operator,omitempty"`
+	BoxSuggestions  []BoxSuggestion          `json:"box_suggestions,omitempty"` // Only set when the QC is created
+	Parcels         *QcParcelProgress        `json:"parcels,omitempty"`         // Only set when the QC is created
 }
 
 // ToQcOnlineResponse converts QcOnline to QcOnlineResponse
@@ -83,13 +93,17 @@ func (qco *QcOnline) ToQcOnlineResponse() QcOnlineResponse {
 	}
 
 	response := QcOnlineResponse{
-		ID:              qco.ID,
-		Tracking:        qco.Tracking,
-		QcBy:            qco.QcBy,
-		Complained:      qco.Complained,
-		CreatedAt:       qco.CreatedAt,
-		UpdatedAt:       qco.UpdatedAt,
-		QcOnlineDetails: detailResponses,
+		ID:                 qco.ID,
+		Tracking:           qco.Tracking,
+		ParcelNumber:       qco.ParcelNumber,
+		QcBy:               qco.QcBy,
+		Complained:         qco.Complained,
+		SuggestedBoxID:     qco.SuggestedBoxID,
+		SuggestionAccepted: qco.SuggestionAccepted,
+		CancelledAt:        qco.CancelledAt,
+		CreatedAt:          qco.CreatedAt,
+		UpdatedAt:          qco.UpdatedAt,
+		QcOnlineDetails:    detailResponses,
 	}
 
 	// Include order data if loaded
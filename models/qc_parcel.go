@@ -0,0 +1,67 @@
+package models
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// QcParcelProgress is how far QC of a multi-parcel order got at one QC station
+type QcParcelProgress struct {
+	ParcelCount int   `json:"parcel_count" example:"2"` // Parcels declared for the order
+	Parcels     []int `json:"parcels"`                  // Parcel numbers QC'd, ascending
+	Complete    bool  `json:"complete"`                 // Every declared parcel is QC'd
+}
+
+// QcParcelNumbers returns the parcel numbers of tracking QC'd at the station of model, QcRibbon or
+// QcOnline, ascending
+func QcParcelNumbers(db *gorm.DB, model interface{}, tracking string) ([]int, error) {
+	parcels := []int{}
+	err := db.Model(model).
+		Where("tracking = ?", tracking).
+		Distinct("parcel_number").
+		Order("parcel_number ASC").
+		Pluck("parcel_number", &parcels).Error
+	return parcels, err
+}
+
+// NewQcParcelProgress returns the progress of an order declaring parcelCount parcels once parcels
+// are QC'd
+func NewQcParcelProgress(parcelCount int, parcels []int) QcParcelProgress {
+	if parcelCount < 1 {
+		parcelCount = 1
+	}
+	done := 0
+	for _, parcel := range parcels {
+		if parcel >= 1 && parcel <= parcelCount {
+			done++
+		}
+	}
+	return QcParcelProgress{ParcelCount: parcelCount, Parcels: parcels, Complete: done >= parcelCount}
+}
+
+// ProcessingStatus is the processing status of the order: qc complete once every parcel is QC'd,
+// qc process while parcels are left
+func (p QcParcelProgress) ProcessingStatus() string {
+	if p.Complete {
+		return "qc complete"
+	}
+	return "qc process"
+}
+
+// ValidateQcParcel checks parcelNumber can be QC'd for an order declaring parcelCount parcels, of
+// which parcels are QC'd already. The declared count cannot drop below a parcel already QC'd.
+func ValidateQcParcel(parcelNumber, parcelCount int, parcels []int) error {
+	if parcelCount < 1 {
+		return fmt.Errorf("parcel_count must be at least 1")
+	}
+	if parcelNumber < 1 || parcelNumber > parcelCount {
+		return fmt.Errorf("parcel_number must be between 1 and %d, the parcels declared for the order", parcelCount)
+	}
+	for _, parcel := range parcels {
+		if parcel > parcelCount {
+			return fmt.Errorf("parcel %d is QC'd already, parcel_count cannot be less than %d", parcel, parcel)
+		}
+	}
+	return nil
+}
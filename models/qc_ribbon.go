@@ -7,13 +7,17 @@ import (
 )
 
 type QcRibbon struct {
-	ID         uint           `gorm:"primaryKey" json:"id"`
-	Tracking   string         `gorm:"unique;not null" json:"tracking" example:"QC1234567890"`
-	QcBy       *uint          `gorm:"default:null" json:"qc_by"`
-	Complained bool           `gorm:"default:false" json:"complained"`
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                 uint           `gorm:"primaryKey" json:"id"`
+	Tracking           string         `gorm:"not null;uniqueIndex:idx_qc_ribbon_parcel" json:"tracking" example:"QC1234567890"`
+	ParcelNumber       int            `gorm:"not null;default:1;uniqueIndex:idx_qc_ribbon_parcel" json:"parcel_number" example:"1"` // Parcel of a multi-parcel order, from 1
+	QcBy               *uint          `gorm:"default:null" json:"qc_by"`
+	Complained         bool           `gorm:"default:false" json:"complained"`
+	SuggestedBoxID     *uint          `gorm:"default:null" json:"suggested_box_id"`    // Top box suggestion shown when the QC was created
+	SuggestionAccepted *bool          `gorm:"default:null" json:"suggestion_accepted"` // Whether the chosen boxes included it
+	CancelledAt        *time.Time     `gorm:"default:null" json:"cancelled_at"`        // Set when the order was cancelled after QC
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationship
 	QcRibbonDetails []QcRibbonDetail `gorm:"foreignKey:QcRibbonID" json:"details"`
@@ -47,17 +51,23 @@ type QcRibbonDetailResponse struct {
 }
 
 type QcRibbonResponse struct {
-	ID         uint      `json:"id"`
-	Tracking   string    `json:"tracking"`
-	QcBy       *uint     `json:"qc_by"`
-	Complained bool      `json:"complained"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID                 uint       `json:"id"`
+	Tracking           string     `json:"tracking"`
+	ParcelNumber       int        `json:"parcel_number"`
+	QcBy               *uint      `json:"qc_by"`
+	Complained         bool       `json:"complained"`
+	SuggestedBoxID     *uint      `json:"suggested_box_id"`
+	SuggestionAccepted *bool      `json:"suggestion_accepted"`
+	CancelledAt        *time.Time `json:"cancelled_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
 
 	// Related data
 	QcRibbonDetails []QcRibbonDetailResponse `json:"qc_ribbon_details"`
 	Order           *OrderResponse           `json:"order,omitempty"`
 	QcOperator      *UserResponse            `json:"qc_operator,omitempty"`
+	BoxSuggestions  []BoxSuggestion          `json:"box_suggestions,omitempty"` // Only set when the QC is created
+	Parcels         *QcParcelProgress        `json:"parcels,omitempty"`         // Only set when the QC is created
 }
 
 // ToQcRibbonResponse converts QcRibbon to QcRibbonResponse
@@ -83,13 +93,17 @@ func (qcr *QcRibbon) ToQcRibbonResponse() QcRibbonResponse {
 	}
 
 	response := QcRibbonResponse{
-		ID:              qcr.ID,
-		Tracking:        qcr.Tracking,
-		QcBy:            qcr.QcBy,
-		Complained:      qcr.Complained,
-		CreatedAt:       qcr.CreatedAt,
-		UpdatedAt:       qcr.UpdatedAt,
-		QcRibbonDetails: detailResponses,
+		ID:                 qcr.ID,
+		Tracking:           qcr.Tracking,
+		ParcelNumber:       qcr.ParcelNumber,
+		QcBy:               qcr.QcBy,
+		Complained:         qcr.Complained,
+		SuggestedBoxID:     qcr.SuggestedBoxID,
+		SuggestionAccepted: qcr.SuggestionAccepted,
+		CancelledAt:        qcr.CancelledAt,
+		CreatedAt:          qcr.CreatedAt,
+		UpdatedAt:          qcr.UpdatedAt,
+		QcRibbonDetails:    detailResponses,
 	}
 
 	// Include order data if loaded
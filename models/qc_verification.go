@@ -0,0 +1,148 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// QcVerification is a count of the contents of a parcel at QC, every SKU confirmed against the order
+// details. Lines whose count does not match are kept as discrepancies: a surplus goes to lost and
+// found, a shortage raises a complain draft.
+type QcVerification struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	Tracking   string         `gorm:"not null;index" json:"tracking" example:"JNE1234567890"`
+	OrderID    uint           `gorm:"not null;index" json:"order_id"`
+	VerifiedBy uint           `gorm:"not null" json:"verified_by"`
+	Matched    bool           `gorm:"not null;default:false" json:"matched"` // Every SKU counted as ordered
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationship
+	Discrepancies []QcDiscrepancy `gorm:"foreignKey:QcVerificationID" json:"discrepancies"`
+	Verifier      *User           `gorm:"foreignKey:VerifiedBy" json:"verifier,omitempty"`
+}
+
+// QcDiscrepancy is a SKU of a QC verification counted differently than ordered, with the lost and
+// found record or complain its difference was handed to
+type QcDiscrepancy struct {
+	ID                uint           `gorm:"primaryKey" json:"id"`
+	QcVerificationID  uint           `gorm:"not null;index" json:"qc_verification_id"`
+	Sku               string         `gorm:"not null" json:"sku" example:"LY-GLIPOW-128-HL705-30G"`
+	ExpectedQuantity  int            `gorm:"not null" json:"expected_quantity" example:"2"`
+	ConfirmedQuantity int            `gorm:"not null" json:"confirmed_quantity" example:"1"`
+	LostFoundID       *uint          `gorm:"default:null" json:"lost_found_id"` // Surplus moved to lost and found
+	ComplainID        *uint          `gorm:"default:null" json:"complain_id"`   // Complain the shortage was raised on
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// Difference is the quantity confirmed minus the quantity ordered, positive for a surplus
+func (d *QcDiscrepancy) Difference() int {
+	return d.ConfirmedQuantity - d.ExpectedQuantity
+}
+
+// Response structures
+type QcDiscrepancyResponse struct {
+	Sku               string `json:"sku"`
+	ExpectedQuantity  int    `json:"expected_quantity"`
+	ConfirmedQuantity int    `json:"confirmed_quantity"`
+	Difference        int    `json:"difference" example:"-1"`
+	Resolution        string `json:"resolution" example:"complain"` // lost_found, complain or - when nothing could be drafted
+	LostFoundID       *uint  `json:"lost_found_id"`
+	ComplainID        *uint  `json:"complain_id"`
+}
+
+type QcVerificationResponse struct {
+	ID            uint                    `json:"id"`
+	Tracking      string                  `json:"tracking"`
+	OrderID       uint                    `json:"order_id"`
+	Matched       bool                    `json:"matched"`
+	VerifiedBy    string                  `json:"verified_by"`
+	CreatedAt     string                  `json:"created_at"`
+	Discrepancies []QcDiscrepancyResponse `json:"discrepancies"`
+}
+
+// ToQcVerificationResponse converts QcVerification model to QcVerificationResponse
+func (v *QcVerification) ToQcVerificationResponse() QcVerificationResponse {
+	response := QcVerificationResponse{
+		ID:            v.ID,
+		Tracking:      v.Tracking,
+		OrderID:       v.OrderID,
+		Matched:       v.Matched,
+		VerifiedBy:    "-",
+		CreatedAt:     v.CreatedAt.Format("2006-01-02 15:04:05"),
+		Discrepancies: make([]QcDiscrepancyResponse, len(v.Discrepancies)),
+	}
+	if v.Verifier != nil {
+		response.VerifiedBy = v.Verifier.FullName
+	}
+	for i := range v.Discrepancies {
+		d := &v.Discrepancies[i]
+		resolution := "-"
+		if d.LostFoundID != nil {
+			resolution = "lost_found"
+		} else if d.ComplainID != nil {
+			resolution = "complain"
+		}
+		response.Discrepancies[i] = QcDiscrepancyResponse{
+			Sku:               d.Sku,
+			ExpectedQuantity:  d.ExpectedQuantity,
+			ConfirmedQuantity: d.ConfirmedQuantity,
+			Difference:        d.Difference(),
+			Resolution:        resolution,
+			LostFoundID:       d.LostFoundID,
+			ComplainID:        d.ComplainID,
+		}
+	}
+	return response
+}
+
+// CompareQcCounts compares the quantities confirmed per SKU with the order details and returns the
+// SKUs counted differently, in order detail order followed by SKUs the order does not have. SKUs of
+// the order missing from confirmed count as zero. Keys of confirmed must be SKUs as written on the
+// products; SKUs are compared case-insensitively.
+func CompareQcCounts(order *Order, confirmed map[string]int) []QcDiscrepancy {
+	counted := make(map[string]int, len(confirmed))
+	extra := make(map[string]string) // SKUs not on the order, as written in confirmed
+	for sku, quantity := range confirmed {
+		key := strings.ToUpper(sku)
+		counted[key] += quantity
+		extra[key] = sku
+	}
+
+	var skus []string
+	expected := make(map[string]int)
+	for _, detail := range order.OrderDetails {
+		key := strings.ToUpper(detail.Sku)
+		if _, seen := expected[key]; !seen {
+			skus = append(skus, detail.Sku)
+		}
+		expected[key] += detail.Quantity
+		delete(extra, key)
+	}
+
+	var others []string
+	for _, sku := range extra {
+		others = append(others, sku)
+	}
+	sort.Strings(others)
+	skus = append(skus, others...)
+
+	var discrepancies []QcDiscrepancy
+	for _, sku := range skus {
+		key := strings.ToUpper(sku)
+		if counted[key] != expected[key] {
+			discrepancies = append(discrepancies, QcDiscrepancy{
+				Sku:               sku,
+				ExpectedQuantity:  expected[key],
+				ConfirmedQuantity: counted[key],
+			})
+		}
+	}
+	return discrepancies
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Report delivery statuses
+const (
+	ReportDeliverySending = "sending"
+	ReportDeliverySent    = "sent"
+	ReportDeliveryFailed  = "failed"
+)
+
+// ReportDelivery records one scheduled report run so a schedule slot is only delivered once,
+// even across restarts or several running instances
+type ReportDelivery struct {
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	Report       string         `gorm:"not null;uniqueIndex:idx_report_delivery_slot" json:"report" example:"daily-summary"`
+	ScheduledFor time.Time      `gorm:"not null;uniqueIndex:idx_report_delivery_slot" json:"scheduled_for"`
+	ReportDate   string         `json:"report_date" example:"2025-01-31"`
+	Status       string         `gorm:"not null" json:"status" example:"sent"`
+	Error        string         `json:"error"`
+	SentAt       *time.Time     `gorm:"default:null" json:"sent_at"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
@@ -0,0 +1,311 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"livo-backend/utilities"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Report preset schedules
+const (
+	ReportPresetScheduleNone   = ""
+	ReportPresetScheduleDaily  = "daily"
+	ReportPresetScheduleWeekly = "weekly"
+)
+
+// Date styles of preset targets
+const (
+	reportPresetDateRange = "range" // start_date and end_date
+	reportPresetDateDay   = "day"   // a single date
+)
+
+// ReportPresetTarget is a list or report a preset can filter, named after its path under /api
+type ReportPresetTarget struct {
+	DateStyle  string   // range for start_date and end_date, day for a single date
+	Filters    []string // Query parameters besides the dates
+	Exportable bool     // Exports as xlsx, so the preset can be scheduled
+	Roles      []string // Roles allowed to use the target, any logged-in user when empty
+}
+
+// ReportPresetTargets are the targets presets can be saved for
+var ReportPresetTargets = map[string]ReportPresetTarget{
+	"orders":                    {DateStyle: reportPresetDateRange, Filters: []string{"search", "channel", "store", "instant", "priority", "at_risk", "sort", "direction"}},
+	"reports/boxes-count":       {DateStyle: reportPresetDateRange, Filters: []string{"search"}, Exportable: true},
+	"reports/handout-outbounds": {DateStyle: reportPresetDateDay, Filters: []string{"search"}, Exportable: true},
+	"reports/handout-returns":   {DateStyle: reportPresetDateDay, Filters: []string{"search"}, Exportable: true},
+	"reports/handout-complains": {DateStyle: reportPresetDateDay, Exportable: true},
+	"reports/user-fees":         {DateStyle: reportPresetDateRange, Filters: []string{"search"}},
+	"reports/packing-billing":   {DateStyle: reportPresetDateRange, Filters: []string{"store"}, Exportable: true, Roles: []string{"superadmin", "finance"}},
+}
+
+// ReportPresetDateRanges are the relative date ranges of presets, resolved when the preset is
+// applied or exported. Targets filtering a single date only accept today and yesterday.
+var ReportPresetDateRanges = []string{"today", "yesterday", "last_7_days", "last_30_days", "this_month", "last_month"}
+
+var reportPresetTime = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// ReportPreset is a named set of filters a user saved for a list or report. Presets of exportable
+// reports can be scheduled to be exported and emailed daily or weekly.
+type ReportPreset struct {
+	ID              uint           `gorm:"primaryKey" json:"id"`
+	UserID          uint           `gorm:"not null;index" json:"user_id"`
+	Name            string         `gorm:"not null" json:"name" example:"Yesterday's outbounds"`
+	Target          string         `gorm:"not null" json:"target" example:"reports/handout-outbounds"`
+	Filters         string         `gorm:"type:jsonb;not null" json:"filters"` // Query parameters, JSON object
+	DateRange       string         `json:"date_range" example:"yesterday"`     // Relative range, replaces the date filters when set
+	Schedule        string         `gorm:"not null;default:''" json:"schedule" example:"daily"`
+	ScheduleTime    string         `json:"schedule_time" example:"07:00"` // Warehouse time of day
+	ScheduleWeekday int            `json:"schedule_weekday" example:"1"`  // Weekly schedules, 0 is Sunday
+	Recipients      string         `json:"recipients" example:"ops@livotech.com"`
+	ScheduledSince  *time.Time     `gorm:"default:null" json:"scheduled_since"` // Schedule slots before this are not exported
+	LastSentAt      *time.Time     `gorm:"default:null" json:"last_sent_at"`
+	LastError       string         `json:"last_error"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationship
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+type ReportPresetResponse struct {
+	ID              uint              `json:"id"`
+	Name            string            `json:"name"`
+	Target          string            `json:"target" example:"reports/handout-outbounds"`
+	Filters         map[string]string `json:"filters"`
+	DateRange       string            `json:"date_range" example:"yesterday"`
+	Schedule        string            `json:"schedule" example:"daily"`
+	ScheduleTime    string            `json:"schedule_time" example:"07:00"`
+	ScheduleWeekday int               `json:"schedule_weekday" example:"1"`
+	Recipients      []string          `json:"recipients"`
+	LastSentAt      string            `json:"last_sent_at"`
+	LastError       string            `json:"last_error"`
+	CreatedAt       string            `json:"created_at"`
+	UpdatedAt       string            `json:"updated_at"`
+}
+
+// ToReportPresetResponse converts ReportPreset model to ReportPresetResponse
+func (p *ReportPreset) ToReportPresetResponse() ReportPresetResponse {
+	lastSentAt := "-"
+	if p.LastSentAt != nil {
+		lastSentAt = p.LastSentAt.Format("2006-01-02 15:04:05")
+	}
+
+	return ReportPresetResponse{
+		ID:              p.ID,
+		Name:            p.Name,
+		Target:          p.Target,
+		Filters:         p.FilterValues(),
+		DateRange:       p.DateRange,
+		Schedule:        p.Schedule,
+		ScheduleTime:    p.ScheduleTime,
+		ScheduleWeekday: p.ScheduleWeekday,
+		Recipients:      p.RecipientList(),
+		LastSentAt:      lastSentAt,
+		LastError:       p.LastError,
+		CreatedAt:       p.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:       p.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// FilterValues returns the saved filters of the preset
+func (p *ReportPreset) FilterValues() map[string]string {
+	filters := map[string]string{}
+	if p.Filters != "" {
+		_ = json.Unmarshal([]byte(p.Filters), &filters)
+	}
+	return filters
+}
+
+// RecipientList returns the recipients of the scheduled export
+func (p *ReportPreset) RecipientList() []string {
+	recipients := []string{}
+	for _, recipient := range strings.Split(p.Recipients, ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			recipients = append(recipients, recipient)
+		}
+	}
+	return recipients
+}
+
+// SetFilters validates filters against the preset target and stores them. Date filters are
+// dropped when the preset uses a relative date range.
+func (p *ReportPreset) SetFilters(filters map[string]string) error {
+	target, ok := ReportPresetTargets[p.Target]
+	if !ok {
+		return fmt.Errorf("unknown target %q", p.Target)
+	}
+
+	allowed := map[string]bool{}
+	for _, name := range target.Filters {
+		allowed[name] = true
+	}
+	dateParams := []string{"start_date", "end_date"}
+	if target.DateStyle == reportPresetDateDay {
+		dateParams = []string{"date"}
+	}
+	for _, name := range dateParams {
+		allowed[name] = p.DateRange == ""
+	}
+
+	cleaned := make(map[string]string, len(filters))
+	for name, value := range filters {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		if !allowed[name] {
+			return fmt.Errorf("filter %q is not supported by %s", name, p.Target)
+		}
+		if strings.HasSuffix(name, "date") {
+			if _, err := utilities.ParseDate(value); err != nil {
+				return fmt.Errorf("%s must be in YYYY-MM-DD format", name)
+			}
+		}
+		cleaned[name] = value
+	}
+
+	data, err := json.Marshal(cleaned)
+	if err != nil {
+		return err
+	}
+	p.Filters = string(data)
+	return nil
+}
+
+// ValidateReportPresetDateRange checks a relative date range is known and fits the target
+func ValidateReportPresetDateRange(target, dateRange string) error {
+	if dateRange == "" {
+		return nil
+	}
+	known := false
+	for _, name := range ReportPresetDateRanges {
+		known = known || name == dateRange
+	}
+	if !known {
+		return fmt.Errorf("date_range must be one of %s", strings.Join(ReportPresetDateRanges, ", "))
+	}
+	if ReportPresetTargets[target].DateStyle == reportPresetDateDay && dateRange != "today" && dateRange != "yesterday" {
+		return fmt.Errorf("%s filters a single date, date_range must be today or yesterday", target)
+	}
+	return nil
+}
+
+// ValidateReportPresetSchedule checks the schedule settings of a preset of target
+func ValidateReportPresetSchedule(target, schedule, at string, weekday int) error {
+	switch schedule {
+	case ReportPresetScheduleNone:
+		return nil
+	case ReportPresetScheduleDaily, ReportPresetScheduleWeekly:
+	default:
+		return fmt.Errorf("schedule must be daily, weekly or empty")
+	}
+	if !ReportPresetTargets[target].Exportable {
+		return fmt.Errorf("%s cannot be exported, only presets of %s can be scheduled", target, strings.Join(ExportableReportPresetTargets(), ", "))
+	}
+	if !reportPresetTime.MatchString(at) {
+		return fmt.Errorf("schedule_time must be in HH:MM format")
+	}
+	if schedule == ReportPresetScheduleWeekly && (weekday < 0 || weekday > 6) {
+		return fmt.Errorf("schedule_weekday must be between 0 (Sunday) and 6 (Saturday)")
+	}
+	return nil
+}
+
+// ExportableReportPresetTargets returns the targets whose presets can be scheduled, sorted
+func ExportableReportPresetTargets() []string {
+	var targets []string
+	for name, target := range ReportPresetTargets {
+		if target.Exportable {
+			targets = append(targets, name)
+		}
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// CanUseReportPresetTarget reports whether a user with roles may use the target
+func CanUseReportPresetTarget(target string, roles []string) bool {
+	allowed := ReportPresetTargets[target].Roles
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, role := range roles {
+		for _, name := range allowed {
+			if role == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ResolveReportPresetDateRange returns the first and last day of a relative date range on the day of now
+func ResolveReportPresetDateRange(dateRange string, now time.Time) (time.Time, time.Time) {
+	today, _ := utilities.DayRange(now)
+	switch dateRange {
+	case "yesterday":
+		yesterday := today.AddDate(0, 0, -1)
+		return yesterday, yesterday
+	case "last_7_days":
+		return today.AddDate(0, 0, -6), today
+	case "last_30_days":
+		return today.AddDate(0, 0, -29), today
+	case "this_month":
+		return today.AddDate(0, 0, 1-today.Day()), today
+	case "last_month":
+		firstOfMonth := today.AddDate(0, 0, 1-today.Day())
+		return firstOfMonth.AddDate(0, -1, 0), firstOfMonth.AddDate(0, 0, -1)
+	default:
+		return today, today
+	}
+}
+
+// Query returns the query parameters applying the preset on the day of now, with the relative
+// date range resolved
+func (p *ReportPreset) Query(now time.Time) url.Values {
+	query := url.Values{}
+	for name, value := range p.FilterValues() {
+		query.Set(name, value)
+	}
+
+	if p.DateRange != "" {
+		start, end := ResolveReportPresetDateRange(p.DateRange, now)
+		if ReportPresetTargets[p.Target].DateStyle == reportPresetDateDay {
+			query.Set("date", end.Format("2006-01-02"))
+		} else {
+			query.Set("start_date", start.Format("2006-01-02"))
+			query.Set("end_date", end.Format("2006-01-02"))
+		}
+	}
+	return query
+}
+
+// LastScheduleSlot returns the latest schedule slot of the preset at or before now, zero when the
+// preset is not scheduled
+func (p *ReportPreset) LastScheduleSlot(now time.Time) time.Time {
+	if p.Schedule == ReportPresetScheduleNone {
+		return time.Time{}
+	}
+	at, err := time.Parse("15:04", p.ScheduleTime)
+	if err != nil {
+		return time.Time{}
+	}
+
+	today, _ := utilities.DayRange(now)
+	slot := time.Date(today.Year(), today.Month(), today.Day(), at.Hour(), at.Minute(), 0, 0, today.Location())
+	if slot.After(now) {
+		slot = slot.AddDate(0, 0, -1)
+	}
+	if p.Schedule == ReportPresetScheduleWeekly {
+		back := (int(slot.Weekday()) - p.ScheduleWeekday + 7) % 7
+		slot = slot.AddDate(0, 0, -back)
+	}
+	return slot
+}
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Operations with a daily request quota
+const (
+	QuotaOperationReport     = "report"
+	QuotaOperationExport     = "export"
+	QuotaOperationBulkImport = "bulk_import"
+)
+
+// QuotaOperations lists every operation with a daily request quota
+var QuotaOperations = []string{QuotaOperationReport, QuotaOperationExport, QuotaOperationBulkImport}
+
+// QuotaPolicy maps role names to the number of requests a user with that role may make per day.
+// The "default" entry applies to roles not listed; without it those roles are unlimited.
+type QuotaPolicy map[string]int
+
+// QuotaUsage counts the requests a user made for an operation on one day
+type QuotaUsage struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_quota_usage" json:"user_id"`
+	Operation string    `gorm:"not null;uniqueIndex:idx_quota_usage" json:"operation" example:"export"`
+	Day       string    `gorm:"not null;size:10;uniqueIndex:idx_quota_usage" json:"day" example:"2025-01-31"` // Server local date
+	Count     int       `gorm:"not null;default:0" json:"count" example:"3"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// QuotaUsageResponse describes a user's quota of one operation for today. Limit and remaining are
+// -1 when the operation is unlimited for the user.
+type QuotaUsageResponse struct {
+	Operation string `json:"operation" example:"export"`
+	Used      int    `json:"used" example:"3"`
+	Limit     int    `json:"limit" example:"5"`
+	Remaining int    `json:"remaining" example:"2"`
+	ResetsAt  string `json:"resets_at" example:"2025-02-01 00:00:00"`
+}
+
+// ParseQuotaPolicy parses "role=limit" pairs separated by commas, e.g. "guest=5,default=100"
+func ParseQuotaPolicy(value string) (QuotaPolicy, error) {
+	policy := QuotaPolicy{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		role, limit, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("quota %q must look like role=limit", pair)
+		}
+
+		parsed, err := strconv.Atoi(strings.TrimSpace(limit))
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("quota of role %s must be a non-negative number", strings.TrimSpace(role))
+		}
+		policy[strings.TrimSpace(role)] = parsed
+	}
+
+	return policy, nil
+}
+
+// LimitFor returns the daily limit of a user with the given roles, the most generous of their
+// roles. Limited is false when any of the roles has no limit; superadmins are never limited.
+func (p QuotaPolicy) LimitFor(roles []string) (limit int, limited bool) {
+	limit = -1
+	for _, role := range roles {
+		if role == "superadmin" {
+			return 0, false
+		}
+
+		roleLimit, found := p[role]
+		if !found {
+			roleLimit, found = p["default"]
+		}
+		if !found {
+			return 0, false
+		}
+		limit = max(limit, roleLimit)
+	}
+
+	if limit < 0 {
+		// No roles at all, fall back to the default when there is one
+		roleLimit, found := p["default"]
+		if !found {
+			return 0, false
+		}
+		limit = roleLimit
+	}
+
+	return limit, true
+}
+
+// QuotaDay returns the quota day of t and when that day's quotas reset
+func QuotaDay(t time.Time) (day string, resetsAt time.Time) {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return start.Format("2006-01-02"), start.AddDate(0, 0, 1)
+}
+
+// ConsumeQuota counts one request of the user for operation on day when fewer than limit were made,
+// and returns the usage after the request. Allowed is false, and nothing is counted, once the limit
+// is reached. The check and the increment are a single statement so concurrent requests cannot
+// overshoot the limit.
+func ConsumeQuota(db *gorm.DB, userID uint, operation, day string, limit int) (used int, allowed bool, err error) {
+	if limit > 0 {
+		usage := QuotaUsage{UserID: userID, Operation: operation, Day: day, Count: 1}
+		result := db.Clauses(
+			clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "operation"}, {Name: "day"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("quota_usages.count + 1"), "updated_at": time.Now()}),
+				Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "quota_usages.count < ?", Vars: []interface{}{limit}}}},
+			},
+			clause.Returning{Columns: []clause.Column{{Name: "count"}}},
+		).Create(&usage)
+		if result.Error != nil {
+			return 0, false, fmt.Errorf("failed to count quota usage: %w", result.Error)
+		}
+		if result.RowsAffected > 0 {
+			return usage.Count, true, nil
+		}
+	}
+
+	// Limit reached (or a zero limit), report the current usage
+	used, err = QuotaUsed(db, userID, operation, day)
+	return used, false, err
+}
+
+// QuotaUsed returns how many requests the user made for operation on day
+func QuotaUsed(db *gorm.DB, userID uint, operation, day string) (int, error) {
+	var usage QuotaUsage
+	err := db.Where("user_id = ? AND operation = ? AND day = ?", userID, operation, day).First(&usage).Error
+	if err == gorm.ErrRecordNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to load quota usage: %w", err)
+	}
+	return usage.Count, nil
+}
@@ -23,6 +23,9 @@ type Return struct {
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
 
+	// Exchange, the order shipped to the buyer in place of the returned one
+	ReplacementOrderID *uint `gorm:"default:null;index" json:"replacement_order_id"`
+
 	// Relationship
 	ReturnDetails  []ReturnDetail `gorm:"foreignKey:ReturnID" json:"return_details"`
 	Order          *Order         `gorm:"-" json:"order,omitempty"`
@@ -30,6 +33,9 @@ type Return struct {
 	Store          *Store         `gorm:"foreignKey:StoreID" json:"store,omitempty"`
 	CreateOperator *User          `gorm:"foreignKey:CreatedBy" json:"create_operator,omitempty"`
 	UpdateOperator *User          `gorm:"foreignKey:UpdatedBy" json:"update_operator,omitempty"`
+
+	// Returns and replacement orders linked to this return, oldest first (see LoadReplacementChain)
+	ReplacementChain []ReturnReplacement `gorm:"-" json:"replacement_chain,omitempty"`
 }
 
 type ReturnDetail struct {
@@ -74,6 +80,10 @@ type ReturnResponse struct {
 	UpdatedAt     time.Time              `json:"updated_at"`
 	ReturnDetails []ReturnDetailResponse `json:"return_details"`
 
+	// Exchange
+	ReplacementOrderID *uint               `json:"replacement_order_id"`
+	ReplacementChain   []ReturnReplacement `json:"replacement_chain,omitempty"` // Linked returns and replacement orders, oldest first
+
 	// Related data
 	Order          *OrderResponse   `json:"order,omitempty"`
 	Channel        *ChannelResponse `json:"channel,omitempty"`
@@ -133,6 +143,9 @@ func (r *Return) ToReturnResponse() ReturnResponse {
 		CreatedAt:     r.CreatedAt,
 		UpdatedAt:     r.UpdatedAt,
 		ReturnDetails: detailResponses,
+
+		ReplacementOrderID: r.ReplacementOrderID,
+		ReplacementChain:   r.ReplacementChain,
 	}
 
 	// Handle UpdatedBy (nullable field)
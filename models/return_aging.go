@@ -0,0 +1,168 @@
+package models
+
+import (
+	"fmt"
+	"livo-backend/utilities"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Return dispositions, see ReturnDisposition
+const (
+	ReturnDispositionRestock = "restock"
+	ReturnDispositionScrap   = "scrap"
+	ReturnDispositionPending = "pending"
+)
+
+// ReturnAgeBuckets are the age ranges of the returns aging report, in days since the return was
+// received. The last bucket has no upper bound.
+var ReturnAgeBuckets = []struct {
+	Label   string
+	MaxDays int
+}{
+	{"0-7", 7},
+	{"8-14", 14},
+	{"15-30", 30},
+	{"30+", -1},
+}
+
+// ReturnDisposition tells what happens to a return: scrapped once it has a scrap number, restocked
+// once it has a return number, pending while it has neither
+func ReturnDisposition(ret *Return) string {
+	switch {
+	case ret.ScrapNumber != "":
+		return ReturnDispositionScrap
+	case ret.ReturnNumber != "":
+		return ReturnDispositionRestock
+	default:
+		return ReturnDispositionPending
+	}
+}
+
+// ReturnAgingCounts counts returns per disposition
+type ReturnAgingCounts struct {
+	Restock int `json:"restock" example:"4"`
+	Scrap   int `json:"scrap" example:"1"`
+	Pending int `json:"pending" example:"9"`
+	Total   int `json:"total" example:"14"`
+}
+
+func (c *ReturnAgingCounts) add(disposition string) {
+	switch disposition {
+	case ReturnDispositionRestock:
+		c.Restock++
+	case ReturnDispositionScrap:
+		c.Scrap++
+	default:
+		c.Pending++
+	}
+	c.Total++
+}
+
+// ReturnAgingBucket counts the returns of one age range
+type ReturnAgingBucket struct {
+	Bucket string `json:"bucket" example:"8-14"`
+	ReturnAgingCounts
+}
+
+// ReturnAgingGroup counts the returns of one channel and store, per age range
+type ReturnAgingGroup struct {
+	Channel    string              `json:"channel" example:"Shopee"`
+	Store      string              `json:"store" example:"AXON"`
+	OldestDays int                 `json:"oldest_days" example:"21"`
+	Buckets    []ReturnAgingBucket `json:"buckets"`
+	ReturnAgingCounts
+}
+
+// ReturnAging is the returns aging report
+type ReturnAging struct {
+	GeneratedAt string              `json:"generated_at"`
+	Buckets     []ReturnAgingBucket `json:"buckets"`
+	Groups      []ReturnAgingGroup  `json:"groups"` // Per channel and store, most returns first
+	Total       ReturnAgingCounts   `json:"total"`
+}
+
+// BuildReturnAging buckets returns by days since they were received and by disposition, overall and
+// per channel and store. Only open returns are counted unless includeClosed is set: returns without
+// a disposition and restocked returns whose items are not all put away yet. Scrapped returns and
+// restocked returns put away entirely are closed.
+func BuildReturnAging(db *gorm.DB, now time.Time, includeClosed bool) (ReturnAging, error) {
+	query := db.Table("returns").
+		Select(`returns.created_at, returns.return_number, returns.scrap_number,
+			COALESCE(channels.name, '-') AS channel, COALESCE(stores.name, '-') AS store`).
+		Joins("LEFT JOIN channels ON channels.id = returns.channel_id").
+		Joins("LEFT JOIN stores ON stores.id = returns.store_id").
+		Where("returns.deleted_at IS NULL")
+	if !includeClosed {
+		query = query.Where(`returns.scrap_number = '' AND (returns.return_number = '' OR EXISTS (
+			SELECT 1 FROM putaway_tasks WHERE putaway_tasks.return_id = returns.id
+				AND putaway_tasks.status = ? AND putaway_tasks.deleted_at IS NULL))`, PutawayStatusPending)
+	}
+
+	var rows []struct {
+		CreatedAt    time.Time
+		ReturnNumber string
+		ScrapNumber  string
+		Channel      string
+		Store        string
+	}
+	if err := query.Scan(&rows).Error; err != nil {
+		return ReturnAging{}, fmt.Errorf("failed to load returns: %w", err)
+	}
+
+	newBuckets := func() []ReturnAgingBucket {
+		buckets := make([]ReturnAgingBucket, len(ReturnAgeBuckets))
+		for i, bucket := range ReturnAgeBuckets {
+			buckets[i].Bucket = bucket.Label
+		}
+		return buckets
+	}
+
+	report := ReturnAging{
+		GeneratedAt: now.In(utilities.AppLocation()).Format("2006-01-02 15:04:05"),
+		Buckets:     newBuckets(),
+		Groups:      []ReturnAgingGroup{},
+	}
+	groups := make(map[[2]string]*ReturnAgingGroup)
+	for _, row := range rows {
+		disposition := ReturnDisposition(&Return{ReturnNumber: row.ReturnNumber, ScrapNumber: row.ScrapNumber})
+		days := max(int(now.Sub(row.CreatedAt).Hours()/24), 0)
+		bucket := len(ReturnAgeBuckets) - 1
+		for i, ageBucket := range ReturnAgeBuckets {
+			if ageBucket.MaxDays >= 0 && days <= ageBucket.MaxDays {
+				bucket = i
+				break
+			}
+		}
+
+		key := [2]string{row.Channel, row.Store}
+		group, found := groups[key]
+		if !found {
+			group = &ReturnAgingGroup{Channel: row.Channel, Store: row.Store, Buckets: newBuckets()}
+			groups[key] = group
+		}
+		group.OldestDays = max(group.OldestDays, days)
+		group.Buckets[bucket].add(disposition)
+		group.add(disposition)
+
+		report.Buckets[bucket].add(disposition)
+		report.Total.add(disposition)
+	}
+
+	for _, group := range groups {
+		report.Groups = append(report.Groups, *group)
+	}
+	sort.Slice(report.Groups, func(a, b int) bool {
+		if report.Groups[a].Total != report.Groups[b].Total {
+			return report.Groups[a].Total > report.Groups[b].Total
+		}
+		if report.Groups[a].Channel != report.Groups[b].Channel {
+			return report.Groups[a].Channel < report.Groups[b].Channel
+		}
+		return report.Groups[a].Store < report.Groups[b].Store
+	})
+
+	return report, nil
+}
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// maxReplacementHops bounds how far a replacement chain is followed
+const maxReplacementHops = 20
+
+// ReturnReplacement is one exchange of a replacement chain: a return and the order shipped in
+// place of the returned one. The replacement order can be returned and replaced again, which
+// continues the chain.
+type ReturnReplacement struct {
+	ReturnID                uint   `json:"return_id"`
+	ReturnTracking          string `json:"return_tracking" example:"JNE0987654321"` // New tracking of the return
+	ReturnedTracking        string `json:"returned_tracking" example:"JNE1234567890"`
+	ReplacementOrderID      uint   `json:"replacement_order_id"`
+	ReplacementOrderGineeID string `json:"replacement_order_ginee_id" example:"2510026HB12XK9"`
+	ReplacementTracking     string `json:"replacement_tracking" example:"JNE1122334455"`
+	ReplacementStatus       string `json:"replacement_status" example:"picking complete"`
+	ReplacementCancelled    bool   `json:"replacement_cancelled"`
+}
+
+// ValidateReplacementOrder checks that order can be shipped in exchange for ret: it is not cancelled,
+// not the returned order itself, not the replacement of another return and not earlier in the
+// replacement chain of ret.
+func ValidateReplacementOrder(db *gorm.DB, ret *Return, order *Order) error {
+	if order.CancelledAt != nil {
+		return fmt.Errorf("order %s is cancelled", order.OrderGineeID)
+	}
+	if order.Tracking == ret.OldTracking {
+		return fmt.Errorf("order %s is the returned order", order.OrderGineeID)
+	}
+
+	var other Return
+	err := db.Where("replacement_order_id = ? AND id <> ?", order.ID, ret.ID).First(&other).Error
+	if err == nil {
+		return fmt.Errorf("order %s already replaces return %s", order.OrderGineeID, other.NewTracking)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	// Linking an order returned earlier in the chain would loop the chain
+	chain, err := LoadReplacementChain(db, ret)
+	if err != nil {
+		return err
+	}
+	for _, link := range chain {
+		if link.ReturnID == ret.ID {
+			break
+		}
+		if link.ReturnedTracking == order.Tracking {
+			return fmt.Errorf("order %s is earlier in the replacement chain of this return", order.OrderGineeID)
+		}
+	}
+	return nil
+}
+
+// LoadReplacementChain follows the exchanges ret is part of: back through the returns whose
+// replacement was returned as ret, and forward through the replacement of ret and the returns of
+// that replacement. Returns the exchanges oldest first, empty when ret was never replaced nor is
+// the return of a replacement.
+func LoadReplacementChain(db *gorm.DB, ret *Return) ([]ReturnReplacement, error) {
+	visited := map[uint]bool{ret.ID: true}
+
+	// Walk back to the first return of the chain
+	first := *ret
+	for hops := 0; hops < maxReplacementHops && first.OldTracking != ""; hops++ {
+		var previous Return
+		err := db.Joins("JOIN orders ON orders.id = returns.replacement_order_id").
+			Where("orders.tracking = ?", first.OldTracking).
+			First(&previous).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if visited[previous.ID] {
+			break
+		}
+		visited[previous.ID] = true
+		first = previous
+	}
+
+	// Walk forward through each replacement and its return
+	chain := []ReturnReplacement{}
+	current := first
+	seen := map[uint]bool{first.ID: true}
+	for hops := 0; hops < maxReplacementHops && current.ReplacementOrderID != nil; hops++ {
+		var order Order
+		if err := db.Unscoped().First(&order, *current.ReplacementOrderID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				break
+			}
+			return nil, err
+		}
+		chain = append(chain, ReturnReplacement{
+			ReturnID:                current.ID,
+			ReturnTracking:          current.NewTracking,
+			ReturnedTracking:        current.OldTracking,
+			ReplacementOrderID:      order.ID,
+			ReplacementOrderGineeID: order.OrderGineeID,
+			ReplacementTracking:     order.Tracking,
+			ReplacementStatus:       order.ProcessingStatus,
+			ReplacementCancelled:    order.CancelledAt != nil,
+		})
+
+		var next Return
+		err := db.Where("old_tracking = ?", order.Tracking).First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if seen[next.ID] {
+			break
+		}
+		seen[next.ID] = true
+		current = next
+	}
+
+	return chain, nil
+}
+
+// Replacement returns the exchange of the loaded replacement chain replacing r, nil when r was not
+// replaced or the chain is not loaded
+func (r *Return) Replacement() *ReturnReplacement {
+	for i := range r.ReplacementChain {
+		if r.ReplacementChain[i].ReturnID == r.ID {
+			return &r.ReplacementChain[i]
+		}
+	}
+	return nil
+}
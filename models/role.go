@@ -8,12 +8,13 @@ import (
 
 // Role represents system roles
 type Role struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	Name        string         `gorm:"unique;not null" json:"name" example:"admin"`
-	Description string         `json:"description" example:"Administrator role"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID               uint           `gorm:"primaryKey" json:"id"`
+	Name             string         `gorm:"unique;not null" json:"name" example:"admin"`
+	Description      string         `json:"description" example:"Administrator role"`
+	RequireTwoFactor bool           `gorm:"not null;default:false" json:"require_two_factor" example:"false"` // Users with this role must log in with a TOTP code
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // GetRoleHierarchy returns role hierarchy levels
@@ -49,20 +50,22 @@ func (r *Role) CanManageRole(targetRole string) bool {
 }
 
 type RoleListResponse struct {
-	ID          uint      `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID               uint      `json:"id"`
+	Name             string    `json:"name"`
+	Description      string    `json:"description"`
+	RequireTwoFactor bool      `json:"require_two_factor"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 }
 
 // ToRoleListResponse converts Role model to RoleListResponse
 func (r *Role) ToRoleListResponse() RoleListResponse {
 	return RoleListResponse{
-		ID:          r.ID,
-		Name:        r.Name,
-		Description: r.Description,
-		CreatedAt:   r.CreatedAt,
-		UpdatedAt:   r.UpdatedAt,
+		ID:               r.ID,
+		Name:             r.Name,
+		Description:      r.Description,
+		RequireTwoFactor: r.RequireTwoFactor,
+		CreatedAt:        r.CreatedAt,
+		UpdatedAt:        r.UpdatedAt,
 	}
 }
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RoleTemplate bundles what a new user of a kind gets on onboarding: the role, the warehouse zone
+// pickers are assigned to and whether the account starts active, e.g. "seasonal-picker"
+type RoleTemplate struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Name        string         `gorm:"unique;not null" json:"name" example:"seasonal-picker"`
+	Description string         `json:"description" example:"Seasonal pickers for the sale period"`
+	RoleID      uint           `gorm:"not null" json:"role_id"`
+	ZoneID      *uint          `gorm:"default:null" json:"zone_id"` // Zone of the warehouse the user picks in, none when nil
+	IsActive    bool           `gorm:"not null;default:true" json:"is_active" example:"true"`
+	CreatedBy   uint           `gorm:"not null" json:"created_by"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationships
+	Role    *Role `gorm:"foreignKey:RoleID" json:"role,omitempty"`
+	Zone    *Zone `gorm:"foreignKey:ZoneID" json:"zone,omitempty"`
+	Creator *User `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
+}
+
+type RoleTemplateResponse struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Role        string `json:"role"`
+	ZoneID      *uint  `json:"zone_id"`
+	Zone        string `json:"zone"`
+	IsActive    bool   `json:"is_active"`
+	CreatedBy   string `json:"created_by"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// ToRoleTemplateResponse converts RoleTemplate model to RoleTemplateResponse
+func (t *RoleTemplate) ToRoleTemplateResponse() RoleTemplateResponse {
+	role := "-"
+	if t.Role != nil {
+		role = t.Role.Name
+	}
+	zone := "-"
+	if t.Zone != nil {
+		zone = t.Zone.Code
+	}
+	createdBy := "-"
+	if t.Creator != nil {
+		createdBy = t.Creator.FullName
+	}
+
+	return RoleTemplateResponse{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Role:        role,
+		ZoneID:      t.ZoneID,
+		Zone:        zone,
+		IsActive:    t.IsActive,
+		CreatedBy:   createdBy,
+		CreatedAt:   t.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:   t.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// ApplyRoleTemplate gives a newly created user the role and zone of template
+func ApplyRoleTemplate(tx *gorm.DB, user *User, template *RoleTemplate, assignedBy uint) error {
+	userRole := UserRole{
+		UserID:     user.ID,
+		RoleID:     template.RoleID,
+		AssignedBy: assignedBy,
+	}
+	if err := tx.Create(&userRole).Error; err != nil {
+		return fmt.Errorf("failed to assign role: %w", err)
+	}
+
+	if template.ZoneID != nil {
+		pickerZone := PickerZone{
+			UserID:     user.ID,
+			ZoneID:     *template.ZoneID,
+			AssignedBy: assignedBy,
+		}
+		if err := tx.Create(&pickerZone).Error; err != nil {
+			return fmt.Errorf("failed to assign zone: %w", err)
+		}
+	}
+
+	return nil
+}
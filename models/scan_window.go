@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// IsRepeatScan reports whether userID scanning now repeats the scan of a record scannedBy created at
+// createdAt, within ScanRepeatWindow
+func IsRepeatScan(scannedBy *uint, createdAt time.Time, userID uint, now time.Time) bool {
+	window := CurrentSettings().ScanRepeatWindow
+	if window <= 0 || scannedBy == nil || *scannedBy != userID {
+		return false
+	}
+	return now.Sub(createdAt) <= window
+}
@@ -0,0 +1,249 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrSessionRevoked is returned for tokens issued before the user's sessions were revoked
+var ErrSessionRevoked = errors.New("session has been revoked, please login again")
+
+// ErrTokenOutdated is returned for tokens issued before the user's roles changed
+var ErrTokenOutdated = errors.New("your roles have changed, please login again")
+
+// ErrSessionIdle is returned when the user has been inactive for longer than the idle timeout
+var ErrSessionIdle = errors.New("session expired due to inactivity, please login again")
+
+// ErrRefreshTokenUsed is returned for a refresh token the session already exchanged for newer tokens
+var ErrRefreshTokenUsed = errors.New("refresh token was already used, please login again")
+
+// lastActiveResolution limits how often a request writes the user's last activity time
+const lastActiveResolution = time.Minute
+
+// CheckUserSession rejects tokens issued before the user's sessions were revoked or carrying an
+// older token version than the user's, and revokes the sessions of a user that stayed idle for
+// longer than idleTimeout. Other requests refresh the user's last activity time.
+func CheckUserSession(db *gorm.DB, userID uint, issuedAt time.Time, tokenVersion uint, idleTimeout time.Duration) error {
+	var user User
+	if err := db.Select("id", "last_active_at", "sessions_revoked_at", "token_version").First(&user, userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrSessionRevoked
+		}
+		return err
+	}
+
+	// Roles and role level in the token are only trusted while the versions match. Tokens issued
+	// before token versions existed carry 0 and pass until they expire, as long as the user is
+	// still at the initial version 1, so the roles they carry are still current.
+	if tokenVersion != user.TokenVersion && !(tokenVersion == 0 && user.TokenVersion == 1) {
+		return ErrTokenOutdated
+	}
+
+	// Token timestamps only carry whole seconds
+	if user.SessionsRevokedAt != nil && issuedAt.Before(user.SessionsRevokedAt.Truncate(time.Second)) {
+		return ErrSessionRevoked
+	}
+
+	now := time.Now()
+	if idleTimeout > 0 && user.LastActiveAt != nil && now.Sub(*user.LastActiveAt) > idleTimeout {
+		if err := RevokeSessions(db.Where("id = ?", userID), now); err != nil {
+			return err
+		}
+		return ErrSessionIdle
+	}
+
+	if user.LastActiveAt == nil || now.Sub(*user.LastActiveAt) > lastActiveResolution {
+		if err := db.Model(&User{}).Where("id = ?", userID).UpdateColumn("last_active_at", now).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BumpTokenVersion invalidates every token of a user, used when the roles carried in its claims change
+func BumpTokenVersion(db *gorm.DB, userID interface{}) error {
+	return db.Model(&User{}).Where("id = ?", userID).UpdateColumn("token_version", gorm.Expr("token_version + 1")).Error
+}
+
+// RevokeSessions invalidates every token issued before at for the users matched by query
+func RevokeSessions(query *gorm.DB, at time.Time) error {
+	return query.Model(&User{}).UpdateColumn("sessions_revoked_at", at).Error
+}
+
+// RevokeSessionsForRoles invalidates tokens issued before at for active users holding any of the roles.
+// Users already revoked at or after that time are left alone so the call can be repeated safely.
+func RevokeSessionsForRoles(db *gorm.DB, roles []string, at time.Time) (int64, error) {
+	userIDs := db.Table("user_roles").
+		Select("user_roles.user_id").
+		Joins("JOIN roles ON roles.id = user_roles.role_id").
+		Where("roles.name IN ? AND user_roles.deleted_at IS NULL", roles)
+
+	result := db.Model(&User{}).
+		Where("id IN (?) AND last_active_at IS NOT NULL AND (sessions_revoked_at IS NULL OR sessions_revoked_at < ?)", userIDs, at).
+		UpdateColumn("sessions_revoked_at", at)
+
+	return result.RowsAffected, result.Error
+}
+
+// Session is a device logged in as a user, opened at login. It keeps a hash of the device's current
+// refresh token, replaced on every refresh, so revoking the session logs that device out without
+// touching the user's other devices.
+type Session struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	UserID           uint       `gorm:"not null;index" json:"user_id"`
+	RefreshTokenHash string     `gorm:"not null" json:"-"`
+	DeviceName       string     `json:"device_name" example:"Zebra TC26"` // Sent by the client at login
+	UserAgent        string     `json:"user_agent" example:"okhttp/4.12.0"`
+	IP               string     `json:"ip" example:"10.0.0.12"` // Last seen from
+	LastSeenAt       time.Time  `json:"last_seen_at"`
+	RefreshedAt      time.Time  `json:"refreshed_at"` // When the current refresh token was issued
+	ExpiresAt        time.Time  `json:"expires_at"`   // When the current refresh token expires
+	RevokedAt        *time.Time `gorm:"default:null" json:"revoked_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+type SessionResponse struct {
+	ID         uint   `json:"id"`
+	DeviceName string `json:"device_name" example:"Zebra TC26"`
+	UserAgent  string `json:"user_agent" example:"okhttp/4.12.0"`
+	IP         string `json:"ip" example:"10.0.0.12"`
+	Current    bool   `json:"current"` // The session of the token making the request
+	LastSeenAt string `json:"last_seen_at"`
+	ExpiresAt  string `json:"expires_at"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// ToSessionResponse converts Session model to SessionResponse, currentID is the session of the caller
+func (s *Session) ToSessionResponse(currentID uint) SessionResponse {
+	return SessionResponse{
+		ID:         s.ID,
+		DeviceName: s.DeviceName,
+		UserAgent:  s.UserAgent,
+		IP:         s.IP,
+		Current:    s.ID == currentID,
+		LastSeenAt: s.LastSeenAt.Format("2006-01-02 15:04:05"),
+		ExpiresAt:  s.ExpiresAt.Format("2006-01-02 15:04:05"),
+		CreatedAt:  s.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// hashRefreshToken returns the stored form of a refresh token
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// OpenSession starts the session of a device logging in. The refresh token is set with Rotate once
+// the tokens carrying the session ID are generated.
+func OpenSession(db *gorm.DB, userID uint, deviceName, userAgent, ip string, now time.Time) (*Session, error) {
+	session := Session{
+		UserID:      userID,
+		DeviceName:  deviceName,
+		UserAgent:   userAgent,
+		IP:          ip,
+		LastSeenAt:  now,
+		RefreshedAt: now,
+		ExpiresAt:   now,
+	}
+	if err := db.Create(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Rotate stores the new refresh token of the session, valid for ttl
+func (s *Session) Rotate(db *gorm.DB, refreshToken, ip string, ttl time.Duration, now time.Time) error {
+	s.RefreshTokenHash = hashRefreshToken(refreshToken)
+	s.IP = ip
+	s.LastSeenAt = now
+	s.RefreshedAt = now
+	s.ExpiresAt = now.Add(ttl)
+	return db.Model(s).Select("refresh_token_hash", "ip", "last_seen_at", "refreshed_at", "expires_at").Updates(s).Error
+}
+
+// FindRefreshSession returns the session of a user a refresh token belongs to. The token must be the
+// session's current one: a token exchanged before was copied or stolen, so the session is revoked.
+func FindRefreshSession(db *gorm.DB, userID, sessionID uint, refreshToken string, now time.Time) (*Session, error) {
+	var session Session
+	if err := db.Where("id = ? AND user_id = ?", sessionID, userID).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSessionRevoked
+		}
+		return nil, err
+	}
+	if session.RevokedAt != nil || !now.Before(session.ExpiresAt) {
+		return nil, ErrSessionRevoked
+	}
+	if session.RefreshTokenHash != hashRefreshToken(refreshToken) {
+		if err := db.Model(&session).UpdateColumn("revoked_at", now).Error; err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenUsed
+	}
+	return &session, nil
+}
+
+// CheckDeviceSession rejects access tokens of a revoked session and refreshes the session's last
+// activity. Tokens issued before sessions were tracked carry no session and pass.
+func CheckDeviceSession(db *gorm.DB, sessionID uint, ip string, now time.Time) error {
+	if sessionID == 0 {
+		return nil
+	}
+
+	var session Session
+	if err := db.Select("id", "ip", "last_seen_at", "revoked_at").First(&session, sessionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionRevoked
+		}
+		return err
+	}
+	if session.RevokedAt != nil {
+		return ErrSessionRevoked
+	}
+
+	if now.Sub(session.LastSeenAt) > lastActiveResolution || session.IP != ip {
+		return db.Model(&session).UpdateColumns(map[string]interface{}{"last_seen_at": now, "ip": ip}).Error
+	}
+	return nil
+}
+
+// ActiveSessions returns the sessions of user that can still refresh their tokens, most recently
+// seen first. Sessions refreshed before the user's sessions were revoked are left out.
+func ActiveSessions(db *gorm.DB, user *User, now time.Time) ([]Session, error) {
+	query := db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", user.ID, now)
+	if user.SessionsRevokedAt != nil {
+		query = query.Where("refreshed_at >= ?", user.SessionsRevokedAt.Truncate(time.Second))
+	}
+
+	sessions := []Session{}
+	err := query.Order("last_seen_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+// RevokeSession logs one device of a user out. Returns gorm.ErrRecordNotFound when the user has no
+// such session still active.
+func RevokeSession(db *gorm.DB, userID, sessionID uint, now time.Time) error {
+	result := db.Model(&Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		UpdateColumn("revoked_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RevokeUserSessions logs every device of a user out once its access token expires
+func RevokeUserSessions(db *gorm.DB, userID uint, now time.Time) error {
+	return db.Model(&Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		UpdateColumn("revoked_at", now).Error
+}
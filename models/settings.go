@@ -0,0 +1,132 @@
+package models
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Settings are the policies of the models package that come from the configuration. They can
+// change while the server runs (see bootstrap.ApplySettings), so they are never mutated in place:
+// a reload builds a new Settings and swaps it in with SetSettings. Read them with
+// CurrentSettings and treat the maps as read-only.
+type Settings struct {
+	// OrderDuplicatePolicy is the duplication policy in use
+	OrderDuplicatePolicy DuplicatePolicy
+
+	// InstantSLA is how long an instant courier order may take from creation until it is sent out
+	InstantSLA time.Duration
+
+	// ScanRepeatWindow is how long after a QC or outbound scan the same operator scanning the
+	// same tracking again counts as a double scan rather than a duplicate. Zero disables the
+	// tolerance.
+	ScanRepeatWindow time.Duration
+
+	// ComplainStageWeights weighs the stages for the stage_weight policy
+	ComplainStageWeights map[string]int
+
+	// IntegrationHealth is the policy used by the readiness check and the integration health job
+	IntegrationHealth IntegrationHealthPolicy
+
+	// PickerProductivity is the policy used when pickers grab orders
+	PickerProductivity PickerProductivityPolicy
+
+	// AutoAssign is the auto-assignment policy
+	AutoAssign AutoAssignPolicy
+
+	// OrderUrgentWindow is how long before sent before an order becomes urgent
+	OrderUrgentWindow time.Duration
+
+	// BoxStock is the box usage forecast policy
+	BoxStock BoxStockPolicy
+
+	// ChannelIntake is the channel intake monitor policy
+	ChannelIntake ChannelIntakePolicy
+
+	// OrderStuckThresholds are how long an order may stay in a processing status before it is
+	// flagged at risk. Statuses left out are not monitored.
+	OrderStuckThresholds map[string]time.Duration
+
+	// OrderListDefaultSort is the sort of the order list when the client sends none
+	OrderListDefaultSort string
+
+	// ImportedUserEmailDomain is the domain of the placeholder email of users imported without one
+	ImportedUserEmailDomain string
+
+	// LoginLockout is the lockout applied to password logins
+	LoginLockout LoginLockoutPolicy
+
+	// Geocoder validates the addresses of created and edited orders, none when nil
+	Geocoder AddressGeocoder
+
+	// AddressValidationRequired rejects orders whose address the geocoder cannot place instead
+	// of keeping them as unresolved. Geocoder failures never reject orders.
+	AddressValidationRequired bool
+
+	// MasterDeleteGuardDays is how many days back transactions keep the boxes, channels, stores
+	// and expeditions they reference from being deleted, 0 disables the guard
+	MasterDeleteGuardDays int
+
+	// RequestQuotas holds the quota policy of each operation
+	RequestQuotas map[string]QuotaPolicy
+
+	// ComplainSLAHours is how long complains of stores without an SLA of their own have to be
+	// checked
+	ComplainSLAHours int
+}
+
+// currentSettings holds the Settings in use
+var currentSettings atomic.Pointer[Settings]
+
+func init() {
+	currentSettings.Store(DefaultSettings())
+}
+
+// DefaultSettings returns the settings used before the configuration is applied
+func DefaultSettings() *Settings {
+	return &Settings{
+		OrderDuplicatePolicy: DuplicatePolicy{
+			GineeIDSuffix:  "-X{n}",
+			TrackingPrefix: "X-",
+			MaxDuplicates:  3,
+			ModifyOriginal: true,
+		},
+		InstantSLA:       60 * time.Minute,
+		ScanRepeatWindow: 10 * time.Second,
+		ComplainStageWeights: map[string]int{
+			ComplainStagePicking:  40,
+			ComplainStageQcRibbon: 30,
+			ComplainStageQcOnline: 30,
+			ComplainStageOutbound: 30,
+			ComplainStagePending:  10,
+			ComplainStageReturn:   10,
+		},
+		IntegrationHealth:  IntegrationHealthPolicy{Window: 15 * time.Minute, ErrorRate: 0.5, MinCalls: 5},
+		PickerProductivity: PickerProductivityPolicy{Days: 14, MinPicks: 20, SpeedWeight: 50, AccuracyWeight: 50, UrgentWindow: 2 * time.Hour},
+		AutoAssign:         AutoAssignPolicy{ActiveMinutes: 30, MaxOpenOrders: 5, ZoneAffinity: true},
+		OrderUrgentWindow:  6 * time.Hour,
+		BoxStock:           BoxStockPolicy{UsageDays: 14, AlertDays: 7},
+		ChannelIntake:      ChannelIntakePolicy{BaselineDays: 14, DropPercent: 50, MinExpected: 20},
+		OrderStuckThresholds: map[string]time.Duration{
+			"picking process":   4 * time.Hour,
+			"picking complete":  12 * time.Hour,
+			"picking completed": 12 * time.Hour,
+		},
+		OrderListDefaultSort:    "created_at:desc",
+		ImportedUserEmailDomain: "users.livo.local",
+		LoginLockout:            LoginLockoutPolicy{MaxFailures: 5, Duration: 15 * time.Minute},
+		MasterDeleteGuardDays:   90,
+		RequestQuotas:           map[string]QuotaPolicy{},
+		ComplainSLAHours:        48,
+	}
+}
+
+// CurrentSettings returns the settings in use. Take it once per operation so every read of the
+// operation sees the same configuration.
+func CurrentSettings() *Settings {
+	return currentSettings.Load()
+}
+
+// SetSettings makes settings the settings in use. settings must not be modified afterwards.
+func SetSettings(settings *Settings) {
+	currentSettings.Store(settings)
+}
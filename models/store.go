@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"gorm.io/gorm"
@@ -10,27 +11,47 @@ type Store struct {
 	ID        uint           `gorm:"primaryKey" json:"id"`
 	Code      string         `gorm:"unique;not null" json:"code" example:"AX"`
 	Name      string         `gorm:"not null;unique" json:"name" example:"AXON"`
+	Settings  string         `gorm:"type:jsonb;not null;default:'{}'" json:"-"` // StoreSettings, JSON object
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
+// StoreSettings are the settings a store overrides the global configuration with, zero values keep
+// the global behaviour
+type StoreSettings struct {
+	LabelHeader      string `json:"label_header" binding:"max=60" example:"AXON Official Store"` // Printed at the top of shipping labels instead of the store name
+	ComplainSLAHours int    `json:"complain_sla_hours" binding:"min=0" example:"24"`             // Replaces COMPLAIN_SLA_HOURS for complains of the store
+	DefaultCourier   string `json:"default_courier" binding:"max=100" example:"J&T Express"`     // Courier of orders arriving without one
+	DocumentFooter   string `json:"document_footer" binding:"max=200" example:"cs@axon.id"`      // Printed at the bottom of complain reports, e.g. the store's contact details
+}
+
 type StoreResponse struct {
-	ID      uint      `json:"id"`
-	Code    string    `json:"code"`
-	Name    string    `json:"name"`
-	Created time.Time `json:"created_at"`
-	Updated time.Time `json:"updated_at"`
+	ID        uint           `json:"id"`
+	Code      string         `json:"code"`
+	Name      string         `json:"name"`
+	Settings  *StoreSettings `json:"settings,omitempty"`
+	Created   time.Time      `json:"created_at"`
+	Updated   time.Time      `json:"updated_at"`
+	DeletedAt string         `json:"deleted_at,omitempty"` // Only set on deleted stores listed in the trash
 }
 
 // ToStoreResponse converts Store model to StoreResponse
 func (s *Store) ToStoreResponse() StoreResponse {
+	deletedAt := ""
+	if s.DeletedAt.Valid {
+		deletedAt = s.DeletedAt.Time.Format("2006-01-02 15:04:05")
+	}
+
+	settings := s.GetSettings()
 	return StoreResponse{
-		ID:      s.ID,
-		Code:    s.Code,
-		Name:    s.Name,
-		Created: s.CreatedAt,
-		Updated: s.UpdatedAt,
+		ID:        s.ID,
+		Code:      s.Code,
+		Name:      s.Name,
+		Settings:  &settings,
+		Created:   s.CreatedAt,
+		Updated:   s.UpdatedAt,
+		DeletedAt: deletedAt,
 	}
 }
 
@@ -44,3 +65,57 @@ func (s *Store) ToStoreMobileResponse() StoreResponse {
 		Updated: s.UpdatedAt,
 	}
 }
+
+// GetSettings returns the settings of the store, the global behaviour when none are saved
+func (s *Store) GetSettings() StoreSettings {
+	var settings StoreSettings
+	if s.Settings != "" {
+		json.Unmarshal([]byte(s.Settings), &settings)
+	}
+	return settings
+}
+
+// SetSettings saves settings on the store
+func (s *Store) SetSettings(settings StoreSettings) error {
+	raw, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	s.Settings = string(raw)
+	return nil
+}
+
+// FindStoreSettings returns the settings of the store named name, as orders name their store. Unknown
+// stores have no settings.
+func FindStoreSettings(db *gorm.DB, name string) (StoreSettings, error) {
+	var stores []Store
+	if err := db.Where("name = ?", name).Limit(1).Find(&stores).Error; err != nil {
+		return StoreSettings{}, err
+	}
+	if len(stores) == 0 {
+		return StoreSettings{}, nil
+	}
+	return stores[0].GetSettings(), nil
+}
+
+// ComplainSLAHours returns the complain SLA of the store with storeID, globalHours when the store
+// does not set its own
+func ComplainSLAHours(db *gorm.DB, storeID uint, globalHours int) (int, error) {
+	var stores []Store
+	if err := db.Where("id = ?", storeID).Limit(1).Find(&stores).Error; err != nil {
+		return 0, err
+	}
+	if len(stores) > 0 {
+		if hours := stores[0].GetSettings().ComplainSLAHours; hours > 0 {
+			return hours, nil
+		}
+	}
+	return globalHours, nil
+}
+
+// StoreComplainSLAHoursSQL is the SQL expression of the complain SLA hours of complains.store_id,
+// taking the global hours as argument for stores without their own
+const StoreComplainSLAHoursSQL = `COALESCE((
+	SELECT (stores.settings->>'complain_sla_hours')::int FROM stores
+	WHERE stores.id = complains.store_id AND (stores.settings->>'complain_sla_hours')::int > 0
+), ?)`
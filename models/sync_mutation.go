@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Mutation types accepted by the mobile sync endpoint
+const (
+	SyncMutationScan        = "scan"         // Scan a product barcode against an order line
+	SyncMutationComplete    = "complete"     // Complete picking an order
+	SyncMutationPendingPick = "pending_pick" // Put an order in picking back to pending, approved by a coordinator
+)
+
+// Sync mutation outcomes
+const (
+	SyncMutationProcessing = "processing" // Claimed by a submission that is still applying it
+	SyncMutationApplied    = "applied"    // Applied by an earlier or this submission
+	SyncMutationRejected   = "rejected"   // Refused by the server, e.g. the order moved on meanwhile; resubmitting returns the same outcome
+)
+
+// SyncMutation records the outcome of a mutation a mobile client queued while offline, keyed by the
+// UUID the client generated, so resubmitting a batch does not apply a mutation twice. Mutations that
+// failed on a server error are not recorded and are applied again when resubmitted.
+type SyncMutation struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	ClientID   string         `gorm:"not null;uniqueIndex:idx_sync_mutation_client" json:"client_id" example:"0b8f6c1e-3f5a-4c1e-9a57-2d6f0e4b7a11"`
+	UserID     uint           `gorm:"not null;uniqueIndex:idx_sync_mutation_client" json:"user_id"`
+	Type       string         `gorm:"not null" json:"type" example:"scan"`
+	OrderID    uint           `gorm:"index" json:"order_id"`
+	Status     string         `gorm:"not null" json:"status" example:"applied"`
+	StatusCode int            `json:"status_code" example:"200"`
+	Code       string         `json:"code" example:"ORDER_STATE_INVALID"` // Error code of rejected mutations
+	Message    string         `json:"message" example:"Scanned 1 of 2 for SKU-001"`
+	Error      string         `json:"error"`
+	Result     string         `gorm:"type:text" json:"-"` // JSON of the data returned when applied
+	QueuedAt   *time.Time     `json:"queued_at"`          // When the client queued the mutation
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// trackingReference is a table column holding an order's tracking
+type trackingReference struct {
+	module string
+	table  string
+	column string
+}
+
+// Records that tie an order to its tracking. Once any of them exists the tracking can only be
+// changed through RenameTracking, which moves them along.
+var trackingReferences = []trackingReference{
+	{"qc-ribbon", "qc_ribbons", "tracking"},
+	{"qc-online", "qc_onlines", "tracking"},
+	{"outbound", "outbounds", "tracking"},
+	{"return", "returns", "old_tracking"},
+	{"complain", "complains", "tracking"},
+}
+
+// History tables that follow a renamed tracking but do not block a rename
+var trackingHistoryReferences = []trackingReference{
+	{"delivery history", "delivery_histories", "tracking"},
+	{"outbound alert", "outbound_alerts", "tracking"},
+	{"qc verification", "qc_verifications", "tracking"},
+}
+
+// trackingRenameSetting is the transaction setting RenameTracking raises so the database guard lets
+// its updates through
+const trackingRenameSetting = "livo.allow_tracking_rename"
+
+// TrackingReferencedError is returned when an order's tracking is changed while other modules
+// still reference it
+type TrackingReferencedError struct {
+	Tracking string
+	Modules  []string
+}
+
+func (e *TrackingReferencedError) Error() string {
+	return fmt.Sprintf("tracking %s is referenced by %s records, use the tracking renumbering tool to change it", e.Tracking, strings.Join(e.Modules, ", "))
+}
+
+// FindTrackingReferences returns the modules (qc-ribbon, qc-online, outbound, return, complain)
+// with a record referencing tracking
+func FindTrackingReferences(db *gorm.DB, tracking string) ([]string, error) {
+	var modules []string
+	for _, ref := range trackingReferences {
+		var count int64
+		if err := db.Table(ref.table).Where(ref.column+" = ? AND deleted_at IS NULL", tracking).Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("failed to check %s references: %w", ref.module, err)
+		}
+		if count > 0 {
+			modules = append(modules, ref.module)
+		}
+	}
+	return modules, nil
+}
+
+// CheckTrackingChange returns a TrackingReferencedError when an order's tracking changes from
+// oldTracking to newTracking while other modules reference it
+func CheckTrackingChange(db *gorm.DB, oldTracking, newTracking string) error {
+	if oldTracking == newTracking || oldTracking == "" {
+		return nil
+	}
+
+	modules, err := FindTrackingReferences(db, oldTracking)
+	if err != nil {
+		return err
+	}
+	if len(modules) > 0 {
+		return &TrackingReferencedError{Tracking: oldTracking, Modules: modules}
+	}
+	return nil
+}
+
+// RenameTracking changes an order's tracking together with every record referencing it. tx must be
+// a transaction: the rename is only allowed by the database guard until it commits.
+func RenameTracking(tx *gorm.DB, from, to string) error {
+	if err := tx.Exec("SELECT set_config(?, 'on', true)", trackingRenameSetting).Error; err != nil {
+		return fmt.Errorf("failed to allow tracking rename: %w", err)
+	}
+
+	if err := tx.Model(&Order{}).Where("tracking = ?", from).Update("tracking", to).Error; err != nil {
+		return fmt.Errorf("failed to rename order tracking: %w", err)
+	}
+
+	for _, ref := range append(trackingReferences, trackingHistoryReferences...) {
+		if err := tx.Table(ref.table).Where(ref.column+" = ?", from).Update(ref.column, to).Error; err != nil {
+			return fmt.Errorf("failed to rename %s tracking: %w", ref.module, err)
+		}
+	}
+
+	return nil
+}
+
+// InstallTrackingRenameGuard creates the database trigger that rejects changing an order's tracking
+// while qc, outbound, return or complain records reference it, unless RenameTracking allowed it in
+// the same transaction. Safe to run on every startup.
+func InstallTrackingRenameGuard(db *gorm.DB) error {
+	var checks []string
+	for _, ref := range trackingReferences {
+		checks = append(checks, fmt.Sprintf("SELECT 1 FROM %s WHERE %s = OLD.tracking AND deleted_at IS NULL", ref.table, ref.column))
+	}
+
+	function := fmt.Sprintf(`
+		CREATE OR REPLACE FUNCTION guard_order_tracking_rename() RETURNS trigger AS $$
+		BEGIN
+			IF NEW.tracking IS DISTINCT FROM OLD.tracking
+				AND COALESCE(current_setting('%s', true), '') <> 'on'
+				AND EXISTS (%s) THEN
+				RAISE EXCEPTION 'tracking %% is referenced by qc, outbound, return or complain records, use the tracking renumbering tool to change it', OLD.tracking
+					USING ERRCODE = 'integrity_constraint_violation';
+			END IF;
+			RETURN NEW;
+		END;
+		$$ LANGUAGE plpgsql`, trackingRenameSetting, strings.Join(checks, " UNION ALL "))
+	if err := db.Exec(function).Error; err != nil {
+		return fmt.Errorf("failed to create tracking rename guard function: %w", err)
+	}
+
+	if err := db.Exec("DROP TRIGGER IF EXISTS orders_tracking_rename_guard ON orders").Error; err != nil {
+		return fmt.Errorf("failed to replace tracking rename guard trigger: %w", err)
+	}
+	if err := db.Exec(`CREATE TRIGGER orders_tracking_rename_guard BEFORE UPDATE OF tracking ON orders
+		FOR EACH ROW EXECUTE FUNCTION guard_order_tracking_rename()`).Error; err != nil {
+		return fmt.Errorf("failed to create tracking rename guard trigger: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"livo-backend/utilities"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Wrong codes allowed before two-factor checks lock, and how long the lock lasts after the last
+// wrong code. Six digit codes cannot be brute forced within that budget.
+const (
+	TwoFactorMaxFailures = 5
+	TwoFactorLockout     = 15 * time.Minute
+)
+
+// TwoFactorChallengeTTL is how long the token handed out after the password check stays valid
+const TwoFactorChallengeTTL = 5 * time.Minute
+
+// TwoFactorEnrollmentTTL is how long an enrollment token issued by an admin can be redeemed
+const TwoFactorEnrollmentTTL = 24 * time.Hour
+
+// TwoFactorIssuer is the name authenticator apps show for livo accounts
+const TwoFactorIssuer = "Livo"
+
+// Errors returned by VerifyTwoFactorCode
+var (
+	ErrTwoFactorInvalidCode = errors.New("invalid two-factor code")
+	ErrTwoFactorLocked      = errors.New("too many invalid two-factor codes, try again later")
+	ErrTwoFactorNotEnrolled = errors.New("two-factor authentication is not enrolled")
+)
+
+// ErrTwoFactorEnrollmentInvalid is returned for unknown, expired or already redeemed enrollment tokens
+var ErrTwoFactorEnrollmentInvalid = errors.New("invalid or expired enrollment token")
+
+// TwoFactorEnabled reports whether the user logs in with a TOTP code
+func (u *User) TwoFactorEnabled() bool {
+	return u.TwoFactorEnabledAt != nil
+}
+
+// TwoFactorRequired reports whether one of the user's roles enforces two-factor authentication.
+// UserRoles.Role must be loaded.
+func (u *User) TwoFactorRequired() bool {
+	for _, userRole := range u.UserRoles {
+		if userRole.Role.RequireTwoFactor {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyTwoFactorCode checks a TOTP code against the user's secret, enrolled or pending. Every
+// check counts as a failure in the database before the code is looked at, and accepted codes clear
+// the count again, so concurrent guesses cannot get past the lockout. Accepted codes cannot be used
+// again, not even by a concurrent check.
+func VerifyTwoFactorCode(db *gorm.DB, user *User, code string, now time.Time) error {
+	if user.TOTPSecret == "" {
+		return ErrTwoFactorNotEnrolled
+	}
+
+	// A lock still running is kept as it is, failures older than the lockout start a new count
+	lockStart := now.Add(-TwoFactorLockout)
+	var counted User
+	if err := db.Model(&counted).Clauses(clause.Returning{Columns: []clause.Column{{Name: "two_factor_failures"}, {Name: "two_factor_failed_at"}}}).
+		Where("id = ?", user.ID).
+		UpdateColumns(map[string]interface{}{
+			"two_factor_failures": gorm.Expr("CASE WHEN two_factor_failed_at IS NULL OR two_factor_failed_at <= ? THEN 1 WHEN two_factor_failures >= ? THEN two_factor_failures ELSE two_factor_failures + 1 END",
+				lockStart, TwoFactorMaxFailures+1),
+			"two_factor_failed_at": gorm.Expr("CASE WHEN two_factor_failures >= ? AND two_factor_failed_at > ? THEN two_factor_failed_at ELSE ? END",
+				TwoFactorMaxFailures+1, lockStart, now),
+		}).Error; err != nil {
+		return err
+	}
+	user.TwoFactorFailures = counted.TwoFactorFailures
+	user.TwoFactorFailedAt = counted.TwoFactorFailedAt
+	if counted.TwoFactorFailures > TwoFactorMaxFailures {
+		return ErrTwoFactorLocked
+	}
+
+	step, ok := utilities.ValidateTOTP(user.TOTPSecret, code, now)
+	if !ok {
+		return ErrTwoFactorInvalidCode
+	}
+
+	// Only the first check of a code moves the last step past it
+	result := db.Model(&User{}).Where("id = ? AND two_factor_last_step < ?", user.ID, step).
+		UpdateColumns(map[string]interface{}{"two_factor_last_step": step, "two_factor_failures": 0, "two_factor_failed_at": nil})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTwoFactorInvalidCode
+	}
+
+	user.TwoFactorLastStep = step
+	user.TwoFactorFailures = 0
+	user.TwoFactorFailedAt = nil
+	return nil
+}
+
+// ResetTwoFactor removes the user's secret and turns two-factor authentication off
+func ResetTwoFactor(db *gorm.DB, userID uint) error {
+	return db.Model(&User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"totp_secret":           "",
+		"two_factor_enabled_at": nil,
+		"two_factor_last_step":  0,
+		"two_factor_failures":   0,
+		"two_factor_failed_at":  nil,
+	}).Error
+}
+
+// IssueTwoFactorEnrollment gives the user a new enrollment token, valid for TwoFactorEnrollmentTTL
+// and replacing an earlier one. Only its hash is stored, the token is returned once.
+func IssueTwoFactorEnrollment(db *gorm.DB, userID uint, now time.Time) (string, time.Time, error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", time.Time{}, err
+	}
+	token := hex.EncodeToString(secret)
+	expiresAt := now.Add(TwoFactorEnrollmentTTL)
+
+	err := db.Model(&User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"two_factor_enrollment_hash":       hashEnrollmentToken(token),
+		"two_factor_enrollment_expires_at": expiresAt,
+	}).Error
+	return token, expiresAt, err
+}
+
+// FindTwoFactorEnrollment loads the user with roles an unexpired enrollment token was issued to
+func FindTwoFactorEnrollment(db *gorm.DB, token string, now time.Time) (User, error) {
+	var user User
+	err := db.Preload("UserRoles.Role").Preload("UserRoles.Assigner").
+		Where("two_factor_enrollment_hash = ? AND two_factor_enrollment_expires_at > ?", hashEnrollmentToken(token), now).
+		First(&user).Error
+	if err == gorm.ErrRecordNotFound {
+		return user, ErrTwoFactorEnrollmentInvalid
+	}
+	return user, err
+}
+
+// RedeemTwoFactorEnrollment uses up the user's enrollment token, once: of concurrent redemptions
+// only one succeeds, the others get ErrTwoFactorEnrollmentInvalid
+func RedeemTwoFactorEnrollment(db *gorm.DB, userID uint, token string, now time.Time) error {
+	result := db.Model(&User{}).
+		Where("id = ? AND two_factor_enrollment_hash = ? AND two_factor_enrollment_expires_at > ?", userID, hashEnrollmentToken(token), now).
+		UpdateColumns(map[string]interface{}{"two_factor_enrollment_hash": "", "two_factor_enrollment_expires_at": nil})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTwoFactorEnrollmentInvalid
+	}
+	return nil
+}
+
+// hashEnrollmentToken returns the stored form of an enrollment token
+func hashEnrollmentToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -8,16 +8,28 @@ import (
 
 // User represents a user in the system
 type User struct {
-	ID           uint           `gorm:"primaryKey" json:"id"`
-	Username     string         `gorm:"unique;not null" json:"username" example:"john_doe"`
-	Email        string         `gorm:"unique;not null" json:"email" example:"john@example.com"`
-	Password     string         `gorm:"not null" json:"-"`
-	FullName     string         `gorm:"not null" json:"full_name" example:"John Doe"`
-	IsActive     bool           `gorm:"default:true" json:"is_active" example:"true"`
-	RefreshToken string         `json:"-"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                           uint           `gorm:"primaryKey" json:"id"`
+	Username                     string         `gorm:"unique;not null" json:"username" example:"john_doe"`
+	Email                        string         `gorm:"unique;not null" json:"email" example:"john@example.com"`
+	Password                     string         `gorm:"not null" json:"-"`
+	FullName                     string         `gorm:"not null" json:"full_name" example:"John Doe"`
+	IsActive                     bool           `gorm:"default:true" json:"is_active" example:"true"`
+	RefreshToken                 string         `json:"-"`
+	LastActiveAt                 *time.Time     `gorm:"default:null" json:"-"`
+	SessionsRevokedAt            *time.Time     `gorm:"default:null" json:"-"`       // Tokens issued before this time are rejected
+	TokenVersion                 uint           `gorm:"not null;default:1" json:"-"` // Tokens carrying another version are rejected, bumped on role changes
+	TOTPSecret                   string         `json:"-"`                           // Set on enrollment, only checked once TwoFactorEnabledAt is set
+	TwoFactorEnabledAt           *time.Time     `gorm:"default:null" json:"-"`
+	TwoFactorLastStep            int64          `gorm:"not null;default:0" json:"-"` // Last accepted TOTP time step, older codes are replays
+	TwoFactorFailures            int            `gorm:"not null;default:0" json:"-"`
+	TwoFactorFailedAt            *time.Time     `gorm:"default:null" json:"-"`
+	TwoFactorEnrollmentHash      string         `gorm:"size:64;index" json:"-"` // Hash of the admin-issued enrollment token, cleared when redeemed
+	TwoFactorEnrollmentExpiresAt *time.Time     `gorm:"default:null" json:"-"`
+	LoginFailures                int            `gorm:"not null;default:0" json:"-"` // Wrong passwords since the last successful login or lock
+	LockedUntil                  *time.Time     `gorm:"default:null" json:"-"`       // Logins are refused until then
+	CreatedAt                    time.Time      `json:"created_at"`
+	UpdatedAt                    time.Time      `json:"updated_at"`
+	DeletedAt                    gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationship
 	UserRoles []UserRole `gorm:"foreignKey:UserID" json:"user_roles"`
@@ -25,14 +37,16 @@ type User struct {
 
 // UserResponse represents user data for API responses
 type UserResponse struct {
-	ID        uint           `json:"id"`
-	Username  string         `json:"username"`
-	Email     string         `json:"email"`
-	FullName  string         `json:"full_name"`
-	IsActive  bool           `json:"is_active"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	Roles     []RoleResponse `json:"roles"`
+	ID               uint           `json:"id"`
+	Username         string         `json:"username"`
+	Email            string         `json:"email"`
+	FullName         string         `json:"full_name"`
+	IsActive         bool           `json:"is_active"`
+	TwoFactorEnabled bool           `json:"two_factor_enabled"`
+	LockedUntil      *time.Time     `json:"locked_until"` // Set while logins are locked after too many wrong passwords
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	Roles            []RoleResponse `json:"roles"`
 }
 
 // RoleResponse represents role data for API responses
@@ -57,15 +71,22 @@ func (u *User) ToUserResponse() UserResponse {
 		}
 	}
 
+	var lockedUntil *time.Time
+	if u.LoginLocked(time.Now()) {
+		lockedUntil = u.LockedUntil
+	}
+
 	return UserResponse{
-		ID:        u.ID,
-		Username:  u.Username,
-		Email:     u.Email,
-		FullName:  u.FullName,
-		IsActive:  u.IsActive,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
-		Roles:     roles,
+		ID:               u.ID,
+		Username:         u.Username,
+		Email:            u.Email,
+		FullName:         u.FullName,
+		IsActive:         u.IsActive,
+		TwoFactorEnabled: u.TwoFactorEnabledAt != nil,
+		LockedUntil:      lockedUntil,
+		CreatedAt:        u.CreatedAt,
+		UpdatedAt:        u.UpdatedAt,
+		Roles:            roles,
 	}
 }
 
@@ -81,11 +102,20 @@ func (u *User) HasRole(roleName string) bool {
 
 // GetHighestRoleLevel returns the highest role level of the user
 func (u *User) GetHighestRoleLevel() int {
+	roleNames := make([]string, len(u.UserRoles))
+	for i, userRole := range u.UserRoles {
+		roleNames[i] = userRole.Role.Name
+	}
+	return HighestRoleLevel(roleNames)
+}
+
+// HighestRoleLevel returns the highest hierarchy level of the named roles, 0 when none is known
+func HighestRoleLevel(roleNames []string) int {
 	hierarchy := GetRoleHierarchy()
 	maxLevel := 0
 
-	for _, userRole := range u.UserRoles {
-		if level, exists := hierarchy[userRole.Role.Name]; exists {
+	for _, name := range roleNames {
+		if level, exists := hierarchy[name]; exists {
 			if level > maxLevel {
 				maxLevel = level
 			}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Zone is a warehouse area; a product belongs to the zone whose location prefix matches its location
+type Zone struct {
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	Code           string         `gorm:"unique;not null" json:"code" example:"A"`
+	Name           string         `gorm:"not null" json:"name" example:"Zone A"`
+	LocationPrefix string         `gorm:"not null" json:"location_prefix" example:"Rak A"`
+	PickSequence   int            `gorm:"not null;default:0" json:"pick_sequence" example:"1"` // Zones are walked in ascending sequence when picking
+	RackDescending bool           `gorm:"not null;default:false" json:"rack_descending"`       // Racks are walked from the highest number down
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationship
+	PickerZones []PickerZone `gorm:"foreignKey:ZoneID" json:"picker_zones,omitempty"`
+}
+
+// PickerZone assigns a picker to a zone
+type PickerZone struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	UserID     uint           `gorm:"not null;uniqueIndex:idx_picker_zone" json:"user_id"`
+	ZoneID     uint           `gorm:"not null;uniqueIndex:idx_picker_zone" json:"zone_id"`
+	AssignedBy uint           `gorm:"not null" json:"assigned_by"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationship
+	User     *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Zone     *Zone `gorm:"foreignKey:ZoneID" json:"zone,omitempty"`
+	Assigner *User `gorm:"foreignKey:AssignedBy" json:"assigner,omitempty"`
+}
+
+type ZoneResponse struct {
+	ID             uint           `json:"id"`
+	Code           string         `json:"code"`
+	Name           string         `json:"name"`
+	LocationPrefix string         `json:"location_prefix"`
+	PickSequence   int            `json:"pick_sequence"`
+	RackDescending bool           `json:"rack_descending"`
+	Pickers        []UserResponse `json:"pickers"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// ToZoneResponse converts Zone model to ZoneResponse
+func (z *Zone) ToZoneResponse() ZoneResponse {
+	pickers := make([]UserResponse, 0, len(z.PickerZones))
+	for _, pz := range z.PickerZones {
+		if pz.User != nil {
+			pickers = append(pickers, pz.User.ToUserResponse())
+		}
+	}
+
+	return ZoneResponse{
+		ID:             z.ID,
+		Code:           z.Code,
+		Name:           z.Name,
+		LocationPrefix: z.LocationPrefix,
+		PickSequence:   z.PickSequence,
+		RackDescending: z.RackDescending,
+		Pickers:        pickers,
+		CreatedAt:      z.CreatedAt,
+		UpdatedAt:      z.UpdatedAt,
+	}
+}
+
+// GetPickerZoneIDs returns the zones a picker is assigned to
+func GetPickerZoneIDs(db *gorm.DB, userID uint) ([]uint, error) {
+	var zoneIDs []uint
+	err := db.Model(&PickerZone{}).Where("user_id = ?", userID).Pluck("zone_id", &zoneIDs).Error
+	return zoneIDs, err
+}
+
+// OrderInZonesCondition is a SQL condition on the orders table that holds when at
+// least one ordered product lives in one of the given zones. Pass the zone IDs as its argument.
+const OrderInZonesCondition = `EXISTS (
+	SELECT 1 FROM order_details
+	JOIN products ON products.sku = order_details.sku AND products.deleted_at IS NULL
+	JOIN zones ON products.location ILIKE zones.location_prefix || '%' AND zones.deleted_at IS NULL
+	WHERE order_details.order_id = orders.id AND zones.id IN ?
+)`
+
+// Picker zone modes
+const (
+	PickerZoneModePrefer   = "prefer"   // in-zone orders first, then everything else
+	PickerZoneModeRestrict = "restrict" // only in-zone orders
+)
+
+// OrderByZonePreference sorts late and urgent orders first, then instant courier orders, then orders in the
+// given zones, then by ship deadline
+func OrderByZonePreference(zoneIDs []uint) clause.OrderBy {
+	return clause.OrderBy{Expression: clause.Expr{
+		SQL:                OrderPriorityRank + ", orders.instant DESC, CASE WHEN " + OrderInZonesCondition + " THEN 0 ELSE 1 END, orders.sent_before ASC, orders.id ASC",
+		Vars:               []interface{}{zoneIDs},
+		WithoutParentheses: true,
+	}}
+}
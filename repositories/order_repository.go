@@ -0,0 +1,156 @@
+// Package repositories loads records together with the relations their API responses show, so the
+// preload chains and the product lookups of order lines are defined once instead of in every
+// controller.
+package repositories
+
+import (
+	"context"
+	"livo-backend/models"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// Relations of an order preloaded on top of its details and picker when asked for
+const (
+	OrderPickerRoles = "PickOperator.UserRoles" // Roles of the picker with who assigned them
+	OrderAssigner    = "AssignOperator"
+	OrderPending     = "PendingOperator"
+	OrderChanger     = "ChangeOperator"
+	OrderCanceller   = "CancelOperator"
+)
+
+// OrderOperators are all operator relations of an order besides the picker
+var OrderOperators = []string{OrderAssigner, OrderPending, OrderChanger, OrderCanceller}
+
+// OrderRepository loads orders with their details, picker and operators
+type OrderRepository struct {
+	DB *gorm.DB
+}
+
+// NewOrderRepository creates a new order repository
+func NewOrderRepository(db *gorm.DB) *OrderRepository {
+	return &OrderRepository{DB: db}
+}
+
+// OrderWithDetails preloads the order details and the picker, plus the given relations, for use
+// with Scopes
+func OrderWithDetails(relations ...string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return preloadOrder(db, "", relations)
+	}
+}
+
+// PickedOrderWithDetails preloads the picker of a pick and the picked order with its details, both
+// pickers with their roles, for use with Scopes
+func PickedOrderWithDetails(db *gorm.DB) *gorm.DB {
+	db = preloadPickerRoles(db, "PickOperator")
+	return preloadOrder(db, "Order.", []string{OrderPickerRoles})
+}
+
+// preloadOrder preloads the relations of OrderWithDetails on the order at prefix, "" for the
+// queried order itself
+func preloadOrder(db *gorm.DB, prefix string, relations []string) *gorm.DB {
+	db = db.Preload(prefix + "OrderDetails")
+	if slices.Contains(relations, OrderPickerRoles) {
+		db = preloadPickerRoles(db, prefix+"PickOperator")
+	} else {
+		db = db.Preload(prefix + "PickOperator")
+	}
+	for _, relation := range relations {
+		if relation != OrderPickerRoles {
+			db = db.Preload(prefix + relation)
+		}
+	}
+	return db
+}
+
+// preloadPickerRoles preloads the user at picker with their roles and who assigned them
+func preloadPickerRoles(db *gorm.DB, picker string) *gorm.DB {
+	return db.Preload(picker + ".UserRoles.Role").Preload(picker + ".UserRoles.Assigner")
+}
+
+// FindWithDetails loads an order by ID with its details, their products,
+// the picker and the given relations. Returns gorm.ErrRecordNotFound when there is no such order.
+func (r *OrderRepository) FindWithDetails(ctx context.Context, id uint, relations ...string) (models.Order, error) {
+	var order models.Order
+	if err := r.DB.WithContext(ctx).Scopes(OrderWithDetails(relations...)).First(&order, id).Error; err != nil {
+		return order, err
+	}
+	return order, r.AttachProducts(ctx, &order)
+}
+
+// Reload loads order again after a change, with the relations of FindWithDetails
+func (r *OrderRepository) Reload(ctx context.Context, order *models.Order, relations ...string) error {
+	if err := r.DB.WithContext(ctx).Scopes(OrderWithDetails(relations...)).First(order, order.ID).Error; err != nil {
+		return err
+	}
+	return r.AttachProducts(ctx, order)
+}
+
+// FindByTracking loads the order of a tracking with its details, picker and the given relations,
+// without products. Returns gorm.ErrRecordNotFound when there is no such order.
+func (r *OrderRepository) FindByTracking(ctx context.Context, tracking string, relations ...string) (models.Order, error) {
+	var order models.Order
+	err := r.DB.WithContext(ctx).Scopes(OrderWithDetails(relations...)).Where("tracking = ?", tracking).First(&order).Error
+	return order, err
+}
+
+// FindByTrackings loads the orders of the given trackings with their details, picker and the given
+// relations, without products, keyed by tracking
+func (r *OrderRepository) FindByTrackings(ctx context.Context, trackings []string, relations ...string) (map[string]*models.Order, error) {
+	byTracking := make(map[string]*models.Order, len(trackings))
+	if len(trackings) == 0 {
+		return byTracking, nil
+	}
+
+	var orders []models.Order
+	if err := r.DB.WithContext(ctx).Scopes(OrderWithDetails(relations...)).Where("tracking IN ?", trackings).Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		byTracking[orders[i].Tracking] = &orders[i]
+	}
+	return byTracking, nil
+}
+
+// AttachListProducts attaches the products of every order of a list, see AttachProducts
+func (r *OrderRepository) AttachListProducts(ctx context.Context, orders []models.Order) error {
+	pointers := make([]*models.Order, len(orders))
+	for i := range orders {
+		pointers[i] = &orders[i]
+	}
+	return r.AttachProducts(ctx, pointers...)
+}
+
+// AttachProducts sets the product of every order line of the orders from the products table, in
+// one query. Lines whose SKU has no product are left without one.
+func (r *OrderRepository) AttachProducts(ctx context.Context, orders ...*models.Order) error {
+	var skus []string
+	for _, order := range orders {
+		for i := range order.OrderDetails {
+			skus = append(skus, order.OrderDetails[i].Sku)
+		}
+	}
+	if len(skus) == 0 {
+		return nil
+	}
+
+	var products []models.Product
+	if err := r.DB.WithContext(ctx).Where("sku IN ?", skus).Find(&products).Error; err != nil {
+		return err
+	}
+	bySku := make(map[string]*models.Product, len(products))
+	for i := range products {
+		bySku[products[i].Sku] = &products[i]
+	}
+
+	for _, order := range orders {
+		for i := range order.OrderDetails {
+			if product, found := bySku[order.OrderDetails[i].Sku]; found {
+				order.OrderDetails[i].Product = product
+			}
+		}
+	}
+	return nil
+}
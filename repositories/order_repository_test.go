@@ -0,0 +1,308 @@
+package repositories
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"livo-backend/models"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// fakeDB is a gorm connection that answers queries from fixture rows instead of a database. Rows
+// are filtered by the key conditions gorm builds for primary keys and preloads, conditions written
+// as SQL are only recorded.
+type fakeDB struct {
+	rows    map[string][]interface{}
+	queries []string
+}
+
+// newFakeDB returns a connection that answers queries from rows, keyed by table
+func newFakeDB(t *testing.T, rows map[string][]interface{}) (*gorm.DB, *fakeDB) {
+	t.Helper()
+	fake := &fakeDB{rows: rows}
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DisableAutomaticPing:   true,
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("open fake database: %v", err)
+	}
+	if err := db.Callback().Query().Replace("gorm:query", fake.query); err != nil {
+		t.Fatalf("register fake query: %v", err)
+	}
+	return db, fake
+}
+
+func (f *fakeDB) query(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	callbacks.BuildQuerySQL(db)
+	f.queries = append(f.queries, db.Dialector.Explain(db.Statement.SQL.String(), db.Statement.Vars...))
+
+	var rows []interface{}
+	for _, row := range f.rows[db.Statement.Table] {
+		if matchesKeys(db, row) {
+			rows = append(rows, row)
+		}
+	}
+	dest := reflect.Indirect(reflect.ValueOf(db.Statement.Dest))
+	switch dest.Kind() {
+	case reflect.Slice:
+		for _, row := range rows {
+			value := reflect.ValueOf(row)
+			if dest.Type().Elem().Kind() == reflect.Ptr {
+				pointer := reflect.New(value.Type())
+				pointer.Elem().Set(value)
+				value = pointer
+			}
+			dest.Set(reflect.Append(dest, value))
+		}
+		db.RowsAffected = int64(len(rows))
+	case reflect.Struct:
+		if len(rows) > 0 {
+			dest.Set(reflect.ValueOf(rows[0]))
+			db.RowsAffected = 1
+		}
+	}
+	if db.RowsAffected == 0 && db.Statement.RaiseErrorOnNotFound {
+		db.AddError(gorm.ErrRecordNotFound)
+	}
+}
+
+// matchesKeys reports whether row meets the column conditions of the query
+func matchesKeys(db *gorm.DB, row interface{}) bool {
+	where, ok := db.Statement.Clauses["WHERE"].Expression.(clause.Where)
+	if !ok {
+		return true
+	}
+	for _, expression := range where.Exprs {
+		var column interface{}
+		var values []interface{}
+		switch condition := expression.(type) {
+		case clause.IN:
+			column, values = condition.Column, condition.Values
+		case clause.Eq:
+			column, values = condition.Column, []interface{}{condition.Value}
+		default:
+			continue
+		}
+		name, ok := column.(clause.Column)
+		if !ok {
+			continue
+		}
+		field := db.Statement.Schema.PrioritizedPrimaryField
+		if name.Name != clause.PrimaryKey {
+			field = db.Statement.Schema.LookUpField(name.Name)
+		}
+		value := reflect.New(reflect.TypeOf(row)).Elem()
+		value.Set(reflect.ValueOf(row))
+		fieldValue, zero := field.ValueOf(db.Statement.Context, value)
+		if len(values) == 1 && isNull(values[0]) {
+			// Soft delete condition, deleted_at IS NULL
+			if !zero {
+				return false
+			}
+			continue
+		}
+		key := fmt.Sprint(reflect.Indirect(reflect.ValueOf(fieldValue)))
+		if zero || !slices.ContainsFunc(values, func(v interface{}) bool { return fmt.Sprint(reflect.Indirect(reflect.ValueOf(v))) == key }) {
+			return false
+		}
+	}
+	return true
+}
+
+// isNull reports whether value is written as NULL
+func isNull(value interface{}) bool {
+	if valuer, ok := value.(driver.Valuer); ok {
+		value, _ = valuer.Value()
+	}
+	return value == nil
+}
+
+// queriesOn returns the recorded queries that read table
+func (f *fakeDB) queriesOn(table string) []string {
+	var found []string
+	for _, query := range f.queries {
+		if strings.Contains(query, `FROM "`+table+`"`) {
+			found = append(found, query)
+		}
+	}
+	return found
+}
+
+// preloadsOf returns the sorted preloads a scope adds to a query
+func preloadsOf(t *testing.T, scope func(*gorm.DB) *gorm.DB) []string {
+	t.Helper()
+	db, _ := newFakeDB(t, nil)
+	statement := db.Scopes(scope).Find(&[]models.Order{}).Statement
+	preloads := make([]string, 0, len(statement.Preloads))
+	for preload := range statement.Preloads {
+		preloads = append(preloads, preload)
+	}
+	sort.Strings(preloads)
+	return preloads
+}
+
+func uintPointer(value uint) *uint {
+	return &value
+}
+
+// orderFixtures are an order with two lines picked by a user holding a role assigned by another user
+func orderFixtures() map[string][]interface{} {
+	return map[string][]interface{}{
+		"orders": {models.Order{ID: 1, Tracking: "JNE1", PickedBy: uintPointer(7), AssignedBy: uintPointer(8)}},
+		"order_details": {
+			models.OrderDetail{ID: 10, OrderID: 1, Sku: "SKU-A"},
+			models.OrderDetail{ID: 11, OrderID: 1, Sku: "SKU-MISSING"},
+		},
+		"users": {
+			models.User{ID: 7, Username: "picker"},
+			models.User{ID: 8, Username: "coordinator"},
+		},
+		"user_roles": {models.UserRole{ID: 20, UserID: 7, RoleID: 30, AssignedBy: 8}},
+		"roles":      {models.Role{ID: 30, Name: "picker"}},
+		"products":   {models.Product{ID: 40, Sku: "SKU-A"}},
+	}
+}
+
+func TestOrderWithDetailsPreloads(t *testing.T) {
+	tests := []struct {
+		name      string
+		relations []string
+		want      []string
+	}{
+		{
+			name: "details and picker",
+			want: []string{"OrderDetails", "PickOperator"},
+		},
+		{
+			name:      "picker roles replace the plain picker",
+			relations: []string{OrderPickerRoles, OrderAssigner},
+			want:      []string{"AssignOperator", "OrderDetails", "PickOperator.UserRoles.Assigner", "PickOperator.UserRoles.Role"},
+		},
+		{
+			name:      "operators",
+			relations: OrderOperators,
+			want:      []string{"AssignOperator", "CancelOperator", "ChangeOperator", "OrderDetails", "PendingOperator", "PickOperator"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := preloadsOf(t, OrderWithDetails(tt.relations...)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("preloads = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPickedOrderWithDetailsPreloads(t *testing.T) {
+	want := []string{
+		"Order.OrderDetails",
+		"Order.PickOperator.UserRoles.Assigner",
+		"Order.PickOperator.UserRoles.Role",
+		"PickOperator.UserRoles.Assigner",
+		"PickOperator.UserRoles.Role",
+	}
+	if got := preloadsOf(t, PickedOrderWithDetails); !reflect.DeepEqual(got, want) {
+		t.Errorf("preloads = %v, want %v", got, want)
+	}
+}
+
+func TestOrderRepositoryFindWithDetails(t *testing.T) {
+	db, fake := newFakeDB(t, orderFixtures())
+	repository := NewOrderRepository(db)
+
+	order, err := repository.FindWithDetails(context.Background(), 1, OrderPickerRoles, OrderAssigner)
+	if err != nil {
+		t.Fatalf("FindWithDetails: %v", err)
+	}
+
+	if len(order.OrderDetails) != 2 {
+		t.Fatalf("order details = %d, want 2", len(order.OrderDetails))
+	}
+	if product := order.OrderDetails[0].Product; product == nil || product.ID != 40 {
+		t.Errorf("product of SKU-A = %+v, want product 40", product)
+	}
+	if product := order.OrderDetails[1].Product; product != nil {
+		t.Errorf("product of SKU-MISSING = %+v, want none", product)
+	}
+	if order.PickOperator == nil || len(order.PickOperator.UserRoles) != 1 {
+		t.Fatalf("picker = %+v, want the picker with one role", order.PickOperator)
+	}
+	userRole := order.PickOperator.UserRoles[0]
+	if userRole.Role.Name != "picker" || userRole.Assigner.ID != 8 {
+		t.Errorf("picker role = %+v, want role picker assigned by user 8", userRole)
+	}
+	if order.AssignOperator == nil || order.AssignOperator.ID != 8 {
+		t.Errorf("assigner = %+v, want user 8", order.AssignOperator)
+	}
+
+	products := fake.queriesOn("products")
+	if len(products) != 1 || !strings.Contains(products[0], `sku IN ('SKU-A','SKU-MISSING')`) {
+		t.Errorf("product queries = %v, want one query for both SKUs", products)
+	}
+}
+
+func TestOrderRepositoryFindWithDetailsNotFound(t *testing.T) {
+	db, fake := newFakeDB(t, nil)
+
+	_, err := NewOrderRepository(db).FindWithDetails(context.Background(), 1)
+	if err != gorm.ErrRecordNotFound {
+		t.Fatalf("error = %v, want gorm.ErrRecordNotFound", err)
+	}
+	if products := fake.queriesOn("products"); len(products) != 0 {
+		t.Errorf("product queries = %v, want none", products)
+	}
+}
+
+func TestOrderRepositoryFindByTrackings(t *testing.T) {
+	db, fake := newFakeDB(t, orderFixtures())
+
+	orders, err := NewOrderRepository(db).FindByTrackings(context.Background(), []string{"JNE1", "JNE2"})
+	if err != nil {
+		t.Fatalf("FindByTrackings: %v", err)
+	}
+	if len(orders) != 1 || orders["JNE1"] == nil || orders["JNE1"].ID != 1 {
+		t.Errorf("orders = %v, want order 1 keyed by JNE1", orders)
+	}
+	if queries := fake.queriesOn("orders"); len(queries) != 1 || !strings.Contains(queries[0], `tracking IN ('JNE1','JNE2')`) {
+		t.Errorf("order queries = %v, want one query for both trackings", queries)
+	}
+	if products := fake.queriesOn("products"); len(products) != 0 {
+		t.Errorf("product queries = %v, want none", products)
+	}
+}
+
+func TestOrderRepositoryWithoutInput(t *testing.T) {
+	db, fake := newFakeDB(t, orderFixtures())
+	repository := NewOrderRepository(db)
+
+	orders, err := repository.FindByTrackings(context.Background(), nil)
+	if err != nil || orders == nil || len(orders) != 0 {
+		t.Errorf("FindByTrackings(nil) = %v, %v, want an empty map", orders, err)
+	}
+	if err := repository.AttachProducts(context.Background(), &models.Order{}); err != nil {
+		t.Errorf("AttachProducts of an order without lines: %v", err)
+	}
+	if err := repository.AttachListProducts(context.Background(), nil); err != nil {
+		t.Errorf("AttachListProducts(nil): %v", err)
+	}
+	if len(fake.queries) != 0 {
+		t.Errorf("queries = %v, want none", fake.queries)
+	}
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"livo-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// ReturnRepository loads returns with their details, channel, store, operators and original order
+type ReturnRepository struct {
+	DB     *gorm.DB
+	Orders *OrderRepository
+}
+
+// NewReturnRepository creates a new return repository
+func NewReturnRepository(db *gorm.DB) *ReturnRepository {
+	return &ReturnRepository{DB: db, Orders: NewOrderRepository(db)}
+}
+
+// ReturnWithDetails preloads the return details with their products, the channel, the store and the
+// operators of a return, for use with Scopes
+func ReturnWithDetails(db *gorm.DB) *gorm.DB {
+	return db.Preload("ReturnDetails.Product").
+		Preload("Channel").
+		Preload("Store").
+		Preload("CreateOperator").
+		Preload("UpdateOperator")
+}
+
+// FindWithDetails loads a return by ID with the relations of
+// ReturnWithDetails and its original order (matched by old tracking, with the roles of its picker)
+// when there is one. Returns gorm.ErrRecordNotFound when there is no such return.
+func (r *ReturnRepository) FindWithDetails(ctx context.Context, id uint) (models.Return, error) {
+	var ret models.Return
+	if err := r.DB.WithContext(ctx).Scopes(ReturnWithDetails).First(&ret, id).Error; err != nil {
+		return ret, err
+	}
+	return ret, r.AttachOrder(ctx, &ret, OrderPickerRoles)
+}
+
+// Reload loads ret again after a change, with the relations of FindWithDetails
+func (r *ReturnRepository) Reload(ctx context.Context, ret *models.Return) error {
+	if err := r.DB.WithContext(ctx).Scopes(ReturnWithDetails).First(ret, ret.ID).Error; err != nil {
+		return err
+	}
+	return r.AttachOrder(ctx, ret, OrderPickerRoles)
+}
+
+// FindByOldTracking loads the return of an original tracking with the relations of
+// ReturnWithDetails, without its order. Returns gorm.ErrRecordNotFound when there is no such return.
+func (r *ReturnRepository) FindByOldTracking(ctx context.Context, tracking string) (models.Return, error) {
+	var ret models.Return
+	err := r.DB.WithContext(ctx).Scopes(ReturnWithDetails).Where("old_tracking = ?", tracking).First(&ret).Error
+	return ret, err
+}
+
+// AttachOrder sets the original order of a return, matched by its old tracking, with the order's
+// details, picker and the given order relations. Returns without an old tracking or a matching
+// order are left without one.
+func (r *ReturnRepository) AttachOrder(ctx context.Context, ret *models.Return, relations ...string) error {
+	if ret.OldTracking == "" {
+		return nil
+	}
+
+	order, err := r.Orders.FindByTracking(ctx, ret.OldTracking, relations...)
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	ret.Order = &order
+	return nil
+}
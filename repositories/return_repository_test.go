@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"livo-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// returnFixtures are a return of the order of orderFixtures with one line, its channel and store
+func returnFixtures() map[string][]interface{} {
+	rows := orderFixtures()
+	rows["returns"] = []interface{}{models.Return{ID: 5, OldTracking: "JNE1", NewTracking: "JNE1-R", ChannelID: 2, StoreID: 3, CreatedBy: 8}}
+	rows["return_details"] = []interface{}{models.ReturnDetail{ID: 50, ReturnID: 5, ProductID: 40, Quantity: 1}}
+	rows["channels"] = []interface{}{models.Channel{ID: 2, Code: "SP"}}
+	rows["stores"] = []interface{}{models.Store{ID: 3}}
+	return rows
+}
+
+func TestReturnRepositoryFindWithDetails(t *testing.T) {
+	db, fake := newFakeDB(t, returnFixtures())
+
+	ret, err := NewReturnRepository(db).FindWithDetails(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("FindWithDetails: %v", err)
+	}
+
+	if len(ret.ReturnDetails) != 1 || ret.ReturnDetails[0].Product.ID != 40 {
+		t.Errorf("return details = %+v, want one line of product 40", ret.ReturnDetails)
+	}
+	if ret.Channel == nil || ret.Store == nil || ret.CreateOperator == nil || ret.CreateOperator.ID != 8 {
+		t.Errorf("channel, store, creator = %+v, %+v, %+v, want all loaded", ret.Channel, ret.Store, ret.CreateOperator)
+	}
+	if ret.Order == nil || ret.Order.ID != 1 {
+		t.Fatalf("order = %+v, want order 1", ret.Order)
+	}
+	if ret.Order.PickOperator == nil || len(ret.Order.PickOperator.UserRoles) != 1 {
+		t.Errorf("picker of the order = %+v, want the picker with their roles", ret.Order.PickOperator)
+	}
+	if queries := fake.queriesOn("orders"); len(queries) != 1 || !strings.Contains(queries[0], "tracking = 'JNE1'") {
+		t.Errorf("order queries = %v, want one query for the old tracking", queries)
+	}
+}
+
+func TestReturnRepositoryFindWithDetailsNotFound(t *testing.T) {
+	db, fake := newFakeDB(t, nil)
+
+	_, err := NewReturnRepository(db).FindWithDetails(context.Background(), 5)
+	if err != gorm.ErrRecordNotFound {
+		t.Fatalf("error = %v, want gorm.ErrRecordNotFound", err)
+	}
+	if queries := fake.queriesOn("orders"); len(queries) != 0 {
+		t.Errorf("order queries = %v, want none", queries)
+	}
+}
+
+func TestReturnRepositoryAttachOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		rows        map[string][]interface{}
+		oldTracking string
+		wantOrder   bool
+		wantQueries int
+	}{
+		{name: "matching order", rows: orderFixtures(), oldTracking: "JNE1", wantOrder: true, wantQueries: 1},
+		{name: "no matching order", oldTracking: "JNE9", wantQueries: 1},
+		{name: "no old tracking", rows: orderFixtures()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t, tt.rows)
+			ret := models.Return{ID: 5, OldTracking: tt.oldTracking}
+
+			if err := NewReturnRepository(db).AttachOrder(context.Background(), &ret); err != nil {
+				t.Fatalf("AttachOrder: %v", err)
+			}
+			if (ret.Order != nil) != tt.wantOrder {
+				t.Errorf("order = %+v, want order %v", ret.Order, tt.wantOrder)
+			}
+			if queries := fake.queriesOn("orders"); len(queries) != tt.wantQueries {
+				t.Errorf("order queries = %v, want %d", queries, tt.wantQueries)
+			}
+		})
+	}
+}
+
+func TestReturnRepositoryFindByOldTracking(t *testing.T) {
+	db, fake := newFakeDB(t, returnFixtures())
+
+	ret, err := NewReturnRepository(db).FindByOldTracking(context.Background(), "JNE1")
+	if err != nil {
+		t.Fatalf("FindByOldTracking: %v", err)
+	}
+	if ret.ID != 5 || ret.Channel == nil || len(ret.ReturnDetails) != 1 {
+		t.Errorf("return = %+v, want return 5 with its details and channel", ret)
+	}
+	if ret.Order != nil {
+		t.Errorf("order = %+v, want none", ret.Order)
+	}
+	if queries := fake.queriesOn("returns"); len(queries) != 1 || !strings.Contains(queries[0], "old_tracking = 'JNE1'") {
+		t.Errorf("return queries = %v, want one query for the old tracking", queries)
+	}
+}
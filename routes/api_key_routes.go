@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"net/http"
+)
+
+// apiKeyRoutes lists API key management routes (admin only)
+func apiKeyRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/api-keys", ctl.APIKey.GetAPIKeys, Admin, "Get API keys"},
+		{http.MethodPost, "/api/api-keys", ctl.APIKey.CreateAPIKey, Admin, "Create a scoped API key"},
+		{http.MethodDelete, "/api/api-keys/:id", ctl.APIKey.RevokeAPIKey, Admin, "Revoke an API key"},
+	}
+}
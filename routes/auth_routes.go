@@ -1,22 +1,22 @@
 package routes
 
 import (
-	"livo-backend/config"
-	"livo-backend/controllers"
-	"livo-backend/middleware"
-
-	"github.com/gin-gonic/gin"
+	"net/http"
 )
 
-// SetupAuthRoutes configures authentication routes
-func SetupAuthRoutes(api *gin.RouterGroup, cfg *config.Config, authController *controllers.AuthController) {
-	// Auth routes (public)
-	auth := api.Group("/auth")
-	{
-		// Public auth routes
-		auth.POST("/register", authController.Register)                             // User registration
-		auth.POST("/login", authController.Login)                                   // User login
-		auth.POST("/refresh", authController.RefreshToken)                          // Refresh access token
-		auth.POST("/logout", middleware.AuthMiddleware(cfg), authController.Logout) // User logout
+// authRoutes lists authentication routes, public except those acting on the logged-in user
+func authRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodPost, "/api/auth/register", ctl.Auth.Register, Public, "User registration"},
+		{http.MethodPost, "/api/auth/login", ctl.Auth.Login, Public, "User login"},
+		{http.MethodPost, "/api/auth/refresh", ctl.Auth.RefreshToken, Public, "Refresh access token"},
+		{http.MethodPost, "/api/auth/logout", ctl.Auth.Logout, Authenticated, "User logout"},
+
+		// Two-factor authentication routes
+		{http.MethodPost, "/api/auth/2fa/verify", ctl.Auth.VerifyTwoFactor, Public, "Complete login with a TOTP code"},
+		{http.MethodPost, "/api/auth/2fa/enroll/token", ctl.Auth.RedeemTwoFactorEnrollment, Public, "Enroll in two-factor authentication with an admin-issued enrollment token"},
+		{http.MethodPost, "/api/auth/2fa/enroll", ctl.Auth.EnrollTwoFactor, Authenticated, "Generate a TOTP secret"},
+		{http.MethodPost, "/api/auth/2fa/confirm", ctl.Auth.ConfirmTwoFactor, Authenticated, "Enable two-factor authentication"},
+		{http.MethodPost, "/api/auth/2fa/disable", ctl.Auth.DisableTwoFactor, Authenticated, "Disable two-factor authentication"},
 	}
 }
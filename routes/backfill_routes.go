@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"net/http"
+)
+
+// backfillRoutes lists data backfill routes (admin only)
+func backfillRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/backfills", ctl.Backfill.GetBackfills, Admin, "Get available backfills and recent runs"},
+		{http.MethodGet, "/api/backfills/runs/:id", ctl.Backfill.GetBackfillRun, Admin, "Get the progress of a run"},
+		{http.MethodPost, "/api/backfills/:name", ctl.Backfill.StartBackfill, Admin, "Start a backfill in the background"},
+	}
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"net/http"
+)
+
+// backupRoutes lists database backup routes (admin only)
+func backupRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/backups", ctl.Backup.GetBackups, Admin, "Get backup history"},
+		{http.MethodGet, "/api/backups/health", ctl.Backup.GetBackupHealth, Admin, "Get backup health"},
+		{http.MethodPost, "/api/backups", ctl.Backup.CreateBackup, Admin, "Trigger a backup now"},
+		{http.MethodPost, "/api/backups/:id/verify", ctl.Backup.VerifyBackup, Admin, "Restore a backup into the scratch schema"},
+	}
+}
@@ -1,24 +1,19 @@
 package routes
 
 import (
-	"livo-backend/config"
-	"livo-backend/controllers"
-	"livo-backend/middleware"
-
-	"github.com/gin-gonic/gin"
+	"net/http"
 )
 
-// SetupBoxRoutes configures box-related routes
-func SetupBoxRoutes(api *gin.RouterGroup, cfg *config.Config, boxController *controllers.BoxController) {
-	// Box routes (authenticated)
-	box := api.Group("/boxes")
-	box.Use(middleware.AuthMiddleware(cfg))
-	{
-		// Public box routes
-		box.POST("", boxController.CreateBox)       // Create new box
-		box.GET("", boxController.GetBoxes)         // Get all boxes (with optional search)
-		box.GET("/:id", boxController.GetBox)       // Get box by ID
-		box.PUT("/:id", boxController.UpdateBox)    // Update box by ID
-		box.DELETE("/:id", boxController.RemoveBox) // Delete box by ID
+// boxRoutes lists box-related routes
+func boxRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodPost, "/api/boxes", ctl.Box.CreateBox, Authenticated, "Create new box"},
+		{http.MethodGet, "/api/boxes", ctl.Box.GetBoxes, Authenticated, "Get all boxes (with optional search)"},
+		{http.MethodGet, "/api/boxes/:id", ctl.Box.GetBox, Authenticated, "Get box by ID"},
+		{http.MethodPut, "/api/boxes/:id", ctl.Box.UpdateBox, Authenticated, "Update box by ID"},
+		{http.MethodPut, "/api/boxes/:id/stock", ctl.Box.UpdateBoxStock, Authenticated, "Replenish or count box stock"},
+		{http.MethodDelete, "/api/boxes/:id", ctl.Box.RemoveBox, Authenticated, "Delete box by ID"},
+		{http.MethodGet, "/api/boxes/trash", ctl.Box.GetBoxTrash, Admin, "Get deleted boxes"},
+		{http.MethodPost, "/api/boxes/:id/restore", ctl.Box.RestoreBox, Admin, "Restore a deleted box"},
 	}
 }
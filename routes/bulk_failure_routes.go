@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"net/http"
+)
+
+// bulkFailureRoutes lists failed bulk row routes (admin and coordinator)
+func bulkFailureRoutes(ctl *Controllers) []Route {
+	staff := Roles("superadmin", "admin", "coordinator")
+	return []Route{
+		{http.MethodGet, "/api/bulk-failures", ctl.BulkFailure.GetBulkFailures, staff, "Get failed bulk rows"},
+		{http.MethodPost, "/api/bulk-failures/:id/retry", ctl.BulkFailure.RetryBulkFailure, staff, "Retry a failed bulk row"},
+	}
+}
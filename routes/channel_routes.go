@@ -1,33 +1,22 @@
 package routes
 
 import (
-	"livo-backend/config"
-	"livo-backend/controllers"
-	"livo-backend/middleware"
-
-	"github.com/gin-gonic/gin"
+	"net/http"
 )
 
-// SetupChannelRoutes configures channel-related routes
-func SetupChannelRoutes(api *gin.RouterGroup, cfg *config.Config, channelController *controllers.ChannelController) {
-	// Channel routes (authenticated)
-	channel := api.Group("/channels")
-	channel.Use(middleware.AuthMiddleware(cfg))
-	{
-		// Public channel routes
-		channel.GET("", channelController.GetChannels)          // Get all channels (with optional search)
-		channel.GET("/:id", channelController.GetChannel)       // Get channel by ID
-		channel.POST("", channelController.CreateChannel)       // Create new channel
-		channel.PUT("/:id", channelController.UpdateChannel)    // Update channel by ID
-		channel.DELETE("/:id", channelController.RemoveChannel) // Delete channel by ID
-	}
-}
+// channelRoutes lists channel-related routes
+func channelRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/channels", ctl.Channel.GetChannels, Authenticated, "Get all channels (with optional search)"},
+		{http.MethodGet, "/api/channels/:id", ctl.Channel.GetChannel, Authenticated, "Get channel by ID"},
+		{http.MethodPost, "/api/channels", ctl.Channel.CreateChannel, Authenticated, "Create new channel"},
+		{http.MethodPut, "/api/channels/:id", ctl.Channel.UpdateChannel, Authenticated, "Update channel by ID"},
+		{http.MethodDelete, "/api/channels/:id", ctl.Channel.RemoveChannel, Authenticated, "Delete channel by ID"},
+		{http.MethodPut, "/api/channels/:id/intake", ctl.Channel.UpdateChannelIntake, Coordinator, "Update channel daily quota and intake drop threshold"},
+		{http.MethodGet, "/api/channels/trash", ctl.Channel.GetChannelTrash, Admin, "Get deleted channels"},
+		{http.MethodPost, "/api/channels/:id/restore", ctl.Channel.RestoreChannel, Admin, "Restore a deleted channel"},
 
-func SetupMobileChannelRoutes(api *gin.RouterGroup, cfg *config.Config, mobileChannelController *controllers.MobileChannelController) {
-	// Mobile channel routes (public)
-	mobileChannel := api.Group("/mobile/channels")
-	{
-		// Public mobile channel routes
-		mobileChannel.GET("", mobileChannelController.GetMobileChannels) // Get all channels for mobile (with optional search)
+		// Mobile channel routes
+		{http.MethodGet, "/api/mobile/channels", ctl.MobileChannel.GetMobileChannels, Public, "Get all channels for mobile (with optional search)"},
 	}
 }
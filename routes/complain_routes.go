@@ -1,24 +1,23 @@
 package routes
 
 import (
-	"livo-backend/config"
-	"livo-backend/controllers"
-	"livo-backend/middleware"
-
-	"github.com/gin-gonic/gin"
+	"livo-backend/models"
+	"net/http"
 )
 
-// SetupComplainRoutes configures complain-related routes
-func SetupComplainRoutes(api *gin.RouterGroup, cfg *config.Config, complainController *controllers.ComplainController) {
-	// Complain routes (authenticated)
-	complain := api.Group("/complains")
-	complain.Use(middleware.AuthMiddleware(cfg))
-	{
-		// Public complain routes
-		complain.POST("", complainController.CreateComplain)                     // Create new complain
-		complain.GET("", complainController.GetComplains)                        // Get all complains (with optional search)
-		complain.GET("/:id", complainController.GetComplain)                     // Get complain by ID
-		complain.PUT("/:id/solution", complainController.UpdateSolutionComplain) // Update complain solution and total fee
-		complain.PUT("/:id/check", complainController.UpdateCheckComplain)       // Update complain checked status
+// complainRoutes lists complain-related routes
+func complainRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodPost, "/api/complains", ctl.Complain.CreateComplain, Authenticated, "Create new complain"},
+		{http.MethodGet, "/api/complains", ctl.Complain.GetComplains, Authenticated, "Get all complains (with optional search)"},
+		{http.MethodGet, "/api/complains/overdue", ctl.Complain.GetOverdueComplains, Authenticated, "Get unchecked complains past their SLA"},
+		{http.MethodGet, "/api/complains/:id", ctl.Complain.GetComplain, Authenticated, "Get complain by ID"},
+		{http.MethodGet, "/api/complains/:id/document", ctl.Complain.GetComplainDocument, Authenticated, "Print complain report (PDF) for marketplace dispute forms"},
+		{http.MethodGet, "/api/complains/:id/evidence", ctl.Complain.GetComplainEvidenceBundle, Authenticated.WithQuota(models.QuotaOperationExport), "Export evidence bundle (PDF or ZIP) for dispute appeals"},
+		{http.MethodPost, "/api/complains/:id/fee-split", ctl.Complain.SplitComplainFee, Authenticated, "Suggest fee charges per operator for a total fee"},
+		{http.MethodPut, "/api/complains/:id/solution", ctl.Complain.UpdateSolutionComplain, Authenticated, "Update complain solution and total fee"},
+		{http.MethodPut, "/api/complains/:id/check", ctl.Complain.UpdateCheckComplain, Authenticated, "Update complain checked status"},
+		{http.MethodPut, "/api/complains/:id/escalate", ctl.Complain.EscalateComplain, Authenticated, "Escalate complain to the next tier"},
+		{http.MethodPut, "/api/complains/:id/status", ctl.Complain.UpdateComplainStatus, Authenticated, "Move complain through its resolution workflow"},
 	}
 }
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"net/http"
+)
+
+// configRoutes lists runtime config routes (superadmin only)
+func configRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/admin/config", ctl.Config.GetConfig, Superadmin, "Get the reloadable settings and their overrides"},
+		{http.MethodGet, "/api/admin/config/changes", ctl.Config.GetConfigChanges, Superadmin, "Get the audit of setting overrides"},
+		{http.MethodPut, "/api/admin/config/:key", ctl.Config.UpdateConfig, Superadmin, "Override a reloadable setting"},
+		{http.MethodDelete, "/api/admin/config/:key", ctl.Config.ResetConfig, Superadmin, "Remove a setting override"},
+	}
+}
@@ -1,24 +1,19 @@
 package routes
 
 import (
-	"livo-backend/config"
-	"livo-backend/controllers"
-	"livo-backend/middleware"
-
-	"github.com/gin-gonic/gin"
+	"net/http"
 )
 
-// SetupExpeditionRoutes configures expedition-related routes
-func SetupExpeditionRoutes(api *gin.RouterGroup, cfg *config.Config, expeditionController *controllers.ExpeditionController) {
-	// Expedition routes (authenticated)
-	expedition := api.Group("/expeditions")
-	expedition.Use(middleware.AuthMiddleware(cfg))
-	{
-		// Public expedition routes
-		expedition.GET("", expeditionController.GetExpeditions)          // Get all expeditions (with optional search)
-		expedition.GET("/:id", expeditionController.GetExpedition)       // Get expedition by ID
-		expedition.POST("", expeditionController.CreateExpedition)       // Create new expedition
-		expedition.PUT("/:id", expeditionController.UpdateExpedition)    // Update expedition by ID
-		expedition.DELETE("/:id", expeditionController.RemoveExpedition) // Delete expedition by ID
+// expeditionRoutes lists expedition-related routes
+func expeditionRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/expeditions", ctl.Expedition.GetExpeditions, Authenticated, "Get all expeditions (with optional search)"},
+		{http.MethodGet, "/api/expeditions/:id", ctl.Expedition.GetExpedition, Authenticated, "Get expedition by ID"},
+		{http.MethodPost, "/api/expeditions", ctl.Expedition.CreateExpedition, Authenticated, "Create new expedition"},
+		{http.MethodPost, "/api/expeditions/match", ctl.Expedition.MatchExpedition, Authenticated, "Preview which expedition a tracking resolves to"},
+		{http.MethodPut, "/api/expeditions/:id", ctl.Expedition.UpdateExpedition, Authenticated, "Update expedition by ID"},
+		{http.MethodDelete, "/api/expeditions/:id", ctl.Expedition.RemoveExpedition, Authenticated, "Delete expedition by ID"},
+		{http.MethodGet, "/api/expeditions/trash", ctl.Expedition.GetExpeditionTrash, Admin, "Get deleted expeditions"},
+		{http.MethodPost, "/api/expeditions/:id/restore", ctl.Expedition.RestoreExpedition, Admin, "Restore a deleted expedition"},
 	}
 }
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"livo-backend/config"
+	"livo-backend/models"
+	"net/http"
+)
+
+// graphQLRoutes lists the GraphQL gateway routes for dashboard queries, none when the gateway is
+// disabled. Queries count as report requests.
+func graphQLRoutes(cfg *config.Config, ctl *Controllers) []Route {
+	if !cfg.GraphQLEnabled {
+		return nil
+	}
+
+	report := Authenticated.WithQuota(models.QuotaOperationReport)
+	return []Route{
+		{http.MethodGet, "/graphql/schema", ctl.GraphQL.GetSchema, Authenticated, "Get the query fields"},
+		{http.MethodPost, "/graphql", ctl.GraphQL.Query, report, "Run a read-only query"},
+		{http.MethodGet, "/graphql", ctl.GraphQL.Query, report, "Run a read-only query passed as ?query="},
+	}
+}
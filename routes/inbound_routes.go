@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"net/http"
+)
+
+// inboundRoutes lists supplier shipment routes
+func inboundRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/inbounds", ctl.Inbound.GetInbounds, Authenticated, "Get all inbounds (with optional search, status and date filtering)"},
+		{http.MethodGet, "/api/inbounds/:id", ctl.Inbound.GetInbound, Authenticated, "Get inbound by ID"},
+		{http.MethodPost, "/api/inbounds", ctl.Inbound.CreateInbound, Coordinator, "Announce a supplier shipment"},
+		{http.MethodPut, "/api/inbounds/:id", ctl.Inbound.UpdateInbound, Coordinator, "Update an inbound before receiving starts"},
+		{http.MethodDelete, "/api/inbounds/:id", ctl.Inbound.DeleteInbound, Coordinator, "Delete an inbound before receiving starts"},
+	}
+}
+
+// mobileInboundRoutes lists mobile receiving routes
+func mobileInboundRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/mobile/inbounds", ctl.MobileInbound.GetMobileInbounds, Authenticated, "Get inbounds still to be received"},
+		{http.MethodGet, "/api/mobile/inbounds/:id/checklist", ctl.MobileInbound.GetInboundChecklist, Authenticated, "Get the receiving checklist of an inbound"},
+		{http.MethodPut, "/api/mobile/inbounds/:id/checklist", ctl.MobileInbound.CheckInboundItems, Authenticated, "Record counted lines and optionally complete receiving"},
+	}
+}
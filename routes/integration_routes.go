@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"net/http"
+)
+
+// integrationRoutes lists marketplace integration routes (admin only)
+func integrationRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/integrations/ginee/runs", ctl.Ginee.GetGineeRuns, Admin, "Get Ginee order intake runs"},
+		{http.MethodPost, "/api/integrations/ginee/runs", ctl.Ginee.TriggerGineeSync, Admin, "Poll Ginee for paid orders now"},
+	}
+}
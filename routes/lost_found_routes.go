@@ -1,24 +1,17 @@
 package routes
 
 import (
-	"livo-backend/config"
-	"livo-backend/controllers"
-	"livo-backend/middleware"
-
-	"github.com/gin-gonic/gin"
+	"net/http"
 )
 
-// SetupLostFoundRoutes configures lost and found related routes
-func SetupLostFoundRoutes(api *gin.RouterGroup, cfg *config.Config, lostFoundController *controllers.LostFoundController) {
-	// Lost and found routes (authenticated)
-	lostFound := api.Group("/lost-founds")
-	lostFound.Use(middleware.AuthMiddleware(cfg))
-	{
-		// Public lost and found routes
-		lostFound.GET("/", lostFoundController.GetLostFounds)
-		lostFound.GET("/:id", lostFoundController.GetLostFound)
-		lostFound.POST("/", lostFoundController.CreateLostFound)
-		lostFound.PUT("/:id", lostFoundController.UpdateLostFound)
-		lostFound.DELETE("/:id", lostFoundController.RemoveLostFound)
+// lostFoundRoutes lists lost and found routes
+func lostFoundRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/lost-founds/", ctl.LostFound.GetLostFounds, Authenticated, "Get all lost and found items"},
+		{http.MethodGet, "/api/lost-founds/:id", ctl.LostFound.GetLostFound, Authenticated, "Get lost and found item by ID"},
+		{http.MethodPost, "/api/lost-founds/", ctl.LostFound.CreateLostFound, Authenticated, "Create new lost and found item"},
+		{http.MethodPut, "/api/lost-founds/:id", ctl.LostFound.UpdateLostFound, Authenticated, "Update lost and found item by ID"},
+		{http.MethodPut, "/api/lost-founds/:id/resolve", ctl.LostFound.ResolveLostFound, Coordinator, "Resolve a lost and found item as found or written off"},
+		{http.MethodDelete, "/api/lost-founds/:id", ctl.LostFound.RemoveLostFound, Authenticated, "Delete lost and found item by ID"},
 	}
 }
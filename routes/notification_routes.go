@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"net/http"
+)
+
+// notificationRoutes lists notification routes
+func notificationRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/notifications", ctl.Notification.GetNotifications, Authenticated, "Get my notifications"},
+		{http.MethodPut, "/api/notifications/:id/read", ctl.Notification.MarkNotificationRead, Authenticated, "Mark notification as read"},
+	}
+}
@@ -1,35 +1,26 @@
 package routes
 
 import (
-	"livo-backend/config"
-	"livo-backend/controllers"
-	"livo-backend/middleware"
-
-	"github.com/gin-gonic/gin"
+	"net/http"
 )
 
-// SetupQcOnlineRoutes configures qc-online-related routes
-func SetupQcOnlineRoutes(api *gin.RouterGroup, cfg *config.Config, qcOnlineController *controllers.QcOnlineController) {
-	// Qc-Online routes (authenticated)
-	qcOnline := api.Group("/onlines/qc-onlines")
-	qcOnline.Use(middleware.AuthMiddleware(cfg))
-	{
-		// Public qc-online routes
-		qcOnline.GET("", qcOnlineController.GetQcOnlines)            // Get all qc-onlines (with optional search and date filtering)
-		qcOnline.GET("/:id", qcOnlineController.GetQcOnline)         // Get qc-online by ID
-		qcOnline.POST("", qcOnlineController.CreateQcOnline)         // Create new qc-online
-		qcOnline.GET("/chart", qcOnlineController.GetChartQcOnlines) // Get qc-online counts per day for current month
+// onlineRoutes lists qc-online and online flow routes
+func onlineRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/onlines/qc-onlines", ctl.QcOnline.GetQcOnlines, Authenticated, "Get all qc-onlines (with optional search and date filtering)"},
+		{http.MethodGet, "/api/onlines/qc-onlines/:id", ctl.QcOnline.GetQcOnline, Authenticated, "Get qc-online by ID"},
+		{http.MethodPost, "/api/onlines/qc-onlines", ctl.QcOnline.CreateQcOnline, Authenticated, "Create new qc-online"},
+		{http.MethodGet, "/api/onlines/qc-onlines/chart", ctl.QcOnline.GetChartQcOnlines, Authenticated, "Get qc-online counts per day for current month"},
+
+		// Online flow routes
+		{http.MethodGet, "/api/onlines/online-flows", ctl.OnlineFlow.GetOnlineFlows, Authenticated, "Get all online flows (with optional search and date filtering)"},
+		{http.MethodGet, "/api/onlines/online-flows/:tracking", ctl.OnlineFlow.GetOnlineFlow, Authenticated, "Get online flow by tracking number"},
 	}
 }
 
-// SetupOnlineFlowRoutes configures online-flow-related routes
-func SetupOnlineFlowRoutes(api *gin.RouterGroup, cfg *config.Config, onlineFlowController *controllers.OnlineFlowController) {
-	// Online-flow routes (authenticated)
-	onlineFlow := api.Group("/onlines/online-flows")
-	onlineFlow.Use(middleware.AuthMiddleware(cfg))
-	{
-		// Public online-flow routes
-		onlineFlow.GET("", onlineFlowController.GetOnlineFlows)          // Get all online flows (with optional search and date filtering)
-		onlineFlow.GET("/:tracking", onlineFlowController.GetOnlineFlow) // Get online flow by tracking number
+// mobileQcRoutes lists mobile QC routes
+func mobileQcRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodPost, "/api/mobile/qc", ctl.MobileQc.VerifyQcItems, Authenticated, "Verify the parcel contents against the order details"},
 	}
 }
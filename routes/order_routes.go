@@ -1,64 +1,59 @@
 package routes
 
 import (
-	"livo-backend/config"
-	"livo-backend/controllers"
-	"livo-backend/middleware"
-
-	"github.com/gin-gonic/gin"
+	"livo-backend/models"
+	"net/http"
 )
 
-// SetupOrderRoutes configures order-related routes
-func SetupOrderRoutes(api *gin.RouterGroup, cfg *config.Config, orderController *controllers.OrderController) {
-	// Order routes (authenticated)
-	order := api.Group("/orders")
-	order.Use(middleware.AuthMiddleware(cfg))
-	{
-		// Public order routes
-		order.GET("", orderController.GetOrders)                                         // Get all orders (with optional search and date filtering)
-		order.GET("/:id", orderController.GetOrder)                                      // Get specific order by ID (full details)
-		order.POST("/bulk", orderController.BulkCreateOrders)                            // Create multiple orders
-		order.PUT("/:id", orderController.UpdateOrder)                                   // Update order details
-		order.PUT("/:id/complained", orderController.UpdateOrderComplainedStatus)        // Update order complained status
-		order.PUT("/:id/qc-process", orderController.QCProcessStatusOrder)               // Update order QC process status
-		order.PUT("/:id/picking-completed", orderController.PickingCompletedStatusOrder) // Update order picking complete
-	}
+// orderRoutes lists order-related routes
+func orderRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/orders", ctl.Order.GetOrders, Authenticated, "Get all orders (with optional search and date filtering)"},
+		{http.MethodGet, "/api/orders/status-summary", ctl.Order.GetOrderStatusSummary, Authenticated, "Get order counts per processing and event status for dashboard tiles"},
+		{http.MethodGet, "/api/orders/:id", ctl.Order.GetOrder, Authenticated, "Get specific order by ID (full details)"},
+		{http.MethodGet, "/api/orders/:id/delivery-history", ctl.Order.GetOrderDeliveryHistory, Authenticated, "Get courier delivery history of an order"},
+		{http.MethodGet, "/api/orders/:id/history", ctl.Order.GetOrderHistory, Authenticated, "Get edit history of an order (changed fields and details)"},
+		{http.MethodGet, "/api/orders/:id/revisions", ctl.Order.GetOrderRevisions, Authenticated, "Get stored copies of an order taken before updates, cancellations and duplications"},
+		{http.MethodGet, "/api/orders/:id/revisions/diff", ctl.Order.GetOrderRevisionDiff, Authenticated, "Compare two stored copies of an order, or one with the current order"},
+		{http.MethodGet, "/api/orders/:id/timeline", ctl.Order.GetOrderTimeline, Authenticated, "Get all workflow events of an order in chronological order"},
+		{http.MethodGet, "/api/orders/:id/label", ctl.Order.GetOrderLabel, Authenticated, "Reprint shipping label (pdf or zpl)"},
+		{http.MethodPost, "/api/orders/bulk", ctl.Order.BulkCreateOrders, Authenticated.WithQuota(models.QuotaOperationBulkImport), "Create multiple orders"},
+		{http.MethodPut, "/api/orders/:id", ctl.Order.UpdateOrder, Authenticated, "Update order details"},
+		{http.MethodPatch, "/api/orders/:id", ctl.Order.PatchOrder, Authenticated, "Partially update an order, adding, updating or removing details explicitly"},
+		{http.MethodPut, "/api/orders/:id/complained", ctl.Order.UpdateOrderComplainedStatus, Authenticated, "Update order complained status"},
+		{http.MethodPut, "/api/orders/:id/qc-process", ctl.Order.QCProcessStatusOrder, Authenticated, "Update order QC process status"},
+		{http.MethodPut, "/api/orders/:id/picking-completed", ctl.Order.PickingCompletedStatusOrder, Authenticated, "Update order picking complete"},
+		{http.MethodPost, "/api/orders/:id/contact/reveal", ctl.Order.RevealOrderContact, Authenticated, "Reveal the unmasked buyer contact (audited)"},
 
-	// Order management routes (admin only)
-	order.Use(middleware.RequireAdminRoles())
-	{
-		order.POST("/:id/duplicate", orderController.DuplicateOrder) // Duplicate an order
-		order.PUT("/:id/cancel", orderController.CancelOrder)        // Cancel an order
-	}
-
-	// Order management routes (coordinator only)
-	orderCoordinator := api.Group("/orders")
-	orderCoordinator.Use(middleware.AuthMiddleware(cfg))
-	orderCoordinator.Use(middleware.RequireCoordinatorRoles())
-	{
-		orderCoordinator.PUT("/:id/pending-pick", orderController.PendingPickOrders) // Pending an picked orders
-		orderCoordinator.GET("/assigned", orderController.GetAssignedOrders)         // Get all assigned orders for current date
-		orderCoordinator.POST("/assign-picker", orderController.AssignPicker)        // Assign picker to order
+		// Order management routes
+		{http.MethodPost, "/api/orders/:id/duplicate", ctl.Order.DuplicateOrder, Admin, "Duplicate an order"},
+		{http.MethodPut, "/api/orders/:id/cancel", ctl.Order.CancelOrder, Admin, "Cancel an order"},
+		{http.MethodPost, "/api/orders/merge", ctl.Order.MergeOrders, Admin, "Merge an order resent under another ID into the canonical order"},
+		{http.MethodGet, "/api/orders/contact-reveals", ctl.Order.GetContactReveals, Admin, "Get contact reveal log"},
+		{http.MethodPost, "/api/orders/renumber-trackings", ctl.Order.RenumberTrackings, Admin, "Change order trackings together with every record referencing them"},
+		{http.MethodDelete, "/api/orders/:id", ctl.Order.DeleteOrder, Superadmin, "Soft delete an order"},
+		{http.MethodPost, "/api/orders/:id/restore", ctl.Order.RestoreOrder, Superadmin, "Restore a soft deleted order"},
+		{http.MethodPut, "/api/orders/:id/pending-pick", ctl.Order.PendingPickOrders, Coordinator, "Pending an picked orders"},
+		{http.MethodPut, "/api/orders/:id/hold", ctl.Order.HoldOrder, Coordinator, "Put an order on hold, blocking assignment, QC and outbound"},
+		{http.MethodPut, "/api/orders/:id/unhold", ctl.Order.UnholdOrder, Coordinator, "Release an order from hold"},
+		{http.MethodGet, "/api/orders/assigned", ctl.Order.GetAssignedOrders, Coordinator, "Get all assigned orders for current date"},
+		{http.MethodPost, "/api/orders/assign-picker", ctl.Order.AssignPicker, Coordinator, "Assign picker to order"},
+		{http.MethodPost, "/api/orders/auto-assign", ctl.Order.AutoAssignOrders, Coordinator, "Propose or apply an assignment of ready to pick orders to active pickers"},
 	}
 }
 
-// SetupMobileOrderRoutes configures mobile order-related routes
-func SetupMobileOrderRoutes(api *gin.RouterGroup, cfg *config.Config, mobileOrderController *controllers.MobileOrderController) {
-	// Mobile order routes (authenticated)
-	mobileOrder := api.Group("/mobile/orders")
-	mobileOrder.Use(middleware.AuthMiddleware(cfg))
-	{
-		// Mobile order routes
-		mobileOrder.GET("", mobileOrderController.GetMyPickingOrders)                // Get my ongoing picking orders
-		mobileOrder.GET(":id", mobileOrderController.GetMyPickingOrder)              // Get my ongoing picking order
-		mobileOrder.PUT(":id/pending-pick", mobileOrderController.PendingPickOrders) // Pending picking order
-		mobileOrder.PUT(":id/complete", mobileOrderController.CompletePickingOrder)  // Complete order
-	}
-	mobileOrderCoordinator := api.Group("/mobile/orders")
-	mobileOrderCoordinator.Use(middleware.AuthMiddleware(cfg))
-	mobileOrderCoordinator.Use(middleware.RequireCoordinatorRoles())
-	{
-		mobileOrderCoordinator.POST("/bulk-assign-picker", mobileOrderController.BulkAssignPicker) // Bulk assign pickers to orders
-		mobileOrderCoordinator.GET("/picked-orders", mobileOrderController.GetMobilePickedOrders)  // Get picked orders for coordinator
+// mobileOrderRoutes lists mobile picking routes
+func mobileOrderRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/mobile/dashboard", ctl.MobileOrder.GetPickerDashboard, Authenticated, "Get my picking numbers for today (assigned, completed, pending, pick time, streak)"},
+		{http.MethodGet, "/api/mobile/orders", ctl.MobileOrder.GetMyPickingOrders, Authenticated, "Get my ongoing picking orders"},
+		{http.MethodPost, "/api/mobile/orders/grab-next", ctl.MobileOrder.GrabNextOrder, Authenticated, "Grab the next order to pick (zone aware)"},
+		{http.MethodGet, "/api/mobile/orders/:id", ctl.MobileOrder.GetMyPickingOrder, Authenticated, "Get my ongoing picking order"},
+		{http.MethodPut, "/api/mobile/orders/:id/pending-pick", ctl.MobileOrder.PendingPickOrders, Authenticated, "Pending picking order"},
+		{http.MethodPut, "/api/mobile/orders/:id/complete", ctl.MobileOrder.CompletePickingOrder, Authenticated, "Complete order"},
+		{http.MethodPut, "/api/mobile/orders/:id/details/:detailId/scan", ctl.MobileOrder.ScanOrderDetail, Authenticated, "Scan a product barcode for an order line"},
+		{http.MethodPost, "/api/mobile/orders/bulk-assign-picker", ctl.MobileOrder.BulkAssignPicker, Coordinator, "Bulk assign pickers to orders"},
+		{http.MethodGet, "/api/mobile/orders/picked-orders", ctl.MobileOrder.GetMobilePickedOrders, Coordinator, "Get picked orders for coordinator"},
+		{http.MethodPost, "/api/mobile/sync", ctl.MobileOrder.SyncMutations, Authenticated, "Apply mutations queued offline"},
 	}
 }
@@ -1,24 +1,22 @@
 package routes
 
 import (
-	"livo-backend/config"
-	"livo-backend/controllers"
-	"livo-backend/middleware"
-
-	"github.com/gin-gonic/gin"
+	"livo-backend/models"
+	"net/http"
 )
 
-// SetupOutboundRoutes configures outbound-related routes
-func SetupOutboundRoutes(api *gin.RouterGroup, cfg *config.Config, outboundController *controllers.OutboundController) {
-	// Outbound routes (authenticated)
-	outbound := api.Group("/outbounds")
-	outbound.Use(middleware.AuthMiddleware(cfg))
-	{
-		// Public outbound routes
-		outbound.GET("", outboundController.GetOutbounds)            // Get all outbounds (with optional search)
-		outbound.GET("/:id", outboundController.GetOutbound)         // Get outbound by ID
-		outbound.POST("", outboundController.CreateOutbound)         // Create new outbound
-		outbound.PUT("/:id", outboundController.UpdateOutbound)      // Update outbound by ID
-		outbound.GET("/chart", outboundController.GetChartOutbounds) // Get outbound counts per day for current month
+// outboundRoutes lists outbound-related routes
+func outboundRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/outbounds", ctl.Outbound.GetOutbounds, Authenticated, "Get all outbounds (with optional search)"},
+		{http.MethodGet, "/api/outbounds/:id", ctl.Outbound.GetOutbound, Authenticated, "Get outbound by ID"},
+		{http.MethodPost, "/api/outbounds", ctl.Outbound.CreateOutbound, Authenticated, "Create new outbound"},
+		{http.MethodPost, "/api/outbounds/bulk", ctl.Outbound.BulkCreateOutbounds, Authenticated.WithQuota(models.QuotaOperationBulkImport), "Create outbounds for a burst of scanned trackings"},
+		{http.MethodPut, "/api/outbounds/:id", ctl.Outbound.UpdateOutbound, Authenticated, "Update outbound by ID"},
+		{http.MethodGet, "/api/outbounds/chart", ctl.Outbound.GetChartOutbounds, Authenticated, "Get outbound counts per day for current month"},
+
+		// Duplicate parcel alert routes
+		{http.MethodGet, "/api/outbounds/alerts", ctl.Outbound.GetOutboundAlerts, Coordinator, "Get duplicate parcel alerts"},
+		{http.MethodPut, "/api/outbounds/alerts/:id/resolve", ctl.Outbound.ResolveOutboundAlert, Coordinator, "Override or dismiss an alert"},
 	}
 }
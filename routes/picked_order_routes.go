@@ -1,21 +1,13 @@
 package routes
 
 import (
-	"livo-backend/config"
-	"livo-backend/controllers"
-	"livo-backend/middleware"
-
-	"github.com/gin-gonic/gin"
+	"net/http"
 )
 
-// SetupPickedOrderRoutes configures pick order-related routes
-func SetupPickedOrderRoutes(api *gin.RouterGroup, cfg *config.Config, pickedOrderController *controllers.PickedOrderController) {
-	// Pick Order routes (authenticated)
-	pickedOrders := api.Group("/picked-orders")
-	pickedOrders.Use(middleware.AuthMiddleware(cfg))
-	{
-		// Public pick order routes
-		pickedOrders.GET("", pickedOrderController.GetPickedOrders) // Get all pick orders (with optional search and date filtering)
-		pickedOrders.GET("/:id", pickedOrderController.GetPickedOrder)
+// pickedOrderRoutes lists picked order routes
+func pickedOrderRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/picked-orders", ctl.PickedOrder.GetPickedOrders, Authenticated, "Get all pick orders (with optional search and date filtering)"},
+		{http.MethodGet, "/api/picked-orders/:id", ctl.PickedOrder.GetPickedOrder, Authenticated, "Get pick order by ID"},
 	}
 }
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"net/http"
+)
+
+// pickupRoutes lists courier pickup scheduling routes
+func pickupRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/pickups", ctl.Pickup.GetPickupSlots, Authenticated, "Get daily courier pickup slots"},
+		{http.MethodGet, "/api/pickups/today", ctl.Pickup.GetTodayPickups, Authenticated, "Get today's pickups with their fill rate"},
+
+		// Pickup slot management routes
+		{http.MethodPost, "/api/pickups", ctl.Pickup.CreatePickupSlot, Coordinator, "Create pickup slot"},
+		{http.MethodPut, "/api/pickups/:id", ctl.Pickup.UpdatePickupSlot, Coordinator, "Update pickup slot by ID"},
+		{http.MethodDelete, "/api/pickups/:id", ctl.Pickup.RemovePickupSlot, Coordinator, "Delete pickup slot by ID"},
+	}
+}
@@ -1,30 +1,21 @@
 package routes
 
 import (
-	"livo-backend/config"
-	"livo-backend/controllers"
-	"livo-backend/middleware"
-
-	"github.com/gin-gonic/gin"
+	"net/http"
 )
 
-// SetupProductRoutes configures product-related routes
-func SetupProductRoutes(api *gin.RouterGroup, cfg *config.Config, productController *controllers.ProductController) {
-	// Product routes (authenticated)
-	product := api.Group("/products")
-	product.Use(middleware.AuthMiddleware(cfg))
-	{
-		// Public product routes
-		product.GET("", productController.GetProducts)    // Get all products (with optional search)
-		product.GET("/:id", productController.GetProduct) // Get product by ID
+// productRoutes lists product-related routes
+func productRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/products", ctl.Product.GetProducts, Authenticated, "Get all products (with optional search)"},
+		{http.MethodGet, "/api/products/changes", ctl.Product.GetProductChanges, Authenticated, "Get products changed since a cursor"},
+		{http.MethodGet, "/api/products/:id", ctl.Product.GetProduct, Authenticated, "Get product by ID"},
 
-		// Admin product management routes (coordinator roles)
-		productAdmin := product.Group("")
-		productAdmin.Use(middleware.RequireCoordinatorRoles())
-		{
-			productAdmin.POST("", productController.CreateProduct)       // Create new product
-			productAdmin.PUT("/:id", productController.UpdateProduct)    // Update product by ID
-			productAdmin.DELETE("/:id", productController.RemoveProduct) // Delete product by ID
-		}
+		// Product management routes
+		{http.MethodPost, "/api/products", ctl.Product.CreateProduct, Coordinator, "Create new product"},
+		{http.MethodPost, "/api/products/sync", ctl.Product.SyncProducts, Coordinator, "Upsert a batch of products by SKU"},
+		{http.MethodPut, "/api/products/:id", ctl.Product.UpdateProduct, Coordinator, "Update product by ID"},
+		{http.MethodPost, "/api/products/:id/image", ctl.Product.UploadProductImage, Coordinator, "Upload product image and generate its thumbnail"},
+		{http.MethodDelete, "/api/products/:id", ctl.Product.RemoveProduct, Coordinator, "Delete product by ID"},
 	}
 }
@@ -0,0 +1,169 @@
+package routes
+
+import (
+	"livo-backend/config"
+	"livo-backend/controllers"
+	"livo-backend/middleware"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Controllers are the controllers serving the routes
+type Controllers struct {
+	Auth          *controllers.AuthController
+	UserManager   *controllers.UserManagerController
+	Box           *controllers.BoxController
+	Channel       *controllers.ChannelController
+	MobileChannel *controllers.MobileChannelController
+	Expedition    *controllers.ExpeditionController
+	Product       *controllers.ProductController
+	Store         *controllers.StoreController
+	MobileStore   *controllers.MobileStoreController
+	QcRibbon      *controllers.QcRibbonController
+	RibbonFlow    *controllers.RibbonFlowController
+	QcOnline      *controllers.QcOnlineController
+	OnlineFlow    *controllers.OnlineFlowController
+	Outbound      *controllers.OutboundController
+	Return        *controllers.ReturnController
+	MobileReturn  *controllers.MobileReturnController
+	Complain      *controllers.ComplainController
+	Order         *controllers.OrderController
+	MobileOrder   *controllers.MobileOrderController
+	User          *controllers.UserController
+	LostFound     *controllers.LostFoundController
+	Report        *controllers.ReportController
+	PickedOrder   *controllers.PickedOrderController
+	Notification  *controllers.NotificationController
+	Zone          *controllers.ZoneController
+	Backup        *controllers.BackupController
+	Backfill      *controllers.BackfillController
+	APIKey        *controllers.APIKeyController
+	BulkFailure   *controllers.BulkFailureController
+	GraphQL       *controllers.GraphQLController
+	ReportPreset  *controllers.ReportPresetController
+	Inbound       *controllers.InboundController
+	MobileInbound *controllers.MobileInboundController
+	MobileQc      *controllers.MobileQcController
+	Ginee         *controllers.GineeController
+	Pickup        *controllers.PickupController
+	RoleTemplate  *controllers.RoleTemplateController
+	Config        *controllers.ConfigController
+}
+
+// Permission is what a request needs to reach a route
+type Permission struct {
+	Public bool     // No login needed
+	Roles  []string // Roles allowed, any logged-in user when empty
+	Quota  string   // Daily quota operation the request counts against, none when empty
+}
+
+// Permissions used by the route tables
+var (
+	Public        = Permission{Public: true}
+	Authenticated = Permission{}
+	Coordinator   = Roles(middleware.CoordinatorRoles...)
+	Admin         = Roles(middleware.AdminRoles...)
+	Finance       = Roles(middleware.FinanceRoles...)
+	Superadmin    = Roles("superadmin")
+)
+
+// Roles is the permission of logged-in users having any of roles
+func Roles(roles ...string) Permission {
+	return Permission{Roles: roles}
+}
+
+// WithQuota returns the permission counting requests against the daily quota of operation
+func (p Permission) WithQuota(operation string) Permission {
+	p.Quota = operation
+	return p
+}
+
+// Route is an endpoint with the permission it requires
+type Route struct {
+	Method      string
+	Path        string
+	Handler     gin.HandlerFunc
+	Permission  Permission
+	Description string
+}
+
+// RouteInfo is a registered route in the permission matrix
+type RouteInfo struct {
+	Method        string   `json:"method" example:"GET"`
+	Path          string   `json:"path" example:"/api/orders"`
+	Authenticated bool     `json:"authenticated"`
+	Roles         []string `json:"roles"` // Any logged-in user when empty
+	Quota         string   `json:"quota" example:"report"`
+	Handler       string   `json:"handler" example:"OrderController.GetOrders"`
+	Description   string   `json:"description" example:"Get all orders (with optional search and date filtering)"`
+}
+
+// Registry mounts routes behind the middleware their permission requires and keeps the permission
+// matrix of every route mounted
+type Registry struct {
+	cfg    *config.Config
+	routes []RouteInfo
+}
+
+// NewRegistry creates a new route registry
+func NewRegistry(cfg *config.Config) *Registry {
+	return &Registry{cfg: cfg}
+}
+
+// Mount registers routes on router. Logged-in routes check the token first, then the roles, then
+// the daily quota.
+func (r *Registry) Mount(router gin.IRoutes, routes []Route) {
+	for _, route := range routes {
+		var handlers []gin.HandlerFunc
+		if !route.Permission.Public {
+			handlers = append(handlers, middleware.AuthMiddleware(r.cfg))
+			if len(route.Permission.Roles) > 0 {
+				handlers = append(handlers, middleware.RequireRoles(route.Permission.Roles...))
+			}
+			if route.Permission.Quota != "" {
+				handlers = append(handlers, middleware.RequireQuota(route.Permission.Quota))
+			}
+		}
+		router.Handle(route.Method, route.Path, append(handlers, route.Handler)...)
+
+		roles := route.Permission.Roles
+		if roles == nil {
+			roles = []string{}
+		}
+		r.routes = append(r.routes, RouteInfo{
+			Method:        route.Method,
+			Path:          route.Path,
+			Authenticated: !route.Permission.Public,
+			Roles:         roles,
+			Quota:         route.Permission.Quota,
+			Handler:       handlerName(route.Handler),
+			Description:   route.Description,
+		})
+	}
+}
+
+// Routes returns the permission matrix sorted by path and method
+func (r *Registry) Routes() []RouteInfo {
+	routes := make([]RouteInfo, len(r.routes))
+	copy(routes, r.routes)
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// handlerName returns the controller and method name of a handler, e.g. OrderController.GetOrders
+func handlerName(handler gin.HandlerFunc) string {
+	name := runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	name = strings.NewReplacer("(*", "", ")", "", "-fm", "").Replace(name)
+	_, name, _ = strings.Cut(name, ".")
+	return name
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"livo-backend/models"
+	"net/http"
+)
+
+// reportPresetRoutes lists report preset routes, each user manages their own presets
+func reportPresetRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/report-presets", ctl.ReportPreset.GetReportPresets, Authenticated, "Get presets of the current user"},
+		{http.MethodPost, "/api/report-presets", ctl.ReportPreset.CreateReportPreset, Authenticated, "Save a preset, optionally scheduled"},
+		{http.MethodGet, "/api/report-presets/:id", ctl.ReportPreset.GetReportPreset, Authenticated, "Get preset by ID"},
+		{http.MethodPut, "/api/report-presets/:id", ctl.ReportPreset.UpdateReportPreset, Authenticated, "Update preset by ID"},
+		{http.MethodDelete, "/api/report-presets/:id", ctl.ReportPreset.DeleteReportPreset, Authenticated, "Delete preset by ID"},
+		{http.MethodGet, "/api/report-presets/:id/apply", ctl.ReportPreset.ApplyReportPreset, Authenticated, "Get the target request of a preset applied today"},
+		{http.MethodGet, "/api/report-presets/:id/export", ctl.ReportPreset.ExportReportPreset, Authenticated.WithQuota(models.QuotaOperationExport), "Download the preset's report as a spreadsheet"},
+	}
+}
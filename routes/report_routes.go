@@ -1,24 +1,44 @@
 package routes
 
 import (
-	"livo-backend/config"
-	"livo-backend/controllers"
-	"livo-backend/middleware"
-
-	"github.com/gin-gonic/gin"
+	"livo-backend/models"
+	"net/http"
 )
 
-// SetupReportRoutes configures report-related routes
-func SetupReportRoutes(api *gin.RouterGroup, cfg *config.Config, reportController *controllers.ReportController) {
-	// Report routes (authenticated)
-	report := api.Group("/reports")
-	report.Use(middleware.AuthMiddleware(cfg))
-	{
-		// Public report routes
-		report.GET("/boxes-count", reportController.GetBoxReports)            // Get box count reports
-		report.GET("/handout-outbounds", reportController.GetOutboundReports) // Get handout outbound reports
-		report.GET("/handout-returns", reportController.GetReturnReports)     // Get return reports
-		report.GET("/handout-complains", reportController.GetComplainReports) // Get handout complain reports
-		report.GET("/user-fees", reportController.GetUserFeeReports)          // Get user fee reports
+// reportRoutes lists report routes, each request counts against the report quota
+func reportRoutes(ctl *Controllers) []Route {
+	report := Authenticated.WithQuota(models.QuotaOperationReport)
+	finance := Finance.WithQuota(models.QuotaOperationReport)
+	coordinator := Coordinator.WithQuota(models.QuotaOperationReport)
+
+	return []Route{
+		{http.MethodGet, "/api/reports/boxes-count", ctl.Report.GetBoxReports, report, "Get box count reports"},
+		{http.MethodGet, "/api/reports/handout-outbounds", ctl.Report.GetOutboundReports, report, "Get handout outbound reports"},
+		{http.MethodGet, "/api/reports/handout-returns", ctl.Report.GetReturnReports, report, "Get return reports"},
+		{http.MethodGet, "/api/reports/handout-complains", ctl.Report.GetComplainReports, report, "Get handout complain reports"},
+		{http.MethodGet, "/api/reports/user-fees", ctl.Report.GetUserFeeReports, report, "Get user fee reports"},
+		{http.MethodGet, "/api/reports/complain-escalations", ctl.Report.GetComplainEscalationReports, report, "Get complain escalation reports"},
+		{http.MethodGet, "/api/reports/daily-summary", ctl.Report.GetDailySummary, report, "Get daily summary (orders, QC, outbounds, returns, complains)"},
+		{http.MethodGet, "/api/reports/box-suggestions", ctl.Report.GetBoxSuggestionReport, report, "Get box suggestion acceptance rate"},
+		{http.MethodGet, "/api/reports/channel-intake", ctl.Report.GetChannelIntakeReport, report, "Get orders per channel today against quota and baseline"},
+		{http.MethodGet, "/api/reports/box-forecast", ctl.Report.GetBoxForecastReport, report, "Get box stock and projected run out dates"},
+		{http.MethodGet, "/api/reports/qc-dashboard", ctl.Report.GetQcDashboard, report, "Get today's QC counts per operator and the QC backlog"},
+		{http.MethodGet, "/api/reports/returns-aging", ctl.Report.GetReturnsAgingReport, report, "Get open returns by age and disposition, per channel and store"},
+		{http.MethodGet, "/api/reports/cycle-times", ctl.Report.GetCycleTimesReport, report, "Get stage durations from order creation to outbound"},
+		{http.MethodGet, "/api/reports/destinations", ctl.Report.GetDestinationsReport, report, "Get orders per shipping province and city"},
+
+		// Finance report routes
+		{http.MethodGet, "/api/reports/return-valuation", ctl.Report.GetReturnValuationReport, finance, "Get restocked and scrapped return values per period"},
+		{http.MethodGet, "/api/reports/packing-billing", ctl.Report.GetPackingBillingReport, finance, "Get packing material cost per store for intercompany billing"},
+		{http.MethodGet, "/api/reports/complain-fees", ctl.Report.GetComplainFeeReport, finance, "Get complain fees per operator for a month, CSV for payroll"},
+		{http.MethodPost, "/api/reports/complain-fees/approve", ctl.Report.ApproveComplainFees, finance, "Approve or withdraw the complain fees of operators for a month"},
+
+		// Coordinator report routes
+		{http.MethodGet, "/api/reports/picker-productivity", ctl.Report.GetPickerProductivityReport, coordinator, "Get picker speed, error rate and urgent order weight"},
+		{http.MethodGet, "/api/reports/order-events", ctl.Report.GetOrderEventsReport, coordinator, "Get changed, duplicated, cancelled and merged orders per event and operator"},
+		{http.MethodGet, "/api/reports/operator-fault-scores", ctl.Report.GetOperatorFaultScoresReport, coordinator, "Get complains linked to each operator per parcels handled, by week"},
+
+		// Flow export routes
+		{http.MethodGet, "/api/flows/export", ctl.Report.ExportFlows, Coordinator.WithQuota(models.QuotaOperationExport), "Export pick, QC and outbound flows for a date range as CSV"},
 	}
 }
@@ -1,36 +1,29 @@
 package routes
 
 import (
-	"livo-backend/config"
-	"livo-backend/controllers"
-	"livo-backend/middleware"
-
-	"github.com/gin-gonic/gin"
+	"net/http"
 )
 
-// SetupReturnRoutes configures return-related routes
-func SetupReturnRoutes(api *gin.RouterGroup, cfg *config.Config, returnController *controllers.ReturnController) {
-	// Return routes (authenticated)
-	returns := api.Group("/returns")
-	returns.Use(middleware.AuthMiddleware(cfg))
-	{
-		// Public return routes
-		returns.POST("", returnController.CreateReturn)
-		returns.GET("", returnController.GetReturns)           // Get all returns (with optional search and date filtering)
-		returns.GET("/:id", returnController.GetReturn)        // Get return by ID
-		returns.PUT("/:id", returnController.UpdateDataReturn) // Update partial data return for return admins
+// returnRoutes lists return-related routes
+func returnRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodPost, "/api/returns", ctl.Return.CreateReturn, Authenticated, "Create new return"},
+		{http.MethodGet, "/api/returns", ctl.Return.GetReturns, Authenticated, "Get all returns (with optional search and date filtering)"},
+		{http.MethodGet, "/api/returns/:id", ctl.Return.GetReturn, Authenticated, "Get return by ID"},
+		{http.MethodPut, "/api/returns/:id", ctl.Return.UpdateDataReturn, Authenticated, "Update partial data return for return admins"},
+		{http.MethodPut, "/api/returns/:id/link-replacement", ctl.Return.LinkReplacementOrder, Authenticated, "Link or unlink the replacement order of a return"},
 	}
 }
 
-// SetupMobileReturnRoutes configures mobile return-related routes
-func SetupMobileReturnRoutes(api *gin.RouterGroup, cfg *config.Config, mobileReturnController *controllers.MobileReturnController) {
-	// Mobile return routes (authenticated)
-	mobileReturns := api.Group("/mobile/returns")
-	mobileReturns.Use(middleware.AuthMiddleware(cfg))
-	{
-		// Public mobile return routes
-		mobileReturns.GET("", mobileReturnController.GetMobileReturns)    // Get all mobile returns (with optional search and date filtering)
-		mobileReturns.GET("/:id", mobileReturnController.GetMobileReturn) // Get mobile return by ID
-		mobileReturns.POST("", mobileReturnController.CreateMobileReturn) // Create new mobile return
+// mobileReturnRoutes lists mobile return and putaway routes
+func mobileReturnRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/mobile/returns", ctl.MobileReturn.GetMobileReturns, Authenticated, "Get all mobile returns (with optional search and date filtering)"},
+		{http.MethodGet, "/api/mobile/returns/:id", ctl.MobileReturn.GetMobileReturn, Authenticated, "Get mobile return by ID"},
+		{http.MethodPost, "/api/mobile/returns", ctl.MobileReturn.CreateMobileReturn, Authenticated, "Create new mobile return"},
+
+		// Putaway tasks for approved returns
+		{http.MethodGet, "/api/mobile/putaway-tasks", ctl.MobileReturn.GetPutawayTasks, Authenticated, "Get putaway tasks (pending by default)"},
+		{http.MethodPut, "/api/mobile/putaway-tasks/:id/complete", ctl.MobileReturn.CompletePutawayTask, Authenticated, "Complete a putaway task with the scanned location"},
 	}
 }
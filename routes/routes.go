@@ -1,9 +1,14 @@
 package routes
 
 import (
+	"expvar"
 	"fmt"
 	"livo-backend/config"
-	"livo-backend/controllers"
+	"livo-backend/health"
+	"livo-backend/middleware"
+	"livo-backend/storage"
+	"livo-backend/utilities"
+	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -14,32 +19,32 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-// SetupRoutes configures all routes for the application
-func SetupRoutes(cfg *config.Config, authController *controllers.AuthController, userManagerController *controllers.UserManagerController, boxController *controllers.BoxController, channelController *controllers.ChannelController, mobileChannelController *controllers.MobileChannelController, expeditionController *controllers.ExpeditionController, productController *controllers.ProductController, storeController *controllers.StoreController, mobileStoreController *controllers.MobileStoreController, qcRibbonController *controllers.QcRibbonController, ribbonFlowController *controllers.RibbonFlowController, qcOnlineController *controllers.QcOnlineController, onlineFlowController *controllers.OnlineFlowController, outboundController *controllers.OutboundController, returnController *controllers.ReturnController, mobileReturnController *controllers.MobileReturnController, complainController *controllers.ComplainController, orderController *controllers.OrderController, mobileOrderController *controllers.MobileOrderController, userController *controllers.UserController, lostFoundController *controllers.LostFoundController, reportController *controllers.ReportController, pickedOrderController *controllers.PickedOrderController) *gin.Engine {
+// SetupRoutes configures all routes for the application. API routes are declared in route tables
+// with the permission they require and mounted through the route registry.
+func SetupRoutes(cfg *config.Config, ctl *Controllers) *gin.Engine {
 	// Set Gin mode
 	gin.SetMode(cfg.GinMode)
 
+	// Custom binding tags (tracking, sku, date...) and field level validation errors
+	if err := utilities.RegisterValidators(); err != nil {
+		log.Printf("⚠️ Failed to register request validators: %v", err)
+	}
+
 	router := gin.Default()
 
+	// The gin context is passed to GORM as the context of the request's queries, so they stop when
+	// the client disconnects or the query timeout passes
+	router.ContextWithFallback = true
+
 	// CORS middleware - single unified configuration
 	corsConfig := cors.Config{
-		AllowOrigins: strings.Split(cfg.CORSAllowedOrigins, ","),
-		AllowMethods: strings.Split(cfg.CORSAllowedMethods, ","),
-		AllowHeaders: []string{
-			"Origin",
-			"Content-Length",
-			"Content-Type",
-			"Authorization",
-			"Accept",
-			"X-Requested-With",
-		},
-		ExposeHeaders: []string{
-			"Content-Length",
-			"Content-Type",
-		},
-		AllowCredentials: true,
+		AllowOrigins:     splitConfigList(cfg.CORSAllowedOrigins),
+		AllowMethods:     splitConfigList(cfg.CORSAllowedMethods),
+		AllowHeaders:     splitConfigList(cfg.CORSAllowedHeaders),
+		ExposeHeaders:    splitConfigList(cfg.CORSExposedHeaders),
+		AllowCredentials: cfg.CORSAllowCredentials,
 		AllowAllOrigins:  false,
-		MaxAge:           12 * time.Hour,
+		MaxAge:           time.Duration(cfg.CORSMaxAgeHours) * time.Hour,
 	}
 
 	// If no origins configured, allow all
@@ -50,8 +55,22 @@ func SetupRoutes(cfg *config.Config, authController *controllers.AuthController,
 
 	router.Use(cors.New(corsConfig))
 
-	// Set trusted proxies for security
-	router.SetTrustedProxies([]string{"localhost:3000", "127.0.0.1", "192.168.31.52", "192.168.31.53", "192.168.31.54", "192.168.31.55", "::1"})
+	// Large lists over the warehouse Wi-Fi: gzip, and 304 for pages the client has already
+	router.Use(middleware.Compression(splitConfigList(cfg.GzipRoutes), splitConfigList(cfg.ETagRoutes), cfg.GzipMinBytes))
+
+	// Queries of a request running past the timeout are cancelled and answered 503
+	router.Use(middleware.QueryTimeout(
+		time.Duration(cfg.QueryTimeoutSeconds)*time.Second,
+		time.Duration(cfg.LongQueryTimeoutSeconds)*time.Second,
+		splitConfigList(cfg.LongQueryRoutes),
+	))
+
+	// Only trust forwarded client IPs from the configured proxies
+	if err := router.SetTrustedProxies(splitConfigList(cfg.TrustedProxies)); err != nil {
+		log.Printf("⚠️ Invalid TRUSTED_PROXIES, trusting no proxy: %v", err)
+		router.SetTrustedProxies(nil)
+	}
+	router.RemoteIPHeaders = splitConfigList(cfg.RemoteIPHeaders)
 
 	// Serve static files from static directory
 	router.Static("/static", "./static")
@@ -165,33 +184,100 @@ func SetupRoutes(cfg *config.Config, authController *controllers.AuthController,
 		})
 	})
 
-	// API routes
-	api := router.Group("/api")
-
-	// Setup route groups
-	SetupAuthRoutes(api, cfg, authController)
-	SetupUserManagerRoutes(api, cfg, userManagerController)
-	SetupBoxRoutes(api, cfg, boxController)
-	SetupChannelRoutes(api, cfg, channelController)
-	SetupMobileChannelRoutes(api, cfg, mobileChannelController)
-	SetupExpeditionRoutes(api, cfg, expeditionController)
-	SetupProductRoutes(api, cfg, productController)
-	SetupStoreRoutes(api, cfg, storeController)
-	SetupMobileStoreRoutes(api, cfg, mobileStoreController)
-	SetupQcRibbonRoutes(api, cfg, qcRibbonController)
-	SetupRibbonFlowRoutes(api, cfg, ribbonFlowController)
-	SetupQcOnlineRoutes(api, cfg, qcOnlineController)
-	SetupOnlineFlowRoutes(api, cfg, onlineFlowController)
-	SetupOutboundRoutes(api, cfg, outboundController)
-	SetupReturnRoutes(api, cfg, returnController)
-	SetupMobileReturnRoutes(api, cfg, mobileReturnController)
-	SetupComplainRoutes(api, cfg, complainController)
-	SetupOrderRoutes(api, cfg, orderController)
-	SetupMobileOrderRoutes(api, cfg, mobileOrderController)
-	SetupUserRoutes(api, cfg, userController)
-	SetupLostFoundRoutes(api, cfg, lostFoundController)
-	SetupReportRoutes(api, cfg, reportController)
-	SetupPickedOrderRoutes(api, cfg, pickedOrderController)
+	// Liveness probe, the process is up
+	router.GET("/health/live", func(c *gin.Context) {
+		c.JSON(http.StatusOK, health.Live())
+	})
+
+	// Readiness probe: database, pending migrations, upload disk space and backups. /readyz is kept for
+	// existing monitors.
+	ready := func(c *gin.Context) {
+		report := health.Ready(config.GetDB(), cfg)
+		status := http.StatusOK
+		if !report.Ready() {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, report)
+	}
+	router.GET("/health/ready", ready)
+	router.GET("/readyz", ready)
+
+	// Uploaded files kept on disk (product images), public like the marketplace image URLs. Keys are
+	// never reused, so clients may cache the files for good.
+	if cfg.StorageDriver == "" || cfg.StorageDriver == "disk" {
+		uploads := router.Group(storage.UploadsPath, func(c *gin.Context) {
+			c.Header("Cache-Control", storage.CacheControl)
+			c.Next()
+		})
+		uploads.StaticFS("/", gin.Dir(cfg.UploadDir, false))
+	}
+
+	registry := NewRegistry(cfg)
+	registry.Mount(router, metaRoutes(registry))
+	for _, table := range [][]Route{
+		authRoutes(ctl),
+		userManagerRoutes(ctl),
+		boxRoutes(ctl),
+		channelRoutes(ctl),
+		expeditionRoutes(ctl),
+		productRoutes(ctl),
+		storeRoutes(ctl),
+		ribbonRoutes(ctl),
+		onlineRoutes(ctl),
+		mobileQcRoutes(ctl),
+		outboundRoutes(ctl),
+		returnRoutes(ctl),
+		mobileReturnRoutes(ctl),
+		complainRoutes(ctl),
+		orderRoutes(ctl),
+		mobileOrderRoutes(ctl),
+		userRoutes(ctl),
+		lostFoundRoutes(ctl),
+		reportRoutes(ctl),
+		pickedOrderRoutes(ctl),
+		notificationRoutes(ctl),
+		zoneRoutes(ctl),
+		backupRoutes(ctl),
+		backfillRoutes(ctl),
+		apiKeyRoutes(ctl),
+		bulkFailureRoutes(ctl),
+		reportPresetRoutes(ctl),
+		inboundRoutes(ctl),
+		mobileInboundRoutes(ctl),
+		integrationRoutes(ctl),
+		pickupRoutes(ctl),
+		configRoutes(ctl),
+		graphQLRoutes(cfg, ctl),
+	} {
+		registry.Mount(router, table)
+	}
 
 	return router
 }
+
+// metaRoutes lists routes describing the API itself
+func metaRoutes(registry *Registry) []Route {
+	return []Route{
+		{http.MethodGet, "/api/error-codes", getErrorCodes, Public, "Get the codes sent in the code field of error responses"},
+		{http.MethodGet, "/api/meta/routes", func(c *gin.Context) {
+			utilities.SuccessResponse(c, http.StatusOK, "Routes retrieved successfully", registry.Routes())
+		}, Admin, "Get every API route with the login, roles and quota it requires"},
+		{http.MethodGet, "/debug/vars", gin.WrapH(expvar.Handler()), Admin, "Runtime metrics, including database transaction retry counters"},
+	}
+}
+
+// getErrorCodes sends the error code catalogue
+func getErrorCodes(c *gin.Context) {
+	utilities.SuccessResponse(c, http.StatusOK, "Error codes retrieved successfully", utilities.ErrorCodes())
+}
+
+// splitConfigList splits a comma separated config value, dropping blanks
+func splitConfigList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
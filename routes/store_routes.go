@@ -1,31 +1,23 @@
 package routes
 
 import (
-	"livo-backend/config"
-	"livo-backend/controllers"
-	"livo-backend/middleware"
-
-	"github.com/gin-gonic/gin"
+	"net/http"
 )
 
-// SetupStoreRoutes configures store-related routes
-func SetupStoreRoutes(api *gin.RouterGroup, cfg *config.Config, storeController *controllers.StoreController) {
-	// Store routes (authenticated)
-	store := api.Group("/stores")
-	store.Use(middleware.AuthMiddleware(cfg))
-	{
-		// Public store routes
-		store.GET("", storeController.GetStores)          // Get all stores (with optional search)
-		store.GET("/:id", storeController.GetStore)       // Get store by ID
-		store.POST("", storeController.CreateStore)       // Create new store
-		store.PUT("/:id", storeController.UpdateStore)    // Update store by ID
-		store.DELETE("/:id", storeController.RemoveStore) // Delete store by ID
-	}
-}
+// storeRoutes lists store-related routes
+func storeRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/stores", ctl.Store.GetStores, Authenticated, "Get all stores (with optional search)"},
+		{http.MethodGet, "/api/stores/:id", ctl.Store.GetStore, Authenticated, "Get store by ID"},
+		{http.MethodPost, "/api/stores", ctl.Store.CreateStore, Authenticated, "Create new store"},
+		{http.MethodPut, "/api/stores/:id", ctl.Store.UpdateStore, Authenticated, "Update store by ID"},
+		{http.MethodDelete, "/api/stores/:id", ctl.Store.RemoveStore, Authenticated, "Delete store by ID"},
+		{http.MethodGet, "/api/stores/trash", ctl.Store.GetStoreTrash, Admin, "Get deleted stores"},
+		{http.MethodPost, "/api/stores/:id/restore", ctl.Store.RestoreStore, Admin, "Restore a deleted store"},
+		{http.MethodGet, "/api/stores/:id/settings", ctl.Store.GetStoreSettings, Authenticated, "Get store settings (label header, complain SLA, default courier, document footer)"},
+		{http.MethodPut, "/api/stores/:id/settings", ctl.Store.UpdateStoreSettings, Coordinator, "Update store settings"},
 
-// SetupMobileStoreRoutes configures mobile store-related routes
-func SetupMobileStoreRoutes(api *gin.RouterGroup, cfg *config.Config, mobileStoreController *controllers.MobileStoreController) {
-	// Mobile store routes (public)
-	mobileStore := api.Group("/mobile/stores")
-	mobileStore.GET("", mobileStoreController.GetMobileStores) // Get all stores (with optional search)
+		// Mobile store routes
+		{http.MethodGet, "/api/mobile/stores", ctl.MobileStore.GetMobileStores, Public, "Get all stores (with optional search)"},
+	}
 }
@@ -1,43 +1,37 @@
 package routes
 
 import (
-	"livo-backend/config"
-	"livo-backend/controllers"
-	"livo-backend/middleware"
-
-	"github.com/gin-gonic/gin"
+	"livo-backend/models"
+	"net/http"
 )
 
-// SetupUserManagerRoutes configures user manager-related routes
-func SetupUserManagerRoutes(api *gin.RouterGroup, cfg *config.Config, userManagerController *controllers.UserManagerController) {
-	// User manager routes (authenticated + role-based)
-	userManager := api.Group("/user-manager")
-	userManager.Use(middleware.AuthMiddleware(cfg))
-	{
-		// Get all roles - public to all authenticated users (no role restriction)
-		userManager.GET("/roles", userManagerController.GetRoles)
+// userManagerRoutes lists user management routes
+func userManagerRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/user-manager/roles", ctl.UserManager.GetRoles, Authenticated, "Get all roles"},
+		{http.MethodGet, "/api/user-manager/users", ctl.UserManager.GetUsers, Authenticated, "Get all users"},
+		{http.MethodGet, "/api/user-manager/users/:id", ctl.UserManager.GetUser, Authenticated, "Get user by ID"},
+		{http.MethodPut, "/api/user-manager/users/:id/password", ctl.UserManager.UpdateUserPassword, Authenticated, "Update user password"},
+		{http.MethodPut, "/api/user-manager/users/:id/profile", ctl.UserManager.UpdateUserProfile, Authenticated, "Update user profile"},
 
-		// Get all users - public to all authenticated users (no role restriction)
-		userManager.GET("/users", userManagerController.GetUsers)
-		userManager.GET("/users/:id", userManagerController.GetUser)                     // Get user by ID
-		userManager.PUT("/users/:id/password", userManagerController.UpdateUserPassword) // Update user password
-		userManager.PUT("/users/:id/profile", userManagerController.UpdateUserProfile)   // Update user profile
+		// User management and role assignment
+		{http.MethodPut, "/api/user-manager/users/:id/status", ctl.UserManager.UpdateUserStatus, Coordinator, "Update user status (active/inactive)"},
+		{http.MethodPost, "/api/user-manager/users", ctl.UserManager.CreateUser, Coordinator, "Create new user"},
+		{http.MethodPost, "/api/user-manager/users/bulk", ctl.UserManager.BulkCreateUsers, Coordinator.WithQuota(models.QuotaOperationBulkImport), "Import users from JSON or CSV with role templates and generated passwords"},
+		{http.MethodDelete, "/api/user-manager/users/:id", ctl.UserManager.DeleteUser, Coordinator, "Delete user"},
+		{http.MethodPost, "/api/user-manager/users/:id/roles", ctl.UserManager.AssignRole, Coordinator, "Assign role to user"},
+		{http.MethodDelete, "/api/user-manager/users/:id/roles", ctl.UserManager.RemoveRole, Coordinator, "Remove role from user"},
+		{http.MethodPut, "/api/user-manager/users/:id/unlock", ctl.UserManager.UnlockUser, Admin, "Unlock a user locked after too many wrong passwords"},
 
-		// User management (coordinator only)
-		users := userManager.Group("/users")
-		users.Use(middleware.RequireCoordinatorRoles())
-		{
-			users.PUT("/:id/status", userManagerController.UpdateUserStatus)     // Update user status (active/inactive)
-			users.POST("", userManagerController.CreateUser)                     // Create new user
-			users.DELETE("/:id", userManagerController.DeleteUser)               // Delete user
-		}
+		// Onboarding role templates
+		{http.MethodGet, "/api/user-manager/role-templates", ctl.RoleTemplate.GetRoleTemplates, Coordinator, "Get onboarding role templates"},
+		{http.MethodPost, "/api/user-manager/role-templates", ctl.RoleTemplate.CreateRoleTemplate, Coordinator, "Create onboarding role template"},
+		{http.MethodPut, "/api/user-manager/role-templates/:id", ctl.RoleTemplate.UpdateRoleTemplate, Coordinator, "Update onboarding role template"},
+		{http.MethodDelete, "/api/user-manager/role-templates/:id", ctl.RoleTemplate.RemoveRoleTemplate, Coordinator, "Delete onboarding role template"},
 
-		// Role assignment (coordinator only)
-		roleAssignment := userManager.Group("/users/:id/roles") // Assign or remove roles to/from a user
-		roleAssignment.Use(middleware.RequireCoordinatorRoles())
-		{
-			roleAssignment.POST("", userManagerController.AssignRole)   // Assign role to user
-			roleAssignment.DELETE("", userManagerController.RemoveRole) // Remove role from user
-		}
+		// Two-factor enforcement
+		{http.MethodPut, "/api/user-manager/roles/:id/two-factor", ctl.UserManager.UpdateRoleTwoFactor, Superadmin, "Require two-factor authentication for a role"},
+		{http.MethodDelete, "/api/user-manager/users/:id/two-factor", ctl.UserManager.ResetUserTwoFactor, Superadmin, "Reset a user's two-factor authentication"},
+		{http.MethodPost, "/api/user-manager/users/:id/two-factor/enrollment-token", ctl.UserManager.IssueTwoFactorEnrollment, Superadmin, "Issue a token for a user to enroll in two-factor authentication"},
 	}
 }
@@ -1,21 +1,21 @@
 package routes
 
 import (
-	"livo-backend/config"
-	"livo-backend/controllers"
-	"livo-backend/middleware"
-
-	"github.com/gin-gonic/gin"
+	"net/http"
 )
 
-// SetupUserRoutes configures user-related routes
-func SetupUserRoutes(api *gin.RouterGroup, cfg *config.Config, userController *controllers.UserController) {
-	// User routes (authenticated)
-	user := api.Group("/user")
-	user.Use(middleware.AuthMiddleware(cfg))
-	{
-		// Public user routes
-		user.GET("/profile", userController.GetProfile)    // Get user profile
-		user.PUT("/profile", userController.UpdateProfile) // Update user profile
+// userRoutes lists routes of the logged-in user
+func userRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/user/profile", ctl.User.GetProfile, Authenticated, "Get user profile"},
+		{http.MethodPut, "/api/user/profile", ctl.User.UpdateProfile, Authenticated, "Update user profile"},
+
+		// Self-service routes
+		{http.MethodGet, "/api/me", ctl.User.GetProfile, Authenticated, "Get my profile"},
+		{http.MethodPut, "/api/me", ctl.User.UpdateProfile, Authenticated, "Update my profile"},
+		{http.MethodPut, "/api/me/password", ctl.User.ChangePassword, Authenticated, "Change my password"},
+		{http.MethodGet, "/api/me/quotas", ctl.User.GetMyQuotas, Authenticated, "Get my daily request quotas and usage"},
+		{http.MethodGet, "/api/me/sessions", ctl.User.GetMySessions, Authenticated, "List the devices logged in as me"},
+		{http.MethodDelete, "/api/me/sessions/:id", ctl.User.RevokeMySession, Authenticated, "Log one of my devices out"},
 	}
 }
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"net/http"
+)
+
+// zoneRoutes lists picking zone routes
+func zoneRoutes(ctl *Controllers) []Route {
+	return []Route{
+		{http.MethodGet, "/api/zones", ctl.Zone.GetZones, Authenticated, "Get all zones with their pickers"},
+
+		// Zone management routes
+		{http.MethodPost, "/api/zones", ctl.Zone.CreateZone, Admin, "Create new zone"},
+		{http.MethodPut, "/api/zones/:id", ctl.Zone.UpdateZone, Admin, "Update zone by ID"},
+		{http.MethodDelete, "/api/zones/:id", ctl.Zone.RemoveZone, Admin, "Delete zone by ID"},
+		{http.MethodPut, "/api/zones/:id/pickers", ctl.Zone.SetZonePickers, Admin, "Replace pickers assigned to a zone"},
+	}
+}
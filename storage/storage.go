@@ -0,0 +1,55 @@
+// Package storage stores uploaded files on the local disk or in an S3 compatible bucket
+package storage
+
+import (
+	"context"
+	"fmt"
+	"livo-backend/config"
+	"strings"
+)
+
+// Storage stores files under slash separated keys, e.g. "products/12/5f0c.jpg"
+type Storage interface {
+	// Put stores data under key, replacing any file stored under it
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	// Delete removes the file stored under key, a missing file is not an error
+	Delete(ctx context.Context, key string) error
+	// URL returns the URL clients fetch the file stored under key from
+	URL(key string) string
+}
+
+// New returns the storage selected by STORAGE_DRIVER
+func New(cfg *config.Config) (Storage, error) {
+	switch cfg.StorageDriver {
+	case "", "disk":
+		publicURL := cfg.StoragePublicURL
+		if publicURL == "" {
+			publicURL = UploadsPath
+		}
+		return &Disk{Dir: cfg.UploadDir, PublicURL: publicURL}, nil
+	case "s3":
+		if cfg.S3Bucket == "" || cfg.S3AccessKey == "" || cfg.S3SecretKey == "" {
+			return nil, fmt.Errorf("S3 storage requires S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY")
+		}
+		return &S3{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PublicURL: cfg.StoragePublicURL,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown storage driver %q, use disk or s3", cfg.StorageDriver)
+}
+
+// UploadsPath is the route files stored on disk are served from
+const UploadsPath = "/uploads"
+
+// CacheControl is sent with every stored file, keys are never reused so the files never change
+const CacheControl = "public, max-age=31536000, immutable"
+
+// joinURL joins a base URL and a key
+func joinURL(base, key string) string {
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(key, "/")
+}
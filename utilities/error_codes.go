@@ -0,0 +1,295 @@
+package utilities
+
+import (
+	"net/http"
+	"sort"
+)
+
+// Error codes sent in the code field of error responses. Clients branch on the code, the message
+// is for people and may be reworded.
+const (
+	// Generic codes, used when the message has no specific code
+	ErrCodeBadRequest          = "BAD_REQUEST"
+	ErrCodeUnauthorized        = "UNAUTHORIZED"
+	ErrCodeForbidden           = "FORBIDDEN"
+	ErrCodeNotFound            = "NOT_FOUND"
+	ErrCodeConflict            = "CONFLICT"
+	ErrCodeUnprocessable       = "UNPROCESSABLE_ENTITY"
+	ErrCodeTooManyRequests     = "TOO_MANY_REQUESTS"
+	ErrCodeInternal            = "INTERNAL_ERROR"
+	ErrCodeServiceUnavailable  = "SERVICE_UNAVAILABLE"
+	ErrCodeQueryTimeout        = "QUERY_TIMEOUT"     // The request's queries ran past the query timeout
+	ErrCodeRequestCancelled    = "REQUEST_CANCELLED" // The client disconnected before the queries finished
+	ErrCodeValidationFailed    = "VALIDATION_FAILED"
+	ErrCodeInvalidParameter    = "INVALID_PARAMETER" // Malformed query or path parameter
+	ErrCodeUnknownOperation    = "UNKNOWN_OPERATION"
+	ErrCodeStateInvalid        = "STATE_INVALID" // The record is not in a state that allows the action
+	ErrCodeJobRunning          = "JOB_RUNNING"
+	ErrCodeFeatureDisabled     = "FEATURE_DISABLED"
+	ErrCodeQuotaExceeded       = "QUOTA_EXCEEDED"
+	ErrCodeDuplicateCode       = "DUPLICATE_CODE" // Master data code already taken
+	ErrCodeMasterInUse         = "MASTER_IN_USE"  // Master data still referenced by recent transactions
+	ErrCodeActionNotAllowed    = "ACTION_NOT_ALLOWED"
+	ErrCodeLabelRenderFailed   = "LABEL_RENDER_FAILED"
+	ErrCodeStatusTransition    = "STATUS_TRANSITION_INVALID"
+	ErrCodeInsufficientAccess  = "INSUFFICIENT_PERMISSIONS"
+	ErrCodeInvalidRole         = "INVALID_ROLE"
+	ErrCodeRoleAlreadyAssigned = "ROLE_ALREADY_ASSIGNED"
+	ErrCodeInvalidImage        = "INVALID_IMAGE"
+	ErrCodeFileTooLarge        = "FILE_TOO_LARGE"
+	ErrCodeInvalidFile         = "INVALID_FILE"   // Uploaded file missing or not in the expected format
+	ErrCodeDuplicateName       = "DUPLICATE_NAME" // Name already taken by another record of the user
+
+	// Authentication
+	ErrCodeAuthRequired           = "AUTH_REQUIRED"
+	ErrCodeInvalidToken           = "INVALID_TOKEN"
+	ErrCodeSessionExpired         = "SESSION_EXPIRED"
+	ErrCodeInvalidRefreshToken    = "INVALID_REFRESH_TOKEN"
+	ErrCodeInvalidCredentials     = "INVALID_CREDENTIALS"
+	ErrCodeAccountInactive        = "ACCOUNT_INACTIVE"
+	ErrCodeAccountLocked          = "ACCOUNT_LOCKED"
+	ErrCodeInvalidAPIKey          = "INVALID_API_KEY"
+	ErrCodeAPIKeyScopeDenied      = "API_KEY_SCOPE_DENIED"
+	ErrCodeTwoFactorRequired      = "TWO_FACTOR_REQUIRED"
+	ErrCodeTwoFactorInvalid       = "TWO_FACTOR_INVALID"
+	ErrCodeTwoFactorNotEnabled    = "TWO_FACTOR_NOT_ENABLED"
+	ErrCodeTwoFactorEnabled       = "TWO_FACTOR_ALREADY_ENABLED"
+	ErrCodeTwoFactorLocked        = "TWO_FACTOR_LOCKED"
+	ErrCodePasswordReused         = "PASSWORD_REUSED"
+	ErrCodePasswordWeak           = "PASSWORD_TOO_WEAK" // The password breaks the configured password policy
+	ErrCodeEmailTaken             = "EMAIL_TAKEN"
+	ErrCodeUserExists             = "USER_EXISTS"
+	ErrCodeCoordinatorCredentials = "INVALID_COORDINATOR_CREDENTIALS"
+
+	// Orders and flows
+	ErrCodeOrderNotFound         = "ORDER_NOT_FOUND"
+	ErrCodeOrderDetailNotFound   = "ORDER_DETAIL_NOT_FOUND"
+	ErrCodeOrderStateInvalid     = "ORDER_STATE_INVALID" // The order is not in a processing status that allows the action
+	ErrCodeOrderCancelled        = "ORDER_CANCELLED"
+	ErrCodeOrderHeld             = "ORDER_HELD" // The order is on hold until it is released
+	ErrCodeOrderVersionConflict  = "ORDER_VERSION_CONFLICT"
+	ErrCodeOrderDuplicateLimit   = "ORDER_DUPLICATE_LIMIT"
+	ErrCodeOrderMergeInvalid     = "ORDER_MERGE_INVALID"
+	ErrCodeNoOrderAvailable      = "NO_ORDER_AVAILABLE"
+	ErrCodeTrackingNotFound      = "TRACKING_NOT_FOUND"
+	ErrCodeInvalidTracking       = "INVALID_TRACKING"
+	ErrCodeDuplicateTracking     = "DUPLICATE_TRACKING" // A record with the tracking already exists
+	ErrCodeTrackingLocked        = "TRACKING_LOCKED"
+	ErrCodeDuplicateParcel       = "DUPLICATE_PARCEL" // Outbound blocked, the parcel already left
+	ErrCodeCourierMismatch       = "COURIER_MISMATCH" // Outbound blocked, the label is of another courier than the order's
+	ErrCodeInvalidOverrideToken  = "INVALID_OVERRIDE_TOKEN"
+	ErrCodeInvalidParcel         = "INVALID_PARCEL" // Parcel number or count does not fit the parcels declared for the order
+	ErrCodeBarcodeMismatch       = "BARCODE_MISMATCH"
+	ErrCodeScanQuantityExceeded  = "SCAN_QUANTITY_EXCEEDED"
+	ErrCodeOrderNotFullyScanned  = "ORDER_NOT_FULLY_SCANNED"
+	ErrCodePickerUnavailable     = "PICKER_UNAVAILABLE"
+	ErrCodeDuplicateBox          = "DUPLICATE_BOX"
+	ErrCodeComplainStateInvalid  = "COMPLAIN_STATE_INVALID"
+	ErrCodeDuplicateComplain     = "DUPLICATE_COMPLAIN"
+	ErrCodeReturnDetailsInvalid  = "RETURN_DETAILS_INVALID"
+	ErrCodeReplacementInvalid    = "REPLACEMENT_ORDER_INVALID" // The order cannot replace the return
+	ErrCodeAlreadyResolved       = "ALREADY_RESOLVED"
+	ErrCodeReasonRequired        = "REASON_REQUIRED"
+	ErrCodeDateRangeInvalid      = "DATE_RANGE_INVALID"
+	ErrCodeInvalidDateFormat     = "INVALID_DATE_FORMAT"
+	ErrCodeBackupUnavailable     = "BACKUP_UNAVAILABLE"
+	ErrCodeInvalidTrackingConfig = "INVALID_TRACKING_RULES"
+	ErrCodeDuplicateInbound      = "DUPLICATE_INBOUND" // Supplier reference already announced
+	ErrCodeInboundStateInvalid   = "INBOUND_STATE_INVALID"
+	ErrCodeInboundDetailsInvalid = "INBOUND_DETAILS_INVALID"
+	ErrCodeInboundCountInvalid   = "INBOUND_COUNT_INVALID"
+	ErrCodeInboundNotChecked     = "INBOUND_NOT_FULLY_CHECKED"
+	ErrCodeQcItemsInvalid        = "QC_ITEMS_INVALID"
+	ErrCodeQcAlreadyVerified     = "QC_ALREADY_VERIFIED"
+
+	// Records not found
+	ErrCodeAPIKeyNotFound        = "API_KEY_NOT_FOUND"
+	ErrCodeBackfillNotFound      = "BACKFILL_NOT_FOUND"
+	ErrCodeBackupNotFound        = "BACKUP_NOT_FOUND"
+	ErrCodeBoxNotFound           = "BOX_NOT_FOUND"
+	ErrCodeBulkFailureNotFound   = "BULK_FAILURE_NOT_FOUND"
+	ErrCodeChannelNotFound       = "CHANNEL_NOT_FOUND"
+	ErrCodeComplainNotFound      = "COMPLAIN_NOT_FOUND"
+	ErrCodeConfigNotFound        = "CONFIG_NOT_FOUND"
+	ErrCodeExpeditionNotFound    = "EXPEDITION_NOT_FOUND"
+	ErrCodeInboundNotFound       = "INBOUND_NOT_FOUND"
+	ErrCodeLostFoundNotFound     = "LOST_FOUND_NOT_FOUND"
+	ErrCodeNotificationNotFound  = "NOTIFICATION_NOT_FOUND"
+	ErrCodeOrderRevisionNotFound = "ORDER_REVISION_NOT_FOUND"
+	ErrCodeOutboundAlertNotFound = "OUTBOUND_ALERT_NOT_FOUND"
+	ErrCodeOutboundNotFound      = "OUTBOUND_NOT_FOUND"
+	ErrCodePickOrderNotFound     = "PICK_ORDER_NOT_FOUND"
+	ErrCodePickerNotFound        = "PICKER_NOT_FOUND"
+	ErrCodePickupSlotNotFound    = "PICKUP_SLOT_NOT_FOUND"
+	ErrCodeProductNotFound       = "PRODUCT_NOT_FOUND"
+	ErrCodePutawayTaskNotFound   = "PUTAWAY_TASK_NOT_FOUND"
+	ErrCodeQcNotFound            = "QC_NOT_FOUND"
+	ErrCodeReportPresetNotFound  = "REPORT_PRESET_NOT_FOUND"
+	ErrCodeReturnNotFound        = "RETURN_NOT_FOUND"
+	ErrCodeRoleNotFound          = "ROLE_NOT_FOUND"
+	ErrCodeRoleTemplateNotFound  = "ROLE_TEMPLATE_NOT_FOUND"
+	ErrCodeSessionNotFound       = "SESSION_NOT_FOUND"
+	ErrCodeStoreNotFound         = "STORE_NOT_FOUND"
+	ErrCodeUserNotFound          = "USER_NOT_FOUND"
+	ErrCodeZoneNotFound          = "ZONE_NOT_FOUND"
+)
+
+// errorCodeDescriptions describes every error code for the catalogue
+var errorCodeDescriptions = map[string]string{
+	ErrCodeBadRequest:             "The request is invalid",
+	ErrCodeUnauthorized:           "The request is not authenticated",
+	ErrCodeForbidden:              "The user may not perform the request",
+	ErrCodeNotFound:               "A requested record does not exist",
+	ErrCodeConflict:               "The request conflicts with existing records",
+	ErrCodeUnprocessable:          "The request cannot be applied to the records",
+	ErrCodeTooManyRequests:        "Too many requests, try again later",
+	ErrCodeInternal:               "The server failed to handle the request",
+	ErrCodeServiceUnavailable:     "The server cannot handle the request right now",
+	ErrCodeQueryTimeout:           "The request's queries ran past the query timeout",
+	ErrCodeRequestCancelled:       "The client disconnected before the queries finished",
+	ErrCodeValidationFailed:       "The request body is invalid, the fields field tells what is wrong",
+	ErrCodeInvalidParameter:       "A query or path parameter is malformed",
+	ErrCodeUnknownOperation:       "The requested operation or mutation type does not exist",
+	ErrCodeStateInvalid:           "The record is not in a state that allows the action",
+	ErrCodeJobRunning:             "The job is already running",
+	ErrCodeFeatureDisabled:        "The feature is disabled in the configuration",
+	ErrCodeQuotaExceeded:          "The daily quota of the operation is used up",
+	ErrCodeDuplicateCode:          "The master data code is already taken",
+	ErrCodeMasterInUse:            "The master data is still referenced by recent transactions",
+	ErrCodeActionNotAllowed:       "The action is not allowed on the record",
+	ErrCodeLabelRenderFailed:      "The shipping label could not be rendered",
+	ErrCodeStatusTransition:       "The record cannot move to the requested status",
+	ErrCodeInsufficientAccess:     "The user's roles do not allow the request",
+	ErrCodeInvalidRole:            "The role does not exist",
+	ErrCodeRoleAlreadyAssigned:    "The user already has the role",
+	ErrCodeInvalidImage:           "The image is missing or not a supported image",
+	ErrCodeFileTooLarge:           "The uploaded file is too large",
+	ErrCodeInvalidFile:            "The uploaded file is missing or not in the expected format",
+	ErrCodeDuplicateName:          "The name is already taken by another record of the user",
+	ErrCodeAuthRequired:           "The request needs a logged in user",
+	ErrCodeInvalidToken:           "The access token is invalid",
+	ErrCodeSessionExpired:         "The session ended, log in again",
+	ErrCodeInvalidRefreshToken:    "The refresh token is invalid",
+	ErrCodeInvalidCredentials:     "The username or password is wrong",
+	ErrCodeAccountInactive:        "The user account is disabled",
+	ErrCodeAccountLocked:          "The account is locked after too many wrong passwords",
+	ErrCodeInvalidAPIKey:          "The API key is invalid or revoked",
+	ErrCodeAPIKeyScopeDenied:      "The API key's scopes do not allow the request",
+	ErrCodeTwoFactorRequired:      "The login needs a two-factor code or enrollment",
+	ErrCodeTwoFactorInvalid:       "The two-factor code or token is wrong",
+	ErrCodeTwoFactorNotEnabled:    "The user has no two-factor authentication",
+	ErrCodeTwoFactorEnabled:       "The user already has two-factor authentication",
+	ErrCodeTwoFactorLocked:        "Too many wrong two-factor codes",
+	ErrCodePasswordReused:         "The new password equals the current one",
+	ErrCodePasswordWeak:           "The password breaks the configured password policy",
+	ErrCodeEmailTaken:             "The email is already used by another user",
+	ErrCodeUserExists:             "The username is already taken",
+	ErrCodeCoordinatorCredentials: "The coordinator confirming the action gave wrong credentials",
+	ErrCodeOrderNotFound:          "The order does not exist",
+	ErrCodeOrderDetailNotFound:    "The order line does not exist",
+	ErrCodeOrderStateInvalid:      "The order is not in a processing status that allows the action",
+	ErrCodeOrderCancelled:         "The order is cancelled",
+	ErrCodeOrderHeld:              "The order is on hold until it is released",
+	ErrCodeOrderVersionConflict:   "The order was changed since it was loaded",
+	ErrCodeOrderDuplicateLimit:    "The order was duplicated as often as allowed",
+	ErrCodeOrderMergeInvalid:      "The orders cannot be merged",
+	ErrCodeNoOrderAvailable:       "No order is waiting to be picked",
+	ErrCodeTrackingNotFound:       "No order has the tracking",
+	ErrCodeInvalidTracking:        "The tracking breaks the tracking rules",
+	ErrCodeDuplicateTracking:      "A record with the tracking already exists",
+	ErrCodeTrackingLocked:         "The tracking is referenced by other modules and cannot be changed",
+	ErrCodeDuplicateParcel:        "Outbound blocked, the parcel already left",
+	ErrCodeCourierMismatch:        "Outbound blocked, the label is of another courier than the order's",
+	ErrCodeInvalidOverrideToken:   "The override token is invalid or expired",
+	ErrCodeInvalidParcel:          "The parcel number or count does not fit the parcels declared for the order",
+	ErrCodeBarcodeMismatch:        "The scanned barcode is not the product of the order line",
+	ErrCodeScanQuantityExceeded:   "The scan exceeds the ordered quantity",
+	ErrCodeOrderNotFullyScanned:   "Not every line of the order is scanned",
+	ErrCodePickerUnavailable:      "The picker cannot take the order",
+	ErrCodeDuplicateBox:           "The box is given more than once",
+	ErrCodeComplainStateInvalid:   "The complain is not in a state that allows the action",
+	ErrCodeDuplicateComplain:      "A complain with the tracking already exists",
+	ErrCodeReturnDetailsInvalid:   "The return lines are invalid",
+	ErrCodeReplacementInvalid:     "The order cannot replace the return",
+	ErrCodeAlreadyResolved:        "The record is already resolved",
+	ErrCodeReasonRequired:         "The action needs a reason",
+	ErrCodeDateRangeInvalid:       "The date range is missing, reversed or too large",
+	ErrCodeInvalidDateFormat:      "A date is not in the expected format",
+	ErrCodeBackupUnavailable:      "The backup cannot be verified",
+	ErrCodeInvalidTrackingConfig:  "The tracking validation rules are invalid",
+	ErrCodeDuplicateInbound:       "The supplier reference is already announced",
+	ErrCodeInboundStateInvalid:    "The inbound cannot be changed anymore",
+	ErrCodeInboundDetailsInvalid:  "The inbound lines are invalid",
+	ErrCodeInboundCountInvalid:    "The counted quantity is invalid",
+	ErrCodeInboundNotChecked:      "The inbound has unchecked lines",
+	ErrCodeQcItemsInvalid:         "The confirmed QC items are invalid",
+	ErrCodeQcAlreadyVerified:      "The order is already verified at QC",
+	ErrCodeAPIKeyNotFound:         "The API key does not exist",
+	ErrCodeBackfillNotFound:       "The backfill or backfill run does not exist",
+	ErrCodeBackupNotFound:         "The backup does not exist",
+	ErrCodeBoxNotFound:            "The box does not exist",
+	ErrCodeBulkFailureNotFound:    "The bulk failure does not exist",
+	ErrCodeChannelNotFound:        "The channel does not exist",
+	ErrCodeComplainNotFound:       "The complain does not exist",
+	ErrCodeConfigNotFound:         "The config setting or override does not exist",
+	ErrCodeExpeditionNotFound:     "The expedition does not exist",
+	ErrCodeInboundNotFound:        "The inbound does not exist",
+	ErrCodeLostFoundNotFound:      "The lost and found item does not exist",
+	ErrCodeNotificationNotFound:   "The notification does not exist",
+	ErrCodeOrderRevisionNotFound:  "The order revision does not exist",
+	ErrCodeOutboundAlertNotFound:  "The outbound alert does not exist",
+	ErrCodeOutboundNotFound:       "The outbound does not exist",
+	ErrCodePickOrderNotFound:      "The pick order does not exist",
+	ErrCodePickerNotFound:         "The picker does not exist",
+	ErrCodePickupSlotNotFound:     "The pickup slot does not exist",
+	ErrCodeProductNotFound:        "The product does not exist",
+	ErrCodePutawayTaskNotFound:    "The putaway task does not exist",
+	ErrCodeQcNotFound:             "The QC record does not exist",
+	ErrCodeReportPresetNotFound:   "The report preset does not exist",
+	ErrCodeReturnNotFound:         "The return does not exist",
+	ErrCodeRoleNotFound:           "The role does not exist",
+	ErrCodeRoleTemplateNotFound:   "The role template does not exist",
+	ErrCodeSessionNotFound:        "The session does not exist",
+	ErrCodeStoreNotFound:          "The store does not exist",
+	ErrCodeUserNotFound:           "The user does not exist",
+	ErrCodeZoneNotFound:           "The zone does not exist",
+}
+
+// errorCodesByStatus are the generic codes of each status, sent when the handler gives no specific code
+var errorCodesByStatus = map[int]string{
+	http.StatusBadRequest:          ErrCodeBadRequest,
+	http.StatusUnauthorized:        ErrCodeUnauthorized,
+	http.StatusForbidden:           ErrCodeForbidden,
+	http.StatusNotFound:            ErrCodeNotFound,
+	http.StatusConflict:            ErrCodeConflict,
+	http.StatusUnprocessableEntity: ErrCodeUnprocessable,
+	http.StatusTooManyRequests:     ErrCodeTooManyRequests,
+	http.StatusServiceUnavailable:  ErrCodeServiceUnavailable,
+}
+
+// GenericErrorCode returns the code of an error response with the given status and no specific code
+func GenericErrorCode(status int) string {
+	if code, found := errorCodesByStatus[status]; found {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return ErrCodeInternal
+	}
+	return ErrCodeBadRequest
+}
+
+// ErrorCodeInfo describes an error code
+type ErrorCodeInfo struct {
+	Code        string `json:"code" example:"ORDER_NOT_FOUND"`
+	Description string `json:"description" example:"The order does not exist"`
+}
+
+// ErrorCodes lists the error code catalogue, ordered by code
+func ErrorCodes() []ErrorCodeInfo {
+	codes := make([]ErrorCodeInfo, 0, len(errorCodeDescriptions))
+	for code, description := range errorCodeDescriptions {
+		codes = append(codes, ErrorCodeInfo{Code: code, Description: description})
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i].Code < codes[j].Code })
+	return codes
+}
@@ -0,0 +1,388 @@
+package utilities
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// responseCall is a call that responds with a message, or aborts a transaction with one. A message
+// given in a variable makes one responseCall for each string assigned to the variable.
+type responseCall struct {
+	pos     string
+	status  int    // 0 when the status is not a constant
+	code    string // Name of the ErrCode constant, "" for the generic code of the status
+	message string // The message, or the format of a Messagef message
+	dynamic bool   // The message is built at the call site, it has no fixed text
+}
+
+// responseCallPackages are the packages that respond to requests
+var responseCallPackages = []string{"../controllers", "../middleware", "../routes", "../bootstrap"}
+
+// responseCalls returns the calls of the response helpers in the packages that respond to requests
+func responseCalls(t *testing.T) []responseCall {
+	t.Helper()
+
+	var calls []responseCall
+	fset := token.NewFileSet()
+	for _, dir := range responseCallPackages {
+		files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, path := range files {
+			file, err := parser.ParseFile(fset, path, nil, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, decl := range file.Decls {
+				if function, ok := decl.(*ast.FuncDecl); ok && function.Body != nil {
+					calls = append(calls, functionResponseCalls(fset, function)...)
+				}
+			}
+		}
+	}
+	if len(calls) == 0 {
+		t.Fatal("no response calls found")
+	}
+	return calls
+}
+
+// functionResponseCalls returns the response calls of function
+func functionResponseCalls(fset *token.FileSet, function *ast.FuncDecl) []responseCall {
+	// Values assigned to the variables of the function, and the variables appended to
+	assigned := map[string][]ast.Expr{}
+	appended := map[string]bool{}
+	ast.Inspect(function.Body, func(node ast.Node) bool {
+		switch statement := node.(type) {
+		case *ast.AssignStmt:
+			if len(statement.Lhs) == len(statement.Rhs) {
+				for i, lhs := range statement.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok {
+						assigned[ident.Name] = append(assigned[ident.Name], statement.Rhs[i])
+						appended[ident.Name] = appended[ident.Name] || statement.Tok == token.ADD_ASSIGN
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range statement.Names {
+				if i < len(statement.Values) {
+					assigned[name.Name] = append(assigned[name.Name], statement.Values[i])
+				}
+			}
+		}
+		return true
+	})
+
+	var calls []responseCall
+	ast.Inspect(function.Body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		name, ok := utilitiesCall(call)
+		if !ok {
+			return true
+		}
+
+		pos := fset.Position(call.Pos()).String()
+		add := func(status int, code string, message ast.Expr) {
+			values := []ast.Expr{message}
+			composed := false
+			if ident, ok := message.(*ast.Ident); ok {
+				composed = appended[ident.Name]
+				values = assigned[ident.Name]
+				if len(values) == 0 {
+					// A parameter, the callers' messages are checked at their calls
+					calls = append(calls, responseCall{pos: pos, status: status, code: code, dynamic: true})
+					return
+				}
+			}
+			for _, value := range values {
+				text, dynamic := messageText(value)
+				if _, literal := value.(*ast.BasicLit); literal && composed {
+					// Only whole messages are translated, the parts of a composed one need Messagef
+					dynamic = true
+				}
+				calls = append(calls, responseCall{pos: pos, status: status, code: code, message: text, dynamic: dynamic})
+			}
+		}
+		args := call.Args
+		switch name {
+		case "SuccessResponse", "ErrorResponse":
+			add(httpStatuses[selectorName(args[1])], "", args[2])
+		case "ErrorResponseCode":
+			add(httpStatuses[selectorName(args[1])], selectorName(args[2]), args[3])
+		case "NewStatusError":
+			add(httpStatuses[selectorName(args[0])], "", args[1])
+		case "NewStatusErrorCode":
+			add(httpStatuses[selectorName(args[0])], selectorName(args[1]), args[2])
+		case "LookupErrorResponse":
+			add(http.StatusNotFound, selectorName(args[2]), args[3])
+			add(http.StatusInternalServerError, "", args[4])
+		case "TransactionErrorResponse":
+			add(http.StatusInternalServerError, "", args[2])
+		}
+		return true
+	})
+	return calls
+}
+
+// utilitiesCall returns the name of the utilities function call calls
+func utilitiesCall(call *ast.CallExpr) (string, bool) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	if receiver, ok := selector.X.(*ast.Ident); !ok || receiver.Name != "utilities" {
+		return "", false
+	}
+	return selector.Sel.Name, true
+}
+
+// messageText returns the text of a message expression: a string literal, or the format of a
+// Messagef call. Reports true when the message is built otherwise.
+func messageText(expr ast.Expr) (string, bool) {
+	if call, ok := expr.(*ast.CallExpr); ok {
+		if name, ok := utilitiesCall(call); ok && name == "Messagef" && len(call.Args) > 1 {
+			if format := stringLiteral(call.Args[1]); format != "" {
+				return format, false
+			}
+		}
+		return "", true
+	}
+	text := stringLiteral(expr)
+	return text, text == ""
+}
+
+// httpStatuses maps the names of the net/http status constants the handlers use to their value
+var httpStatuses = map[string]int{
+	"StatusOK":                    http.StatusOK,
+	"StatusCreated":               http.StatusCreated,
+	"StatusAccepted":              http.StatusAccepted,
+	"StatusBadRequest":            http.StatusBadRequest,
+	"StatusUnauthorized":          http.StatusUnauthorized,
+	"StatusForbidden":             http.StatusForbidden,
+	"StatusNotFound":              http.StatusNotFound,
+	"StatusConflict":              http.StatusConflict,
+	"StatusRequestEntityTooLarge": http.StatusRequestEntityTooLarge,
+	"StatusUnprocessableEntity":   http.StatusUnprocessableEntity,
+	"StatusLocked":                http.StatusLocked,
+	"StatusTooManyRequests":       http.StatusTooManyRequests,
+	"StatusInternalServerError":   http.StatusInternalServerError,
+	"StatusServiceUnavailable":    http.StatusServiceUnavailable,
+}
+
+func selectorName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
+
+func stringLiteral(expr ast.Expr) string {
+	literal, ok := expr.(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return ""
+	}
+	value, _ := strconv.Unquote(literal.Value)
+	return value
+}
+
+// errorCodeConstants returns the values of the ErrCode constants, keyed by name
+func errorCodeConstants(t *testing.T) map[string]string {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), "error_codes.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	constants := map[string]string{}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			for i, name := range value.Names {
+				constants[name.Name] = stringLiteral(value.Values[i])
+			}
+		}
+	}
+	return constants
+}
+
+func TestErrorCodeCatalogueDescribesEveryCode(t *testing.T) {
+	described := map[string]bool{}
+	for _, info := range ErrorCodes() {
+		if info.Description == "" {
+			t.Errorf("code %s has no description", info.Code)
+		}
+		described[info.Code] = true
+	}
+	for name, code := range errorCodeConstants(t) {
+		if !described[code] {
+			t.Errorf("%s (%s) is missing from the catalogue", name, code)
+		}
+	}
+}
+
+// TestErrorCodesConsistent checks a message that has a specific error code at one call site is
+// responded with that code everywhere, so clients see one code per failure
+func TestErrorCodesConsistent(t *testing.T) {
+	constants := errorCodeConstants(t)
+	codes := map[string]string{}
+	sites := map[string]string{}
+	calls := responseCalls(t)
+	for _, call := range calls {
+		if call.code != "" {
+			if _, found := constants[call.code]; !found {
+				t.Errorf("%s: %s is not an error code", call.pos, call.code)
+			}
+			if call.code == GenericErrorCode(call.status) {
+				continue
+			}
+		}
+		if call.message == "" || call.code == "" {
+			continue
+		}
+		if code, found := codes[call.message]; found && code != call.code {
+			t.Errorf("%s: %q responded with %s, %s responds it with %s", call.pos, call.message, call.code, sites[call.message], code)
+		}
+		codes[call.message], sites[call.message] = call.code, call.pos
+	}
+	for _, call := range calls {
+		if call.code != "" || call.message == "" || call.status >= http.StatusInternalServerError || call.status < http.StatusBadRequest {
+			continue
+		}
+		if code, found := codes[call.message]; found {
+			t.Errorf("%s: %q responded with the generic code, %s responds it with %s", call.pos, call.message, sites[call.message], code)
+		}
+	}
+}
+
+// respond runs respond against a test context and returns the response
+func respond(t *testing.T, ctx context.Context, respond func(c *gin.Context)) (int, Response) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	c.Request.Header.Set("Accept-Language", LanguageEnglish)
+	respond(c)
+
+	var response Response
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return recorder.Code, response
+}
+
+func TestErrorResponseCodes(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		respond    func(c *gin.Context)
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name: "given code",
+			respond: func(c *gin.Context) {
+				ErrorResponseCode(c, http.StatusConflict, ErrCodeOrderHeld, "Order is on hold", "")
+			},
+			wantStatus: http.StatusConflict,
+			wantCode:   ErrCodeOrderHeld,
+		},
+		{
+			name:       "generic code",
+			respond:    func(c *gin.Context) { ErrorResponse(c, http.StatusNotFound, "Order not found", "") },
+			wantStatus: http.StatusNotFound,
+			wantCode:   ErrCodeNotFound,
+		},
+		{
+			name:       "server error",
+			respond:    func(c *gin.Context) { ErrorResponse(c, http.StatusInternalServerError, "Failed to update order", "") },
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   ErrCodeInternal,
+		},
+		{
+			name: "status error code",
+			respond: func(c *gin.Context) {
+				TransactionErrorResponse(c, NewStatusErrorCode(http.StatusConflict, ErrCodeOrderCancelled, "Order is cancelled", ""), "Failed")
+			},
+			wantStatus: http.StatusConflict,
+			wantCode:   ErrCodeOrderCancelled,
+		},
+		{
+			name: "status error without code",
+			respond: func(c *gin.Context) {
+				TransactionErrorResponse(c, NewStatusError(http.StatusBadRequest, "Invalid quantity", ""), "Failed")
+			},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   ErrCodeBadRequest,
+		},
+		{
+			name: "missing record",
+			respond: func(c *gin.Context) {
+				LookupErrorResponse(c, gorm.ErrRecordNotFound, ErrCodeBoxNotFound, "Box not found", "Failed to retrieve box")
+			},
+			wantStatus: http.StatusNotFound,
+			wantCode:   ErrCodeBoxNotFound,
+		},
+		{
+			name: "failed lookup",
+			respond: func(c *gin.Context) {
+				LookupErrorResponse(c, errors.New("connection reset"), ErrCodeBoxNotFound, "Box not found", "Failed to retrieve box")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   ErrCodeInternal,
+		},
+		{
+			name: "cancelled request",
+			ctx:  cancelled,
+			respond: func(c *gin.Context) {
+				LookupErrorResponse(c, context.Canceled, ErrCodeBoxNotFound, "Box not found", "Failed to retrieve box")
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantCode:   ErrCodeRequestCancelled,
+		},
+		{
+			name: "statement timeout",
+			respond: func(c *gin.Context) {
+				ErrorResponseCode(c, http.StatusNotFound, ErrCodeBoxNotFound, "Box not found", "ERROR: canceling statement due to statement timeout (SQLSTATE 57014)")
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantCode:   ErrCodeQueryTimeout,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			status, response := respond(t, ctx, tt.respond)
+			if status != tt.wantStatus || response.Code != tt.wantCode {
+				t.Errorf("response = %d %s, want %d %s", status, response.Code, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package utilities
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Languages responses can be given in
+const (
+	LanguageEnglish    = "en"
+	LanguageIndonesian = "id"
+)
+
+// messageCatalogues holds the translations of response messages per language. Messages are their own
+// keys: the English message at the call site keys the catalogues, so English needs no catalogue.
+var messageCatalogues = map[string]map[string]string{
+	LanguageIndonesian: indonesianMessages,
+}
+
+// serverErrorMessages is the message of server errors without a translation of their own
+var serverErrorMessages = map[string]string{
+	LanguageIndonesian: "Terjadi kesalahan pada server, silakan coba lagi",
+}
+
+// SupportedLanguage reports whether responses can be given in lang
+func SupportedLanguage(lang string) bool {
+	_, found := messageCatalogues[lang]
+	return found || lang == LanguageEnglish
+}
+
+// RequestLanguage returns the language to respond to the request in: the supported language the
+// Accept-Language header prefers most, DefaultLanguage when none is supported
+func RequestLanguage(c *gin.Context) string {
+	type candidate struct {
+		lang    string
+		quality float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(c.GetHeader("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		// Regional variants (id-ID, en-US) use the catalogue of their language
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if quality > 0 && SupportedLanguage(lang) {
+			candidates = append(candidates, candidate{lang: lang, quality: quality})
+		}
+	}
+	if len(candidates) == 0 {
+		return CurrentSettings().DefaultLanguage
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	return candidates[0].lang
+}
+
+// Translate returns message in lang, message itself when it has no translation
+func Translate(lang, message string) string {
+	if translated, found := messageCatalogues[lang][message]; found {
+		return translated
+	}
+	return message
+}
+
+// Messagef formats a response message in the language of the request: format is translated before
+// the arguments are filled in. Pass the result as the message of SuccessResponse or ErrorResponse.
+func Messagef(c *gin.Context, format string, args ...interface{}) string {
+	return fmt.Sprintf(Translate(RequestLanguage(c), format), args...)
+}
+
+// translateResponse returns the message of a response with statusCode in the language of the request
+// and marks the language of the response. Server errors without a translation get the generic server
+// error message of the language, their detail stays in the error.
+func translateResponse(c *gin.Context, statusCode int, message string) string {
+	lang := RequestLanguage(c)
+	c.Header("Content-Language", lang)
+	if lang == LanguageEnglish {
+		return message
+	}
+	if translated, found := messageCatalogues[lang][message]; found {
+		return translated
+	}
+	if statusCode >= http.StatusInternalServerError {
+		if generic, found := serverErrorMessages[lang]; found {
+			return generic
+		}
+	}
+	return message
+}
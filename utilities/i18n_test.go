@@ -0,0 +1,46 @@
+package utilities
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestResponseMessagesTranslated checks every message the handlers respond with has an Indonesian
+// translation. Server errors are exempt, untranslated ones get the generic server error message.
+func TestResponseMessagesTranslated(t *testing.T) {
+	for _, call := range responseCalls(t) {
+		if call.status >= http.StatusInternalServerError {
+			continue
+		}
+		if call.dynamic {
+			t.Errorf("%s: message is built at the call site, use utilities.Messagef so it can be translated", call.pos)
+			continue
+		}
+		if _, found := indonesianMessages[call.message]; !found {
+			t.Errorf("%s: %q has no Indonesian translation", call.pos, call.message)
+		}
+	}
+}
+
+func TestMessagef(t *testing.T) {
+	tests := []struct {
+		lang string
+		want string
+	}{
+		{lang: LanguageEnglish, want: "Order JNE1 assigned to you"},
+		{lang: LanguageIndonesian, want: "Pesanan JNE1 ditugaskan kepada Anda"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			c.Request.Header.Set("Accept-Language", tt.lang)
+			if got := Messagef(c, "Order %s assigned to you", "JNE1"); got != tt.want {
+				t.Errorf("Messagef = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
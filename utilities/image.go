@@ -0,0 +1,85 @@
+package utilities
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // Registers the GIF decoder
+	"image/jpeg"
+	_ "image/png" // Registers the PNG decoder
+)
+
+// MaxImagePixels caps the dimensions of decoded uploads, a small file can declare a huge image
+const MaxImagePixels = 40_000_000
+
+// DecodeImage decodes a JPEG, PNG or GIF image and returns it with its format
+func DecodeImage(data []byte) (image.Image, string, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("not a JPEG, PNG or GIF image: %w", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxImagePixels {
+		return nil, "", fmt.Errorf("image of %dx%d pixels is too large", config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode %s image: %w", format, err)
+	}
+	return img, format, nil
+}
+
+// Thumbnail scales img down to fit in a size x size square, keeping its aspect ratio, and encodes it
+// as JPEG. Transparent areas become white. Images smaller than the square are not scaled up.
+func Thumbnail(img image.Image, size int, quality int) ([]byte, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	thumbWidth, thumbHeight := width, height
+	if width > size || height > size {
+		if width >= height {
+			thumbWidth, thumbHeight = size, max(height*size/width, 1)
+		} else {
+			thumbWidth, thumbHeight = max(width*size/height, 1), size
+		}
+	}
+
+	// Flatten onto white first, the decoders' own pixel layouts are then read as plain RGBA
+	src := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(src, src.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Over)
+
+	dst := image.NewRGBA(image.Rect(0, 0, thumbWidth, thumbHeight))
+	for y := 0; y < thumbHeight; y++ {
+		y0, y1 := y*height/thumbHeight, max((y+1)*height/thumbHeight, y*height/thumbHeight+1)
+		for x := 0; x < thumbWidth; x++ {
+			x0, x1 := x*width/thumbWidth, max((x+1)*width/thumbWidth, x*width/thumbWidth+1)
+
+			// Average the source pixels the thumbnail pixel covers
+			var r, g, b, n int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					r += int(row[sx*4])
+					g += int(row[sx*4+1])
+					b += int(row[sx*4+2])
+					n++
+				}
+			}
+
+			offset := y*dst.Stride + x*4
+			dst.Pix[offset] = uint8(r / n)
+			dst.Pix[offset+1] = uint8(g / n)
+			dst.Pix[offset+2] = uint8(b / n)
+			dst.Pix[offset+3] = 0xff
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
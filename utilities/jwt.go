@@ -8,24 +8,31 @@ import (
 )
 
 type JWTClaims struct {
-	UserID   uint     `json:"user_id"`
-	Username string   `json:"username"`
-	Roles    []string `json:"roles"`
+	UserID       uint     `json:"user_id"`
+	Username     string   `json:"username"`
+	Roles        []string `json:"roles"`
+	RoleLevel    int      `json:"role_level"`    // Highest role hierarchy level of the user
+	TokenVersion uint     `json:"token_version"` // Must match the user's token version, see models.BumpTokenVersion
+	SessionID    uint     `json:"sid,omitempty"` // Device session the token belongs to, see models.Session
 	jwt.RegisteredClaims
 }
 
 type RefreshClaims struct {
-	UserID uint `json:"user_id"`
+	UserID    uint `json:"user_id"`
+	SessionID uint `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateTokens generates both access and refresh tokens
-func GenerateTokens(userID uint, username string, roles []string, jwtSecret string, jwtExpireHours int, refreshExpireDays int) (string, string, error) {
+// GenerateTokens generates both access and refresh tokens of a device session
+func GenerateTokens(userID uint, username string, roles []string, roleLevel int, tokenVersion uint, sessionID uint, jwtSecret string, jwtExpireHours int, refreshExpireDays int) (string, string, error) {
 	// Generate access token
 	accessClaims := JWTClaims{
-		UserID:   userID,
-		Username: username,
-		Roles:    roles,
+		UserID:       userID,
+		Username:     username,
+		Roles:        roles,
+		RoleLevel:    roleLevel,
+		TokenVersion: tokenVersion,
+		SessionID:    sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * time.Duration(jwtExpireHours))),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -41,7 +48,8 @@ func GenerateTokens(userID uint, username string, roles []string, jwtSecret stri
 
 	// Generate refresh token
 	refreshClaims := RefreshClaims{
-		UserID: userID,
+		UserID:    userID,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24 * time.Duration(refreshExpireDays))),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -99,3 +107,51 @@ func ValidateRefreshToken(tokenString string, jwtSecret string) (*RefreshClaims,
 
 	return claims, nil
 }
+
+// TwoFactorClaims identify a user who passed the password check and still has to send a TOTP code
+type TwoFactorClaims struct {
+	UserID uint `json:"user_id"`
+	jwt.RegisteredClaims
+}
+
+// twoFactorSigningKey derives the key of two-factor tokens from the JWT secret. A separate key keeps
+// them from ever passing as access or refresh tokens.
+func twoFactorSigningKey(jwtSecret string) []byte {
+	return []byte(jwtSecret + ":two-factor")
+}
+
+// GenerateTwoFactorToken generates the short lived token exchanged for real tokens with a TOTP code
+func GenerateTwoFactorToken(userID uint, jwtSecret string, ttl time.Duration) (string, error) {
+	claims := TwoFactorClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(twoFactorSigningKey(jwtSecret))
+}
+
+// ValidateTwoFactorToken validates and parses a two-factor token
+func ValidateTwoFactorToken(tokenString string, jwtSecret string) (*TwoFactorClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &TwoFactorClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return twoFactorSigningKey(jwtSecret), nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*TwoFactorClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid two-factor token")
+	}
+
+	return claims, nil
+}
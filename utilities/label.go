@@ -0,0 +1,169 @@
+package utilities
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Shipping labels are 4x6 inch: 812x1218 dots on a 203 dpi printer, 288x432 points in PDF
+const (
+	labelPDFWidth  = 288
+	labelPDFHeight = 432
+	labelPDFMargin = 14
+	labelMaxItems  = 10
+)
+
+// LabelItem is one order line printed on a shipping label
+type LabelItem struct {
+	Sku      string
+	Variant  string
+	Quantity int
+}
+
+// LabelData holds everything printed on a shipping label
+type LabelData struct {
+	Header       string // Top line, the store name unless the store brands its labels
+	Tracking     string
+	Courier      string
+	Store        string
+	Channel      string
+	Buyer        string
+	Address      string
+	OrderGineeID string
+	SentBefore   string
+	PrintedAt    string
+	Items        []LabelItem
+	MoreItems    int // Lines that did not fit on the label
+}
+
+// NewLabelData fills the label and keeps only as many items as fit on it
+func NewLabelData(data LabelData, items []LabelItem) LabelData {
+	if data.Header == "" {
+		data.Header = data.Store
+	}
+	if len(items) > labelMaxItems {
+		data.MoreItems = len(items) - labelMaxItems
+		items = items[:labelMaxItems]
+	}
+	data.Items = items
+	return data
+}
+
+var labelZPLTemplate = template.Must(template.New("label").Funcs(template.FuncMap{
+	"zpl": escapeZPL,
+	"add": func(a, b int) int { return a + b },
+	"mul": func(a, b int) int { return a * b },
+}).Parse(`^XA
+^CI28
+^PW812
+^LL1218
+^FO40,40^A0N,40,40^FH^FD{{zpl .Header}}^FS
+^FO40,90^A0N,28,28^FH^FD{{zpl .Channel}}^FS
+^FO520,40^A0N,56,56^FH^FD{{zpl .Courier}}^FS
+^FO40,150^GB732,3,3^FS
+^FO60,190^BY3^BCN,200,Y,N,N^FH^FD{{zpl .Tracking}}^FS
+^FO40,460^GB732,3,3^FS
+^FO40,490^A0N,32,32^FH^FDTo: {{zpl .Buyer}}^FS
+^FO40,540^FB732,4,0,L^A0N,26,26^FH^FD{{zpl .Address}}^FS
+^FO40,680^GB732,3,3^FS
+^FO40,710^A0N,26,26^FH^FDOrder: {{zpl .OrderGineeID}}^FS
+^FO40,750^A0N,26,26^FH^FDSend before: {{zpl .SentBefore}}^FS
+{{range $i, $item := .Items}}^FO40,{{add 800 (mul $i 34)}}^A0N,24,24^FH^FD{{$item.Quantity}} x {{zpl $item.Sku}} {{zpl $item.Variant}}^FS
+{{end}}{{if .MoreItems}}^FO40,1140^A0N,24,24^FH^FD+{{.MoreItems}} more line(s)^FS
+{{end}}^FO40,1175^A0N,20,20^FH^FDReprinted {{zpl .PrintedAt}}^FS
+^XZ
+`))
+
+// RenderLabelZPL renders the label as a ZPL II script for thermal printers
+func RenderLabelZPL(data LabelData) ([]byte, error) {
+	var out bytes.Buffer
+	if err := labelZPLTemplate.Execute(&out, data); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// escapeZPL hex-encodes the characters ZPL treats as commands, fields are printed with ^FH
+func escapeZPL(text string) string {
+	return strings.NewReplacer("_", "_5F", "^", "_5E", "~", "_7E", "\n", " ", "\r", "").Replace(text)
+}
+
+// RenderLabelPDF renders the label as a single 4x6 inch PDF page with a Code 128 tracking barcode
+func RenderLabelPDF(data LabelData) ([]byte, error) {
+	widths, err := EncodeCode128(data.Tracking)
+	if err != nil {
+		return nil, err
+	}
+
+	var content bytes.Buffer
+	text := func(font string, size int, x, y float64, value string) {
+		fmt.Fprintf(&content, "BT /%s %d Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escapePDFText(value))
+	}
+	rule := func(y float64) {
+		fmt.Fprintf(&content, "0.8 w %d %.2f m %d %.2f l S\n", labelPDFMargin, y, labelPDFWidth-labelPDFMargin, y)
+	}
+
+	// Header: store branding, channel and courier
+	text("F2", 12, labelPDFMargin, 408, data.Header)
+	text("F1", 9, labelPDFMargin, 394, data.Channel)
+	text("F2", 16, 190, 404, data.Courier)
+	rule(384)
+
+	// Tracking barcode scaled to the printable width, leaving a 10 module quiet zone on each side
+	modules := 0
+	for _, w := range widths {
+		modules += w
+	}
+	moduleWidth := float64(labelPDFWidth-2*labelPDFMargin) / float64(modules+20)
+	if moduleWidth > 2 {
+		moduleWidth = 2
+	}
+	x := (labelPDFWidth - float64(modules)*moduleWidth) / 2
+	for i, w := range widths {
+		if i%2 == 0 {
+			fmt.Fprintf(&content, "%.3f 300 %.3f 70 re f\n", x, float64(w)*moduleWidth)
+		}
+		x += float64(w) * moduleWidth
+	}
+	text("F2", 11, labelPDFMargin, 286, data.Tracking)
+	rule(276)
+
+	// Recipient
+	text("F2", 10, labelPDFMargin, 260, "To: "+data.Buyer)
+	y := 246.0
+	addressLines := wrapPDFText(data.Address, 55)
+	if len(addressLines) > 4 {
+		addressLines = addressLines[:4]
+	}
+	for _, line := range addressLines {
+		text("F1", 9, labelPDFMargin, y, line)
+		y -= 11
+	}
+	rule(196)
+
+	// Order and contents
+	text("F1", 9, labelPDFMargin, 182, "Order: "+data.OrderGineeID)
+	text("F1", 9, labelPDFMargin, 170, "Send before: "+data.SentBefore)
+	y = 152
+	for _, item := range data.Items {
+		text("F1", 8, labelPDFMargin, y, fmt.Sprintf("%d x %s %s", item.Quantity, item.Sku, item.Variant))
+		y -= 10
+	}
+	if data.MoreItems > 0 {
+		text("F1", 8, labelPDFMargin, y, fmt.Sprintf("+%d more line(s)", data.MoreItems))
+	}
+	text("F1", 7, labelPDFMargin, 14, "Reprinted "+data.PrintedAt)
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [5 0 R] /Count 1 >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents 6 0 R >>", labelPDFWidth, labelPDFHeight),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	return writePDFObjects(objects), nil
+}
@@ -0,0 +1,99 @@
+package utilities
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPSettings holds the outgoing mail server settings
+type SMTPSettings struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// MailAttachment is a file attached to an email
+type MailAttachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// SendMail sends a plain text email. Authentication is skipped when no username is configured.
+func SendMail(settings SMTPSettings, to []string, subject, body string) error {
+	return SendMailWithAttachments(settings, to, subject, body)
+}
+
+// SendMailWithAttachments sends a plain text email with files attached. Without attachments the
+// email is sent as plain text like SendMail.
+func SendMailWithAttachments(settings SMTPSettings, to []string, subject, body string, attachments ...MailAttachment) error {
+	if settings.Host == "" {
+		return fmt.Errorf("SMTP host is not configured")
+	}
+	if len(to) == 0 {
+		return fmt.Errorf("no recipients")
+	}
+
+	var auth smtp.Auth
+	if settings.Username != "" {
+		auth = smtp.PlainAuth("", settings.Username, settings.Password, settings.Host)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", settings.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+
+	text := strings.ReplaceAll(body, "\n", "\r\n")
+	if len(attachments) == 0 {
+		msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+		msg.WriteString(text)
+		return smtp.SendMail(settings.Host+":"+settings.Port, auth, settings.From, to, []byte(msg.String()))
+	}
+
+	boundaryBytes := make([]byte, 16)
+	if _, err := rand.Read(boundaryBytes); err != nil {
+		return err
+	}
+	boundary := "livo-" + hex.EncodeToString(boundaryBytes)
+
+	fmt.Fprintf(&msg, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", boundary)
+	fmt.Fprintf(&msg, "--%s\r\n", boundary)
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(text)
+	msg.WriteString("\r\n")
+
+	for _, attachment := range attachments {
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		filename := mime.QEncoding.Encode("UTF-8", attachment.Filename)
+
+		fmt.Fprintf(&msg, "--%s\r\n", boundary)
+		fmt.Fprintf(&msg, "Content-Type: %s; name=%q\r\n", contentType, filename)
+		msg.WriteString("Content-Transfer-Encoding: base64\r\n")
+		fmt.Fprintf(&msg, "Content-Disposition: attachment; filename=%q\r\n\r\n", filename)
+
+		// Base64 lines are limited to 76 characters
+		encoded := base64.StdEncoding.EncodeToString(attachment.Data)
+		for len(encoded) > 76 {
+			msg.WriteString(encoded[:76] + "\r\n")
+			encoded = encoded[76:]
+		}
+		msg.WriteString(encoded + "\r\n")
+	}
+	fmt.Fprintf(&msg, "--%s--\r\n", boundary)
+
+	return smtp.SendMail(settings.Host+":"+settings.Port, auth, settings.From, to, []byte(msg.String()))
+}
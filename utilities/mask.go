@@ -0,0 +1,44 @@
+package utilities
+
+import "strings"
+
+// MaskPhone hides the middle digits of a phone number, keeping the first four and last three
+// digits so the number can still be recognised. Short numbers only keep the last two digits.
+// Numbers masked already are returned as they are.
+func MaskPhone(phone string) string {
+	if strings.Contains(phone, "*") {
+		return phone
+	}
+
+	digits := 0
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	if digits == 0 {
+		return phone
+	}
+
+	keepStart, keepEnd := 4, 3
+	if digits < 10 {
+		keepStart, keepEnd = 0, 2
+	}
+
+	var masked strings.Builder
+	position := 0
+	for _, r := range phone {
+		if r < '0' || r > '9' {
+			masked.WriteRune(r)
+			continue
+		}
+		if position < keepStart || position >= digits-keepEnd {
+			masked.WriteRune(r)
+		} else {
+			masked.WriteRune('*')
+		}
+		position++
+	}
+
+	return masked.String()
+}
@@ -0,0 +1,539 @@
+package utilities
+
+// indonesianMessages translates response messages to Bahasa Indonesia, keyed by the English message
+var indonesianMessages = map[string]string{
+	// Generic
+	"Validation failed":                     "Validasi gagal",
+	"Unauthorized":                          "Tidak memiliki akses",
+	"Insufficient permissions":              "Hak akses tidak mencukupi",
+	"Invalid date format":                   "Format tanggal tidak valid",
+	"Invalid date range":                    "Rentang tanggal tidak valid",
+	"Date range is required":                "Rentang tanggal wajib diisi",
+	"Date range too large":                  "Rentang tanggal terlalu panjang",
+	"Invalid start_date format":             "Format start_date tidak valid",
+	"Invalid end_date format":               "Format end_date tidak valid",
+	"Invalid expected_at format":            "Format expected_at tidak valid",
+	"Invalid format":                        "Format tidak valid",
+	"Invalid export format":                 "Format ekspor tidak valid",
+	"Invalid filters":                       "Filter tidak valid",
+	"Invalid sort":                          "Urutan tidak valid",
+	"Invalid status":                        "Status tidak valid",
+	"Invalid status transition":             "Perubahan status tidak diizinkan",
+	"Invalid month":                         "Bulan tidak valid",
+	"Invalid period":                        "Periode tidak valid",
+	"Invalid schedule":                      "Jadwal tidak valid",
+	"Invalid quantity":                      "Jumlah tidak valid",
+	"Invalid location":                      "Lokasi tidak valid",
+	"Invalid event":                         "Event tidak valid",
+	"Invalid picker_id":                     "picker_id tidak valid",
+	"Invalid operator_id":                   "operator_id tidak valid",
+	"Invalid assigned_to":                   "assigned_to tidak valid",
+	"Invalid at_risk filter":                "Filter at_risk tidak valid",
+	"Invalid held filter":                   "Filter held tidak valid",
+	"Invalid include_deleted filter":        "Filter include_deleted tidak valid",
+	"Invalid include_closed filter":         "Filter include_closed tidak valid",
+	"Invalid instant filter":                "Filter instant tidak valid",
+	"Invalid priority filter":               "Filter priority tidak valid",
+	"Invalid since cursor":                  "Kursor since tidak valid",
+	"Invalid operation":                     "Operasi tidak valid",
+	"Invalid update":                        "Perubahan tidak valid",
+	"Update not allowed":                    "Perubahan tidak diizinkan",
+	"Reason is required":                    "Alasan wajib diisi",
+	"Query timed out":                       "Waktu query habis",
+	"Request cancelled":                     "Permintaan dibatalkan",
+	"Daily quota exceeded":                  "Kuota harian telah habis",
+	"Quotas retrieved successfully":         "Kuota berhasil diambil",
+	"Error codes retrieved successfully":    "Kode error berhasil diambil",
+	"Routes retrieved successfully":         "Daftar route berhasil diambil",
+	"GraphQL schema retrieved successfully": "Skema GraphQL berhasil diambil",
+	"Unknown bulk operation":                "Operasi massal tidak dikenal",
+	"Unknown mutation type":                 "Jenis mutasi tidak dikenal",
+	"Invalid bulk failure payload":          "Data kegagalan massal tidak valid",
+	"Image is required":                     "Gambar wajib diunggah",
+	"Image too large":                       "Ukuran gambar terlalu besar",
+	"Invalid image":                         "Gambar tidak valid",
+	" (filtered by %s)":                     " (difilter berdasarkan %s)",
+	" (filtered by code: %s)":               " (difilter berdasarkan kode: %s)",
+	" (filtered by code or name: %s)":       " (difilter berdasarkan kode atau nama: %s)",
+	" (filtered by tracking: %s)":           " (difilter berdasarkan tracking: %s)",
+	" (filtered by SKU: %s)":                " (difilter berdasarkan SKU: %s)",
+	" (filtered by status: %s)":             " (difilter berdasarkan status: %s)",
+
+	// Authentication and sessions
+	"Authorization header is required":                         "Header Authorization wajib diisi",
+	"Invalid authorization header format":                      "Format header Authorization tidak valid",
+	"Invalid token":                                            "Token tidak valid",
+	"Invalid refresh token":                                    "Refresh token tidak valid",
+	"Session expired":                                          "Sesi telah berakhir, silakan login kembali",
+	"Invalid API key":                                          "API key tidak valid",
+	"API key scope does not allow this request":                "Cakupan API key tidak mengizinkan permintaan ini",
+	"No roles found in token":                                  "Tidak ada role pada token",
+	"Invalid roles format":                                     "Format role tidak valid",
+	"User not authenticated":                                   "Pengguna belum login",
+	"Invalid credentials":                                      "Username atau password salah",
+	"Invalid coordinator credentials":                          "Kredensial koordinator salah",
+	"Account is inactive":                                      "Akun tidak aktif",
+	"Account is locked":                                        "Akun terkunci sementara",
+	"User is inactive":                                         "Pengguna tidak aktif",
+	"Login successful":                                         "Login berhasil",
+	"Logout successful":                                        "Logout berhasil",
+	"Token refreshed successfully":                             "Token berhasil diperbarui",
+	"Current password is incorrect":                            "Password saat ini salah",
+	"New password must be different from the current password": "Password baru harus berbeda dari password saat ini",
+	"Password does not meet the policy":                        "Password tidak memenuhi ketentuan",
+	"Password changed successfully, please login again":        "Password berhasil diubah, silakan login kembali",
+	"Password updated successfully":                            "Password berhasil diperbarui",
+	"Two-factor code required":                                 "Kode verifikasi dua langkah diperlukan",
+	"Two-factor authentication is required":                    "Verifikasi dua langkah wajib diaktifkan",
+	"Two-factor authentication is required for your role, add the secret to an authenticator app and send its code": "Role Anda wajib menggunakan verifikasi dua langkah, tambahkan secret ke aplikasi authenticator lalu kirim kodenya",
+	"Add the secret to an authenticator app and confirm with its code":                                              "Tambahkan secret ke aplikasi authenticator lalu konfirmasi dengan kodenya",
+	"Invalid two-factor code":                      "Kode verifikasi dua langkah salah",
+	"Invalid two-factor token":                     "Token verifikasi dua langkah tidak valid",
+	"Too many invalid two-factor codes":            "Terlalu banyak kode verifikasi dua langkah yang salah",
+	"Two-factor authentication already enabled":    "Verifikasi dua langkah sudah aktif",
+	"Two-factor authentication not enabled":        "Verifikasi dua langkah belum aktif",
+	"Two-factor authentication not enrolled":       "Verifikasi dua langkah belum didaftarkan",
+	"Two-factor authentication enabled":            "Verifikasi dua langkah berhasil diaktifkan",
+	"Two-factor authentication disabled":           "Verifikasi dua langkah berhasil dinonaktifkan",
+	"Two-factor authentication reset successfully": "Verifikasi dua langkah berhasil direset",
+	"Two-factor enrollment required":               "Pendaftaran verifikasi dua langkah diperlukan",
+	"Invalid enrollment token":                     "Token pendaftaran tidak valid",
+	"Enrollment token issued successfully":         "Token pendaftaran berhasil dibuat",
+	"Invalid session ID":                           "ID sesi tidak valid",
+	"Session not found":                            "Sesi tidak ditemukan",
+	"Session revoked successfully":                 "Sesi berhasil dicabut",
+	"Sessions retrieved successfully":              "Daftar sesi berhasil diambil",
+	"API key not found":                            "API key tidak ditemukan",
+	"Invalid API key ID":                           "ID API key tidak valid",
+	"API key already revoked":                      "API key sudah dicabut",
+	"API key revoked successfully":                 "API key berhasil dicabut",
+	"API keys retrieved successfully":              "Daftar API key berhasil diambil",
+	"API key created successfully, store it now as it will not be shown again": "API key berhasil dibuat, simpan sekarang karena tidak akan ditampilkan lagi",
+	"Invalid scopes": "Cakupan tidak valid",
+
+	// Users and roles
+	"User not found":                                   "Pengguna tidak ditemukan",
+	"User already exists":                              "Pengguna sudah ada",
+	"Email already exists":                             "Email sudah terdaftar",
+	"Email already taken":                              "Email sudah digunakan",
+	"Invalid user ID":                                  "ID pengguna tidak valid",
+	"Invalid user ID parameter":                        "Parameter ID pengguna tidak valid",
+	"Cannot delete your own account":                   "Tidak dapat menghapus akun sendiri",
+	"Insufficient permissions to assign this role":     "Hak akses tidak mencukupi untuk memberikan role ini",
+	"Insufficient permissions to delete this user":     "Hak akses tidak mencukupi untuk menghapus pengguna ini",
+	"Insufficient permissions to remove this role":     "Hak akses tidak mencukupi untuk mencabut role ini",
+	"Insufficient permissions to update this user":     "Hak akses tidak mencukupi untuk mengubah pengguna ini",
+	"Invalid role specified":                           "Role tidak valid",
+	"Role not found":                                   "Role tidak ditemukan",
+	"Invalid role ID":                                  "ID role tidak valid",
+	"Config setting not found":                         "Pengaturan tidak ditemukan",
+	"Config override not found":                        "Pengaturan tidak sedang ditimpa",
+	"Invalid address":                                  "Alamat tidak valid",
+	"Invalid config value":                             "Nilai pengaturan tidak valid",
+	"Role template not found":                          "Template role tidak ditemukan",
+	"User already has this role":                       "Pengguna sudah memiliki role ini",
+	"Role assigned successfully":                       "Role berhasil diberikan",
+	"Role removed successfully":                        "Role berhasil dicabut",
+	"Role two-factor requirement updated successfully": "Kewajiban verifikasi dua langkah role berhasil diperbarui",
+	"Roles retrieved successfully":                     "Daftar role berhasil diambil",
+	"User created successfully":                        "Pengguna berhasil dibuat",
+	"User deleted successfully":                        "Pengguna berhasil dihapus",
+	"User registered successfully":                     "Pengguna berhasil didaftarkan",
+	"User retrieved successfully":                      "Pengguna berhasil diambil",
+	"User profile updated successfully":                "Profil pengguna berhasil diperbarui",
+	"User status updated successfully":                 "Status pengguna berhasil diperbarui",
+	"User unlocked successfully":                       "Akun pengguna berhasil dibuka",
+	"Users retrieved successfully":                     "Daftar pengguna berhasil diambil",
+	"Profile retrieved successfully":                   "Profil berhasil diambil",
+	"Profile updated successfully":                     "Profil berhasil diperbarui",
+	"Contact reveals retrieved successfully":           "Riwayat pembukaan kontak berhasil diambil",
+
+	// Orders and picking
+	"Order not found":                                                     "Order tidak ditemukan",
+	"Order detail not found":                                              "Detail order tidak ditemukan",
+	"Invalid order ID":                                                    "ID order tidak valid",
+	"Invalid order detail ID":                                             "ID detail order tidak valid",
+	"Invalid revision ID":                                                 "ID revisi tidak valid",
+	"Order is cancelled":                                                  "Order sudah dibatalkan",
+	"Order is on hold":                                                    "Order sedang ditahan",
+	"Order is not on hold":                                                "Order tidak sedang ditahan",
+	"Order already sent out":                                              "Order sudah dikirim",
+	"Order already cancelled":                                             "Order sudah dibatalkan",
+	"Order already duplicated":                                            "Order sudah diduplikasi",
+	"Order already merged":                                                "Order sudah digabungkan",
+	"Order already finished qc":                                           "Order sudah selesai QC",
+	"Order already in QC process":                                         "Order sudah dalam proses QC",
+	"Order has no details":                                                "Order tidak memiliki detail",
+	"Order has no tracking number":                                        "Order tidak memiliki nomor resi",
+	"Order is not deleted":                                                "Order tidak dalam keadaan terhapus",
+	"Order deletion not allowed":                                          "Order tidak boleh dihapus",
+	"Order modification not allowed":                                      "Order tidak boleh diubah",
+	"Order was modified by someone else":                                  "Order sudah diubah oleh pengguna lain, muat ulang lalu coba lagi",
+	"Order not found or not in picking process":                           "Order tidak ditemukan atau tidak dalam proses picking",
+	"Order not in picking process":                                        "Order tidak dalam proses picking",
+	"Order not fully scanned":                                             "Order belum selesai discan",
+	"Orders cannot be merged":                                             "Order tidak dapat digabungkan",
+	"Duplicate limit reached":                                             "Batas duplikasi order telah tercapai",
+	"No order available":                                                  "Tidak ada order yang tersedia",
+	"Cannot assign picker":                                                "Picker tidak dapat ditugaskan",
+	"Picker not found":                                                    "Picker tidak ditemukan",
+	"Picker assigned successfully":                                        "Picker berhasil ditugaskan",
+	"Barcode mismatch":                                                    "Barcode tidak sesuai",
+	"Scanned quantity exceeds ordered quantity":                           "Jumlah scan melebihi jumlah order",
+	"Cannot print label for a cancelled order":                            "Label order yang dibatalkan tidak dapat dicetak",
+	"Failed to render label":                                              "Gagal membuat label",
+	"Invalid label format":                                                "Format label tidak valid",
+	"Invalid replacement order":                                           "Order pengganti tidak valid",
+	"Invalid pickup cutoff":                                               "Batas waktu pickup tidak valid",
+	"Assigned orders retrieved successfully":                              "Daftar order yang ditugaskan berhasil diambil",
+	"Orders retrieved successfully":                                       "Daftar order berhasil diambil",
+	"Order retrieved successfully":                                        "Order berhasil diambil",
+	"Order updated successfully":                                          "Order berhasil diperbarui",
+	"Order deleted successfully":                                          "Order berhasil dihapus",
+	"Order restored successfully":                                         "Order berhasil dipulihkan",
+	"Order cancelled successfully":                                        "Order berhasil dibatalkan",
+	"Order status summary retrieved successfully":                         "Ringkasan status order berhasil diambil",
+	"Order held successfully":                                             "Order berhasil ditahan",
+	"Orders auto-assigned successfully":                                   "Order berhasil dibagikan otomatis ke picker",
+	"Auto-assignment proposed successfully":                               "Usulan pembagian order ke picker berhasil dibuat",
+	"Order released successfully":                                         "Order berhasil dilepas dari penahanan",
+	"Order duplicated successfully":                                       "Order berhasil diduplikasi",
+	"Orders merged successfully":                                          "Order berhasil digabungkan",
+	"Order contact revealed":                                              "Kontak order berhasil ditampilkan",
+	"Order complained status updated successfully":                        "Status komplain order berhasil diperbarui",
+	"Order marked as complained":                                          "Order ditandai sebagai komplain",
+	"Order unmarked as complained":                                        "Tanda komplain order berhasil dihapus",
+	"Order history retrieved successfully":                                "Riwayat order berhasil diambil",
+	"Order revisions retrieved successfully":                              "Revisi order berhasil diambil",
+	"Order revision diff retrieved successfully":                          "Perbandingan revisi order berhasil diambil",
+	"Order timeline retrieved successfully":                               "Linimasa order berhasil diambil",
+	"Order delivery history retrieved successfully":                       "Riwayat pengiriman order berhasil diambil",
+	"Order events report retrieved successfully":                          "Laporan event order berhasil diambil",
+	"Order set to pending pick successfully":                              "Order berhasil ditunda pickingnya",
+	"Order status updated to QC process":                                  "Status order diperbarui menjadi proses QC",
+	"Order status updated to picking completed":                           "Status order diperbarui menjadi picking selesai",
+	"Order picking completed successfully and pick order records created": "Picking order berhasil diselesaikan",
+	"Replacement order linked successfully":                               "Order pengganti berhasil ditautkan",
+	"Replacement order unlinked successfully":                             "Tautan order pengganti berhasil dihapus",
+	"Bulk order creation completed":                                       "Pembuatan order massal selesai",
+	"Bulk order creation completed with some issues":                      "Pembuatan order massal selesai dengan beberapa masalah",
+	"No orders could be created":                                          "Tidak ada order yang berhasil dibuat",
+	"Bulk user import completed":                                          "Impor pengguna massal selesai",
+	"Bulk user import completed with some issues":                         "Impor pengguna massal selesai dengan beberapa masalah",
+	"No users could be created":                                           "Tidak ada pengguna yang berhasil dibuat",
+	"No users to import":                                                  "Tidak ada pengguna untuk diimpor",
+	"Too many users":                                                      "Terlalu banyak pengguna",
+	"CSV file is required":                                                "File CSV wajib diisi",
+	"Invalid CSV file":                                                    "File CSV tidak valid",
+	"All orders were skipped":                                             "Semua order dilewati",
+	"All orders were skipped (already exist)":                             "Semua order dilewati (sudah ada)",
+	"Bulk picker assignment completed":                                    "Penugasan picker massal selesai",
+	"Bulk picker assignment completed with some issues":                   "Penugasan picker massal selesai dengan beberapa masalah",
+	"No orders could be assigned":                                         "Tidak ada order yang berhasil ditugaskan",
+	"Pick order not found":                                                "Pick order tidak ditemukan",
+	"Pick order retrieved successfully":                                   "Pick order berhasil diambil",
+	"Pick orders retrieved successfully":                                  "Daftar pick order berhasil diambil",
+	"Picker dashboard retrieved successfully":                             "Dashboard picker berhasil diambil",
+	"Picker productivity report retrieved successfully":                   "Laporan produktivitas picker berhasil diambil",
+	"Found %d order(s) currently being picked for you":                    "Ditemukan %d pesanan yang sedang Anda pick",
+	"Found %d picked order(s)":                                            "Ditemukan %d pesanan yang sudah di-pick",
+	"Order %s assigned to you":                                            "Pesanan %s ditugaskan kepada Anda",
+	"Scanned %d of %d for %s":                                             "Terpindai %d dari %d untuk %s",
+	"Synced %d mutation(s): %d applied, %d rejected, %d failed":           "%d mutasi disinkronkan: %d diterapkan, %d ditolak, %d gagal",
+	"Successfully assigned %d order(s) to picker":                         "%d pesanan berhasil ditugaskan ke picker",
+	"Order cancelled successfully, %d pick and QC record(s) flagged as cancelled": "Pesanan berhasil dibatalkan, %d data pick dan QC ditandai batal",
+
+	// Trackings
+	"Tracking not found":                            "Nomor resi tidak ditemukan",
+	"Tracking already exists":                       "Nomor resi sudah ada",
+	"Tracking cannot be changed":                    "Nomor resi tidak dapat diubah",
+	"Tracking does not match any expedition":        "Nomor resi tidak cocok dengan ekspedisi mana pun",
+	"Invalid tracking":                              "Nomor resi tidak valid",
+	"Invalid tracking code":                         "Kode resi tidak valid",
+	"Invalid tracking number":                       "Nomor resi tidak valid",
+	"Invalid tracking validation rules":             "Aturan validasi nomor resi tidak valid",
+	"Invalid rename":                                "Perubahan nomor resi tidak valid",
+	"All trackings were skipped (already sent out)": "Semua nomor resi dilewati (sudah dikirim)",
+	"%d tracking(s) renumbered successfully":        "%d tracking berhasil diganti nomornya",
+	"Tracking resolves to %s":                       "Tracking termasuk ekspedisi %s",
+
+	// QC
+	"QC process required":                                     "Order harus melalui proses QC terlebih dahulu",
+	"Invalid parcel":                                          "Nomor paket tidak valid",
+	"Duplicate box ID":                                        "ID box duplikat",
+	"Invalid QC items":                                        "Item QC tidak valid",
+	"QC already verified":                                     "Isi paket sudah diverifikasi",
+	"QC verified successfully":                                "Isi paket sesuai dengan order",
+	"QC verified with discrepancies":                          "Isi paket tidak sesuai dengan order, selisih sudah dicatat",
+	"QC dashboard retrieved successfully":                     "Dashboard QC berhasil diambil",
+	"Returns aging report retrieved successfully":             "Laporan umur retur berhasil diambil",
+	"Cycle times report retrieved successfully":               "Laporan waktu siklus pesanan berhasil diambil",
+	"Channel intake report retrieved successfully":            "Laporan order masuk per channel berhasil diambil",
+	"Channel intake updated successfully":                     "Batas order masuk channel berhasil diperbarui",
+	"Destinations report retrieved successfully":              "Laporan tujuan pengiriman berhasil diambil",
+	"QC Online with this tracking already exists":             "QC online dengan nomor resi ini sudah ada",
+	"Qc-online already recorded":                              "QC online sudah tercatat",
+	"Qc-online created successfully":                          "QC online berhasil dibuat",
+	"Qc-online not found":                                     "QC online tidak ditemukan",
+	"Qc-online retrieved successfully":                        "QC online berhasil diambil",
+	"Qc-onlines retrieved successfully":                       "Daftar QC online berhasil diambil",
+	"Qc-ribbon with this tracking already exists":             "QC ribbon dengan nomor resi ini sudah ada",
+	"Qc-ribbon already recorded":                              "QC ribbon sudah tercatat",
+	"Qc-ribbon created successfully":                          "QC ribbon berhasil dibuat",
+	"Qc-ribbon not found":                                     "QC ribbon tidak ditemukan",
+	"Qc-ribbon retrieved successfully":                        "QC ribbon berhasil diambil",
+	"Qc-ribbons retrieved successfully":                       "Daftar QC ribbon berhasil diambil",
+	"Box suggestions retrieved successfully":                  "Saran box berhasil diambil",
+	"Online flow retrieved successfully":                      "Alur online berhasil diambil",
+	"Online flows retrieved successfully":                     "Daftar alur online berhasil diambil",
+	"Ribbon flow retrieved successfully":                      "Alur ribbon berhasil diambil",
+	"Ribbon flows retrieved successfully":                     "Daftar alur ribbon berhasil diambil",
+	"Qc-online daily counts for %s %d retrieved successfully": "Jumlah harian QC online %s %d berhasil diambil",
+	"Qc-ribbon daily counts for %s %d retrieved successfully": "Jumlah harian QC ribbon %s %d berhasil diambil",
+
+	// Outbound
+	"Outbound not found":                                     "Outbound tidak ditemukan",
+	"Outbound already recorded":                              "Outbound sudah tercatat",
+	"Outbound created successfully":                          "Outbound berhasil dibuat",
+	"Outbound retrieved successfully":                        "Outbound berhasil diambil",
+	"Outbound updated successfully":                          "Outbound berhasil diperbarui",
+	"Outbounds retrieved successfully":                       "Daftar outbound berhasil diambil",
+	"Outbound reports retrieved successfully":                "Laporan outbound berhasil diambil",
+	"Duplicate parcel blocked":                               "Paket duplikat diblokir",
+	"Courier mismatch":                                       "Kurir tidak sesuai dengan order",
+	"Invalid override token":                                 "Token override tidak valid",
+	"Outbound alert not found":                               "Peringatan outbound tidak ditemukan",
+	"Order revision not found":                               "Revisi order tidak ditemukan",
+	"Outbound alert already resolved":                        "Peringatan outbound sudah diselesaikan",
+	"Outbound alert resolved successfully":                   "Peringatan outbound berhasil diselesaikan",
+	"Outbound alerts retrieved successfully":                 "Daftar peringatan outbound berhasil diambil",
+	"Alert cannot be overridden":                             "Peringatan tidak dapat diabaikan",
+	"Bulk outbound scan completed":                           "Scan outbound massal selesai",
+	"Bulk outbound scan completed with some issues":          "Scan outbound massal selesai dengan beberapa masalah",
+	"No outbounds could be created":                          "Tidak ada outbound yang berhasil dibuat",
+	"Outbound daily counts for %s %d retrieved successfully": "Jumlah harian outbound %s %d berhasil diambil",
+
+	// Returns and putaway
+	"Return not found":                                           "Retur tidak ditemukan",
+	"Invalid return ID":                                          "ID retur tidak valid",
+	"Return retrieved successfully":                              "Retur berhasil diambil",
+	"Returns retrieved successfully":                             "Daftar retur berhasil diambil",
+	"Return data updated successfully":                           "Data retur berhasil diperbarui",
+	"Return mobile created successfully":                         "Retur berhasil dicatat",
+	"Return mobile retrieved successfully":                       "Retur berhasil diambil",
+	"Return mobile tracking already exists":                      "Retur dengan nomor resi ini sudah ada",
+	"Return mobiles from the last 7 days retrieved successfully": "Daftar retur 7 hari terakhir berhasil diambil",
+	"Failed to create return details":                            "Gagal menyimpan detail retur",
+	"Return reports retrieved successfully":                      "Laporan retur berhasil diambil",
+	"Return valuation report retrieved successfully":             "Laporan nilai retur berhasil diambil",
+	"Putaway task not found":                                     "Tugas putaway tidak ditemukan",
+	"Putaway task already completed":                             "Tugas putaway sudah selesai",
+	"Putaway task completed successfully":                        "Tugas putaway berhasil diselesaikan",
+	"Putaway tasks retrieved successfully":                       "Daftar tugas putaway berhasil diambil",
+	"Return created successfully (%d of %d products synced)":     "Retur berhasil dibuat (%d dari %d produk tersinkron)",
+	". Warning: %d product(s) not found - SKU: %s":               ". Peringatan: %d produk tidak ditemukan - SKU: %s",
+
+	// Complains
+	"Complain not found":                                 "Komplain tidak ditemukan",
+	"Complain tracking already exists":                   "Komplain dengan nomor resi ini sudah ada",
+	"Complain already checked":                           "Komplain sudah diperiksa",
+	"Complain cannot be escalated":                       "Komplain tidak dapat dieskalasi",
+	"Cannot split fee":                                   "Biaya tidak dapat dibagi",
+	"Complain created successfully":                      "Komplain berhasil dibuat",
+	"Complain retrieved successfully":                    "Komplain berhasil diambil",
+	"Complains retrieved successfully":                   "Daftar komplain berhasil diambil",
+	"Complain solution updated successfully":             "Solusi komplain berhasil diperbarui",
+	"Complain check status updated successfully":         "Status pemeriksaan komplain berhasil diperbarui",
+	"Complain fee split calculated successfully":         "Pembagian biaya komplain berhasil dihitung",
+	"Complain reports retrieved successfully":            "Laporan komplain berhasil diambil",
+	"Complain escalation reports retrieved successfully": "Laporan eskalasi komplain berhasil diambil",
+	"Overdue complains retrieved successfully":           "Daftar komplain yang melewati tenggat berhasil diambil",
+	"User fee reports retrieved successfully":            "Laporan biaya pengguna berhasil diambil",
+	"Complain escalated to %s successfully":              "Komplain berhasil dieskalasi ke %s",
+	"Complain status updated to %s successfully":         "Status komplain berhasil diubah menjadi %s",
+
+	// Inbounds
+	"Inbound not found":                        "Inbound tidak ditemukan",
+	"Invalid inbound ID":                       "ID inbound tidak valid",
+	"Inbound reference already exists":         "Referensi inbound sudah ada",
+	"Inbound cannot be changed":                "Inbound tidak dapat diubah",
+	"Invalid inbound details":                  "Detail inbound tidak valid",
+	"Invalid inbound count":                    "Jumlah hitungan inbound tidak valid",
+	"Inbound has unchecked lines":              "Masih ada item inbound yang belum dihitung",
+	"Inbound created successfully":             "Inbound berhasil dibuat",
+	"Inbound updated successfully":             "Inbound berhasil diperbarui",
+	"Inbound deleted successfully":             "Inbound berhasil dihapus",
+	"Inbound retrieved successfully":           "Inbound berhasil diambil",
+	"Inbounds retrieved successfully":          "Daftar inbound berhasil diambil",
+	"Inbound checked successfully":             "Hitungan inbound berhasil disimpan",
+	"Inbound checklist retrieved successfully": "Checklist inbound berhasil diambil",
+
+	// Master data
+	"Box not found":                                                  "Box tidak ditemukan",
+	"Box code already exists":                                        "Kode box sudah ada",
+	"Box created successfully":                                       "Box berhasil dibuat",
+	"Box updated successfully":                                       "Box berhasil diperbarui",
+	"Box removed successfully":                                       "Box berhasil dihapus",
+	"Box retrieved successfully":                                     "Box berhasil diambil",
+	"Boxes retrieved successfully":                                   "Daftar box berhasil diambil",
+	"Box stock updated successfully":                                 "Stok box berhasil diperbarui",
+	"Box forecast retrieved successfully":                            "Perkiraan kebutuhan box berhasil diambil",
+	"Box suggestion report retrieved successfully":                   "Laporan saran box berhasil diambil",
+	"Box count reports retrieved successfully":                       "Laporan jumlah box berhasil diambil",
+	"Channel not found":                                              "Channel tidak ditemukan",
+	"Channel code already exists":                                    "Kode channel sudah ada",
+	"Channel created successfully":                                   "Channel berhasil dibuat",
+	"Channel updated successfully":                                   "Channel berhasil diperbarui",
+	"Channel removed successfully":                                   "Channel berhasil dihapus",
+	"Channel retrieved successfully":                                 "Channel berhasil diambil",
+	"Channels retrieved successfully":                                "Daftar channel berhasil diambil",
+	"Expedition not found":                                           "Ekspedisi tidak ditemukan",
+	"Expedition code already exists":                                 "Kode ekspedisi sudah ada",
+	"Expedition created successfully":                                "Ekspedisi berhasil dibuat",
+	"Expedition updated successfully":                                "Ekspedisi berhasil diperbarui",
+	"Expedition removed successfully":                                "Ekspedisi berhasil dihapus",
+	"Expedition retrieved successfully":                              "Ekspedisi berhasil diambil",
+	"Expeditions retrieved successfully":                             "Daftar ekspedisi berhasil diambil",
+	"Product not found":                                              "Produk tidak ditemukan",
+	"Invalid product ID":                                             "ID produk tidak valid",
+	"Product created successfully":                                   "Produk berhasil dibuat",
+	"Product updated successfully":                                   "Produk berhasil diperbarui",
+	"Product removed successfully":                                   "Produk berhasil dihapus",
+	"Product retrieved successfully":                                 "Produk berhasil diambil",
+	"Products retrieved successfully":                                "Daftar produk berhasil diambil",
+	"Product image uploaded successfully":                            "Gambar produk berhasil diunggah",
+	"Product changes retrieved successfully":                         "Riwayat perubahan produk berhasil diambil",
+	"Store not found":                                                "Toko tidak ditemukan",
+	"Store code already exists":                                      "Kode toko sudah ada",
+	"Store created successfully":                                     "Toko berhasil dibuat",
+	"Store updated successfully":                                     "Toko berhasil diperbarui",
+	"Box is still in use":                                            "Box masih digunakan",
+	"Box is not deleted":                                             "Box tidak dalam keadaan terhapus",
+	"Box restored successfully":                                      "Box berhasil dipulihkan",
+	"Deleted boxes retrieved successfully":                           "Box yang terhapus berhasil diambil",
+	"Channel is still in use":                                        "Channel masih digunakan",
+	"Channel is not deleted":                                         "Channel tidak dalam keadaan terhapus",
+	"Channel restored successfully":                                  "Channel berhasil dipulihkan",
+	"Deleted channels retrieved successfully":                        "Channel yang terhapus berhasil diambil",
+	"Store is still in use":                                          "Toko masih digunakan",
+	"Store is not deleted":                                           "Toko tidak dalam keadaan terhapus",
+	"Store restored successfully":                                    "Toko berhasil dipulihkan",
+	"Deleted stores retrieved successfully":                          "Toko yang terhapus berhasil diambil",
+	"Expedition is still in use":                                     "Ekspedisi masih digunakan",
+	"Expedition is not deleted":                                      "Ekspedisi tidak dalam keadaan terhapus",
+	"Expedition restored successfully":                               "Ekspedisi berhasil dipulihkan",
+	"Deleted expeditions retrieved successfully":                     "Ekspedisi yang terhapus berhasil diambil",
+	"Store removed successfully":                                     "Toko berhasil dihapus",
+	"Store retrieved successfully":                                   "Toko berhasil diambil",
+	"Stores retrieved successfully":                                  "Daftar toko berhasil diambil",
+	"Store settings retrieved successfully":                          "Pengaturan toko berhasil diambil",
+	"Store settings updated successfully":                            "Pengaturan toko berhasil diperbarui",
+	"Store mobiles retrieved successfully":                           "Daftar toko berhasil diambil",
+	"Zone not found":                                                 "Zona tidak ditemukan",
+	"Zone code already exists":                                       "Kode zona sudah ada",
+	"Zone created successfully":                                      "Zona berhasil dibuat",
+	"Config retrieved successfully":                                  "Pengaturan berhasil diambil",
+	"Config updated successfully":                                    "Pengaturan berhasil diperbarui",
+	"Config reset successfully":                                      "Pengaturan berhasil dikembalikan ke nilai environment",
+	"Config changes retrieved successfully":                          "Riwayat perubahan pengaturan berhasil diambil",
+	"Role templates retrieved successfully":                          "Template role berhasil diambil",
+	"Role template created successfully":                             "Template role berhasil dibuat",
+	"Role template updated successfully":                             "Template role berhasil diperbarui",
+	"Role template removed successfully":                             "Template role berhasil dihapus",
+	"Zone updated successfully":                                      "Zona berhasil diperbarui",
+	"Zone removed successfully":                                      "Zona berhasil dihapus",
+	"Zones retrieved successfully":                                   "Daftar zona berhasil diambil",
+	"Pickup slot not found":                                          "Jadwal pickup tidak ditemukan",
+	"Pickup slot already exists":                                     "Jadwal pickup sudah ada",
+	"Invalid pickup time":                                            "Jam pickup tidak valid",
+	"Pickup slot created successfully":                               "Jadwal pickup berhasil dibuat",
+	"Pickup slot updated successfully":                               "Jadwal pickup berhasil diperbarui",
+	"Pickup slot removed successfully":                               "Jadwal pickup berhasil dihapus",
+	"Pickup slots retrieved successfully":                            "Daftar jadwal pickup berhasil diambil",
+	"Pickups retrieved successfully":                                 "Daftar pickup berhasil diambil",
+	"Zone pickers updated successfully":                              "Picker zona berhasil diperbarui",
+	"Lost and found item not found":                                  "Barang lost and found tidak ditemukan",
+	"Lost and found item created successfully":                       "Barang lost and found berhasil dicatat",
+	"Lost and found item updated successfully":                       "Barang lost and found berhasil diperbarui",
+	"Lost and found item removed successfully":                       "Barang lost and found berhasil dihapus",
+	"Lost and found item retrieved successfully":                     "Barang lost and found berhasil diambil",
+	"Lost and found items retrieved successfully":                    "Daftar barang lost and found berhasil diambil",
+	"Lost and found items retrieved successfully with search filter": "Daftar barang lost and found berhasil diambil dengan filter pencarian",
+	"Lost and found item resolved successfully":                      "Barang lost and found berhasil diselesaikan",
+	"Lost and found item already resolved":                           "Barang lost and found sudah diselesaikan",
+	"Notification not found":                                         "Notifikasi tidak ditemukan",
+	"Notification marked as read":                                    "Notifikasi ditandai sudah dibaca",
+	"Notifications retrieved successfully":                           "Daftar notifikasi berhasil diambil",
+	"Products synced: %d created, %d updated, %d deleted, %d unchanged, %d failed": "Produk tersinkron: %d dibuat, %d diperbarui, %d dihapus, %d tidak berubah, %d gagal",
+
+	// Reports and presets
+	"Daily summary retrieved successfully":              "Ringkasan harian berhasil diambil",
+	"Packing billing report retrieved successfully":     "Laporan tagihan packing berhasil diambil",
+	"Operator fault scores retrieved successfully":      "Skor kesalahan operator berhasil diambil",
+	"Report cannot be exported":                         "Laporan tidak dapat diekspor",
+	"Report preset not found":                           "Preset laporan tidak ditemukan",
+	"Invalid report preset ID":                          "ID preset laporan tidak valid",
+	"Report preset name already exists":                 "Nama preset laporan sudah ada",
+	"Role template name already exists":                 "Nama template role sudah ada",
+	"Invalid report preset target":                      "Tujuan preset laporan tidak valid",
+	"Report preset created successfully":                "Preset laporan berhasil dibuat",
+	"Report preset updated successfully":                "Preset laporan berhasil diperbarui",
+	"Report preset deleted successfully":                "Preset laporan berhasil dihapus",
+	"Report preset applied successfully":                "Preset laporan berhasil diterapkan",
+	"Report preset retrieved successfully":              "Preset laporan berhasil diambil",
+	"Report presets retrieved successfully":             "Daftar preset laporan berhasil diambil",
+	"Failed to export report":                           "Gagal mengekspor laporan",
+	"Complain fee report for %s retrieved successfully": "Laporan biaya komplain %s berhasil diambil",
+	"Complain fees of %d operators approved":            "Biaya komplain %d operator disetujui",
+	"%d complain fee approvals withdrawn":               "%d persetujuan biaya komplain ditarik",
+
+	// Operations
+	"Bulk failure not found":                 "Kegagalan massal tidak ditemukan",
+	"Invalid bulk failure ID":                "ID kegagalan massal tidak valid",
+	"Bulk failure already resolved":          "Kegagalan massal sudah diselesaikan",
+	"Bulk failure retried successfully":      "Kegagalan massal berhasil dicoba ulang",
+	"Bulk failures retrieved successfully":   "Daftar kegagalan massal berhasil diambil",
+	"Retry failed again":                     "Percobaan ulang gagal lagi",
+	"Backfill not found":                     "Backfill tidak ditemukan",
+	"Backfill run not found":                 "Proses backfill tidak ditemukan",
+	"Invalid backfill run ID":                "ID proses backfill tidak valid",
+	"Backfill is already running":            "Backfill sedang berjalan",
+	"Backfill started":                       "Backfill dimulai",
+	"Backfill run retrieved successfully":    "Proses backfill berhasil diambil",
+	"Backfills retrieved successfully":       "Daftar backfill berhasil diambil",
+	"Backup not found":                       "Backup tidak ditemukan",
+	"Invalid backup ID":                      "ID backup tidak valid",
+	"Backups are disabled":                   "Backup tidak diaktifkan",
+	"A backup is already running":            "Backup sedang berjalan",
+	"Backup started":                         "Backup dimulai",
+	"Backup cannot be verified":              "Backup tidak dapat diverifikasi",
+	"Backup verification is already running": "Verifikasi backup sedang berjalan",
+	"Backup verification started":            "Verifikasi backup dimulai",
+	"Backup health retrieved successfully":   "Kesehatan backup berhasil diambil",
+	"Ginee sync is disabled":                 "Sinkronisasi Ginee tidak diaktifkan",
+	"A Ginee sync is already running":        "Sinkronisasi Ginee sedang berjalan",
+	"Ginee sync started":                     "Sinkronisasi Ginee dimulai",
+	"Ginee sync runs retrieved successfully": "Riwayat sinkronisasi Ginee berhasil diambil",
+	"Backups retrieved successfully":         "Daftar backup berhasil diambil",
+
+	// Field validation, see validationMessages
+	"is required":                          "wajib diisi",
+	"is invalid":                           "tidak valid",
+	"must be at least %s":                  "minimal %s",
+	"must be at most %s":                   "maksimal %s",
+	"must have length %s":                  "harus sepanjang %s",
+	"must be greater than %s":              "harus lebih dari %s",
+	"must be less than %s":                 "harus kurang dari %s",
+	"must be one of: %s":                   "harus salah satu dari: %s",
+	"must be a valid email address":        "harus berupa alamat email yang valid",
+	"must contain only letters and digits": "hanya boleh berisi huruf dan angka",
+	"must be a number":                     "harus berupa angka",
+	"must be a valid URL":                  "harus berupa URL yang valid",
+	"must be of type %s":                   "harus bertipe %s",
+	"must be a tracking number of 5 to 50 letters, digits or dashes":         "harus berupa nomor resi 5 sampai 50 huruf, angka atau tanda hubung",
+	"must be a SKU of letters, digits, dashes, dots, underscores or slashes": "harus berupa SKU dari huruf, angka, tanda hubung, titik, garis bawah atau garis miring",
+	"must be a date in YYYY-MM-DD format":                                    "harus berupa tanggal dengan format YYYY-MM-DD",
+	"must be a month in YYYY-MM format":                                      "harus berupa bulan dengan format YYYY-MM",
+	"must be a time in HH:MM format":                                         "harus berupa jam dengan format HH:MM",
+	"must be a date and time in YYYY-MM-DD HH:MM:SS format":                  "harus berupa tanggal dan jam dengan format YYYY-MM-DD HH:MM:SS",
+}
@@ -1,6 +1,14 @@
 package utilities
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+)
 
 // HashPassword generates a bcrypt hash of the password
 func HashPassword(password string) (string, error) {
@@ -13,3 +21,93 @@ func CheckPasswordHash(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
+
+// PasswordPolicy is the complexity new passwords must meet
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+// Validate returns an error listing every rule password breaks, nil when it meets the policy
+func (p PasswordPolicy) Validate(password string) error {
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	var broken []string
+	if length := len([]rune(password)); length < p.MinLength {
+		broken = append(broken, fmt.Sprintf("at least %d characters", p.MinLength))
+	}
+	if p.RequireUpper && !upper {
+		broken = append(broken, "an uppercase letter")
+	}
+	if p.RequireLower && !lower {
+		broken = append(broken, "a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		broken = append(broken, "a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		broken = append(broken, "a symbol")
+	}
+	if len(broken) > 0 {
+		return fmt.Errorf("password must contain %s", strings.Join(broken, ", "))
+	}
+	return nil
+}
+
+// Character sets of generated passwords, leaving out characters easily mistaken for one another
+const (
+	generatedUpper  = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	generatedLower  = "abcdefghijkmnopqrstuvwxyz"
+	generatedDigit  = "23456789"
+	generatedSymbol = "!@#$%*?"
+)
+
+// generatedPasswordLength is the length of generated passwords, unless the policy asks for more
+const generatedPasswordLength = 12
+
+// Generate returns a random password meeting the policy, to hand out as an initial password. It
+// always has an uppercase and lowercase letter, a digit and a symbol.
+func (p PasswordPolicy) Generate() (string, error) {
+	length := max(p.MinLength, generatedPasswordLength)
+	sets := []string{generatedUpper, generatedLower, generatedDigit, generatedSymbol}
+	all := strings.Join(sets, "")
+
+	password := make([]byte, length)
+	for i := range password {
+		set := all
+		if i < len(sets) {
+			set = sets[i]
+		}
+		index, err := rand.Int(rand.Reader, big.NewInt(int64(len(set))))
+		if err != nil {
+			return "", err
+		}
+		password[i] = set[index.Int64()]
+	}
+
+	// Shuffle so the required characters are not always up front
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
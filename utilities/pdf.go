@@ -0,0 +1,186 @@
+package utilities
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// PDF page geometry (A4 in points)
+const (
+	pdfPageWidth   = 595
+	pdfPageHeight  = 842
+	pdfMargin      = 50
+	pdfFontSize    = 10
+	pdfHeadingSize = 13
+	pdfLeading     = 14
+	pdfWrapColumn  = 95
+)
+
+type pdfLine struct {
+	text    string
+	heading bool
+}
+
+// PDFDocument is a minimal text-only PDF writer using the built-in Helvetica fonts.
+// It is meant for simple printable documents (reports, evidence summaries), not layout work.
+type PDFDocument struct {
+	title string
+	lines []pdfLine
+}
+
+// NewPDFDocument creates an empty document with the given title
+func NewPDFDocument(title string) *PDFDocument {
+	return &PDFDocument{title: title}
+}
+
+// AddHeading adds a bold heading line preceded by a blank line
+func (d *PDFDocument) AddHeading(text string) {
+	if len(d.lines) > 0 {
+		d.lines = append(d.lines, pdfLine{})
+	}
+	d.lines = append(d.lines, pdfLine{text: text, heading: true})
+}
+
+// AddLine adds a line of text, wrapping it when it is too long for the page
+func (d *PDFDocument) AddLine(text string) {
+	for _, line := range wrapPDFText(text, pdfWrapColumn) {
+		d.lines = append(d.lines, pdfLine{text: line})
+	}
+}
+
+// AddField adds a "label: value" line
+func (d *PDFDocument) AddField(label string, value interface{}) {
+	d.AddLine(fmt.Sprintf("%s: %v", label, value))
+}
+
+// Bytes renders the document
+func (d *PDFDocument) Bytes() []byte {
+	linesPerPage := (pdfPageHeight - 2*pdfMargin) / pdfLeading
+
+	// Title goes on the first line of the first page
+	lines := append([]pdfLine{{text: d.title, heading: true}, {}}, d.lines...)
+
+	var pages [][]pdfLine
+	for len(lines) > 0 {
+		n := linesPerPage
+		if n > len(lines) {
+			n = len(lines)
+		}
+		pages = append(pages, lines[:n])
+		lines = lines[n:]
+	}
+
+	// Object layout: 1 catalog, 2 pages, 3 regular font, 4 bold font,
+	// then a page object and a content stream object per page
+	var objects []string
+	objects = append(objects, "<< /Type /Catalog /Pages 2 0 R >>")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+	objects = append(objects, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range pages {
+		var content bytes.Buffer
+		y := pdfPageHeight - pdfMargin
+		for _, line := range page {
+			if line.text != "" {
+				font, size := "F1", pdfFontSize
+				if line.heading {
+					font, size = "F2", pdfHeadingSize
+				}
+				fmt.Fprintf(&content, "BT /%s %d Tf %d %d Td (%s) Tj ET\n", font, size, pdfMargin, y, escapePDFText(line.text))
+			}
+			y -= pdfLeading
+		}
+
+		// Page number footer
+		fmt.Fprintf(&content, "BT /F1 8 Tf %d %d Td (Page %d of %d) Tj ET\n", pdfPageWidth-pdfMargin-50, pdfMargin/2, i+1, len(pages))
+
+		objects = append(objects, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, 6+i*2))
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	return writePDFObjects(objects)
+}
+
+// writePDFObjects serializes numbered objects (object 1 must be the catalog) with the cross-reference table
+func writePDFObjects(objects []string) []byte {
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return out.Bytes()
+}
+
+// escapePDFText escapes PDF string delimiters and replaces characters the standard fonts cannot show
+func escapePDFText(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteString("    ")
+		case r < 32 || r > 126:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func wrapPDFText(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+
+		line := ""
+		for _, word := range words {
+			for len(word) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				lines = append(lines, word[:width])
+				word = word[width:]
+			}
+
+			switch {
+			case line == "":
+				line = word
+			case len(line)+1+len(word) > width:
+				lines = append(lines, line)
+				line = word
+			default:
+				line += " " + word
+			}
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,36 @@
+package utilities
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Messages of requests whose queries were stopped before they finished
+const (
+	queryTimedOutMessage    = "Query timed out"
+	requestCancelledMessage = "Request cancelled"
+)
+
+// postgresQueryCanceled is the SQLSTATE of statements cancelled by Postgres, e.g. by statement_timeout
+const postgresQueryCanceled = "SQLSTATE 57014"
+
+// queryInterruption returns the code and message for a failed request whose queries were stopped:
+// its context ran past the query timeout or was cancelled because the client disconnected, or
+// Postgres cancelled the statement. Reports false for any other failure.
+func queryInterruption(c *gin.Context, detail string) (string, string, bool) {
+	if c.Request != nil {
+		switch err := c.Request.Context().Err(); {
+		case errors.Is(err, context.DeadlineExceeded):
+			return ErrCodeQueryTimeout, queryTimedOutMessage, true
+		case errors.Is(err, context.Canceled):
+			return ErrCodeRequestCancelled, requestCancelledMessage, true
+		}
+	}
+	if strings.Contains(detail, postgresQueryCanceled) {
+		return ErrCodeQueryTimeout, queryTimedOutMessage, true
+	}
+	return "", "", false
+}
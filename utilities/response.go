@@ -1,16 +1,21 @@
 package utilities
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type Response struct {
 	Success bool        `json:"success"`
+	Code    string      `json:"code,omitempty" example:"ORDER_NOT_FOUND"` // Error code, see ErrorCodes
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+	// What is wrong with each invalid field of a validation error, keyed by the field's JSON path
+	Fields map[string]string `json:"fields,omitempty" example:"tracking:is required"`
 }
 
 // PaginationResponse represents pagination info
@@ -20,29 +25,54 @@ type PaginationResponse struct {
 	Total int `json:"total"`
 }
 
-// SuccessResponse returns a success response
+// SuccessResponse returns a success response, the message in the language of the request
 func SuccessResponse(c *gin.Context, statusCode int, message string, data interface{}) {
 	c.JSON(statusCode, Response{
 		Success: true,
-		Message: message,
+		Message: translateResponse(c, statusCode, message),
 		Data:    data,
 	})
 }
 
-// ErrorResponse returns an error response
+// ErrorResponse returns an error response with the generic code of statusCode, see ErrorResponseCode
 func ErrorResponse(c *gin.Context, statusCode int, message string, err string) {
+	ErrorResponseCode(c, statusCode, GenericErrorCode(statusCode), message, err)
+}
+
+// ErrorResponseCode returns an error response with the given code and the message in the language of
+// the request. Errors caused by the request's queries being stopped (query timeout, client gone) are
+// answered 503 whatever their status, keeping the message in err, so a stopped lookup is never
+// reported as a missing record.
+func ErrorResponseCode(c *gin.Context, statusCode int, code, message string, err string) {
+	if interruptedCode, interrupted, found := queryInterruption(c, err); found {
+		statusCode, code, message, err = http.StatusServiceUnavailable, interruptedCode, interrupted, message+": "+err
+	}
 	c.JSON(statusCode, Response{
 		Success: false,
-		Message: message,
+		Code:    code,
+		Message: translateResponse(c, statusCode, message),
 		Error:   err,
 	})
 }
 
-// ValidationErrorResponse returns a validation error response
+// LookupErrorResponse answers a failed lookup of a single record: 404 with code and notFound only
+// when the record does not exist, a server error with failed for any other error
+func LookupErrorResponse(c *gin.Context, err error, code, notFound, failed string) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ErrorResponseCode(c, http.StatusNotFound, code, notFound, err.Error())
+		return
+	}
+	ErrorResponse(c, http.StatusInternalServerError, failed, err.Error())
+}
+
+// ValidationErrorResponse returns a validation error response, with what is wrong with each invalid
+// field when err comes from binding the request
 func ValidationErrorResponse(c *gin.Context, err error) {
 	c.JSON(http.StatusBadRequest, Response{
 		Success: false,
-		Message: "Validation failed",
+		Code:    ErrCodeValidationFailed,
+		Message: translateResponse(c, http.StatusBadRequest, "Validation failed"),
 		Error:   err.Error(),
+		Fields:  ValidationErrorFields(c, err),
 	})
 }
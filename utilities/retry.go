@@ -0,0 +1,124 @@
+package utilities
+
+import (
+	"database/sql/driver"
+	"errors"
+	"expvar"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RetryPolicy controls how often a transaction is retried after a transient database error
+type RetryPolicy struct {
+	Attempts  int           // Total attempts including the first one
+	BaseDelay time.Duration // Delay before the first retry, doubled for every further retry
+	MaxDelay  time.Duration
+}
+
+// Retry metrics per operation, published on /debug/vars
+var (
+	transactionRetries  = expvar.NewMap("db_transaction_retries")
+	transactionRecovers = expvar.NewMap("db_transaction_recovered")
+	transactionGiveUps  = expvar.NewMap("db_transaction_retry_exhausted")
+)
+
+// transientSQLStates are server errors that succeed when the transaction is simply run again
+var transientSQLStates = map[string]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+	"53300": true, // too_many_connections
+	"57P01": true, // admin_shutdown
+	"57P02": true, // crash_shutdown
+	"57P03": true, // cannot_connect_now
+}
+
+// WithRetryTransaction runs fn like WithTransaction and runs it again in a fresh transaction when it
+// fails with a transient PostgreSQL error (serialization failure, deadlock, dropped connection).
+// fn must not keep state between attempts: reset anything it assigns before it writes.
+func WithRetryTransaction(db *gorm.DB, operation string, fn func(tx *gorm.DB) error) error {
+	policy := CurrentSettings().TransactionRetry
+	attempts := policy.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	delay := policy.BaseDelay
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = WithTransaction(db, fn)
+		if err == nil {
+			if attempt > 1 {
+				transactionRecovers.Add(operation, 1)
+			}
+			return nil
+		}
+
+		if !IsTransientDBError(err) {
+			return err
+		}
+
+		if attempt == attempts {
+			transactionGiveUps.Add(operation, 1)
+			break
+		}
+
+		transactionRetries.Add(operation, 1)
+		log.Printf("⚠️ Transient database error in %s (attempt %d/%d), retrying: %v", operation, attempt, attempts, err)
+
+		// Jitter keeps concurrent scanners that hit the same conflict from retrying in lockstep
+		time.Sleep(delay + time.Duration(rand.Int63n(int64(delay)/2+1)))
+		delay *= 2
+		if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+	}
+
+	return err
+}
+
+// IsTransientDBError reports whether err is worth retrying in a new transaction
+func IsTransientDBError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// Errors that carry a response status are business rules, never retry them
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return false
+	}
+
+	// PostgreSQL server errors expose their SQLSTATE
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) {
+		code := pgErr.SQLState()
+		// Class 08 is connection_exception
+		return transientSQLStates[code] || strings.HasPrefix(code, "08")
+	}
+
+	// The driver marks errors that happened before anything reached the server
+	var safe interface{ SafeToRetry() bool }
+	if errors.As(err, &safe) && safe.SafeToRetry() {
+		return true
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	message := err.Error()
+	return strings.Contains(message, "connection reset by peer") ||
+		strings.Contains(message, "broken pipe") ||
+		strings.Contains(message, "conn closed")
+}
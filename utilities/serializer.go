@@ -0,0 +1,105 @@
+package utilities
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Serializer applies the fields= and include= query options of a request.
+// include= lists the relations to preload (every relation when the option is absent)
+// and fields= trims serialized objects down to the listed top-level keys.
+type Serializer struct {
+	fields   map[string]bool
+	includes map[string]bool
+}
+
+// NewSerializer reads fields= and include= from the request query
+func NewSerializer(c *gin.Context) *Serializer {
+	s := &Serializer{}
+	if raw, ok := c.GetQuery("fields"); ok {
+		s.fields = splitQuerySet(raw)
+	}
+	if raw, ok := c.GetQuery("include"); ok {
+		s.includes = splitQuerySet(raw)
+	}
+	return s
+}
+
+// Includes reports whether the named relation was requested
+func (s *Serializer) Includes(name string) bool {
+	return s.includes == nil || s.includes[name]
+}
+
+// Preload adds the given preload paths to query when the named relation is included
+func (s *Serializer) Preload(query *gorm.DB, name string, paths ...string) *gorm.DB {
+	if !s.Includes(name) {
+		return query
+	}
+	for _, path := range paths {
+		query = query.Preload(path)
+	}
+	return query
+}
+
+// Serialize trims data to the requested fields. listKey names the array inside
+// data whose items are trimmed, an empty listKey trims data itself (object or array).
+// data is returned untouched when fields= was not given.
+func (s *Serializer) Serialize(data interface{}, listKey string) interface{} {
+	if s.fields == nil {
+		return data
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return data
+	}
+
+	if listKey == "" {
+		return s.trim(decoded)
+	}
+
+	if object, ok := decoded.(map[string]interface{}); ok {
+		object[listKey] = s.trim(object[listKey])
+		return object
+	}
+
+	return decoded
+}
+
+func (s *Serializer) trim(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		for i := range v {
+			v[i] = s.trim(v[i])
+		}
+		return v
+	case map[string]interface{}:
+		for key := range v {
+			// id is always kept so clients can still address the record
+			if key != "id" && !s.fields[key] {
+				delete(v, key)
+			}
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+func splitQuerySet(raw string) map[string]bool {
+	set := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			set[part] = true
+		}
+	}
+	return set
+}
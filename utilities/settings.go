@@ -0,0 +1,50 @@
+package utilities
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Settings are the policies of the utilities package that come from the configuration. Like
+// models.Settings they are swapped as a whole on reload with SetSettings and never mutated in place.
+type Settings struct {
+	// Location is the timezone of the warehouse, see AppLocation
+	Location *time.Location
+
+	// TransactionRetry is the policy used by WithRetryTransaction
+	TransactionRetry RetryPolicy
+
+	// PasswordRules is the policy new passwords are checked against. Existing passwords keep
+	// working until they are changed.
+	PasswordRules PasswordPolicy
+
+	// DefaultLanguage is the language of requests without a supported Accept-Language
+	DefaultLanguage string
+}
+
+// currentSettings holds the Settings in use
+var currentSettings atomic.Pointer[Settings]
+
+func init() {
+	currentSettings.Store(DefaultSettings())
+}
+
+// DefaultSettings returns the settings used before the configuration is applied
+func DefaultSettings() *Settings {
+	return &Settings{
+		Location:         time.Local,
+		TransactionRetry: RetryPolicy{Attempts: 3, BaseDelay: 50 * time.Millisecond, MaxDelay: time.Second},
+		PasswordRules:    PasswordPolicy{MinLength: 8, RequireDigit: true},
+		DefaultLanguage:  LanguageEnglish,
+	}
+}
+
+// CurrentSettings returns the settings in use
+func CurrentSettings() *Settings {
+	return currentSettings.Load()
+}
+
+// SetSettings makes settings the settings in use. settings must not be modified afterwards.
+func SetSettings(settings *Settings) {
+	currentSettings.Store(settings)
+}
@@ -0,0 +1,39 @@
+package utilities
+
+import "time"
+
+// AppLocation returns the timezone of the warehouse. Day boundaries ("today", date filters, daily
+// counts) follow it instead of the timezone of the server or the database.
+func AppLocation() *time.Location {
+	return CurrentSettings().Location
+}
+
+// Now returns the current time in AppLocation
+func Now() time.Time {
+	return time.Now().In(AppLocation())
+}
+
+// DayRange returns the start of the day of date in AppLocation and the start of the next day, for
+// "column >= start AND column < end" filters
+func DayRange(date time.Time) (time.Time, time.Time) {
+	location := AppLocation()
+	date = date.In(location)
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, location)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// ParseDate parses a YYYY-MM-DD date as midnight in AppLocation
+func ParseDate(value string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", value, AppLocation())
+}
+
+// LocalDate is the SQL expression of the AppLocation date of a timestamp column, for grouping per
+// day. Takes the timezone name as argument, see AppTimezone.
+func LocalDate(column string) string {
+	return "DATE(" + column + " AT TIME ZONE ?)"
+}
+
+// AppTimezone returns the name of AppLocation for LocalDate
+func AppTimezone() string {
+	return AppLocation().String()
+}
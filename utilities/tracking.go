@@ -0,0 +1,93 @@
+package utilities
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Tracking checksum algorithms couriers use for the check digit of their tracking numbers
+const (
+	TrackingChecksumLuhn  = "luhn"  // Luhn over the trailing digits
+	TrackingChecksumMod10 = "mod10" // GS1 style 3-1 weighted mod 10 over the trailing digits
+	TrackingChecksumS10   = "s10"   // UPU S10: 2 letters, 8 digits, check digit, 2 letters
+)
+
+// TrackingChecksums lists the supported checksum algorithms
+var TrackingChecksums = []string{TrackingChecksumLuhn, TrackingChecksumMod10, TrackingChecksumS10}
+
+var (
+	trailingDigitsPattern = regexp.MustCompile(`[0-9]+$`)
+	s10Pattern            = regexp.MustCompile(`^[A-Z]{2}([0-9]{8})([0-9])[A-Z]{2}$`)
+	s10Weights            = []int{8, 6, 4, 2, 3, 5, 9, 7}
+)
+
+// IsTrackingChecksum reports whether algorithm is a supported checksum algorithm
+func IsTrackingChecksum(algorithm string) bool {
+	for _, known := range TrackingChecksums {
+		if known == algorithm {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTrackingChecksum verifies the check digit of a tracking number
+func ValidateTrackingChecksum(algorithm, tracking string) error {
+	switch algorithm {
+	case "":
+		return nil
+	case TrackingChecksumLuhn, TrackingChecksumMod10:
+		digits := trailingDigitsPattern.FindString(tracking)
+		if len(digits) < 2 {
+			return fmt.Errorf("tracking %s has no check digit", tracking)
+		}
+
+		sum := 0
+		for i := len(digits) - 2; i >= 0; i-- {
+			d := int(digits[i] - '0')
+			// Position counted from the check digit, odd positions get the heavy weight
+			heavy := (len(digits)-2-i)%2 == 0
+			if algorithm == TrackingChecksumLuhn {
+				if heavy {
+					d *= 2
+					if d > 9 {
+						d -= 9
+					}
+				}
+			} else if heavy {
+				d *= 3
+			}
+			sum += d
+		}
+
+		if expected := (10 - sum%10) % 10; int(digits[len(digits)-1]-'0') != expected {
+			return fmt.Errorf("check digit of tracking %s does not match", tracking)
+		}
+		return nil
+	case TrackingChecksumS10:
+		match := s10Pattern.FindStringSubmatch(strings.ToUpper(tracking))
+		if match == nil {
+			return fmt.Errorf("tracking %s is not a valid S10 number", tracking)
+		}
+
+		sum := 0
+		for i, r := range match[1] {
+			sum += int(r-'0') * s10Weights[i]
+		}
+		expected := 11 - sum%11
+		switch expected {
+		case 10:
+			expected = 0
+		case 11:
+			expected = 5
+		}
+
+		if int(match[2][0]-'0') != expected {
+			return fmt.Errorf("check digit of tracking %s does not match", tracking)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown tracking checksum %q", algorithm)
+	}
+}
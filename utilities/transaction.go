@@ -0,0 +1,66 @@
+package utilities
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// StatusError aborts a transaction with a specific HTTP status, error code and message
+type StatusError struct {
+	Status  int
+	Code    string
+	Message string
+	Detail  string
+}
+
+func (e *StatusError) Error() string {
+	return e.Detail
+}
+
+// NewStatusError creates a StatusError with the generic code of status, return it from a
+// WithTransaction callback to roll back and respond with the given status
+func NewStatusError(status int, message, detail string) *StatusError {
+	return NewStatusErrorCode(status, GenericErrorCode(status), message, detail)
+}
+
+// NewStatusErrorCode creates a StatusError responded with the given error code
+func NewStatusErrorCode(status int, code, message, detail string) *StatusError {
+	return &StatusError{Status: status, Code: code, Message: message, Detail: detail}
+}
+
+// WithTransaction runs fn inside a database transaction. The transaction is committed
+// when fn returns nil and rolled back when fn returns an error or panics.
+func WithTransaction(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// TransactionErrorResponse responds with the status carried by a StatusError,
+// any other error is reported as an internal server error with the given message
+func TransactionErrorResponse(c *gin.Context, err error, message string) {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		ErrorResponseCode(c, statusErr.Status, statusErr.Code, statusErr.Message, statusErr.Detail)
+		return
+	}
+	ErrorResponse(c, http.StatusInternalServerError, message, err.Error())
+}
@@ -0,0 +1,159 @@
+package utilities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Custom validation tags for the binding tags of request structs, see RegisterValidators
+const (
+	ValidateTracking = "tracking"  // Tracking number: letters, digits and dashes
+	ValidateSKU      = "sku"       // Product SKU: letters, digits, dashes, dots, underscores and slashes
+	ValidateDate     = "date"      // YYYY-MM-DD
+	ValidateMonth    = "month"     // YYYY-MM
+	ValidateClock    = "clock"     // HH:MM
+	ValidateDateTime = "date_time" // YYYY-MM-DD HH:MM:SS
+)
+
+var (
+	trackingPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]{4,49}$`)
+	skuPattern      = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]{0,99}$`)
+)
+
+// customValidators are the custom tags with their check of the field value
+var customValidators = map[string]func(value string) bool{
+	ValidateTracking: func(value string) bool { return trackingPattern.MatchString(strings.TrimSpace(value)) },
+	ValidateSKU:      func(value string) bool { return skuPattern.MatchString(strings.TrimSpace(value)) },
+	ValidateDate:     timeLayoutValidator("2006-01-02"),
+	ValidateMonth:    timeLayoutValidator("2006-01"),
+	ValidateClock:    timeLayoutValidator("15:04"),
+	ValidateDateTime: timeLayoutValidator("2006-01-02 15:04:05"),
+}
+
+// validationMessages are the messages of failed validation tags per field, %s is the tag's
+// parameter. Like response messages they key the translation catalogues.
+var validationMessages = map[string]string{
+	"required":       "is required",
+	"required_if":    "is required",
+	"required_with":  "is required",
+	"min":            "must be at least %s",
+	"max":            "must be at most %s",
+	"len":            "must have length %s",
+	"gt":             "must be greater than %s",
+	"gte":            "must be at least %s",
+	"lt":             "must be less than %s",
+	"lte":            "must be at most %s",
+	"oneof":          "must be one of: %s",
+	"email":          "must be a valid email address",
+	"alphanum":       "must contain only letters and digits",
+	"numeric":        "must be a number",
+	"url":            "must be a valid URL",
+	ValidateTracking: "must be a tracking number of 5 to 50 letters, digits or dashes",
+	ValidateSKU:      "must be a SKU of letters, digits, dashes, dots, underscores or slashes",
+	ValidateDate:     "must be a date in YYYY-MM-DD format",
+	ValidateMonth:    "must be a month in YYYY-MM format",
+	ValidateClock:    "must be a time in HH:MM format",
+	ValidateDateTime: "must be a date and time in YYYY-MM-DD HH:MM:SS format",
+}
+
+// RegisterValidators registers the custom validation tags on the validator of gin's request binding
+// and makes validation errors name fields by their JSON (or query) name
+func RegisterValidators() error {
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("request binding does not use go-playground/validator")
+	}
+
+	engine.RegisterTagNameFunc(requestFieldName)
+	for tag, valid := range customValidators {
+		valid := valid
+		err := engine.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+			if fl.Field().Kind() != reflect.String {
+				return false
+			}
+			return valid(fl.Field().String())
+		})
+		if err != nil {
+			return fmt.Errorf("failed to register %s validator: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+// ValidationErrorFields maps the fields failing validation in a binding error to what is wrong with
+// them, in the language of the request. Fields are named by their JSON path, e.g.
+// "details[0].quantity". Returns nil for errors not about fields, such as malformed JSON.
+func ValidationErrorFields(c *gin.Context, err error) map[string]string {
+	lang := RequestLanguage(c)
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		fields := make(map[string]string, len(validationErrors))
+		for _, fieldError := range validationErrors {
+			fields[fieldPath(fieldError)] = validationMessage(lang, fieldError.Tag(), fieldError.Param())
+		}
+		return fields
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) && typeError.Field != "" {
+		message := fmt.Sprintf(Translate(lang, "must be of type %s"), typeError.Type.String())
+		return map[string]string{typeError.Field: message}
+	}
+
+	return nil
+}
+
+// validationMessage returns the message of a failed validation tag in lang
+func validationMessage(lang, tag, param string) string {
+	message, found := validationMessages[tag]
+	if !found {
+		return Translate(lang, "is invalid")
+	}
+	message = Translate(lang, message)
+	if strings.Contains(message, "%s") {
+		return fmt.Sprintf(message, param)
+	}
+	return message
+}
+
+// fieldPath returns the path of a failing field below the request struct, dropping the struct's
+// own name from the namespace
+func fieldPath(fieldError validator.FieldError) string {
+	namespace := fieldError.Namespace()
+	if _, path, found := strings.Cut(namespace, "."); found {
+		return path
+	}
+	return fieldError.Field()
+}
+
+// requestFieldName names a struct field by its JSON name, or its query name for query bindings
+func requestFieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// timeLayoutValidator returns a check that a value is a time in layout
+func timeLayoutValidator(layout string) func(value string) bool {
+	return func(value string) bool {
+		_, err := time.Parse(layout, value)
+		return err == nil
+	}
+}
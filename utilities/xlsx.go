@@ -0,0 +1,231 @@
+package utilities
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// XLSX cell styles, indexes into cellXfs of the styles part
+const (
+	xlsxStyleDefault = 0
+	xlsxStyleHeader  = 1
+	xlsxStyleNumber  = 2
+)
+
+// Column width bounds in characters
+const (
+	xlsxMinColumnWidth = 8
+	xlsxMaxColumnWidth = 60
+)
+
+// XLSXWorkbook is a minimal spreadsheet writer producing Office Open XML workbooks with a styled
+// header row, frozen and filterable, on every sheet. Meant for report exports, not formulas or charts.
+type XLSXWorkbook struct {
+	sheets []*XLSXSheet
+}
+
+// XLSXSheet is one worksheet of a workbook
+type XLSXSheet struct {
+	name   string
+	widths []int
+	rows   [][]interface{}
+}
+
+// NewXLSXWorkbook creates an empty workbook
+func NewXLSXWorkbook() *XLSXWorkbook {
+	return &XLSXWorkbook{}
+}
+
+// AddSheet adds a worksheet with the given header row. Names are cut to the 31 characters Excel allows.
+func (w *XLSXWorkbook) AddSheet(name string, headers ...string) *XLSXSheet {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, name)
+	if len([]rune(name)) > 31 {
+		name = string([]rune(name)[:31])
+	}
+
+	sheet := &XLSXSheet{name: name}
+	row := make([]interface{}, len(headers))
+	for i, header := range headers {
+		row[i] = header
+	}
+	sheet.AddRow(row...)
+	w.sheets = append(w.sheets, sheet)
+	return sheet
+}
+
+// AddRow appends a row. Numbers are written as numeric cells, times as "2006-01-02 15:04:05" text
+// and nil values as empty cells.
+func (s *XLSXSheet) AddRow(values ...interface{}) {
+	row := make([]interface{}, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case time.Time:
+			value = v.Format("2006-01-02 15:04:05")
+		case *time.Time:
+			if v == nil {
+				value = nil
+			} else {
+				value = v.Format("2006-01-02 15:04:05")
+			}
+		}
+		row[i] = value
+
+		for len(s.widths) <= i {
+			s.widths = append(s.widths, xlsxMinColumnWidth)
+		}
+		if value != nil {
+			s.widths[i] = min(max(s.widths[i], len([]rune(fmt.Sprint(value)))+2), xlsxMaxColumnWidth)
+		}
+	}
+	s.rows = append(s.rows, row)
+}
+
+// Bytes renders the workbook as an .xlsx file
+func (w *XLSXWorkbook) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	var contentTypes, workbookSheets, workbookRels strings.Builder
+	for i, sheet := range w.sheets {
+		n := i + 1
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+		fmt.Fprintf(&workbookSheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXML(sheet.name), n, n)
+		fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n)
+	}
+	fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(w.sheets)+1)
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+			contentTypes.String() + `</Types>`},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets>` + workbookSheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			workbookRels.String() + `</Relationships>`},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for i, sheet := range w.sheets {
+		parts = append(parts, struct {
+			name    string
+			content string
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), sheet.render()})
+	}
+
+	for _, part := range parts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := file.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (s *XLSXSheet) render() string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+
+	// Keep the header row in view while scrolling
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+
+	if len(s.widths) > 0 {
+		b.WriteString(`<cols>`)
+		for i, width := range s.widths {
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, width)
+		}
+		b.WriteString(`</cols>`)
+	}
+
+	b.WriteString(`<sheetData>`)
+	for r, row := range s.rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for col, value := range row {
+			ref := xlsxColumnName(col) + strconv.Itoa(r+1)
+			style := xlsxStyleDefault
+			if r == 0 {
+				style = xlsxStyleHeader
+			}
+
+			switch v := value.(type) {
+			case nil:
+				continue
+			case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+				if r > 0 {
+					style = xlsxStyleNumber
+				}
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%d</v></c>`, ref, style, v)
+			case float32, float64:
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%v</v></c>`, ref, style, v)
+			default:
+				fmt.Fprintf(&b, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style, escapeXML(fmt.Sprint(v)))
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData>`)
+
+	if len(s.widths) > 0 {
+		fmt.Fprintf(&b, `<autoFilter ref="A1:%s%d"/>`, xlsxColumnName(len(s.widths)-1), len(s.rows))
+	}
+
+	b.WriteString(`</worksheet>`)
+	return b.String()
+}
+
+// xlsxColumnName converts a zero based column index to its letters (0 = A, 26 = AA)
+func xlsxColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+func escapeXML(text string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
+
+// xlsxStyles defines the default style, a bold shaded bordered header and thousands separated numbers
+const xlsxStyles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="3"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill>` +
+	`<fill><patternFill patternType="solid"><fgColor rgb="FFD9E1F2"/><bgColor indexed="64"/></patternFill></fill></fills>` +
+	`<borders count="2"><border><left/><right/><top/><bottom/><diagonal/></border>` +
+	`<border><left/><right/><top/><bottom style="thin"><color auto="1"/></bottom><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="2" borderId="1" xfId="0" applyFont="1" applyFill="1" applyBorder="1"/>` +
+	`<xf numFmtId="3" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`